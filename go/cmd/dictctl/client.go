@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// idempotencyKeyHeader matches middleware.IdempotencyKeyHeader
+const idempotencyKeyHeader = "X-Idempotency-Key"
+
+// apiResponse is the envelope every simulator response uses
+type apiResponse struct {
+	Code    string          `json:"code"`
+	Data    json.RawMessage `json:"data"`
+	Error   string          `json:"error"`
+	Message string          `json:"message"`
+}
+
+// client calls the simulator API for a profile
+type client struct {
+	profile *Profile
+	http    *http.Client
+}
+
+func newClient(profile *Profile) *client {
+	return &client{
+		profile: profile,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// do sends the request and returns the decoded envelope, or an error for non-2xx responses
+func (c *client) do(method, path string, body any, headers map[string]string) (*apiResponse, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, strings.TrimRight(c.profile.URL, "/")+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.profile.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.profile.Token)
+	}
+	if c.profile.AdminToken != "" && strings.HasPrefix(path, "/admin/") {
+		req.Header.Set("X-Admin-Token", c.profile.AdminToken)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var envelope apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("%s %s: status %d: invalid response: %w", method, path, resp.StatusCode, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s %s: status %d: %s: %s", method, path, resp.StatusCode, envelope.Error, envelope.Message)
+	}
+	return &envelope, nil
+}
+
+// printData writes the response data as indented JSON
+func printData(out io.Writer, resp *apiResponse) error {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, resp.Data, "", "  "); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	_, err := buf.WriteTo(out)
+	return err
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/seed"
+)
+
+// authResult is the data returned by register and login
+type authResult struct {
+	Token string `json:"token"`
+	User  struct {
+		ID    string `json:"id"`
+		Email string `json:"email"`
+	} `json:"user"`
+}
+
+// storeToken saves the token from an auth response in the active profile
+func (a *app) storeToken(resp *apiResponse) error {
+	var result authResult
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return err
+	}
+
+	a.cfg.profile(a.profileName).Token = result.Token
+	if err := a.cfg.save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "Logged in as %s (token saved to profile)\n", result.User.Email)
+	return nil
+}
+
+func newUsersCmd(a *app) *cobra.Command {
+	users := &cobra.Command{
+		Use:   "users",
+		Short: "Manage simulator users",
+	}
+
+	var email, password, name string
+	register := &cobra.Command{
+		Use:   "register",
+		Short: "Register a user and store its token",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			resp, err := newClient(a.cfg.profile(a.profileName)).do(http.MethodPost, "/auth/register", map[string]string{
+				"email":    email,
+				"password": password,
+				"name":     name,
+			}, nil)
+			if err != nil {
+				return err
+			}
+			return a.storeToken(resp)
+		},
+	}
+	register.Flags().StringVar(&email, "email", "", "user email")
+	register.Flags().StringVar(&password, "password", "", "password (min 6 characters)")
+	register.Flags().StringVar(&name, "name", "", "display name")
+
+	users.AddCommand(register)
+	return users
+}
+
+func newLoginCmd(a *app) *cobra.Command {
+	var email, password string
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Log in and store the token",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			resp, err := newClient(a.cfg.profile(a.profileName)).do(http.MethodPost, "/auth/login", map[string]string{
+				"email":    email,
+				"password": password,
+			}, nil)
+			if err != nil {
+				return err
+			}
+			return a.storeToken(resp)
+		},
+	}
+	cmd.Flags().StringVar(&email, "email", "", "user email")
+	cmd.Flags().StringVar(&password, "password", "", "password")
+	return cmd
+}
+
+func newEntriesCmd(a *app) *cobra.Command {
+	entries := &cobra.Command{
+		Use:   "entries",
+		Short: "Create, look up and delete entries",
+	}
+	entries.AddCommand(newEntriesCreateCmd(a), newEntriesGetCmd(a), newEntriesDeleteCmd(a))
+	return entries
+}
+
+func newEntriesCreateCmd(a *app) *cobra.Command {
+	var (
+		generate       bool
+		file           string
+		idempotencyKey string
+
+		key, keyType, participant, branch, accountNumber, accountType string
+		openingDate, ownerType, taxID, ownerName, tradeName, reason   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create an entry",
+		Long:  "Create an entry from flags, a JSON file or a random valid entry. Explicitly set flags always win over the file or generated values.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			req := models.CreateEntryRequest{
+				Account: models.Account{AccountType: "CACC"},
+				Owner:   models.Owner{Type: models.OwnerTypeNaturalPerson},
+				Reason:  "USER_REQUESTED",
+			}
+
+			switch {
+			case file != "":
+				if err := readJSON(file, &req); err != nil {
+					return err
+				}
+			case generate:
+				opts := seed.Options{}
+				if keyType != "" {
+					opts.KeyTypes = map[models.KeyType]int{models.KeyType(keyType): 1}
+				}
+				req = seed.NewGenerator(opts).Next()
+			}
+
+			flags := cmd.Flags()
+			if flags.Changed("key") {
+				req.Key = key
+			}
+			if flags.Changed("type") {
+				req.KeyType = models.KeyType(keyType)
+			}
+			if flags.Changed("participant") {
+				req.Account.Participant = participant
+			}
+			if flags.Changed("branch") {
+				req.Account.Branch = branch
+			}
+			if flags.Changed("account") {
+				req.Account.AccountNumber = accountNumber
+			}
+			if flags.Changed("account-type") {
+				req.Account.AccountType = models.AccountType(accountType)
+			}
+			if flags.Changed("opening-date") {
+				date, err := time.Parse(time.DateOnly, openingDate)
+				if err != nil {
+					return fmt.Errorf("invalid --opening-date: %w", err)
+				}
+				req.Account.OpeningDate = date
+			}
+			if flags.Changed("owner-type") {
+				req.Owner.Type = models.OwnerType(ownerType)
+			}
+			if flags.Changed("tax-id") {
+				req.Owner.TaxIdNumber = taxID
+			}
+			if flags.Changed("name") {
+				req.Owner.Name = ownerName
+			}
+			if flags.Changed("trade-name") {
+				req.Owner.TradeName = tradeName
+			}
+			if flags.Changed("reason") {
+				req.Reason = models.Reason(reason)
+			}
+
+			if req.RequestId == "" {
+				req.RequestId = uuid.NewString()
+			}
+			if idempotencyKey == "" {
+				idempotencyKey = uuid.NewString()
+			}
+
+			resp, err := newClient(a.cfg.profile(a.profileName)).do(http.MethodPost, "/entries", req, map[string]string{
+				idempotencyKeyHeader: idempotencyKey,
+			})
+			if err != nil {
+				return err
+			}
+			return printData(a.out, resp)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&generate, "generate", false, "start from a random valid entry (explicit flags override it)")
+	flags.StringVar(&file, "file", "", "read the request JSON from a file (- for stdin)")
+	flags.StringVar(&idempotencyKey, "idempotency-key", "", "X-Idempotency-Key (default: random)")
+
+	flags.StringVar(&key, "key", "", "Pix key")
+	flags.StringVar(&keyType, "type", "", "key type: CPF, CNPJ, EMAIL, PHONE or EVP")
+	flags.StringVar(&participant, "participant", "", "8-digit ISPB")
+	flags.StringVar(&branch, "branch", "", "4-digit branch")
+	flags.StringVar(&accountNumber, "account", "", "account number")
+	flags.StringVar(&accountType, "account-type", "", "CACC, SVGS, SLRY, PAGA or TRAN")
+	flags.StringVar(&openingDate, "opening-date", "", "account opening date (YYYY-MM-DD)")
+	flags.StringVar(&ownerType, "owner-type", "", "NATURAL_PERSON or LEGAL_PERSON")
+	flags.StringVar(&taxID, "tax-id", "", "owner CPF or CNPJ")
+	flags.StringVar(&ownerName, "name", "", "owner name")
+	flags.StringVar(&tradeName, "trade-name", "", "trade name (LEGAL_PERSON only)")
+	flags.StringVar(&reason, "reason", "", "USER_REQUESTED or RECONCILIATION")
+	cmd.MarkFlagsMutuallyExclusive("generate", "file")
+	return cmd
+}
+
+func newEntriesGetCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <key>",
+		Short: "Look up an entry",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := newClient(a.cfg.profile(a.profileName)).do(http.MethodGet, "/entries/"+url.PathEscape(args[0]), nil, nil)
+			if err != nil {
+				return err
+			}
+			return printData(a.out, resp)
+		},
+	}
+}
+
+func newEntriesDeleteCmd(a *app) *cobra.Command {
+	var participant, reason string
+	cmd := &cobra.Command{
+		Use:   "delete <key>",
+		Short: "Delete an entry",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := args[0]
+			resp, err := newClient(a.cfg.profile(a.profileName)).do(http.MethodPost, "/entries/"+url.PathEscape(key)+"/delete", models.DeleteEntryRequest{
+				Key:         key,
+				Participant: participant,
+				Reason:      models.Reason(reason),
+			}, nil)
+			if err != nil {
+				return err
+			}
+			return printData(a.out, resp)
+		},
+	}
+	cmd.Flags().StringVar(&participant, "participant", "", "8-digit ISPB of the entry's participant")
+	cmd.Flags().StringVar(&reason, "reason", "USER_REQUESTED", "USER_REQUESTED, ACCOUNT_CLOSURE, RECONCILIATION, FRAUD or RFB_VALIDATION")
+	return cmd
+}
+
+func newSeedCmd(a *app) *cobra.Command {
+	var count int
+	var seedValue uint64
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Bulk-generate entries (admin)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			resp, err := newClient(a.cfg.profile(a.profileName)).do(http.MethodPost, "/admin/seed", map[string]any{
+				"count": count,
+				"seed":  seedValue,
+			}, nil)
+			if err != nil {
+				return err
+			}
+			return printData(a.out, resp)
+		},
+	}
+	cmd.Flags().IntVar(&count, "count", 100, "number of entries to generate")
+	cmd.Flags().Uint64Var(&seedValue, "seed", 0, "generator seed, to reproduce a previous dataset")
+	return cmd
+}
+
+func newProfileCmd(a *app) *cobra.Command {
+	profile := &cobra.Command{
+		Use:   "profile",
+		Short: "Show or update the active profile",
+	}
+
+	show := &cobra.Command{
+		Use:   "show",
+		Short: "Print the active profile",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			name := a.profileName
+			if name == "" {
+				name = a.cfg.Current
+			}
+			p := a.cfg.profile(name)
+
+			token := "(none)"
+			if p.Token != "" {
+				token = "(set)"
+			}
+			adminToken := "(none)"
+			if p.AdminToken != "" {
+				adminToken = "(set)"
+			}
+			fmt.Fprintf(a.out, "profile: %s\nurl:     %s\ntoken:   %s\nadmin:   %s\n", name, p.URL, token, adminToken)
+			return nil
+		},
+	}
+
+	var apiURL, token, adminToken string
+	var use bool
+	set := &cobra.Command{
+		Use:   "set",
+		Short: "Update the active profile",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			p := a.cfg.profile(a.profileName)
+			if apiURL != "" {
+				p.URL = apiURL
+			}
+			if token != "" {
+				p.Token = token
+			}
+			if adminToken != "" {
+				p.AdminToken = adminToken
+			}
+			if use && a.profileName != "" {
+				a.cfg.Current = a.profileName
+			}
+			return a.cfg.save()
+		},
+	}
+	set.Flags().StringVar(&apiURL, "url", "", "simulator base URL")
+	set.Flags().StringVar(&token, "token", "", "bearer token")
+	set.Flags().StringVar(&adminToken, "admin-token", "", "ADMIN_TOKEN of the simulator, sent on admin routes")
+	set.Flags().BoolVar(&use, "use", false, "make this profile the current one")
+
+	profile.AddCommand(show, set)
+	return profile
+}
+
+// readJSON decodes a JSON file, or stdin for "-"
+func readJSON(path string, v any) error {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	return json.NewDecoder(r).Decode(v)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// defaultURL is where a fresh profile points
+const defaultURL = "http://localhost:3000"
+
+// Profile is a simulator endpoint and the tokens used to call it
+type Profile struct {
+	URL        string `json:"url"`
+	Token      string `json:"token,omitempty"`
+	AdminToken string `json:"adminToken,omitempty"`
+}
+
+// Config is the dictctl configuration file
+type Config struct {
+	Current  string              `json:"current"`
+	Profiles map[string]*Profile `json:"profiles"`
+
+	path string
+}
+
+// configPath returns $DICTCTL_CONFIG or <user config dir>/dictctl/config.json
+func configPath() (string, error) {
+	if path := os.Getenv("DICTCTL_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "dictctl", "config.json"), nil
+}
+
+// loadConfig reads the configuration file, starting with a default profile when it does not exist
+func loadConfig(path string) (*Config, error) {
+	cfg := &Config{
+		Current:  "default",
+		Profiles: map[string]*Profile{},
+		path:     path,
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]*Profile{}
+	}
+	return cfg, nil
+}
+
+// profile returns the named profile, or the current one when name is empty, creating it if needed
+func (c *Config) profile(name string) *Profile {
+	if name == "" {
+		name = c.Current
+	}
+	p, ok := c.Profiles[name]
+	if !ok {
+		p = &Profile{URL: defaultURL}
+		c.Profiles[name] = p
+	}
+	return p
+}
+
+// save writes the configuration file; it holds tokens, so it is only readable by the user
+func (c *Config) save() error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, append(data, '\n'), 0o600)
+}
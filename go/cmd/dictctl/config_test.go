@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dictctl", "config.json")
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig() on missing file error = %v", err)
+	}
+	if got := cfg.profile("").URL; got != defaultURL {
+		t.Errorf("default profile URL = %q, want %q", got, defaultURL)
+	}
+
+	cfg.profile("staging").URL = "https://dict.staging.example.com"
+	cfg.profile("staging").Token = "token"
+	if err := cfg.save(); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("config permissions = %o, want 600", perm)
+	}
+
+	loaded, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	staging := loaded.profile("staging")
+	if staging.URL != "https://dict.staging.example.com" || staging.Token != "token" {
+		t.Errorf("staging profile = %+v, want saved URL and token", staging)
+	}
+}
+
+func TestProfileFlag(t *testing.T) {
+	t.Setenv("DICTCTL_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+
+	if err := run([]string{"--profile", "staging", "profile", "set", "--url", "https://dict.staging.example.com", "--use"}, io.Discard); err != nil {
+		t.Fatalf("profile set error = %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := run([]string{"profile", "show"}, &out); err != nil {
+		t.Fatalf("profile show error = %v", err)
+	}
+	if !strings.Contains(out.String(), "profile: staging") || !strings.Contains(out.String(), "https://dict.staging.example.com") {
+		t.Errorf("profile show = %q, want the staging profile made current", out.String())
+	}
+
+	if err := run([]string{"entries", "get"}, io.Discard); err == nil {
+		t.Errorf("entries get without a key succeeded")
+	}
+}
@@ -0,0 +1,71 @@
+// Command dictctl is a command-line client for the DICT simulator.
+//
+// It keeps the simulator URL and an auth token per profile in a config file so requests
+// can be made without hand-writing curl commands and JSON payloads:
+//
+//	dictctl users register --email dev@example.com --password secret123 --name Dev
+//	dictctl entries create --generate --type EMAIL
+//	dictctl entries get dev@example.com
+//	dictctl --profile staging seed --count 1000
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// app is the state shared by every command
+type app struct {
+	cfg         *Config
+	profileName string
+	out         io.Writer
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "dictctl:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	root := newRootCmd(out)
+	root.SetArgs(args)
+	return root.Execute()
+}
+
+// newRootCmd builds the command tree; the config file is loaded once a command runs
+func newRootCmd(out io.Writer) *cobra.Command {
+	a := &app{out: out}
+
+	root := &cobra.Command{
+		Use:   "dictctl",
+		Short: "Command-line client for the DICT simulator",
+		Long: "Command-line client for the DICT simulator.\n\n" +
+			"Profiles are stored in $DICTCTL_CONFIG (default: <user config dir>/dictctl/config.json).",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			path, err := configPath()
+			if err != nil {
+				return err
+			}
+			a.cfg, err = loadConfig(path)
+			return err
+		},
+	}
+	root.SetOut(out)
+	root.PersistentFlags().StringVar(&a.profileName, "profile", "", "profile to use (default: the current profile)")
+
+	root.AddCommand(
+		newUsersCmd(a),
+		newLoginCmd(a),
+		newEntriesCmd(a),
+		newSeedCmd(a),
+		newProfileCmd(a),
+	)
+	return root
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/seed"
+)
+
+// expectedStatus is the status of a successful request per operation
+var expectedStatus = map[operation]int{
+	opCreate: http.StatusCreated,
+	opGet:    http.StatusOK,
+	opDelete: http.StatusOK,
+}
+
+// registered is a key created during the run, kept so gets and deletes hit existing entries
+type registered struct {
+	key         string
+	participant string
+}
+
+// generator schedules requests and records their outcome
+type generator struct {
+	target *target
+	opts   *options
+
+	mu      sync.Mutex
+	rng     *rand.Rand
+	entries *seed.Generator
+	keys    []registered
+	stats   map[operation]*opStats
+	dropped int
+}
+
+func newGenerator(t *target, opts *options) *generator {
+	entries := seed.NewGenerator(seed.Options{Seed: opts.seed})
+	stats := make(map[operation]*opStats, len(operations))
+	for _, op := range operations {
+		stats[op] = newOpStats()
+	}
+
+	return &generator{
+		target:  t,
+		opts:    opts,
+		rng:     rand.New(rand.NewPCG(entries.Seed(), 0)),
+		entries: entries,
+		stats:   stats,
+	}
+}
+
+// run starts requests on schedule until the duration elapses or ctx is cancelled, then waits
+// for the ones in flight
+func (g *generator) run(ctx context.Context) *report {
+	jobs := make(chan operation)
+	var wg sync.WaitGroup
+	for range g.opts.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for op := range jobs {
+				g.do(ctx, op)
+			}
+		}()
+	}
+
+	start := time.Now()
+	interval := time.Second / time.Duration(g.opts.rps)
+	deadline := time.NewTimer(g.opts.duration)
+	defer deadline.Stop()
+
+schedule:
+	for i := 1; ; i++ {
+		g.mu.Lock()
+		op := g.opts.mix.pick(g.rng)
+		g.mu.Unlock()
+
+		select {
+		case jobs <- op:
+		default:
+			// Every worker is busy: skipping keeps the offered rate instead of queueing behind a slow server
+			g.mu.Lock()
+			g.dropped++
+			g.mu.Unlock()
+		}
+
+		wait := time.NewTimer(time.Until(start.Add(time.Duration(i) * interval)))
+		select {
+		case <-wait.C:
+		case <-deadline.C:
+			wait.Stop()
+			break schedule
+		case <-ctx.Done():
+			wait.Stop()
+			break schedule
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return &report{elapsed: time.Since(start), stats: g.stats, dropped: g.dropped}
+}
+
+// do sends one request, falling back to a create when there is no key to get or delete yet
+func (g *generator) do(ctx context.Context, op operation) {
+	g.mu.Lock()
+	var (
+		entry registered
+		req   models.CreateEntryRequest
+	)
+	if op != opCreate && len(g.keys) > 0 {
+		i := g.rng.IntN(len(g.keys))
+		entry = g.keys[i]
+		if op == opDelete {
+			g.keys[i] = g.keys[len(g.keys)-1]
+			g.keys = g.keys[:len(g.keys)-1]
+		}
+	} else {
+		op = opCreate
+		req = g.entries.Next()
+	}
+	g.mu.Unlock()
+
+	var (
+		status int
+		err    error
+	)
+	start := time.Now()
+	switch op {
+	case opCreate:
+		status, err = g.target.create(ctx, &req)
+	case opGet:
+		status, err = g.target.get(ctx, entry.key)
+	case opDelete:
+		status, err = g.target.delete(ctx, entry.key, entry.participant)
+	}
+	elapsed := time.Since(start)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.stats[op].record(elapsed, status, err)
+	if op == opCreate && status == http.StatusCreated {
+		g.keys = append(g.keys, registered{key: req.Key, participant: req.Account.Participant})
+	}
+}
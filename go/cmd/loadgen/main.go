@@ -0,0 +1,101 @@
+// Command loadgen drives a mix of entry operations against a running DICT simulator at a target rate.
+//
+// Requests are started on a fixed schedule (open loop), so a slow server shows up as higher latency
+// and dropped requests rather than a lower offered rate:
+//
+//	loadgen -url http://localhost:3000 -rps 500 -duration 1m -mix create=10,get=80,delete=10
+//
+// Without -token a throwaway user is registered first. Rate limiting counts against that user, so
+// start the simulator with RATE_LIMIT_ENABLED=false to measure the server rather than its limits.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+// options are the command-line settings of a run
+type options struct {
+	url      string
+	token    string
+	rps      int
+	duration time.Duration
+	workers  int
+	mix      mix
+	seed     uint64
+	timeout  time.Duration
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "loadgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, out io.Writer) error {
+	opts, err := parseFlags(args, out)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{
+		Timeout: opts.timeout,
+		Transport: &http.Transport{
+			MaxIdleConns:        opts.workers,
+			MaxIdleConnsPerHost: opts.workers,
+		},
+	}
+	target := &target{baseURL: strings.TrimRight(opts.url, "/"), token: opts.token, http: client}
+
+	if target.token == "" {
+		if target.token, err = target.register(ctx); err != nil {
+			return fmt.Errorf("register load test user: %w", err)
+		}
+	}
+
+	fmt.Fprintf(out, "Running %s at %d req/s against %s (%s)\n\n", opts.duration, opts.rps, opts.url, opts.mix)
+	report := newGenerator(target, opts).run(ctx)
+	return report.write(out)
+}
+
+// parseFlags reads the options of a run from args
+func parseFlags(args []string, out io.Writer) (*options, error) {
+	opts := &options{mix: defaultMix()}
+
+	fs := flag.NewFlagSet("loadgen", flag.ContinueOnError)
+	fs.SetOutput(out)
+	fs.StringVar(&opts.url, "url", "http://localhost:3000", "simulator base URL")
+	fs.StringVar(&opts.token, "token", "", "JWT to send; empty registers a new user")
+	fs.IntVar(&opts.rps, "rps", 100, "requests started per second")
+	fs.DurationVar(&opts.duration, "duration", 30*time.Second, "how long to generate load")
+	fs.IntVar(&opts.workers, "workers", 64, "most requests in flight; requests due while all are busy are dropped")
+	fs.Var(&opts.mix, "mix", "relative weight of each operation (create, get, delete)")
+	fs.Uint64Var(&opts.seed, "seed", 0, "seed for generated entries; 0 picks a random one")
+	fs.DurationVar(&opts.timeout, "timeout", 10*time.Second, "per-request timeout")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+	if opts.rps < 1 || opts.workers < 1 || opts.duration <= 0 {
+		return nil, errors.New("-rps, -workers and -duration must be positive")
+	}
+	return opts, nil
+}
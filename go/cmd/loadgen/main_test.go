@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dict-simulator/go/simulator"
+)
+
+func TestRunAgainstSimulator(t *testing.T) {
+	sim := simulator.New()
+	srv := httptest.NewServer(sim)
+	t.Cleanup(func() {
+		srv.Close()
+		sim.Shutdown(context.Background())
+	})
+
+	var out bytes.Buffer
+	err := run(context.Background(), []string{
+		"-url", srv.URL,
+		"-rps", "200",
+		"-duration", "500ms",
+		"-mix", "create=2,get=6,delete=2",
+		"-seed", "42",
+	}, &out)
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	report := out.String()
+	for _, op := range []string{"create", "get", "delete"} {
+		if !strings.Contains(report, op) {
+			t.Errorf("report has no %s row:\n%s", op, report)
+		}
+	}
+	if strings.Contains(report, "unsuccessful") {
+		t.Errorf("requests failed against the simulator:\n%s", report)
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"-rps", "0"},
+		{"-mix", "bogus"},
+		{"extra"},
+	} {
+		if _, err := parseFlags(args, &bytes.Buffer{}); err == nil {
+			t.Errorf("parseFlags(%q) succeeded, want an error", args)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// operation is a kind of request the generator sends
+type operation string
+
+const (
+	opCreate operation = "create"
+	opGet    operation = "get"
+	opDelete operation = "delete"
+)
+
+// operations fixes the order operations are picked and reported in
+var operations = []operation{opCreate, opGet, opDelete}
+
+// mix is the relative weight of each operation, set with -mix create=10,get=80,delete=10
+// Operations left out get no requests.
+type mix map[operation]int
+
+func defaultMix() mix {
+	return mix{opCreate: 10, opGet: 80, opDelete: 10}
+}
+
+func (m mix) String() string {
+	parts := make([]string, 0, len(operations))
+	for _, op := range operations {
+		if w := m[op]; w > 0 {
+			parts = append(parts, fmt.Sprintf("%s=%d", op, w))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set parses a mix, replacing the default
+func (m *mix) Set(value string) error {
+	parsed := mix{}
+	total := 0
+	for _, part := range strings.Split(value, ",") {
+		name, weight, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return fmt.Errorf("%q is not operation=weight", part)
+		}
+		op := operation(strings.TrimSpace(name))
+		if op != opCreate && op != opGet && op != opDelete {
+			return fmt.Errorf("unknown operation %q (want create, get or delete)", name)
+		}
+		w, err := strconv.Atoi(strings.TrimSpace(weight))
+		if err != nil || w < 0 {
+			return fmt.Errorf("weight of %s must be a non-negative integer", op)
+		}
+		parsed[op] = w
+		total += w
+	}
+	if total == 0 {
+		return fmt.Errorf("mix %q has no weight", value)
+	}
+	*m = parsed
+	return nil
+}
+
+// pick draws an operation with probability proportional to its weight
+func (m mix) pick(rng *rand.Rand) operation {
+	total := 0
+	for _, op := range operations {
+		total += m[op]
+	}
+	n := rng.IntN(total)
+	for _, op := range operations {
+		if n < m[op] {
+			return op
+		}
+		n -= m[op]
+	}
+	return opGet
+}
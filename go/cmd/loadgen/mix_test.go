@@ -0,0 +1,32 @@
+package main
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestMixSet(t *testing.T) {
+	var m mix
+	if err := m.Set("create=1, get=3"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if m[opCreate] != 1 || m[opGet] != 3 || m[opDelete] != 0 {
+		t.Errorf("mix = %v, want create=1,get=3", m)
+	}
+
+	for _, value := range []string{"create", "update=1", "get=-1", "get=0,delete=0"} {
+		if err := m.Set(value); err == nil {
+			t.Errorf("Set(%q) succeeded, want an error", value)
+		}
+	}
+}
+
+func TestMixPick(t *testing.T) {
+	m := mix{opGet: 1}
+	rng := rand.New(rand.NewPCG(1, 0))
+	for range 100 {
+		if op := m.pick(rng); op != opGet {
+			t.Fatalf("pick() = %s, want get only", op)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// opStats is the outcome of the requests of one operation
+type opStats struct {
+	latencies []time.Duration
+	statuses  map[int]int
+	failed    int // no response: connection errors and timeouts
+}
+
+func newOpStats() *opStats {
+	return &opStats{statuses: map[int]int{}}
+}
+
+// record adds a request's latency and status, or its failure to get a response
+func (s *opStats) record(latency time.Duration, status int, err error) {
+	if err != nil {
+		s.failed++
+		return
+	}
+	s.latencies = append(s.latencies, latency)
+	s.statuses[status]++
+}
+
+// percentile returns the latency below which p percent of responses arrived (nearest rank)
+// latencies must be sorted.
+func percentile(latencies []time.Duration, p float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	rank := int(float64(len(latencies))*p/100+0.5) - 1
+	return latencies[min(max(rank, 0), len(latencies)-1)]
+}
+
+// report summarises a run
+type report struct {
+	elapsed time.Duration
+	stats   map[operation]*opStats
+	dropped int
+}
+
+// write prints a table of throughput and latency percentiles per operation, followed by the
+// statuses of unsuccessful responses
+func (r *report) write(out io.Writer) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "operation\trequests\terrors\treq/s\tp50\tp90\tp99\tmax\t")
+
+	var unexpected []string
+	for _, op := range operations {
+		s := r.stats[op]
+		total := len(s.latencies) + s.failed
+		if total == 0 {
+			continue
+		}
+		slices.Sort(s.latencies)
+
+		errors := s.failed
+		for _, status := range slices.Sorted(maps.Keys(s.statuses)) {
+			if status != expectedStatus[op] {
+				errors += s.statuses[status]
+				unexpected = append(unexpected, fmt.Sprintf("%s: %d x %d", op, s.statuses[status], status))
+			}
+		}
+		if s.failed > 0 {
+			unexpected = append(unexpected, fmt.Sprintf("%s: %d x no response", op, s.failed))
+		}
+
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\t\n",
+			op, total, errors, float64(total)/r.elapsed.Seconds(),
+			round(percentile(s.latencies, 50)),
+			round(percentile(s.latencies, 90)),
+			round(percentile(s.latencies, 99)),
+			round(percentile(s.latencies, 100)),
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nelapsed %s, %d dropped (all workers busy)\n", r.elapsed.Round(time.Millisecond), r.dropped)
+	if len(unexpected) > 0 {
+		fmt.Fprintf(out, "unsuccessful: %s\n", strings.Join(unexpected, ", "))
+	}
+	return nil
+}
+
+// round trims a latency to a readable precision
+func round(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond)
+	default:
+		return d.Round(time.Microsecond)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	tests := map[float64]time.Duration{
+		50:  50 * time.Millisecond,
+		90:  90 * time.Millisecond,
+		99:  99 * time.Millisecond,
+		100: 100 * time.Millisecond,
+		0:   time.Millisecond,
+	}
+	for p, want := range tests {
+		if got := percentile(latencies, p); got != want {
+			t.Errorf("percentile(%v) = %v, want %v", p, got, want)
+		}
+	}
+
+	if got := percentile(nil, 99); got != 0 {
+		t.Errorf("percentile of no latencies = %v, want 0", got)
+	}
+}
+
+func TestReportWrite(t *testing.T) {
+	create := newOpStats()
+	create.record(2*time.Millisecond, http.StatusCreated, nil)
+	create.record(4*time.Millisecond, http.StatusConflict, nil)
+	create.record(0, 0, errors.New("timeout"))
+
+	r := &report{
+		elapsed: time.Second,
+		stats:   map[operation]*opStats{opCreate: create, opGet: newOpStats(), opDelete: newOpStats()},
+		dropped: 5,
+	}
+
+	var out bytes.Buffer
+	if err := r.write(&out); err != nil {
+		t.Fatalf("write() error = %v", err)
+	}
+
+	for _, want := range []string{"create", "5 dropped", "create: 1 x 409", "create: 1 x no response"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "delete") {
+		t.Errorf("report lists operations without requests:\n%s", out.String())
+	}
+}
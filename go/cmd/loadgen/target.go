@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// idempotencyKeyHeader matches middleware.IdempotencyKeyHeader
+const idempotencyKeyHeader = "X-Idempotency-Key"
+
+// target sends requests to the simulator under test
+type target struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// register creates a throwaway user and returns its token
+func (t *target) register(ctx context.Context) (string, error) {
+	id := uuid.NewString()
+	body, err := json.Marshal(map[string]string{
+		"email":    "loadgen-" + id + "@example.com",
+		"password": id,
+		"name":     "Load Generator",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+"/auth/register", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return "", fmt.Errorf("status %d: invalid response: %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("status %d: %s", resp.StatusCode, envelope.Message)
+	}
+	return envelope.Data.Token, nil
+}
+
+// create registers an entry and returns the response status
+// The entry's request ID doubles as the idempotency key POST /entries requires.
+func (t *target) create(ctx context.Context, entry *models.CreateEntryRequest) (int, error) {
+	return t.send(ctx, http.MethodPost, "/entries", entry, entry.RequestId)
+}
+
+// get looks up a key and returns the response status
+func (t *target) get(ctx context.Context, key string) (int, error) {
+	return t.send(ctx, http.MethodGet, "/entries/"+url.PathEscape(key), nil, "")
+}
+
+// delete deletes a key on behalf of its participant and returns the response status
+func (t *target) delete(ctx context.Context, key, participant string) (int, error) {
+	return t.send(ctx, http.MethodPost, "/entries/"+url.PathEscape(key)+"/delete", models.DeleteEntryRequest{
+		Key:         key,
+		Participant: participant,
+		Reason:      models.ReasonUserRequested,
+	}, "")
+}
+
+// send makes an authenticated request and drains the response so the connection is reused
+func (t *target) send(ctx context.Context, method, path string, body any, idempotencyKey string) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, t.baseURL+path, reader)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	if idempotencyKey != "" {
+		req.Header.Set(idempotencyKeyHeader, idempotencyKey)
+	}
+
+	resp, err := t.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
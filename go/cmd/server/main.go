@@ -5,7 +5,7 @@
 //
 //	@title						DICT Simulator API
 //	@version					1.0.0
-//	@description				A simulated implementation of the Brazilian Central Bank's DICT API for managing Pix keys.
+//	@description				A simulated implementation of the Brazilian Central Bank's DICT API for managing Pix keys. Every route below except health, metrics, docs and the JWKS is served under /api/v1 (e.g. /api/v1/entries); the unprefixed paths serve the same contract but are deprecated and answer with Deprecation, Sunset and Link headers.
 //	@termsOfService				http://swagger.io/terms/
 //
 //	@contact.name				API Support
@@ -23,47 +23,62 @@
 //	@name						Authorization
 //	@description				JWT Bearer token. Format: "Bearer {token}"
 //
+//	@securityDefinitions.apikey	AdminToken
+//	@in							header
+//	@name						X-Admin-Token
+//	@description				ADMIN_TOKEN value, required by the /admin routes
+//
 //	@tag.name					health
 //	@tag.description			Health check endpoints
 //
 //	@tag.name					auth
 //	@tag.description			Authentication endpoints for user registration and login
 //
+//	@tag.name					oauth
+//	@tag.description			OAuth2 client credentials tokens for machine clients, and the keys that verify tokens
+//
 //	@tag.name					entries
 //	@tag.description			DICT entry management for Pix keys
+//
+//	@tag.name					webhooks
+//	@tag.description			Callback registrations for directory events
+//
+//	@tag.name					settlements
+//	@tag.description			Test payments referenced by end-to-end ID
+//
+//	@tag.name					files
+//	@tag.description			Daily reconciliation files per participant
+//
+//	@tag.name					admin
+//	@tag.description			Administrative endpoints for test and demo environments
+
+//go:generate go run github.com/swaggo/swag/cmd/swag@v1.16.6 init --dir ../../ --generalInfo cmd/server/main.go --output ../../docs
 
 package main
 
 import (
 	"context"
+	"crypto/tls"
 	"net/http"
+	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/dict-simulator/go/internal/app"
 	"github.com/dict-simulator/go/internal/config"
 	"github.com/dict-simulator/go/internal/db"
+	"github.com/dict-simulator/go/internal/diagnostics"
 	"github.com/dict-simulator/go/internal/logger"
-	"github.com/dict-simulator/go/internal/middleware"
-	"github.com/dict-simulator/go/internal/models"
-	"github.com/dict-simulator/go/internal/modules/auth"
-	"github.com/dict-simulator/go/internal/modules/entries"
-	"github.com/dict-simulator/go/internal/ratelimit"
-	"github.com/dict-simulator/go/internal/router"
 	"github.com/dict-simulator/go/internal/server"
 	"github.com/dict-simulator/go/internal/telemetry"
 )
 
 // databases holds database connections
+// postgres is only connected when STORAGE=postgres; nothing is connected when STORAGE=memory.
 type databases struct {
-	mongo *db.Mongo
-	redis *db.Redis
-}
-
-// repositories holds all repository instances
-type repositories struct {
-	entry       *models.EntryRepository
-	user        *models.UserRepository
-	idempotency *models.IdempotencyRepository
+	mongo    *db.Mongo
+	postgres *db.Postgres
+	redis    *db.Redis
 }
 
 func main() {
@@ -73,40 +88,96 @@ func main() {
 	defer shutdownTelemetry()
 
 	dbs := setupDatabases()
-	defer dbs.mongo.Disconnect()
-	defer dbs.redis.Disconnect()
+	defer dbs.close()
+
+	application, err := app.BuildApplication(context.Background(), config.Env, app.Dependencies{
+		Mongo:      dbs.mongo,
+		Postgres:   dbs.postgres,
+		Redis:      dbs.redis,
+		ReadConfig: config.Read,
+		// SIGHUP re-reads the configuration, like POST /admin/config/reload
+		WatchSignals: true,
+	})
+	if err != nil {
+		logger.Fatal("Failed to build the application", zap.Error(err))
+	}
+	application.Start()
 
-	repos := setupRepositories(dbs.mongo)
+	srv := server.New(application, config.Env.Port, config.Env.ShutdownTimeout)
+	setupTLS(srv)
 
-	handler := setupApp(repos, dbs.redis)
+	application.OnShutdown(srv.OnShutdown)
+	startDiagnostics(srv)
 
-	srv := server.New(handler, config.Env.Port)
 	srv.ListenAndServeWithGracefulShutdown()
 }
 
-// setupTelemetry initializes OpenTelemetry tracing provider.
+// setupTLS serves HTTPS when a certificate is configured, verifying client certificates as configured.
+func setupTLS(srv *server.Server) {
+	if config.Env.TLSCertFile == "" {
+		return
+	}
+
+	clientAuth := tls.NoClientCert
+	switch config.Env.TLSClientAuth {
+	case config.TLSClientAuthOptional:
+		clientAuth = tls.VerifyClientCertIfGiven
+	case config.TLSClientAuthRequire:
+		clientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	tlsConfig, err := server.LoadTLSConfig(server.TLSOptions{
+		CertFile:         config.Env.TLSCertFile,
+		KeyFile:          config.Env.TLSKeyFile,
+		ClientAuth:       clientAuth,
+		ClientCAFile:     config.Env.TLSClientCAFile,
+		RequireICPBrasil: config.Env.TLSClientICPBrasil,
+	})
+	if err != nil {
+		logger.Fatal("Failed to load TLS configuration", zap.Error(err))
+	}
+	srv.UseTLS(tlsConfig)
+}
+
+// setupTelemetry initializes OpenTelemetry tracing and, with METRICS_EXPORTER=otlp, metrics providers.
 // Returns a cleanup function that should be deferred.
 func setupTelemetry() func() {
-	shutdownTracing, err := telemetry.InitTracer(config.Env.OTELExporterEndpoint)
+	shutdownTracing, err := telemetry.InitTracer(config.Env.OTELExporterEndpoint, config.Env.TracesSampler, config.Env.Redactor)
 	if err != nil {
 		logger.Fatal("Failed to initialize tracer", zap.Error(err))
 	}
 
-	if err := logger.Init(config.Env.Environment, nil); err != nil {
+	// The last export is flushed on shutdown
+	shutdownMetrics := func(context.Context) error { return nil }
+	if config.Env.MetricsExporter == config.MetricsExporterOTLP {
+		shutdownMetrics, err = telemetry.InitMeterProvider(config.Env.OTELExporterEndpoint, config.Env.MetricsExportInterval)
+		if err != nil {
+			logger.Fatal("Failed to initialize meter provider", zap.Error(err))
+		}
+	}
+
+	if err := logger.Init(config.Env.Environment, config.Env.LogLevel, config.Env.LogFormat, config.Env.Redactor, nil); err != nil {
 		panic("failed to initialize logger: " + err.Error())
 	}
 
 	return func() {
 		ctx := context.Background()
+		shutdownMetrics(ctx)
 		shutdownTracing(ctx)
 		logger.Sync()
 	}
 }
 
-// setupDatabases establishes connections to MongoDB and Redis.
-// Fatals on connection failure.
+// setupDatabases establishes connections to MongoDB, Redis and, when selected, PostgreSQL.
+// STORAGE=memory needs no databases at all.
+// Fatals on connection or migration failure.
 func setupDatabases() *databases {
-	mongoDB, err := db.ConnectMongo(config.Env.MongoDBURI)
+	if config.Env.Storage == config.StorageMemory {
+		logger.Info("Using in-memory storage; data is lost on restart")
+		return &databases{}
+	}
+
+	mongoDB, err := db.ConnectMongo(config.Env.MongoDBURI, config.Env.MongoDB)
 	if err != nil {
 		logger.Fatal("Failed to connect to MongoDB", zap.Error(err))
 	}
@@ -116,46 +187,56 @@ func setupDatabases() *databases {
 		logger.Fatal("Failed to connect to Redis", zap.Error(err))
 	}
 
+	var postgresDB *db.Postgres
+	if config.Env.Storage == config.StoragePostgres {
+		postgresDB, err = db.ConnectPostgres(config.Env.PostgresURL)
+		if err != nil {
+			logger.Fatal("Failed to connect to PostgreSQL", zap.Error(err))
+		}
+		if err := postgresDB.Migrate(context.Background()); err != nil {
+			logger.Fatal("Failed to migrate PostgreSQL", zap.Error(err))
+		}
+	}
+
 	return &databases{
-		mongo: mongoDB,
-		redis: redisDB,
+		mongo:    mongoDB,
+		postgres: postgresDB,
+		redis:    redisDB,
 	}
 }
 
-// setupRepositories creates all repository instances and ensures database indexes.
-// Fatals on index creation failure.
-func setupRepositories(mongoDB *db.Mongo) *repositories {
-	entryRepo := models.NewEntryRepository(mongoDB)
-	userRepo := models.NewUserRepository(mongoDB)
-	idempotencyRepo := models.NewIdempotencyRepository(mongoDB)
-
-	ctx := context.Background()
-
-	if err := entryRepo.EnsureIndexes(ctx); err != nil {
-		logger.Fatal("Failed to ensure entry indexes", zap.Error(err))
-	}
-	if err := userRepo.EnsureIndexes(ctx); err != nil {
-		logger.Fatal("Failed to ensure user indexes", zap.Error(err))
+// close disconnects whichever databases were connected
+func (dbs *databases) close() {
+	if dbs.mongo != nil {
+		dbs.mongo.Disconnect()
 	}
-	if err := idempotencyRepo.EnsureIndexes(ctx); err != nil {
-		logger.Fatal("Failed to ensure idempotency indexes", zap.Error(err))
+	if dbs.redis != nil {
+		dbs.redis.Disconnect()
 	}
-
-	return &repositories{
-		entry:       entryRepo,
-		user:        userRepo,
-		idempotency: idempotencyRepo,
+	if dbs.postgres != nil {
+		dbs.postgres.Disconnect()
 	}
 }
 
-// setupApp initializes handlers, middleware, and the HTTP router.
-// Returns the fully configured HTTP handler ready to serve requests.
-func setupApp(repos *repositories, redisDB *db.Redis) http.Handler {
-	rateLimitBucket := ratelimit.NewBucket(redisDB.Client)
-	mwManager := middleware.NewManager(repos.idempotency, rateLimitBucket, config.Env.RateLimitEnabled)
-
-	authHandler := auth.NewHandler(repos.user, config.Env.JWTSecret)
-	entriesHandler := entries.NewHandler(repos.entry)
+// startDiagnostics serves pprof and expvar on DIAGNOSTICS_ADDR, apart from the API port, until srv shuts down.
+// The listener has no authentication, so it should only be reachable by operators (e.g. localhost).
+func startDiagnostics(srv *server.Server) {
+	if config.Env.DiagnosticsAddr == "" {
+		return
+	}
 
-	return router.Setup(config.Env, authHandler, entriesHandler, mwManager, ratelimit.DefaultPolicies())
+	// No write timeout: CPU profiles and execution traces stream for as long as ?seconds= asks
+	diag := &http.Server{
+		Addr:              config.Env.DiagnosticsAddr,
+		Handler:           diagnostics.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		logger.Info("Diagnostics listening", zap.String("addr", config.Env.DiagnosticsAddr))
+		if err := diag.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("Diagnostics server failed", zap.Error(err))
+		}
+	}()
+
+	srv.OnShutdown("diagnostics", diag.Shutdown)
 }
@@ -23,9 +23,34 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Public keys that verify the tokens issued by the simulator, user and client tokens alike (RFC 7517). Tokens name their key in the kid header; keys are rotated every JWT_KEY_ROTATION_INTERVAL and stay published until the tokens they signed have expired. Verifiers should fetch the set again when a token names a key they have not seen. Empty when tokens are signed with the shared HS256 secret.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "summary": "JSON Web Key Set",
+                "responses": {
+                    "200": {
+                        "description": "Public keys, newest first",
+                        "schema": {
+                            "$ref": "#/definitions/jwtkeys.JWKS"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{participant}/{branch}/{accountNumber}/close": {
             "post": {
-                "description": "Authenticate a user with email and password. Returns a JWT token on success.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes every key linked to the account (reason ACCOUNT_CLOSURE), or moves them all to another account of the same participant when transferTo is given. Each key gets its own ENTRY_DELETED or ENTRY_UPDATED event.",
                 "consumes": [
                     "application/json"
                 ],
@@ -33,23 +58,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "entries"
                 ],
-                "summary": "User login",
+                "summary": "Close an account",
                 "parameters": [
                     {
-                        "description": "User login credentials",
+                        "type": "string",
+                        "description": "ISPB of the participant holding the account",
+                        "name": "participant",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch",
+                        "name": "branch",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account number",
+                        "name": "accountNumber",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Where to move the keys, if anywhere",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/auth.LoginRequest"
+                            "$ref": "#/definitions/models.CloseAccountRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Login successful",
+                        "description": "Account closed",
                         "schema": {
                             "allOf": [
                                 {
@@ -59,7 +105,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/auth.AuthResponse"
+                                            "$ref": "#/definitions/models.CloseAccountResponse"
                                         }
                                     }
                                 }
@@ -67,77 +113,31 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid request body or account",
                         "schema": {
                             "$ref": "#/definitions/httputil.APIResponse"
                         }
                     },
                     "401": {
-                        "description": "Invalid credentials",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/httputil.APIResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "Missing scope or participant mismatch",
                         "schema": {
                             "$ref": "#/definitions/httputil.APIResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/auth/register": {
-            "post": {
-                "description": "Create a new user account with email, password, and name. Returns a JWT token on success.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "auth"
-                ],
-                "summary": "Register a new user",
-                "parameters": [
-                    {
-                        "description": "User registration details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/auth.RegisterRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "User registered successfully",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/httputil.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/auth.AuthResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
                     },
-                    "400": {
-                        "description": "Invalid request body",
+                    "404": {
+                        "description": "No entry linked to the account",
                         "schema": {
                             "$ref": "#/definitions/httputil.APIResponse"
                         }
                     },
-                    "409": {
-                        "description": "User already exists",
+                    "429": {
+                        "description": "Rate limit exceeded",
                         "schema": {
                             "$ref": "#/definitions/httputil.APIResponse"
                         }
@@ -151,45 +151,65 @@ const docTemplate = `{
                 }
             }
         },
-        "/entries": {
-            "post": {
+        "/admin/audit": {
+            "get": {
                 "security": [
+                    {
+                        "AdminToken": []
+                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Register a new Pix key entry in the DICT system. The key must be unique and valid for its type.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns the actions taken through the admin routes, newest first, a page at a time: who took each one, its parameters, status and correlation ID. Reads (GET) are not recorded. Pass nextCursor back as cursor, with the same sort, to read older actions; it is absent on the last page. sort=createdAt lists oldest first, and fields cuts each action down to the named fields.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "entries"
+                    "admin"
                 ],
-                "summary": "Create a new DICT entry",
+                "summary": "List admin actions",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Idempotency key for request deduplication",
-                        "name": "X-Idempotency-Key",
-                        "in": "header",
-                        "required": true
+                        "description": "Only actions by this actor (admin-token or client:{id})",
+                        "name": "actor",
+                        "in": "query"
                     },
                     {
-                        "description": "Entry creation request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateEntryRequest"
-                        }
+                        "type": "string",
+                        "description": "Only this action (e.g. admin.seed)",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (1-1000, default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "-createdAt (default) or createdAt for oldest first",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated record fields to return, e.g. action,status",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Entry created successfully",
+                    "200": {
+                        "description": "Page of admin actions",
                         "schema": {
                             "allOf": [
                                 {
@@ -199,7 +219,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.EntryResponse"
+                                            "$ref": "#/definitions/models.AdminAuditPage"
                                         }
                                     }
                                 }
@@ -207,7 +227,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or key format",
+                        "description": "Invalid limit, cursor, sort or fields",
                         "schema": {
                             "$ref": "#/definitions/httputil.APIResponse"
                         }
@@ -218,14 +238,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/httputil.APIResponse"
                         }
                     },
-                    "409": {
-                        "description": "Key already exists",
-                        "schema": {
-                            "$ref": "#/definitions/httputil.APIResponse"
-                        }
-                    },
-                    "429": {
-                        "description": "Rate limit exceeded",
+                    "403": {
+                        "description": "Bearer token without the admin scope",
                         "schema": {
                             "$ref": "#/definitions/httputil.APIResponse"
                         }
@@ -239,36 +253,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/entries/{key}": {
+        "/admin/brownout": {
             "get": {
                 "security": [
+                    {
+                        "AdminToken": []
+                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a Pix key entry from the DICT system using the key value",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns the brownout last started, its phase and its intensity on the simulated clock. An ended brownout is still returned, in the ENDED phase.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "entries"
-                ],
-                "summary": "Get a DICT entry by key",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "The Pix key to retrieve (CPF, CNPJ, EMAIL, PHONE, or EVP)",
-                        "name": "key",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "Get the brownout",
                 "responses": {
                     "200": {
-                        "description": "Entry found",
+                        "description": "Brownout",
                         "schema": {
                             "allOf": [
                                 {
@@ -278,39 +283,27 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.EntryResponse"
+                                            "$ref": "#/definitions/admin.BrownoutResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Key is required",
-                        "schema": {
-                            "$ref": "#/definitions/httputil.APIResponse"
-                        }
-                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/httputil.APIResponse"
                         }
                     },
-                    "404": {
-                        "description": "Entry not found",
-                        "schema": {
-                            "$ref": "#/definitions/httputil.APIResponse"
-                        }
-                    },
-                    "429": {
-                        "description": "Rate limit exceeded",
+                    "403": {
+                        "description": "Bearer token without the admin scope",
                         "schema": {
                             "$ref": "#/definitions/httputil.APIResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "No brownout started",
                         "schema": {
                             "$ref": "#/definitions/httputil.APIResponse"
                         }
@@ -319,11 +312,14 @@ const docTemplate = `{
             },
             "put": {
                 "security": [
+                    {
+                        "AdminToken": []
+                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing Pix key entry. EVP keys cannot be updated. Only account info, name, and trade name can be modified.",
+                "description": "Degrades the DICT routes progressively, to exercise client circuit breakers and adaptive retries: the share of requests answered 503 (errorRate) and delayed by latencyMs (latencyRate) grows linearly from none to these peaks over rampUp, stays there for hold, then shrinks back to none over rampDown. The ramp follows the simulated clock, so POST /admin/time/advance moves it along. Starting a brownout replaces the one in progress; fault rules keep applying alongside.",
                 "consumes": [
                     "application/json"
                 ],
@@ -331,30 +327,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "entries"
+                    "admin"
                 ],
-                "summary": "Update a DICT entry",
+                "summary": "Start a brownout",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "The Pix key to update",
-                        "name": "key",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Update entry request",
+                        "description": "Peak rates and ramp",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateEntryRequest"
+                            "$ref": "#/definitions/admin.BrownoutRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Entry updated successfully",
+                        "description": "Brownout started",
                         "schema": {
                             "allOf": [
                                 {
@@ -364,7 +353,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.EntryResponse"
+                                            "$ref": "#/definitions/admin.BrownoutResponse"
                                         }
                                     }
                                 }
@@ -372,7 +361,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request body, key mismatch, or EVP key update attempt",
+                        "description": "Invalid rates or durations",
                         "schema": {
                             "$ref": "#/definitions/httputil.APIResponse"
                         }
@@ -383,20 +372,52 @@ const docTemplate = `{
                             "$ref": "#/definitions/httputil.APIResponse"
                         }
                     },
-                    "404": {
-                        "description": "Entry not found",
+                    "403": {
+                        "description": "Bearer token without the admin scope",
                         "schema": {
                             "$ref": "#/definitions/httputil.APIResponse"
                         }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
                     },
-                    "429": {
-                        "description": "Rate limit exceeded",
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ends the brownout at once, without ramping down",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Stop the brownout",
+                "responses": {
+                    "200": {
+                        "description": "Brownout stopped",
                         "schema": {
                             "$ref": "#/definitions/httputil.APIResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No brownout started",
                         "schema": {
                             "$ref": "#/definitions/httputil.APIResponse"
                         }
@@ -404,45 +425,100 @@ const docTemplate = `{
                 }
             }
         },
-        "/entries/{key}/delete": {
+        "/admin/config/reload": {
             "post": {
                 "security": [
+                    {
+                        "AdminToken": []
+                    },
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a Pix key entry from the DICT system. The requesting participant must own the entry.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Re-reads the environment and CONFIG_FILE and applies rate limiting, latency profiles and FAULT_RULES without a restart, as SIGHUP does. Other settings need a restart. An invalid configuration is rejected as a whole.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "entries"
+                    "admin"
                 ],
-                "summary": "Delete a DICT entry",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "The Pix key to delete",
-                        "name": "key",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Delete entry request with participant and reason",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                "summary": "Reload the configuration",
+                "responses": {
+                    "200": {
+                        "description": "Configuration reloaded",
                         "schema": {
-                            "$ref": "#/definitions/models.DeleteEntryRequest"
-                        }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.ConfigResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid configuration; nothing was applied",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "No configuration to reload (embedded simulator)",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/erasure/{taxId}": {
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes the entries owned by the CPF or CNPJ, the fraud markers left on its deleted entries, the periods in which it held keys in their ownership history, the cached idempotent responses that name it and its key creation counts, and reports what was removed. Entries are deleted one by one through the usual delete path and reported with an ENTRY_DELETED event each, whatever the EVENT_SOURCE. The audit record of the erasure masks the tax ID. The simulator has no claims, so there are none to erase.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Erase a tax ID's data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CPF (11 digits) or CNPJ (14 digits)",
+                        "name": "taxId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Entry deleted successfully",
+                        "description": "Subject erased",
                         "schema": {
                             "allOf": [
                                 {
@@ -452,7 +528,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.DeleteEntryResponse"
+                                            "$ref": "#/definitions/admin.ErasureResponse"
                                         }
                                     }
                                 }
@@ -460,7 +536,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or key mismatch",
+                        "description": "taxId is not a valid CPF or CNPJ",
                         "schema": {
                             "$ref": "#/definitions/httputil.APIResponse"
                         }
@@ -472,19 +548,68 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - participant mismatch",
+                        "description": "Bearer token without the admin scope",
                         "schema": {
                             "$ref": "#/definitions/httputil.APIResponse"
                         }
                     },
-                    "404": {
-                        "description": "Entry not found",
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/export": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Writes every entry, in key order, with its timestamps. The default JSON document lists the participants owning entries and can be posted back to /admin/import as is; format=ndjson streams one entry per line instead, without holding the directory in memory. Claims are not part of the snapshot, as the simulator has none.",
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Export the directory",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "json (default) or ndjson",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Directory snapshot",
+                        "schema": {
+                            "$ref": "#/definitions/admin.Snapshot"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown format",
                         "schema": {
                             "$ref": "#/definitions/httputil.APIResponse"
                         }
                     },
-                    "429": {
-                        "description": "Rate limit exceeded",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
                         "schema": {
                             "$ref": "#/definitions/httputil.APIResponse"
                         }
@@ -498,227 +623,5793 @@ const docTemplate = `{
                 }
             }
         },
-        "/health": {
+        "/admin/faults": {
             "get": {
-                "description": "Returns the health status of the service",
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the fault rules currently applied to DICT routes",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "admin"
                 ],
-                "summary": "Health check",
+                "summary": "List fault injection rules",
                 "responses": {
                     "200": {
-                        "description": "Service is healthy",
+                        "description": "Active fault rules",
                         "schema": {
-                            "$ref": "#/definitions/health.HealthResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/chaos.Fault"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Injects latency, 5xx errors or connection resets on matching routes and keys. Rules match when both route and keySuffix match (empty fields match everything) and then fire with the given probability (default 1).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create a fault injection rule",
+                "parameters": [
+                    {
+                        "description": "Fault rule (id and createdAt are ignored)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chaos.Fault"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Fault rule created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/chaos.Fault"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes every active fault rule, restoring normal behaviour",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Clear all fault injection rules",
+                "responses": {
+                    "200": {
+                        "description": "Fault rules cleared",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/faults/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a fault rule by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete a fault injection rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Fault rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Fault rule deleted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.DeleteFaultResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Fault rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/import": {
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restores the entries of a snapshot from /admin/export, timestamps included, so the same dataset can be loaded in any environment. Send the JSON document, or one entry per line with Content-Type application/x-ndjson; NDJSON is read and inserted in batches as it arrives. Snapshots may be up to MAX_IMPORT_BYTES. Keys that are already registered are skipped; nothing is deleted first (see /admin/reset). Entries are validated like new ones, and missing timestamps default to the simulated time. An invalid NDJSON line stops the import, leaving the batches before it imported. No events are published, except with EVENT_SOURCE=changestream.",
+                "consumes": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Import a directory snapshot",
+                "parameters": [
+                    {
+                        "description": "Snapshot to restore",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.Snapshot"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Snapshot imported",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.ImportResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid snapshot",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Snapshot larger than MAX_IMPORT_BYTES",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/key-policies": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the key types that can't be created and whether PHONE keys must be Brazilian numbers: KEY_TYPES_DISABLED and PHONE_KEYS_BRAZIL_ONLY unless changed through PUT /admin/key-policies",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get the key policies",
+                "responses": {
+                    "200": {
+                        "description": "Key policies",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.KeyPoliciesResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the key policies at once, so a test environment can mimic another production policy: creations of a disabled key type are refused with KEY_TYPE_DISABLED, and with brazilianPhonesOnly PHONE keys that aren't +55 mobile or landline numbers with INVALID_PHONE. POST /keys/validate reports the same. Registered keys are not affected. The change lasts until the next change or restart, in this instance only; a config reload leaves it alone.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Change the key policies",
+                "parameters": [
+                    {
+                        "description": "New key policies",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.KeyPoliciesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Key policies changed",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.KeyPoliciesResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown key type",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/log-level": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the minimum level the server logs, LOG_LEVEL unless changed through PUT /admin/log-level",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get the log level",
+                "responses": {
+                    "200": {
+                        "description": "Log level",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.LogLevelResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes the minimum level the server logs at once, e.g. to debug while chasing an issue. The change lasts until the next change or restart; a config reload leaves it alone.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Change the log level",
+                "parameters": [
+                    {
+                        "description": "New level",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.LogLevelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Log level changed",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.LogLevelResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown level",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/oauth/clients": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the registered clients, oldest first, without their secrets.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List OAuth clients",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only the clients of this participant ISPB",
+                        "name": "participant",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Clients found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.OAuthClientResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Registers a client that gets access tokens from POST /oauth/token with the client credentials grant. Its tokens act for the participant and only reach the routes of the granted scopes; the admin scope reaches the /admin routes in place of X-Admin-Token. The client secret is generated and returned only in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Register an OAuth client",
+                "parameters": [
+                    {
+                        "description": "Participant, name and scopes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateOAuthClientRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Client registered",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.OAuthClientResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/oauth/clients/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the client. Access tokens already issued to it are refused from then on.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete an OAuth client",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Client deleted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.DeleteOAuthClientResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Client not found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/quotas": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the default quota, QUOTA_MAX_ENTRIES and QUOTA_REQUESTS_PER_MINUTE unless changed through PUT /admin/quotas, and the participants given a quota of their own. Zero fields set no cap.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List the quotas",
+                "responses": {
+                    "200": {
+                        "description": "Quotas",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.QuotasResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the quota of every participant without one of its own. Creations by a participant already holding maxEntries entries are refused with ENTRY_QUOTA_EXCEEDED, and DICT requests beyond requestsPerMinute with REQUEST_QUOTA_EXCEEDED; zero sets no cap. Entries already stored are kept. The change lasts until the next change or restart, in this instance only; a config reload leaves it alone.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Change the default quota",
+                "parameters": [
+                    {
+                        "description": "New default quota",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/quota.Quota"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Default quota changed",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.QuotasResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/quotas/{participant}": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the quota the participant is held to, its own or the default, and how many entries it holds",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get a participant's quota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Participant ISPB",
+                        "name": "participant",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Participant's quota",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.ParticipantQuotaResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Participant is not an 8-digit ISPB",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gives the participant a quota of its own in place of the default, e.g. to let one team's load test through or to stop its seeding job from filling the shared database. Zero fields set no cap. Entries already stored are kept, even beyond maxEntries. The change lasts until the next change or restart, in this instance only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Change a participant's quota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Participant ISPB",
+                        "name": "participant",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Participant's quota",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/quota.Quota"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Participant's quota changed",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.ParticipantQuotaResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid participant or request body",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the participant's own quota, so the default applies to it again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Remove a participant's quota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Participant ISPB",
+                        "name": "participant",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Participant back on the default quota",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.ParticipantQuotaResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Participant is not an 8-digit ISPB",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Participant has no quota of its own",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reports/usage": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns each participant's requests, 4xx and 5xx answers, error rate and consumed rate limit tokens per day (UTC, on the simulated clock), by day and then participant. Requests made with the admin token or without a participant are not counted. Counts are current as of the request.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report usage by participant and day",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only this participant's usage (ISPB)",
+                        "name": "participant",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day, inclusive (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, inclusive (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Usage per participant and day",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.UsageReportResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid from or to",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reset": {
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes the participant's entries, the requestIds it created them with and the idempotency records it claimed, and flushes its rate limit buckets. No ENTRY_DELETED events are published for the entries, except with EVENT_SOURCE=changestream, which reports every deletion. Other participants are not affected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reset a participant's data",
+                "parameters": [
+                    {
+                        "description": "Participant to reset",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.ResetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Participant reset",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.ResetResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/seed": {
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generates N entries with valid CPFs/CNPJs, random key types and a configurable participant distribution. Keys that already exist are skipped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Seed the directory with generated entries",
+                "parameters": [
+                    {
+                        "description": "Seeding options",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.SeedRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Entries seeded",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.SeedResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/stubs": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the stubs currently answering DICT requests, in registration order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List stubs",
+                "responses": {
+                    "200": {
+                        "description": "Stubs",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/stubs.Stub"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Answers matching requests with a canned status, JSON body and headers, after an optional delay, instead of running the route. A stub matches when its route (a pattern such as GET /entries/{key}), key (the {key} path value) and request headers all match; empty fields match everything. When several match, the most recently added wins. Stubs answer before authentication, rate limiting and idempotency, but after fault injection; responses carry the stub's ID in X-Stub-Id and are not checked against the OpenAPI document.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create a stub",
+                "parameters": [
+                    {
+                        "description": "Stub (id and createdAt are ignored)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/stubs.Stub"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Stub created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/stubs.Stub"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes every stub, so all requests reach their handlers again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Clear all stubs",
+                "responses": {
+                    "200": {
+                        "description": "Stubs cleared",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/stubs/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a stub by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete a stub",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stub ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stub deleted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.DeleteStubResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Stub not found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/time": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the time seen by entries, idempotency expiry and rate limit refills, and how far it is ahead of the wall clock",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get the simulated time",
+                "responses": {
+                    "200": {
+                        "description": "Simulated time",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.TimeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes any offset so the simulated clock matches the wall clock again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reset the simulated time",
+                "responses": {
+                    "200": {
+                        "description": "Clock reset",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.TimeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/time/advance": {
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves the simulated clock forward so expiries and bucket refills can be exercised without waiting. Time keeps flowing from the new point.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Advance the simulated time",
+                "parameters": [
+                    {
+                        "description": "How far to advance",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.AdvanceTimeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Clock advanced",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.TimeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid duration",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the registered users in creation order, a page at a time. Pass nextCursor back as cursor, with the same sort, to read the next page; it is absent on the last one. fields cuts each user down to the named fields.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (1-1000, default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "createdAt (default) or -createdAt for newest first",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated user fields to return, e.g. id,email",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of users",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserPage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit, cursor, sort or fields",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the user and forgets their failed logins. Tokens already issued to them are refused from then on; the entries they registered are kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User deleted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.DeleteUserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/disable": {
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Refuses the user's logins and every token already issued to them, until the user is enabled again. Their data is kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Disable a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User disabled",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.UserStatusResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/enable": {
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lets a disabled user log in again. Tokens issued before they were disabled are accepted again while they last.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Enable a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User enabled",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.UserStatusResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/password-reset": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the token issued by the user's latest POST /auth/password-reset, as the reset email would carry it. Tokens expire by the wall clock, not the simulated one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Read a pending password reset token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pending reset token",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.PasswordResetResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Malformed user ID, or no reset pending",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/unlock": {
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lifts the lock placed on an account after too many failed logins and forgets its failures, so the user can log in again at once. Unlocking an account that is not locked only forgets its failures.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Unlock a user account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Account unlocked",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/admin.UnlockResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bearer token without the admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Malformed user ID",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/change-password": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the authenticated user's password. The current password is checked like a login: wrong guesses count towards the account lockout.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Change password",
+                "parameters": [
+                    {
+                        "description": "Current and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password changed",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized, or wrong current password",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User no longer exists",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Account locked after too many failed logins",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts from this address",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Authenticate a user with email and password. Returns a JWT token on success, carrying the requested scopes, or every scope a user can hold (all but admin) when scope is omitted. Ask for entries:read alone for a monitoring token that can't change the directory.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "User login",
+                "parameters": [
+                    {
+                        "description": "User login credentials",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/auth.AuthResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid credentials, or disabled account",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Account locked after too many failed logins",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts from this address",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the profile of the user the bearer token was issued to.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Get the current user",
+                "responses": {
+                    "200": {
+                        "description": "Current user",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized, or disabled account",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes the email and/or name of the user the bearer token was issued to; fields left out keep their value. Tokens already issued stay valid but carry the old email and name until the next login.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Update the current user",
+                "parameters": [
+                    {
+                        "description": "New email and/or name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Profile updated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, or nothing to update",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized, or disabled account",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Email used by another user",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password-reset": {
+            "post": {
+                "description": "Issues a single-use reset token for the account with this email, replacing any earlier one. The simulator sends no email: read the token with GET /admin/users/{id}/password-reset. The answer is the same whether or not the email is registered, so it can't be used to find accounts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Request a password reset",
+                "parameters": [
+                    {
+                        "description": "Account email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.PasswordResetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Reset requested",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts from this address",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password-reset/confirm": {
+            "post": {
+                "description": "Sets a new password for the account the reset token was issued to. The token can be used once, and the account's failed logins and lock are cleared.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Complete a password reset",
+                "parameters": [
+                    {
+                        "description": "Reset token and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.ConfirmPasswordResetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password reset",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, or invalid, expired or used token",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts from this address",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Create a new user account with email, password, and name. Returns a JWT token on success.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "User registration details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User registered successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/auth.AuthResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "User already exists",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts from this address",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/entries": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Register a new Pix key entry in the DICT system. The key must be unique and valid for its type, and the owner consistent with it: NATURAL_PERSON owners have an 11-digit CPF, LEGAL_PERSON owners a 14-digit CNPJ (and are the only ones with a trade name), and CPF/CNPJ keys are the owner's tax ID. Inconsistent owners are rejected with INCONSISTENT_OWNERSHIP and a violation per field. An account whose branch is not 4 digits or whose number is not up to 20 digits (ending with its check digit when ACCOUNT_NUMBER_CHECK_DIGIT is set) is rejected with INVALID_ACCOUNT, and one opened in the future or before ACCOUNT_MIN_OPENING_DATE with INVALID_OPENING_DATE, each with a violation per field. An owner that has created its daily limit of keys (KEY_CREATION_DAILY_LIMIT) is refused with ENTRY_LIMIT_EXCEEDED and a Retry-After until the next day. A requestId the account's participant already created an entry with is refused with REQUEST_ID_ALREADY_USED, whatever X-Idempotency-Key says; one whose request was refused can be sent again. Key types disabled through PUT /admin/key-policies (or KEY_TYPES_DISABLED) are refused with KEY_TYPE_DISABLED, and while only Brazilian phones are accepted, a PHONE key that isn't a +55 mobile or landline number with INVALID_PHONE. A participant already holding the entries its quota allows (see /admin/quotas) is refused with ENTRY_QUOTA_EXCEEDED until it deletes some.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "entries"
+                ],
+                "summary": "Create a new DICT entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Idempotency key for request deduplication",
+                        "name": "X-Idempotency-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Entry creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Entry created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.EntryResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, key format, owner or account",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope, participant mismatch or entry quota exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Key already exists, requestId already used, or idempotency key in use",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Key type disabled by the key policies",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit or owner's daily key limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/entries/{key}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a Pix key entry from the DICT system using the key value. Participants other than the entry's own see a natural person owner masked (CPF as ***982247**, name as initials) unless they name the payer in PI-PayerId. Responses carry ETag and Last-Modified; send them back in If-None-Match or If-Modified-Since to get a bodiless 304 while the entry is unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "entries"
+                ],
+                "summary": "Get a DICT entry by key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The Pix key to retrieve (CPF, CNPJ, EMAIL, PHONE, or EVP)",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "CPF or CNPJ of the payer the lookup is made for; shows the owner unmasked",
+                        "name": "PI-PayerId",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of a previous response",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Entry found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.EntryResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Weak entity tag of the entry"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Entry's last update"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Entry not modified"
+                    },
+                    "400": {
+                        "description": "Key is required or invalid PI-PayerId",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope or participant mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Entry not found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing Pix key entry. The request's participant must own the entry and EVP keys cannot be updated. Only account info, name, and trade name can be modified. Reason RFB_VALIDATION also clears a PENDING_RFB_VALIDATION status set by the periodic Receita Federal review.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "entries"
+                ],
+                "summary": "Update a DICT entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The Pix key to update",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update entry request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Entry updated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.EntryResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, account, key mismatch, or EVP key update attempt",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Entry owned by another participant, missing scope or participant mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Entry not found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/entries/{key}/delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a Pix key entry from the DICT system. The requesting participant must own the entry. With reason FRAUD a fraud marker is left on the key (see GET /entries/{key}/fraud-markers); with ACCOUNT_CLOSURE every other key linked to the same account is released too and listed in releasedKeys.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "entries"
+                ],
+                "summary": "Delete a DICT entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The Pix key to delete",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Delete entry request with participant and reason",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DeleteEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Entry deleted successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.DeleteEntryResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or key mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - participant mismatch or missing scope",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Entry not found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/entries/{key}/fraud-markers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the markers left each time the key was deleted with reason FRAUD, newest first. Markers outlive the entry, so a key registered again still shows them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "entries"
+                ],
+                "summary": "List fraud markers of a key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The Pix key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Fraud markers (possibly none)",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.FraudMarkerResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope or participant mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/entries/{key}/ownership-history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns each period in which a participant held the key for an owner, newest first, as the directory's statistics do for fraud analysis. A period starts when the key is registered, or moves to another participant (portability) or owner, and ends when it is deleted or moves again; the current one has no to. Account and name changes don't start a period. The history outlives the entry, so a released key still shows it. It is built from the entry events, so changes no event is published for, such as participant resets, don't show. Owners of periods held by other participants are masked as in GET /entries/{key}.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "entries"
+                ],
+                "summary": "List the ownership history of a key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The Pix key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ownership periods (possibly none)",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.KeyOwnershipResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope or participant mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/files": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the daily reconciliation files generated for a participant, newest first. Each covers every key the participant held when it was generated.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "List reconciliation files",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Participant ISPB",
+                        "name": "participant",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Files found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ReconciliationFileResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Participant is required",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope or participant mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/files/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the file's rows (key, CID and last modification, in key order) as CSV, or as XML with format=xml",
+                "produces": [
+                    "text/csv",
+                    "application/xml",
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Download a reconciliation file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv (default) or xml",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reconciliation file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown format",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope or participant mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "File not found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Returns the health status of the service",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "Service is healthy",
+                        "schema": {
+                            "$ref": "#/definitions/health.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/keys/validate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Runs the checks POST /entries applies to a key (format for its type, allowed by the key policies, not already registered) without creating anything, so clients can give feedback before submitting. Invalid keys are reported in the result, not as an error status.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "entries"
+                ],
+                "summary": "Validate a key",
+                "parameters": [
+                    {
+                        "description": "Key to validate",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidateKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Validation result",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.KeyValidationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope or participant mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "description": "Returns Prometheus metrics for monitoring",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Prometheus metrics",
+                "responses": {
+                    "200": {
+                        "description": "Prometheus metrics in text format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/token": {
+            "post": {
+                "description": "Client credentials grant (RFC 6749 section 4.4) for participants' service-to-service calls. Authenticate with HTTP Basic (client ID and secret) or with client_id and client_secret in the body, not both. The token carries the client's participant ISPB and the granted scopes: all of the client's scopes when scope is omitted. Errors of the grant itself use the OAuth error format instead of the DICT envelope.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "summary": "Issue an access token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Must be client_credentials",
+                        "name": "grant_type",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Space-delimited scopes, a subset of the client's",
+                        "name": "scope",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client ID, when not sent with HTTP Basic",
+                        "name": "client_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client secret, when not sent with HTTP Basic",
+                        "name": "client_secret",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Access token",
+                        "schema": {
+                            "$ref": "#/definitions/oauth.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid_request, unsupported_grant_type or invalid_scope",
+                        "schema": {
+                            "$ref": "#/definitions/oauth.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "invalid_client",
+                        "schema": {
+                            "$ref": "#/definitions/oauth.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts from this address",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/participants/{ispb}/entries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the entries registered under the ISPB in key order, a page at a time. With since, only entries created or updated at or after that instant are listed. Pass nextCursor back as cursor, with the same sort, to read the next page; it is absent on the last one. fields cuts each entry down to the named fields. A token speaking for a participant can only list its own entries; users speaking for none see natural person owners masked.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "entries"
+                ],
+                "summary": "List a participant's entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Participant ISPB",
+                        "name": "ispb",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (1-1000, default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "key (default) or -key for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated entry fields to return, e.g. key,keyType",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 timestamp; only entries modified at or after it",
+                        "name": "since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of entries",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.EntryPage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit, cursor, sort, fields or since",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope or participant mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/settlements": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records a test payment between two registered keys. The participants are taken from the keys' entries and the end-to-end ID must start with the payer participant's ISPB. A token speaking for a participant can only settle as the payer. Amounts are in centavos.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "Post a settlement",
+                "parameters": [
+                    {
+                        "description": "Settlement",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSettlementRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Settlement recorded",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.SettlementResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or end-to-end ID",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope or participant mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "End-to-end ID already settled",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Payer or payee key not registered",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/settlements/{endToEndId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a settlement by its end-to-end ID, to the payer's or payee's participant",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "Get a settlement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "End-to-end ID",
+                        "name": "endToEndId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Settlement found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.SettlementResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope or participant mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Settlement not found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the webhooks registered by a participant. Secrets are not returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhooks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Participant ISPB",
+                        "name": "participant",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhooks found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.WebhookResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Participant is required",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope or participant mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Registers a callback URL that receives signed POSTs for the participant's events. The signing secret is only returned in this response; it is generated when omitted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Register a webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook registration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Webhook registered",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.WebhookResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope or participant mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a webhook registration. Its delivery log is kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook deleted",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope or participant mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the most recent delivery attempts (up to 100, newest first), including retries",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delivery attempts",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httputil.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.WebhookDelivery"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing scope or participant mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.APIResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "admin.AdvanceTimeRequest": {
+            "type": "object",
+            "required": [
+                "duration"
+            ],
+            "properties": {
+                "duration": {
+                    "description": "Go duration, e.g. 168h for 7 days",
+                    "type": "string",
+                    "example": "168h"
+                }
+            }
+        },
+        "admin.BrownoutRequest": {
+            "type": "object",
+            "properties": {
+                "errorRate": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0,
+                    "example": 0.3
+                },
+                "hold": {
+                    "description": "Go duration; empty holds the peak until DELETE /admin/brownout",
+                    "type": "string",
+                    "example": "5m"
+                },
+                "latencyMs": {
+                    "type": "integer",
+                    "maximum": 60000,
+                    "minimum": 1,
+                    "example": 1500
+                },
+                "latencyRate": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0,
+                    "example": 0.5
+                },
+                "rampDown": {
+                    "description": "Go duration; empty ends the brownout right after the hold",
+                    "type": "string",
+                    "example": "1m"
+                },
+                "rampUp": {
+                    "description": "Go duration; empty starts at the peak",
+                    "type": "string",
+                    "example": "1m"
+                }
+            }
+        },
+        "admin.BrownoutResponse": {
+            "type": "object",
+            "properties": {
+                "errorRate": {
+                    "type": "number",
+                    "example": 0.3
+                },
+                "hold": {
+                    "type": "string",
+                    "example": "5m0s"
+                },
+                "intensity": {
+                    "description": "share of the peak rates applied now, from 0 to 1",
+                    "type": "number",
+                    "example": 0.5
+                },
+                "latencyMs": {
+                    "type": "integer",
+                    "example": 1500
+                },
+                "latencyRate": {
+                    "type": "number",
+                    "example": 0.5
+                },
+                "phase": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/chaos.Phase"
+                        }
+                    ],
+                    "example": "RAMPING_UP"
+                },
+                "rampDown": {
+                    "type": "string",
+                    "example": "1m0s"
+                },
+                "rampUp": {
+                    "type": "string",
+                    "example": "1m0s"
+                },
+                "startedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin.ConfigResponse": {
+            "type": "object",
+            "properties": {
+                "faultRules": {
+                    "description": "from FAULT_RULES; rules added through /admin/faults are listed there",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chaos.Fault"
+                    }
+                },
+                "latencyRoutes": {
+                    "description": "routes with a latency profile (\"*\" covers the rest)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "GET /entries/{key}"
+                    ]
+                },
+                "rateLimitEnabled": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "admin.DeleteFaultResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                }
+            }
+        },
+        "admin.DeleteOAuthClientResponse": {
+            "type": "object",
+            "properties": {
+                "clientId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                }
+            }
+        },
+        "admin.DeleteStubResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                }
+            }
+        },
+        "admin.DeleteUserResponse": {
+            "type": "object",
+            "properties": {
+                "userId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                }
+            }
+        },
+        "admin.ErasedEntry": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "example": "52998224725"
+                },
+                "keyType": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.KeyType"
+                        }
+                    ],
+                    "example": "CPF"
+                },
+                "participant": {
+                    "type": "string",
+                    "example": "12345678"
+                }
+            }
+        },
+        "admin.ErasureResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.ErasedEntry"
+                    }
+                },
+                "fraudMarkersDeleted": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "idempotencyRecordsDeleted": {
+                    "description": "cached responses naming the tax ID",
+                    "type": "integer",
+                    "example": 2
+                },
+                "keyCreationCountsDeleted": {
+                    "description": "days with keys counted against the daily limit",
+                    "type": "integer",
+                    "example": 1
+                },
+                "ownershipPeriodsDeleted": {
+                    "description": "periods of the key ownership history",
+                    "type": "integer",
+                    "example": 2
+                },
+                "taxIdNumber": {
+                    "type": "string",
+                    "example": "52998224725"
+                }
+            }
+        },
+        "admin.ImportResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer",
+                    "example": 998
+                },
+                "requested": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "skipped": {
+                    "description": "keys that were already registered",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "admin.KeyPoliciesRequest": {
+            "type": "object",
+            "properties": {
+                "brazilianPhonesOnly": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "disabledKeyTypes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.KeyType"
+                    },
+                    "example": [
+                        "EVP"
+                    ]
+                }
+            }
+        },
+        "admin.KeyPoliciesResponse": {
+            "type": "object",
+            "properties": {
+                "brazilianPhonesOnly": {
+                    "description": "PHONE keys must be +55 numbers instead of any E.164 one",
+                    "type": "boolean",
+                    "example": false
+                },
+                "disabledKeyTypes": {
+                    "description": "key types that can't be created",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.KeyType"
+                    },
+                    "example": [
+                        "EVP"
+                    ]
+                }
+            }
+        },
+        "admin.LogLevelRequest": {
+            "type": "object",
+            "required": [
+                "level"
+            ],
+            "properties": {
+                "level": {
+                    "type": "string",
+                    "enum": [
+                        "debug",
+                        "info",
+                        "warn",
+                        "error"
+                    ],
+                    "example": "debug"
+                }
+            }
+        },
+        "admin.LogLevelResponse": {
+            "type": "object",
+            "properties": {
+                "level": {
+                    "type": "string",
+                    "example": "info"
+                }
+            }
+        },
+        "admin.ParticipantQuotaResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "description": "entries the participant holds",
+                    "type": "integer",
+                    "example": 9120
+                },
+                "own": {
+                    "description": "false when the participant is on the default quota",
+                    "type": "boolean",
+                    "example": true
+                },
+                "participant": {
+                    "type": "string",
+                    "example": "12345678"
+                },
+                "quota": {
+                    "$ref": "#/definitions/quota.Quota"
+                }
+            }
+        },
+        "admin.PasswordResetResponse": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string",
+                    "example": "2024-01-15T11:30:00Z"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                }
+            }
+        },
+        "admin.QuotasResponse": {
+            "type": "object",
+            "properties": {
+                "defaults": {
+                    "$ref": "#/definitions/quota.Quota"
+                },
+                "participants": {
+                    "description": "by ISPB",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/quota.Quota"
+                    }
+                }
+            }
+        },
+        "admin.ResetRequest": {
+            "type": "object",
+            "required": [
+                "participant"
+            ],
+            "properties": {
+                "participant": {
+                    "description": "ISPB, as sent in X-Participant-Id",
+                    "type": "string",
+                    "example": "12345678"
+                }
+            }
+        },
+        "admin.ResetResponse": {
+            "type": "object",
+            "properties": {
+                "entriesDeleted": {
+                    "type": "integer",
+                    "example": 25
+                },
+                "idempotencyKeysDeleted": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "participant": {
+                    "type": "string",
+                    "example": "12345678"
+                },
+                "rateLimitKeysDeleted": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "requestIdsDeleted": {
+                    "description": "requestIds its entries were created with",
+                    "type": "integer",
+                    "example": 25
+                }
+            }
+        },
+        "admin.SeedRequest": {
+            "type": "object",
+            "required": [
+                "count"
+            ],
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "maximum": 100000,
+                    "minimum": 1,
+                    "example": 1000
+                },
+                "keyTypes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "participants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/seed.ParticipantShare"
+                    }
+                },
+                "seed": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "admin.SeedResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer",
+                    "example": 998
+                },
+                "requested": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "seed": {
+                    "description": "pass back on the same (simulated) day to reproduce the same dataset",
+                    "type": "integer",
+                    "example": 42
+                },
+                "skipped": {
+                    "description": "keys that already existed in the directory",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "admin.Snapshot": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EntryResponse"
+                    }
+                },
+                "exportedAt": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "participants": {
+                    "description": "derived from the entries; ignored on import",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.SnapshotParticipant"
+                    }
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "admin.SnapshotParticipant": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "integer",
+                    "example": 25
+                },
+                "ispb": {
+                    "type": "string",
+                    "example": "12345678"
+                }
+            }
+        },
+        "admin.TimeResponse": {
+            "type": "object",
+            "properties": {
+                "now": {
+                    "type": "string",
+                    "example": "2024-01-22T10:30:00Z"
+                },
+                "offset": {
+                    "type": "string",
+                    "example": "168h0m0s"
+                },
+                "offsetSeconds": {
+                    "type": "integer",
+                    "example": 604800
+                }
+            }
+        },
+        "admin.UnlockResponse": {
+            "type": "object",
+            "properties": {
+                "unlocked": {
+                    "description": "false when the account was not locked",
+                    "type": "boolean",
+                    "example": true
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                }
+            }
+        },
+        "admin.UserStatusResponse": {
+            "type": "object",
+            "properties": {
+                "disabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                }
+            }
+        },
+        "auth.AuthResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "user": {
+                    "$ref": "#/definitions/models.UserResponse"
+                }
+            }
+        },
+        "auth.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "currentPassword",
+                "newPassword"
+            ],
+            "properties": {
+                "currentPassword": {
+                    "type": "string",
+                    "example": "password123"
+                },
+                "newPassword": {
+                    "type": "string",
+                    "minLength": 6,
+                    "example": "newpassword123"
+                }
+            }
+        },
+        "auth.ConfirmPasswordResetRequest": {
+            "type": "object",
+            "required": [
+                "newPassword",
+                "token"
+            ],
+            "properties": {
+                "newPassword": {
+                    "type": "string",
+                    "minLength": 6,
+                    "example": "newpassword123"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                }
+            }
+        },
+        "auth.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                },
+                "scope": {
+                    "description": "space-delimited; every user scope when omitted",
+                    "type": "string",
+                    "example": "entries:read"
+                }
+            }
+        },
+        "auth.PasswordResetRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                }
+            }
+        },
+        "auth.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6,
+                    "example": "password123"
+                }
+            }
+        },
+        "chaos.Fault": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "keySuffix": {
+                    "type": "string",
+                    "example": "999"
+                },
+                "latencyMs": {
+                    "type": "integer",
+                    "maximum": 60000,
+                    "minimum": 1,
+                    "example": 1500
+                },
+                "probability": {
+                    "type": "number",
+                    "maximum": 1,
+                    "example": 1
+                },
+                "route": {
+                    "type": "string",
+                    "example": "GET /entries/{key}"
+                },
+                "statusCode": {
+                    "type": "integer",
+                    "maximum": 599,
+                    "minimum": 500,
+                    "example": 503
+                },
+                "type": {
+                    "enum": [
+                        "LATENCY",
+                        "ERROR",
+                        "RESET"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/chaos.FaultType"
+                        }
+                    ],
+                    "example": "ERROR"
+                }
+            }
+        },
+        "chaos.FaultType": {
+            "type": "string",
+            "enum": [
+                "LATENCY",
+                "ERROR",
+                "RESET"
+            ],
+            "x-enum-varnames": [
+                "FaultLatency",
+                "FaultError",
+                "FaultReset"
+            ]
+        },
+        "chaos.Phase": {
+            "type": "string",
+            "enum": [
+                "RAMPING_UP",
+                "HOLDING",
+                "RAMPING_DOWN",
+                "ENDED"
+            ],
+            "x-enum-varnames": [
+                "PhaseRampingUp",
+                "PhaseHolding",
+                "PhaseRampingDown",
+                "PhaseEnded"
+            ]
+        },
+        "constants.FieldViolation": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "owner.taxIdNumber"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Must be an 11-digit CPF for NATURAL_PERSON"
+                }
+            }
+        },
+        "events.Type": {
+            "type": "string",
+            "enum": [
+                "ENTRY_CREATED",
+                "ENTRY_UPDATED",
+                "ENTRY_DELETED",
+                "CLAIM_OPENED",
+                "CLAIM_COMPLETED",
+                "INFRACTION_CREATED",
+                "ACCOUNT_LOCKED"
+            ],
+            "x-enum-comments": {
+                "AccountLocked": "Security events concern a user rather than a participant, so they reach no webhook"
+            },
+            "x-enum-descriptions": [
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "Security events concern a user rather than a participant, so they reach no webhook"
+            ],
+            "x-enum-varnames": [
+                "EntryCreated",
+                "EntryUpdated",
+                "EntryDeleted",
+                "ClaimOpened",
+                "ClaimCompleted",
+                "InfractionCreated",
+                "AccountLocked"
+            ]
+        },
+        "health.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "timestamp": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                }
+            }
+        },
+        "httputil.APIResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "ENTRY_CREATED"
+                },
+                "correlationId": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "data": {},
+                "error": {
+                    "type": "string",
+                    "example": "INVALID_REQUEST"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Request processed successfully"
+                },
+                "requestId": {
+                    "description": "server-generated; set on errors",
+                    "type": "string",
+                    "example": "9b2f6c1e-4d3a-4f7e-8c21-0e5b7a9d3f40"
+                },
+                "responseTime": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "violations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/constants.FieldViolation"
+                    }
+                }
+            }
+        },
+        "jwtkeys.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string",
+                    "example": "RS256"
+                },
+                "crv": {
+                    "description": "EC curve",
+                    "type": "string"
+                },
+                "e": {
+                    "description": "RSA exponent",
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string",
+                    "example": "3f1c9a0d7e5b2c48"
+                },
+                "kty": {
+                    "type": "string",
+                    "example": "RSA"
+                },
+                "n": {
+                    "description": "RSA modulus",
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string",
+                    "example": "sig"
+                },
+                "x": {
+                    "description": "EC point",
+                    "type": "string"
+                },
+                "y": {
+                    "type": "string"
+                }
+            }
+        },
+        "jwtkeys.JWKS": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/jwtkeys.JWK"
+                    }
+                }
+            }
+        },
+        "models.Account": {
+            "type": "object",
+            "required": [
+                "accountNumber",
+                "accountType",
+                "branch",
+                "openingDate",
+                "participant"
+            ],
+            "properties": {
+                "accountNumber": {
+                    "type": "string",
+                    "example": "123456789"
+                },
+                "accountType": {
+                    "enum": [
+                        "CACC",
+                        "SVGS",
+                        "SLRY",
+                        "PAGA",
+                        "TRAN"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AccountType"
+                        }
+                    ],
+                    "example": "CACC"
+                },
+                "branch": {
+                    "type": "string",
+                    "example": "0001"
+                },
+                "openingDate": {
+                    "type": "string",
+                    "example": "2024-01-15T00:00:00Z"
+                },
+                "participant": {
+                    "type": "string",
+                    "example": "12345678"
+                }
+            }
+        },
+        "models.AccountType": {
+            "type": "string",
+            "enum": [
+                "CACC",
+                "SVGS",
+                "SLRY",
+                "PAGA",
+                "TRAN"
+            ],
+            "x-enum-comments": {
+                "AccountTypeCACC": "checking account",
+                "AccountTypePAGA": "prepaid payment account",
+                "AccountTypeSLRY": "salary account",
+                "AccountTypeSVGS": "savings account",
+                "AccountTypeTRAN": "transactional account"
+            },
+            "x-enum-descriptions": [
+                "checking account",
+                "savings account",
+                "salary account",
+                "prepaid payment account",
+                "transactional account"
+            ],
+            "x-enum-varnames": [
+                "AccountTypeCACC",
+                "AccountTypeSVGS",
+                "AccountTypeSLRY",
+                "AccountTypePAGA",
+                "AccountTypeTRAN"
+            ]
+        },
+        "models.AdminAuditPage": {
+            "type": "object",
+            "properties": {
+                "nextCursor": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "records": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AdminAuditResponse"
+                    }
+                }
+            }
+        },
+        "models.AdminAuditResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "admin.seed"
+                },
+                "actor": {
+                    "type": "string",
+                    "example": "admin-token"
+                },
+                "correlationId": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "method": {
+                    "type": "string",
+                    "example": "POST"
+                },
+                "parameters": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "path": {
+                    "type": "string",
+                    "example": "/admin/seed"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 201
+                }
+            }
+        },
+        "models.CloseAccountRequest": {
+            "type": "object",
+            "properties": {
+                "transferTo": {
+                    "$ref": "#/definitions/models.TransferAccount"
+                }
+            }
+        },
+        "models.CloseAccountResponse": {
+            "type": "object",
+            "properties": {
+                "accountNumber": {
+                    "type": "string",
+                    "example": "123456789"
+                },
+                "branch": {
+                    "type": "string",
+                    "example": "0001"
+                },
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "+5511999999999"
+                    ]
+                },
+                "participant": {
+                    "type": "string",
+                    "example": "12345678"
+                },
+                "transferredTo": {
+                    "$ref": "#/definitions/models.TransferAccount"
+                }
+            }
+        },
+        "models.CreateEntryRequest": {
+            "type": "object",
+            "required": [
+                "account",
+                "key",
+                "keyType",
+                "owner",
+                "reason",
+                "requestId"
+            ],
+            "properties": {
+                "account": {
+                    "$ref": "#/definitions/models.Account"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "+5511999999999"
+                },
+                "keyType": {
+                    "enum": [
+                        "CPF",
+                        "CNPJ",
+                        "EMAIL",
+                        "PHONE",
+                        "EVP"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.KeyType"
+                        }
+                    ],
+                    "example": "PHONE"
+                },
+                "owner": {
+                    "$ref": "#/definitions/models.Owner"
+                },
+                "reason": {
+                    "enum": [
+                        "USER_REQUESTED",
+                        "RECONCILIATION"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Reason"
+                        }
+                    ],
+                    "example": "USER_REQUESTED"
+                },
+                "requestId": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                }
+            }
+        },
+        "models.CreateOAuthClientRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "participant",
+                "scopes"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "PSP reconciliation service"
+                },
+                "participant": {
+                    "type": "string",
+                    "example": "12345678"
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.Scope"
+                    },
+                    "example": [
+                        "entries:read",
+                        "entries:write"
+                    ]
+                }
+            }
+        },
+        "models.CreateSettlementRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "endToEndId",
+                "payeeKey",
+                "payerKey"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "in centavos",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 15075
+                },
+                "endToEndId": {
+                    "type": "string",
+                    "example": "E1234567820240115103000000000001"
+                },
+                "payeeKey": {
+                    "type": "string",
+                    "example": "+5511999999999"
+                },
+                "payerKey": {
+                    "type": "string",
+                    "example": "52998224725"
+                }
+            }
+        },
+        "models.CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "events",
+                "participant",
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/events.Type"
+                    },
+                    "example": [
+                        "ENTRY_CREATED",
+                        "ENTRY_DELETED"
+                    ]
+                },
+                "participant": {
+                    "type": "string",
+                    "example": "12345678"
+                },
+                "secret": {
+                    "description": "generated when omitted",
+                    "type": "string",
+                    "minLength": 16,
+                    "example": "my-shared-signing-secret"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://psp.example.com/dict/callbacks"
+                }
+            }
+        },
+        "models.DeleteEntryRequest": {
+            "type": "object",
+            "required": [
+                "key",
+                "participant",
+                "reason"
+            ],
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "example": "+5511999999999"
+                },
+                "participant": {
+                    "type": "string",
+                    "example": "12345678"
+                },
+                "reason": {
+                    "enum": [
+                        "USER_REQUESTED",
+                        "ACCOUNT_CLOSURE",
+                        "RECONCILIATION",
+                        "FRAUD",
+                        "RFB_VALIDATION"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Reason"
+                        }
+                    ],
+                    "example": "USER_REQUESTED"
+                }
+            }
+        },
+        "models.DeleteEntryResponse": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "example": "+5511999999999"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Entry deleted successfully"
+                },
+                "releasedKeys": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "52998224725"
+                    ]
+                }
+            }
+        },
+        "models.EntryPage": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EntryResponse"
+                    }
+                },
+                "nextCursor": {
+                    "type": "string",
+                    "example": "KzU1MTE5OTk5OTk5OTk"
+                }
+            }
+        },
+        "models.EntryResponse": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "$ref": "#/definitions/models.Account"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "+5511999999999"
+                },
+                "keyOwnershipDate": {
+                    "type": "string"
+                },
+                "keyType": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.KeyType"
+                        }
+                    ],
+                    "example": "PHONE"
+                },
+                "owner": {
+                    "$ref": "#/definitions/models.Owner"
+                },
+                "sequence": {
+                    "description": "Incremented by each change to the entry, so its events can be ordered; deletion takes the next number",
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.EntryStatus"
+                        }
+                    ],
+                    "example": "ACTIVE"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EntryStatus": {
+            "type": "string",
+            "enum": [
+                "ACTIVE",
+                "PENDING_RFB_VALIDATION"
+            ],
+            "x-enum-comments": {
+                "EntryStatusPendingRFBValidation": "owner irregular at the Receita Federal; an RFB_VALIDATION update clears it"
+            },
+            "x-enum-descriptions": [
+                "",
+                "owner irregular at the Receita Federal; an RFB_VALIDATION update clears it"
+            ],
+            "x-enum-varnames": [
+                "EntryStatusActive",
+                "EntryStatusPendingRFBValidation"
+            ]
+        },
+        "models.FraudMarkerResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "+5511999999999"
+                },
+                "keyType": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.KeyType"
                         }
-                    }
+                    ],
+                    "example": "PHONE"
+                },
+                "participant": {
+                    "type": "string",
+                    "example": "12345678"
+                },
+                "taxIdNumber": {
+                    "type": "string",
+                    "example": "12345678901"
                 }
             }
         },
-        "/metrics": {
-            "get": {
-                "description": "Returns Prometheus metrics for monitoring",
-                "produces": [
-                    "text/plain"
-                ],
-                "tags": [
-                    "health"
-                ],
-                "summary": "Prometheus metrics",
-                "responses": {
-                    "200": {
-                        "description": "Prometheus metrics in text format",
-                        "schema": {
-                            "type": "string"
+        "models.KeyOwnershipResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "+5511999999999"
+                },
+                "keyType": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.KeyType"
                         }
-                    }
+                    ],
+                    "example": "PHONE"
+                },
+                "owner": {
+                    "$ref": "#/definitions/models.Owner"
+                },
+                "participant": {
+                    "type": "string",
+                    "example": "12345678"
+                },
+                "to": {
+                    "description": "absent while the period is current",
+                    "type": "string"
                 }
             }
-        }
-    },
-    "definitions": {
-        "auth.AuthResponse": {
+        },
+        "models.KeyType": {
+            "type": "string",
+            "enum": [
+                "CPF",
+                "CNPJ",
+                "EMAIL",
+                "PHONE",
+                "EVP"
+            ],
+            "x-enum-varnames": [
+                "KeyTypeCPF",
+                "KeyTypeCNPJ",
+                "KeyTypeEMAIL",
+                "KeyTypePHONE",
+                "KeyTypeEVP"
+            ]
+        },
+        "models.KeyValidationResponse": {
             "type": "object",
             "properties": {
-                "token": {
+                "error": {
                     "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                    "example": "KEY_ALREADY_EXISTS"
                 },
-                "user": {
-                    "$ref": "#/definitions/models.UserResponse"
+                "key": {
+                    "type": "string",
+                    "example": "+5511999999999"
+                },
+                "keyType": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.KeyType"
+                        }
+                    ],
+                    "example": "PHONE"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "This key is already registered in the directory"
+                },
+                "valid": {
+                    "type": "boolean",
+                    "example": false
                 }
             }
         },
-        "auth.LoginRequest": {
+        "models.OAuthClientResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
+                "clientId": {
                     "type": "string",
-                    "example": "user@example.com"
+                    "example": "507f1f77bcf86cd799439011"
                 },
-                "password": {
+                "clientSecret": {
                     "type": "string",
-                    "example": "password123"
+                    "example": "8c6976e5b5410415bde908bd4dee15dfb167a9c873fc4bb8a81f6f2ab448a918"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "PSP reconciliation service"
+                },
+                "participant": {
+                    "type": "string",
+                    "example": "12345678"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Scope"
+                    },
+                    "example": [
+                        "entries:read",
+                        "entries:write"
+                    ]
                 }
             }
         },
-        "auth.RegisterRequest": {
+        "models.Owner": {
             "type": "object",
             "required": [
-                "email",
                 "name",
-                "password"
+                "taxIdNumber",
+                "type"
             ],
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "user@example.com"
-                },
                 "name": {
                     "type": "string",
                     "example": "John Doe"
                 },
-                "password": {
+                "taxIdNumber": {
                     "type": "string",
-                    "minLength": 6,
-                    "example": "password123"
+                    "example": "12345678901"
+                },
+                "tradeName": {
+                    "description": "Only for LEGAL_PERSON",
+                    "type": "string",
+                    "example": "Doe Enterprises"
+                },
+                "type": {
+                    "enum": [
+                        "NATURAL_PERSON",
+                        "LEGAL_PERSON"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.OwnerType"
+                        }
+                    ],
+                    "example": "NATURAL_PERSON"
                 }
             }
         },
-        "health.HealthResponse": {
+        "models.OwnerType": {
+            "type": "string",
+            "enum": [
+                "NATURAL_PERSON",
+                "LEGAL_PERSON"
+            ],
+            "x-enum-varnames": [
+                "OwnerTypeNaturalPerson",
+                "OwnerTypeLegalPerson"
+            ]
+        },
+        "models.Reason": {
+            "type": "string",
+            "enum": [
+                "USER_REQUESTED",
+                "ACCOUNT_CLOSURE",
+                "BRANCH_TRANSFER",
+                "RECONCILIATION",
+                "FRAUD",
+                "RFB_VALIDATION"
+            ],
+            "x-enum-varnames": [
+                "ReasonUserRequested",
+                "ReasonAccountClosure",
+                "ReasonBranchTransfer",
+                "ReasonReconciliation",
+                "ReasonFraud",
+                "ReasonRFBValidation"
+            ]
+        },
+        "models.ReconciliationFileResponse": {
             "type": "object",
             "properties": {
-                "status": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "date": {
                     "type": "string",
-                    "example": "ok"
+                    "example": "2024-01-15"
                 },
-                "timestamp": {
+                "entries": {
+                    "type": "integer",
+                    "example": 1500
+                },
+                "id": {
                     "type": "string",
-                    "example": "2024-01-15T10:30:00Z"
+                    "example": "65a4f0c2e4b0a1b2c3d4e5f6"
+                },
+                "participant": {
+                    "type": "string",
+                    "example": "12345678"
                 }
             }
         },
-        "httputil.APIResponse": {
+        "models.Scope": {
+            "type": "string",
+            "enum": [
+                "entries:read",
+                "entries:write",
+                "reconciliation",
+                "webhooks",
+                "settlements",
+                "claims:manage",
+                "admin"
+            ],
+            "x-enum-comments": {
+                "ScopeAdmin": "the /admin routes, in place of X-Admin-Token",
+                "ScopeClaimsManage": "portability and ownership claims",
+                "ScopeEntriesRead": "getEntry, fraud markers and key validation",
+                "ScopeEntriesWrite": "createEntry, updateEntry, deleteEntry and account closure",
+                "ScopeReconciliation": "participant listing and reconciliation files"
+            },
+            "x-enum-descriptions": [
+                "getEntry, fraud markers and key validation",
+                "createEntry, updateEntry, deleteEntry and account closure",
+                "participant listing and reconciliation files",
+                "",
+                "",
+                "portability and ownership claims",
+                "the /admin routes, in place of X-Admin-Token"
+            ],
+            "x-enum-varnames": [
+                "ScopeEntriesRead",
+                "ScopeEntriesWrite",
+                "ScopeReconciliation",
+                "ScopeWebhooks",
+                "ScopeSettlements",
+                "ScopeClaimsManage",
+                "ScopeAdmin"
+            ]
+        },
+        "models.SettlementResponse": {
             "type": "object",
             "properties": {
-                "code": {
+                "amount": {
+                    "type": "integer",
+                    "example": 15075
+                },
+                "endToEndId": {
                     "type": "string",
-                    "example": "ENTRY_CREATED"
+                    "example": "E1234567820240115103000000000001"
                 },
-                "correlationId": {
+                "payeeKey": {
                     "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                    "example": "+5511999999999"
                 },
-                "data": {},
-                "error": {
+                "payeeParticipant": {
                     "type": "string",
-                    "example": "INVALID_REQUEST"
+                    "example": "87654321"
                 },
-                "message": {
+                "payerKey": {
                     "type": "string",
-                    "example": "Request processed successfully"
+                    "example": "52998224725"
                 },
-                "responseTime": {
+                "payerParticipant": {
                     "type": "string",
-                    "example": "2024-01-15T10:30:00Z"
+                    "example": "12345678"
+                },
+                "settledAt": {
+                    "type": "string"
                 }
             }
         },
-        "models.Account": {
+        "models.TransferAccount": {
             "type": "object",
             "required": [
                 "accountNumber",
                 "accountType",
                 "branch",
-                "openingDate",
-                "participant"
+                "openingDate"
             ],
             "properties": {
                 "accountNumber": {
                     "type": "string",
-                    "example": "123456789"
+                    "example": "987654321"
                 },
                 "accountType": {
-                    "type": "string",
                     "enum": [
                         "CACC",
                         "SVGS",
-                        "SLRY"
+                        "SLRY",
+                        "PAGA",
+                        "TRAN"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AccountType"
+                        }
                     ],
                     "example": "CACC"
                 },
                 "branch": {
                     "type": "string",
-                    "example": "0001"
+                    "example": "0002"
                 },
                 "openingDate": {
                     "type": "string",
-                    "example": "2024-01-15T00:00:00Z"
-                },
-                "participant": {
-                    "type": "string",
-                    "example": "12345678"
+                    "example": "2024-06-01T00:00:00Z"
                 }
             }
         },
-        "models.CreateEntryRequest": {
+        "models.UpdateAccount": {
             "type": "object",
-            "required": [
-                "account",
-                "key",
-                "keyType",
-                "owner",
-                "reason",
-                "requestId"
-            ],
             "properties": {
-                "account": {
-                    "$ref": "#/definitions/models.Account"
-                },
-                "key": {
+                "accountNumber": {
                     "type": "string",
-                    "example": "+5511999999999"
+                    "example": "123456789"
                 },
-                "keyType": {
+                "accountType": {
                     "enum": [
-                        "CPF",
-                        "CNPJ",
-                        "EMAIL",
-                        "PHONE",
-                        "EVP"
+                        "CACC",
+                        "SVGS",
+                        "SLRY",
+                        "PAGA",
+                        "TRAN"
                     ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/models.KeyType"
+                            "$ref": "#/definitions/models.AccountType"
                         }
                     ],
-                    "example": "PHONE"
+                    "example": "CACC"
                 },
-                "owner": {
-                    "$ref": "#/definitions/models.Owner"
+                "branch": {
+                    "type": "string",
+                    "example": "0001"
                 },
-                "reason": {
+                "openingDate": {
                     "type": "string",
-                    "enum": [
-                        "USER_REQUESTED",
-                        "RECONCILIATION"
-                    ],
-                    "example": "USER_REQUESTED"
+                    "example": "2024-01-15T00:00:00Z"
                 },
-                "requestId": {
+                "participant": {
                     "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                    "example": "12345678"
                 }
             }
         },
-        "models.DeleteEntryRequest": {
+        "models.UpdateEntryRequest": {
             "type": "object",
             "required": [
                 "key",
@@ -726,218 +6417,416 @@ const docTemplate = `{
                 "reason"
             ],
             "properties": {
+                "account": {
+                    "$ref": "#/definitions/models.UpdateAccount"
+                },
                 "key": {
                     "type": "string",
                     "example": "+5511999999999"
                 },
+                "owner": {
+                    "$ref": "#/definitions/models.UpdateOwner"
+                },
                 "participant": {
+                    "description": "Must own the entry",
                     "type": "string",
                     "example": "12345678"
                 },
                 "reason": {
-                    "type": "string",
                     "enum": [
                         "USER_REQUESTED",
-                        "ACCOUNT_CLOSURE",
+                        "BRANCH_TRANSFER",
                         "RECONCILIATION",
-                        "FRAUD",
                         "RFB_VALIDATION"
                     ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Reason"
+                        }
+                    ],
                     "example": "USER_REQUESTED"
                 }
             }
         },
-        "models.DeleteEntryResponse": {
+        "models.UpdateOwner": {
             "type": "object",
             "properties": {
-                "key": {
+                "name": {
                     "type": "string",
-                    "example": "+5511999999999"
+                    "example": "John Doe"
                 },
-                "message": {
+                "tradeName": {
                     "type": "string",
-                    "example": "Entry deleted successfully"
+                    "example": "Doe Enterprises"
                 }
             }
         },
-        "models.EntryResponse": {
+        "models.UpdateProfileRequest": {
             "type": "object",
             "properties": {
-                "account": {
-                    "$ref": "#/definitions/models.Account"
+                "email": {
+                    "type": "string",
+                    "example": "new@example.com"
                 },
-                "createdAt": {
-                    "type": "string"
+                "name": {
+                    "type": "string",
+                    "example": "Jane Doe"
+                }
+            }
+        },
+        "models.UsageReportResponse": {
+            "type": "object",
+            "properties": {
+                "clientErrors": {
+                    "type": "integer",
+                    "example": 30
                 },
-                "key": {
+                "day": {
                     "type": "string",
-                    "example": "+5511999999999"
+                    "example": "2024-01-15"
                 },
-                "keyOwnershipDate": {
-                    "type": "string"
+                "errorRate": {
+                    "type": "number",
+                    "example": 0.0267
                 },
-                "keyType": {
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.KeyType"
-                        }
-                    ],
-                    "example": "PHONE"
+                "participant": {
+                    "type": "string",
+                    "example": "12345678"
                 },
-                "owner": {
-                    "$ref": "#/definitions/models.Owner"
+                "requests": {
+                    "type": "integer",
+                    "example": 1200
+                },
+                "serverErrors": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "tokensConsumed": {
+                    "type": "integer",
+                    "example": 1290
                 },
                 "updatedAt": {
                     "type": "string"
                 }
             }
         },
-        "models.KeyType": {
-            "type": "string",
-            "enum": [
-                "CPF",
-                "CNPJ",
-                "EMAIL",
-                "PHONE",
-                "EVP"
-            ],
-            "x-enum-varnames": [
-                "KeyTypeCPF",
-                "KeyTypeCNPJ",
-                "KeyTypeEMAIL",
-                "KeyTypePHONE",
-                "KeyTypeEVP"
-            ]
+        "models.UserPage": {
+            "type": "object",
+            "properties": {
+                "nextCursor": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UserResponse"
+                    }
+                }
+            }
         },
-        "models.Owner": {
+        "models.UserResponse": {
             "type": "object",
-            "required": [
-                "name",
-                "taxIdNumber",
-                "type"
-            ],
             "properties": {
-                "name": {
+                "createdAt": {
                     "type": "string",
-                    "example": "John Doe"
+                    "example": "2024-01-15T10:30:00Z"
                 },
-                "taxIdNumber": {
+                "disabled": {
+                    "description": "disabled users can't log in and their tokens are refused",
+                    "type": "boolean",
+                    "example": false
+                },
+                "email": {
                     "type": "string",
-                    "example": "12345678901"
+                    "example": "user@example.com"
                 },
-                "tradeName": {
-                    "description": "Only for LEGAL_PERSON",
+                "id": {
                     "type": "string",
-                    "example": "Doe Enterprises"
+                    "example": "507f1f77bcf86cd799439011"
                 },
-                "type": {
+                "name": {
+                    "type": "string",
+                    "example": "John Doe"
+                }
+            }
+        },
+        "models.ValidateKeyRequest": {
+            "type": "object",
+            "required": [
+                "key",
+                "keyType"
+            ],
+            "properties": {
+                "key": {
                     "type": "string",
+                    "example": "+5511999999999"
+                },
+                "keyType": {
                     "enum": [
-                        "NATURAL_PERSON",
-                        "LEGAL_PERSON"
+                        "CPF",
+                        "CNPJ",
+                        "EMAIL",
+                        "PHONE",
+                        "EVP"
                     ],
-                    "example": "NATURAL_PERSON"
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.KeyType"
+                        }
+                    ],
+                    "example": "PHONE"
                 }
             }
         },
-        "models.UpdateAccount": {
+        "models.WebhookDelivery": {
             "type": "object",
             "properties": {
-                "accountNumber": {
-                    "type": "string",
-                    "example": "123456789"
+                "attempt": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "accountType": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "durationMs": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "error": {
+                    "type": "string"
+                },
+                "eventId": {
                     "type": "string",
-                    "enum": [
-                        "CACC",
-                        "SVGS",
-                        "SLRY"
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "eventType": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/events.Type"
+                        }
                     ],
-                    "example": "CACC"
+                    "example": "ENTRY_CREATED"
                 },
-                "branch": {
+                "statusCode": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "url": {
                     "type": "string",
-                    "example": "0001"
+                    "example": "https://psp.example.com/dict/callbacks"
+                }
+            }
+        },
+        "models.WebhookResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
                 },
-                "openingDate": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/events.Type"
+                    },
+                    "example": [
+                        "ENTRY_CREATED",
+                        "ENTRY_DELETED"
+                    ]
+                },
+                "id": {
                     "type": "string",
-                    "example": "2024-01-15T00:00:00Z"
+                    "example": "507f1f77bcf86cd799439011"
                 },
                 "participant": {
                     "type": "string",
                     "example": "12345678"
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "3f9a6c1e..."
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://psp.example.com/dict/callbacks"
                 }
             }
         },
-        "models.UpdateEntryRequest": {
+        "oauth.ErrorResponse": {
             "type": "object",
-            "required": [
-                "key",
-                "reason"
-            ],
             "properties": {
-                "account": {
-                    "$ref": "#/definitions/models.UpdateAccount"
-                },
-                "key": {
+                "error": {
                     "type": "string",
-                    "example": "+5511999999999"
+                    "example": "invalid_client"
                 },
-                "owner": {
-                    "$ref": "#/definitions/models.UpdateOwner"
-                },
-                "reason": {
+                "error_description": {
                     "type": "string",
-                    "enum": [
-                        "USER_REQUESTED",
-                        "BRANCH_TRANSFER",
-                        "RECONCILIATION",
-                        "RFB_VALIDATION"
-                    ],
-                    "example": "USER_REQUESTED"
+                    "example": "Unknown client or wrong client secret"
                 }
             }
         },
-        "models.UpdateOwner": {
+        "oauth.TokenResponse": {
             "type": "object",
             "properties": {
-                "name": {
+                "access_token": {
                     "type": "string",
-                    "example": "John Doe"
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
                 },
-                "tradeName": {
+                "expires_in": {
+                    "description": "seconds",
+                    "type": "integer",
+                    "example": 3600
+                },
+                "scope": {
                     "type": "string",
-                    "example": "Doe Enterprises"
+                    "example": "entries:read entries:write"
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
                 }
             }
         },
-        "models.UserResponse": {
+        "quota.Quota": {
             "type": "object",
             "properties": {
-                "email": {
+                "maxEntries": {
+                    "description": "entries the participant can hold at once",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 10000
+                },
+                "requestsPerMinute": {
+                    "description": "DICT requests across all routes",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 600
+                }
+            }
+        },
+        "seed.ParticipantShare": {
+            "type": "object",
+            "required": [
+                "ispb",
+                "weight"
+            ],
+            "properties": {
+                "ispb": {
                     "type": "string",
-                    "example": "user@example.com"
+                    "example": "60701190"
+                },
+                "weight": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 3
+                }
+            }
+        },
+        "stubs.Stub": {
+            "type": "object",
+            "required": [
+                "statusCode"
+            ],
+            "properties": {
+                "body": {
+                    "description": "sent as is, as JSON",
+                    "type": "object"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "delayMs": {
+                    "type": "integer",
+                    "maximum": 60000,
+                    "minimum": 1,
+                    "example": 1500
+                },
+                "headers": {
+                    "description": "request headers that must carry these values",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
                 "id": {
                     "type": "string",
-                    "example": "507f1f77bcf86cd799439011"
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
                 },
-                "name": {
+                "key": {
                     "type": "string",
-                    "example": "John Doe"
+                    "example": "+5511999999999"
+                },
+                "responseHeaders": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "route": {
+                    "type": "string",
+                    "example": "GET /entries/{key}"
+                },
+                "statusCode": {
+                    "type": "integer",
+                    "maximum": 599,
+                    "minimum": 200,
+                    "example": 409
                 }
             }
         }
     },
     "securityDefinitions": {
+        "AdminToken": {
+            "description": "ADMIN_TOKEN value, required by the /admin routes",
+            "type": "apiKey",
+            "name": "X-Admin-Token",
+            "in": "header"
+        },
         "BearerAuth": {
             "description": "JWT Bearer token. Format: \"Bearer {token}\"",
             "type": "apiKey",
             "name": "Authorization",
             "in": "header"
         }
-    }
+    },
+    "tags": [
+        {
+            "description": "Health check endpoints",
+            "name": "health"
+        },
+        {
+            "description": "Authentication endpoints for user registration and login",
+            "name": "auth"
+        },
+        {
+            "description": "OAuth2 client credentials tokens for machine clients, and the keys that verify tokens",
+            "name": "oauth"
+        },
+        {
+            "description": "DICT entry management for Pix keys",
+            "name": "entries"
+        },
+        {
+            "description": "Callback registrations for directory events",
+            "name": "webhooks"
+        },
+        {
+            "description": "Test payments referenced by end-to-end ID",
+            "name": "settlements"
+        },
+        {
+            "description": "Daily reconciliation files per participant",
+            "name": "files"
+        },
+        {
+            "description": "Administrative endpoints for test and demo environments",
+            "name": "admin"
+        }
+    ]
 }`
 
 // SwaggerInfo holds exported Swagger Info so clients can modify it
@@ -947,7 +6836,7 @@ var SwaggerInfo = &swag.Spec{
 	BasePath:         "/",
 	Schemes:          []string{"http", "https"},
 	Title:            "DICT Simulator API",
-	Description:      "A simulated implementation of the Brazilian Central Bank's DICT API for managing Pix keys.",
+	Description:      "A simulated implementation of the Brazilian Central Bank's DICT API for managing Pix keys. Every route below except health, metrics, docs and the JWKS is served under /api/v1 (e.g. /api/v1/entries); the unprefixed paths serve the same contract but are deprecated and answer with Deprecation, Sunset and Link headers.",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 	LeftDelim:        "{{",
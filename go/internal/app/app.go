@@ -0,0 +1,309 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/dict-simulator/go/internal/broker"
+	"github.com/dict-simulator/go/internal/chaos"
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/config"
+	"github.com/dict-simulator/go/internal/db"
+	"github.com/dict-simulator/go/internal/events"
+	"github.com/dict-simulator/go/internal/hotreload"
+	"github.com/dict-simulator/go/internal/jwtkeys"
+	"github.com/dict-simulator/go/internal/keylimit"
+	"github.com/dict-simulator/go/internal/keypolicy"
+	"github.com/dict-simulator/go/internal/lockout"
+	"github.com/dict-simulator/go/internal/middleware"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/modules/admin"
+	"github.com/dict-simulator/go/internal/modules/auth"
+	"github.com/dict-simulator/go/internal/modules/entries"
+	"github.com/dict-simulator/go/internal/modules/files"
+	"github.com/dict-simulator/go/internal/modules/oauth"
+	"github.com/dict-simulator/go/internal/modules/settlements"
+	"github.com/dict-simulator/go/internal/modules/webhooks"
+	"github.com/dict-simulator/go/internal/ownership"
+	"github.com/dict-simulator/go/internal/passwordreset"
+	"github.com/dict-simulator/go/internal/quota"
+	"github.com/dict-simulator/go/internal/ratelimit"
+	"github.com/dict-simulator/go/internal/router"
+	"github.com/dict-simulator/go/internal/signing"
+	"github.com/dict-simulator/go/internal/stubs"
+	"github.com/dict-simulator/go/internal/usage"
+	"github.com/dict-simulator/go/internal/webhook"
+)
+
+// Dependencies are the connections an Application is built on, opened (and migrated) by the caller
+// Mongo is needed unless cfg.Storage is memory, and Postgres when it is postgres. Redis, when given,
+// keeps rate limit buckets, IP bans, signature nonces, lockouts, reset tokens, key creation counts
+// and JWT key pairs, so replicas share them; without it they are per process.
+type Dependencies struct {
+	Mongo    *db.Mongo
+	Postgres *db.Postgres
+	Redis    *db.Redis
+
+	// ReadConfig re-reads the configuration for POST /admin/config/reload; nil answers 501
+	ReadConfig func() (*config.Config, error)
+	// WatchSignals also re-reads it on SIGHUP, once the Application is started
+	WatchSignals bool
+
+	// KeyFilterName names the bloom filter in Redis, "entries" when empty
+	KeyFilterName string
+}
+
+// Repositories holds all repository instances
+type Repositories struct {
+	Entry           models.EntryRepository
+	User            models.UserRepository
+	Idempotency     models.IdempotencyRepository
+	Webhook         models.WebhookRepository
+	WebhookDelivery models.WebhookDeliveryRepository
+	Settlement      models.SettlementRepository
+	FraudMarker     models.FraudMarkerRepository
+	KeyOwnership    models.KeyOwnershipRepository
+	RequestID       models.RequestIDRepository
+	Reconciliation  models.ReconciliationFileRepository
+	StreamOffset    *models.StreamOffsetRepository // nil unless STORAGE=mongo
+	Outbox          models.OutboxRepository
+	OAuthClient     models.OAuthClientRepository
+	AdminAudit      models.AdminAuditRepository
+	UsageReport     models.UsageReportRepository
+}
+
+// Application is the simulator wired from one configuration: the HTTP handler and everything behind it
+// The server, the embedded simulator and the integration tests all build theirs with BuildApplication.
+type Application struct {
+	Config      *config.Config
+	Clock       *clock.Simulated
+	Repos       *Repositories
+	Keys        *jwtkeys.KeySet
+	Bus         *events.Bus
+	Dispatcher  *webhook.Dispatcher
+	RateLimiter ratelimit.Limiter
+	Middleware  *middleware.Manager
+	Reloader    *hotreload.Reloader // nil without Dependencies.ReadConfig
+	Usage       *usage.Recorder
+	Handler     http.Handler
+
+	// Background components, nil when disabled
+	configReload *worker
+	scheduler    *worker
+	eventSource  *worker
+	outboxRelay  *worker
+	usageReports *worker
+
+	saveRateLimitState func(context.Context) error
+}
+
+// BuildApplication constructs the repositories, services, middlewares and handlers cfg describes
+// Indexes are ensured and the key filter warmed before it returns, but no background component
+// runs until Start. Call Shutdown, or register its steps with OnShutdown, to stop them.
+func BuildApplication(ctx context.Context, cfg *config.Config, deps Dependencies) (*Application, error) {
+	// Shared by every time-dependent flow; only moves when driven through /admin/time
+	a := &Application{Config: cfg, Clock: clock.NewSimulated()}
+
+	repos, err := setupRepositories(ctx, cfg, deps, a.Clock)
+	if err != nil {
+		return nil, err
+	}
+	a.Repos = repos
+
+	// Innermost, so every wrapper above it works on opened owners
+	if err := setupPIIEncryption(ctx, cfg, repos); err != nil {
+		return nil, err
+	}
+
+	if err := setupKeyFilter(ctx, cfg, deps, repos); err != nil {
+		return nil, err
+	}
+
+	setupTransactionalOutbox(cfg, deps.Mongo, repos, a.Clock)
+
+	// Outermost, so each entry.* span covers the key filter and outbox work it triggers
+	repos.Entry = models.NewTracedEntryRepository(repos.Entry)
+
+	a.Dispatcher = webhook.NewDispatcher(repos.Webhook, repos.WebhookDelivery, a.Clock, webhook.Config{
+		Timeout:        cfg.WebhookTimeout,
+		MaxAttempts:    cfg.WebhookMaxAttempts,
+		InitialBackoff: cfg.WebhookInitialBackoff,
+		MaxBackoff:     cfg.WebhookMaxBackoff,
+	})
+
+	a.Bus = events.NewBus()
+	a.Bus.Subscribe("webhooks", a.Dispatcher.Publish)
+	a.Bus.Subscribe("metrics", events.CountMetric)
+	a.Bus.Subscribe("audit", events.AuditLog)
+	a.Bus.Subscribe("ownership", ownership.NewRecorder(repos.KeyOwnership).Record)
+
+	if a.outboxRelay, err = setupOutboxRelay(cfg, repos, a.Bus); err != nil {
+		return nil, err
+	}
+
+	if a.eventSource, err = setupEventSource(ctx, cfg, deps.Mongo, repos, a.Bus, a.Clock); err != nil {
+		return nil, err
+	}
+
+	if a.Keys, err = setupJWTKeys(ctx, cfg, deps.Redis); err != nil {
+		return nil, err
+	}
+
+	a.scheduler = setupScheduler(cfg, repos, a.Keys, a.Clock)
+
+	if a.RateLimiter, a.saveRateLimitState, err = setupRateLimiter(ctx, cfg, deps.Redis, a.Clock); err != nil {
+		return nil, err
+	}
+
+	a.setupHandler(deps)
+
+	return a, nil
+}
+
+// setupHandler initializes handlers, middleware, and the HTTP router, and the reloader that
+// applies configuration changes to its middlewares.
+func (a *Application) setupHandler(deps Dependencies) {
+	cfg, repos := a.Config, a.Repos
+
+	var nonces signing.NonceStore
+	var bans ratelimit.BanStore
+	var logins lockout.Store
+	var resets passwordreset.Store
+	var creations keylimit.Store
+	if deps.Redis != nil {
+		nonces = signing.NewRedisNonceStore(deps.Redis.Client)
+		bans = ratelimit.NewRedisBanStore(deps.Redis.Client)
+		logins = lockout.NewRedisStore(deps.Redis.Client)
+		resets = passwordreset.NewRedisStore(deps.Redis.Client)
+		creations = keylimit.NewRedisStore(deps.Redis.Client)
+	} else {
+		// Signature nonces, IP bans, account lockouts, reset tokens and key creation counts are per process
+		nonces = signing.NewMemoryNonceStore()
+		bans = ratelimit.NewMemoryBanStore()
+		logins = lockout.NewMemoryStore()
+		resets = passwordreset.NewMemoryStore()
+		creations = keylimit.NewMemoryStore()
+	}
+	faults := chaos.NewInjector(a.Clock)
+	keyPolicies := keypolicy.NewStore(cfg.KeyPolicies)
+	quotas := quota.NewStore(cfg.DefaultQuota)
+	stubRegistry := stubs.NewRegistry(a.Clock)
+	a.Usage = usage.NewRecorder(repos.UsageReport, a.Clock, cfg.UsageFlushInterval)
+	a.usageReports = &worker{name: "usage reports", run: a.Usage.Run}
+	a.Middleware = middleware.NewManager(repos.Idempotency, a.RateLimiter, bans, nonces, faults, middleware.NewSettings(cfg))
+
+	// A nil *hotreload.Reloader in the interface would not read as nil to the admin handler
+	var reloader admin.ConfigReloader
+	if deps.ReadConfig != nil {
+		a.Reloader = hotreload.New(deps.ReadConfig, a.Middleware)
+		reloader = a.Reloader
+		if deps.WatchSignals {
+			a.configReload = &worker{name: "config reload", run: a.Reloader.WatchSignals}
+		}
+	}
+
+	// Security events are not directory writes, so they go to the bus whatever the event source
+	lockoutPolicy := lockout.Policy{MaxFailures: cfg.LoginMaxFailures, Duration: cfg.LoginLockoutDuration}
+	authHandler := auth.NewHandler(repos.User, a.Keys, logins, lockoutPolicy, resets, cfg.PasswordResetTTL, a.Bus, a.Clock)
+	publisher := handlerPublisher(cfg, a.Bus)
+	entriesHandler := entries.NewHandler(repos.Entry, repos.FraudMarker, repos.KeyOwnership, repos.RequestID, creations, cfg.KeyCreationDailyLimit, entries.AccountRules{
+		MinOpeningDate: cfg.AccountMinOpeningDate,
+		CheckDigit:     cfg.AccountNumberCheckDigit,
+	}, keyPolicies, quotas, publisher, a.Clock)
+	webhooksHandler := webhooks.NewHandler(repos.Webhook, repos.WebhookDelivery)
+	settlementsHandler := settlements.NewHandler(repos.Settlement, repos.Entry, a.Clock)
+	filesHandler := files.NewHandler(repos.Reconciliation)
+	oauthHandler := oauth.NewHandler(repos.OAuthClient, a.Keys, cfg.OAuthTokenTTL)
+	adminHandler := admin.NewHandler(repos.Entry, repos.FraudMarker, repos.KeyOwnership, repos.RequestID, repos.User, repos.OAuthClient, repos.AdminAudit, a.Usage, repos.Idempotency, a.RateLimiter, creations, logins, resets, faults, stubRegistry, keyPolicies, quotas, publisher, a.Clock, reloader)
+
+	policies := ratelimit.WithInitialFill(ratelimit.DefaultPolicies(), cfg.RateLimitInitialFill)
+	policies = ratelimit.WithAlgorithms(policies, cfg.RateLimitAlgorithms)
+
+	a.Handler = router.Setup(cfg, a.Clock, a.Keys, repos.User, repos.OAuthClient, repos.AdminAudit, a.Usage, authHandler, oauthHandler, entriesHandler, webhooksHandler, settlementsHandler, filesHandler, adminHandler, a.Middleware, stubRegistry, quotas, policies)
+}
+
+// ServeHTTP serves the DICT API
+func (a *Application) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.Handler.ServeHTTP(w, r)
+}
+
+// Start runs the enabled background components: the config reload signal watcher, the outbox relay,
+// the change stream watcher, the scheduler and the usage report flusher
+func (a *Application) Start() {
+	for _, w := range []*worker{a.configReload, a.outboxRelay, a.eventSource, a.scheduler, a.usageReports} {
+		if w != nil {
+			w.start()
+		}
+	}
+}
+
+// OnShutdown registers the steps that stop the Application with register, e.g. server.Server.OnShutdown
+// Producers stop before the components they feed, so nothing is handed over mid-shutdown.
+// Pending webhook retries are abandoned; their earlier attempts remain in the delivery log.
+func (a *Application) OnShutdown(register func(name string, fn func(ctx context.Context) error)) {
+	for _, w := range []*worker{a.configReload, a.scheduler, a.eventSource, a.outboxRelay, a.usageReports} {
+		if w != nil {
+			register(w.name, w.stop)
+		}
+	}
+	register("webhook dispatcher", a.Dispatcher.Shutdown)
+	register("rate limit state", a.saveRateLimitState)
+}
+
+// Shutdown runs the OnShutdown steps in order, sharing ctx's deadline
+// Stop serving requests before calling it.
+func (a *Application) Shutdown(ctx context.Context) error {
+	var err error
+	a.OnShutdown(func(name string, fn func(context.Context) error) {
+		if stepErr := fn(ctx); stepErr != nil {
+			err = errors.Join(err, fmt.Errorf("%s: %w", name, stepErr))
+		}
+	})
+	return err
+}
+
+// worker is a background component that runs from Start until it is stopped
+type worker struct {
+	name string
+	run  func(ctx context.Context)
+	// close releases what run used once it has returned; may be nil
+	close func()
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// start runs the worker on its own goroutine
+func (w *worker) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.done = make(chan struct{})
+	go func() {
+		defer close(w.done)
+		w.run(ctx)
+	}()
+}
+
+// stop cancels the worker's context and waits for it to return, giving up when ctx is done
+func (w *worker) stop(ctx context.Context) error {
+	var err error
+	if w.done != nil {
+		w.cancel()
+		select {
+		case <-w.done:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+	if w.close != nil {
+		w.close()
+	}
+	return err
+}
+
+// closeBroker adapts b.Close to worker.close
+func closeBroker(b broker.Broker) func() {
+	return func() { b.Close() }
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dict-simulator/go/internal/config"
+	"github.com/dict-simulator/go/internal/jwtkeys"
+)
+
+func memoryConfig() *config.Config {
+	return &config.Config{
+		Environment:            "test",
+		Storage:                config.StorageMemory,
+		JWTSecret:              "test-secret",
+		JWTAlgorithm:           jwtkeys.HS256,
+		RateLimitBucketSize:    60,
+		RateLimitRefillSeconds: 60,
+		RateLimitInitialFill:   1,
+		MaxBodyBytes:           1 << 20,
+		AccessLogSampleRate:    1,
+		WebhookTimeout:         time.Second,
+		WebhookMaxAttempts:     1,
+		EventSource:            config.EventSourceInline,
+	}
+}
+
+func TestBuildApplicationWithoutDatabases(t *testing.T) {
+	a, err := BuildApplication(context.Background(), memoryConfig(), Dependencies{})
+	if err != nil {
+		t.Fatalf("BuildApplication: %v", err)
+	}
+	a.Start()
+	defer a.Shutdown(context.Background())
+
+	if a.Reloader != nil {
+		t.Error("Reloader is set without ReadConfig")
+	}
+
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("GET /health = %d, want 200", rec.Code)
+	}
+}
+
+func TestOnShutdownStopsProducersFirst(t *testing.T) {
+	cfg := memoryConfig()
+	cfg.SchedulerEnabled = true
+	a, err := BuildApplication(context.Background(), cfg, Dependencies{
+		ReadConfig:   func() (*config.Config, error) { return cfg, nil },
+		WatchSignals: true,
+	})
+	if err != nil {
+		t.Fatalf("BuildApplication: %v", err)
+	}
+	a.Start()
+
+	var names []string
+	a.OnShutdown(func(name string, fn func(context.Context) error) {
+		names = append(names, name)
+		if err := fn(context.Background()); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	})
+
+	want := []string{"config reload", "scheduler", "usage reports", "webhook dispatcher", "rate limit state"}
+	if !slices.Equal(names, want) {
+		t.Errorf("shutdown steps = %v, want %v", names, want)
+	}
+}
+
+func TestShutdownSavesRateLimitState(t *testing.T) {
+	cfg := memoryConfig()
+	cfg.RateLimitStateFile = filepath.Join(t.TempDir(), "buckets.json")
+
+	a, err := BuildApplication(context.Background(), cfg, Dependencies{})
+	if err != nil {
+		t.Fatalf("BuildApplication: %v", err)
+	}
+	if err := a.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	if _, err := os.Stat(cfg.RateLimitStateFile); err != nil {
+		t.Errorf("rate limit state not saved: %v", err)
+	}
+
+	// The saved state is loaded by the next application
+	if _, err := BuildApplication(context.Background(), cfg, Dependencies{}); err != nil {
+		t.Errorf("BuildApplication with saved state: %v", err)
+	}
+}
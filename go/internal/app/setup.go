@@ -0,0 +1,430 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/dict-simulator/go/internal/broker"
+	"github.com/dict-simulator/go/internal/changestream"
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/config"
+	"github.com/dict-simulator/go/internal/db"
+	"github.com/dict-simulator/go/internal/events"
+	"github.com/dict-simulator/go/internal/jobs"
+	"github.com/dict-simulator/go/internal/jwtkeys"
+	"github.com/dict-simulator/go/internal/keyfilter"
+	"github.com/dict-simulator/go/internal/logger"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/modules/auth"
+	"github.com/dict-simulator/go/internal/outbox"
+	"github.com/dict-simulator/go/internal/pii"
+	"github.com/dict-simulator/go/internal/ratelimit"
+	"github.com/dict-simulator/go/internal/scheduler"
+)
+
+// indexed is a MongoDB repository whose indexes must exist before it is used
+type indexed struct {
+	name string
+	repo interface {
+		IndexSpec() db.IndexSpec
+		EnsureIndexes(ctx context.Context) error
+	}
+}
+
+// setupRepositories creates all repository instances and ensures database indexes.
+// Entries, users and idempotency records live in the configured STORAGE backend;
+// webhooks, deliveries, settlements, fraud markers, key ownership history, used requestIds, reconciliation files, stream offsets, the outbox,
+// OAuth clients, the admin audit log and usage reports use MongoDB unless STORAGE=memory,
+// so STORAGE=postgres still needs MongoDB and config validation requires MONGODB_URI with it.
+func setupRepositories(ctx context.Context, cfg *config.Config, deps Dependencies, clk clock.Clock) (*Repositories, error) {
+	if cfg.Storage == config.StorageMemory {
+		return &Repositories{
+			Entry:           models.NewMemoryEntryRepository(clk),
+			User:            models.NewMemoryUserRepository(),
+			Idempotency:     models.NewMemoryIdempotencyRepository(clk),
+			Webhook:         models.NewMemoryWebhookRepository(clk),
+			WebhookDelivery: models.NewMemoryWebhookDeliveryRepository(),
+			Settlement:      models.NewMemorySettlementRepository(),
+			FraudMarker:     models.NewMemoryFraudMarkerRepository(),
+			KeyOwnership:    models.NewMemoryKeyOwnershipRepository(),
+			RequestID:       models.NewMemoryRequestIDRepository(),
+			Reconciliation:  models.NewMemoryReconciliationFileRepository(),
+			Outbox:          models.NewMemoryOutboxRepository(),
+			OAuthClient:     models.NewMemoryOAuthClientRepository(clk),
+			AdminAudit:      models.NewMemoryAdminAuditRepository(clk),
+			UsageReport:     models.NewMemoryUsageReportRepository(clk),
+		}, nil
+	}
+
+	webhookRepo := models.NewMongoWebhookRepository(deps.Mongo, clk)
+	webhookDeliveryRepo := models.NewMongoWebhookDeliveryRepository(deps.Mongo)
+	settlementRepo := models.NewMongoSettlementRepository(deps.Mongo)
+	fraudMarkerRepo := models.NewMongoFraudMarkerRepository(deps.Mongo)
+	keyOwnershipRepo := models.NewMongoKeyOwnershipRepository(deps.Mongo)
+	requestIDRepo := models.NewMongoRequestIDRepository(deps.Mongo)
+	reconciliationRepo := models.NewMongoReconciliationFileRepository(deps.Mongo)
+	outboxRepo := models.NewMongoOutboxRepository(deps.Mongo)
+	oauthClientRepo := models.NewMongoOAuthClientRepository(deps.Mongo, clk)
+	adminAuditRepo := models.NewMongoAdminAuditRepository(deps.Mongo, clk)
+	usageReportRepo := models.NewMongoUsageReportRepository(deps.Mongo, clk)
+
+	repos := &Repositories{
+		Webhook:         webhookRepo,
+		WebhookDelivery: webhookDeliveryRepo,
+		Settlement:      settlementRepo,
+		FraudMarker:     fraudMarkerRepo,
+		KeyOwnership:    keyOwnershipRepo,
+		RequestID:       requestIDRepo,
+		Reconciliation:  reconciliationRepo,
+		StreamOffset:    models.NewStreamOffsetRepository(deps.Mongo),
+		Outbox:          outboxRepo,
+		OAuthClient:     oauthClientRepo,
+		AdminAudit:      adminAuditRepo,
+		UsageReport:     usageReportRepo,
+	}
+
+	indexes := []indexed{
+		{"webhook", webhookRepo},
+		{"webhook delivery", webhookDeliveryRepo},
+		{"settlement", settlementRepo},
+		{"fraud marker", fraudMarkerRepo},
+		{"key ownership", keyOwnershipRepo},
+		{"request ID", requestIDRepo},
+		{"reconciliation file", reconciliationRepo},
+		{"outbox", outboxRepo},
+		{"OAuth client", oauthClientRepo},
+		{"admin audit", adminAuditRepo},
+		{"usage report", usageReportRepo},
+	}
+
+	switch cfg.Storage {
+	case config.StoragePostgres:
+		// Tables and indexes come from the migrations the caller has run
+		repos.Entry = models.NewPostgresEntryRepository(deps.Postgres, clk)
+		repos.User = models.NewPostgresUserRepository(deps.Postgres)
+		repos.Idempotency = models.NewPostgresIdempotencyRepository(deps.Postgres, clk)
+	default:
+		entryRepo := models.NewMongoEntryRepository(deps.Mongo, clk)
+		userRepo := models.NewMongoUserRepository(deps.Mongo)
+		idempotencyRepo := models.NewMongoIdempotencyRepository(deps.Mongo, clk)
+
+		indexes = append(indexes,
+			indexed{"entry", entryRepo},
+			indexed{"user", userRepo},
+			indexed{"idempotency", idempotencyRepo},
+		)
+
+		repos.Entry = entryRepo
+		repos.User = userRepo
+		repos.Idempotency = idempotencyRepo
+	}
+
+	if cfg.MongoDBIndexes != config.MongoDBIndexesCheck {
+		for _, ix := range indexes {
+			if err := ix.repo.EnsureIndexes(ctx); err != nil {
+				return nil, fmt.Errorf("ensure %s indexes: %w", ix.name, err)
+			}
+		}
+	}
+	checkIndexes(ctx, deps.Mongo, indexes)
+
+	return repos, nil
+}
+
+// checkIndexes logs the declared indexes that are missing, the built ones no longer declared and
+// the declared ones no query has used since the server started. It never fails startup: a
+// missing index only makes its queries scan the collection.
+func checkIndexes(ctx context.Context, mongoDB *db.Mongo, indexes []indexed) {
+	var missing, undeclared, unused int
+	for _, ix := range indexes {
+		report, err := mongoDB.CheckIndexes(ctx, ix.repo.IndexSpec())
+		if err != nil {
+			logger.Warn("Failed to check indexes", zap.String("repository", ix.name), zap.Error(err))
+			continue
+		}
+
+		for _, name := range report.Missing {
+			logger.Warn("Index missing", zap.String("collection", report.Collection), zap.String("index", name))
+		}
+		for _, name := range report.Undeclared {
+			logger.Warn("Index not declared", zap.String("collection", report.Collection), zap.String("index", name))
+		}
+		for _, index := range report.Unused {
+			logger.Info("Index unused",
+				zap.String("collection", report.Collection),
+				zap.String("index", index.Name),
+				zap.Time("since", index.Since),
+			)
+		}
+		missing += len(report.Missing)
+		undeclared += len(report.Undeclared)
+		unused += len(report.Unused)
+	}
+
+	logger.Info("Indexes checked",
+		zap.Int("collections", len(indexes)),
+		zap.Int("missing", missing),
+		zap.Int("undeclared", undeclared),
+		zap.Int("unused", unused),
+	)
+}
+
+// setupKeyFilter puts the Redis bloom filter of registered keys in front of entry lookups.
+// The filter is warmed from storage before any request is served.
+func setupKeyFilter(ctx context.Context, cfg *config.Config, deps Dependencies, repos *Repositories) error {
+	if !cfg.KeyFilterEnabled {
+		return nil
+	}
+
+	name := deps.KeyFilterName
+	if name == "" {
+		name = "entries"
+	}
+	filter := keyfilter.NewBloom(deps.Redis.Client, name, cfg.KeyFilterCapacity, cfg.KeyFilterFPRate)
+
+	start := time.Now()
+	count, err := filter.Warm(ctx, repos.Entry.ForEachKey)
+	if err != nil {
+		return fmt.Errorf("warm key filter: %w", err)
+	}
+	logger.Info("Key filter warmed",
+		zap.Int("keys", count),
+		zap.Duration("duration", time.Since(start)),
+	)
+
+	repos.Entry = keyfilter.NewEntryRepository(repos.Entry, filter)
+	return nil
+}
+
+// setupPIIEncryption seals the entry and ownership period owners stored in plaintext, or under a retired
+// key, then wraps the storage repositories so owners, fraud marker tax IDs and queued event data are
+// sealed on write and opened on read from here on
+func setupPIIEncryption(ctx context.Context, cfg *config.Config, repos *Repositories) error {
+	if cfg.PIIKeys == nil {
+		return nil
+	}
+
+	start := time.Now()
+	count, err := pii.Migrate(ctx, repos.Entry, cfg.PIIKeys)
+	if err != nil {
+		return fmt.Errorf("encrypt stored owners: %w", err)
+	}
+	periods, err := pii.MigrateOwnership(ctx, repos.KeyOwnership, cfg.PIIKeys)
+	if err != nil {
+		return fmt.Errorf("encrypt stored ownership periods: %w", err)
+	}
+	logger.Info("Stored owners encrypted",
+		zap.Int("entries", count),
+		zap.Int("ownershipPeriods", periods),
+		zap.Duration("duration", time.Since(start)),
+	)
+
+	repos.Entry = pii.NewEntryRepository(repos.Entry, cfg.PIIKeys)
+	repos.FraudMarker = pii.NewFraudMarkerRepository(repos.FraudMarker, cfg.PIIKeys)
+	repos.KeyOwnership = pii.NewKeyOwnershipRepository(repos.KeyOwnership, cfg.PIIKeys)
+	repos.Outbox = pii.NewOutboxRepository(repos.Outbox, cfg.PIIKeys)
+	return nil
+}
+
+// setupJWTKeys loads the keys tokens are signed with, creating the first key pair if there is none.
+// Key pairs are kept in Redis so every replica verifies the tokens of the others.
+func setupJWTKeys(ctx context.Context, cfg *config.Config, redisDB *db.Redis) (*jwtkeys.KeySet, error) {
+	var store jwtkeys.Store
+	if redisDB != nil {
+		store = jwtkeys.NewRedisStore(redisDB.Client)
+	} else {
+		// Key pairs are per process, and tokens do not survive a restart
+		store = jwtkeys.NewMemoryStore()
+	}
+
+	keys := jwtkeys.New(jwtkeys.Config{
+		Algorithm: cfg.JWTAlgorithm,
+		Secret:    cfg.JWTSecret,
+		Rotation:  cfg.JWTKeyRotation,
+		// A replaced key verifies until the longest-lived token it signed has expired
+		Retention: max(auth.TokenLifetime, cfg.OAuthTokenTTL),
+	}, store)
+	if err := keys.Rotate(ctx); err != nil {
+		return nil, fmt.Errorf("load JWT signing keys: %w", err)
+	}
+	logger.Info("JWT signing keys loaded",
+		zap.String("algorithm", keys.Algorithm()),
+		zap.Int("keys", len(keys.JWKS().Keys)),
+	)
+	return keys, nil
+}
+
+// setupTransactionalOutbox makes entry writes enqueue their events in the same transaction.
+// Only with EVENT_SOURCE=outbox; the outbox relay publishes the events once committed.
+func setupTransactionalOutbox(cfg *config.Config, mongoDB *db.Mongo, repos *Repositories, clk clock.Clock) {
+	if cfg.EventSource != config.EventSourceOutbox {
+		return
+	}
+	repos.Entry = outbox.NewEntryRepository(repos.Entry, mongoDB, repos.Outbox, clk)
+}
+
+// handlerPublisher returns what request handlers publish to.
+// With the change stream or the outbox as the source, handlers stay silent so each write is published once.
+func handlerPublisher(cfg *config.Config, bus *events.Bus) events.Publisher {
+	if cfg.EventSource != config.EventSourceInline {
+		return events.Discard
+	}
+	return bus
+}
+
+// setupOutboxRelay publishes the outbox: to the bus when it is the event source (EVENT_SOURCE=outbox),
+// and to the configured message broker. Otherwise bus events are enqueued for the broker.
+// The returned worker closes the broker connection once stopped; it is nil when there is nothing to relay.
+func setupOutboxRelay(cfg *config.Config, repos *Repositories, bus *events.Bus) (*worker, error) {
+	transactional := cfg.EventSource == config.EventSourceOutbox
+	if cfg.EventBroker == "" && !transactional {
+		return nil, nil
+	}
+
+	var b broker.Broker
+	if cfg.EventBroker != "" {
+		var err error
+		b, err = broker.New(cfg.EventBroker, cfg.EventBrokerURL)
+		if err != nil {
+			return nil, fmt.Errorf("create event broker: %w", err)
+		}
+	}
+	if transactional {
+		b = outbox.NewBusBroker(bus, b)
+	}
+
+	relay := outbox.NewRelay(repos.Outbox, b, outbox.Config{
+		Topic:        cfg.EventBrokerTopic,
+		Broker:       cfg.EventBroker,
+		PollInterval: cfg.OutboxPollInterval,
+		BatchSize:    100,
+		MaxBackoff:   cfg.OutboxMaxBackoff,
+		MaxAttempts:  cfg.OutboxMaxAttempts,
+	})
+	if !transactional {
+		bus.Subscribe("outbox", relay.Enqueue)
+	}
+
+	if transactional {
+		logger.Info("Publishing events from the outbox")
+	}
+	if cfg.EventBroker != "" {
+		logger.Info("Publishing events to broker",
+			zap.String("broker", cfg.EventBroker),
+			zap.String("topic", cfg.EventBrokerTopic),
+		)
+	}
+
+	return &worker{name: "outbox relay", run: relay.Run, close: closeBroker(b)}, nil
+}
+
+// setupEventSource opens the entries change stream when it is the configured source.
+// The returned worker tails it into the bus; it is nil with any other source.
+func setupEventSource(ctx context.Context, cfg *config.Config, mongoDB *db.Mongo, repos *Repositories, bus *events.Bus, clk clock.Clock) (*worker, error) {
+	if cfg.EventSource != config.EventSourceChangeStream {
+		return nil, nil
+	}
+
+	// Deletes carry the entry's participant only in the pre-image
+	// (config.Load only allows the change stream with STORAGE=mongo)
+	if err := models.NewMongoEntryRepository(mongoDB, clk).EnablePreImages(ctx); err != nil {
+		return nil, fmt.Errorf("enable entry pre-images (change streams need a replica set): %w", err)
+	}
+
+	watcher := changestream.NewWatcher(mongoDB, repos.StreamOffset, bus, cfg.PIIKeys, clk)
+	if err := watcher.Init(ctx); err != nil {
+		return nil, fmt.Errorf("open entries change stream: %w", err)
+	}
+
+	return &worker{name: "event source", run: watcher.Run}, nil
+}
+
+// setupScheduler plans the periodic housekeeping jobs, or returns nil when the scheduler is disabled.
+// Stopping the returned worker waits for running jobs to return.
+func setupScheduler(cfg *config.Config, repos *Repositories, keys *jwtkeys.KeySet, clk clock.Clock) *worker {
+	if !cfg.SchedulerEnabled {
+		return nil
+	}
+
+	s := scheduler.New()
+	s.Every("heartbeat", 15*time.Second, jobs.Heartbeat)
+	s.Every("idempotency_purge", time.Minute, jobs.PurgeUnfinishedIdempotency(repos.Idempotency, clk))
+	s.Every("entry_statistics", time.Minute, jobs.EntryStatistics(repos.Entry))
+	// Hourly, so a day's files follow the simulated clock into that day within the hour
+	s.Every("reconciliation_files", time.Hour, jobs.ReconciliationFiles(repos.Entry, repos.Reconciliation, clk))
+	// Each key is reviewed once per simulated day; running every minute reviews new keys within a minute
+	if cfg.RFBIrregularRate > 0 {
+		s.Every("rfb_review", time.Minute, jobs.RFBReview(repos.Entry, clk, cfg.RFBIrregularRate))
+	}
+	// Policies set to 0 are skipped; with none left the job isn't scheduled
+	policies := []jobs.RetentionPolicy{
+		{Collection: "admin_audit", Retention: cfg.AdminAuditRetention, Purge: repos.AdminAudit.DeleteCreatedBefore},
+		{Collection: "fraud_markers", Retention: cfg.FraudMarkerRetention, Purge: repos.FraudMarker.DeleteCreatedBefore},
+		{Collection: "webhook_deliveries", Retention: cfg.WebhookDeliveryRetention, Purge: repos.WebhookDelivery.DeleteCreatedBefore},
+		{Collection: "idempotency", Retention: cfg.IdempotencyRetention, Purge: repos.Idempotency.DeleteExpired},
+	}
+	if slices.ContainsFunc(policies, func(p jobs.RetentionPolicy) bool { return p.Retention > 0 }) {
+		s.Every("retention", time.Minute, jobs.Retention(policies, clk))
+	}
+	// Checked every minute, so a key pair is replaced within a minute of JWT_KEY_ROTATION_INTERVAL
+	if keys.Algorithm() != jwtkeys.HS256 {
+		s.Every("jwt_key_rotation", time.Minute, keys.Rotate)
+	}
+
+	return &worker{name: "scheduler", run: s.Run}
+}
+
+// setupRateLimiter creates the token buckets: in Redis, or in process memory without it.
+// In-memory buckets are loaded from RATE_LIMIT_STATE_FILE, when set, so a restart keeps each
+// participant's budget; the returned function saves them back on shutdown. Redis buckets load
+// their scripts into Redis first, unless it is unreachable: they are sent when first run then.
+func setupRateLimiter(ctx context.Context, cfg *config.Config, redisDB *db.Redis, clk clock.Clock) (ratelimit.Limiter, func(context.Context) error, error) {
+	saveNothing := func(context.Context) error { return nil }
+	if redisDB != nil {
+		buckets := ratelimit.NewBucket(redisDB.Client, clk)
+		if err := buckets.LoadScripts(ctx); err != nil {
+			logger.Warn("Rate limit scripts not loaded; they will be sent when first run", zap.Error(err))
+		}
+		return buckets, saveNothing, nil
+	}
+
+	buckets := ratelimit.NewMemoryBucket(clk)
+	path := cfg.RateLimitStateFile
+	if path == "" {
+		return buckets, saveNothing, nil
+	}
+
+	f, err := os.Open(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		logger.Info("No rate limit state to load; buckets start over", zap.String("file", path))
+	case err != nil:
+		return nil, nil, fmt.Errorf("open rate limit state: %w", err)
+	default:
+		err = buckets.Load(f)
+		f.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("load rate limit state from %s: %w", path, err)
+		}
+		logger.Info("Rate limit state loaded", zap.String("file", path))
+	}
+
+	return buckets, func(context.Context) error {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		if err := buckets.Save(f); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}, nil
+}
@@ -0,0 +1,38 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+)
+
+// Supported brokers
+const (
+	KindNATS  = "nats"
+	KindKafka = "kafka"
+)
+
+// Message is a serialized event addressed to a topic
+type Message struct {
+	Topic string // NATS subject or Kafka topic
+	Key   string // Partitioning key; events of one participant share a key to keep their order
+	Value []byte
+}
+
+// Broker publishes messages to an external message broker
+// Publish returns only once the broker has accepted the message.
+type Broker interface {
+	Publish(ctx context.Context, msg Message) error
+	Close() error
+}
+
+// New creates the broker client for kind
+func New(kind, url string) (Broker, error) {
+	switch kind {
+	case KindNATS:
+		return NewNATS(url), nil
+	case KindKafka:
+		return NewKafkaREST(url), nil
+	default:
+		return nil, fmt.Errorf("unsupported event broker %q", kind)
+	}
+}
@@ -0,0 +1,50 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestKafkaRESTPublish(t *testing.T) {
+	var got kafkaProduceRequest
+	var path, contentType string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		contentType = r.Header.Get("Content-Type")
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"offsets":[{"partition":0,"offset":42}]}`))
+	}))
+	defer srv.Close()
+
+	k := NewKafkaREST(srv.URL + "/")
+	err := k.Publish(context.Background(), Message{Topic: "dict.events", Key: "12345678", Value: []byte(`{"id":"1"}`)})
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if path != "/topics/dict.events" {
+		t.Errorf("path = %q, want /topics/dict.events", path)
+	}
+	if contentType != kafkaJSONContentType {
+		t.Errorf("Content-Type = %q, want %q", contentType, kafkaJSONContentType)
+	}
+	if len(got.Records) != 1 || got.Records[0].Key != "12345678" || string(got.Records[0].Value) != `{"id":"1"}` {
+		t.Errorf("records = %+v, want one record keyed 12345678", got.Records)
+	}
+}
+
+func TestKafkaRESTPublishRecordError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"offsets":[{"partition":null,"offset":null,"error_code":50003,"error":"Kafka error"}]}`))
+	}))
+	defer srv.Close()
+
+	err := NewKafkaREST(srv.URL).Publish(context.Background(), Message{Topic: "dict.events", Value: []byte(`{}`)})
+	if err == nil {
+		t.Errorf("Publish() error = nil, want record error")
+	}
+}
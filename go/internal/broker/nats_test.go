@@ -0,0 +1,116 @@
+package broker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+// fakeNATS accepts one connection, records the CONNECT and PUB it receives, answers the
+// handshake PING with PONG and every later PING with reply
+func fakeNATS(t *testing.T, reply string) (string, <-chan string, <-chan string) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	connected := make(chan string, 1)
+	published := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		fmt.Fprint(conn, "INFO {\"server_id\":\"test\",\"max_payload\":1048576}\r\n")
+		r := bufio.NewReader(conn)
+
+		handshake := true
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch {
+			case strings.HasPrefix(line, "CONNECT "):
+				connected <- strings.TrimSpace(strings.TrimPrefix(line, "CONNECT "))
+			case strings.HasPrefix(line, "PUB "):
+				var subject string
+				var size int
+				fmt.Sscanf(line, "PUB %s %d", &subject, &size)
+				payload := make([]byte, size+2)
+				io.ReadFull(r, payload)
+				published <- subject + " " + string(payload[:size])
+			case strings.HasPrefix(line, "PING"):
+				if handshake {
+					handshake = false
+					fmt.Fprint(conn, "PONG\r\n")
+					continue
+				}
+				fmt.Fprint(conn, reply)
+			}
+		}
+	}()
+
+	return "nats://" + ln.Addr().String(), connected, published
+}
+
+func TestNATSPublish(t *testing.T) {
+	url, _, published := fakeNATS(t, "PONG\r\n")
+
+	n := NewNATS(url)
+	defer n.Close()
+
+	err := n.Publish(context.Background(), Message{Topic: "dict.events.ENTRY_CREATED", Value: []byte(`{"id":"1"}`)})
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	want := `dict.events.ENTRY_CREATED {"id":"1"}`
+	if got := <-published; got != want {
+		t.Errorf("server received %q, want %q", got, want)
+	}
+}
+
+func TestNATSPublishSendsCredentials(t *testing.T) {
+	url, connected, _ := fakeNATS(t, "PONG\r\n")
+
+	n := NewNATS(strings.Replace(url, "nats://", "nats://dict:s3cret@", 1))
+	defer n.Close()
+
+	if err := n.Publish(context.Background(), Message{Topic: "dict.events.ENTRY_CREATED", Value: []byte(`{}`)}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	var options struct {
+		User string `json:"user"`
+		Pass string `json:"pass"`
+	}
+	if err := json.Unmarshal([]byte(<-connected), &options); err != nil {
+		t.Fatalf("decode CONNECT: %v", err)
+	}
+	if options.User != "dict" || options.Pass != "s3cret" {
+		t.Errorf("CONNECT user/pass = %q/%q, want the URL's credentials", options.User, options.Pass)
+	}
+}
+
+func TestNATSPublishServerError(t *testing.T) {
+	url, _, _ := fakeNATS(t, "-ERR 'Permissions Violation for Publish to \"dict.events.ENTRY_CREATED\"'\r\nPONG\r\n")
+
+	n := NewNATS(url)
+	defer n.Close()
+
+	err := n.Publish(context.Background(), Message{Topic: "dict.events.ENTRY_CREATED", Value: []byte(`{}`)})
+	if err == nil || !strings.Contains(err.Error(), "Permissions Violation") {
+		t.Errorf("Publish() error = %v, want permissions violation", err)
+	}
+}
@@ -0,0 +1,213 @@
+package changestream
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/db"
+	"github.com/dict-simulator/go/internal/events"
+	"github.com/dict-simulator/go/internal/logger"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/pii"
+)
+
+// entriesStream names the entries change stream in the offsets collection
+const entriesStream = "entries"
+
+// retryDelay is how long the watcher waits before reopening a failed stream
+const retryDelay = time.Second
+
+// Server error codes meaning the stored resume token can no longer be used
+const (
+	changeStreamHistoryLostCode = 286
+	changeStreamFatalErrorCode  = 280
+)
+
+// changeEvent is the subset of a MongoDB change event the watcher needs
+type changeEvent struct {
+	OperationType            string        `bson:"operationType"`
+	FullDocument             *models.Entry `bson:"fullDocument"`
+	FullDocumentBeforeChange *models.Entry `bson:"fullDocumentBeforeChange"`
+}
+
+// Watcher tails the entries collection and publishes a directory event for every change.
+// Events are derived from committed writes rather than from request handlers, so a handler
+// crashing after its write still produces the event. The resume token is stored after each
+// event is published, giving at-least-once delivery across restarts; redelivered events keep
+// the same ID since it is derived from the change's resume token.
+type Watcher struct {
+	collection *mongo.Collection
+	offsets    *models.StreamOffsetRepository
+	publisher  events.Publisher
+	keys       *pii.Keyring // opens sealed owners; nil when they are stored in plaintext
+	clock      clock.Clock
+}
+
+// NewWatcher creates a watcher for the entries collection
+func NewWatcher(mongoDB *db.Mongo, offsets *models.StreamOffsetRepository, publisher events.Publisher, keys *pii.Keyring, clk clock.Clock) *Watcher {
+	return &Watcher{
+		collection: mongoDB.Collection("entries"),
+		offsets:    offsets,
+		publisher:  publisher,
+		keys:       keys,
+		clock:      clk,
+	}
+}
+
+// Init pins the current stream position when none is stored yet, so writes made once it returns
+// are published even if Run has not opened the stream by then
+func (w *Watcher) Init(ctx context.Context) error {
+	token, err := w.offsets.Find(ctx, entriesStream)
+	if err != nil || token != nil {
+		return err
+	}
+
+	stream, err := w.collection.Watch(ctx, mongo.Pipeline{})
+	if err != nil {
+		return err
+	}
+	defer stream.Close(ctx)
+
+	return w.offsets.Save(ctx, entriesStream, stream.ResumeToken())
+}
+
+// Run consumes the change stream until ctx is cancelled, reopening it after failures
+func (w *Watcher) Run(ctx context.Context) {
+	for {
+		err := w.watch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		logger.Error("Entries change stream failed, reopening", zap.Error(err))
+
+		// The stored position fell off the oplog; start again from the current head
+		if isResumeTokenLost(err) {
+			logger.Warn("Entries change stream position lost, events in the gap are skipped")
+			if err := w.offsets.Delete(ctx, entriesStream); err != nil {
+				logger.Error("Failed to reset entries stream offset", zap.Error(err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+func (w *Watcher) watch(ctx context.Context) error {
+	token, err := w.offsets.Find(ctx, entriesStream)
+	if err != nil {
+		return err
+	}
+
+	opts := options.ChangeStream().
+		SetFullDocument(options.UpdateLookup).
+		SetFullDocumentBeforeChange(options.WhenAvailable)
+	if token != nil {
+		opts.SetStartAfter(token)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"operationType": bson.M{"$in": bson.A{"insert", "update", "replace", "delete"}}}}},
+	}
+
+	stream, err := w.collection.Watch(ctx, pipeline, opts)
+	if err != nil {
+		return err
+	}
+	defer stream.Close(context.WithoutCancel(ctx))
+
+	logger.Info("Entries change stream opened", zap.Bool("resumed", token != nil))
+
+	for stream.Next(ctx) {
+		var change changeEvent
+		if err := stream.Decode(&change); err != nil {
+			return err
+		}
+
+		if event, ok := w.toEvent(stream.ResumeToken(), &change); ok {
+			w.publisher.Publish(ctx, event)
+		}
+
+		if err := w.offsets.Save(ctx, entriesStream, stream.ResumeToken()); err != nil {
+			return err
+		}
+	}
+
+	return stream.Err()
+}
+
+// toEvent maps a change to a directory event; changes that cannot be attributed to a participant are skipped
+func (w *Watcher) toEvent(token bson.Raw, change *changeEvent) (events.Event, bool) {
+	var (
+		eventType  events.Type
+		entry      *models.Entry
+		occurredAt time.Time
+	)
+
+	switch change.OperationType {
+	case "insert":
+		eventType, entry = events.EntryCreated, change.FullDocument
+		if entry != nil {
+			occurredAt = entry.CreatedAt
+		}
+	case "update", "replace":
+		eventType, entry = events.EntryUpdated, change.FullDocument
+		if entry != nil {
+			occurredAt = entry.UpdatedAt
+		}
+	case "delete":
+		eventType, entry = events.EntryDeleted, change.FullDocumentBeforeChange
+		occurredAt = w.clock.Now()
+	}
+
+	// Updates whose document was deleted before the lookup, or deletes without a pre-image
+	if entry == nil {
+		logger.Warn("Skipping entry change without a document",
+			zap.String("operationType", change.OperationType),
+		)
+		return events.Event{}, false
+	}
+
+	// The stream reads the collection directly, past the repository that opens owners
+	if w.keys != nil {
+		if err := w.keys.OpenOwner(&entry.Owner); err != nil {
+			logger.Warn("Skipping entry change whose owner cannot be decrypted",
+				zap.String("operationType", change.OperationType),
+				zap.Error(err),
+			)
+			return events.Event{}, false
+		}
+	}
+
+	data := entry.ToResponse()
+	if eventType == events.EntryDeleted {
+		data = entry.DeletedResponse()
+	}
+	return events.Event{
+		ID:          uuid.NewSHA1(uuid.NameSpaceOID, token).String(),
+		Type:        eventType,
+		Participant: entry.Account.Participant,
+		OccurredAt:  occurredAt.UTC(),
+		Data:        data,
+	}, true
+}
+
+func isResumeTokenLost(err error) bool {
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return false
+	}
+	return serverErr.HasErrorCode(changeStreamHistoryLostCode) || serverErr.HasErrorCode(changeStreamFatalErrorCode)
+}
@@ -0,0 +1,99 @@
+package chaos
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// Phase is where a brownout stands on its ramp
+type Phase string
+
+const (
+	PhaseRampingUp   Phase = "RAMPING_UP"
+	PhaseHolding     Phase = "HOLDING"
+	PhaseRampingDown Phase = "RAMPING_DOWN"
+	PhaseEnded       Phase = "ENDED"
+)
+
+// Brownout degrades the service progressively, to exercise client circuit breakers and retry
+// policies: the share of requests answered 503 or delayed grows from none to its peak over
+// RampUp, stays there for Hold, then shrinks back to none over RampDown.
+type Brownout struct {
+	ErrorRate   float64       // share of requests answered 503 at the peak
+	LatencyRate float64       // share of requests delayed by Latency at the peak
+	Latency     time.Duration // delay of a slowed request, which doesn't ramp
+	RampUp      time.Duration
+	Hold        time.Duration // zero holds the peak until the brownout is stopped
+	RampDown    time.Duration
+	StartedAt   time.Time
+}
+
+// At returns the phase of the brownout at t and its intensity, from 0 (no degradation) to 1 (the peak)
+func (b Brownout) At(t time.Time) (Phase, float64) {
+	elapsed := max(t.Sub(b.StartedAt), 0)
+	if elapsed < b.RampUp {
+		return PhaseRampingUp, float64(elapsed) / float64(b.RampUp)
+	}
+
+	elapsed -= b.RampUp
+	if b.Hold == 0 || elapsed < b.Hold {
+		return PhaseHolding, 1
+	}
+
+	elapsed -= b.Hold
+	if elapsed < b.RampDown {
+		return PhaseRampingDown, 1 - float64(elapsed)/float64(b.RampDown)
+	}
+	return PhaseEnded, 0
+}
+
+// trigger returns the faults the brownout injects into one request at now
+// The latency and the error are rolled independently, so a request can be delayed and then fail.
+func (b Brownout) trigger(now time.Time) []Fault {
+	_, intensity := b.At(now)
+	if intensity == 0 {
+		return nil
+	}
+
+	var faults []Fault
+	if b.Latency > 0 && rand.Float64() < b.LatencyRate*intensity {
+		faults = append(faults, Fault{Type: FaultLatency, LatencyMs: int(b.Latency.Milliseconds()), Probability: 1})
+	}
+	if rand.Float64() < b.ErrorRate*intensity {
+		faults = append(faults, Fault{Type: FaultError, StatusCode: http.StatusServiceUnavailable, Probability: 1})
+	}
+	return faults
+}
+
+// StartBrownout starts b now, replacing any brownout in progress, and returns it
+func (i *Injector) StartBrownout(b Brownout) Brownout {
+	b.StartedAt = i.clock.Now().UTC()
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.brownout = &b
+
+	return b
+}
+
+// Brownout returns the brownout in effect, ended or not, and whether there is one
+func (i *Injector) Brownout() (Brownout, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	if i.brownout == nil {
+		return Brownout{}, false
+	}
+	return *i.brownout, true
+}
+
+// StopBrownout ends the brownout at once and reports whether there was one
+func (i *Injector) StopBrownout() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	stopped := i.brownout != nil
+	i.brownout = nil
+	return stopped
+}
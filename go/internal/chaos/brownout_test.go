@@ -0,0 +1,76 @@
+package chaos
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/dict-simulator/go/internal/clock"
+)
+
+func TestBrownoutAt(t *testing.T) {
+	start := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	b := Brownout{RampUp: time.Minute, Hold: 2 * time.Minute, RampDown: time.Minute, StartedAt: start}
+
+	tests := []struct {
+		after     time.Duration
+		phase     Phase
+		intensity float64
+	}{
+		{0, PhaseRampingUp, 0},
+		{15 * time.Second, PhaseRampingUp, 0.25},
+		{time.Minute, PhaseHolding, 1},
+		{2 * time.Minute, PhaseHolding, 1},
+		{3*time.Minute + 45*time.Second, PhaseRampingDown, 0.25},
+		{4 * time.Minute, PhaseEnded, 0},
+		{-time.Minute, PhaseRampingUp, 0}, // before it started
+	}
+
+	for _, tt := range tests {
+		phase, intensity := b.At(start.Add(tt.after))
+		if phase != tt.phase || math.Abs(intensity-tt.intensity) > 1e-9 {
+			t.Errorf("At(start+%s) = %s, %v; want %s, %v", tt.after, phase, intensity, tt.phase, tt.intensity)
+		}
+	}
+
+	// Without a hold the peak lasts until the brownout is stopped
+	endless := Brownout{StartedAt: start}
+	if phase, intensity := endless.At(start.Add(24 * time.Hour)); phase != PhaseHolding || intensity != 1 {
+		t.Errorf("At() without a hold = %s, %v; want HOLDING, 1", phase, intensity)
+	}
+}
+
+func TestInjectorBrownout(t *testing.T) {
+	clk := clock.NewSimulated()
+	injector := NewInjector(clk)
+
+	if _, ok := injector.Brownout(); ok {
+		t.Error("Brownout() reported one before any was started")
+	}
+
+	b := injector.StartBrownout(Brownout{ErrorRate: 1, LatencyRate: 1, Latency: 5 * time.Millisecond, Hold: time.Minute, RampDown: time.Minute})
+	if b.StartedAt.IsZero() {
+		t.Error("StartBrownout() did not stamp StartedAt")
+	}
+
+	// At the peak every request is delayed, then fails
+	faults := injector.Triggered("GET /entries/{key}", "12345678909")
+	if len(faults) != 2 || faults[0].Type != FaultLatency || faults[0].LatencyMs != 5 || faults[1].Type != FaultError || faults[1].StatusCode != DefaultErrorStatus {
+		t.Errorf("Triggered() at the peak = %+v, want a 5ms latency then a 503", faults)
+	}
+
+	// The ramp follows the simulated clock
+	clk.Advance(2 * time.Minute)
+	if faults := injector.Triggered("GET /entries/{key}", "12345678909"); faults != nil {
+		t.Errorf("Triggered() after the brownout ended = %+v, want none", faults)
+	}
+	if b, ok := injector.Brownout(); !ok {
+		t.Error("Brownout() lost an ended brownout")
+	} else if phase, _ := b.At(clk.Now()); phase != PhaseEnded {
+		t.Errorf("phase = %s, want ENDED", phase)
+	}
+
+	if !injector.StopBrownout() || injector.StopBrownout() {
+		t.Error("StopBrownout() should report the brownout once")
+	}
+}
@@ -0,0 +1,182 @@
+package chaos
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dict-simulator/go/internal/clock"
+)
+
+// FaultType identifies the kind of fault to inject
+type FaultType string
+
+const (
+	// FaultLatency delays the request before it reaches the handler
+	FaultLatency FaultType = "LATENCY"
+
+	// FaultError short-circuits the request with a 5xx response
+	FaultError FaultType = "ERROR"
+
+	// FaultReset drops the connection without writing a response
+	FaultReset FaultType = "RESET"
+)
+
+// DefaultErrorStatus is used for ERROR faults that don't specify a status code
+const DefaultErrorStatus = http.StatusServiceUnavailable
+
+// Fault describes a single fault injection rule
+// A rule matches when both Route and KeySuffix match (empty fields match everything)
+// and then triggers with the given Probability
+type Fault struct {
+	ID          string    `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Type        FaultType `json:"type" validate:"required,oneof=LATENCY ERROR RESET" example:"ERROR"`
+	Route       string    `json:"route,omitempty" example:"GET /entries/{key}"`
+	KeySuffix   string    `json:"keySuffix,omitempty" example:"999"`
+	Probability float64   `json:"probability,omitempty" validate:"omitempty,gt=0,lte=1" example:"1"`
+	LatencyMs   int       `json:"latencyMs,omitempty" validate:"required_if=Type LATENCY,omitempty,min=1,max=60000" example:"1500"`
+	StatusCode  int       `json:"statusCode,omitempty" validate:"omitempty,min=500,max=599" example:"503"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// Latency returns the configured delay as a duration
+func (f Fault) Latency() time.Duration {
+	return time.Duration(f.LatencyMs) * time.Millisecond
+}
+
+// matches reports whether the rule applies to the given route pattern and key
+func (f Fault) matches(route, key string) bool {
+	if f.Route != "" && f.Route != route {
+		return false
+	}
+	if f.KeySuffix != "" && (key == "" || !strings.HasSuffix(key, f.KeySuffix)) {
+		return false
+	}
+	return true
+}
+
+// Injector holds the active fault rules and brownout
+// Rules live in memory and are local to a single simulator instance
+type Injector struct {
+	mu       sync.RWMutex
+	faults   []Fault
+	brownout *Brownout
+	clock    clock.Clock
+}
+
+// NewInjector creates an injector with no active rules
+// clk stamps CreatedAt on the rules it adds and drives brownout ramps, so time travel moves them along.
+func NewInjector(clk clock.Clock) *Injector {
+	return &Injector{clock: clk}
+}
+
+// withDefaults fills in the probability and status code of rules that leave them out
+func (f Fault) withDefaults() Fault {
+	if f.Probability == 0 {
+		f.Probability = 1
+	}
+	if f.Type == FaultError && f.StatusCode == 0 {
+		f.StatusCode = DefaultErrorStatus
+	}
+	return f
+}
+
+// ParseFaults parses rules from a JSON array, as in the FAULT_RULES setting:
+//
+//	[{"type":"ERROR","route":"GET /entries/{key}","probability":0.1}]
+//
+// Defaults are filled in as by Add, but rules get no ID. An empty string yields no rules.
+func ParseFaults(s string) ([]Fault, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.DisallowUnknownFields()
+	var faults []Fault
+	if err := dec.Decode(&faults); err != nil {
+		return nil, err
+	}
+	for idx := range faults {
+		faults[idx] = faults[idx].withDefaults()
+	}
+	return faults, nil
+}
+
+// Add registers a new rule, filling in the ID and defaults, and returns it
+func (i *Injector) Add(f Fault) Fault {
+	f = f.withDefaults()
+	f.ID = uuid.New().String()
+	f.CreatedAt = i.clock.Now().UTC()
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.faults = append(i.faults, f)
+
+	return f
+}
+
+// List returns a snapshot of the active rules
+func (i *Injector) List() []Fault {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	faults := make([]Fault, len(i.faults))
+	copy(faults, i.faults)
+	return faults
+}
+
+// Remove deletes a rule by ID and reports whether it existed
+func (i *Injector) Remove(id string) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for idx, f := range i.faults {
+		if f.ID == id {
+			i.faults = append(i.faults[:idx], i.faults[idx+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Clear removes all rules
+func (i *Injector) Clear() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.faults = nil
+}
+
+// Triggered returns the rules that fire for this request, in registration order, then the
+// faults the brownout injects
+func (i *Injector) Triggered(route, key string) []Fault {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	triggered := Trigger(i.faults, route, key)
+	if i.brownout != nil {
+		triggered = append(triggered, i.brownout.trigger(i.clock.Now())...)
+	}
+	return triggered
+}
+
+// Trigger returns the rules from faults that fire for this request, in order
+// Each matching rule rolls its own probability independently
+func Trigger(faults []Fault, route, key string) []Fault {
+	if len(faults) == 0 {
+		return nil
+	}
+
+	var triggered []Fault
+	for _, f := range faults {
+		if f.matches(route, key) && rand.Float64() < f.Probability {
+			triggered = append(triggered, f)
+		}
+	}
+	return triggered
+}
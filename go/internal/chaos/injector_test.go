@@ -0,0 +1,135 @@
+package chaos
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dict-simulator/go/internal/clock"
+)
+
+func TestFaultMatches(t *testing.T) {
+	tests := []struct {
+		name  string
+		fault Fault
+		route string
+		key   string
+		want  bool
+	}{
+		{
+			name:  "empty rule matches everything",
+			fault: Fault{},
+			route: "POST /entries",
+			want:  true,
+		},
+		{
+			name:  "route must match exactly",
+			fault: Fault{Route: "GET /entries/{key}"},
+			route: "PUT /entries/{key}",
+			key:   "12345678909",
+			want:  false,
+		},
+		{
+			name:  "key suffix matches",
+			fault: Fault{Route: "GET /entries/{key}", KeySuffix: "999"},
+			route: "GET /entries/{key}",
+			key:   "12345678999",
+			want:  true,
+		},
+		{
+			name:  "key suffix does not match",
+			fault: Fault{KeySuffix: "999"},
+			route: "GET /entries/{key}",
+			key:   "12345678909",
+			want:  false,
+		},
+		{
+			name:  "key suffix never matches routes without a key",
+			fault: Fault{KeySuffix: "999"},
+			route: "POST /entries",
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.fault.matches(tt.route, tt.key)
+			if got != tt.want {
+				t.Errorf("matches(%q, %q) = %v, want %v", tt.route, tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInjectorAddDefaults(t *testing.T) {
+	clk := clock.NewSimulated()
+	clk.Advance(24 * time.Hour)
+	injector := NewInjector(clk)
+
+	f := injector.Add(Fault{Type: FaultError})
+
+	if f.ID == "" {
+		t.Errorf("Add() did not assign an ID")
+	}
+	if f.Probability != 1 {
+		t.Errorf("Probability = %v, want 1", f.Probability)
+	}
+	if f.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("StatusCode = %d, want %d", f.StatusCode, http.StatusServiceUnavailable)
+	}
+	if f.CreatedAt.Before(time.Now().Add(23 * time.Hour)) {
+		t.Errorf("CreatedAt = %v, want the simulated time", f.CreatedAt)
+	}
+}
+
+func TestInjectorTriggeredAndRemove(t *testing.T) {
+	injector := NewInjector(clock.System)
+
+	latency := injector.Add(Fault{Type: FaultLatency, LatencyMs: 10})
+	injector.Add(Fault{Type: FaultError, KeySuffix: "999"})
+
+	if got := len(injector.Triggered("GET /entries/{key}", "12345678999")); got != 2 {
+		t.Errorf("Triggered() returned %d faults, want 2", got)
+	}
+	if got := len(injector.Triggered("GET /entries/{key}", "12345678909")); got != 1 {
+		t.Errorf("Triggered() returned %d faults, want 1", got)
+	}
+
+	if !injector.Remove(latency.ID) {
+		t.Errorf("Remove(%q) = false, want true", latency.ID)
+	}
+	if injector.Remove(latency.ID) {
+		t.Errorf("Remove(%q) on removed fault = true, want false", latency.ID)
+	}
+	if got := len(injector.List()); got != 1 {
+		t.Errorf("List() returned %d faults, want 1", got)
+	}
+
+	injector.Clear()
+	if got := injector.Triggered("GET /entries/{key}", "12345678999"); got != nil {
+		t.Errorf("Triggered() after Clear() = %v, want nil", got)
+	}
+}
+
+func TestParseFaults(t *testing.T) {
+	faults, err := ParseFaults(`[{"type":"ERROR","route":"GET /entries/{key}"},{"type":"LATENCY","latencyMs":50,"probability":0.5}]`)
+	if err != nil {
+		t.Fatalf("ParseFaults() error = %v", err)
+	}
+	if len(faults) != 2 {
+		t.Fatalf("ParseFaults() returned %d faults, want 2", len(faults))
+	}
+	if faults[0].Probability != 1 || faults[0].StatusCode != DefaultErrorStatus {
+		t.Errorf("ParseFaults() did not fill in defaults: %+v", faults[0])
+	}
+	if faults[1].Probability != 0.5 {
+		t.Errorf("Probability = %v, want 0.5", faults[1].Probability)
+	}
+
+	if faults, err := ParseFaults(""); err != nil || faults != nil {
+		t.Errorf("ParseFaults(\"\") = %v, %v; want no rules", faults, err)
+	}
+	if _, err := ParseFaults(`[{"type":"ERROR","status":500}]`); err == nil {
+		t.Errorf("ParseFaults() accepted an unknown field")
+	}
+}
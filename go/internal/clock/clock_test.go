@@ -0,0 +1,39 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSimulatedAdvance(t *testing.T) {
+	c := NewSimulated()
+
+	if c.Offset() != 0 {
+		t.Errorf("new clock Offset() = %v, want 0", c.Offset())
+	}
+
+	week := 7 * 24 * time.Hour
+	before := time.Now()
+	now := c.Advance(week)
+
+	if now.Before(before.Add(week)) {
+		t.Errorf("Advance(%v) = %v, want at least %v", week, now, before.Add(week))
+	}
+	if got := c.Now(); got.Before(now) {
+		t.Errorf("Now() = %v went backwards from %v", got, now)
+	}
+
+	c.Advance(time.Hour)
+	if c.Offset() != week+time.Hour {
+		t.Errorf("Offset() = %v, want %v", c.Offset(), week+time.Hour)
+	}
+
+	if loc := c.Now().Location(); loc != time.UTC {
+		t.Errorf("Now() location = %v, want UTC", loc)
+	}
+
+	c.Reset()
+	if got := c.Now(); got.Sub(time.Now()) > time.Second {
+		t.Errorf("Now() after Reset() = %v, want wall clock", got)
+	}
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"go.yaml.in/yaml/v2"
+)
+
+// readFile reads a YAML config file of flat settings named after their environment variables:
+//
+//	PORT: 3000
+//	STORAGE: postgres
+//	WEBHOOK_TIMEOUT: 10s
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	settings := make(map[string]string, len(raw))
+	for key, value := range raw {
+		switch value.(type) {
+		case nil:
+			settings[key] = ""
+		case string, bool, int, float64:
+			settings[key] = fmt.Sprint(value)
+		default:
+			return nil, fmt.Errorf("%s: %s must be a single value", path, key)
+		}
+	}
+	return settings, nil
+}
@@ -0,0 +1,255 @@
+package config
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// loader reads settings by their environment variable name and collects every invalid one
+// Each getter returns the default when a setting is unset or empty, and the default plus a
+// recorded problem when it is set but invalid, so Parse can report all problems at once.
+type loader struct {
+	lookup   func(key string) (string, bool)
+	problems []error
+	seen     map[string]bool
+}
+
+func newLoader(lookup func(key string) (string, bool)) *loader {
+	return &loader{lookup: lookup, seen: map[string]bool{}}
+}
+
+func (l *loader) get(key string) (string, bool) {
+	l.seen[key] = true
+	value, ok := l.lookup(key)
+	if !ok || value == "" {
+		return "", false
+	}
+	return value, true
+}
+
+func (l *loader) problemf(format string, args ...any) {
+	l.problems = append(l.problems, fmt.Errorf(format, args...))
+}
+
+func (l *loader) str(key, def string) string {
+	if value, ok := l.get(key); ok {
+		return value
+	}
+	return def
+}
+
+func (l *loader) required(key string) string {
+	value, ok := l.get(key)
+	if !ok {
+		l.problemf("%s is required", key)
+	}
+	return value
+}
+
+func (l *loader) oneOf(key, def string, allowed ...string) string {
+	value := l.str(key, def)
+	if !slices.Contains(allowed, value) {
+		l.problemf("%s must be one of %s, got %q", key, strings.Join(allowed, ", "), value)
+		return def
+	}
+	return value
+}
+
+// integer reads a whole number in [lo, hi]
+func (l *loader) integer(key string, def, lo, hi int) int {
+	value, ok := l.get(key)
+	if !ok {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		l.problemf("%s must be an integer, got %q", key, value)
+		return def
+	}
+	if n < lo || n > hi {
+		l.problemf("%s must be between %d and %d, got %d", key, lo, hi, n)
+		return def
+	}
+	return n
+}
+
+// duration reads a positive Go duration such as 500ms or 5m
+func (l *loader) duration(key string, def time.Duration) time.Duration {
+	value, ok := l.get(key)
+	if !ok {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		l.problemf("%s must be a duration such as 5s or 1m, got %q", key, value)
+		return def
+	}
+	if d <= 0 {
+		l.problemf("%s must be positive, got %s", key, value)
+		return def
+	}
+	return d
+}
+
+// optionalDuration reads a Go duration such as 720h, where 0 turns off what it configures
+func (l *loader) optionalDuration(key string, def time.Duration) time.Duration {
+	value, ok := l.get(key)
+	if !ok {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		l.problemf("%s must be a duration such as 24h, or 0, got %q", key, value)
+		return def
+	}
+	if d < 0 {
+		l.problemf("%s must not be negative, got %s", key, value)
+		return def
+	}
+	return d
+}
+
+func (l *loader) boolean(key string, def bool) bool {
+	value, ok := l.get(key)
+	if !ok {
+		return def
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		l.problemf("%s must be true or false, got %q", key, value)
+		return def
+	}
+	return b
+}
+
+// fraction reads a number strictly between 0 and 1
+func (l *loader) fraction(key string, def float64) float64 {
+	value, ok := l.get(key)
+	if !ok {
+		return def
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f <= 0 || f >= 1 {
+		l.problemf("%s must be a number between 0 and 1, got %q", key, value)
+		return def
+	}
+	return f
+}
+
+// ratio reads a number between 0 and 1 inclusive
+func (l *loader) ratio(key string, def float64) float64 {
+	value, ok := l.get(key)
+	if !ok {
+		return def
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 || f > 1 {
+		l.problemf("%s must be a number from 0 to 1, got %q", key, value)
+		return def
+	}
+	return f
+}
+
+// date reads a calendar day such as 2027-06-30, as midnight UTC
+func (l *loader) date(key string) time.Time {
+	value, ok := l.get(key)
+	if !ok {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		l.problemf("%s must be a date such as 2027-06-30, got %q", key, value)
+		return time.Time{}
+	}
+	return t
+}
+
+// uri reads an absolute URI with one of the given schemes
+// URIs can carry credentials, so problems don't quote the value.
+func (l *loader) uri(key, def string, schemes ...string) string {
+	value := l.str(key, def)
+	if value == "" {
+		return value
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		l.problemf("%s is not a valid URI", key)
+		return value
+	}
+	if !slices.Contains(schemes, u.Scheme) {
+		l.problemf("%s must use scheme %s", key, strings.Join(schemes, " or "))
+		return value
+	}
+	if u.Host == "" {
+		l.problemf("%s has no host", key)
+	}
+	return value
+}
+
+// prefixes reads a comma-separated list of CIDR ranges; a bare address is a range of one
+func (l *loader) prefixes(key string) []netip.Prefix {
+	value, ok := l.get(key)
+	if !ok {
+		return nil
+	}
+	var out []netip.Prefix
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if addr, err := netip.ParseAddr(item); err == nil {
+			out = append(out, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(item)
+		if err != nil {
+			l.problemf("%s must be a comma-separated list of CIDR ranges or addresses, got %q", key, item)
+			return nil
+		}
+		out = append(out, prefix.Masked())
+	}
+	return out
+}
+
+// names reads a comma-separated list of field names
+func (l *loader) names(key string, def []string) []string {
+	value, ok := l.get(key)
+	if !ok {
+		return def
+	}
+	var out []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// routes reads a comma-separated list of route patterns as the ServeMux writes them ("GET /entries/{key}")
+func (l *loader) routes(key string) []string {
+	value, ok := l.get(key)
+	if !ok {
+		return nil
+	}
+	var out []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		method, path, ok := strings.Cut(item, " ")
+		if !ok || method == "" || method != strings.ToUpper(method) || !strings.HasPrefix(path, "/") {
+			l.problemf("%s must be a comma-separated list of route patterns like \"GET /entries/{key}\", got %q", key, item)
+			return nil
+		}
+		out = append(out, item)
+	}
+	return out
+}
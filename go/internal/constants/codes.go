@@ -7,28 +7,178 @@ const (
 	CodeInvalidRequest = "INVALID_REQUEST"
 	CodeInternalError  = "INTERNAL_ERROR"
 	CodeForbidden      = "FORBIDDEN"
+	CodeOpenAPIDrift   = "OPENAPI_DRIFT"
+
+	// Request body codes
+	CodePayloadTooLarge = "PAYLOAD_TOO_LARGE"
+
+	// Routing codes
+	CodeNotFound         = "NOT_FOUND"
+	CodeMethodNotAllowed = "METHOD_NOT_ALLOWED"
+
+	// Availability codes
+	CodeServiceUnavailable  = "SERVICE_UNAVAILABLE"
+	CodeTimeout             = "TIMEOUT"
+	CodeClientClosedRequest = "CLIENT_CLOSED_REQUEST"
 
 	// Entry-specific codes
-	CodeEntryNotFound    = "ENTRY_NOT_FOUND"
-	CodeKeyAlreadyExists = "KEY_ALREADY_EXISTS"
-	CodeInvalidOperation = "INVALID_OPERATION"
+	CodeEntryNotFound         = "ENTRY_NOT_FOUND"
+	CodeKeyAlreadyExists      = "KEY_ALREADY_EXISTS"
+	CodeInvalidOperation      = "INVALID_OPERATION"
+	CodeInconsistentOwnership = "INCONSISTENT_OWNERSHIP"
+	CodeEntryLimitExceeded    = "ENTRY_LIMIT_EXCEEDED"
+	CodeRequestIDAlreadyUsed  = "REQUEST_ID_ALREADY_USED"
+	CodeIdempotencyKeyInUse   = "IDEMPOTENCY_KEY_IN_USE"
+	CodeInvalidOpeningDate    = "INVALID_OPENING_DATE"
+	CodeInvalidAccount        = "INVALID_ACCOUNT"
+	CodeInvalidPhone          = "INVALID_PHONE"
+	CodeKeyTypeDisabled       = "KEY_TYPE_DISABLED"
+	CodeEntryQuotaExceeded    = "ENTRY_QUOTA_EXCEEDED"
 
 	// Auth-specific codes
 	CodeUnauthorized       = "UNAUTHORIZED"
 	CodeInvalidCredentials = "INVALID_CREDENTIALS"
 	CodeUserAlreadyExists  = "USER_ALREADY_EXISTS"
+	CodeAccountLocked      = "ACCOUNT_LOCKED"
+	CodeUserNotFound       = "USER_NOT_FOUND"
+	CodeInvalidResetToken  = "INVALID_RESET_TOKEN"
+	CodeResetTokenNotFound = "RESET_TOKEN_NOT_FOUND"
+	CodeUserDisabled       = "USER_DISABLED"
+	CodeInsufficientScope  = "INSUFFICIENT_SCOPE"
+	CodeInvalidScope       = "INVALID_SCOPE"
+
+	// OAuth token endpoint codes, as defined by RFC 6749 section 5.2
+	CodeOAuthInvalidRequest       = "invalid_request"
+	CodeOAuthInvalidClient        = "invalid_client"
+	CodeOAuthInvalidScope         = "invalid_scope"
+	CodeOAuthUnsupportedGrantType = "unsupported_grant_type"
+
+	// Request signing codes
+	CodeSignatureRequired     = "SIGNATURE_REQUIRED"
+	CodeInvalidSignature      = "INVALID_SIGNATURE"
+	CodeSignatureExpired      = "SIGNATURE_EXPIRED"
+	CodeSignatureReplayed     = "SIGNATURE_REPLAYED"
+	CodeCorrelationIDReplayed = "CORRELATION_ID_REPLAYED"
 
 	// Rate limiting codes
-	CodeTooManyRequests = "TOO_MANY_REQUESTS"
+	CodeTooManyRequests      = "TOO_MANY_REQUESTS"
+	CodeIPBanned             = "IP_BANNED"
+	CodeRequestQuotaExceeded = "REQUEST_QUOTA_EXCEEDED"
 
 	// Success codes - Entry operations
 	CodeEntryCreated = "ENTRY_CREATED"
 	CodeEntryFound   = "ENTRY_FOUND"
 	CodeEntryUpdated = "ENTRY_UPDATED"
 	CodeEntryDeleted = "ENTRY_DELETED"
+	CodeKeyValidated = "KEY_VALIDATED"
+
+	CodeFraudMarkersFound     = "FRAUD_MARKERS_FOUND"
+	CodeOwnershipHistoryFound = "OWNERSHIP_HISTORY_FOUND"
+	CodeAccountClosed         = "ACCOUNT_CLOSED"
+	CodeEntriesListed         = "ENTRIES_LISTED"
 
 	// Success codes - Auth operations
 	CodeUserRegistered = "USER_REGISTERED"
 	CodeLoginSuccess   = "LOGIN_SUCCESS"
 	CodeUserFound      = "USER_FOUND"
+
+	CodePasswordResetRequested = "PASSWORD_RESET_REQUESTED"
+	CodePasswordReset          = "PASSWORD_RESET"
+	CodePasswordChanged        = "PASSWORD_CHANGED"
+	CodeProfileUpdated         = "PROFILE_UPDATED"
+
+	// Webhook codes
+	CodeWebhookNotFound = "WEBHOOK_NOT_FOUND"
+
+	// Success codes - Webhook operations
+	CodeWebhookCreated         = "WEBHOOK_CREATED"
+	CodeWebhooksFound          = "WEBHOOKS_FOUND"
+	CodeWebhookDeleted         = "WEBHOOK_DELETED"
+	CodeWebhookDeliveriesFound = "WEBHOOK_DELIVERIES_FOUND"
+
+	// Settlement codes
+	CodeSettlementNotFound      = "SETTLEMENT_NOT_FOUND"
+	CodeSettlementAlreadyExists = "SETTLEMENT_ALREADY_EXISTS"
+
+	// Success codes - Settlement operations
+	CodeSettlementCreated = "SETTLEMENT_CREATED"
+	CodeSettlementFound   = "SETTLEMENT_FOUND"
+
+	// Reconciliation file codes
+	CodeFileNotFound = "FILE_NOT_FOUND"
+
+	// Success codes - Reconciliation file operations
+	CodeFilesFound = "FILES_FOUND"
+
+	// Admin codes
+	CodeFaultNotFound    = "FAULT_NOT_FOUND"
+	CodeBrownoutNotFound = "BROWNOUT_NOT_FOUND"
+	CodeStubNotFound     = "STUB_NOT_FOUND"
+
+	// Success codes - Admin operations
+	CodeEntriesSeeded   = "ENTRIES_SEEDED"
+	CodeFaultCreated    = "FAULT_CREATED"
+	CodeFaultsFound     = "FAULTS_FOUND"
+	CodeFaultDeleted    = "FAULT_DELETED"
+	CodeFaultsCleared   = "FAULTS_CLEARED"
+	CodeBrownoutStarted = "BROWNOUT_STARTED"
+	CodeBrownoutFound   = "BROWNOUT_FOUND"
+	CodeBrownoutStopped = "BROWNOUT_STOPPED"
+	CodeStubCreated     = "STUB_CREATED"
+	CodeStubsFound      = "STUBS_FOUND"
+	CodeStubDeleted     = "STUB_DELETED"
+	CodeStubsCleared    = "STUBS_CLEARED"
+	CodeTimeFound       = "TIME_FOUND"
+	CodeTimeAdvanced    = "TIME_ADVANCED"
+	CodeTimeReset       = "TIME_RESET"
+
+	// Account lockout codes
+	CodeAccountUnlocked = "ACCOUNT_UNLOCKED"
+	CodeResetTokenFound = "RESET_TOKEN_FOUND"
+
+	// OAuth client codes
+	CodeClientNotFound = "CLIENT_NOT_FOUND"
+	CodeClientCreated  = "CLIENT_CREATED"
+	CodeClientsFound   = "CLIENTS_FOUND"
+	CodeClientDeleted  = "CLIENT_DELETED"
+
+	// User management codes
+	CodeUsersListed       = "USERS_LISTED"
+	CodeUserStatusUpdated = "USER_STATUS_UPDATED"
+	CodeUserDeleted       = "USER_DELETED"
+
+	// Participant reset codes
+	CodeParticipantReset = "PARTICIPANT_RESET"
+
+	// Subject erasure codes
+	CodeSubjectErased = "SUBJECT_ERASED"
+
+	// Snapshot codes
+	CodeSnapshotImported = "SNAPSHOT_IMPORTED"
+
+	// Config reload codes
+	CodeConfigReloaded          = "CONFIG_RELOADED"
+	CodeConfigReloadFailed      = "CONFIG_RELOAD_FAILED"
+	CodeConfigReloadUnavailable = "CONFIG_RELOAD_UNAVAILABLE"
+
+	// Log level codes
+	CodeLogLevelFound   = "LOG_LEVEL_FOUND"
+	CodeLogLevelChanged = "LOG_LEVEL_CHANGED"
+
+	// Key policy codes
+	CodeKeyPoliciesFound   = "KEY_POLICIES_FOUND"
+	CodeKeyPoliciesChanged = "KEY_POLICIES_CHANGED"
+
+	// Quota codes
+	CodeQuotaNotFound = "QUOTA_NOT_FOUND"
+	CodeQuotasFound   = "QUOTAS_FOUND"
+	CodeQuotaFound    = "QUOTA_FOUND"
+	CodeQuotaChanged  = "QUOTA_CHANGED"
+	CodeQuotaRemoved  = "QUOTA_REMOVED"
+
+	// Admin audit codes
+	CodeAdminAuditListed = "ADMIN_AUDIT_LISTED"
+
+	// Usage report codes
+	CodeUsageReported = "USAGE_REPORTED"
 )
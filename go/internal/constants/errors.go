@@ -5,21 +5,44 @@ import "net/http"
 // APIError represents a standardized API error with code, message, and HTTP status.
 // Use these predefined errors for consistent API responses across the application.
 type APIError struct {
-	Code    string
-	Message string
-	Status  int
+	Code       string
+	Message    string
+	Status     int
+	Violations []FieldViolation
+}
+
+// FieldViolation says what is wrong with one field of a request body
+type FieldViolation struct {
+	Field   string `json:"field" example:"owner.taxIdNumber"`
+	Message string `json:"message" example:"Must be an 11-digit CPF for NATURAL_PERSON"`
+}
+
+// Error makes an APIError usable as an error, so code outside the handlers can return one
+// and httputil.WriteError still answers with it
+func (e APIError) Error() string {
+	return e.Code + ": " + e.Message
 }
 
 // WithMessage returns a copy of the APIError with a custom message.
 // Useful for validation errors or other dynamic messages.
 func (e APIError) WithMessage(message string) APIError {
 	return APIError{
-		Code:    e.Code,
-		Message: message,
-		Status:  e.Status,
+		Code:       e.Code,
+		Message:    message,
+		Status:     e.Status,
+		Violations: e.Violations,
 	}
 }
 
+// WithViolations returns a copy of the APIError listing the fields that caused it.
+func (e APIError) WithViolations(violations []FieldViolation) APIError {
+	e.Violations = violations
+	return e
+}
+
+// StatusClientClosedRequest is the non-standard status of a request the client gave up on
+const StatusClientClosedRequest = 499
+
 // Common errors - shared across multiple modules
 var (
 	ErrInvalidRequestBody = APIError{
@@ -27,6 +50,21 @@ var (
 		Message: MsgInvalidRequestBody,
 		Status:  http.StatusBadRequest,
 	}
+	ErrRouteNotFound = APIError{
+		Code:    CodeNotFound,
+		Message: MsgRouteNotFound,
+		Status:  http.StatusNotFound,
+	}
+	ErrMethodNotAllowed = APIError{
+		Code:    CodeMethodNotAllowed,
+		Message: MsgMethodNotAllowed,
+		Status:  http.StatusMethodNotAllowed,
+	}
+	ErrRequestTooLarge = APIError{
+		Code:    CodePayloadTooLarge,
+		Message: MsgRequestTooLarge,
+		Status:  http.StatusRequestEntityTooLarge,
+	}
 	ErrKeyRequired = APIError{
 		Code:    CodeInvalidRequest,
 		Message: MsgKeyRequired,
@@ -42,6 +80,27 @@ var (
 		Message: MsgInternalError,
 		Status:  http.StatusInternalServerError,
 	}
+	ErrFaultInjected = APIError{
+		Code:    CodeServiceUnavailable,
+		Message: MsgFaultInjected,
+		Status:  http.StatusServiceUnavailable,
+	}
+	ErrOpenAPIDrift = APIError{
+		Code:    CodeOpenAPIDrift,
+		Message: MsgOpenAPIDrift,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrTimeout = APIError{
+		Code:    CodeTimeout,
+		Message: MsgTimeout,
+		Status:  http.StatusGatewayTimeout,
+	}
+	// Nobody reads it; the status is the one nginx logs for requests the client abandoned
+	ErrClientClosedRequest = APIError{
+		Code:    CodeClientClosedRequest,
+		Message: MsgClientClosed,
+		Status:  StatusClientClosedRequest,
+	}
 )
 
 // Entry-related errors
@@ -91,6 +150,96 @@ var (
 		Message: MsgForbiddenParticipant,
 		Status:  http.StatusForbidden,
 	}
+	ErrInconsistentOwnership = APIError{
+		Code:    CodeInconsistentOwnership,
+		Message: MsgInconsistentOwnership,
+		Status:  http.StatusBadRequest,
+	}
+	ErrEntryLimitExceeded = APIError{
+		Code:    CodeEntryLimitExceeded,
+		Message: MsgEntryLimitExceeded,
+		Status:  http.StatusTooManyRequests,
+	}
+	ErrRequestIDAlreadyUsed = APIError{
+		Code:    CodeRequestIDAlreadyUsed,
+		Message: MsgRequestIDAlreadyUsed,
+		Status:  http.StatusConflict,
+	}
+	ErrIdempotencyKeyInUse = APIError{
+		Code:    CodeIdempotencyKeyInUse,
+		Message: MsgIdempotencyKeyInUse,
+		Status:  http.StatusConflict,
+	}
+	ErrInvalidOpeningDate = APIError{
+		Code:    CodeInvalidOpeningDate,
+		Message: MsgInvalidOpeningDate,
+		Status:  http.StatusBadRequest,
+	}
+	ErrInvalidAccount = APIError{
+		Code:    CodeInvalidAccount,
+		Message: MsgInvalidAccount,
+		Status:  http.StatusBadRequest,
+	}
+	ErrPhoneNotBrazilian = APIError{
+		Code:    CodeInvalidPhone,
+		Message: MsgPhoneNotBrazilian,
+		Status:  http.StatusBadRequest,
+	}
+	ErrKeyTypeDisabled = APIError{
+		Code:    CodeKeyTypeDisabled,
+		Message: MsgKeyTypeDisabled,
+		Status:  http.StatusUnprocessableEntity,
+	}
+	ErrEntryQuotaExceeded = APIError{
+		Code:    CodeEntryQuotaExceeded,
+		Message: MsgEntryQuotaExceeded,
+		Status:  http.StatusForbidden,
+	}
+	ErrFailedToMarkFraud = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToMarkFraud,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrFailedToReleaseKeys = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToReleaseKeys,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrFailedToFindMarkers = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToFindMarkers,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrFailedToFindOwnership = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToFindOwnership,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrAccountHasNoEntries = APIError{
+		Code:    CodeEntryNotFound,
+		Message: MsgAccountHasNoEntries,
+		Status:  http.StatusNotFound,
+	}
+	ErrFailedToCloseAccount = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToCloseAccount,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrInvalidListQuery = APIError{
+		Code:    CodeInvalidRequest,
+		Message: MsgInvalidListQuery,
+		Status:  http.StatusBadRequest,
+	}
+	ErrFailedToListEntries = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToListEntries,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrInvalidPayerID = APIError{
+		Code:    CodeInvalidRequest,
+		Message: MsgInvalidPayerID,
+		Status:  http.StatusBadRequest,
+	}
 )
 
 // Auth-related errors
@@ -145,6 +294,180 @@ var (
 		Message: MsgFailedToGenerateToken,
 		Status:  http.StatusInternalServerError,
 	}
+	ErrUserNotFound = APIError{
+		Code:    CodeUserNotFound,
+		Message: MsgUserNotFound,
+		Status:  http.StatusNotFound,
+	}
+	ErrAccountLocked = APIError{
+		Code:    CodeAccountLocked,
+		Message: MsgAccountLocked,
+		Status:  http.StatusLocked,
+	}
+	ErrFailedToUnlockAccount = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToUnlockAccount,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrInvalidCurrentPassword = APIError{
+		Code:    CodeInvalidCredentials,
+		Message: MsgInvalidCurrentPassword,
+		Status:  http.StatusUnauthorized,
+	}
+	ErrInvalidResetToken = APIError{
+		Code:    CodeInvalidResetToken,
+		Message: MsgInvalidResetToken,
+		Status:  http.StatusBadRequest,
+	}
+	ErrResetTokenNotFound = APIError{
+		Code:    CodeResetTokenNotFound,
+		Message: MsgResetTokenNotFound,
+		Status:  http.StatusNotFound,
+	}
+	ErrFailedToIssueResetToken = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToIssueResetToken,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrFailedToFindResetToken = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToFindResetToken,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrFailedToResetPassword = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToResetPassword,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrFailedToChangePassword = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToChangePassword,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrUserDisabled = APIError{
+		Code:    CodeUserDisabled,
+		Message: MsgUserDisabled,
+		Status:  http.StatusUnauthorized,
+	}
+	ErrEmptyProfileUpdate = APIError{
+		Code:    CodeInvalidRequest,
+		Message: MsgEmptyProfileUpdate,
+		Status:  http.StatusBadRequest,
+	}
+	ErrFailedToUpdateUser = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToUpdateUser,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrInvalidUserListQuery = APIError{
+		Code:    CodeInvalidRequest,
+		Message: MsgInvalidUserListQuery,
+		Status:  http.StatusBadRequest,
+	}
+	ErrFailedToListUsers = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToListUsers,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrFailedToDeleteUser = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToDeleteUser,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrInsufficientScope = APIError{
+		Code:    CodeInsufficientScope,
+		Message: MsgInsufficientScope,
+		Status:  http.StatusForbidden,
+	}
+	ErrInvalidScope = APIError{
+		Code:    CodeInvalidScope,
+		Message: MsgInvalidScope,
+		Status:  http.StatusBadRequest,
+	}
+	ErrParticipantMismatch = APIError{
+		Code:    CodeForbidden,
+		Message: MsgParticipantMismatch,
+		Status:  http.StatusForbidden,
+	}
+	ErrParticipantOverride = APIError{
+		Code:    CodeForbidden,
+		Message: MsgParticipantOverride,
+		Status:  http.StatusForbidden,
+	}
+	ErrParticipantNotAllowed = APIError{
+		Code:    CodeForbidden,
+		Message: MsgParticipantNotAllowed,
+		Status:  http.StatusForbidden,
+	}
+	ErrFailedToFindClient = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToFindClient,
+		Status:  http.StatusInternalServerError,
+	}
+)
+
+// OAuth token endpoint errors
+// Written as {"error", "error_description"} rather than the DICT envelope, so OAuth client libraries understand them.
+var (
+	ErrOAuthInvalidRequest = APIError{
+		Code:    CodeOAuthInvalidRequest,
+		Message: MsgOAuthInvalidRequest,
+		Status:  http.StatusBadRequest,
+	}
+	ErrOAuthInvalidClient = APIError{
+		Code:    CodeOAuthInvalidClient,
+		Message: MsgOAuthInvalidClient,
+		Status:  http.StatusUnauthorized,
+	}
+	ErrOAuthInvalidScope = APIError{
+		Code:    CodeOAuthInvalidScope,
+		Message: MsgOAuthInvalidScope,
+		Status:  http.StatusBadRequest,
+	}
+	ErrOAuthUnsupportedGrantType = APIError{
+		Code:    CodeOAuthUnsupportedGrantType,
+		Message: MsgOAuthUnsupportedGrantType,
+		Status:  http.StatusBadRequest,
+	}
+)
+
+// Request signing errors
+var (
+	ErrSignatureRequired = APIError{
+		Code:    CodeSignatureRequired,
+		Message: MsgSignatureRequired,
+		Status:  http.StatusUnauthorized,
+	}
+	ErrSignatureParticipant = APIError{
+		Code:    CodeInvalidSignature,
+		Message: MsgSignatureParticipant,
+		Status:  http.StatusUnauthorized,
+	}
+	ErrInvalidSignature = APIError{
+		Code:    CodeInvalidSignature,
+		Message: MsgInvalidSignature,
+		Status:  http.StatusUnauthorized,
+	}
+	ErrSignatureExpired = APIError{
+		Code:    CodeSignatureExpired,
+		Message: MsgSignatureExpired,
+		Status:  http.StatusUnauthorized,
+	}
+	ErrSignatureReplayed = APIError{
+		Code:    CodeSignatureReplayed,
+		Message: MsgSignatureReplayed,
+		Status:  http.StatusUnauthorized,
+	}
+	ErrCorrelationIDReplayed = APIError{
+		Code:    CodeCorrelationIDReplayed,
+		Message: MsgCorrelationIDReplayed,
+		Status:  http.StatusUnauthorized,
+	}
+	ErrSignatureNonceInternal = APIError{
+		Code:    CodeInternalError,
+		Message: MsgSignatureNonceInternal,
+		Status:  http.StatusInternalServerError,
+	}
 )
 
 // Rate limiting errors
@@ -159,4 +482,265 @@ var (
 		Message: MsgRateLimitInternal,
 		Status:  http.StatusInternalServerError,
 	}
+	ErrIPBanned = APIError{
+		Code:    CodeIPBanned,
+		Message: MsgIPBanned,
+		Status:  http.StatusTooManyRequests,
+	}
+	ErrRequestQuotaExceeded = APIError{
+		Code:    CodeRequestQuotaExceeded,
+		Message: MsgRequestQuotaExceeded,
+		Status:  http.StatusTooManyRequests,
+	}
+)
+
+// Webhook errors
+var (
+	ErrWebhookNotFound = APIError{
+		Code:    CodeWebhookNotFound,
+		Message: MsgWebhookNotFound,
+		Status:  http.StatusNotFound,
+	}
+	ErrParticipantRequired = APIError{
+		Code:    CodeInvalidRequest,
+		Message: MsgParticipantRequired,
+		Status:  http.StatusBadRequest,
+	}
+	ErrFailedToCreateWebhook = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToCreateWebhook,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrFailedToFindWebhooks = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToFindWebhooks,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrFailedToDeleteWebhook = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToDeleteWebhook,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrFailedToFindDeliveries = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToFindDeliveries,
+		Status:  http.StatusInternalServerError,
+	}
+)
+
+// Settlement errors
+var (
+	ErrSettlementNotFound = APIError{
+		Code:    CodeSettlementNotFound,
+		Message: MsgSettlementNotFound,
+		Status:  http.StatusNotFound,
+	}
+	ErrSettlementAlreadyExists = APIError{
+		Code:    CodeSettlementAlreadyExists,
+		Message: MsgSettlementAlreadyExists,
+		Status:  http.StatusConflict,
+	}
+	ErrPayerKeyNotFound = APIError{
+		Code:    CodeEntryNotFound,
+		Message: MsgPayerKeyNotFound,
+		Status:  http.StatusUnprocessableEntity,
+	}
+	ErrPayeeKeyNotFound = APIError{
+		Code:    CodeEntryNotFound,
+		Message: MsgPayeeKeyNotFound,
+		Status:  http.StatusUnprocessableEntity,
+	}
+	ErrEndToEndIDParticipant = APIError{
+		Code:    CodeInvalidRequest,
+		Message: MsgEndToEndIDParticipant,
+		Status:  http.StatusBadRequest,
+	}
+	ErrFailedToCreateSettlement = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToCreateSettlement,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrFailedToFindSettlement = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToFindSettlement,
+		Status:  http.StatusInternalServerError,
+	}
+)
+
+// Reconciliation file errors
+var (
+	ErrFileNotFound = APIError{
+		Code:    CodeFileNotFound,
+		Message: MsgFileNotFound,
+		Status:  http.StatusNotFound,
+	}
+	ErrInvalidFileFormat = APIError{
+		Code:    CodeInvalidRequest,
+		Message: MsgInvalidFileFormat,
+		Status:  http.StatusBadRequest,
+	}
+	ErrFailedToFindFiles = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToFindFiles,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrFailedToWriteFile = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToWriteFile,
+		Status:  http.StatusInternalServerError,
+	}
+)
+
+// Admin errors
+var (
+	ErrAdminTokenRequired = APIError{
+		Code:    CodeUnauthorized,
+		Message: MsgAdminTokenRequired,
+		Status:  http.StatusUnauthorized,
+	}
+	ErrInvalidAdminToken = APIError{
+		Code:    CodeUnauthorized,
+		Message: MsgInvalidAdminToken,
+		Status:  http.StatusUnauthorized,
+	}
+	ErrFailedToSeedEntries = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToSeedEntries,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrFaultNotFound = APIError{
+		Code:    CodeFaultNotFound,
+		Message: MsgFaultNotFound,
+		Status:  http.StatusNotFound,
+	}
+	ErrInvalidTimeAdvance = APIError{
+		Code:    CodeInvalidRequest,
+		Message: MsgInvalidTimeAdvance,
+		Status:  http.StatusBadRequest,
+	}
+	ErrInvalidBrownout = APIError{
+		Code:    CodeInvalidRequest,
+		Message: MsgInvalidBrownout,
+		Status:  http.StatusBadRequest,
+	}
+	ErrBrownoutNotFound = APIError{
+		Code:    CodeBrownoutNotFound,
+		Message: MsgBrownoutNotFound,
+		Status:  http.StatusNotFound,
+	}
+	ErrStubNotFound = APIError{
+		Code:    CodeStubNotFound,
+		Message: MsgStubNotFound,
+		Status:  http.StatusNotFound,
+	}
+	ErrClientNotFound = APIError{
+		Code:    CodeClientNotFound,
+		Message: MsgClientNotFound,
+		Status:  http.StatusNotFound,
+	}
+	ErrFailedToCreateClient = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToCreateClient,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrFailedToListClients = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToListClients,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrFailedToDeleteClient = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToDeleteClient,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrFailedToResetParticipant = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToResetParticipant,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrInvalidErasureTaxID = APIError{
+		Code:    CodeInvalidRequest,
+		Message: MsgInvalidErasureTaxID,
+		Status:  http.StatusBadRequest,
+	}
+	ErrFailedToEraseSubject = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToEraseSubject,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrInvalidSnapshotFormat = APIError{
+		Code:    CodeInvalidRequest,
+		Message: MsgInvalidSnapshotFormat,
+		Status:  http.StatusBadRequest,
+	}
+	ErrUnsupportedSnapshotVersion = APIError{
+		Code:    CodeInvalidRequest,
+		Message: MsgUnsupportedSnapshotVersion,
+		Status:  http.StatusBadRequest,
+	}
+	ErrFailedToExportSnapshot = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToExportSnapshot,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrFailedToImportSnapshot = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToImportSnapshot,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrConfigReloadFailed = APIError{
+		Code:    CodeConfigReloadFailed,
+		Message: MsgConfigReloadFailed,
+		Status:  http.StatusUnprocessableEntity,
+	}
+	ErrConfigReloadUnavailable = APIError{
+		Code:    CodeConfigReloadUnavailable,
+		Message: MsgConfigReloadUnavailable,
+		Status:  http.StatusNotImplemented,
+	}
+	ErrInvalidLogLevel = APIError{
+		Code:    CodeInvalidRequest,
+		Message: MsgInvalidLogLevel,
+		Status:  http.StatusBadRequest,
+	}
+	ErrInvalidKeyPolicies = APIError{
+		Code:    CodeInvalidRequest,
+		Message: MsgInvalidKeyPolicies,
+		Status:  http.StatusBadRequest,
+	}
+	ErrInvalidQuotaParticipant = APIError{
+		Code:    CodeInvalidRequest,
+		Message: MsgInvalidQuotaParticipant,
+		Status:  http.StatusBadRequest,
+	}
+	ErrQuotaNotFound = APIError{
+		Code:    CodeQuotaNotFound,
+		Message: MsgQuotaNotFound,
+		Status:  http.StatusNotFound,
+	}
+	ErrFailedToCountEntries = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToCountEntries,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrInvalidAdminAuditQuery = APIError{
+		Code:    CodeInvalidRequest,
+		Message: MsgInvalidAdminAuditQuery,
+		Status:  http.StatusBadRequest,
+	}
+	ErrFailedToListAdminActions = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToListAdminActions,
+		Status:  http.StatusInternalServerError,
+	}
+	ErrInvalidUsageReportQuery = APIError{
+		Code:    CodeInvalidRequest,
+		Message: MsgInvalidUsageReportQuery,
+		Status:  http.StatusBadRequest,
+	}
+	ErrFailedToReportUsage = APIError{
+		Code:    CodeInternalError,
+		Message: MsgFailedToReportUsage,
+		Status:  http.StatusInternalServerError,
+	}
 )
@@ -8,31 +8,176 @@ const (
 	MsgKeyRequired        = "Key is required"
 	MsgKeyMismatch        = "Key in path must match key in body"
 	MsgInternalError      = "An internal error occurred"
+	MsgFaultInjected      = "Fault injected by the simulator"
+	MsgOpenAPIDrift       = "Exchange does not match the OpenAPI document"
+	MsgTimeout            = "A storage operation did not complete in time"
+	MsgClientClosed       = "The client closed the request before it completed"
+
+	// Routing messages
+	MsgRouteNotFound    = "No route matches the request path"
+	MsgMethodNotAllowed = "The route does not accept this method; see the Allow header"
+
+	// Request body messages
+	MsgRequestTooLarge      = "Request body is too large"
+	MsgRequestBodyEmpty     = "Request body is empty"
+	MsgRequestBodyTruncated = "Request body ends before the JSON value is complete"
+	MsgRequestBodyTrailing  = "Request body must contain a single JSON value"
 
 	// Entry-specific messages
-	MsgEntryNotFound        = "No entry found for this key"
-	MsgKeyAlreadyExists     = "This key is already registered in the directory"
-	MsgFailedToCheckEntry   = "Failed to check existing entry"
-	MsgFailedToFindEntry    = "Failed to find entry"
-	MsgFailedToCreateEntry  = "Failed to create entry"
-	MsgFailedToUpdateEntry  = "Failed to update entry"
-	MsgFailedToDeleteEntry  = "Failed to delete entry"
-	MsgEVPKeyNotUpdatable   = "EVP keys cannot be updated"
-	MsgForbiddenParticipant = "Participant does not match the entry's participant"
+	MsgEntryNotFound         = "No entry found for this key"
+	MsgKeyAlreadyExists      = "This key is already registered in the directory"
+	MsgFailedToCheckEntry    = "Failed to check existing entry"
+	MsgFailedToFindEntry     = "Failed to find entry"
+	MsgFailedToCreateEntry   = "Failed to create entry"
+	MsgFailedToUpdateEntry   = "Failed to update entry"
+	MsgFailedToDeleteEntry   = "Failed to delete entry"
+	MsgEVPKeyNotUpdatable    = "EVP keys cannot be updated"
+	MsgForbiddenParticipant  = "Participant does not match the entry's participant"
+	MsgInconsistentOwnership = "Owner is inconsistent with the key"
+	MsgEntryLimitExceeded    = "This owner has created its daily limit of keys; try again tomorrow"
+	MsgRequestIDAlreadyUsed  = "This participant already created an entry with this requestId"
+	MsgIdempotencyKeyInUse   = "A request with this idempotency key is still being processed; retry once it completes"
+	MsgInvalidOpeningDate    = "Account opening date is in the future or before the earliest accepted date"
+	MsgInvalidAccount        = "Account branch or number is invalid"
+	MsgPhoneNotBrazilian     = "Only Brazilian (+55) phone numbers can be registered"
+	MsgKeyTypeDisabled       = "Keys of this type can't be created"
+	MsgEntryQuotaExceeded    = "This participant holds as many entries as its quota allows; delete some or ask for a larger quota"
+	MsgFailedToMarkFraud     = "Entry deleted, but failed to record the fraud marker"
+	MsgFailedToReleaseKeys   = "Entry deleted, but failed to release the account's other keys"
+	MsgFailedToFindMarkers   = "Failed to find fraud markers"
+	MsgFailedToFindOwnership = "Failed to find the key's ownership history"
+	MsgAccountHasNoEntries   = "No entry is linked to this account"
+	MsgFailedToCloseAccount  = "Failed to close account"
+	MsgInvalidListQuery      = "Invalid limit, cursor, sort, fields or since parameter"
+	MsgFailedToListEntries   = "Failed to list entries"
+	MsgInvalidPayerID        = "PI-PayerId must be a valid CPF or CNPJ"
 
 	// Auth-specific messages
-	MsgUserAlreadyExists     = "User with this email already exists"
-	MsgInvalidCredentials    = "Invalid email or password"
-	MsgUserNotFound          = "User ID not found"
-	MsgAuthHeaderRequired    = "Authorization header is required"
-	MsgInvalidToken          = "Invalid or expired token"
-	MsgInvalidTokenClaims    = "Invalid token claims"
-	MsgFailedToCheckUser     = "Failed to check existing user"
-	MsgFailedToFindUser      = "Failed to find user"
-	MsgFailedToCreateUser    = "Failed to create user"
-	MsgFailedToGenerateToken = "Failed to generate token"
+	MsgUserAlreadyExists       = "User with this email already exists"
+	MsgInvalidCredentials      = "Invalid email or password"
+	MsgUserNotFound            = "User ID not found"
+	MsgAuthHeaderRequired      = "Authorization header is required"
+	MsgInvalidToken            = "Invalid or expired token"
+	MsgInvalidTokenClaims      = "Invalid token claims"
+	MsgFailedToCheckUser       = "Failed to check existing user"
+	MsgFailedToFindUser        = "Failed to find user"
+	MsgFailedToCreateUser      = "Failed to create user"
+	MsgFailedToGenerateToken   = "Failed to generate token"
+	MsgAccountLocked           = "Too many failed logins; the account is temporarily locked"
+	MsgFailedToUnlockAccount   = "Failed to unlock account"
+	MsgInvalidCurrentPassword  = "Current password is incorrect"
+	MsgInvalidResetToken       = "Reset token is invalid, expired or already used"
+	MsgResetTokenNotFound      = "No password reset is pending for this user"
+	MsgFailedToIssueResetToken = "Failed to issue reset token"
+	MsgFailedToFindResetToken  = "Failed to find reset token"
+	MsgFailedToResetPassword   = "Failed to reset password"
+	MsgFailedToChangePassword  = "Failed to change password"
+	MsgUserDisabled            = "This account has been disabled"
+	MsgEmptyProfileUpdate      = "Provide an email or a name to update"
+	MsgFailedToUpdateUser      = "Failed to update user"
+	MsgInvalidUserListQuery    = "Invalid limit, cursor, sort or fields parameter"
+	MsgFailedToListUsers       = "Failed to list users"
+	MsgFailedToDeleteUser      = "Failed to delete user"
+	MsgInsufficientScope       = "The token was not granted the scope this operation needs"
+	MsgInvalidScope            = "A requested scope is unknown or can't be granted to users"
+	MsgParticipantMismatch     = "X-Participant-Id does not match the participant of the token"
+	MsgParticipantOverride     = "X-Participant-Id needs an OAuth client token, or X-Admin-Token in tests"
+	MsgParticipantNotAllowed   = "The token speaks for another participant than the one the request names"
+	MsgFailedToFindClient      = "Failed to find OAuth client"
+
+	// OAuth token endpoint messages
+	MsgOAuthInvalidRequest       = "grant_type is required, and client credentials must be sent once, in the Authorization header or the body"
+	MsgOAuthInvalidClient        = "Unknown client or wrong client secret"
+	MsgOAuthInvalidScope         = "A requested scope is unknown or was not granted to the client"
+	MsgOAuthUnsupportedGrantType = "Only the client_credentials grant is supported"
+
+	// OAuth client messages
+	MsgClientNotFound       = "No OAuth client found with this ID"
+	MsgFailedToCreateClient = "Failed to register OAuth client"
+	MsgFailedToListClients  = "Failed to list OAuth clients"
+	MsgFailedToDeleteClient = "Failed to delete OAuth client"
+
+	// Request signing messages
+	MsgSignatureRequired      = "X-Signature, X-Signature-Timestamp and X-Signature-Nonce headers are required"
+	MsgSignatureParticipant   = "No signing secret is configured for this participant"
+	MsgInvalidSignature       = "Signature does not match the request"
+	MsgSignatureExpired       = "Signature timestamp is outside the allowed clock skew"
+	MsgSignatureReplayed      = "Signature nonce has already been used"
+	MsgCorrelationIDReplayed  = "X-Correlation-Id has already been used by this participant recently"
+	MsgSignatureNonceInternal = "Signature nonce check failed"
 
 	// Rate limiting messages
-	MsgTooManyRequests   = "Rate limit exceeded. Please try again later."
-	MsgRateLimitInternal = "Rate limit check failed"
+	MsgTooManyRequests      = "Rate limit exceeded. Please try again later."
+	MsgRateLimitInternal    = "Rate limit check failed"
+	MsgIPBanned             = "Too many requests from this address; it is temporarily blocked"
+	MsgRequestQuotaExceeded = "This participant has sent as many requests this minute as its quota allows"
+
+	// Webhook messages
+	MsgWebhookNotFound        = "No webhook found with this ID"
+	MsgParticipantRequired    = "Participant query parameter is required"
+	MsgFailedToCreateWebhook  = "Failed to create webhook"
+	MsgFailedToFindWebhooks   = "Failed to find webhooks"
+	MsgFailedToDeleteWebhook  = "Failed to delete webhook"
+	MsgFailedToFindDeliveries = "Failed to find webhook deliveries"
+
+	// Settlement messages
+	MsgSettlementNotFound       = "No settlement found with this end-to-end ID"
+	MsgSettlementAlreadyExists  = "A settlement with this end-to-end ID already exists"
+	MsgPayerKeyNotFound         = "Payer key is not registered in the directory"
+	MsgPayeeKeyNotFound         = "Payee key is not registered in the directory"
+	MsgEndToEndIDParticipant    = "End-to-end ID must start with the payer participant's ISPB"
+	MsgFailedToCreateSettlement = "Failed to create settlement"
+	MsgFailedToFindSettlement   = "Failed to find settlement"
+
+	// Reconciliation file messages
+	MsgFileNotFound      = "No reconciliation file found with this ID"
+	MsgInvalidFileFormat = "Format must be csv or xml"
+	MsgFailedToFindFiles = "Failed to find reconciliation files"
+	MsgFailedToWriteFile = "Failed to write reconciliation file"
+
+	// Admin messages
+	MsgAdminTokenRequired  = "X-Admin-Token header is required"
+	MsgInvalidAdminToken   = "Invalid admin token"
+	MsgFailedToSeedEntries = "Failed to seed entries"
+	MsgFaultNotFound       = "No fault found with this ID"
+	MsgInvalidTimeAdvance  = "Duration must be a positive Go duration such as 168h"
+	MsgInvalidBrownout     = "rampUp, hold and rampDown must be Go durations such as 30s, not negative"
+	MsgBrownoutNotFound    = "No brownout has been started"
+	MsgStubNotFound        = "No stub found with this ID"
+
+	// Participant reset messages
+	MsgFailedToResetParticipant = "Failed to reset the participant's data"
+
+	// Subject erasure messages
+	MsgInvalidErasureTaxID  = "taxId must be a valid CPF or CNPJ"
+	MsgFailedToEraseSubject = "Failed to erase the subject's data"
+
+	// Snapshot messages
+	MsgInvalidSnapshotFormat      = "Format must be json or ndjson"
+	MsgUnsupportedSnapshotVersion = "Unsupported snapshot version; this simulator reads version 1"
+	MsgFailedToExportSnapshot     = "Failed to export the directory"
+	MsgFailedToImportSnapshot     = "Failed to import the snapshot"
+
+	// Config reload messages
+	MsgConfigReloadFailed      = "Configuration is invalid; the previous settings remain in effect"
+	MsgConfigReloadUnavailable = "This simulator has no configuration to reload"
+
+	// Log level messages
+	MsgInvalidLogLevel = "Level must be debug, info, warn or error"
+
+	// Key policy messages
+	MsgInvalidKeyPolicies = "disabledKeyTypes must only list CPF, CNPJ, EMAIL, PHONE or EVP"
+
+	// Quota messages
+	MsgInvalidQuotaParticipant = "Participant must be an 8-digit ISPB"
+	MsgQuotaNotFound           = "This participant has no quota of its own"
+	MsgFailedToCountEntries    = "Failed to count the participant's entries"
+
+	// Admin audit messages
+	MsgInvalidAdminAuditQuery   = "Invalid limit, cursor, sort or fields parameter"
+	MsgFailedToListAdminActions = "Failed to list admin actions"
+
+	// Usage report messages
+	MsgInvalidUsageReportQuery = "Invalid from or to parameter; days are YYYY-MM-DD and from may not follow to"
+	MsgFailedToReportUsage     = "Failed to report usage"
 )
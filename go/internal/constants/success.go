@@ -27,6 +27,26 @@ var (
 		Code:   CodeEntryDeleted,
 		Status: http.StatusOK,
 	}
+	SuccessKeyValidated = APISuccess{
+		Code:   CodeKeyValidated,
+		Status: http.StatusOK,
+	}
+	SuccessFraudMarkersFound = APISuccess{
+		Code:   CodeFraudMarkersFound,
+		Status: http.StatusOK,
+	}
+	SuccessOwnershipHistoryFound = APISuccess{
+		Code:   CodeOwnershipHistoryFound,
+		Status: http.StatusOK,
+	}
+	SuccessAccountClosed = APISuccess{
+		Code:   CodeAccountClosed,
+		Status: http.StatusOK,
+	}
+	SuccessEntriesListed = APISuccess{
+		Code:   CodeEntriesListed,
+		Status: http.StatusOK,
+	}
 )
 
 // Auth-related success responses
@@ -43,4 +63,212 @@ var (
 		Code:   CodeUserFound,
 		Status: http.StatusOK,
 	}
+	SuccessPasswordResetRequested = APISuccess{
+		Code:   CodePasswordResetRequested,
+		Status: http.StatusAccepted,
+	}
+	SuccessPasswordReset = APISuccess{
+		Code:   CodePasswordReset,
+		Status: http.StatusOK,
+	}
+	SuccessPasswordChanged = APISuccess{
+		Code:   CodePasswordChanged,
+		Status: http.StatusOK,
+	}
+	SuccessProfileUpdated = APISuccess{
+		Code:   CodeProfileUpdated,
+		Status: http.StatusOK,
+	}
+)
+
+// Webhook success responses
+var (
+	SuccessWebhookCreated = APISuccess{
+		Code:   CodeWebhookCreated,
+		Status: http.StatusCreated,
+	}
+	SuccessWebhooksFound = APISuccess{
+		Code:   CodeWebhooksFound,
+		Status: http.StatusOK,
+	}
+	SuccessWebhookDeleted = APISuccess{
+		Code:   CodeWebhookDeleted,
+		Status: http.StatusOK,
+	}
+	SuccessWebhookDeliveriesFound = APISuccess{
+		Code:   CodeWebhookDeliveriesFound,
+		Status: http.StatusOK,
+	}
+)
+
+// Settlement success responses
+var (
+	SuccessSettlementCreated = APISuccess{
+		Code:   CodeSettlementCreated,
+		Status: http.StatusCreated,
+	}
+	SuccessSettlementFound = APISuccess{
+		Code:   CodeSettlementFound,
+		Status: http.StatusOK,
+	}
+)
+
+// Reconciliation file success responses
+var (
+	SuccessFilesFound = APISuccess{
+		Code:   CodeFilesFound,
+		Status: http.StatusOK,
+	}
+)
+
+// Admin success responses
+var (
+	SuccessEntriesSeeded = APISuccess{
+		Code:   CodeEntriesSeeded,
+		Status: http.StatusCreated,
+	}
+	SuccessFaultCreated = APISuccess{
+		Code:   CodeFaultCreated,
+		Status: http.StatusCreated,
+	}
+	SuccessFaultsFound = APISuccess{
+		Code:   CodeFaultsFound,
+		Status: http.StatusOK,
+	}
+	SuccessFaultDeleted = APISuccess{
+		Code:   CodeFaultDeleted,
+		Status: http.StatusOK,
+	}
+	SuccessFaultsCleared = APISuccess{
+		Code:   CodeFaultsCleared,
+		Status: http.StatusOK,
+	}
+	SuccessBrownoutStarted = APISuccess{
+		Code:   CodeBrownoutStarted,
+		Status: http.StatusOK,
+	}
+	SuccessBrownoutFound = APISuccess{
+		Code:   CodeBrownoutFound,
+		Status: http.StatusOK,
+	}
+	SuccessBrownoutStopped = APISuccess{
+		Code:   CodeBrownoutStopped,
+		Status: http.StatusOK,
+	}
+	SuccessStubCreated = APISuccess{
+		Code:   CodeStubCreated,
+		Status: http.StatusCreated,
+	}
+	SuccessStubsFound = APISuccess{
+		Code:   CodeStubsFound,
+		Status: http.StatusOK,
+	}
+	SuccessStubDeleted = APISuccess{
+		Code:   CodeStubDeleted,
+		Status: http.StatusOK,
+	}
+	SuccessStubsCleared = APISuccess{
+		Code:   CodeStubsCleared,
+		Status: http.StatusOK,
+	}
+	SuccessTimeFound = APISuccess{
+		Code:   CodeTimeFound,
+		Status: http.StatusOK,
+	}
+	SuccessTimeAdvanced = APISuccess{
+		Code:   CodeTimeAdvanced,
+		Status: http.StatusOK,
+	}
+	SuccessTimeReset = APISuccess{
+		Code:   CodeTimeReset,
+		Status: http.StatusOK,
+	}
+	SuccessAccountUnlocked = APISuccess{
+		Code:   CodeAccountUnlocked,
+		Status: http.StatusOK,
+	}
+	SuccessResetTokenFound = APISuccess{
+		Code:   CodeResetTokenFound,
+		Status: http.StatusOK,
+	}
+	SuccessClientCreated = APISuccess{
+		Code:   CodeClientCreated,
+		Status: http.StatusCreated,
+	}
+	SuccessClientsFound = APISuccess{
+		Code:   CodeClientsFound,
+		Status: http.StatusOK,
+	}
+	SuccessClientDeleted = APISuccess{
+		Code:   CodeClientDeleted,
+		Status: http.StatusOK,
+	}
+	SuccessUsersListed = APISuccess{
+		Code:   CodeUsersListed,
+		Status: http.StatusOK,
+	}
+	SuccessUserStatusUpdated = APISuccess{
+		Code:   CodeUserStatusUpdated,
+		Status: http.StatusOK,
+	}
+	SuccessUserDeleted = APISuccess{
+		Code:   CodeUserDeleted,
+		Status: http.StatusOK,
+	}
+	SuccessParticipantReset = APISuccess{
+		Code:   CodeParticipantReset,
+		Status: http.StatusOK,
+	}
+	SuccessSubjectErased = APISuccess{
+		Code:   CodeSubjectErased,
+		Status: http.StatusOK,
+	}
+	SuccessSnapshotImported = APISuccess{
+		Code:   CodeSnapshotImported,
+		Status: http.StatusCreated,
+	}
+	SuccessConfigReloaded = APISuccess{
+		Code:   CodeConfigReloaded,
+		Status: http.StatusOK,
+	}
+	SuccessLogLevelFound = APISuccess{
+		Code:   CodeLogLevelFound,
+		Status: http.StatusOK,
+	}
+	SuccessLogLevelChanged = APISuccess{
+		Code:   CodeLogLevelChanged,
+		Status: http.StatusOK,
+	}
+	SuccessKeyPoliciesFound = APISuccess{
+		Code:   CodeKeyPoliciesFound,
+		Status: http.StatusOK,
+	}
+	SuccessKeyPoliciesChanged = APISuccess{
+		Code:   CodeKeyPoliciesChanged,
+		Status: http.StatusOK,
+	}
+	SuccessQuotasFound = APISuccess{
+		Code:   CodeQuotasFound,
+		Status: http.StatusOK,
+	}
+	SuccessQuotaFound = APISuccess{
+		Code:   CodeQuotaFound,
+		Status: http.StatusOK,
+	}
+	SuccessQuotaChanged = APISuccess{
+		Code:   CodeQuotaChanged,
+		Status: http.StatusOK,
+	}
+	SuccessQuotaRemoved = APISuccess{
+		Code:   CodeQuotaRemoved,
+		Status: http.StatusOK,
+	}
+	SuccessAdminAuditListed = APISuccess{
+		Code:   CodeAdminAuditListed,
+		Status: http.StatusOK,
+	}
+	SuccessUsageReported = APISuccess{
+		Code:   CodeUsageReported,
+		Status: http.StatusOK,
+	}
 )
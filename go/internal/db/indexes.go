@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// IndexSpec is the indexes a repository declares on its collection
+type IndexSpec struct {
+	Collection string
+	Indexes    []mongo.IndexModel
+}
+
+// IndexReport compares a collection's indexes with those declared for it
+type IndexReport struct {
+	Collection string
+	Missing    []string      // declared but not built, so the queries they serve scan the collection
+	Undeclared []string      // built but no longer declared, e.g. superseded by a compound index
+	Unused     []UnusedIndex // declared and built, but no query has used them
+}
+
+// UnusedIndex is an index no query has used since the server started counting
+type UnusedIndex struct {
+	Name  string
+	Since time.Time // the server's startup or the index's build, whichever came last
+}
+
+// indexUsage is what $indexStats reports for one index
+type indexUsage struct {
+	Name     string `bson:"name"`
+	Accesses struct {
+		Ops   int64     `bson:"ops"`
+		Since time.Time `bson:"since"`
+	} `bson:"accesses"`
+}
+
+// CheckIndexes compares the indexes of spec's collection with those it declares.
+// Usage comes from $indexStats, which counts from the server's startup and needs the indexStats
+// privilege; when it can't be read, the report has no unused indexes. Unique and TTL indexes
+// work without being queried, so they are never reported unused.
+func (m *Mongo) CheckIndexes(ctx context.Context, spec IndexSpec) (IndexReport, error) {
+	collection := m.Collection(spec.Collection)
+
+	built, err := collection.Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return IndexReport{}, fmt.Errorf("list %s indexes: %w", spec.Collection, err)
+	}
+
+	var usage []indexUsage
+	cursor, err := collection.Aggregate(ctx, mongo.Pipeline{{{Key: "$indexStats", Value: bson.M{}}}})
+	if err == nil {
+		if err := cursor.All(ctx, &usage); err != nil {
+			usage = nil
+		}
+	}
+
+	return compareIndexes(spec, built, usage), nil
+}
+
+// compareIndexes builds the report of spec's collection from its built indexes and their usage
+func compareIndexes(spec IndexSpec, built []*mongo.IndexSpecification, usage []indexUsage) IndexReport {
+	report := IndexReport{Collection: spec.Collection}
+
+	declared := make(map[string]bool, len(spec.Indexes))
+	for _, model := range spec.Indexes {
+		declared[IndexName(model)] = true
+	}
+
+	exists := make(map[string]bool, len(built))
+	working := map[string]bool{}
+	for _, index := range built {
+		exists[index.Name] = true
+		if index.Name == "_id_" {
+			continue
+		}
+		if (index.Unique != nil && *index.Unique) || index.ExpireAfterSeconds != nil {
+			working[index.Name] = true
+		}
+		if !declared[index.Name] {
+			report.Undeclared = append(report.Undeclared, index.Name)
+		}
+	}
+
+	for _, model := range spec.Indexes {
+		if name := IndexName(model); !exists[name] {
+			report.Missing = append(report.Missing, name)
+		}
+	}
+
+	for _, u := range usage {
+		if u.Accesses.Ops == 0 && declared[u.Name] && !working[u.Name] {
+			report.Unused = append(report.Unused, UnusedIndex{Name: u.Name, Since: u.Accesses.Since})
+		}
+	}
+
+	slices.Sort(report.Undeclared)
+	slices.SortFunc(report.Unused, func(a, b UnusedIndex) int { return strings.Compare(a.Name, b.Name) })
+	return report
+}
+
+// IndexName returns the name MongoDB gives model: its own, or its fields and directions
+// joined by underscores, e.g. account.participant_1_normalizedKey_1
+func IndexName(model mongo.IndexModel) string {
+	if model.Options != nil && model.Options.Name != nil {
+		return *model.Options.Name
+	}
+
+	keys, ok := model.Keys.(bson.D)
+	if !ok {
+		return ""
+	}
+	parts := make([]string, 0, 2*len(keys))
+	for _, key := range keys {
+		parts = append(parts, key.Key, fmt.Sprint(key.Value))
+	}
+	return strings.Join(parts, "_")
+}
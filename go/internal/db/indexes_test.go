@@ -0,0 +1,76 @@
+package db
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestIndexName(t *testing.T) {
+	tests := []struct {
+		model mongo.IndexModel
+		want  string
+	}{
+		{mongo.IndexModel{Keys: bson.D{{Key: "owner.taxIdNumber", Value: 1}}}, "owner.taxIdNumber_1"},
+		{mongo.IndexModel{Keys: bson.D{{Key: "webhookId", Value: 1}, {Key: "createdAt", Value: -1}}}, "webhookId_1_createdAt_-1"},
+		{mongo.IndexModel{Keys: bson.D{{Key: "name", Value: "text"}}}, "name_text"},
+		{mongo.IndexModel{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetName("by_key")}, "by_key"},
+	}
+
+	for _, tt := range tests {
+		if got := IndexName(tt.model); got != tt.want {
+			t.Errorf("IndexName(%v) = %q, want %q", tt.model.Keys, got, tt.want)
+		}
+	}
+}
+
+func TestCompareIndexes(t *testing.T) {
+	spec := IndexSpec{
+		Collection: "entries",
+		Indexes: []mongo.IndexModel{
+			{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "account.participant", Value: 1}, {Key: "keyType", Value: 1}}},
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "updatedAt", Value: 1}}},
+			{Keys: bson.D{{Key: "createdAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(60)},
+		},
+	}
+	unique, ttl := true, int32(60)
+	built := []*mongo.IndexSpecification{
+		{Name: "_id_"},
+		{Name: "key_1", Unique: &unique},
+		{Name: "account.participant_1_keyType_1"},
+		{Name: "createdAt_1", ExpireAfterSeconds: &ttl},
+		{Name: "owner.taxIdNumber_1"},
+	}
+	since := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	usage := make([]indexUsage, len(built))
+	for i, index := range built {
+		usage[i].Name = index.Name
+		usage[i].Accesses.Since = since
+	}
+
+	report := compareIndexes(spec, built, usage)
+
+	if report.Collection != "entries" {
+		t.Errorf("Collection = %q, want entries", report.Collection)
+	}
+	if want := []string{"status_1_updatedAt_1"}; !slices.Equal(report.Missing, want) {
+		t.Errorf("Missing = %v, want %v", report.Missing, want)
+	}
+	if want := []string{"owner.taxIdNumber_1"}; !slices.Equal(report.Undeclared, want) {
+		t.Errorf("Undeclared = %v, want %v", report.Undeclared, want)
+	}
+	// Unique and TTL indexes work without queries, and undeclared ones are already reported
+	if want := []UnusedIndex{{Name: "account.participant_1_keyType_1", Since: since}}; !slices.Equal(report.Unused, want) {
+		t.Errorf("Unused = %v, want %v", report.Unused, want)
+	}
+
+	// Without usage statistics nothing is reported unused
+	if report := compareIndexes(spec, built, nil); len(report.Unused) != 0 {
+		t.Errorf("Unused without statistics = %v, want none", report.Unused)
+	}
+}
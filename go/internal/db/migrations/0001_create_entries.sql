@@ -0,0 +1,13 @@
+-- Account and owner are stored as JSON documents, mirroring the MongoDB entries collection
+CREATE TABLE entries (
+    id                 TEXT PRIMARY KEY,
+    key                TEXT NOT NULL UNIQUE,
+    key_type           TEXT NOT NULL,
+    account            JSONB NOT NULL,
+    owner              JSONB NOT NULL,
+    created_at         TIMESTAMPTZ NOT NULL,
+    updated_at         TIMESTAMPTZ NOT NULL,
+    key_ownership_date TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX entries_owner_tax_id_number_idx ON entries ((owner ->> 'taxIdNumber'));
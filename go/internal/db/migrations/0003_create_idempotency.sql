@@ -0,0 +1,9 @@
+-- There is no TTL index: expired records are ignored on lookup and replaced when the key is claimed again
+CREATE TABLE idempotency (
+    key         TEXT PRIMARY KEY,
+    response    TEXT NOT NULL DEFAULT '',
+    status_code INTEGER NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idempotency_created_at_idx ON idempotency (created_at);
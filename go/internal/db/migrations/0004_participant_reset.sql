@@ -0,0 +1,6 @@
+-- Lets POST /admin/reset find a participant's entries and idempotency records
+-- Records claimed before this migration have no participant and are left to expire
+ALTER TABLE idempotency ADD COLUMN participant TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idempotency_participant_idx ON idempotency (participant);
+CREATE INDEX entries_participant_idx ON entries ((account ->> 'participant'));
@@ -0,0 +1,7 @@
+-- Lookups match the normalized key (trimmed, lowercase emails, phones without formatting)
+-- Keys were validated in canonical form before, so existing rows start with their raw key
+ALTER TABLE entries ADD COLUMN normalized_key TEXT;
+UPDATE entries SET normalized_key = key;
+ALTER TABLE entries ALTER COLUMN normalized_key SET NOT NULL;
+
+CREATE UNIQUE INDEX entries_normalized_key_idx ON entries (normalized_key);
@@ -0,0 +1,4 @@
+-- Lets GET /participants/{ispb}/entries page through a participant's entries in key order
+-- The new index also serves everything the participant-only index did
+CREATE INDEX entries_participant_key_idx ON entries ((account ->> 'participant'), normalized_key);
+DROP INDEX entries_participant_idx;
@@ -0,0 +1,5 @@
+-- Records carry their own expiry, so error responses can be replayed for less than a day
+-- Existing records keep the 24 hours they were stored for
+ALTER TABLE idempotency ADD COLUMN expires_at TIMESTAMPTZ;
+UPDATE idempotency SET expires_at = created_at + INTERVAL '24 hours';
+ALTER TABLE idempotency ALTER COLUMN expires_at SET NOT NULL;
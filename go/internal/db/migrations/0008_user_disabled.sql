@@ -0,0 +1,2 @@
+-- Lets admins disable a user: their logins and tokens are refused until re-enabled
+ALTER TABLE users ADD COLUMN disabled BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,3 @@
+-- Keys flagged by the Receita Federal review wait in PENDING_RFB_VALIDATION until validated
+ALTER TABLE entries ADD COLUMN status TEXT NOT NULL DEFAULT 'ACTIVE';
+ALTER TABLE entries ADD COLUMN rfb_reviewed_at TIMESTAMPTZ;
@@ -0,0 +1,2 @@
+-- The retention job deletes records some time after they expire
+CREATE INDEX idempotency_expires_at_idx ON idempotency (expires_at);
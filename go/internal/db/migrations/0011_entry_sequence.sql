@@ -0,0 +1,2 @@
+-- Each change to an entry increments its sequence, so its events can be ordered; older rows start at 0
+ALTER TABLE entries ADD COLUMN sequence BIGINT NOT NULL DEFAULT 0;
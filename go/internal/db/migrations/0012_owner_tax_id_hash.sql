@@ -0,0 +1,3 @@
+-- Owners sealed at rest (PII_ENCRYPTION_KEYS) are found by the blind index of their tax ID
+CREATE INDEX entries_owner_tax_id_hash_idx ON entries ((owner ->> 'taxIdHash'))
+    WHERE owner ->> 'taxIdHash' IS NOT NULL;
@@ -2,11 +2,15 @@ package db
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/dict-simulator/go/internal/logger"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 	"go.uber.org/zap"
 )
@@ -16,14 +20,71 @@ type Mongo struct {
 	Database *mongo.Database
 }
 
-func ConnectMongo(uri string) (*Mongo, error) {
+// ReadPreferences are the read preference modes MongoOptions accepts
+var ReadPreferences = []string{"primary", "primaryPreferred", "secondary", "secondaryPreferred", "nearest"}
+
+// MongoOptions tunes the MongoDB client. Zero fields keep what the URI sets, else the driver's default.
+type MongoOptions struct {
+	MaxPoolSize    uint64        // connections per server; the driver's default is 100
+	MinPoolSize    uint64        // connections kept open per server even when idle
+	ReadPreference string        // one of ReadPreferences; reads may then lag behind writes
+	WriteConcern   string        // "majority", or how many members must acknowledge a write
+	SocketTimeout  time.Duration // how long a socket read or write may take
+	ConnectTimeout time.Duration // how long opening a connection may take
+}
+
+// apply sets the tuned options on opts, after the URI's so they take precedence
+func (o MongoOptions) apply(opts *options.ClientOptions) error {
+	if o.MaxPoolSize > 0 {
+		opts.SetMaxPoolSize(o.MaxPoolSize)
+	}
+	if o.MinPoolSize > 0 {
+		opts.SetMinPoolSize(o.MinPoolSize)
+	}
+	if o.ReadPreference != "" {
+		mode, err := readpref.ModeFromString(o.ReadPreference)
+		if err != nil {
+			return err
+		}
+		rp, err := readpref.New(mode)
+		if err != nil {
+			return err
+		}
+		opts.SetReadPreference(rp)
+	}
+	switch o.WriteConcern {
+	case "":
+	case "majority":
+		opts.SetWriteConcern(writeconcern.Majority())
+	default:
+		// Unacknowledged writes (0) would hide duplicate keys and can't run in transactions
+		w, err := strconv.Atoi(o.WriteConcern)
+		if err != nil || w < 1 {
+			return fmt.Errorf("write concern must be majority or a number of members, got %q", o.WriteConcern)
+		}
+		opts.SetWriteConcern(&writeconcern.WriteConcern{W: w})
+	}
+	if o.SocketTimeout > 0 {
+		opts.SetSocketTimeout(o.SocketTimeout)
+	}
+	if o.ConnectTimeout > 0 {
+		opts.SetConnectTimeout(o.ConnectTimeout)
+	}
+	return nil
+}
+
+func ConnectMongo(uri string, tuning MongoOptions) (*Mongo, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Add OpenTelemetry instrumentation monitor
+	// Add OpenTelemetry instrumentation monitor, and the pool metrics
 	clientOptions := options.Client().
 		ApplyURI(uri).
-		SetMonitor(otelmongo.NewMonitor())
+		SetMonitor(otelmongo.NewMonitor()).
+		SetPoolMonitor(poolMonitor())
+	if err := tuning.apply(clientOptions); err != nil {
+		return nil, err
+	}
 
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
@@ -66,3 +127,25 @@ func (m *Mongo) WithDatabase(name string) *Mongo {
 		Database: m.Client.Database(name),
 	}
 }
+
+// WithTransaction runs fn in a multi-document transaction, committing when it returns nil and
+// aborting otherwise. Operations join the transaction only when they are given fn's ctx.
+// Transient errors and unknown commit results are retried by the driver, so fn may run more than once.
+// Requires a replica set (or sharded cluster). outbox.EntryRepository writes each entry change
+// and its event in one (EVENT_SOURCE=outbox).
+func (m *Mongo) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := m.Client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	// Transactions read from the primary whatever MONGODB_READ_PREFERENCE says
+	opts := options.Transaction().
+		SetReadPreference(readpref.Primary()).
+		SetWriteConcern(writeconcern.Majority())
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (any, error) {
+		return nil, fn(sc)
+	}, opts)
+	return err
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+var (
+	mongoPoolConnections = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mongodb_pool_connections",
+			Help: "Open MongoDB connections by server address",
+		},
+		[]string{"address"},
+	)
+	mongoPoolConnectionsInUse = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mongodb_pool_connections_in_use",
+			Help: "MongoDB connections checked out of the pool by server address",
+		},
+		[]string{"address"},
+	)
+	mongoPoolCheckouts = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mongodb_pool_checkouts_total",
+			Help: "Total number of MongoDB connection checkouts by result (ok, timeout, connection_error, pool_closed, error)",
+		},
+		[]string{"result"},
+	)
+	mongoPoolCheckoutDuration = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "mongodb_pool_checkout_duration_seconds",
+			Help:    "Time spent waiting for a MongoDB connection in seconds, whether or not one was checked out",
+			Buckets: []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 5},
+		},
+	)
+	mongoPoolCleared = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mongodb_pool_cleared_total",
+			Help: "Total number of times a MongoDB server's pool was cleared after an error, by server address",
+		},
+		[]string{"address"},
+	)
+)
+
+// checkoutResults names the checkout failure reasons as metric labels
+var checkoutResults = map[string]string{
+	event.ReasonTimedOut:          "timeout",
+	event.ReasonConnectionErrored: "connection_error",
+	event.ReasonPoolClosed:        "pool_closed",
+}
+
+// poolMonitor records the driver's connection pool events as Prometheus metrics, so a load test
+// shows whether requests wait on the pool (raise MONGODB_MAX_POOL_SIZE) or on the server
+func poolMonitor() *event.PoolMonitor {
+	return &event.PoolMonitor{Event: recordPoolEvent}
+}
+
+func recordPoolEvent(e *event.PoolEvent) {
+	switch e.Type {
+	case event.ConnectionCreated:
+		mongoPoolConnections.WithLabelValues(e.Address).Inc()
+	case event.ConnectionClosed:
+		mongoPoolConnections.WithLabelValues(e.Address).Dec()
+	case event.GetSucceeded:
+		mongoPoolConnectionsInUse.WithLabelValues(e.Address).Inc()
+		mongoPoolCheckouts.WithLabelValues("ok").Inc()
+		mongoPoolCheckoutDuration.Observe(e.Duration.Seconds())
+	case event.GetFailed:
+		result, ok := checkoutResults[e.Reason]
+		if !ok {
+			result = "error"
+		}
+		mongoPoolCheckouts.WithLabelValues(result).Inc()
+		mongoPoolCheckoutDuration.Observe(e.Duration.Seconds())
+	case event.ConnectionReturned:
+		mongoPoolConnectionsInUse.WithLabelValues(e.Address).Dec()
+	case event.PoolCleared:
+		mongoPoolCleared.WithLabelValues(e.Address).Inc()
+	}
+}
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+func TestMongoOptionsApply(t *testing.T) {
+	opts := options.Client().ApplyURI("mongodb://localhost:27017/dict?maxPoolSize=50&readPreference=primary")
+	tuning := MongoOptions{
+		MinPoolSize:    10,
+		ReadPreference: "secondaryPreferred",
+		WriteConcern:   "2",
+		SocketTimeout:  5 * time.Second,
+	}
+	if err := tuning.apply(opts); err != nil {
+		t.Fatalf("apply() error = %v", err)
+	}
+
+	// Zero fields keep the URI's settings
+	if opts.MaxPoolSize == nil || *opts.MaxPoolSize != 50 {
+		t.Errorf("MaxPoolSize = %v, want the URI's 50", opts.MaxPoolSize)
+	}
+	if opts.MinPoolSize == nil || *opts.MinPoolSize != 10 {
+		t.Errorf("MinPoolSize = %v, want 10", opts.MinPoolSize)
+	}
+	if opts.ReadPreference == nil || opts.ReadPreference.Mode() != readpref.SecondaryPreferredMode {
+		t.Errorf("ReadPreference = %v, want secondaryPreferred over the URI's", opts.ReadPreference)
+	}
+	if opts.WriteConcern == nil || opts.WriteConcern.W != 2 {
+		t.Errorf("WriteConcern = %+v, want w=2", opts.WriteConcern)
+	}
+	if opts.SocketTimeout == nil || *opts.SocketTimeout != 5*time.Second {
+		t.Errorf("SocketTimeout = %v, want 5s", opts.SocketTimeout)
+	}
+	if opts.ConnectTimeout != nil {
+		t.Errorf("ConnectTimeout = %v, want the driver's default", *opts.ConnectTimeout)
+	}
+
+	for _, wc := range []string{"0", "all"} {
+		if err := (MongoOptions{WriteConcern: wc}).apply(options.Client()); err == nil {
+			t.Errorf("apply() accepted write concern %q", wc)
+		}
+	}
+}
+
+func TestPoolMonitorRecordsMetrics(t *testing.T) {
+	const address = "pool-test:27017"
+	checkouts := testutil.ToFloat64(mongoPoolCheckouts.WithLabelValues("ok"))
+	timeouts := testutil.ToFloat64(mongoPoolCheckouts.WithLabelValues("timeout"))
+
+	monitor := poolMonitor()
+	for _, e := range []event.PoolEvent{
+		{Type: event.ConnectionCreated, Address: address},
+		{Type: event.ConnectionCreated, Address: address},
+		{Type: event.GetSucceeded, Address: address, Duration: time.Millisecond},
+		{Type: event.GetSucceeded, Address: address, Duration: time.Millisecond},
+		{Type: event.ConnectionReturned, Address: address},
+		{Type: event.GetFailed, Address: address, Reason: event.ReasonTimedOut, Duration: time.Second},
+		{Type: event.ConnectionClosed, Address: address},
+		{Type: event.PoolCleared, Address: address},
+	} {
+		monitor.Event(&e)
+	}
+
+	if got := testutil.ToFloat64(mongoPoolConnections.WithLabelValues(address)); got != 1 {
+		t.Errorf("open connections = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(mongoPoolConnectionsInUse.WithLabelValues(address)); got != 1 {
+		t.Errorf("connections in use = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(mongoPoolCheckouts.WithLabelValues("ok")) - checkouts; got != 2 {
+		t.Errorf("successful checkouts = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(mongoPoolCheckouts.WithLabelValues("timeout")) - timeouts; got != 1 {
+		t.Errorf("timed out checkouts = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(mongoPoolCleared.WithLabelValues(address)); got != 1 {
+		t.Errorf("pool clears = %v, want 1", got)
+	}
+}
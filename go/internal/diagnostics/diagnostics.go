@@ -0,0 +1,47 @@
+package diagnostics
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+var publishOnce sync.Once
+
+// runtimeStats is published as the "runtime" expvar, next to the standard memstats and cmdline
+func runtimeStats() any {
+	var gc debug.GCStats
+	debug.ReadGCStats(&gc)
+
+	return map[string]any{
+		"goroutines": runtime.NumGoroutine(),
+		"gomaxprocs": runtime.GOMAXPROCS(0),
+		"gc": map[string]any{
+			"count":       gc.NumGC,
+			"pause_total": gc.PauseTotal.String(),
+			"last":        gc.LastGC.UTC().Format(time.RFC3339Nano),
+		},
+	}
+}
+
+// Handler serves the net/http/pprof profiles under /debug/pprof/ and the expvars under /debug/vars
+// Neither is authenticated, so it belongs on a listener only operators can reach (DIAGNOSTICS_ADDR).
+func Handler() http.Handler {
+	publishOnce.Do(func() {
+		expvar.Publish("runtime", expvar.Func(runtimeStats))
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /debug/pprof/", pprof.Index)
+	mux.HandleFunc("GET /debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("POST /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
+	mux.Handle("GET /debug/vars", expvar.Handler())
+	return mux
+}
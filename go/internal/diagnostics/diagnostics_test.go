@@ -0,0 +1,49 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	srv := httptest.NewServer(Handler())
+	defer srv.Close()
+
+	for _, path := range []string{"/debug/pprof/", "/debug/pprof/heap", "/debug/pprof/goroutine?debug=1", "/debug/pprof/cmdline"} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s = %d, want 200", path, resp.StatusCode)
+		}
+	}
+
+	resp, err := http.Get(srv.URL + "/debug/vars")
+	if err != nil {
+		t.Fatalf("GET /debug/vars: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var vars struct {
+		Memstats map[string]any `json:"memstats"`
+		Runtime  struct {
+			Goroutines int `json:"goroutines"`
+			GC         struct {
+				Count int64 `json:"count"`
+			} `json:"gc"`
+		} `json:"runtime"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&vars); err != nil {
+		t.Fatalf("decode /debug/vars: %v", err)
+	}
+	if vars.Memstats == nil || vars.Runtime.Goroutines == 0 {
+		t.Errorf("/debug/vars = %+v, want memstats and the runtime stats", vars)
+	}
+
+	// Handler can be built more than once without publishing the expvar twice
+	Handler()
+}
@@ -0,0 +1,75 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/dict-simulator/go/internal/logger"
+)
+
+// Handler consumes events fanned out by the Bus
+type Handler func(ctx context.Context, event Event)
+
+type subscriber struct {
+	name   string
+	handle Handler
+}
+
+// Bus fans events out to in-process subscribers
+// It implements Publisher, so it can be fed either by request handlers or by a change stream.
+// Subscribers run synchronously in registration order and must not block; a panicking
+// subscriber is logged and skipped so it cannot starve the others.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers []subscriber
+}
+
+// NewBus creates a bus with no subscribers
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers a handler for every event published on the bus
+func (b *Bus) Subscribe(name string, handle Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, subscriber{name: name, handle: handle})
+}
+
+// Publish delivers the event to every subscriber, stamped with the trace of ctx unless it has one
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	event = WithTrace(ctx, event)
+
+	b.mu.RLock()
+	subscribers := b.subscribers
+	b.mu.RUnlock()
+
+	for _, s := range subscribers {
+		b.deliver(ctx, s, event)
+	}
+}
+
+func (b *Bus) deliver(ctx context.Context, s subscriber, event Event) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			logger.Error("Event subscriber panicked",
+				zap.String("subscriber", s.name),
+				zap.String("eventId", event.ID),
+				zap.String("eventType", string(event.Type)),
+				zap.Any("panic", rec),
+			)
+		}
+	}()
+
+	s.handle(ctx, event)
+}
+
+// discard drops every event
+type discard struct{}
+
+func (discard) Publish(context.Context, Event) {}
+
+// Discard is a Publisher for handlers whose events are sourced elsewhere (e.g. a change stream)
+var Discard Publisher = discard{}
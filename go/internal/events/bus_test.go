@@ -0,0 +1,78 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestBusFansOutInOrder(t *testing.T) {
+	bus := NewBus()
+
+	var got []string
+	bus.Subscribe("first", func(_ context.Context, e Event) { got = append(got, "first:"+e.ID) })
+	bus.Subscribe("second", func(_ context.Context, e Event) { got = append(got, "second:"+e.ID) })
+
+	event := New(EntryCreated, "12345678", nil, time.Now())
+	bus.Publish(context.Background(), event)
+
+	want := []string{"first:" + event.ID, "second:" + event.ID}
+	if len(got) != len(want) {
+		t.Fatalf("subscribers received %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("delivery %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestBusSurvivesPanickingSubscriber(t *testing.T) {
+	bus := NewBus()
+
+	delivered := false
+	bus.Subscribe("broken", func(context.Context, Event) { panic("boom") })
+	bus.Subscribe("healthy", func(context.Context, Event) { delivered = true })
+
+	bus.Publish(context.Background(), New(EntryDeleted, "12345678", nil, time.Now()))
+
+	if !delivered {
+		t.Errorf("subscriber after a panicking one was not called")
+	}
+}
+
+func TestBusStampsTheTrace(t *testing.T) {
+	bus := NewBus()
+	var got Event
+	bus.Subscribe("capture", func(_ context.Context, e Event) { got = e })
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	bus.Publish(ctx, New(EntryCreated, "12345678", nil, time.Now()))
+	want := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	if got.TraceParent != want {
+		t.Fatalf("traceparent = %q, want %q", got.TraceParent, want)
+	}
+	if sc := trace.SpanContextFromContext(got.TraceContext(context.Background())); sc.TraceID() != traceID || sc.SpanID() != spanID {
+		t.Errorf("trace continued from the event = %s/%s, want %s/%s", sc.TraceID(), sc.SpanID(), traceID, spanID)
+	}
+
+	// A relayed event keeps the trace of the request that caused it
+	bus.Publish(context.Background(), got)
+	if got.TraceParent != want {
+		t.Errorf("relayed traceparent = %q, want %q", got.TraceParent, want)
+	}
+
+	bus.Publish(context.Background(), New(EntryCreated, "12345678", nil, time.Now()))
+	if got.TraceParent != "" {
+		t.Errorf("traceparent without a trace = %q, want none", got.TraceParent)
+	}
+}
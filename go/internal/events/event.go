@@ -0,0 +1,75 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Type identifies a directory event
+type Type string
+
+const (
+	EntryCreated      Type = "ENTRY_CREATED"
+	EntryUpdated      Type = "ENTRY_UPDATED"
+	EntryDeleted      Type = "ENTRY_DELETED"
+	ClaimOpened       Type = "CLAIM_OPENED"
+	ClaimCompleted    Type = "CLAIM_COMPLETED"
+	InfractionCreated Type = "INFRACTION_CREATED"
+
+	// Security events concern a user rather than a participant, so they reach no webhook
+	AccountLocked Type = "ACCOUNT_LOCKED"
+)
+
+// Event is a notification about a change in the directory, addressed to a participant
+type Event struct {
+	ID          string    `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Type        Type      `json:"type" example:"ENTRY_CREATED"`
+	Participant string    `json:"participant" example:"12345678"`
+	OccurredAt  time.Time `json:"occurredAt"`
+	Data        any       `json:"data"`
+	TraceParent string    `json:"traceparent,omitempty" example:"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"` // W3C trace context of the request that caused the event
+	TraceState  string    `json:"tracestate,omitempty"`
+}
+
+// New creates an event with a fresh ID
+func New(eventType Type, participant string, data any, occurredAt time.Time) Event {
+	return Event{
+		ID:          uuid.New().String(),
+		Type:        eventType,
+		Participant: participant,
+		OccurredAt:  occurredAt.UTC(),
+		Data:        data,
+	}
+}
+
+// traceContext reads and writes the W3C traceparent and tracestate, whether or not tracing is set up
+var traceContext = propagation.TraceContext{}
+
+// WithTrace returns event carrying the trace context of ctx, unless it already carries one
+// Events published by a request thus keep its trace however they are relayed.
+func WithTrace(ctx context.Context, event Event) Event {
+	if event.TraceParent != "" {
+		return event
+	}
+	carrier := propagation.MapCarrier{}
+	traceContext.Inject(ctx, carrier)
+	event.TraceParent, event.TraceState = carrier.Get("traceparent"), carrier.Get("tracestate")
+	return event
+}
+
+// TraceContext returns ctx continuing the trace the event carries, so work done for it joins that trace
+func (e Event) TraceContext(ctx context.Context) context.Context {
+	if e.TraceParent == "" {
+		return ctx
+	}
+	return traceContext.Extract(ctx, propagation.MapCarrier{"traceparent": e.TraceParent, "tracestate": e.TraceState})
+}
+
+// Publisher delivers events to interested consumers
+// Publish must not block on delivery; failures are handled by the publisher itself.
+type Publisher interface {
+	Publish(ctx context.Context, event Event)
+}
@@ -0,0 +1,34 @@
+package events
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/dict-simulator/go/internal/logger"
+)
+
+var eventsTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "dict_events_total",
+		Help: "Total number of directory events published on the event bus",
+	},
+	[]string{"type"},
+)
+
+// CountMetric is a subscriber that counts events by type
+func CountMetric(_ context.Context, event Event) {
+	eventsTotal.WithLabelValues(string(event.Type)).Inc()
+}
+
+// AuditLog is a subscriber that writes every event to the structured log
+func AuditLog(_ context.Context, event Event) {
+	logger.Info("directory event",
+		zap.String("eventId", event.ID),
+		zap.String("eventType", string(event.Type)),
+		zap.String("participant", event.Participant),
+		zap.Time("occurredAt", event.OccurredAt),
+	)
+}
@@ -0,0 +1,87 @@
+package hotreload
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/dict-simulator/go/internal/config"
+	"github.com/dict-simulator/go/internal/logger"
+	"github.com/dict-simulator/go/internal/middleware"
+)
+
+// Reload triggers
+const (
+	TriggerSignal = "signal"
+	TriggerAdmin  = "admin"
+)
+
+var reloadsTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "config_reloads_total",
+		Help: "Total number of configuration reloads by trigger (signal, admin) and result (success, error)",
+	},
+	[]string{"trigger", "result"},
+)
+
+// Reloader re-reads the configuration and applies its hot-reloadable settings to the middlewares
+// Everything else in the configuration only takes effect on restart.
+type Reloader struct {
+	mu      sync.Mutex
+	read    func() (*config.Config, error)
+	manager *middleware.Manager
+}
+
+// New creates a reloader that reads the configuration with read, normally config.Read
+func New(read func() (*config.Config, error), manager *middleware.Manager) *Reloader {
+	return &Reloader{read: read, manager: manager}
+}
+
+// Reload reads the configuration and applies it, returning the settings now in effect
+// An invalid configuration is rejected as a whole and the previous settings stay in effect.
+func (r *Reloader) Reload(trigger string) (middleware.Settings, error) {
+	// Serialised so two reloads can't apply their reads out of order
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cfg, err := r.read()
+	if err != nil {
+		reloadsTotal.WithLabelValues(trigger, "error").Inc()
+		logger.Error("Configuration reload rejected", zap.String("trigger", trigger), zap.Error(err))
+		return r.manager.Settings(), err
+	}
+
+	settings := middleware.NewSettings(cfg)
+	r.manager.Apply(settings)
+
+	reloadsTotal.WithLabelValues(trigger, "success").Inc()
+	logger.Info("Configuration reloaded",
+		zap.String("trigger", trigger),
+		zap.Bool("rate_limit_enabled", settings.RateLimitEnabled),
+		zap.Int("latency_profiles", len(settings.LatencyProfiles)),
+		zap.Int("fault_rules", len(settings.FaultRules)),
+	)
+	return settings, nil
+}
+
+// WatchSignals reloads on every SIGHUP until ctx is cancelled
+func (r *Reloader) WatchSignals(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.Reload(TriggerSignal)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package hotreload
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dict-simulator/go/internal/chaos"
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/config"
+	"github.com/dict-simulator/go/internal/latency"
+	"github.com/dict-simulator/go/internal/middleware"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/ratelimit"
+	"github.com/dict-simulator/go/internal/signing"
+)
+
+func newManager() *middleware.Manager {
+	clk := clock.NewSimulated()
+	return middleware.NewManager(models.NewMemoryIdempotencyRepository(clk), ratelimit.NewMemoryBucket(clk), ratelimit.NewMemoryBanStore(), signing.NewMemoryNonceStore(), chaos.NewInjector(clk), middleware.Settings{RateLimitEnabled: true})
+}
+
+func TestReloadAppliesSettings(t *testing.T) {
+	manager := newManager()
+	reloader := New(func() (*config.Config, error) {
+		return &config.Config{
+			RateLimitEnabled: false,
+			LatencyProfiles:  latency.Profiles{latency.DefaultRoute: {}},
+			FaultRules:       []chaos.Fault{{Type: chaos.FaultError}},
+		}, nil
+	}, manager)
+
+	settings, err := reloader.Reload(TriggerAdmin)
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	got := manager.Settings()
+	if got.RateLimitEnabled || len(got.LatencyProfiles) != 1 || len(got.FaultRules) != 1 {
+		t.Errorf("manager settings = %+v, want the reloaded ones", got)
+	}
+	if settings.RateLimitEnabled != got.RateLimitEnabled {
+		t.Errorf("Reload() returned %+v, want the settings in effect", settings)
+	}
+}
+
+func TestReloadKeepsSettingsOnError(t *testing.T) {
+	manager := newManager()
+	reloader := New(func() (*config.Config, error) {
+		return nil, errors.New("PORT must be between 1 and 65535")
+	}, manager)
+
+	settings, err := reloader.Reload(TriggerSignal)
+	if err == nil {
+		t.Fatal("Reload() accepted an invalid configuration")
+	}
+	if !settings.RateLimitEnabled || !manager.Settings().RateLimitEnabled {
+		t.Errorf("settings changed after a rejected reload: %+v", manager.Settings())
+	}
+}
@@ -0,0 +1,35 @@
+package httputil
+
+import (
+	"context"
+	"net"
+	"net/http"
+)
+
+// clientIPKey is the context key under which the request's client address is stored
+type clientIPKey struct{}
+
+// WithClientIP returns a copy of ctx carrying the address the request came from
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIPFromContext returns the client address stored by WithClientIP, or ""
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
+
+// ClientIP returns the address the request came from
+// The ClientIP middleware settles it once per request, trusting forwarding headers only from
+// trusted proxies; without it (e.g. handlers served directly in tests) it is the connection's peer.
+func ClientIP(r *http.Request) string {
+	if ip := ClientIPFromContext(r.Context()); ip != "" {
+		return ip
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
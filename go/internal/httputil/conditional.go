@@ -0,0 +1,56 @@
+package httputil
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SetValidators sets the ETag and Last-Modified headers of a response
+// Last-Modified has second precision, as HTTP dates do.
+func SetValidators(w http.ResponseWriter, etag string, lastModified time.Time) {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+}
+
+// NotModified reports whether r's conditional headers match a resource with these validators
+// If-None-Match takes precedence: If-Modified-Since is only checked when the request has no
+// If-None-Match (RFC 9110, section 13.2.2). Only GET and HEAD requests are conditional here.
+func NotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, etag)
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// WriteNotModified answers a conditional request with 304 Not Modified
+// The validators must already be set; a 304 has no body.
+func WriteNotModified(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(CorrelationIDHeader, GetCorrelationID(r))
+	w.WriteHeader(http.StatusNotModified)
+}
+
+// etagMatches reports whether an If-None-Match list holds etag, comparing weakly
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
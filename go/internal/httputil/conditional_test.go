@@ -0,0 +1,54 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNotModified(t *testing.T) {
+	etag := `W/"abc"`
+	modified := time.Date(2024, 1, 15, 10, 30, 0, 500_000_000, time.UTC)
+
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		want    bool
+	}{
+		{name: "unconditional", want: false},
+		{name: "matching etag", headers: map[string]string{"If-None-Match": `W/"abc"`}, want: true},
+		{name: "strong form of etag", headers: map[string]string{"If-None-Match": `"abc"`}, want: true},
+		{name: "etag in list", headers: map[string]string{"If-None-Match": `"xyz", W/"abc"`}, want: true},
+		{name: "wildcard", headers: map[string]string{"If-None-Match": `*`}, want: true},
+		{name: "stale etag", headers: map[string]string{"If-None-Match": `W/"xyz"`}, want: false},
+		{name: "same second", headers: map[string]string{"If-Modified-Since": "Mon, 15 Jan 2024 10:30:00 GMT"}, want: true},
+		{name: "later", headers: map[string]string{"If-Modified-Since": "Mon, 15 Jan 2024 11:00:00 GMT"}, want: true},
+		{name: "earlier", headers: map[string]string{"If-Modified-Since": "Mon, 15 Jan 2024 10:29:59 GMT"}, want: false},
+		{name: "malformed date", headers: map[string]string{"If-Modified-Since": "yesterday"}, want: false},
+		{
+			name:    "stale etag wins over date",
+			headers: map[string]string{"If-None-Match": `W/"xyz"`, "If-Modified-Since": "Mon, 15 Jan 2024 11:00:00 GMT"},
+			want:    false,
+		},
+		{name: "not a GET", method: http.MethodPut, headers: map[string]string{"If-None-Match": `W/"abc"`}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			r := httptest.NewRequest(method, "/entries/k", nil)
+			for name, value := range tt.headers {
+				r.Header.Set(name, value)
+			}
+
+			if got := NotModified(r, etag, modified); got != tt.want {
+				t.Errorf("NotModified() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package httputil
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dict-simulator/go/internal/constants"
+)
+
+// maxPooledBuffer bounds the buffers kept for reuse so one large response doesn't pin its memory
+const maxPooledBuffer = 64 << 10
+
+// maxErrorBodies bounds the error body cache; errors with dynamic messages beyond it are encoded per request
+const maxErrorBodies = 1024
+
+// encoder is a JSON encoder bound to its own buffer, reused across responses
+type encoder struct {
+	buf bytes.Buffer
+	enc *json.Encoder
+}
+
+var encoders = sync.Pool{New: func() any {
+	e := &encoder{}
+	e.enc = json.NewEncoder(&e.buf)
+	return e
+}}
+
+func getEncoder() *encoder {
+	e := encoders.Get().(*encoder)
+	e.buf.Reset()
+	return e
+}
+
+func putEncoder(e *encoder) {
+	if e.buf.Cap() <= maxPooledBuffer {
+		encoders.Put(e)
+	}
+}
+
+// writeEncoded writes v as a JSON response
+// The body is encoded into a pooled buffer first, so it reaches w in one Write.
+func writeEncoded(w http.ResponseWriter, status int, v any) {
+	e := getEncoder()
+	defer putEncoder(e)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := e.enc.Encode(v); err != nil {
+		return
+	}
+	w.Write(e.buf.Bytes())
+}
+
+// errorBodyKey identifies the constant part of an error envelope
+type errorBodyKey struct {
+	code, message string
+}
+
+// errorBodies caches the marshalled tail of error envelopes, from the error code to the closing
+// brace and newline; only the response time and the IDs before it vary per request
+var (
+	errorBodiesMu sync.RWMutex
+	errorBodies   = make(map[errorBodyKey][]byte)
+)
+
+// errorBody returns the marshalled tail of an error envelope without violations
+func errorBody(code, message string) []byte {
+	key := errorBodyKey{code: code, message: message}
+	errorBodiesMu.RLock()
+	body, ok := errorBodies[key]
+	errorBodiesMu.RUnlock()
+	if ok {
+		return body
+	}
+
+	// Marshalling the same omitempty fields as APIResponse keeps the escaping identical
+	fields, _ := json.Marshal(struct {
+		Error   string `json:"error,omitempty"`
+		Message string `json:"message,omitempty"`
+	}{code, message})
+	if len(fields) > 2 {
+		body = append([]byte{','}, fields[1:]...)
+	} else {
+		body = []byte{'}'}
+	}
+	body = append(body, '\n')
+
+	errorBodiesMu.Lock()
+	if len(errorBodies) < maxErrorBodies {
+		errorBodies[key] = body
+	}
+	errorBodiesMu.Unlock()
+	return body
+}
+
+// writeErrorBody writes an error envelope from its cached tail
+// Reports false, having written nothing, when an ID needs JSON escaping.
+func writeErrorBody(w http.ResponseWriter, status int, responseTime time.Time, correlationID, requestID string, apiErr constants.APIError) bool {
+	if !jsonSafe(correlationID) || !jsonSafe(requestID) {
+		return false
+	}
+
+	e := getEncoder()
+	defer putEncoder(e)
+
+	e.buf.WriteString(`{"responseTime":"`)
+	e.buf.Write(responseTime.AppendFormat(e.buf.AvailableBuffer(), time.RFC3339Nano))
+	e.buf.WriteString(`","correlationId":"`)
+	e.buf.WriteString(correlationID)
+	e.buf.WriteByte('"')
+	if requestID != "" {
+		e.buf.WriteString(`,"requestId":"`)
+		e.buf.WriteString(requestID)
+		e.buf.WriteByte('"')
+	}
+	e.buf.Write(errorBody(apiErr.Code, apiErr.Message))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(e.buf.Bytes())
+	return true
+}
+
+// jsonSafe reports whether encoding/json writes s as-is between quotes: printable ASCII other
+// than the characters it escapes
+func jsonSafe(s string) bool {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c < 0x20 || c > 0x7e:
+			return false
+		case c == '"' || c == '\\' || c == '<' || c == '>' || c == '&':
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package httputil
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// domainErrors maps each kind of failure, per resource, to the API error that reports it
+var domainErrors = map[models.Error]constants.APIError{
+	{Kind: models.ErrDuplicateKey, Resource: models.ResourceEntry}:        constants.ErrKeyAlreadyExists,
+	{Kind: models.ErrNotFound, Resource: models.ResourceEntry}:            constants.ErrEntryNotFound,
+	{Kind: models.ErrDuplicateKey, Resource: models.ResourceUser}:         constants.ErrUserAlreadyExists,
+	{Kind: models.ErrNotFound, Resource: models.ResourceUser}:             constants.ErrUserNotFound,
+	{Kind: models.ErrLocked, Resource: models.ResourceAccount}:            constants.ErrAccountLocked,
+	{Kind: models.ErrLimitExceeded, Resource: models.ResourceOwner}:       constants.ErrEntryLimitExceeded,
+	{Kind: models.ErrLimitExceeded, Resource: models.ResourceParticipant}: constants.ErrEntryQuotaExceeded,
+	{Kind: models.ErrDuplicateKey, Resource: models.ResourceRequestID}:    constants.ErrRequestIDAlreadyUsed,
+	{Kind: models.ErrDuplicateKey, Resource: models.ResourceSettlement}:   constants.ErrSettlementAlreadyExists,
+	{Kind: models.ErrNotFound, Resource: models.ResourceSettlement}:       constants.ErrSettlementNotFound,
+}
+
+// APIErrorFor returns the API error err maps to: err itself when it is a constants.APIError, the
+// API error of its kind and resource when it is a *models.Error, 499 when it is context.Canceled,
+// 504 when it is context.DeadlineExceeded or a network timeout, and fallback otherwise
+func APIErrorFor(err error, fallback constants.APIError) constants.APIError {
+	var apiErr constants.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	var domainErr *models.Error
+	if errors.As(err, &domainErr) {
+		if apiErr, ok := domainErrors[models.Error{Kind: domainErr.Kind, Resource: domainErr.Resource}]; ok {
+			return apiErr
+		}
+	}
+	if errors.Is(err, context.Canceled) {
+		return constants.ErrClientClosedRequest
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout() {
+		return constants.ErrTimeout
+	}
+	return fallback
+}
+
+// WriteError writes the API error err maps to (see APIErrorFor)
+// A locked or limited resource that knows when it is allowed again also gets Retry-After, in whole
+// seconds. A store failure while the request's context is done is reported by why it ended, as
+// drivers don't always wrap the context's error.
+func WriteError(w http.ResponseWriter, r *http.Request, err error, fallback constants.APIError) {
+	var domainErr *models.Error
+	if errors.As(err, &domainErr) && domainErr.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(domainErr.RetryAfter.Seconds()))))
+	}
+	apiErr := APIErrorFor(err, fallback)
+	if ctxErr := r.Context().Err(); ctxErr != nil && apiErr.Status >= http.StatusInternalServerError {
+		apiErr = APIErrorFor(ctxErr, apiErr)
+	}
+	WriteAPIError(w, r, apiErr)
+}
@@ -0,0 +1,82 @@
+package httputil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+func TestAPIErrorFor(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want constants.APIError
+	}{
+		{"duplicate entry", models.ErrEntryKeyExists, constants.ErrKeyAlreadyExists},
+		{"wrapped duplicate user", fmt.Errorf("register: %w", models.ErrUserEmailExists), constants.ErrUserAlreadyExists},
+		{"entry not found", models.NotFound(models.ResourceEntry), constants.ErrEntryNotFound},
+		{"locked account", models.Locked(models.ResourceAccount, time.Minute), constants.ErrAccountLocked},
+		{"API error", fmt.Errorf("check: %w", constants.ErrKeyMismatch), constants.ErrKeyMismatch},
+		{"client gone", fmt.Errorf("find: %w", context.Canceled), constants.ErrClientClosedRequest},
+		{"deadline", fmt.Errorf("find: %w", context.DeadlineExceeded), constants.ErrTimeout},
+		{"network timeout", &net.OpError{Op: "read", Err: timeoutError{}}, constants.ErrTimeout},
+		{"unmapped resource", models.NotFound("widget"), constants.ErrInternalError},
+		{"other error", errors.New("connection reset"), constants.ErrInternalError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := APIErrorFor(tt.err, constants.ErrInternalError)
+			if got.Code != tt.want.Code || got.Message != tt.want.Message || got.Status != tt.want.Status {
+				t.Errorf("APIErrorFor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteErrorSetsRetryAfterWhenLocked(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteError(w, newResponseRequest("c1"), models.Locked(models.ResourceAccount, 1500*time.Millisecond), constants.ErrInternalError)
+
+	if w.Code != http.StatusLocked {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusLocked)
+	}
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+}
+
+// timeoutError is a net.Error that timed out
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestWriteErrorReportsEndedRequests(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	<-ctx.Done()
+
+	// The driver's error doesn't say why it failed, but the request's context does
+	w := httptest.NewRecorder()
+	WriteError(w, newResponseRequest("c1").WithContext(ctx), errors.New("connection closed"), constants.ErrFailedToFindEntry)
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusGatewayTimeout)
+	}
+
+	// Answers other than server errors are kept
+	w = httptest.NewRecorder()
+	WriteError(w, newResponseRequest("c1").WithContext(ctx), models.ErrEntryNotFound, constants.ErrFailedToFindEntry)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
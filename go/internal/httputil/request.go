@@ -0,0 +1,91 @@
+package httputil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/dict-simulator/go/internal/constants"
+)
+
+// unknownFieldPrefix starts the message of json.Decoder's unknown field errors, which have no type
+const unknownFieldPrefix = "json: unknown field "
+
+// errTrailingData is returned by DecodeJSON when the body holds more than one JSON value
+var errTrailingData = errors.New("request body must contain a single JSON value")
+
+// DecodeJSON decodes a JSON request body into v
+// Unlike a bare json.Decoder it rejects fields v doesn't declare and anything after the first value.
+// Answer errors with BodyError.
+func DecodeJSON(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return err
+		}
+		return errTrailingData
+	}
+	return nil
+}
+
+// BodyError maps an error reading or decoding a request body to the API error to answer with
+// Oversized bodies get a 413; anything else is a 400 saying what is wrong with the payload.
+func BodyError(err error) constants.APIError {
+	var (
+		maxBytesErr *http.MaxBytesError
+		syntaxErr   *json.SyntaxError
+		typeErr     *json.UnmarshalTypeError
+		invalidBody = constants.ErrInvalidRequestBody
+	)
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		apiErr := constants.ErrRequestTooLarge
+		return apiErr.WithMessage(fmt.Sprintf("%s (limit is %d bytes)", apiErr.Message, maxBytesErr.Limit))
+	case errors.Is(err, io.EOF):
+		return invalidBody.WithMessage(constants.MsgRequestBodyEmpty)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return invalidBody.WithMessage(constants.MsgRequestBodyTruncated)
+	case errors.As(err, &syntaxErr):
+		return invalidBody.WithMessage(fmt.Sprintf("Malformed JSON at byte %d", syntaxErr.Offset))
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return invalidBody.WithMessage(fmt.Sprintf("Field %q must be %s", typeErr.Field, jsonKind(typeErr.Type)))
+	case errors.As(err, &typeErr):
+		return invalidBody.WithMessage("Request body must be " + jsonKind(typeErr.Type))
+	case strings.HasPrefix(err.Error(), unknownFieldPrefix):
+		return invalidBody.WithMessage("Unknown field " + strings.TrimPrefix(err.Error(), unknownFieldPrefix))
+	case errors.Is(err, errTrailingData):
+		return invalidBody.WithMessage(constants.MsgRequestBodyTrailing)
+	default:
+		return invalidBody
+	}
+}
+
+// jsonKind names the JSON value a Go type is decoded from
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Pointer:
+		return jsonKind(t.Elem())
+	default:
+		return "an object"
+	}
+}
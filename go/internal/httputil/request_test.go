@@ -0,0 +1,67 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dict-simulator/go/internal/constants"
+)
+
+type decodeTarget struct {
+	Key     string `json:"key"`
+	Account struct {
+		Branch string `json:"branch"`
+	} `json:"account"`
+	Tags []string `json:"tags"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		limit       int64
+		wantStatus  int
+		wantMessage string
+	}{
+		{name: "valid", body: `{"key":"k","account":{"branch":"0001"}}`},
+		{name: "empty", body: ``, wantStatus: http.StatusBadRequest, wantMessage: constants.MsgRequestBodyEmpty},
+		{name: "truncated", body: `{"key":"k"`, wantStatus: http.StatusBadRequest, wantMessage: constants.MsgRequestBodyTruncated},
+		{name: "malformed", body: `{"key":}`, wantStatus: http.StatusBadRequest, wantMessage: "Malformed JSON at byte 8"},
+		{name: "unknown field", body: `{"key":"k","extra":1}`, wantStatus: http.StatusBadRequest, wantMessage: `Unknown field "extra"`},
+		{name: "unknown nested field", body: `{"account":{"bank":"x"}}`, wantStatus: http.StatusBadRequest, wantMessage: `Unknown field "bank"`},
+		{name: "wrong type", body: `{"account":{"branch":1}}`, wantStatus: http.StatusBadRequest, wantMessage: `Field "account.branch" must be a string`},
+		{name: "wrong array type", body: `{"tags":"a"}`, wantStatus: http.StatusBadRequest, wantMessage: `Field "tags" must be an array`},
+		{name: "not an object", body: `[1]`, wantStatus: http.StatusBadRequest, wantMessage: "Request body must be an object"},
+		{name: "trailing value", body: `{"key":"k"} {"key":"k"}`, wantStatus: http.StatusBadRequest, wantMessage: constants.MsgRequestBodyTrailing},
+		{name: "trailing whitespace", body: "{\"key\":\"k\"}\n"},
+		{name: "over the limit", body: `{"key":"` + strings.Repeat("k", 100) + `"}`, limit: 50, wantStatus: http.StatusRequestEntityTooLarge, wantMessage: "Request body is too large (limit is 50 bytes)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			if tt.limit > 0 {
+				r.Body = http.MaxBytesReader(httptest.NewRecorder(), r.Body, tt.limit)
+			}
+
+			var v decodeTarget
+			err := DecodeJSON(r, &v)
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("DecodeJSON() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("DecodeJSON() accepted the body")
+			}
+
+			apiErr := BodyError(err)
+			if apiErr.Status != tt.wantStatus || apiErr.Message != tt.wantMessage {
+				t.Errorf("BodyError() = %d %q, want %d %q", apiErr.Status, apiErr.Message, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
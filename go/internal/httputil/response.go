@@ -1,27 +1,33 @@
 package httputil
 
 import (
-	"encoding/json"
+	"context"
 	"net/http"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/dict-simulator/go/internal/clock"
 	"github.com/dict-simulator/go/internal/constants"
 )
 
 // CorrelationIDHeader is the header name for correlation ID
 const CorrelationIDHeader = "X-Correlation-Id"
 
+// RequestIDHeader is the header name for the server-generated request ID
+const RequestIDHeader = "X-Request-Id"
+
 // APIResponse wraps all API responses with DICT-compliant metadata
 // Per DICT spec, responses include ResponseTime and CorrelationId
 type APIResponse struct {
-	ResponseTime  time.Time `json:"responseTime" example:"2024-01-15T10:30:00Z"`
-	CorrelationId string    `json:"correlationId" example:"550e8400-e29b-41d4-a716-446655440000"`
-	Code          string    `json:"code,omitempty" example:"ENTRY_CREATED"`
-	Data          any       `json:"data,omitempty"`
-	Error         string    `json:"error,omitempty" example:"INVALID_REQUEST"`
-	Message       string    `json:"message,omitempty" example:"Request processed successfully"`
+	ResponseTime  time.Time                  `json:"responseTime" example:"2024-01-15T10:30:00Z"`
+	CorrelationId string                     `json:"correlationId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	RequestId     string                     `json:"requestId,omitempty" example:"9b2f6c1e-4d3a-4f7e-8c21-0e5b7a9d3f40"` // server-generated; set on errors
+	Code          string                     `json:"code,omitempty" example:"ENTRY_CREATED"`
+	Data          any                        `json:"data,omitempty"`
+	Error         string                     `json:"error,omitempty" example:"INVALID_REQUEST"`
+	Message       string                     `json:"message,omitempty" example:"Request processed successfully"`
+	Violations    []constants.FieldViolation `json:"violations,omitempty"`
 }
 
 // ErrorResponse represents a standard error response (for backwards compatibility)
@@ -30,9 +36,36 @@ type ErrorResponse struct {
 	Message string `json:"message"`
 }
 
-// GetCorrelationID extracts the correlation ID from the request header
-// If not present, generates a new UUID v4
-func GetCorrelationID(r *http.Request) string {
+// correlationIDKey is the context key under which the request's correlation ID is stored
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a copy of ctx carrying the request's correlation ID
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, correlationID)
+}
+
+// CorrelationIDFromContext returns the correlation ID stored by WithCorrelationID, or ""
+func CorrelationIDFromContext(ctx context.Context) string {
+	correlationID, _ := ctx.Value(correlationIDKey{}).(string)
+	return correlationID
+}
+
+// requestIDKey is the context key under which the request's server-generated ID is stored
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request's server-generated ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored by WithRequestID, or ""
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// NewCorrelationID returns the client's correlation ID header, or a new UUID v4 if it sent none
+func NewCorrelationID(r *http.Request) string {
 	correlationID := r.Header.Get(CorrelationIDHeader)
 	if correlationID == "" {
 		correlationID = uuid.New().String()
@@ -40,67 +73,79 @@ func GetCorrelationID(r *http.Request) string {
 	return correlationID
 }
 
+// GetCorrelationID returns the request's correlation ID
+// The CorrelationID middleware settles it once per request; without it (e.g. handlers served
+// directly in tests) it falls back to NewCorrelationID, which may generate a different ID per call.
+func GetCorrelationID(r *http.Request) string {
+	if correlationID := CorrelationIDFromContext(r.Context()); correlationID != "" {
+		return correlationID
+	}
+	return NewCorrelationID(r)
+}
+
+// clockKey is the context key under which the request's clock is stored
+type clockKey struct{}
+
+// WithClock returns a copy of ctx whose responses are stamped with clk's time
+func WithClock(ctx context.Context, clk clock.Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, clk)
+}
+
+// responseTime returns the ResponseTime for r: the clock stored by WithClock, or the wall clock
+// so handlers served directly in tests still get a timestamp
+func responseTime(r *http.Request) time.Time {
+	clk, ok := r.Context().Value(clockKey{}).(clock.Clock)
+	if !ok {
+		clk = clock.System
+	}
+	return clk.Now().UTC()
+}
+
 // WriteJSON writes a JSON response with the given status code
 // This is the legacy function for backwards compatibility
 func WriteJSON(w http.ResponseWriter, status int, data any) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(data)
+	writeEncoded(w, status, data)
 }
 
 // WriteAPIResponse writes a DICT-compliant API response with metadata
-// Includes ResponseTime and CorrelationId from request header
+// Includes ResponseTime and the request's CorrelationId (see GetCorrelationID)
 func WriteAPIResponse(w http.ResponseWriter, r *http.Request, status int, data any) {
 	correlationID := GetCorrelationID(r)
 
 	// Set correlation ID in response header as well
 	w.Header().Set(CorrelationIDHeader, correlationID)
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
 
-	response := APIResponse{
-		ResponseTime:  time.Now().UTC(),
+	writeEncoded(w, status, &APIResponse{
+		ResponseTime:  responseTime(r),
 		CorrelationId: correlationID,
 		Data:          data,
-	}
-
-	json.NewEncoder(w).Encode(response)
+	})
 }
 
 // WriteAPIError writes a DICT-compliant error response with metadata using a predefined APIError.
-// Includes ResponseTime and CorrelationId from request header.
+// Includes ResponseTime, the request's CorrelationId (see GetCorrelationID) and its RequestId, which
+// operators look the failure up by.
 func WriteAPIError(w http.ResponseWriter, r *http.Request, apiErr constants.APIError) {
 	correlationID := GetCorrelationID(r)
+	requestID := RequestIDFromContext(r.Context())
 
 	// Set correlation ID in response header as well
 	w.Header().Set(CorrelationIDHeader, correlationID)
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(apiErr.Status)
 
-	response := APIResponse{
-		ResponseTime:  time.Now().UTC(),
+	// Errors without violations only differ per request in their time and IDs
+	now := responseTime(r)
+	if len(apiErr.Violations) == 0 && writeErrorBody(w, apiErr.Status, now, correlationID, requestID, apiErr) {
+		return
+	}
+
+	writeEncoded(w, apiErr.Status, &APIResponse{
+		ResponseTime:  now,
 		CorrelationId: correlationID,
+		RequestId:     requestID,
 		Error:         apiErr.Code,
 		Message:       apiErr.Message,
-	}
-
-	json.NewEncoder(w).Encode(response)
-}
-
-// WriteError writes a DICT-compliant error response without requiring an http.Request.
-// Useful for middleware that may not have access to the full request context.
-// Note: Does not include CorrelationId since there's no request to extract it from.
-func WriteError(w http.ResponseWriter, apiErr constants.APIError) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(apiErr.Status)
-
-	response := APIResponse{
-		ResponseTime: time.Now().UTC(),
-		Error:        apiErr.Code,
-		Message:      apiErr.Message,
-	}
-
-	json.NewEncoder(w).Encode(response)
+		Violations:    apiErr.Violations,
+	})
 }
 
 // WriteAPISuccess writes a DICT-compliant success response with metadata using a predefined APISuccess.
@@ -110,15 +155,11 @@ func WriteAPISuccess(w http.ResponseWriter, r *http.Request, apiSuccess constant
 
 	// Set correlation ID in response header as well
 	w.Header().Set(CorrelationIDHeader, correlationID)
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(apiSuccess.Status)
 
-	response := APIResponse{
-		ResponseTime:  time.Now().UTC(),
+	writeEncoded(w, apiSuccess.Status, &APIResponse{
+		ResponseTime:  responseTime(r),
 		CorrelationId: correlationID,
 		Code:          apiSuccess.Code,
 		Data:          data,
-	}
-
-	json.NewEncoder(w).Encode(response)
+	})
 }
@@ -0,0 +1,129 @@
+package httputil
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dict-simulator/go/internal/constants"
+)
+
+// discardWriter is a ResponseWriter that keeps nothing, so benchmarks measure the encoding alone
+type discardWriter struct{ header http.Header }
+
+func (d *discardWriter) Header() http.Header         { return d.header }
+func (d *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (d *discardWriter) WriteHeader(int)             {}
+
+// fixedClock stamps every response with the same time
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func newResponseRequest(correlationID string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/entries/k", nil)
+	ctx := WithClock(r.Context(), fixedClock(time.Date(2024, 1, 15, 10, 30, 0, 123456789, time.UTC)))
+	return r.WithContext(WithCorrelationID(ctx, correlationID))
+}
+
+// encodeEnvelope is the reference encoding every response must match byte for byte
+func encodeEnvelope(t *testing.T, response APIResponse) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestWriteAPIErrorMatchesEncoder(t *testing.T) {
+	violations := []constants.FieldViolation{{Field: "key", Message: "Must equal owner.taxIdNumber"}}
+	tests := []struct {
+		name          string
+		apiErr        constants.APIError
+		correlationID string
+		requestID     string
+	}{
+		{"constant error", constants.ErrEntryNotFound, "550e8400-e29b-41d4-a716-446655440000", ""},
+		{"request ID", constants.ErrEntryNotFound, "550e8400-e29b-41d4-a716-446655440000", "9b2f6c1e-4d3a-4f7e-8c21-0e5b7a9d3f40"},
+		{"custom message", constants.ErrInvalidRequestBody.WithMessage(`field "key" <required> & missing`), "c1", "r1"},
+		{"violations", constants.ErrInvalidRequestBody.WithViolations(violations), "c2", "r2"},
+		{"correlation ID needing escapes", constants.ErrEntryNotFound, `a"b\c<d>` + " é", "r3"},
+		{"no code or message", constants.APIError{Status: http.StatusTeapot}, "c3", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Twice: the second write takes the cached body of constant errors
+			for range 2 {
+				r := newResponseRequest(tt.correlationID)
+				if tt.requestID != "" {
+					r = r.WithContext(WithRequestID(r.Context(), tt.requestID))
+				}
+				rec := httptest.NewRecorder()
+				WriteAPIError(rec, r, tt.apiErr)
+
+				want := encodeEnvelope(t, APIResponse{
+					ResponseTime:  responseTime(r),
+					CorrelationId: tt.correlationID,
+					RequestId:     tt.requestID,
+					Error:         tt.apiErr.Code,
+					Message:       tt.apiErr.Message,
+					Violations:    tt.apiErr.Violations,
+				})
+				if rec.Code != tt.apiErr.Status {
+					t.Errorf("status = %d, want %d", rec.Code, tt.apiErr.Status)
+				}
+				if got := rec.Body.String(); got != want {
+					t.Errorf("body = %s\nwant   %s", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteAPISuccessMatchesEncoder(t *testing.T) {
+	r := newResponseRequest("c1")
+	data := map[string]string{"key": "k@example.com"}
+	rec := httptest.NewRecorder()
+	WriteAPISuccess(rec, r, constants.SuccessEntryFound, data)
+
+	want := encodeEnvelope(t, APIResponse{
+		ResponseTime:  responseTime(r),
+		CorrelationId: "c1",
+		Code:          constants.SuccessEntryFound.Code,
+		Data:          data,
+	})
+	if got := rec.Body.String(); got != want {
+		t.Errorf("body = %s\nwant   %s", got, want)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+}
+
+func BenchmarkWriteAPIError(b *testing.B) {
+	r := newResponseRequest("550e8400-e29b-41d4-a716-446655440000")
+	w := &discardWriter{header: http.Header{}}
+	b.ReportAllocs()
+	for b.Loop() {
+		WriteAPIError(w, r, constants.ErrEntryNotFound)
+	}
+}
+
+func BenchmarkWriteAPISuccess(b *testing.B) {
+	r := newResponseRequest("550e8400-e29b-41d4-a716-446655440000")
+	w := &discardWriter{header: http.Header{}}
+	data := struct {
+		Key     string `json:"key"`
+		KeyType string `json:"keyType"`
+		Branch  string `json:"branch"`
+	}{"k@example.com", "EMAIL", "0001"}
+	b.ReportAllocs()
+	for b.Loop() {
+		WriteAPISuccess(w, r, constants.SuccessEntryFound, data)
+	}
+}
@@ -0,0 +1,26 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+)
+
+// DefaultAPIVersion is the contract served when the versioning middleware did not pick one
+const DefaultAPIVersion = "v1"
+
+// apiVersionKey is the context key under which the request's API version is stored
+type apiVersionKey struct{}
+
+// WithAPIVersion returns a copy of ctx carrying the API version the request is served under
+func WithAPIVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, apiVersionKey{}, version)
+}
+
+// APIVersion returns the version of the API contract the request is served under, e.g. "v1"
+// Handlers branch on it when a later version changes a response.
+func APIVersion(r *http.Request) string {
+	if version, _ := r.Context().Value(apiVersionKey{}).(string); version != "" {
+		return version
+	}
+	return DefaultAPIVersion
+}
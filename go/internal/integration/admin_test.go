@@ -0,0 +1,739 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dict-simulator/go/internal/middleware"
+)
+
+// =============================================================================
+// Seeding
+// =============================================================================
+
+func TestAdminSeed(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	req := map[string]any{
+		"count": 25,
+		"seed":  20240115,
+		"participants": []map[string]any{
+			{"ispb": "12345678", "weight": 1},
+		},
+	}
+
+	resp := client.POST("/admin/seed", req)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	apiResp := ParseResponse[struct {
+		Code string `json:"code"`
+		Data struct {
+			Requested int    `json:"requested"`
+			Created   int    `json:"created"`
+			Skipped   int    `json:"skipped"`
+			Seed      uint64 `json:"seed"`
+		} `json:"data"`
+	}](t, resp)
+
+	assert.Equal(t, "ENTRIES_SEEDED", apiResp.Code)
+	assert.Equal(t, 25, apiResp.Data.Requested)
+	assert.Equal(t, 25, apiResp.Data.Created)
+	assert.Equal(t, uint64(20240115), apiResp.Data.Seed)
+}
+
+func TestAdminSeed_SameSeedSkipsExistingKeys(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	req := map[string]any{"count": 10, "seed": 7}
+
+	first := client.POST("/admin/seed", req)
+	first.Body.Close()
+	require.Equal(t, http.StatusCreated, first.StatusCode)
+
+	// The same seed regenerates the same keys, which are now taken
+	second := client.POST("/admin/seed", req)
+	defer second.Body.Close()
+	require.Equal(t, http.StatusCreated, second.StatusCode)
+
+	apiResp := ParseResponse[struct {
+		Data struct {
+			Created int `json:"created"`
+			Skipped int `json:"skipped"`
+		} `json:"data"`
+	}](t, second)
+
+	assert.Equal(t, 0, apiResp.Data.Created)
+	assert.Equal(t, 10, apiResp.Data.Skipped)
+}
+
+func TestAdminSeed_InvalidCount(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	resp := client.POST("/admin/seed", map[string]any{"count": 0})
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestAdmin_RejectsUserToken(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	// A registered user's JWT alone does not unlock the admin routes
+	resp := client.Request(http.MethodPost, "/admin/seed", map[string]any{"count": 1}, map[string]string{
+		middleware.AdminTokenHeader: "",
+	})
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	wrong := client.Request(http.MethodPost, "/admin/seed", map[string]any{"count": 1}, map[string]string{
+		middleware.AdminTokenHeader: "not-the-admin-token",
+	})
+	defer wrong.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, wrong.StatusCode)
+}
+
+// =============================================================================
+// Fault Injection
+// =============================================================================
+
+func TestAdminFaults_ErrorOnKeySuffix(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	// Scope the rule to this test's key so parallel tests are unaffected
+	key := "fault-" + uuid.New().String()
+
+	resp := client.POST("/admin/faults", map[string]any{
+		"type":      "ERROR",
+		"route":     "GET /entries/{key}",
+		"keySuffix": key,
+	})
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	created := ParseResponse[struct {
+		Code string `json:"code"`
+		Data struct {
+			ID          string  `json:"id"`
+			Probability float64 `json:"probability"`
+			StatusCode  int     `json:"statusCode"`
+		} `json:"data"`
+	}](t, resp)
+
+	assert.Equal(t, "FAULT_CREATED", created.Code)
+	assert.Equal(t, 1.0, created.Data.Probability)
+	assert.Equal(t, http.StatusServiceUnavailable, created.Data.StatusCode)
+
+	faulted := client.GET("/entries/" + key)
+	defer faulted.Body.Close()
+	require.Equal(t, http.StatusServiceUnavailable, faulted.StatusCode)
+
+	apiErr := ParseResponse[struct {
+		Error string `json:"error"`
+	}](t, faulted)
+	assert.Equal(t, "SERVICE_UNAVAILABLE", apiErr.Error)
+
+	deleted := client.Request(http.MethodDelete, "/admin/faults/"+created.Data.ID, nil, nil)
+	deleted.Body.Close()
+	require.Equal(t, http.StatusOK, deleted.StatusCode)
+
+	// Without the rule the lookup reaches the handler again
+	restored := client.GET("/entries/" + key)
+	defer restored.Body.Close()
+	assert.Equal(t, http.StatusNotFound, restored.StatusCode)
+}
+
+func TestAdminFaults_DeleteUnknown(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	resp := client.Request(http.MethodDelete, "/admin/faults/"+uuid.New().String(), nil, nil)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestAdminFaults_InvalidLatency(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	// LATENCY faults require latencyMs
+	resp := client.POST("/admin/faults", map[string]any{"type": "LATENCY"})
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+// =============================================================================
+// Participant Reset
+// =============================================================================
+
+func TestAdminReset_DropsOnlyTheParticipantsData(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClientForServer(t, StartRateLimitedServer(t))
+
+	// A participant of its own: rate limit buckets live in the Redis shared by all tests
+	participant := fmt.Sprintf("%08d", time.Now().UnixNano()%100_000_000)
+	headers := map[string]string{"X-Participant-Id": participant}
+
+	var keys []string
+	for range 2 {
+		key := GenerateValidCPF()
+		req := CreateEntryRequest(key)
+		req["account"].(map[string]any)["participant"] = participant
+
+		resp := client.POSTWithHeaders("/entries", req, map[string]string{
+			"X-Participant-Id":  participant,
+			"X-Idempotency-Key": uuid.New().String(),
+		})
+		resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		keys = append(keys, key)
+	}
+	kept := client.CreateEntry() // owned by 12345678
+
+	resp := client.POST("/admin/reset", map[string]any{"participant": participant})
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	reset := ParseResponse[struct {
+		Code string `json:"code"`
+		Data struct {
+			EntriesDeleted         int64 `json:"entriesDeleted"`
+			IdempotencyKeysDeleted int64 `json:"idempotencyKeysDeleted"`
+			RateLimitKeysDeleted   int   `json:"rateLimitKeysDeleted"`
+		} `json:"data"`
+	}](t, resp)
+
+	assert.Equal(t, "PARTICIPANT_RESET", reset.Code)
+	assert.Equal(t, int64(2), reset.Data.EntriesDeleted)
+	assert.Equal(t, int64(2), reset.Data.IdempotencyKeysDeleted)
+	assert.Positive(t, reset.Data.RateLimitKeysDeleted)
+
+	for _, key := range keys {
+		gone := client.GETWithHeaders("/entries/"+key, headers)
+		gone.Body.Close()
+		assert.Equal(t, http.StatusNotFound, gone.StatusCode)
+	}
+
+	other := client.GETWithHeaders("/entries/"+kept, headers)
+	other.Body.Close()
+	assert.Equal(t, http.StatusOK, other.StatusCode, "other participants' entries are kept")
+}
+
+func TestAdminReset_InvalidParticipant(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	resp := client.POST("/admin/reset", map[string]any{"participant": "123"})
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+// =============================================================================
+// Erasure
+// =============================================================================
+
+func TestAdminErasure_DeletesTheSubjectsData(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+	erased := client.CreateEntry()
+	kept := client.CreateEntry()
+
+	resp := client.POST("/admin/erasure/"+erased, nil)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	erasure := ParseResponse[struct {
+		Code string `json:"code"`
+		Data struct {
+			TaxIdNumber               string           `json:"taxIdNumber"`
+			Entries                   []map[string]any `json:"entries"`
+			IdempotencyRecordsDeleted int64            `json:"idempotencyRecordsDeleted"`
+		} `json:"data"`
+	}](t, resp)
+
+	assert.Equal(t, "SUBJECT_ERASED", erasure.Code)
+	assert.Equal(t, erased, erasure.Data.TaxIdNumber)
+	require.Len(t, erasure.Data.Entries, 1)
+	assert.Equal(t, erased, erasure.Data.Entries[0]["key"])
+	assert.Equal(t, int64(1), erasure.Data.IdempotencyRecordsDeleted, "the cached creation response names the tax ID")
+
+	gone := client.GET("/entries/" + erased)
+	gone.Body.Close()
+	assert.Equal(t, http.StatusNotFound, gone.StatusCode)
+
+	other := client.GET("/entries/" + kept)
+	other.Body.Close()
+	assert.Equal(t, http.StatusOK, other.StatusCode, "other subjects' entries are kept")
+}
+
+func TestAdminErasure_InvalidTaxID(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	resp := client.POST("/admin/erasure/12345678900", nil)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+// =============================================================================
+// Snapshots
+// =============================================================================
+
+func TestAdminSnapshot_RestoresInAnotherEnvironment(t *testing.T) {
+	t.Parallel()
+
+	source := NewTestClient(t)
+	cpf := source.CreateEntry()
+
+	resp := source.GET("/admin/export")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	snapshot, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	exported := ParseResponse[struct {
+		Version      int `json:"version"`
+		Participants []struct {
+			ISPB    string `json:"ispb"`
+			Entries int    `json:"entries"`
+		} `json:"participants"`
+		Entries []struct {
+			Key       string    `json:"key"`
+			CreatedAt time.Time `json:"createdAt"`
+		} `json:"entries"`
+	}](t, &http.Response{Body: io.NopCloser(bytes.NewReader(snapshot))})
+	require.Len(t, exported.Entries, 1)
+	assert.Equal(t, 1, exported.Version)
+	assert.Equal(t, cpf, exported.Entries[0].Key)
+	require.Len(t, exported.Participants, 1)
+	assert.Equal(t, "12345678", exported.Participants[0].ISPB)
+
+	target := NewTestClient(t)
+	imported := target.POST("/admin/import", json.RawMessage(snapshot))
+	defer imported.Body.Close()
+	require.Equal(t, http.StatusCreated, imported.StatusCode)
+
+	result := ParseResponse[struct {
+		Code string `json:"code"`
+		Data struct {
+			Created int `json:"created"`
+			Skipped int `json:"skipped"`
+		} `json:"data"`
+	}](t, imported)
+	assert.Equal(t, "SNAPSHOT_IMPORTED", result.Code)
+	assert.Equal(t, 1, result.Data.Created)
+
+	entry := target.GET("/entries/" + cpf)
+	defer entry.Body.Close()
+	require.Equal(t, http.StatusOK, entry.StatusCode)
+
+	found := ParseResponse[struct {
+		Data struct {
+			CreatedAt time.Time `json:"createdAt"`
+		} `json:"data"`
+	}](t, entry)
+	assert.True(t, exported.Entries[0].CreatedAt.Equal(found.Data.CreatedAt), "timestamps are restored")
+
+	// Importing again skips the keys that are already registered
+	again := target.POST("/admin/import", json.RawMessage(snapshot))
+	defer again.Body.Close()
+	require.Equal(t, http.StatusCreated, again.StatusCode)
+	assert.Equal(t, 1, ParseResponse[struct {
+		Data struct {
+			Skipped int `json:"skipped"`
+		} `json:"data"`
+	}](t, again).Data.Skipped)
+}
+
+func TestAdminSnapshot_NDJSON(t *testing.T) {
+	t.Parallel()
+
+	source := NewTestClient(t)
+	cpf := source.CreateEntry()
+
+	resp := source.GET("/admin/export?format=ndjson")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+	lines, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	target := NewTestClient(t)
+	req, err := http.NewRequest(http.MethodPost, target.baseURL+"/admin/import", bytes.NewReader(lines))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req.Header.Set(middleware.AdminTokenHeader, testAdminToken)
+
+	imported, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer imported.Body.Close()
+	require.Equal(t, http.StatusCreated, imported.StatusCode)
+
+	entry := target.GET("/entries/" + cpf)
+	defer entry.Body.Close()
+	assert.Equal(t, http.StatusOK, entry.StatusCode)
+}
+
+func TestAdminSnapshot_InvalidEntry(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	entry := CreateEntryRequest("12345678900") // wrong check digits
+	resp := client.POST("/admin/import", map[string]any{
+		"version": 1,
+		"entries": []any{map[string]any{
+			"key":     entry["key"],
+			"keyType": entry["keyType"],
+			"account": entry["account"],
+			"owner":   entry["owner"],
+		}},
+	})
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+// =============================================================================
+// Simulated Clock
+// =============================================================================
+
+func TestAdminTime_AdvanceAndReset(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	type timeData struct {
+		Now           time.Time `json:"now"`
+		OffsetSeconds int64     `json:"offsetSeconds"`
+	}
+
+	resp := client.POST("/admin/time/advance", map[string]any{"duration": "168h"})
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	advanced := ParseResponse[struct {
+		Code string   `json:"code"`
+		Data timeData `json:"data"`
+	}](t, resp)
+
+	assert.Equal(t, "TIME_ADVANCED", advanced.Code)
+	assert.Equal(t, int64(7*24*60*60), advanced.Data.OffsetSeconds)
+	assert.WithinDuration(t, time.Now().Add(7*24*time.Hour), advanced.Data.Now, time.Minute)
+
+	// Entries created now carry the simulated timestamp
+	cpf := client.CreateEntry()
+	entry := client.GET("/entries/" + cpf)
+	defer entry.Body.Close()
+	require.Equal(t, http.StatusOK, entry.StatusCode)
+
+	found := ParseResponse[struct {
+		Data struct {
+			CreatedAt time.Time `json:"createdAt"`
+		} `json:"data"`
+	}](t, entry)
+	assert.WithinDuration(t, advanced.Data.Now, found.Data.CreatedAt, time.Minute)
+
+	reset := client.Request(http.MethodDelete, "/admin/time", nil, nil)
+	defer reset.Body.Close()
+	require.Equal(t, http.StatusOK, reset.StatusCode)
+
+	current := ParseResponse[struct {
+		Data timeData `json:"data"`
+	}](t, reset)
+	assert.Equal(t, int64(0), current.Data.OffsetSeconds)
+}
+
+func TestAdminTime_InvalidDuration(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	for _, duration := range []string{"7d", "-1h", "0s"} {
+		resp := client.POST("/admin/time/advance", map[string]any{"duration": duration})
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "duration %q", duration)
+	}
+}
+
+func TestAdminTime_IdempotencyKeyExpires(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	cpf := GenerateValidCPF()
+	req := CreateEntryRequest(cpf)
+	headers := map[string]string{"X-Idempotency-Key": uuid.New().String()}
+
+	first := client.POSTWithHeaders("/entries", req, headers)
+	first.Body.Close()
+	require.Equal(t, http.StatusCreated, first.StatusCode)
+
+	// Within the TTL the stored response is replayed
+	replay := client.POSTWithHeaders("/entries", req, headers)
+	replay.Body.Close()
+	require.Equal(t, http.StatusCreated, replay.StatusCode)
+
+	advance := client.POST("/admin/time/advance", map[string]any{"duration": "25h"})
+	advance.Body.Close()
+	require.Equal(t, http.StatusOK, advance.StatusCode)
+
+	// Past the TTL the request is processed again and hits the existing key
+	expired := client.POSTWithHeaders("/entries", req, headers)
+	defer expired.Body.Close()
+	assert.Equal(t, http.StatusConflict, expired.StatusCode)
+}
+
+// =============================================================================
+// Config Reload
+// =============================================================================
+
+// Not parallel: reloads read the process environment
+func TestAdminConfig_ReloadAppliesSettings(t *testing.T) {
+	client := NewTestClientForServer(t, StartRateLimitedServer(t))
+
+	key := "reload-" + uuid.New().String()
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("JWT_SECRET", "test-jwt-secret-for-integration-tests")
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+	t.Setenv("FAULT_RULES", `[{"type":"ERROR","route":"GET /entries/{key}","keySuffix":"`+key+`","statusCode":502}]`)
+
+	before := client.GET("/entries/" + key)
+	before.Body.Close()
+	require.Equal(t, http.StatusNotFound, before.StatusCode)
+	assert.NotEmpty(t, before.Header.Get("X-RateLimit-Limit"))
+
+	resp := client.POST("/admin/config/reload", nil)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	reloaded := ParseResponse[struct {
+		Code string `json:"code"`
+		Data struct {
+			RateLimitEnabled bool             `json:"rateLimitEnabled"`
+			FaultRules       []map[string]any `json:"faultRules"`
+		} `json:"data"`
+	}](t, resp)
+	assert.Equal(t, "CONFIG_RELOADED", reloaded.Code)
+	assert.False(t, reloaded.Data.RateLimitEnabled)
+	assert.Len(t, reloaded.Data.FaultRules, 1)
+
+	after := client.GET("/entries/" + key)
+	after.Body.Close()
+	assert.Equal(t, http.StatusBadGateway, after.StatusCode, "the configured fault rule applies")
+	assert.Empty(t, after.Header.Get("X-RateLimit-Limit"), "rate limiting is off")
+}
+
+func TestAdminConfig_InvalidReloadKeepsSettings(t *testing.T) {
+	client := NewTestClientForServer(t, StartRateLimitedServer(t))
+
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("JWT_SECRET", "test-jwt-secret-for-integration-tests")
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+	t.Setenv("WEBHOOK_TIMEOUT", "soon")
+
+	resp := client.POST("/admin/config/reload", nil)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	apiErr := ParseResponse[struct {
+		Message string `json:"message"`
+	}](t, resp)
+	assert.Contains(t, apiErr.Message, "WEBHOOK_TIMEOUT")
+
+	// Nothing from the rejected configuration was applied
+	lookup := client.GET("/entries/" + GenerateValidCPF())
+	lookup.Body.Close()
+	assert.NotEmpty(t, lookup.Header.Get("X-RateLimit-Limit"))
+}
+
+// =============================================================================
+// Log Level
+// =============================================================================
+
+func TestAdminLogLevel_ChangesAtRuntime(t *testing.T) {
+	client := NewTestClient(t)
+	t.Cleanup(func() {
+		resp := client.PUT("/admin/log-level", map[string]string{"level": "info"})
+		resp.Body.Close()
+	})
+
+	resp := client.PUT("/admin/log-level", map[string]string{"level": "debug"})
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	changed := ParseResponse[struct {
+		Code string `json:"code"`
+		Data struct {
+			Level string `json:"level"`
+		} `json:"data"`
+	}](t, resp)
+	assert.Equal(t, "LOG_LEVEL_CHANGED", changed.Code)
+	assert.Equal(t, "debug", changed.Data.Level)
+
+	current := client.GET("/admin/log-level")
+	defer current.Body.Close()
+	require.Equal(t, http.StatusOK, current.StatusCode)
+	found := ParseResponse[struct {
+		Data struct {
+			Level string `json:"level"`
+		} `json:"data"`
+	}](t, current)
+	assert.Equal(t, "debug", found.Data.Level)
+}
+
+func TestAdminLogLevel_UnknownLevel(t *testing.T) {
+	client := NewTestClient(t)
+
+	resp := client.PUT("/admin/log-level", map[string]string{"level": "verbose"})
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+// =============================================================================
+// Audit
+// =============================================================================
+
+func TestAdminAudit_RecordsActions(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	key := "audit-" + uuid.New().String()
+	resp := client.POST("/admin/faults", map[string]any{
+		"type":      "ERROR",
+		"route":     "GET /entries/{key}",
+		"keySuffix": key,
+	})
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	correlationID := resp.Header.Get("X-Correlation-Id")
+
+	created := ParseResponse[struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}](t, resp)
+	deleted := client.Request(http.MethodDelete, "/admin/faults/"+created.Data.ID, nil, nil)
+	deleted.Body.Close()
+	require.Equal(t, http.StatusOK, deleted.StatusCode)
+
+	list := client.GET("/admin/audit?action=admin.faults.create&limit=1000")
+	defer list.Body.Close()
+	require.Equal(t, http.StatusOK, list.StatusCode)
+
+	page := ParseResponse[struct {
+		Code string `json:"code"`
+		Data struct {
+			Records []struct {
+				Actor         string         `json:"actor"`
+				Action        string         `json:"action"`
+				Parameters    map[string]any `json:"parameters"`
+				Status        int            `json:"status"`
+				CorrelationID string         `json:"correlationId"`
+			} `json:"records"`
+		} `json:"data"`
+	}](t, list)
+	assert.Equal(t, "ADMIN_AUDIT_LISTED", page.Code)
+
+	found := false
+	for _, record := range page.Data.Records {
+		assert.Equal(t, "admin.faults.create", record.Action)
+		if record.CorrelationID == correlationID {
+			found = true
+			assert.Equal(t, "admin-token", record.Actor)
+			assert.Equal(t, http.StatusCreated, record.Status)
+			assert.Equal(t, key, record.Parameters["keySuffix"])
+		}
+	}
+	assert.True(t, found, "the fault creation is recorded")
+}
+
+func TestAdminAudit_InvalidCursor(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	resp := client.GET("/admin/audit?cursor=not-an-id")
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+// =============================================================================
+// Usage reports
+// =============================================================================
+
+func TestAdminUsageReport_CountsParticipantRequests(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	participant := "87654321"
+	headers := map[string]string{middleware.IdentifierHeader: participant}
+	for range 3 {
+		resp := client.GETWithHeaders("/entries/"+GenerateValidCPF(), headers)
+		resp.Body.Close()
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	}
+
+	resp := client.GET("/admin/reports/usage?participant=" + participant)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	report := ParseResponse[struct {
+		Code string `json:"code"`
+		Data []struct {
+			Participant  string  `json:"participant"`
+			Requests     int     `json:"requests"`
+			ClientErrors int     `json:"clientErrors"`
+			ErrorRate    float64 `json:"errorRate"`
+		} `json:"data"`
+	}](t, resp)
+	assert.Equal(t, "USAGE_REPORTED", report.Code)
+	require.Len(t, report.Data, 1)
+	assert.Equal(t, participant, report.Data[0].Participant)
+	assert.Equal(t, 3, report.Data[0].Requests)
+	assert.Equal(t, 3, report.Data[0].ClientErrors)
+	assert.Equal(t, 1.0, report.Data[0].ErrorRate)
+}
+
+func TestAdminUsageReport_InvalidDay(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	resp := client.GET("/admin/reports/usage?from=2024-13-01")
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
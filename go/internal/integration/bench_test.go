@@ -0,0 +1,240 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/ratelimit"
+)
+
+// Benchmarks against the test containers: go test -run '^$' -bench . ./internal/integration
+// Besides ns/op they report p50 and p99 latencies, which the mean hides for network round trips.
+
+// latencies records per-operation durations of a benchmark
+type latencies []time.Duration
+
+// time runs op and records how long it took
+func (l *latencies) time(op func()) {
+	start := time.Now()
+	op()
+	*l = append(*l, time.Since(start))
+}
+
+// report adds the p50 and p99 latencies to the benchmark's results
+func (l latencies) report(b *testing.B) {
+	if len(l) == 0 {
+		return
+	}
+	slices.Sort(l)
+	b.ReportMetric(float64(l[len(l)*50/100].Nanoseconds()), "p50-ns")
+	b.ReportMetric(float64(l[len(l)*99/100].Nanoseconds()), "p99-ns")
+}
+
+// benchmarkEntryRequest builds a distinct EMAIL entry request for the i-th benchmark iteration
+func benchmarkEntryRequest(run string, i int) *models.CreateEntryRequest {
+	return &models.CreateEntryRequest{
+		Key:     fmt.Sprintf("bench-%s-%d@example.com", run, i),
+		KeyType: models.KeyTypeEMAIL,
+		Account: models.Account{
+			Participant:   "12345678",
+			Branch:        "0001",
+			AccountNumber: fmt.Sprintf("%010d", i),
+			AccountType:   "CACC",
+			OpeningDate:   time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC),
+		},
+		Owner: models.Owner{
+			Type:        models.OwnerTypeNaturalPerson,
+			TaxIdNumber: "11144477735",
+			Name:        "Benchmark User",
+		},
+		Reason:    models.ReasonUserRequested,
+		RequestId: uuid.New().String(),
+	}
+}
+
+// benchmarkEntryRepository measures Create and FindByKey on a repository
+func benchmarkEntryRepository(b *testing.B, repo models.EntryRepository) {
+	ctx := context.Background()
+	run := uuid.New().String()[:8]
+
+	b.Run("Create", func(b *testing.B) {
+		var l latencies
+		i := 0
+		for b.Loop() {
+			req := benchmarkEntryRequest(run, i)
+			l.time(func() {
+				if _, err := repo.Create(ctx, req); err != nil {
+					b.Fatal(err)
+				}
+			})
+			i++
+		}
+		l.report(b)
+	})
+
+	b.Run("FindByKey", func(b *testing.B) {
+		const stored = 1000
+		for i := range stored {
+			if _, err := repo.Create(ctx, benchmarkEntryRequest(run+"-find", i)); err != nil {
+				b.Fatal(err)
+			}
+		}
+
+		var l latencies
+		i := 0
+		for b.Loop() {
+			key := fmt.Sprintf("bench-%s-find-%d@example.com", run, i%stored)
+			l.time(func() {
+				if entry, err := repo.FindByKey(ctx, key); err != nil || entry == nil {
+					b.Fatalf("FindByKey(%q) = %v, %v", key, entry, err)
+				}
+			})
+			i++
+		}
+		l.report(b)
+	})
+}
+
+func BenchmarkMongoEntryRepository(b *testing.B) {
+	isolatedMongo := testMongoDB.WithDatabase("test_dict_bench_" + uuid.New().String())
+	b.Cleanup(func() { isolatedMongo.Database.Drop(context.Background()) })
+
+	repo := models.NewMongoEntryRepository(isolatedMongo, clock.System)
+	if err := repo.EnsureIndexes(context.Background()); err != nil {
+		b.Fatal(err)
+	}
+	benchmarkEntryRepository(b, repo)
+}
+
+func BenchmarkPostgresEntryRepository(b *testing.B) {
+	pg := createTestPostgres(b, "test_dict_bench_"+uuid.New().String()[:8])
+	benchmarkEntryRepository(b, models.NewPostgresEntryRepository(pg, clock.System))
+}
+
+// BenchmarkRedisBucket measures the Lua scripts behind each rate-limited request: a Check before
+// the handler runs and a Consume after it, for each algorithm
+func BenchmarkRedisBucket(b *testing.B) {
+	for _, algorithm := range []ratelimit.Algorithm{ratelimit.AlgorithmTokenBucket, ratelimit.AlgorithmSlidingWindow, ratelimit.AlgorithmGCRA} {
+		b.Run(string(algorithm), func(b *testing.B) {
+			ctx := context.Background()
+			bucket := ratelimit.NewBucket(testRedisDB.Client, clock.System)
+			policy := ratelimit.Policy{
+				Name:        "BENCH",
+				BucketSize:  1 << 30,
+				RefillRate:  1 << 20, // refills within a day, so windows fit in a time.Duration
+				SuccessCost: 1,
+				DefaultCost: 1,
+				Algorithm:   algorithm,
+			}
+			identifier := "bench-" + uuid.New().String()
+			b.Cleanup(func() { bucket.Flush(ctx, identifier) })
+
+			var l latencies
+			for b.Loop() {
+				l.time(func() {
+					if _, err := bucket.Check(ctx, policy, identifier); err != nil {
+						b.Fatal(err)
+					}
+					if err := bucket.Consume(ctx, policy, identifier, http.StatusOK); err != nil {
+						b.Fatal(err)
+					}
+				})
+			}
+			l.report(b)
+		})
+	}
+}
+
+// scriptTraffic is a Redis hook counting the bytes of the commands a client sends and the
+// NOSCRIPT replies it gets
+type scriptTraffic struct {
+	sent      atomic.Int64
+	noScripts atomic.Int64
+}
+
+func (h *scriptTraffic) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h *scriptTraffic) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func (h *scriptTraffic) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		for _, arg := range cmd.Args() {
+			h.sent.Add(int64(len(fmt.Sprint(arg))))
+		}
+		err := next(ctx, cmd)
+		if redis.HasErrorPrefix(err, "NOSCRIPT") {
+			h.noScripts.Add(1)
+		}
+		return err
+	}
+}
+
+// BenchmarkRedisBucketScripts compares buckets whose scripts were loaded at startup (preloaded)
+// with buckets meeting an empty script cache, as redis.Script.Run did on its first calls (run),
+// with requests from every CPU at once. Both send scripts by SHA1 once cached, so sent-B/op (what
+// a Check and a Consume write to Redis) barely differs; preloading only saves the NOSCRIPT round
+// trip and EVAL of each script's first call, counted by noscripts.
+func BenchmarkRedisBucketScripts(b *testing.B) {
+	for _, preload := range []bool{false, true} {
+		name := "run"
+		if preload {
+			name = "preloaded"
+		}
+		b.Run(name, func(b *testing.B) {
+			ctx := context.Background()
+			traffic := &scriptTraffic{}
+			client := redis.NewClient(testRedisDB.Client.Options())
+			client.AddHook(traffic)
+			b.Cleanup(func() { client.Close() })
+
+			bucket := ratelimit.NewBucket(client, clock.System)
+			if err := client.ScriptFlush(ctx).Err(); err != nil {
+				b.Fatal(err)
+			}
+			if preload {
+				if err := bucket.LoadScripts(ctx); err != nil {
+					b.Fatal(err)
+				}
+			}
+			policy := ratelimit.Policy{
+				Name:        "BENCH",
+				BucketSize:  1 << 30,
+				RefillRate:  1 << 20,
+				SuccessCost: 1,
+				DefaultCost: 1,
+				Algorithm:   ratelimit.AlgorithmTokenBucket,
+			}
+			identifier := "bench-" + uuid.New().String()
+			b.Cleanup(func() { bucket.Flush(ctx, identifier) })
+
+			traffic.sent.Store(0)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := bucket.Check(ctx, policy, identifier); err != nil {
+						b.Error(err)
+						return
+					}
+					if err := bucket.Consume(ctx, policy, identifier, http.StatusOK); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+			b.ReportMetric(float64(traffic.sent.Load())/float64(b.N), "sent-B/op")
+			b.ReportMetric(float64(traffic.noScripts.Load()), "noscripts")
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/testkit"
+)
+
+// Run these with the race detector, so a data race in the handlers fails them too:
+//
+//	go test -race -run TestConcurrency ./internal/integration/...
+
+// concurrentRequests is how many requests each race sends at once
+const concurrentRequests = 20
+
+// concurrencyServers are the storages the races run against: each enforces key uniqueness and
+// idempotency claims its own way
+var concurrencyServers = map[string]func(t *testing.T) *TestClient{
+	"mongo":    NewTestClient,
+	"postgres": func(t *testing.T) *TestClient { return NewTestClientForServer(t, StartPostgresServer(t)) },
+	"memory":   func(t *testing.T) *TestClient { return NewTestClientForServer(t, StartMemoryServer(t)) },
+}
+
+// raceClient sends the concurrent requests; its connections aren't shared with TestClient's
+var raceClient = &http.Client{Timeout: 10 * time.Second}
+
+// errorsAre holds when every response with status carries the error code
+func errorsAre(status int, code string) testkit.Invariant {
+	return func(rs testkit.Results) error {
+		for _, r := range rs.WithStatus(status) {
+			var body struct {
+				Error string `json:"error"`
+			}
+			if err := json.Unmarshal(r.Body, &body); err != nil || body.Error != code {
+				return fmt.Errorf("request %d answered %d %s, want %s", r.Index, status, r.Body, code)
+			}
+		}
+		return nil
+	}
+}
+
+// entryStatus returns the status of GET /entries/{key}
+func entryStatus(client *TestClient, key string) int {
+	resp := client.GET("/entries/" + key)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestConcurrency_CreateSameKey(t *testing.T) {
+	t.Parallel()
+
+	for name, start := range concurrencyServers {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := start(t)
+			key := GenerateValidCPF()
+
+			// Separate requests: each has its own idempotency key and requestId
+			rs := testkit.Run(raceClient, testkit.Repeat(concurrentRequests, func(int) *http.Request {
+				return client.NewRequest(http.MethodPost, "/entries", CreateEntryRequest(key), map[string]string{
+					"X-Idempotency-Key": uuid.New().String(),
+				})
+			}))
+
+			testkit.Check(t, rs,
+				testkit.ExactlyOne(http.StatusCreated),
+				testkit.OnlyStatuses(http.StatusCreated, http.StatusConflict),
+				errorsAre(http.StatusConflict, constants.CodeKeyAlreadyExists),
+			)
+			assert.Equal(t, http.StatusOK, entryStatus(client, key))
+		})
+	}
+}
+
+func TestConcurrency_SameIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	for name, start := range concurrencyServers {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := start(t)
+			key := GenerateValidCPF()
+			body := CreateEntryRequest(key)
+			idempotencyKey := uuid.New().String()
+
+			// One request sent many times: only one may reach the handler
+			rs := testkit.Run(raceClient, testkit.Repeat(concurrentRequests, func(int) *http.Request {
+				return client.NewRequest(http.MethodPost, "/entries", body, map[string]string{
+					"X-Idempotency-Key": idempotencyKey,
+				})
+			}))
+
+			// A copy arriving before the first one finished is refused, a later one gets its response
+			// replayed; none runs again, which would answer KEY_ALREADY_EXISTS or REQUEST_ID_ALREADY_USED
+			testkit.Check(t, rs,
+				testkit.OnlyStatuses(http.StatusCreated, http.StatusConflict),
+				errorsAre(http.StatusConflict, constants.CodeIdempotencyKeyInUse),
+			)
+			created := rs.WithStatus(http.StatusCreated)
+			require.NotEmpty(t, created, "the request that claimed the key answers 201")
+			testkit.Check(t, created, testkit.SameResponse())
+
+			// Retried once the race is over, the request is a replay
+			retried := client.POSTWithHeaders("/entries", body, map[string]string{"X-Idempotency-Key": idempotencyKey})
+			defer retried.Body.Close()
+			assert.Equal(t, http.StatusCreated, retried.StatusCode)
+		})
+	}
+}
+
+func TestConcurrency_DeleteSameKey(t *testing.T) {
+	t.Parallel()
+
+	for name, start := range concurrencyServers {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := start(t)
+			key := client.CreateEntry()
+
+			rs := testkit.Run(raceClient, testkit.Repeat(concurrentRequests, func(int) *http.Request {
+				return client.NewRequest(http.MethodPost, "/entries/"+key+"/delete", map[string]string{
+					"key":         key,
+					"participant": "12345678",
+					"reason":      "USER_REQUESTED",
+				}, nil)
+			}))
+
+			testkit.Check(t, rs,
+				testkit.ExactlyOne(http.StatusOK),
+				testkit.OnlyStatuses(http.StatusOK, http.StatusNotFound),
+				errorsAre(http.StatusNotFound, constants.CodeEntryNotFound),
+			)
+			assert.Equal(t, http.StatusNotFound, entryStatus(client, key))
+		})
+	}
+}
+
+func TestConcurrency_CreateDeleteRace(t *testing.T) {
+	t.Parallel()
+
+	for name, start := range concurrencyServers {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := start(t)
+			key := client.CreateEntry()
+
+			// Deletes and creations of the same key, interleaved
+			rs := testkit.Run(raceClient, testkit.Repeat(concurrentRequests, func(i int) *http.Request {
+				if i%2 == 0 {
+					return client.NewRequest(http.MethodPost, "/entries/"+key+"/delete", map[string]string{
+						"key":         key,
+						"participant": "12345678",
+						"reason":      "USER_REQUESTED",
+					}, nil)
+				}
+				return client.NewRequest(http.MethodPost, "/entries", CreateEntryRequest(key), map[string]string{
+					"X-Idempotency-Key": uuid.New().String(),
+				})
+			}))
+
+			testkit.Check(t, rs,
+				testkit.OnlyStatuses(http.StatusOK, http.StatusCreated, http.StatusNotFound, http.StatusConflict),
+				errorsAre(http.StatusNotFound, constants.CodeEntryNotFound),
+				errorsAre(http.StatusConflict, constants.CodeKeyAlreadyExists),
+			)
+
+			// The key existed, so deletions and creations alternate: the entry exists afterwards
+			// exactly when there was one creation more than deletions
+			deleted, created := rs.Count(http.StatusOK), rs.Count(http.StatusCreated)
+			require.Contains(t, []int{0, 1}, 1-deleted+created, "%d deletions and %d creations succeeded", deleted, created)
+			want := http.StatusNotFound
+			if 1-deleted+created == 1 {
+				want = http.StatusOK
+			}
+			assert.Equal(t, want, entryStatus(client, key), "%d deletions and %d creations succeeded", deleted, created)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dict-simulator/go/internal/config"
+)
+
+func TestDocs_ServesSpec(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	resp := client.GET("/openapi.json")
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	var spec struct {
+		Swagger string                    `json:"swagger"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&spec))
+
+	assert.Equal(t, "2.0", spec.Swagger)
+	assert.Contains(t, spec.Paths, "/entries")
+	assert.Contains(t, spec.Paths, "/webhooks")
+	assert.Contains(t, spec.Paths, "/admin/seed")
+}
+
+func TestDocs_ServesUI(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	resp := client.GET("/docs/index.html")
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestDocs_LegacySwaggerPathRedirects(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	httpClient := &http.Client{
+		Timeout: 10 * time.Second,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := httpClient.Get(client.baseURL + "/swagger/index.html")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusMovedPermanently, resp.StatusCode)
+	assert.Equal(t, "/docs/", resp.Header.Get("Location"))
+}
+
+func TestDocs_DisabledReturnsNotFound(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Port:                   3000,
+		Environment:            "production",
+		JWTSecret:              "test-jwt-secret-for-integration-tests",
+		RateLimitBucketSize:    60,
+		RateLimitRefillSeconds: 60,
+	}
+	server := createTestServer(t, cfg, "test_dict_nodocs_"+uuid.New().String())
+	client := NewTestClientForServer(t, server)
+
+	for _, path := range []string{"/openapi.json", "/docs/index.html"} {
+		resp := client.GET(path)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, path)
+	}
+}
@@ -2,7 +2,9 @@ package integration
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -83,6 +85,86 @@ func TestGetEntry(t *testing.T) {
 	assert.Equal(t, "12345678", apiResp.Data.Account.Participant)
 }
 
+func TestGetEntry_Conditional(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+	cpf := client.CreateEntry()
+	defer client.CleanupEntry(cpf)
+
+	resp := client.GET("/entries/" + cpf)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	require.NotEmpty(t, etag)
+	require.NotEmpty(t, lastModified)
+
+	// Unchanged entries answer 304 with no body
+	for name, headers := range map[string]map[string]string{
+		"If-None-Match":     {"If-None-Match": etag},
+		"If-Modified-Since": {"If-Modified-Since": lastModified},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := client.GETWithHeaders("/entries/"+cpf, headers)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+			assert.Equal(t, etag, resp.Header.Get("ETag"))
+			body, _ := io.ReadAll(resp.Body)
+			assert.Empty(t, body)
+		})
+	}
+
+	update := client.PUT("/entries/"+cpf, map[string]any{
+		"key":         cpf,
+		"participant": "12345678",
+		"owner":       map[string]any{"name": "Updated User Name"},
+		"reason":      "USER_REQUESTED",
+	})
+	defer update.Body.Close()
+	require.Equal(t, http.StatusOK, update.StatusCode)
+
+	// The old ETag no longer matches
+	stale := client.GETWithHeaders("/entries/"+cpf, map[string]string{"If-None-Match": etag})
+	defer stale.Body.Close()
+	assert.Equal(t, http.StatusOK, stale.StatusCode)
+	assert.NotEqual(t, etag, stale.Header.Get("ETag"))
+}
+
+func TestGetEntry_MasksOwnerForOtherParticipants(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+	cpf := client.CreateEntry()
+	defer client.CleanupEntry(cpf)
+
+	get := func(headers map[string]string) models.Owner {
+		resp := client.GETWithHeaders("/entries/"+cpf, headers)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		return ParseResponse[struct {
+			Data models.EntryResponse `json:"data"`
+		}](t, resp).Data.Owner
+	}
+
+	own := get(map[string]string{"X-Participant-Id": "12345678"})
+	assert.Equal(t, cpf, own.TaxIdNumber)
+	assert.Equal(t, "Test User", own.Name)
+
+	masked := get(map[string]string{"X-Participant-Id": "87654321"})
+	assert.Equal(t, "***"+cpf[3:9]+"**", masked.TaxIdNumber)
+	assert.Equal(t, "T. U.", masked.Name)
+
+	forPayment := get(map[string]string{"X-Participant-Id": "87654321", "PI-PayerId": GenerateValidCPF()})
+	assert.Equal(t, cpf, forPayment.TaxIdNumber)
+	assert.Equal(t, "Test User", forPayment.Name)
+
+	invalid := client.GETWithHeaders("/entries/"+cpf, map[string]string{"PI-PayerId": "123"})
+	defer invalid.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, invalid.StatusCode)
+}
+
 func TestGetEntry_NotFound(t *testing.T) {
 	t.Parallel()
 
@@ -112,7 +194,8 @@ func TestUpdateEntry(t *testing.T) {
 
 	// Update the entry
 	updateReq := map[string]any{
-		"key": cpf,
+		"key":         cpf,
+		"participant": "12345678",
 		"owner": map[string]any{
 			"name": "Updated User Name",
 		},
@@ -165,6 +248,39 @@ func TestDeleteEntry(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, getResp.StatusCode)
 }
 
+func TestUpdateEntry_WrongParticipant(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	cpf := client.CreateEntry()
+	defer client.CleanupEntry(cpf)
+
+	resp := client.PUT("/entries/"+cpf, map[string]any{
+		"key":         cpf,
+		"participant": "87654321",
+		"owner": map[string]any{
+			"name": "Someone Else",
+		},
+		"reason": "USER_REQUESTED",
+	})
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	apiResp := ParseResponse[struct {
+		Error string `json:"error"`
+	}](t, resp)
+	assert.Equal(t, "FORBIDDEN", apiResp.Error)
+
+	// The entry is unchanged; its own participant sees the owner unmasked
+	get := client.GETWithHeaders("/entries/"+cpf, map[string]string{"X-Participant-Id": "12345678"})
+	defer get.Body.Close()
+	found := ParseResponse[struct {
+		Data models.EntryResponse `json:"data"`
+	}](t, get)
+	assert.Equal(t, "Test User", found.Data.Owner.Name)
+}
+
 // =============================================================================
 // Validation Tests
 // =============================================================================
@@ -202,6 +318,49 @@ func TestCreateEntry_InvalidCPF(t *testing.T) {
 	}
 }
 
+func TestCreateEntry_MalformedPayloads(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	withExtraField := CreateEntryRequest(GenerateValidCPF())
+	withExtraField["priority"] = "HIGH"
+
+	oversized := CreateEntryRequest(GenerateValidCPF())
+	oversized["owner"].(map[string]any)["name"] = strings.Repeat("x", 2<<20)
+
+	testCases := []struct {
+		name        string
+		body        any
+		wantStatus  int
+		wantError   string
+		wantMessage string
+	}{
+		{"unknown field", withExtraField, http.StatusBadRequest, "INVALID_REQUEST", `Unknown field "priority"`},
+		{"wrong type", map[string]any{"key": 12345678901}, http.StatusBadRequest, "INVALID_REQUEST", `Field "key" must be a string`},
+		{"over the size limit", oversized, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE", "Request body is too large (limit is 1048576 bytes)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := client.POSTWithHeaders("/entries", tc.body, map[string]string{
+				"X-Idempotency-Key": uuid.New().String(),
+			})
+			defer resp.Body.Close()
+
+			assert.Equal(t, tc.wantStatus, resp.StatusCode)
+
+			var apiResp struct {
+				Error   string `json:"error"`
+				Message string `json:"message"`
+			}
+			json.NewDecoder(resp.Body).Decode(&apiResp)
+			assert.Equal(t, tc.wantError, apiResp.Error)
+			assert.Equal(t, tc.wantMessage, apiResp.Message)
+		})
+	}
+}
+
 func TestCreateEntry_InvalidEmail(t *testing.T) {
 	t.Parallel()
 
@@ -211,7 +370,6 @@ func TestCreateEntry_InvalidEmail(t *testing.T) {
 		name  string
 		email string
 	}{
-		{"uppercase", "Test@Example.com"},
 		{"no @", "testexample.com"},
 		{"no domain", "test@"},
 	}
@@ -298,6 +456,156 @@ func TestCreateEntry_InvalidPhone(t *testing.T) {
 	}
 }
 
+func TestCreateEntry_InconsistentOwnership(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+	cpf := GenerateValidCPF()
+
+	req := CreateEntryRequest(cpf)
+	req["owner"] = map[string]any{
+		"type":        "NATURAL_PERSON",
+		"taxIdNumber": GenerateValidCPF(),
+		"name":        "Test User",
+		"tradeName":   "Test Store",
+	}
+
+	resp := client.POSTWithHeaders("/entries", req, map[string]string{
+		"X-Idempotency-Key": uuid.New().String(),
+	})
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	apiResp := ParseResponse[struct {
+		Error      string `json:"error"`
+		Violations []struct {
+			Field   string `json:"field"`
+			Message string `json:"message"`
+		} `json:"violations"`
+	}](t, resp)
+	assert.Equal(t, "INCONSISTENT_OWNERSHIP", apiResp.Error)
+
+	var fields []string
+	for _, v := range apiResp.Violations {
+		fields = append(fields, v.Field)
+	}
+	assert.ElementsMatch(t, []string{"key", "owner.tradeName"}, fields)
+
+	// Nothing was registered
+	get := client.GET("/entries/" + cpf)
+	defer get.Body.Close()
+	assert.Equal(t, http.StatusNotFound, get.StatusCode)
+}
+
+// =============================================================================
+// Key Normalization
+// =============================================================================
+
+func TestEntry_NormalizedKey(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+	email := "Test." + uuid.New().String()[:8] + "@Example.com"
+
+	req := CreateEntryRequest(GenerateValidCPF())
+	req["key"] = email
+	req["keyType"] = "EMAIL"
+	resp := client.POSTWithHeaders("/entries", req, map[string]string{
+		"X-Idempotency-Key": uuid.New().String(),
+	})
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	// The key is returned as submitted but found in any case
+	get := client.GET("/entries/" + strings.ToLower(email))
+	defer get.Body.Close()
+	require.Equal(t, http.StatusOK, get.StatusCode)
+	found := ParseResponse[struct {
+		Data models.EntryResponse `json:"data"`
+	}](t, get)
+	assert.Equal(t, email, found.Data.Key)
+
+	// Another case of the same email is the same key
+	req["key"] = strings.ToUpper(email)
+	req["requestId"] = uuid.New().String()
+	duplicate := client.POSTWithHeaders("/entries", req, map[string]string{
+		"X-Idempotency-Key": uuid.New().String(),
+	})
+	defer duplicate.Body.Close()
+	assert.Equal(t, http.StatusConflict, duplicate.StatusCode)
+
+	deleted := client.DeleteEntry(strings.ToUpper(email), "12345678", "USER_REQUESTED")
+	defer deleted.Body.Close()
+	assert.Equal(t, http.StatusOK, deleted.StatusCode)
+}
+
+func TestEntry_FormattedPhone(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+	digits := GenerateValidCPF()[:9]
+	formatted := "+55 (11) " + digits[:5] + "-" + digits[5:]
+
+	req := CreateEntryRequest(GenerateValidCPF())
+	req["key"] = formatted
+	req["keyType"] = "PHONE"
+	resp := client.POSTWithHeaders("/entries", req, map[string]string{
+		"X-Idempotency-Key": uuid.New().String(),
+	})
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	get := client.GET("/entries/+5511" + digits)
+	defer get.Body.Close()
+	assert.Equal(t, http.StatusOK, get.StatusCode)
+
+	client.CleanupEntry("+5511" + digits)
+}
+
+// =============================================================================
+// Key Pre-validation
+// =============================================================================
+
+func TestValidateKey(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+	registered := client.CreateEntry()
+	defer client.CleanupEntry(registered)
+
+	tests := []struct {
+		name    string
+		key     string
+		keyType string
+		valid   bool
+		code    string
+	}{
+		{name: "available CPF", key: GenerateValidCPF(), keyType: "CPF", valid: true},
+		{name: "registered CPF", key: registered, keyType: "CPF", code: "KEY_ALREADY_EXISTS"},
+		{name: "bad check digits", key: "12345678900", keyType: "CPF", code: "INVALID_CPF"},
+		{name: "email without domain", key: "someone@", keyType: "EMAIL", code: "INVALID_EMAIL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := client.POST("/keys/validate", map[string]string{"key": tt.key, "keyType": tt.keyType})
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			result := ParseResponse[struct {
+				Data models.KeyValidationResponse `json:"data"`
+			}](t, resp)
+			assert.Equal(t, tt.valid, result.Data.Valid)
+			assert.Equal(t, tt.code, result.Data.Error)
+		})
+	}
+
+	// Nothing was registered by validating
+	resp := client.GET("/entries/" + tests[0].key)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
 // =============================================================================
 // EVP Key Restrictions
 // =============================================================================
@@ -338,7 +646,8 @@ func TestEVPKey_CannotBeUpdated(t *testing.T) {
 
 	// Try to update - should fail
 	updateReq := map[string]any{
-		"key": evpKey,
+		"key":         evpKey,
+		"participant": "12345678",
 		"owner": map[string]any{
 			"name": "New Name",
 		},
@@ -423,6 +732,327 @@ func TestDeleteEntry_ValidReasons(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// Delete Reasons
+// =============================================================================
+
+// fraudMarkers lists the fraud markers left on key
+func fraudMarkers(t *testing.T, client *TestClient, key string) []models.FraudMarkerResponse {
+	t.Helper()
+
+	resp := client.GET("/entries/" + key + "/fraud-markers")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	return ParseResponse[struct {
+		Data []models.FraudMarkerResponse `json:"data"`
+	}](t, resp).Data
+}
+
+func TestDeleteEntry_FraudLeavesMarker(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+	cpf := client.CreateEntry()
+	assert.Empty(t, fraudMarkers(t, client, cpf))
+
+	resp := client.DeleteEntry(cpf, "12345678", "FRAUD")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	markers := fraudMarkers(t, client, cpf)
+	require.Len(t, markers, 1)
+	assert.Equal(t, cpf, markers[0].Key)
+	assert.Equal(t, cpf, markers[0].TaxIdNumber)
+	assert.Equal(t, "12345678", markers[0].Participant)
+
+	// The marker outlives the entry: the key registered again still carries it
+	created := client.POSTWithHeaders("/entries", CreateEntryRequest(cpf), map[string]string{
+		"X-Idempotency-Key": uuid.New().String(),
+	})
+	defer created.Body.Close()
+	require.Equal(t, http.StatusCreated, created.StatusCode)
+	assert.Len(t, fraudMarkers(t, client, cpf), 1)
+}
+
+func TestDeleteEntry_AccountClosureReleasesAccountKeys(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+	closing := client.CreateEntry()
+	sameAccount := client.CreateEntry()
+
+	otherAccount := GenerateValidCPF()
+	req := CreateEntryRequest(otherAccount)
+	req["account"].(map[string]any)["accountNumber"] = "0001111111"
+	created := client.POSTWithHeaders("/entries", req, map[string]string{
+		"X-Idempotency-Key": uuid.New().String(),
+	})
+	defer created.Body.Close()
+	require.Equal(t, http.StatusCreated, created.StatusCode)
+	defer client.CleanupEntry(otherAccount)
+
+	resp := client.DeleteEntry(closing, "12345678", "ACCOUNT_CLOSURE")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	deleted := ParseResponse[struct {
+		Data models.DeleteEntryResponse `json:"data"`
+	}](t, resp)
+	assert.Equal(t, closing, deleted.Data.Key)
+	assert.Equal(t, []string{sameAccount}, deleted.Data.ReleasedKeys)
+
+	for key, want := range map[string]int{
+		closing:      http.StatusNotFound,
+		sameAccount:  http.StatusNotFound,
+		otherAccount: http.StatusOK,
+	} {
+		get := client.GET("/entries/" + key)
+		get.Body.Close()
+		assert.Equal(t, want, get.StatusCode, "GET /entries/%s", key)
+	}
+}
+
+func TestDeleteEntry_ReasonsWithoutSideEffects(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	for _, reason := range []string{"USER_REQUESTED", "RECONCILIATION", "RFB_VALIDATION"} {
+		t.Run(reason, func(t *testing.T) {
+			deleting := client.CreateEntry()
+			sameAccount := client.CreateEntry()
+			defer client.CleanupEntry(sameAccount)
+
+			resp := client.DeleteEntry(deleting, "12345678", reason)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			deleted := ParseResponse[struct {
+				Data models.DeleteEntryResponse `json:"data"`
+			}](t, resp)
+			assert.Empty(t, deleted.Data.ReleasedKeys)
+			assert.Empty(t, fraudMarkers(t, client, deleting))
+
+			get := client.GET("/entries/" + sameAccount)
+			defer get.Body.Close()
+			assert.Equal(t, http.StatusOK, get.StatusCode)
+		})
+	}
+}
+
+// =============================================================================
+// Account Closure
+// =============================================================================
+
+// createEntryInAccount registers a new CPF entry linked to accountNumber of participant 12345678, branch 0001
+func createEntryInAccount(t *testing.T, client *TestClient, accountNumber string) string {
+	t.Helper()
+
+	cpf := GenerateValidCPF()
+	req := CreateEntryRequest(cpf)
+	req["account"].(map[string]any)["accountNumber"] = accountNumber
+	resp := client.POSTWithHeaders("/entries", req, map[string]string{
+		"X-Idempotency-Key": uuid.New().String(),
+	})
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	return cpf
+}
+
+func TestCloseAccount_DeletesAccountKeys(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+	first := createEntryInAccount(t, client, "0002222222")
+	second := createEntryInAccount(t, client, "0002222222")
+	otherAccount := createEntryInAccount(t, client, "0003333333")
+	defer client.CleanupEntry(otherAccount)
+
+	resp := client.POST("/accounts/12345678/0001/0002222222/close", map[string]any{})
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	closed := ParseResponse[struct {
+		Code string                      `json:"code"`
+		Data models.CloseAccountResponse `json:"data"`
+	}](t, resp)
+	assert.Equal(t, "ACCOUNT_CLOSED", closed.Code)
+	assert.ElementsMatch(t, []string{first, second}, closed.Data.Keys)
+	assert.Nil(t, closed.Data.TransferredTo)
+
+	for key, want := range map[string]int{
+		first:        http.StatusNotFound,
+		second:       http.StatusNotFound,
+		otherAccount: http.StatusOK,
+	} {
+		get := client.GET("/entries/" + key)
+		get.Body.Close()
+		assert.Equal(t, want, get.StatusCode, "GET /entries/%s", key)
+	}
+
+	// Nothing is left to close
+	again := client.POST("/accounts/12345678/0001/0002222222/close", map[string]any{})
+	defer again.Body.Close()
+	assert.Equal(t, http.StatusNotFound, again.StatusCode)
+}
+
+func TestCloseAccount_TransfersAccountKeys(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+	first := createEntryInAccount(t, client, "0004444444")
+	second := createEntryInAccount(t, client, "0004444444")
+	defer client.CleanupEntry(first)
+	defer client.CleanupEntry(second)
+
+	resp := client.POST("/accounts/12345678/0001/0004444444/close", map[string]any{
+		"transferTo": map[string]any{
+			"branch":        "0002",
+			"accountNumber": "0005555555",
+			"accountType":   "SVGS",
+			"openingDate":   "2024-06-01T00:00:00Z",
+		},
+	})
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	closed := ParseResponse[struct {
+		Data models.CloseAccountResponse `json:"data"`
+	}](t, resp)
+	assert.ElementsMatch(t, []string{first, second}, closed.Data.Keys)
+	require.NotNil(t, closed.Data.TransferredTo)
+
+	for _, key := range []string{first, second} {
+		get := client.GET("/entries/" + key)
+		defer get.Body.Close()
+		require.Equal(t, http.StatusOK, get.StatusCode)
+
+		entry := ParseResponse[struct {
+			Data models.EntryResponse `json:"data"`
+		}](t, get)
+		assert.Equal(t, "12345678", entry.Data.Account.Participant)
+		assert.Equal(t, "0002", entry.Data.Account.Branch)
+		assert.Equal(t, "0005555555", entry.Data.Account.AccountNumber)
+		assert.Equal(t, models.AccountType("SVGS"), entry.Data.Account.AccountType)
+	}
+}
+
+func TestCloseAccount_InvalidTransfer(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+	key := createEntryInAccount(t, client, "0006666666")
+	defer client.CleanupEntry(key)
+
+	resp := client.POST("/accounts/12345678/0001/0006666666/close", map[string]any{
+		"transferTo": map[string]any{"branch": "2"},
+	})
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// The rejected request left the key where it was
+	get := client.GET("/entries/" + key)
+	defer get.Body.Close()
+	assert.Equal(t, http.StatusOK, get.StatusCode)
+}
+
+// =============================================================================
+// Participant Listing
+// =============================================================================
+
+// listEntries reads one page of GET /participants/12345678/entries
+func listEntries(t *testing.T, client *TestClient, query string) models.EntryPage {
+	t.Helper()
+
+	resp := client.GET("/participants/12345678/entries" + query)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	return ParseResponse[struct {
+		Data models.EntryPage `json:"data"`
+	}](t, resp).Data
+}
+
+func TestListParticipantEntries_Pages(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+	created := []string{client.CreateEntry(), client.CreateEntry(), client.CreateEntry()}
+	for _, key := range created {
+		defer client.CleanupEntry(key)
+	}
+
+	var listed []string
+	query := "?limit=2"
+	for pages := 1; ; pages++ {
+		require.LessOrEqual(t, pages, 2, "three entries fit in two pages of two")
+
+		page := listEntries(t, client, query)
+		for _, entry := range page.Entries {
+			listed = append(listed, entry.Key)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		query = "?limit=2&cursor=" + page.NextCursor
+	}
+
+	assert.ElementsMatch(t, created, listed)
+	assert.IsIncreasing(t, listed, "entries are listed in key order")
+
+	// Another participant's listing is empty
+	other := client.GET("/participants/87654321/entries")
+	defer other.Body.Close()
+	require.Equal(t, http.StatusOK, other.StatusCode)
+	assert.Empty(t, ParseResponse[struct {
+		Data models.EntryPage `json:"data"`
+	}](t, other).Data.Entries)
+}
+
+func TestListParticipantEntries_Since(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+	unchanged := client.CreateEntry()
+	updated := client.CreateEntry()
+	defer client.CleanupEntry(unchanged)
+	defer client.CleanupEntry(updated)
+
+	// Keep the update apart from the creations at millisecond precision
+	time.Sleep(10 * time.Millisecond)
+
+	resp := client.PUT("/entries/"+updated, map[string]any{
+		"key":         updated,
+		"participant": "12345678",
+		"owner":       map[string]any{"name": "Renamed User"},
+		"reason":      "USER_REQUESTED",
+	})
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	entry := ParseResponse[struct {
+		Data models.EntryResponse `json:"data"`
+	}](t, resp)
+
+	page := listEntries(t, client, "?since="+entry.Data.UpdatedAt.UTC().Format(time.RFC3339Nano))
+	require.Len(t, page.Entries, 1)
+	assert.Equal(t, updated, page.Entries[0].Key)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestListParticipantEntries_InvalidQuery(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	for _, query := range []string{"?limit=0", "?limit=1001", "?cursor=!!", "?since=yesterday"} {
+		resp := client.GET("/participants/12345678/entries" + query)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+}
+
 // =============================================================================
 // Idempotency Tests
 // =============================================================================
@@ -486,6 +1116,29 @@ func TestIdempotency_DifferentKeyCausesConflict(t *testing.T) {
 	assert.Equal(t, http.StatusConflict, resp2.StatusCode)
 }
 
+func TestIdempotency_ErrorIsNotReplayed(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	cpf := GenerateValidCPF()
+	headers := map[string]string{"X-Idempotency-Key": uuid.New().String()}
+
+	// A rejected request doesn't keep the key...
+	req := CreateEntryRequest(cpf)
+	req["key"] = "12345678900"
+	resp1 := client.POSTWithHeaders("/entries", req, headers)
+	resp1.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp1.StatusCode)
+
+	// ...so the corrected retry with the same key runs
+	resp2 := client.POSTWithHeaders("/entries", CreateEntryRequest(cpf), headers)
+	defer resp2.Body.Close()
+	assert.Equal(t, http.StatusCreated, resp2.StatusCode)
+
+	client.CleanupEntry(cpf)
+}
+
 func TestCorrelationId_ReturnsProvidedId(t *testing.T) {
 	t.Parallel()
 
@@ -526,6 +1179,7 @@ func TestCorrelationId_GeneratedWhenNotProvided(t *testing.T) {
 	}
 	json.NewDecoder(resp.Body).Decode(&apiResp)
 	assert.NotEmpty(t, apiResp.CorrelationId)
+	assert.Equal(t, resp.Header.Get("X-Correlation-Id"), apiResp.CorrelationId, "header and body must carry the same generated ID")
 }
 
 func TestResponseTime_IncludedInAllResponses(t *testing.T) {
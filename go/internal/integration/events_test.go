@@ -0,0 +1,98 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBus_ChangeStreamDeliversEntryEvents(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClientForServer(t, StartChangeStreamServer(t))
+	receiver, received := startReceiver(t, http.StatusOK)
+
+	registerWebhook(t, client, receiver.URL, "ENTRY_CREATED", "ENTRY_DELETED")
+
+	cpf := client.CreateEntry()
+	resp := client.DeleteEntry(cpf, "12345678", "USER_REQUESTED")
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	type entryEvent struct {
+		ID          string `json:"id"`
+		Type        string `json:"type"`
+		Participant string `json:"participant"`
+		Data        struct {
+			Key string `json:"key"`
+		} `json:"data"`
+	}
+
+	// Changes are published in commit order
+	var created, deleted entryEvent
+	require.NoError(t, json.Unmarshal(awaitCallback(t, received).body, &created))
+	require.NoError(t, json.Unmarshal(awaitCallback(t, received).body, &deleted))
+
+	assert.Equal(t, "ENTRY_CREATED", created.Type)
+	assert.Equal(t, "12345678", created.Participant)
+	assert.Equal(t, cpf, created.Data.Key)
+
+	// The deletion is attributed to the participant through the entry's pre-image
+	assert.Equal(t, "ENTRY_DELETED", deleted.Type)
+	assert.Equal(t, "12345678", deleted.Participant)
+	assert.Equal(t, cpf, deleted.Data.Key)
+
+	// Handlers stay silent in change stream mode, so nothing is published twice
+	assert.NotEqual(t, created.ID, deleted.ID)
+	select {
+	case cb := <-received:
+		t.Errorf("unexpected extra callback: %s", cb.body)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
+func TestEventBus_OutboxDeliversEntryEvents(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClientForServer(t, StartOutboxServer(t))
+	receiver, received := startReceiver(t, http.StatusOK)
+
+	registerWebhook(t, client, receiver.URL, "ENTRY_CREATED", "ENTRY_DELETED")
+
+	cpf := client.CreateEntry()
+
+	// A rejected create has no event
+	duplicate := client.POST("/entries", CreateEntryRequest(cpf))
+	duplicate.Body.Close()
+	require.Equal(t, http.StatusConflict, duplicate.StatusCode)
+
+	resp := client.DeleteEntry(cpf, "12345678", "USER_REQUESTED")
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	type entryEvent struct {
+		Type string `json:"type"`
+		Data struct {
+			Key string `json:"key"`
+		} `json:"data"`
+	}
+
+	var created, deleted entryEvent
+	require.NoError(t, json.Unmarshal(awaitCallback(t, received).body, &created))
+	require.NoError(t, json.Unmarshal(awaitCallback(t, received).body, &deleted))
+
+	assert.Equal(t, "ENTRY_CREATED", created.Type)
+	assert.Equal(t, cpf, created.Data.Key)
+	assert.Equal(t, "ENTRY_DELETED", deleted.Type)
+	assert.Equal(t, cpf, deleted.Data.Key)
+
+	select {
+	case cb := <-received:
+		t.Errorf("unexpected extra callback: %s", cb.body)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
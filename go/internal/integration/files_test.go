@@ -0,0 +1,45 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// Files are generated by the scheduler, which test servers don't run; see jobs.TestReconciliationFiles
+func TestFiles_NoneGenerated(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+	client.CreateEntry()
+
+	resp := client.GET("/files?participant=12345678")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, ParseResponse[struct {
+		Data []models.ReconciliationFileResponse `json:"data"`
+	}](t, resp).Data)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"missing participant", "/files", http.StatusBadRequest},
+		{"unknown file", "/files/65a4f0c2e4b0a1b2c3d4e5f6", http.StatusNotFound},
+		{"malformed ID", "/files/not-an-id", http.StatusNotFound},
+		{"unknown format", "/files/65a4f0c2e4b0a1b2c3d4e5f6?format=pdf", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := client.GET(tt.path)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+}
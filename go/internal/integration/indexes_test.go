@@ -0,0 +1,45 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+func TestIndexes_ReportsMissingAndUndeclared(t *testing.T) {
+	t.Parallel()
+
+	isolatedMongo := testMongoDB.WithDatabase("test_dict_indexes_" + uuid.New().String())
+	t.Cleanup(func() { isolatedMongo.Database.Drop(context.Background()) })
+
+	ctx := context.Background()
+	repo := models.NewMongoEntryRepository(isolatedMongo, clock.System)
+	require.NoError(t, repo.EnsureIndexes(ctx))
+
+	report, err := isolatedMongo.CheckIndexes(ctx, repo.IndexSpec())
+	require.NoError(t, err)
+	assert.Empty(t, report.Missing)
+	assert.Empty(t, report.Undeclared)
+	// Nothing has queried the new collection; the unique indexes don't count
+	assert.Len(t, report.Unused, 4)
+
+	// A database built by an older version: the owner index before it became compound
+	entries := isolatedMongo.Collection("entries")
+	_, err = entries.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "owner.taxIdNumber", Value: 1}}})
+	require.NoError(t, err)
+	_, err = entries.Indexes().DropOne(ctx, "status_1_updatedAt_1")
+	require.NoError(t, err)
+
+	report, err = isolatedMongo.CheckIndexes(ctx, repo.IndexSpec())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"status_1_updatedAt_1"}, report.Missing)
+	assert.Equal(t, []string{"owner.taxIdNumber_1"}, report.Undeclared)
+}
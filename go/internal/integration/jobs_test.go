@@ -0,0 +1,80 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/jobs"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+func TestJobs_PurgeUnfinishedIdempotency(t *testing.T) {
+	t.Parallel()
+
+	isolatedMongo := testMongoDB.WithDatabase("test_dict_jobs_" + uuid.New().String())
+	t.Cleanup(func() { isolatedMongo.Database.Drop(context.Background()) })
+
+	ctx := context.Background()
+	clk := clock.NewSimulated()
+	repo := models.NewMongoIdempotencyRepository(isolatedMongo, clk)
+	require.NoError(t, repo.EnsureIndexes(ctx))
+
+	// One request died before saving its response, the other completed
+	claimed, _, err := repo.ClaimKey(ctx, "abandoned", "12345678")
+	require.NoError(t, err)
+	require.True(t, claimed)
+	require.NoError(t, repo.Save(ctx, "completed", `{"ok":true}`, 201, models.IdempotencyTTL))
+
+	purge := jobs.PurgeUnfinishedIdempotency(repo, clk)
+
+	// Too recent: the request may still be running
+	require.NoError(t, purge(ctx))
+	record, err := repo.FindByKey(ctx, "abandoned")
+	require.NoError(t, err)
+	require.NotNil(t, record)
+
+	clk.Advance(jobs.UnfinishedIdempotencyAge + time.Second)
+	require.NoError(t, purge(ctx))
+
+	record, err = repo.FindByKey(ctx, "abandoned")
+	require.NoError(t, err)
+	assert.Nil(t, record, "the abandoned claim is purged so the key can be used again")
+
+	record, err = repo.FindByKey(ctx, "completed")
+	require.NoError(t, err)
+	require.NotNil(t, record, "completed records are replayed until they expire")
+	assert.Equal(t, 201, record.StatusCode)
+}
+
+func TestJobs_EntryCountsByKeyType(t *testing.T) {
+	t.Parallel()
+
+	isolatedMongo := testMongoDB.WithDatabase("test_dict_jobs_" + uuid.New().String())
+	t.Cleanup(func() { isolatedMongo.Database.Drop(context.Background()) })
+
+	ctx := context.Background()
+	repo := models.NewMongoEntryRepository(isolatedMongo, clock.NewSimulated())
+
+	for range 2 {
+		cpf := GenerateValidCPF()
+		_, err := repo.Create(ctx, &models.CreateEntryRequest{
+			Key:     cpf,
+			KeyType: models.KeyTypeCPF,
+			Account: models.Account{Participant: "12345678", Branch: "0001", AccountNumber: "0007654321", AccountType: "CACC"},
+			Owner:   models.Owner{Type: "NATURAL_PERSON", TaxIdNumber: cpf, Name: "Test User"},
+		})
+		require.NoError(t, err)
+	}
+
+	counts, err := repo.CountByKeyType(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[models.KeyType]int64{models.KeyTypeCPF: 2}, counts)
+
+	require.NoError(t, jobs.EntryStatistics(repo)(ctx))
+}
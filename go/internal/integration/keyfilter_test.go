@@ -0,0 +1,99 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/config"
+	"github.com/dict-simulator/go/internal/keyfilter"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// StartKeyFilterServer starts a new server whose entry lookups go through the Redis key filter
+func StartKeyFilterServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	cfg := &config.Config{
+		Port:              3000,
+		Environment:       "test",
+		JWTSecret:         "test-jwt-secret-for-integration-tests",
+		AdminEnabled:      true,
+		AdminToken:        testAdminToken,
+		OpenAPIValidation: true,
+		KeyFilterEnabled:  true,
+	}
+	dbName := "test_dict_keyfilter_" + uuid.New().String()
+	return createTestServer(t, cfg, dbName)
+}
+
+func TestKeyFilter_LookupsThroughServer(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClientForServer(t, StartKeyFilterServer(t))
+	key := GenerateValidCPF()
+
+	created := client.POST("/entries", CreateEntryRequest(key))
+	defer created.Body.Close()
+	require.Equal(t, http.StatusCreated, created.StatusCode)
+
+	found := client.GET("/entries/" + key)
+	defer found.Body.Close()
+	assert.Equal(t, http.StatusOK, found.StatusCode, "a key created after warming must be found")
+
+	unknown := client.GET("/entries/" + GenerateValidCPF())
+	defer unknown.Body.Close()
+	assert.Equal(t, http.StatusNotFound, unknown.StatusCode)
+
+	deleted := client.DeleteEntry(key, "12345678", "USER_REQUESTED")
+	defer deleted.Body.Close()
+	require.Equal(t, http.StatusOK, deleted.StatusCode)
+
+	gone := client.GET("/entries/" + key)
+	defer gone.Body.Close()
+	assert.Equal(t, http.StatusNotFound, gone.StatusCode, "deleted keys stay in the filter but are not found")
+}
+
+func TestKeyFilter_WarmsFromStorage(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dbName := "test_dict_keyfilter_" + uuid.New().String()
+	repo := models.NewMongoEntryRepository(testMongoDB.WithDatabase(dbName), clock.NewSimulated())
+
+	// Registered before the filter exists, so only warming can add it
+	existing := GenerateValidCPF()
+	_, err := repo.Create(ctx, &models.CreateEntryRequest{
+		Key:     existing,
+		KeyType: models.KeyTypeCPF,
+		Account: models.Account{Participant: "12345678", Branch: "0001", AccountNumber: "0007654321", AccountType: "CACC"},
+		Owner:   models.Owner{Type: "NATURAL_PERSON", TaxIdNumber: existing, Name: "Test User"},
+	})
+	require.NoError(t, err)
+
+	filter := keyfilter.NewBloom(testRedisDB.Client, dbName, 1000, 0.01)
+	filtered := keyfilter.NewEntryRepository(repo, filter)
+
+	// Not warmed yet: lookups go to the database
+	entry, err := filtered.FindByKey(ctx, existing)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+
+	count, err := filter.Warm(ctx, repo.ForEachKey)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	entry, err = filtered.FindByKey(ctx, existing)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.Equal(t, existing, entry.Key)
+
+	entry, err = filtered.FindByKey(ctx, GenerateValidCPF())
+	require.NoError(t, err)
+	assert.Nil(t, entry)
+}
@@ -0,0 +1,49 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dict-simulator/go/internal/lockout"
+)
+
+func TestRedisLockoutStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logins := lockout.NewRedisStore(testRedisDB.Client)
+	account := "lockout-" + uuid.New().String()
+	t.Cleanup(func() { testRedisDB.Client.Del(ctx, "login_failures:"+account, "login_lock:"+account) })
+
+	for want := 1; want <= 3; want++ {
+		n, err := logins.Fail(ctx, account, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, want, n)
+	}
+
+	require.NoError(t, logins.Lock(ctx, account, time.Minute))
+	locked, err := logins.LockedFor(ctx, account)
+	require.NoError(t, err)
+	assert.InDelta(t, time.Minute, locked, float64(5*time.Second))
+
+	n, err := logins.Fail(ctx, account, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n, "locking forgets the failures")
+
+	unlocked, err := logins.Unlock(ctx, account)
+	require.NoError(t, err)
+	assert.True(t, unlocked)
+
+	locked, err = logins.LockedFor(ctx, account)
+	require.NoError(t, err)
+	assert.Zero(t, locked)
+
+	unlocked, err = logins.Unlock(ctx, account)
+	require.NoError(t, err)
+	assert.False(t, unlocked, "nothing left to unlock")
+}
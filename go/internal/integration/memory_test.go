@@ -0,0 +1,141 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dict-simulator/go/internal/config"
+	"github.com/dict-simulator/go/internal/webhook"
+)
+
+// StartMemoryServer starts a new server that keeps everything in memory, rate limits included
+func StartMemoryServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	cfg := &config.Config{
+		Port:                   3000,
+		Environment:            "test",
+		JWTSecret:              "test-jwt-secret-for-integration-tests",
+		RateLimitEnabled:       true,
+		RateLimitBucketSize:    60,
+		RateLimitRefillSeconds: 60,
+		AdminEnabled:           true,
+		AdminToken:             testAdminToken,
+		OpenAPIValidation:      true,
+		Storage:                config.StorageMemory,
+	}
+	dbName := "test_dict_memory_" + uuid.New().String()
+	return createTestServer(t, cfg, dbName)
+}
+
+func TestMemoryStorage_EntryLifecycle(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClientForServer(t, StartMemoryServer(t))
+	key := GenerateValidCPF()
+
+	created := client.POST("/entries", CreateEntryRequest(key))
+	defer created.Body.Close()
+	require.Equal(t, http.StatusCreated, created.StatusCode)
+
+	duplicate := client.POST("/entries", CreateEntryRequest(key))
+	defer duplicate.Body.Close()
+	assert.Equal(t, http.StatusConflict, duplicate.StatusCode)
+
+	updated := client.PUT("/entries/"+key, map[string]any{
+		"key":         key,
+		"participant": "12345678",
+		"reason":      "USER_REQUESTED",
+		"owner":       map[string]any{"name": "Updated Name"},
+	})
+	defer updated.Body.Close()
+	require.Equal(t, http.StatusOK, updated.StatusCode)
+
+	entry := ParseResponse[struct {
+		Data struct {
+			Owner struct {
+				Name        string `json:"name"`
+				TaxIdNumber string `json:"taxIdNumber"`
+			} `json:"owner"`
+		} `json:"data"`
+	}](t, updated)
+	assert.Equal(t, "Updated Name", entry.Data.Owner.Name)
+	assert.Equal(t, key, entry.Data.Owner.TaxIdNumber, "owner fields not in the update are kept")
+
+	wrongParticipant := client.DeleteEntry(key, "99999999", "USER_REQUESTED")
+	defer wrongParticipant.Body.Close()
+	assert.Equal(t, http.StatusNotFound, wrongParticipant.StatusCode)
+
+	deleted := client.DeleteEntry(key, "12345678", "USER_REQUESTED")
+	defer deleted.Body.Close()
+	require.Equal(t, http.StatusOK, deleted.StatusCode)
+
+	gone := client.GET("/entries/" + key)
+	defer gone.Body.Close()
+	assert.Equal(t, http.StatusNotFound, gone.StatusCode)
+}
+
+func TestMemoryStorage_IdempotencyReplaysResponse(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClientForServer(t, StartMemoryServer(t))
+	headers := map[string]string{"X-Idempotency-Key": uuid.New().String()}
+	body := CreateEntryRequest(GenerateValidCPF())
+
+	first := client.POSTWithHeaders("/entries", body, headers)
+	defer first.Body.Close()
+	require.Equal(t, http.StatusCreated, first.StatusCode)
+
+	second := client.POSTWithHeaders("/entries", body, headers)
+	defer second.Body.Close()
+	assert.Equal(t, http.StatusCreated, second.StatusCode)
+}
+
+func TestMemoryStorage_WebhooksAreDeliveredAndLogged(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClientForServer(t, StartMemoryServer(t))
+	receiver, received := startReceiver(t, http.StatusOK)
+
+	id, _ := registerWebhook(t, client, receiver.URL, "ENTRY_CREATED")
+
+	client.CreateEntry()
+	cb := awaitCallback(t, received)
+	assert.Equal(t, "ENTRY_CREATED", cb.header.Get(webhook.EventTypeHeader))
+
+	require.Eventually(t, func() bool {
+		resp := client.GET("/webhooks/" + id + "/deliveries")
+		defer resp.Body.Close()
+		deliveries := ParseResponse[struct {
+			Data []struct {
+				Success bool `json:"success"`
+			} `json:"data"`
+		}](t, resp)
+		return len(deliveries.Data) == 1 && deliveries.Data[0].Success
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestMemoryStorage_RateLimitsWithoutRedis(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClientForServer(t, StartMemoryServer(t))
+
+	remaining := func() int {
+		resp := client.GET("/entries/nonexistent")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		n, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+		require.NoError(t, err)
+		return n
+	}
+
+	first := remaining()
+	assert.Less(t, remaining(), first, "a 404 consumes tokens")
+}
@@ -0,0 +1,90 @@
+package integration
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type oauthClientData struct {
+	Data struct {
+		ClientID     string   `json:"clientId"`
+		Participant  string   `json:"participant"`
+		Scopes       []string `json:"scopes"`
+		ClientSecret string   `json:"clientSecret"`
+	} `json:"data"`
+}
+
+func TestOAuthClientCredentials(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	created := client.POST("/admin/oauth/clients", map[string]any{
+		"participant": "12345678",
+		"name":        "Integration client",
+		"scopes":      []string{"entries:read", "entries:write"},
+	})
+	defer created.Body.Close()
+	require.Equal(t, http.StatusCreated, created.StatusCode)
+	registered := ParseResponse[oauthClientData](t, created)
+	require.NotEmpty(t, registered.Data.ClientSecret, "the secret is returned on registration")
+
+	listed := client.GET("/admin/oauth/clients?participant=12345678")
+	defer listed.Body.Close()
+	require.Equal(t, http.StatusOK, listed.StatusCode)
+	clients := ParseResponse[struct {
+		Data []struct {
+			ClientID     string `json:"clientId"`
+			ClientSecret string `json:"clientSecret"`
+		} `json:"data"`
+	}](t, listed)
+	require.Len(t, clients.Data, 1)
+	assert.Equal(t, registered.Data.ClientID, clients.Data[0].ClientID)
+	assert.Empty(t, clients.Data[0].ClientSecret, "secrets are never listed")
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	req, err := http.NewRequest(http.MethodPost, client.baseURL+"/oauth/token", strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(registered.Data.ClientID, registered.Data.ClientSecret)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+	token := ParseResponse[struct {
+		AccessToken string `json:"access_token"`
+		Scope       string `json:"scope"`
+	}](t, resp)
+	assert.Equal(t, "entries:read entries:write", token.Scope, "every scope of the client by default")
+
+	machine := &TestClient{t: t, baseURL: client.baseURL, authToken: token.AccessToken}
+
+	cpf := machine.CreateEntry()
+	defer client.CleanupEntry(cpf)
+
+	got := machine.GET("/entries/" + cpf)
+	defer got.Body.Close()
+	assert.Equal(t, http.StatusOK, got.StatusCode)
+
+	webhooks := machine.GET("/webhooks?participant=12345678")
+	defer webhooks.Body.Close()
+	assert.Equal(t, http.StatusForbidden, webhooks.StatusCode, "scope not granted")
+
+	mismatch := machine.GETWithHeaders("/entries/"+cpf, map[string]string{"X-Participant-Id": "87654321"})
+	defer mismatch.Body.Close()
+	assert.Equal(t, http.StatusForbidden, mismatch.StatusCode, "the token speaks for its own participant only")
+
+	deleted := client.Request(http.MethodDelete, "/admin/oauth/clients/"+registered.Data.ClientID, nil, nil)
+	defer deleted.Body.Close()
+	require.Equal(t, http.StatusOK, deleted.StatusCode)
+
+	refused := machine.GET("/entries/" + cpf)
+	defer refused.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, refused.StatusCode, "tokens of deleted clients are refused")
+}
@@ -0,0 +1,200 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dict-simulator/go/internal/broker"
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/events"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/outbox"
+)
+
+// fakeBroker records published messages and fails the first `failures` publishes
+type fakeBroker struct {
+	mu        sync.Mutex
+	failures  int
+	published []broker.Message
+}
+
+func (b *fakeBroker) Publish(_ context.Context, msg broker.Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures > 0 {
+		b.failures--
+		return errors.New("broker unavailable")
+	}
+	b.published = append(b.published, msg)
+	return nil
+}
+
+func (b *fakeBroker) Close() error { return nil }
+
+func (b *fakeBroker) messages() []broker.Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]broker.Message(nil), b.published...)
+}
+
+func TestOutbox_PublishesInOrderAfterBrokerRecovers(t *testing.T) {
+	t.Parallel()
+
+	isolatedMongo := testMongoDB.WithDatabase("test_dict_outbox_" + uuid.New().String())
+	t.Cleanup(func() { isolatedMongo.Database.Drop(context.Background()) })
+
+	ctx := context.Background()
+	repo := models.NewMongoOutboxRepository(isolatedMongo)
+	require.NoError(t, repo.EnsureIndexes(ctx))
+
+	fake := &fakeBroker{failures: 1}
+	relay := outbox.NewRelay(repo, fake, outbox.Config{
+		Topic:        "dict.events",
+		Broker:       broker.KindKafka,
+		PollInterval: 20 * time.Millisecond,
+		BatchSize:    10,
+		MaxBackoff:   50 * time.Millisecond,
+	})
+
+	created := events.New(events.EntryCreated, "12345678", map[string]string{"key": "a"}, time.Now())
+	deleted := events.New(events.EntryDeleted, "12345678", map[string]string{"key": "a"}, time.Now())
+	relay.Enqueue(ctx, created)
+	relay.Enqueue(ctx, deleted)
+	relay.Enqueue(ctx, created) // Redelivery of the same event is stored once
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		relay.Run(runCtx)
+	}()
+
+	var published []broker.Message
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		if published = fake.messages(); len(published) >= 2 {
+			break
+		}
+	}
+	cancel()
+	<-done
+
+	require.Len(t, published, 2)
+	assert.Equal(t, "dict.events", published[0].Topic)
+	assert.Equal(t, "12345678", published[0].Key)
+	assert.Contains(t, string(published[0].Value), created.ID)
+	assert.Contains(t, string(published[1].Value), deleted.ID)
+
+	// The first event failed once before the broker accepted it
+	message, err := repo.FindByEventID(ctx, created.ID)
+	require.NoError(t, err)
+	require.NotNil(t, message)
+	assert.NotNil(t, message.PublishedAt)
+	assert.Equal(t, 2, message.Attempts)
+	assert.Empty(t, message.LastError)
+}
+
+func TestOutbox_DeadLettersAfterMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	isolatedMongo := testMongoDB.WithDatabase("test_dict_outbox_" + uuid.New().String())
+	t.Cleanup(func() { isolatedMongo.Database.Drop(context.Background()) })
+
+	ctx := context.Background()
+	repo := models.NewMongoOutboxRepository(isolatedMongo)
+	require.NoError(t, repo.EnsureIndexes(ctx))
+
+	fake := &fakeBroker{failures: 2}
+	relay := outbox.NewRelay(repo, fake, outbox.Config{
+		Topic:        "dict.events",
+		Broker:       broker.KindKafka,
+		PollInterval: 20 * time.Millisecond,
+		BatchSize:    10,
+		MaxBackoff:   50 * time.Millisecond,
+		MaxAttempts:  2,
+	})
+
+	poisoned := events.New(events.EntryCreated, "12345678", map[string]string{"key": "a"}, time.Now())
+	next := events.New(events.EntryCreated, "12345678", map[string]string{"key": "b"}, time.Now())
+	relay.Enqueue(ctx, poisoned)
+	relay.Enqueue(ctx, next)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		relay.Run(runCtx)
+	}()
+
+	var published []broker.Message
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		if published = fake.messages(); len(published) >= 1 {
+			break
+		}
+	}
+	cancel()
+	<-done
+
+	// The dead-lettered event no longer holds back the one queued after it
+	require.Len(t, published, 1)
+	assert.Contains(t, string(published[0].Value), next.ID)
+
+	message, err := repo.FindByEventID(ctx, poisoned.ID)
+	require.NoError(t, err)
+	require.NotNil(t, message)
+	assert.Nil(t, message.PublishedAt)
+	assert.NotNil(t, message.DeadLetteredAt)
+	assert.Equal(t, 2, message.Attempts)
+	assert.Equal(t, "broker unavailable", message.LastError)
+
+	due, err := repo.FindDue(ctx, time.Now().Add(time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+}
+
+func TestOutbox_EntryWriteAndEventCommitTogether(t *testing.T) {
+	t.Parallel()
+
+	isolatedMongo := testMongoDB.WithDatabase("test_dict_outbox_" + uuid.New().String())
+	t.Cleanup(func() { isolatedMongo.Database.Drop(context.Background()) })
+
+	ctx := context.Background()
+	clk := clock.NewSimulated()
+	entryRepo := models.NewMongoEntryRepository(isolatedMongo, clk)
+	outboxRepo := models.NewMongoOutboxRepository(isolatedMongo)
+	require.NoError(t, entryRepo.EnsureIndexes(ctx))
+	require.NoError(t, outboxRepo.EnsureIndexes(ctx))
+
+	repo := outbox.NewEntryRepository(entryRepo, isolatedMongo, outboxRepo, clk)
+
+	cpf := GenerateValidCPF()
+	req := &models.CreateEntryRequest{
+		Key:     cpf,
+		KeyType: models.KeyTypeCPF,
+		Account: models.Account{Participant: "12345678", Branch: "0001", AccountNumber: "0007654321", AccountType: "CACC"},
+		Owner:   models.Owner{Type: "NATURAL_PERSON", TaxIdNumber: cpf, Name: "Test User"},
+	}
+	_, err := repo.Create(ctx, req)
+	require.NoError(t, err)
+
+	// The unique key index rejects the second insert, rolling back its event
+	_, err = repo.Create(ctx, req)
+	require.Error(t, err)
+
+	deleted, err := repo.DeleteByKeyAndParticipant(ctx, cpf, "99999999")
+	require.NoError(t, err)
+	assert.Nil(t, deleted, "nothing deleted, nothing enqueued")
+
+	due, err := outboxRepo.FindDue(ctx, time.Now().Add(time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, events.EntryCreated, due[0].EventType)
+	assert.Equal(t, "12345678", due[0].Participant)
+}
@@ -0,0 +1,52 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dict-simulator/go/internal/passwordreset"
+)
+
+func TestRedisPasswordResetStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	resets := passwordreset.NewRedisStore(testRedisDB.Client)
+	userID := "reset-" + uuid.New().String()
+	first, second := passwordreset.NewToken(), passwordreset.NewToken()
+	t.Cleanup(func() {
+		testRedisDB.Client.Del(ctx, "password_reset_user:"+userID, "password_reset:"+first, "password_reset:"+second)
+	})
+
+	token, _, err := resets.Pending(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, token, "no reset requested yet")
+
+	require.NoError(t, resets.Save(ctx, userID, first, time.Hour))
+	require.NoError(t, resets.Save(ctx, userID, second, time.Hour))
+	token, ttl, err := resets.Pending(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, second, token)
+	assert.InDelta(t, time.Hour, ttl, float64(5*time.Second))
+
+	redeemed, err := resets.Redeem(ctx, first)
+	require.NoError(t, err)
+	assert.Empty(t, redeemed, "saving a token revokes the previous one")
+
+	redeemed, err = resets.Redeem(ctx, second)
+	require.NoError(t, err)
+	assert.Equal(t, userID, redeemed)
+
+	redeemed, err = resets.Redeem(ctx, second)
+	require.NoError(t, err)
+	assert.Empty(t, redeemed, "tokens are single-use")
+
+	token, _, err = resets.Pending(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, token)
+}
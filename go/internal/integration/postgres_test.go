@@ -0,0 +1,224 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/dict-simulator/go/internal/config"
+	"github.com/dict-simulator/go/internal/db"
+)
+
+// PostgreSQL is only needed by the tests below, so its container is started on first use
+var (
+	postgresOnce      sync.Once
+	postgresContainer testcontainers.Container
+	postgresBaseURL   string
+	postgresAdmin     *db.Postgres
+	postgresErr       error
+)
+
+// startPostgres starts the shared PostgreSQL container once
+func startPostgres(t testing.TB) {
+	t.Helper()
+
+	postgresOnce.Do(func() {
+		ctx := context.Background()
+
+		postgresContainer, postgresErr = testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:        "postgres:16-alpine",
+				ExposedPorts: []string{"5432/tcp"},
+				Env:          map[string]string{"POSTGRES_PASSWORD": "postgres"},
+				// The server restarts once after initdb, so wait for the second ready message
+				WaitingFor: wait.ForLog("database system is ready to accept connections").
+					WithOccurrence(2).
+					WithStartupTimeout(60 * time.Second),
+			},
+			Started: true,
+		})
+		if postgresErr != nil {
+			return
+		}
+
+		host, err := postgresContainer.Host(ctx)
+		if err != nil {
+			postgresErr = err
+			return
+		}
+		port, err := postgresContainer.MappedPort(ctx, "5432/tcp")
+		if err != nil {
+			postgresErr = err
+			return
+		}
+
+		postgresBaseURL = fmt.Sprintf("postgres://postgres:postgres@%s:%s", host, port.Port())
+		postgresAdmin, postgresErr = db.ConnectPostgres(postgresBaseURL + "/postgres?sslmode=disable")
+	})
+
+	if postgresErr != nil {
+		t.Fatalf("Failed to start PostgreSQL container: %v", postgresErr)
+	}
+}
+
+// terminatePostgres stops the shared PostgreSQL container if a test started it
+func terminatePostgres(ctx context.Context) {
+	if postgresAdmin != nil {
+		postgresAdmin.Disconnect()
+	}
+	if postgresContainer != nil {
+		postgresContainer.Terminate(ctx)
+	}
+}
+
+// createTestPostgres creates an isolated, migrated database that is dropped when the test ends
+func createTestPostgres(t testing.TB, dbName string) *db.Postgres {
+	t.Helper()
+	startPostgres(t)
+
+	ctx := context.Background()
+	ident := pgx.Identifier{dbName}.Sanitize()
+	if _, err := postgresAdmin.Pool.Exec(ctx, "CREATE DATABASE "+ident); err != nil {
+		t.Fatalf("Failed to create test database %s: %v", dbName, err)
+	}
+
+	pg, err := db.ConnectPostgres(postgresBaseURL + "/" + dbName + "?sslmode=disable")
+	if err != nil {
+		t.Fatalf("Failed to connect to test database %s: %v", dbName, err)
+	}
+	if err := pg.Migrate(ctx); err != nil {
+		t.Fatalf("Failed to migrate test database %s: %v", dbName, err)
+	}
+
+	t.Cleanup(func() {
+		pg.Disconnect()
+		if _, err := postgresAdmin.Pool.Exec(context.Background(), "DROP DATABASE "+ident+" WITH (FORCE)"); err != nil {
+			t.Logf("Failed to drop test database %s: %v", dbName, err)
+		}
+	})
+
+	return pg
+}
+
+// StartPostgresServer starts a new server storing entries, users and idempotency records in PostgreSQL
+func StartPostgresServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	cfg := &config.Config{
+		Port:                   3000,
+		Environment:            "test",
+		JWTSecret:              "test-jwt-secret-for-integration-tests",
+		RateLimitEnabled:       false,
+		RateLimitBucketSize:    60,
+		RateLimitRefillSeconds: 60,
+		AdminEnabled:           true,
+		AdminToken:             testAdminToken,
+		OpenAPIValidation:      true,
+		Storage:                config.StoragePostgres,
+	}
+	// PostgreSQL folds unquoted names to lower case and limits them to 63 bytes
+	dbName := "test_dict_pg_" + strings.ReplaceAll(uuid.New().String(), "-", "")
+	return createTestServer(t, cfg, dbName)
+}
+
+func TestPostgresStorage_EntryLifecycle(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClientForServer(t, StartPostgresServer(t))
+	key := GenerateValidCPF()
+
+	created := client.POST("/entries", CreateEntryRequest(key))
+	defer created.Body.Close()
+	require.Equal(t, http.StatusCreated, created.StatusCode)
+
+	duplicate := client.POST("/entries", CreateEntryRequest(key))
+	defer duplicate.Body.Close()
+	assert.Equal(t, http.StatusConflict, duplicate.StatusCode)
+
+	updated := client.PUT("/entries/"+key, map[string]any{
+		"key":         key,
+		"participant": "12345678",
+		"reason":      "USER_REQUESTED",
+		"owner":       map[string]any{"name": "Updated Name"},
+	})
+	defer updated.Body.Close()
+	require.Equal(t, http.StatusOK, updated.StatusCode)
+
+	entry := ParseResponse[struct {
+		Data struct {
+			Key   string `json:"key"`
+			Owner struct {
+				Name        string `json:"name"`
+				TaxIdNumber string `json:"taxIdNumber"`
+			} `json:"owner"`
+		} `json:"data"`
+	}](t, updated)
+	assert.Equal(t, key, entry.Data.Key)
+	assert.Equal(t, "Updated Name", entry.Data.Owner.Name)
+	assert.Equal(t, key, entry.Data.Owner.TaxIdNumber, "owner fields not in the update are kept")
+
+	wrongParticipant := client.DeleteEntry(key, "99999999", "USER_REQUESTED")
+	defer wrongParticipant.Body.Close()
+	assert.Equal(t, http.StatusNotFound, wrongParticipant.StatusCode)
+
+	deleted := client.DeleteEntry(key, "12345678", "USER_REQUESTED")
+	defer deleted.Body.Close()
+	require.Equal(t, http.StatusOK, deleted.StatusCode)
+
+	gone := client.GET("/entries/" + key)
+	defer gone.Body.Close()
+	assert.Equal(t, http.StatusNotFound, gone.StatusCode)
+}
+
+func TestPostgresStorage_IdempotencyReplaysResponse(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClientForServer(t, StartPostgresServer(t))
+	headers := map[string]string{"X-Idempotency-Key": uuid.New().String()}
+	body := CreateEntryRequest(GenerateValidCPF())
+
+	first := client.POSTWithHeaders("/entries", body, headers)
+	defer first.Body.Close()
+	require.Equal(t, http.StatusCreated, first.StatusCode)
+
+	second := client.POSTWithHeaders("/entries", body, headers)
+	defer second.Body.Close()
+	assert.Equal(t, http.StatusCreated, second.StatusCode)
+}
+
+func TestPostgresStorage_SeedSkipsExistingKeys(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClientForServer(t, StartPostgresServer(t))
+	req := map[string]any{"count": 20, "seed": 7}
+
+	type seedResult struct {
+		Data struct {
+			Created int `json:"created"`
+			Skipped int `json:"skipped"`
+		} `json:"data"`
+	}
+
+	first := client.POST("/admin/seed", req)
+	defer first.Body.Close()
+	require.Equal(t, http.StatusCreated, first.StatusCode)
+	assert.Equal(t, 20, ParseResponse[seedResult](t, first).Data.Created)
+
+	second := client.POST("/admin/seed", req)
+	defer second.Body.Close()
+	require.Equal(t, http.StatusCreated, second.StatusCode)
+	result := ParseResponse[seedResult](t, second)
+	assert.Equal(t, 0, result.Data.Created)
+	assert.Equal(t, 20, result.Data.Skipped)
+}
@@ -0,0 +1,124 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/ratelimit"
+)
+
+// steppedClock stands still between steps, so Redis and memory buckets see the same instant
+type steppedClock struct{ now time.Time }
+
+func (c *steppedClock) Now() time.Time { return c.now }
+
+// TestRateLimitAlgorithms_RedisMatchesMemory replays the same traffic against the Lua scripts and
+// the in-memory mirror of every algorithm
+func TestRateLimitAlgorithms_RedisMatchesMemory(t *testing.T) {
+	t.Parallel()
+
+	steps := []struct {
+		advance time.Duration
+		status  int // response to consume, 0 for none
+	}{
+		{0, 0},
+		{0, http.StatusOK},
+		{0, http.StatusNotFound},
+		{10 * time.Second, http.StatusOK},
+		{25 * time.Second, 0},
+		{40 * time.Second, http.StatusNotFound},
+		{50 * time.Second, 0},
+		{-2 * time.Minute, 0}, // the simulated clock was reset
+		{3 * time.Minute, http.StatusOK},
+		{30 * time.Minute, 0},
+	}
+
+	for _, algorithm := range []ratelimit.Algorithm{ratelimit.AlgorithmTokenBucket, ratelimit.AlgorithmSlidingWindow, ratelimit.AlgorithmGCRA} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			ctx := context.Background()
+			clk := &steppedClock{now: time.Now()}
+			redisBucket := ratelimit.NewBucket(testRedisDB.Client, clk)
+			memoryBucket := ratelimit.NewMemoryBucket(clk)
+
+			policy := ratelimit.DefaultPolicies()[ratelimit.PolicyEntriesReadParticipant]
+			policy.BucketSize = 5
+			policy.Algorithm = algorithm
+			policy.StartDrained = 0.2
+
+			identifier := "algorithms-" + uuid.New().String()
+			t.Cleanup(func() { redisBucket.Flush(ctx, identifier) })
+
+			for i, step := range steps {
+				clk.now = clk.now.Add(step.advance)
+				if step.status != 0 {
+					require.NoError(t, redisBucket.Consume(ctx, policy, identifier, step.status))
+					require.NoError(t, memoryBucket.Consume(ctx, policy, identifier, step.status))
+				}
+
+				fromRedis, err := redisBucket.Check(ctx, policy, identifier)
+				require.NoError(t, err)
+				fromMemory, err := memoryBucket.Check(ctx, policy, identifier)
+				require.NoError(t, err)
+				assert.Equal(t, fromMemory.Remaining, fromRedis.Remaining, "step %d", i)
+			}
+
+			require.NoError(t, redisBucket.Reset(ctx, policy, identifier))
+			state, err := redisBucket.Check(ctx, policy, identifier)
+			require.NoError(t, err)
+			assert.Equal(t, policy.BucketSize, state.Remaining, "Reset fills the bucket")
+
+			deleted, err := redisBucket.Flush(ctx, identifier)
+			require.NoError(t, err)
+			assert.Positive(t, deleted)
+		})
+	}
+}
+
+func TestRedisBanStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bans := ratelimit.NewRedisBanStore(testRedisDB.Client)
+	ip := "ban-" + uuid.New().String()
+	t.Cleanup(func() { testRedisDB.Client.Del(ctx, "ip_ban:"+ip) })
+
+	banned, err := bans.BannedFor(ctx, ip)
+	require.NoError(t, err)
+	assert.Zero(t, banned, "not banned yet")
+
+	require.NoError(t, bans.Ban(ctx, ip, time.Minute))
+	banned, err = bans.BannedFor(ctx, ip)
+	require.NoError(t, err)
+	assert.InDelta(t, time.Minute, banned, float64(5*time.Second))
+}
+
+// TestRedisBucket_ReloadsFlushedScripts checks that buckets keep working once Redis forgets their
+// scripts, as after a restart or a failover
+func TestRedisBucket_ReloadsFlushedScripts(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bucket := ratelimit.NewBucket(testRedisDB.Client, clock.System)
+	require.NoError(t, bucket.LoadScripts(ctx))
+
+	identifier := "reload-" + uuid.New().String()
+	t.Cleanup(func() { bucket.Flush(ctx, identifier) })
+
+	for _, algorithm := range []ratelimit.Algorithm{ratelimit.AlgorithmTokenBucket, ratelimit.AlgorithmSlidingWindow, ratelimit.AlgorithmGCRA} {
+		policy := ratelimit.DefaultPolicies()[ratelimit.PolicyEntriesReadParticipant]
+		policy.Algorithm = algorithm
+
+		require.NoError(t, testRedisDB.Client.ScriptFlush(ctx).Err())
+		state, err := bucket.Check(ctx, policy, identifier)
+		require.NoError(t, err, algorithm)
+		assert.True(t, state.Allowed, algorithm)
+		require.NoError(t, bucket.Consume(ctx, policy, identifier, http.StatusOK), algorithm)
+	}
+}
@@ -0,0 +1,119 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// settlementResponse is the data of a settlement response
+type settlementResponse struct {
+	EndToEndID       string `json:"endToEndId"`
+	Amount           int64  `json:"amount"`
+	PayerKey         string `json:"payerKey"`
+	PayerParticipant string `json:"payerParticipant"`
+	PayeeKey         string `json:"payeeKey"`
+	PayeeParticipant string `json:"payeeParticipant"`
+}
+
+// endToEndID builds an end-to-end ID for a payment sent by participant 12345678
+func endToEndID(sequence string) string {
+	return "E12345678202401151030" + sequence
+}
+
+func TestSettlements_CreateAndGet(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+	payer := client.CreateEntry()
+	payee := client.CreateEntry()
+	id := endToEndID("00000000001")
+
+	resp := client.POST("/settlements", map[string]any{
+		"endToEndId": id,
+		"amount":     15075,
+		"payerKey":   payer,
+		"payeeKey":   payee,
+	})
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	created := ParseResponse[struct {
+		Code string             `json:"code"`
+		Data settlementResponse `json:"data"`
+	}](t, resp)
+	assert.Equal(t, "SETTLEMENT_CREATED", created.Code)
+	assert.Equal(t, int64(15075), created.Data.Amount)
+	assert.Equal(t, "12345678", created.Data.PayerParticipant)
+	assert.Equal(t, "12345678", created.Data.PayeeParticipant)
+
+	get := client.GET("/settlements/" + id)
+	defer get.Body.Close()
+	require.Equal(t, http.StatusOK, get.StatusCode)
+
+	found := ParseResponse[struct {
+		Data settlementResponse `json:"data"`
+	}](t, get)
+	assert.Equal(t, id, found.Data.EndToEndID)
+	assert.Equal(t, payer, found.Data.PayerKey)
+	assert.Equal(t, payee, found.Data.PayeeKey)
+
+	// Each end-to-end ID settles once
+	duplicate := client.POST("/settlements", map[string]any{
+		"endToEndId": id,
+		"amount":     100,
+		"payerKey":   payer,
+		"payeeKey":   payee,
+	})
+	defer duplicate.Body.Close()
+	assert.Equal(t, http.StatusConflict, duplicate.StatusCode)
+}
+
+func TestSettlements_Rejected(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+	payer := client.CreateEntry()
+	payee := client.CreateEntry()
+
+	tests := []struct {
+		name   string
+		body   map[string]any
+		status int
+	}{
+		{
+			name:   "malformed end-to-end ID",
+			body:   map[string]any{"endToEndId": "E123", "amount": 100, "payerKey": payer, "payeeKey": payee},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "end-to-end ID of another participant",
+			body:   map[string]any{"endToEndId": "E87654321202401151030" + "00000000001", "amount": 100, "payerKey": payer, "payeeKey": payee},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "zero amount",
+			body:   map[string]any{"endToEndId": endToEndID("00000000002"), "amount": 0, "payerKey": payer, "payeeKey": payee},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "unregistered payee key",
+			body:   map[string]any{"endToEndId": endToEndID("00000000003"), "amount": 100, "payerKey": payer, "payeeKey": "nobody@example.com"},
+			status: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := client.POST("/settlements", tt.body)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+
+	missing := client.GET("/settlements/" + endToEndID("99999999999"))
+	defer missing.Body.Close()
+	assert.Equal(t, http.StatusNotFound, missing.StatusCode)
+}
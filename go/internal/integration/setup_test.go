@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,17 +17,17 @@ import (
 	"github.com/testcontainers/testcontainers-go/modules/mongodb"
 	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
 
+	"github.com/dict-simulator/go/internal/app"
 	"github.com/dict-simulator/go/internal/config"
 	"github.com/dict-simulator/go/internal/db"
+	"github.com/dict-simulator/go/internal/jwtkeys"
 	"github.com/dict-simulator/go/internal/logger"
 	"github.com/dict-simulator/go/internal/middleware"
-	"github.com/dict-simulator/go/internal/models"
-	"github.com/dict-simulator/go/internal/modules/auth"
-	"github.com/dict-simulator/go/internal/modules/entries"
-	"github.com/dict-simulator/go/internal/ratelimit"
-	"github.com/dict-simulator/go/internal/router"
 )
 
+// testAdminToken unlocks the admin routes of every test server
+const testAdminToken = "test-admin-token"
+
 // Global test infrastructure - shared across all tests via TestMain
 var (
 	testMongoDB *db.Mongo
@@ -36,7 +37,7 @@ var (
 // TestMain sets up shared test infrastructure once for all tests
 func TestMain(m *testing.M) {
 	// Initialize logger before any database connections
-	if err := logger.Init("test", nil); err != nil {
+	if err := logger.Init("test", "info", logger.EncodingJSON, nil, nil); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
 		os.Exit(1)
 	}
@@ -44,8 +45,8 @@ func TestMain(m *testing.M) {
 
 	ctx := context.Background()
 
-	// Start MongoDB container
-	mongoContainer, err := mongodb.Run(ctx, "mongo:7")
+	// Start MongoDB container as a single-node replica set so change streams are available
+	mongoContainer, err := mongodb.Run(ctx, "mongo:7", mongodb.WithReplicaSet("rs0"))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to start MongoDB container: %v\n", err)
 		os.Exit(1)
@@ -73,7 +74,7 @@ func TestMain(m *testing.M) {
 	}
 
 	// Connect to databases
-	testMongoDB, err = db.ConnectMongo(mongoURI)
+	testMongoDB, err = db.ConnectMongo(mongoURI, db.MongoOptions{})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to connect to MongoDB: %v\n", err)
 		os.Exit(1)
@@ -90,6 +91,9 @@ func TestMain(m *testing.M) {
 	// Run tests
 	code := m.Run()
 
+	// Started on demand by the PostgreSQL storage tests
+	terminatePostgres(ctx)
+
 	os.Exit(code)
 }
 
@@ -100,43 +104,65 @@ func createTestServer(t *testing.T, cfg *config.Config, dbName string) *httptest
 	// Create isolated database connection
 	isolatedMongo := testMongoDB.WithDatabase(dbName)
 
-	// Initialize repositories with isolated DB
-	entryRepo := models.NewEntryRepository(isolatedMongo)
-	userRepo := models.NewUserRepository(isolatedMongo)
-	idempotencyRepo := models.NewIdempotencyRepository(isolatedMongo)
+	// The filter is named after the isolated database so parallel servers don't share bits.
+	// Shared Redis is fine for the rate limiter, keys are isolated by user/request.
+	deps := app.Dependencies{Mongo: isolatedMongo, ReadConfig: config.Read, KeyFilterName: dbName}
+	switch cfg.Storage {
+	case config.StorageMemory:
+		deps.Mongo = nil
+	case config.StoragePostgres:
+		deps.Postgres = createTestPostgres(t, dbName)
+		deps.Redis = testRedisDB
+	default:
+		deps.Redis = testRedisDB
+	}
 
-	// Ensure indexes on the new isolated DB
-	ctx := context.Background()
-	if err := entryRepo.EnsureIndexes(ctx); err != nil {
-		t.Fatalf("Failed to ensure entry indexes: %v", err)
+	// Tokens are signed with the shared secret unless a test asks for key pairs
+	if cfg.JWTAlgorithm == "" {
+		cfg.JWTAlgorithm = jwtkeys.HS256
 	}
-	if err := userRepo.EnsureIndexes(ctx); err != nil {
-		t.Fatalf("Failed to ensure user indexes: %v", err)
+	if cfg.EventSource == "" {
+		cfg.EventSource = config.EventSourceInline
 	}
-	if err := idempotencyRepo.EnsureIndexes(ctx); err != nil {
-		t.Fatalf("Failed to ensure idempotency indexes: %v", err)
+	if cfg.RateLimitInitialFill == 0 {
+		cfg.RateLimitInitialFill = 1
+	}
+	if cfg.KeyFilterEnabled {
+		cfg.KeyFilterCapacity = 1000
+		cfg.KeyFilterFPRate = 0.01
 	}
 
-	// Initialize rate limiter (shared Redis is fine, keys are isolated by user/request)
-	rateLimitBucket := ratelimit.NewBucket(testRedisDB.Client)
-	mwManager := middleware.NewManager(idempotencyRepo, rateLimitBucket, cfg.RateLimitEnabled)
-
-	// Initialize handlers
-	authHandler := auth.NewHandler(userRepo, cfg.JWTSecret)
-	entriesHandler := entries.NewHandler(entryRepo)
+	// Short backoff so retry tests don't wait on production delays
+	cfg.WebhookTimeout = 2 * time.Second
+	cfg.WebhookMaxAttempts = 3
+	cfg.WebhookInitialBackoff = 50 * time.Millisecond
+	cfg.WebhookMaxBackoff = 200 * time.Millisecond
+	cfg.OutboxPollInterval = 20 * time.Millisecond
+	cfg.OutboxMaxBackoff = 100 * time.Millisecond
 
-	// Setup router with default policies
-	handler := router.Setup(cfg, authHandler, entriesHandler, mwManager, ratelimit.DefaultPolicies())
+	// Each server gets its own clock so advancing time doesn't leak into parallel tests
+	application, err := app.BuildApplication(context.Background(), cfg, deps)
+	if err != nil {
+		t.Fatalf("Failed to build the application: %v", err)
+	}
+	application.Start()
 
-	srv := httptest.NewServer(handler)
+	srv := httptest.NewServer(application)
 
-	// Register cleanup: Close server first, then Drop DB
+	// Register cleanup: Close server first, then stop the background components and wait for webhook deliveries, then Drop DB
 	// t.Cleanup runs in reverse order of registration
 	t.Cleanup(func() {
 		if err := isolatedMongo.Database.Drop(context.Background()); err != nil {
 			t.Logf("Failed to drop test database %s: %v", dbName, err)
 		}
 	})
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := application.Shutdown(ctx); err != nil {
+			t.Logf("Failed to shut down the application: %v", err)
+		}
+	})
 	t.Cleanup(srv.Close)
 
 	return srv
@@ -152,11 +178,49 @@ func StartRateLimitedServer(t *testing.T) *httptest.Server {
 		RateLimitEnabled:       true,
 		RateLimitBucketSize:    60,
 		RateLimitRefillSeconds: 60,
+		AdminEnabled:           true,
+		AdminToken:             testAdminToken,
 	}
 	dbName := "test_dict_ratelimit_" + uuid.New().String()
 	return createTestServer(t, cfg, dbName)
 }
 
+// StartChangeStreamServer starts a new server whose events come from the entries change stream
+func StartChangeStreamServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	cfg := &config.Config{
+		Port:                   3000,
+		Environment:            "test",
+		JWTSecret:              "test-jwt-secret-for-integration-tests",
+		RateLimitEnabled:       false,
+		RateLimitBucketSize:    60,
+		RateLimitRefillSeconds: 60,
+		AdminEnabled:           true,
+		AdminToken:             testAdminToken,
+		EventSource:            config.EventSourceChangeStream,
+	}
+	dbName := "test_dict_changestream_" + uuid.New().String()
+	return createTestServer(t, cfg, dbName)
+}
+
+// StartOutboxServer starts a new server whose events are written to the outbox with each entry change
+func StartOutboxServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	cfg := &config.Config{
+		Port:                   3000,
+		Environment:            "test",
+		JWTSecret:              "test-jwt-secret-for-integration-tests",
+		RateLimitEnabled:       false,
+		RateLimitBucketSize:    60,
+		RateLimitRefillSeconds: 60,
+		AdminEnabled:           true,
+		AdminToken:             testAdminToken,
+		EventSource:            config.EventSourceOutbox,
+	}
+	dbName := "test_dict_txoutbox_" + uuid.New().String()
+	return createTestServer(t, cfg, dbName)
+}
+
 // TestClient provides HTTP client methods for a specific test
 type TestClient struct {
 	t         *testing.T
@@ -175,6 +239,15 @@ func NewTestClient(t *testing.T) *TestClient {
 		RateLimitEnabled:       false,
 		RateLimitBucketSize:    60,
 		RateLimitRefillSeconds: 60,
+		AdminEnabled:           true,
+		AdminToken:             testAdminToken,
+		TimeTravelEnabled:      true,
+		DocsEnabled:            true,
+		OpenAPIValidation:      true,
+		MaxBodyBytes:           1 << 20,
+		MaxImportBytes:         256 << 20,
+		AccessLogSampleRate:    1,
+		OAuthTokenTTL:          time.Hour,
 	}
 	dbName := "test_dict_" + uuid.New().String()
 	server := createTestServer(t, cfg, dbName)
@@ -230,6 +303,19 @@ func (c *TestClient) registerTestUser() string {
 func (c *TestClient) Request(method, path string, body any, headers map[string]string) *http.Response {
 	c.t.Helper()
 
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(c.NewRequest(method, path, body, headers))
+	if err != nil {
+		c.t.Fatalf("Failed to make request: %v", err)
+	}
+
+	return resp
+}
+
+// NewRequest builds the request Request sends, for tests that send it themselves (see testkit.Run)
+func (c *TestClient) NewRequest(method, path string, body any, headers map[string]string) *http.Request {
+	c.t.Helper()
+
 	var bodyReader io.Reader
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
@@ -250,19 +336,18 @@ func (c *TestClient) Request(method, path string, body any, headers map[string]s
 	if c.authToken != "" {
 		req.Header.Set("Authorization", "Bearer "+c.authToken)
 	}
+	// Admin routes take the admin token instead of the user's JWT, and users only name a participant
+	// alongside it
+	if strings.HasPrefix(path, "/admin/") || headers[middleware.IdentifierHeader] != "" {
+		req.Header.Set(middleware.AdminTokenHeader, testAdminToken)
+	}
 
 	// Add custom headers
 	for k, v := range headers {
 		req.Header.Set(k, v)
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		c.t.Fatalf("Failed to make request: %v", err)
-	}
-
-	return resp
+	return req
 }
 
 // PostNoAuth makes a POST request without auth (for register/login)
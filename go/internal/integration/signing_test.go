@@ -0,0 +1,144 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dict-simulator/go/internal/config"
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/middleware"
+	"github.com/dict-simulator/go/internal/signing"
+)
+
+const (
+	signingParticipant = "12345678"
+	signingSecret      = "participant-signing-secret"
+)
+
+// StartSigningServer starts a new server that requires signed requests on the entries routes
+func StartSigningServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	cfg := &config.Config{
+		Port:                  3000,
+		Environment:           "test",
+		JWTSecret:             "test-jwt-secret-for-integration-tests",
+		AdminEnabled:          true,
+		AdminToken:            testAdminToken,
+		OpenAPIValidation:     true,
+		RequestSigningEnabled: true,
+		RequestSigningSecrets: signing.Secrets{signingParticipant: signingSecret},
+		RequestSigningMaxSkew: 5 * time.Minute,
+	}
+	dbName := "test_dict_signing_" + uuid.New().String()
+	return createTestServer(t, cfg, dbName)
+}
+
+// signedHeaders signs a request the way TestClient.Request will send it
+func signedHeaders(t *testing.T, secret, method, path string, body any, signedAt time.Time) map[string]string {
+	t.Helper()
+
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	nonce := uuid.New().String()
+	return map[string]string{
+		middleware.IdentifierHeader: signingParticipant,
+		signing.TimestampHeader:     timestamp,
+		signing.NonceHeader:         nonce,
+		signing.SignatureHeader:     signing.Sign(secret, timestamp, nonce, method, path, payload),
+	}
+}
+
+func TestSigning_SignedRequestsAccepted(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClientForServer(t, StartSigningServer(t))
+	key := GenerateValidCPF()
+	body := CreateEntryRequest(key)
+
+	headers := signedHeaders(t, signingSecret, http.MethodPost, "/entries", body, time.Now())
+	headers[middleware.IdempotencyKeyHeader] = uuid.New().String()
+	created := client.Request(http.MethodPost, "/entries", body, headers)
+	defer created.Body.Close()
+	require.Equal(t, http.StatusCreated, created.StatusCode)
+
+	found := client.GETWithHeaders("/entries/"+key, signedHeaders(t, signingSecret, http.MethodGet, "/entries/"+key, nil, time.Now()))
+	defer found.Body.Close()
+	assert.Equal(t, http.StatusOK, found.StatusCode)
+}
+
+func TestSigning_RejectsBadSignatures(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClientForServer(t, StartSigningServer(t))
+	path := "/entries/" + GenerateValidCPF()
+
+	replayed := signedHeaders(t, signingSecret, http.MethodGet, path, nil, time.Now())
+	first := client.GETWithHeaders(path, replayed)
+	first.Body.Close()
+	require.Equal(t, http.StatusNotFound, first.StatusCode, "the first use of a nonce reaches the handler")
+
+	unknown := signedHeaders(t, signingSecret, http.MethodGet, path, nil, time.Now())
+	unknown[middleware.IdentifierHeader] = "87654321"
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		wantCode string
+	}{
+		{
+			name:     "unsigned",
+			headers:  nil,
+			wantCode: constants.CodeSignatureRequired,
+		},
+		{
+			name:     "participant without a secret",
+			headers:  unknown,
+			wantCode: constants.CodeInvalidSignature,
+		},
+		{
+			name:     "wrong secret",
+			headers:  signedHeaders(t, "not-the-secret", http.MethodGet, path, nil, time.Now()),
+			wantCode: constants.CodeInvalidSignature,
+		},
+		{
+			name:     "signed for another path",
+			headers:  signedHeaders(t, signingSecret, http.MethodGet, "/entries/"+GenerateValidCPF(), nil, time.Now()),
+			wantCode: constants.CodeInvalidSignature,
+		},
+		{
+			name:     "stale timestamp",
+			headers:  signedHeaders(t, signingSecret, http.MethodGet, path, nil, time.Now().Add(-10*time.Minute)),
+			wantCode: constants.CodeSignatureExpired,
+		},
+		{
+			name:     "replayed nonce",
+			headers:  replayed,
+			wantCode: constants.CodeSignatureReplayed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := client.GETWithHeaders(path, tt.headers)
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			apiResp := ParseResponse[httputil.APIResponse](t, resp)
+			assert.Equal(t, tt.wantCode, apiResp.Error)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestMongoTransaction_CommitsAllWrites(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mongo := testMongoDB.WithDatabase("test_dict_tx_" + uuid.New().String())
+
+	err := mongo.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := mongo.Collection("entries").InsertOne(ctx, bson.M{"key": "a"}); err != nil {
+			return err
+		}
+		_, err := mongo.Collection("audit").InsertOne(ctx, bson.M{"key": "a"})
+		return err
+	})
+	require.NoError(t, err)
+
+	entries, err := mongo.Collection("entries").CountDocuments(ctx, bson.M{})
+	require.NoError(t, err)
+	audit, err := mongo.Collection("audit").CountDocuments(ctx, bson.M{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), entries)
+	assert.Equal(t, int64(1), audit)
+}
+
+func TestMongoTransaction_AbortsOnError(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mongo := testMongoDB.WithDatabase("test_dict_tx_" + uuid.New().String())
+
+	_, err := mongo.Collection("entries").InsertOne(ctx, bson.M{"key": "a"})
+	require.NoError(t, err)
+
+	failure := errors.New("audit write failed")
+	err = mongo.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := mongo.Collection("entries").DeleteOne(ctx, bson.M{"key": "a"}); err != nil {
+			return err
+		}
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	entries, err := mongo.Collection("entries").CountDocuments(ctx, bson.M{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), entries, "the delete must be rolled back")
+}
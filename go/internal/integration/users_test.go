@@ -0,0 +1,95 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type userData struct {
+	Data struct {
+		ID       string `json:"id"`
+		Email    string `json:"email"`
+		Name     string `json:"name"`
+		Disabled bool   `json:"disabled"`
+	} `json:"data"`
+}
+
+func TestUserManagement(t *testing.T) {
+	t.Parallel()
+
+	assertUserManagement(t, NewTestClient(t))
+}
+
+func TestPostgresStorage_UserManagement(t *testing.T) {
+	t.Parallel()
+
+	assertUserManagement(t, NewTestClientForServer(t, StartPostgresServer(t)))
+}
+
+// assertUserManagement runs the profile and admin user routes against the client's storage
+func assertUserManagement(t *testing.T, client *TestClient) {
+	t.Helper()
+
+	me := client.GET("/auth/me")
+	defer me.Body.Close()
+	require.Equal(t, http.StatusOK, me.StatusCode)
+	user := ParseResponse[userData](t, me)
+	assert.False(t, user.Data.Disabled)
+
+	email := fmt.Sprintf("renamed-%s@example.com", uuid.New().String()[:8])
+	updated := client.PUT("/auth/me", map[string]string{"email": email})
+	defer updated.Body.Close()
+	require.Equal(t, http.StatusOK, updated.StatusCode)
+	profile := ParseResponse[userData](t, updated)
+	assert.Equal(t, email, profile.Data.Email)
+	assert.Equal(t, "Test User", profile.Data.Name, "fields left out are kept")
+
+	// A second user can't take the email
+	other := &TestClient{t: t, baseURL: client.baseURL}
+	other.authToken = other.registerTestUser()
+	taken := other.PUT("/auth/me", map[string]string{"email": email})
+	defer taken.Body.Close()
+	assert.Equal(t, http.StatusConflict, taken.StatusCode)
+
+	listed := client.GET("/admin/users?limit=1")
+	defer listed.Body.Close()
+	require.Equal(t, http.StatusOK, listed.StatusCode)
+	page := ParseResponse[struct {
+		Data struct {
+			Users      []struct{ ID string } `json:"users"`
+			NextCursor string                `json:"nextCursor"`
+		} `json:"data"`
+	}](t, listed)
+	require.Len(t, page.Data.Users, 1)
+	assert.Equal(t, user.Data.ID, page.Data.Users[0].ID, "users are listed in creation order")
+	assert.Equal(t, user.Data.ID, page.Data.NextCursor)
+
+	disabled := client.POST("/admin/users/"+user.Data.ID+"/disable", nil)
+	defer disabled.Body.Close()
+	require.Equal(t, http.StatusOK, disabled.StatusCode)
+
+	refused := client.GET("/auth/me")
+	defer refused.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, refused.StatusCode, "tokens of disabled users are refused")
+
+	login := client.PostNoAuth("/auth/login", map[string]string{"email": email, "password": "testpassword123"})
+	defer login.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, login.StatusCode)
+
+	enabled := client.POST("/admin/users/"+user.Data.ID+"/enable", nil)
+	defer enabled.Body.Close()
+	require.Equal(t, http.StatusOK, enabled.StatusCode)
+
+	deleted := client.Request(http.MethodDelete, "/admin/users/"+user.Data.ID, nil, nil)
+	defer deleted.Body.Close()
+	require.Equal(t, http.StatusOK, deleted.StatusCode)
+
+	gone := client.GET("/auth/me")
+	defer gone.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, gone.StatusCode, "tokens of deleted users are refused")
+}
@@ -0,0 +1,235 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dict-simulator/go/internal/webhook"
+)
+
+// callback is a request received by a test webhook receiver
+type callback struct {
+	header http.Header
+	body   []byte
+}
+
+// startReceiver starts a webhook receiver that answers with the given status codes in order
+// (the last one repeats) and forwards every callback it receives
+func startReceiver(t *testing.T, statuses ...int) (*httptest.Server, <-chan callback) {
+	t.Helper()
+
+	received := make(chan callback, 10)
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- callback{header: r.Header.Clone(), body: body}
+
+		n := int(calls.Add(1))
+		w.WriteHeader(statuses[min(n, len(statuses))-1])
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, received
+}
+
+// registerWebhook registers a webhook for participant 12345678 and returns its ID and secret
+func registerWebhook(t *testing.T, client *TestClient, url string, events ...string) (string, string) {
+	t.Helper()
+
+	resp := client.POST("/webhooks", map[string]any{
+		"participant": "12345678",
+		"url":         url,
+		"events":      events,
+	})
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	created := ParseResponse[struct {
+		Code string `json:"code"`
+		Data struct {
+			ID     string `json:"id"`
+			Secret string `json:"secret"`
+		} `json:"data"`
+	}](t, resp)
+	require.Equal(t, "WEBHOOK_CREATED", created.Code)
+	require.NotEmpty(t, created.Data.Secret)
+
+	return created.Data.ID, created.Data.Secret
+}
+
+func awaitCallback(t *testing.T, received <-chan callback) callback {
+	t.Helper()
+
+	select {
+	case cb := <-received:
+		return cb
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for webhook callback")
+		return callback{}
+	}
+}
+
+func TestWebhooks_EntryCreatedIsSignedAndDelivered(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+	receiver, received := startReceiver(t, http.StatusOK)
+
+	_, secret := registerWebhook(t, client, receiver.URL, "ENTRY_CREATED", "ENTRY_DELETED")
+
+	cpf := client.CreateEntry()
+	cb := awaitCallback(t, received)
+
+	assert.Equal(t, "ENTRY_CREATED", cb.header.Get(webhook.EventTypeHeader))
+	assert.Equal(t, "1", cb.header.Get(webhook.AttemptHeader))
+
+	// Verify the signature the way a participant would
+	signature := cb.header.Get(webhook.SignatureHeader)
+	ts, _, ok := strings.Cut(strings.TrimPrefix(signature, "t="), ",")
+	require.True(t, ok, "malformed signature %q", signature)
+	timestamp, err := strconv.ParseInt(ts, 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, webhook.Sign(secret, timestamp, cb.body), signature)
+
+	var event struct {
+		ID          string `json:"id"`
+		Type        string `json:"type"`
+		Participant string `json:"participant"`
+		Data        struct {
+			Key string `json:"key"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(cb.body, &event))
+	assert.Equal(t, cb.header.Get(webhook.EventIDHeader), event.ID)
+	assert.Equal(t, "ENTRY_CREATED", event.Type)
+	assert.Equal(t, "12345678", event.Participant)
+	assert.Equal(t, cpf, event.Data.Key)
+
+	resp := client.DeleteEntry(cpf, "12345678", "USER_REQUESTED")
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	cb = awaitCallback(t, received)
+	assert.Equal(t, "ENTRY_DELETED", cb.header.Get(webhook.EventTypeHeader))
+}
+
+func TestWebhooks_RetriesAndLogsDeliveries(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+	receiver, received := startReceiver(t, http.StatusInternalServerError, http.StatusOK)
+
+	id, _ := registerWebhook(t, client, receiver.URL, "ENTRY_CREATED")
+
+	client.CreateEntry()
+	first := awaitCallback(t, received)
+	second := awaitCallback(t, received)
+	assert.Equal(t, "1", first.header.Get(webhook.AttemptHeader))
+	assert.Equal(t, "2", second.header.Get(webhook.AttemptHeader))
+
+	type delivery struct {
+		Attempt    int  `json:"attempt"`
+		StatusCode int  `json:"statusCode"`
+		Success    bool `json:"success"`
+	}
+
+	// The delivery log is written after each attempt completes, so poll until both are in
+	var deliveries []delivery
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		resp := client.GET("/webhooks/" + id + "/deliveries")
+		deliveries = ParseResponse[struct {
+			Data []delivery `json:"data"`
+		}](t, resp).Data
+		resp.Body.Close()
+		if len(deliveries) == 2 {
+			break
+		}
+	}
+	require.Len(t, deliveries, 2)
+
+	// Newest first
+	assert.Equal(t, delivery{Attempt: 2, StatusCode: http.StatusOK, Success: true}, deliveries[0])
+	assert.Equal(t, delivery{Attempt: 1, StatusCode: http.StatusInternalServerError, Success: false}, deliveries[1])
+}
+
+func TestWebhooks_OnlySubscribedEvents(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+	receiver, received := startReceiver(t, http.StatusOK)
+
+	registerWebhook(t, client, receiver.URL, "ENTRY_DELETED")
+
+	cpf := client.CreateEntry()
+	resp := client.DeleteEntry(cpf, "12345678", "USER_REQUESTED")
+	resp.Body.Close()
+
+	// ENTRY_CREATED was not subscribed, so the first callback is the deletion
+	cb := awaitCallback(t, received)
+	assert.Equal(t, "ENTRY_DELETED", cb.header.Get(webhook.EventTypeHeader))
+}
+
+func TestWebhooks_ListAndDelete(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	id, _ := registerWebhook(t, client, "https://psp.example.com/callbacks", "ENTRY_CREATED")
+
+	resp := client.GET("/webhooks?participant=12345678")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	listed := ParseResponse[struct {
+		Data []struct {
+			ID     string `json:"id"`
+			Secret string `json:"secret"`
+		} `json:"data"`
+	}](t, resp)
+	require.Len(t, listed.Data, 1)
+	assert.Equal(t, id, listed.Data[0].ID)
+	assert.Empty(t, listed.Data[0].Secret, "secret must not be listed")
+
+	deleted := client.Request(http.MethodDelete, "/webhooks/"+id, nil, nil)
+	deleted.Body.Close()
+	assert.Equal(t, http.StatusOK, deleted.StatusCode)
+
+	again := client.Request(http.MethodDelete, "/webhooks/"+id, nil, nil)
+	again.Body.Close()
+	assert.Equal(t, http.StatusNotFound, again.StatusCode)
+}
+
+func TestWebhooks_InvalidRegistration(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t)
+
+	testCases := []struct {
+		name string
+		body map[string]any
+	}{
+		{"missing url", map[string]any{"participant": "12345678", "events": []string{"ENTRY_CREATED"}}},
+		{"unknown event", map[string]any{"participant": "12345678", "url": "https://psp.example.com", "events": []string{"ENTRY_VIEWED"}}},
+		{"no events", map[string]any{"participant": "12345678", "url": "https://psp.example.com", "events": []string{}}},
+		{"short secret", map[string]any{"participant": "12345678", "url": "https://psp.example.com", "events": []string{"ENTRY_CREATED"}, "secret": "short"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := client.POST("/webhooks", tc.body)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package jobs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/logger"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/scheduler"
+)
+
+// UnfinishedIdempotencyAge is how old a claim without a saved response must be before it is purged
+// Far longer than any request can take (the server's write timeout is 15s).
+const UnfinishedIdempotencyAge = 5 * time.Minute
+
+var entriesByKeyType = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "dict_entries",
+		Help: "Number of registered entries by key type, recomputed by the statistics job",
+	},
+	[]string{"key_type"},
+)
+
+var retentionPurgedTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "retention_purged_records_total",
+		Help: "Records deleted by the retention job, by collection",
+	},
+	[]string{"collection"},
+)
+
+// keyTypes are reported even when no entry has them, so a type whose last entry is deleted drops to 0
+var keyTypes = []models.KeyType{
+	models.KeyTypeCPF,
+	models.KeyTypeCNPJ,
+	models.KeyTypeEMAIL,
+	models.KeyTypePHONE,
+	models.KeyTypeEVP,
+}
+
+// Heartbeat does nothing; its scheduler_job_last_success_timestamp_seconds shows the scheduler is alive
+func Heartbeat(context.Context) error {
+	return nil
+}
+
+// PurgeUnfinishedIdempotency deletes idempotency claims whose request never saved a response
+// Such a claim (StatusCode 0) would otherwise be replayed as an empty response until it expires.
+// Age is measured on the simulated clock, which stamps the records.
+func PurgeUnfinishedIdempotency(repo models.IdempotencyRepository, clk clock.Clock) scheduler.Func {
+	return func(ctx context.Context) error {
+		deleted, err := repo.DeleteUnfinished(ctx, clk.Now().Add(-UnfinishedIdempotencyAge))
+		if err != nil {
+			return err
+		}
+		if deleted > 0 {
+			logger.Info("Purged unfinished idempotency claims", zap.Int64("count", deleted))
+		}
+		return nil
+	}
+}
+
+// EntryStatistics recomputes the dict_entries gauge from storage
+func EntryStatistics(repo models.EntryRepository) scheduler.Func {
+	return func(ctx context.Context) error {
+		counts, err := repo.CountByKeyType(ctx)
+		if err != nil {
+			return err
+		}
+		for _, keyType := range keyTypes {
+			entriesByKeyType.WithLabelValues(string(keyType)).Set(float64(counts[keyType]))
+		}
+		return nil
+	}
+}
+
+// ReconciliationFiles generates each participant's verification file for the current day
+// The day is taken from the simulated clock, so advancing it produces the next day's files.
+// Once any file exists for the day the run does nothing; a replica racing another skips the
+// files the other already stored.
+func ReconciliationFiles(entryRepo models.EntryRepository, fileRepo models.ReconciliationFileRepository, clk clock.Clock) scheduler.Func {
+	return func(ctx context.Context) error {
+		now := clk.Now().UTC()
+		date := now.Format(models.ReconciliationDateLayout)
+
+		done, err := fileRepo.HasDate(ctx, date)
+		if err != nil || done {
+			return err
+		}
+
+		// Entries come in key order, so each participant's rows do too
+		rows := map[string][]models.ReconciliationRow{}
+		err = entryRepo.ForEachEntry(ctx, func(entry *models.Entry) error {
+			rows[entry.Account.Participant] = append(rows[entry.Account.Participant], models.NewReconciliationRow(entry))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		created := 0
+		for participant, participantRows := range rows {
+			err := fileRepo.Create(ctx, &models.ReconciliationFile{
+				Participant: participant,
+				Date:        date,
+				Entries:     len(participantRows),
+				Rows:        participantRows,
+				CreatedAt:   now,
+			})
+			if errors.Is(err, models.ErrReconciliationFileExists) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			created++
+		}
+		if created > 0 {
+			logger.Info("Generated reconciliation files", zap.String("date", date), zap.Int("count", created))
+		}
+		return nil
+	}
+}
+
+// RFBReview simulates the DICT's periodic review of CPF and CNPJ keys against the Receita Federal
+// Each key is reviewed once per simulated (UTC) day. An owner's status is irregular on a given day
+// for a fraction irregularRate of tax IDs, picked by hashing the tax ID and the day, so replicas
+// reviewing at once agree. Irregular keys move to PENDING_RFB_VALIDATION until their participant
+// updates them with reason RFB_VALIDATION.
+func RFBReview(repo models.EntryRepository, clk clock.Clock, irregularRate float64) scheduler.Func {
+	return func(ctx context.Context) error {
+		day := clk.Now().UTC().Truncate(24 * time.Hour)
+		flagged, err := repo.ReviewRFB(ctx, day, func(owner models.Owner) bool {
+			return rfbIrregular(owner.TaxIdNumber, day, irregularRate)
+		})
+		if err != nil {
+			return err
+		}
+		if len(flagged) > 0 {
+			logger.Info("Flagged keys pending RFB validation", zap.Int("count", len(flagged)))
+		}
+		return nil
+	}
+}
+
+// rfbIrregular reports whether the simulated Receita Federal finds taxID irregular on day
+func rfbIrregular(taxID string, day time.Time, rate float64) bool {
+	sum := sha256.Sum256([]byte(taxID + "@" + day.Format(time.DateOnly)))
+	// The top 53 bits, as a float64 in [0, 1)
+	return float64(binary.BigEndian.Uint64(sum[:8])>>11)/(1<<53) < rate
+}
+
+// RetentionPolicy deletes a collection's records once they are older than Retention
+type RetentionPolicy struct {
+	Collection string        // metric label and log field, e.g. admin_audit
+	Retention  time.Duration // 0 keeps the records forever
+	// Purge deletes the records older than cutoff and returns how many
+	Purge func(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// Retention applies each policy, measuring age on the simulated clock, which stamps the records.
+// A failing policy doesn't stop the others; their errors are returned together.
+func Retention(policies []RetentionPolicy, clk clock.Clock) scheduler.Func {
+	return func(ctx context.Context) error {
+		now := clk.Now()
+		var errs []error
+		for _, policy := range policies {
+			if policy.Retention <= 0 {
+				continue
+			}
+			deleted, err := policy.Purge(ctx, now.Add(-policy.Retention))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", policy.Collection, err))
+				continue
+			}
+			if deleted > 0 {
+				retentionPurgedTotal.WithLabelValues(policy.Collection).Add(float64(deleted))
+				logger.Info("Purged records past their retention",
+					zap.String("collection", policy.Collection),
+					zap.Int64("count", deleted),
+				)
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
@@ -0,0 +1,184 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/listing"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// createEntry registers an EVP entry owned by participant
+func createEntry(t *testing.T, repo models.EntryRepository, key, participant string) {
+	t.Helper()
+
+	_, err := repo.Create(context.Background(), &models.CreateEntryRequest{
+		Key:     key,
+		KeyType: models.KeyTypeEVP,
+		Account: models.Account{Participant: participant, Branch: "0001", AccountNumber: "0007654321", AccountType: "CACC"},
+		Owner:   models.Owner{Type: models.OwnerTypeNaturalPerson, TaxIdNumber: "52998224725", Name: "Test User"},
+	})
+	if err != nil {
+		t.Fatalf("Create(%q): %v", key, err)
+	}
+}
+
+func TestReconciliationFiles(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewSimulated()
+	entryRepo := models.NewMemoryEntryRepository(clk)
+	fileRepo := models.NewMemoryReconciliationFileRepository()
+	job := ReconciliationFiles(entryRepo, fileRepo, clk)
+
+	createEntry(t, entryRepo, "123e4567-e89b-42d3-a456-426614174002", "12345678")
+	createEntry(t, entryRepo, "123e4567-e89b-42d3-a456-426614174001", "12345678")
+	createEntry(t, entryRepo, "123e4567-e89b-42d3-a456-426614174003", "87654321")
+
+	if err := job(ctx); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+
+	files, err := fileRepo.FindByParticipant(ctx, "12345678")
+	if err != nil {
+		t.Fatalf("FindByParticipant: %v", err)
+	}
+	if len(files) != 1 || files[0].Entries != 2 {
+		t.Fatalf("files of 12345678 = %+v, want one file of 2 entries", files)
+	}
+	if want := clk.Now().UTC().Format(models.ReconciliationDateLayout); files[0].Date != want {
+		t.Errorf("file date = %s, want %s", files[0].Date, want)
+	}
+
+	file, err := fileRepo.FindByID(ctx, files[0].ID)
+	if err != nil || file == nil {
+		t.Fatalf("FindByID = %v, %v", file, err)
+	}
+	if file.Rows[0].Key != "123e4567-e89b-42d3-a456-426614174001" {
+		t.Errorf("first row key = %s, want rows in key order", file.Rows[0].Key)
+	}
+
+	// A second run the same day leaves the day's files alone
+	createEntry(t, entryRepo, "123e4567-e89b-42d3-a456-426614174004", "12345678")
+	if err := job(ctx); err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if files, _ := fileRepo.FindByParticipant(ctx, "12345678"); len(files) != 1 {
+		t.Fatalf("files of 12345678 after a second run = %d, want 1", len(files))
+	}
+
+	// The next simulated day gets its own file
+	clk.Advance(24 * time.Hour)
+	if err := job(ctx); err != nil {
+		t.Fatalf("next day run: %v", err)
+	}
+	files, _ = fileRepo.FindByParticipant(ctx, "12345678")
+	if len(files) != 2 || files[0].Entries != 3 {
+		t.Fatalf("files of 12345678 on the next day = %+v, want the newest first with 3 entries", files)
+	}
+}
+
+func TestRFBReview(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewSimulated()
+	repo := models.NewMemoryEntryRepository(clk)
+
+	createEntry(t, repo, "123e4567-e89b-42d3-a456-426614174001", "12345678")
+	_, err := repo.Create(ctx, &models.CreateEntryRequest{
+		Key:     "52998224725",
+		KeyType: models.KeyTypeCPF,
+		Account: models.Account{Participant: "12345678", Branch: "0001", AccountNumber: "0007654321", AccountType: "CACC"},
+		Owner:   models.Owner{Type: models.OwnerTypeNaturalPerson, TaxIdNumber: "52998224725", Name: "Test User"},
+	})
+	if err != nil {
+		t.Fatalf("Create CPF entry: %v", err)
+	}
+
+	// Every owner is irregular at rate 1, but only CPF and CNPJ keys are reviewed
+	job := RFBReview(repo, clk, 1)
+	if err := job(ctx); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if entry, _ := repo.FindByKey(ctx, "123e4567-e89b-42d3-a456-426614174001"); entry.ToResponse().Status != models.EntryStatusActive {
+		t.Errorf("EVP key status = %s, want ACTIVE", entry.Status)
+	}
+	entry, _ := repo.FindByKey(ctx, "52998224725")
+	if entry.Status != models.EntryStatusPendingRFBValidation {
+		t.Fatalf("CPF key status = %s, want PENDING_RFB_VALIDATION", entry.Status)
+	}
+
+	// Only an update with reason RFB_VALIDATION clears it
+	update := &models.UpdateEntryRequest{Key: "52998224725", Participant: "12345678", Owner: &models.UpdateOwner{Name: "Renamed"}, Reason: models.ReasonUserRequested}
+	if entry, _ := repo.UpdateByKeyAndParticipant(ctx, "52998224725", "12345678", update); entry.Status != models.EntryStatusPendingRFBValidation {
+		t.Errorf("status after a USER_REQUESTED update = %s, want PENDING_RFB_VALIDATION", entry.Status)
+	}
+	update.Reason = models.ReasonRFBValidation
+	if entry, _ := repo.UpdateByKeyAndParticipant(ctx, "52998224725", "12345678", update); entry.Status != models.EntryStatusActive {
+		t.Errorf("status after an RFB_VALIDATION update = %s, want ACTIVE", entry.Status)
+	}
+
+	// A validated key is not reviewed again until the next simulated day
+	if err := job(ctx); err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if entry, _ := repo.FindByKey(ctx, "52998224725"); entry.Status != models.EntryStatusActive {
+		t.Errorf("status after a second run the same day = %s, want ACTIVE", entry.Status)
+	}
+	clk.Advance(24 * time.Hour)
+	if err := job(ctx); err != nil {
+		t.Fatalf("next day run: %v", err)
+	}
+	if entry, _ := repo.FindByKey(ctx, "52998224725"); entry.Status != models.EntryStatusPendingRFBValidation {
+		t.Errorf("status after the next day's run = %s, want PENDING_RFB_VALIDATION", entry.Status)
+	}
+}
+
+func TestRFBIrregularRate(t *testing.T) {
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	irregular := 0
+	for i := range 10000 {
+		if rfbIrregular(fmt.Sprintf("%011d", i), day, 0.1) {
+			irregular++
+		}
+	}
+	if irregular < 900 || irregular > 1100 {
+		t.Errorf("%d of 10000 tax IDs irregular at rate 0.1, want about 1000", irregular)
+	}
+}
+
+func TestRetention(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewSimulated()
+	audit := models.NewMemoryAdminAuditRepository(clk)
+
+	if err := audit.Create(ctx, &models.AdminAuditRecord{Action: "admin.seed"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	clk.Advance(2 * time.Hour)
+	if err := audit.Create(ctx, &models.AdminAuditRecord{Action: "admin.reset"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	job := Retention([]RetentionPolicy{
+		{Collection: "failing", Retention: time.Hour, Purge: func(context.Context, time.Time) (int64, error) {
+			return 0, errors.New("unavailable")
+		}},
+		{Collection: "kept", Retention: 0, Purge: func(context.Context, time.Time) (int64, error) {
+			t.Error("a policy with no retention was applied")
+			return 0, nil
+		}},
+		{Collection: "admin_audit", Retention: time.Hour, Purge: audit.DeleteCreatedBefore},
+	}, clk)
+
+	if err := job(ctx); err == nil || !strings.Contains(err.Error(), "failing: unavailable") {
+		t.Errorf("job error = %v, want the failing policy reported", err)
+	}
+	records, _ := audit.List(ctx, models.AdminAuditFilter{Page: listing.Page{Limit: 10, Descending: true}})
+	if len(records) != 1 || records[0].Action != "admin.reset" {
+		t.Errorf("records = %+v, want only the one newer than an hour", records)
+	}
+}
@@ -0,0 +1,310 @@
+package jwtkeys
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Signing algorithms
+const (
+	HS256 = "HS256" // shared JWT_SECRET; nothing is published
+	RS256 = "RS256" // RSA 2048 key pairs
+	ES256 = "ES256" // ECDSA P-256 key pairs
+)
+
+// refreshTimeout bounds the store lookup made when a token names a key this replica has not loaded
+const refreshTimeout = 2 * time.Second
+
+// ErrUnknownKey is returned for tokens signed by a key that is not (or no longer) in the set
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// Key is one signing key pair
+type Key struct {
+	ID        string
+	Algorithm string
+	Private   crypto.Signer
+	CreatedAt time.Time
+}
+
+// Store keeps the key pairs shared by every replica
+// Implemented by RedisStore and MemoryStore (STORAGE=memory).
+type Store interface {
+	// Keys returns every stored key, newest first
+	Keys(ctx context.Context) ([]Key, error)
+	// Add stores a new key
+	Add(ctx context.Context, key Key) error
+	// Remove deletes keys by ID
+	Remove(ctx context.Context, ids ...string) error
+}
+
+// Config selects how tokens are signed
+type Config struct {
+	Algorithm string
+	Secret    string        // HS256 only
+	Rotation  time.Duration // age at which a new key pair takes over signing
+	Retention time.Duration // how long a replaced key still verifies tokens: the longest token lifetime
+}
+
+// KeySet signs and verifies JWTs
+// With RS256 or ES256 tokens are signed by the newest key pair in the store and carry its ID as kid;
+// replaced keys are kept, and published, until the tokens they signed have expired.
+// With HS256 the shared secret is used and the store is never read.
+type KeySet struct {
+	cfg   Config
+	store Store
+	now   func() time.Time // wall clock, like JWT expiry; replaced in tests
+
+	mu   sync.RWMutex
+	keys []Key // newest first
+}
+
+// New creates a key set; asymmetric sets hold no key until Rotate is called
+func New(cfg Config, store Store) *KeySet {
+	return &KeySet{
+		cfg:   cfg,
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// Algorithm returns the JWT alg tokens are signed with
+func (s *KeySet) Algorithm() string {
+	return s.cfg.Algorithm
+}
+
+// Rotate adds a key pair when the newest one is older than the rotation interval, drops keys
+// replaced longer than the retention ago, and reloads the set from the store
+// Replicas rotating at the same moment may each add a key; all of them verify, the newest signs.
+func (s *KeySet) Rotate(ctx context.Context) error {
+	if s.cfg.Algorithm == HS256 {
+		return nil
+	}
+
+	keys, err := s.store.Keys(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Left over from another JWT_SIGNING_ALG; the tokens they signed are refused anyway
+	var expired []string
+	for _, key := range keys {
+		if key.Algorithm != s.cfg.Algorithm {
+			expired = append(expired, key.ID)
+		}
+	}
+	keys = slices.DeleteFunc(keys, func(k Key) bool { return k.Algorithm != s.cfg.Algorithm })
+
+	now := s.now()
+	if len(keys) == 0 || !now.Before(keys[0].CreatedAt.Add(s.cfg.Rotation)) {
+		key, err := Generate(s.cfg.Algorithm, now)
+		if err != nil {
+			return err
+		}
+		if err := s.store.Add(ctx, key); err != nil {
+			return err
+		}
+		keys = append([]Key{key}, keys...)
+	}
+
+	// A key stops signing when the next one is created
+	for i := 1; i < len(keys); i++ {
+		if keys[i-1].CreatedAt.Add(s.cfg.Retention).Before(now) {
+			expired = append(expired, keys[i].ID)
+		}
+	}
+	if len(expired) > 0 {
+		if err := s.store.Remove(ctx, expired...); err != nil {
+			return err
+		}
+		keys = slices.DeleteFunc(keys, func(k Key) bool { return slices.Contains(expired, k.ID) })
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	return nil
+}
+
+// refresh reloads the keys from the store, picking up those added by other replicas
+func (s *KeySet) refresh(ctx context.Context) error {
+	keys, err := s.store.Keys(ctx)
+	if err != nil {
+		return err
+	}
+	keys = slices.DeleteFunc(keys, func(k Key) bool { return k.Algorithm != s.cfg.Algorithm })
+
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	return nil
+}
+
+// Sign signs claims with the secret or the newest key pair
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	if s.cfg.Algorithm == HS256 {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.cfg.Secret))
+	}
+
+	s.mu.RLock()
+	var key Key
+	if len(s.keys) > 0 {
+		key = s.keys[0]
+	}
+	s.mu.RUnlock()
+	if key.Private == nil {
+		return "", errors.New("no signing key; Rotate has not run")
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
+}
+
+// Keyfunc returns the key that verifies token, for jwt.Parse
+// A kid this replica has not loaded is looked up in the store once, in case another replica rotated.
+func (s *KeySet) Keyfunc(token *jwt.Token) (any, error) {
+	if s.cfg.Algorithm == HS256 {
+		return []byte(s.cfg.Secret), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if key, ok := s.find(kid); ok {
+		return key.Private.Public(), nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+	defer cancel()
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+	if key, ok := s.find(kid); ok {
+		return key.Private.Public(), nil
+	}
+	return nil, ErrUnknownKey
+}
+
+// find returns the loaded key with the given ID
+func (s *KeySet) find(kid string) (Key, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, key := range s.keys {
+		if key.ID == kid {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
+
+// JWK is a public key in JSON Web Key form (RFC 7517)
+type JWK struct {
+	KeyType   string `json:"kty" example:"RSA"`
+	Use       string `json:"use" example:"sig"`
+	Algorithm string `json:"alg" example:"RS256"`
+	KeyID     string `json:"kid" example:"3f1c9a0d7e5b2c48"`
+	N         string `json:"n,omitempty"`   // RSA modulus
+	E         string `json:"e,omitempty"`   // RSA exponent
+	Curve     string `json:"crv,omitempty"` // EC curve
+	X         string `json:"x,omitempty"`   // EC point
+	Y         string `json:"y,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys that verify current tokens, newest first; empty with HS256
+func (s *KeySet) JWKS() JWKS {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	set := JWKS{Keys: []JWK{}}
+	for _, key := range s.keys {
+		set.Keys = append(set.Keys, publicJWK(key))
+	}
+	return set
+}
+
+// publicJWK encodes the public half of a key
+func publicJWK(key Key) JWK {
+	jwk := JWK{Use: "sig", Algorithm: key.Algorithm, KeyID: key.ID}
+	switch pub := key.Private.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		// Uncompressed point: 0x04 || X || Y
+		point, _ := pub.Bytes()
+		size := (len(point) - 1) / 2
+		jwk.KeyType = "EC"
+		jwk.Curve = pub.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(point[1 : 1+size])
+		jwk.Y = base64.RawURLEncoding.EncodeToString(point[1+size:])
+	}
+	return jwk
+}
+
+// Generate creates a key pair for alg with a random ID
+func Generate(alg string, now time.Time) (Key, error) {
+	var private crypto.Signer
+	var err error
+	switch alg {
+	case RS256:
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case ES256:
+		private, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	default:
+		return Key{}, fmt.Errorf("%s has no key pair", alg)
+	}
+	if err != nil {
+		return Key{}, err
+	}
+
+	id := make([]byte, 8)
+	rand.Read(id)
+	return Key{
+		ID:        hex.EncodeToString(id),
+		Algorithm: alg,
+		Private:   private,
+		CreatedAt: now,
+	}, nil
+}
+
+// newestFirst orders keys by creation time, newest first
+func newestFirst(a, b Key) int {
+	return b.CreatedAt.Compare(a.CreatedAt)
+}
+
+// marshalPrivate encodes a private key as PKCS #8 for storage
+func marshalPrivate(key crypto.Signer) ([]byte, error) {
+	return x509.MarshalPKCS8PrivateKey(key)
+}
+
+// parsePrivate decodes a PKCS #8 private key
+func parsePrivate(der []byte) (crypto.Signer, error) {
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("stored key of type %T can't sign", key)
+	}
+	return signer, nil
+}
@@ -0,0 +1,172 @@
+package jwtkeys
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newTestSet(t *testing.T, alg string) (*KeySet, *time.Time) {
+	t.Helper()
+	wall := time.Now()
+	s := New(Config{Algorithm: alg, Secret: "secret", Rotation: 24 * time.Hour, Retention: 7 * 24 * time.Hour}, NewMemoryStore())
+	s.now = func() time.Time { return wall }
+	if err := s.Rotate(context.Background()); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	return s, &wall
+}
+
+func sign(t *testing.T, s *KeySet) string {
+	t.Helper()
+	token, err := s.Sign(jwt.RegisteredClaims{Subject: "user", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))})
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	return token
+}
+
+func verify(s *KeySet, token string) error {
+	_, err := jwt.Parse(token, s.Keyfunc, jwt.WithValidMethods([]string{s.Algorithm()}))
+	return err
+}
+
+func TestKeySetSignsAndVerifies(t *testing.T) {
+	for _, alg := range []string{HS256, RS256, ES256} {
+		t.Run(alg, func(t *testing.T) {
+			s, _ := newTestSet(t, alg)
+			token := sign(t, s)
+			if err := verify(s, token); err != nil {
+				t.Fatalf("verify() error = %v", err)
+			}
+
+			parsed, _, _ := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+			if parsed.Method.Alg() != alg {
+				t.Errorf("alg = %s, want %s", parsed.Method.Alg(), alg)
+			}
+			if _, ok := parsed.Header["kid"]; ok == (alg == HS256) {
+				t.Errorf("kid header present = %v, want %v", ok, alg != HS256)
+			}
+		})
+	}
+}
+
+func TestKeySetRotation(t *testing.T) {
+	ctx := context.Background()
+	s, wall := newTestSet(t, RS256)
+	first := sign(t, s)
+
+	// Not due yet
+	*wall = wall.Add(time.Hour)
+	s.Rotate(ctx)
+	if n := len(s.JWKS().Keys); n != 1 {
+		t.Fatalf("keys after 1h = %d, want 1", n)
+	}
+
+	*wall = wall.Add(24 * time.Hour)
+	s.Rotate(ctx)
+	jwks := s.JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("keys after rotation = %d, want 2", len(jwks.Keys))
+	}
+	second := sign(t, s)
+	parsed, _, _ := jwt.NewParser().ParseUnverified(second, jwt.MapClaims{})
+	if parsed.Header["kid"] != jwks.Keys[0].KeyID {
+		t.Errorf("kid = %v, want the newest key %s", parsed.Header["kid"], jwks.Keys[0].KeyID)
+	}
+	if err := verify(s, first); err != nil {
+		t.Errorf("token of the replaced key: verify() error = %v", err)
+	}
+
+	// The replaced key goes once every token it signed has expired
+	*wall = wall.Add(7*24*time.Hour + time.Minute)
+	s.Rotate(ctx)
+	if err := verify(s, first); err == nil {
+		t.Error("token of a retired key verified")
+	}
+	if err := verify(s, second); err != nil {
+		t.Errorf("token of the key replaced within the retention: verify() error = %v", err)
+	}
+}
+
+func TestKeySetSharesStore(t *testing.T) {
+	store := NewMemoryStore()
+	cfg := Config{Algorithm: ES256, Rotation: time.Hour, Retention: time.Hour}
+	a := New(cfg, store)
+	b := New(cfg, store)
+	if err := a.Rotate(context.Background()); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+
+	// b never loaded the key; it finds it in the store on first use
+	if err := verify(b, sign(t, a)); err != nil {
+		t.Errorf("verify() on another replica error = %v", err)
+	}
+
+	other := New(Config{Algorithm: ES256, Rotation: time.Hour, Retention: time.Hour}, NewMemoryStore())
+	other.Rotate(context.Background())
+	if err := verify(a, sign(t, other)); err == nil {
+		t.Error("token of an unknown key verified")
+	}
+}
+
+func TestKeySetDropsKeysOfAnotherAlgorithm(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	rs := New(Config{Algorithm: RS256, Rotation: time.Hour, Retention: time.Hour}, store)
+	rs.Rotate(ctx)
+
+	es := New(Config{Algorithm: ES256, Rotation: time.Hour, Retention: time.Hour}, store)
+	if err := es.Rotate(ctx); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	keys, _ := store.Keys(ctx)
+	if len(keys) != 1 || keys[0].Algorithm != ES256 {
+		t.Errorf("stored keys = %+v, want a single ES256 key", keys)
+	}
+}
+
+func TestJWKSVerifiesTokens(t *testing.T) {
+	decode := func(s string) *big.Int {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil {
+			t.Fatalf("decode %q: %v", s, err)
+		}
+		return new(big.Int).SetBytes(b)
+	}
+
+	for _, alg := range []string{RS256, ES256} {
+		t.Run(alg, func(t *testing.T) {
+			s, _ := newTestSet(t, alg)
+			jwk := s.JWKS().Keys[0]
+
+			// What a resource server holding only the JWKS would do
+			var public any
+			switch jwk.KeyType {
+			case "RSA":
+				public = &rsa.PublicKey{N: decode(jwk.N), E: int(decode(jwk.E).Int64())}
+			case "EC":
+				if jwk.Curve != "P-256" {
+					t.Fatalf("crv = %s, want P-256", jwk.Curve)
+				}
+				public = &ecdsa.PublicKey{Curve: elliptic.P256(), X: decode(jwk.X), Y: decode(jwk.Y)}
+			}
+			_, err := jwt.Parse(sign(t, s), func(*jwt.Token) (any, error) { return public, nil })
+			if err != nil {
+				t.Errorf("verify with the published key error = %v", err)
+			}
+		})
+	}
+
+	hs, _ := newTestSet(t, HS256)
+	if keys := hs.JWKS().Keys; len(keys) != 0 {
+		t.Errorf("HS256 JWKS = %+v, want no keys", keys)
+	}
+}
@@ -0,0 +1,47 @@
+package jwtkeys
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// MemoryStore keeps the key pairs in process memory (STORAGE=memory)
+// They are per process: tokens signed by one replica don't verify on another.
+type MemoryStore struct {
+	mu   sync.Mutex
+	keys []Key
+}
+
+// NewMemoryStore creates an in-memory key store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Keys returns a copy of the keys, newest first
+func (s *MemoryStore) Keys(ctx context.Context) ([]Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := slices.Clone(s.keys)
+	slices.SortFunc(keys, newestFirst)
+	return keys, nil
+}
+
+// Add appends the key
+func (s *MemoryStore) Add(ctx context.Context, key Key) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys = append(s.keys, key)
+	return nil
+}
+
+// Remove drops the keys with the given IDs
+func (s *MemoryStore) Remove(ctx context.Context, ids ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys = slices.DeleteFunc(s.keys, func(k Key) bool { return slices.Contains(ids, k.ID) })
+	return nil
+}
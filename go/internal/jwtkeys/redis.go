@@ -0,0 +1,80 @@
+package jwtkeys
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKey is the hash holding the key pairs by ID
+const redisKey = "jwt_keys"
+
+// storedKey is a key pair as kept in Redis
+type storedKey struct {
+	Algorithm string    `json:"alg"`
+	Private   []byte    `json:"key"` // PKCS #8
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// RedisStore keeps the key pairs in Redis, shared by every replica
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a key store backed by Redis
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Keys reads the whole hash; there are only ever a handful of keys
+func (s *RedisStore) Keys(ctx context.Context) ([]Key, error) {
+	fields, err := s.client.HGetAll(ctx, redisKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]Key, 0, len(fields))
+	for id, value := range fields {
+		var stored storedKey
+		if err := json.Unmarshal([]byte(value), &stored); err != nil {
+			return nil, err
+		}
+		private, err := parsePrivate(stored.Private)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, Key{
+			ID:        id,
+			Algorithm: stored.Algorithm,
+			Private:   private,
+			CreatedAt: stored.CreatedAt,
+		})
+	}
+	slices.SortFunc(keys, newestFirst)
+	return keys, nil
+}
+
+// Add sets the key's field
+func (s *RedisStore) Add(ctx context.Context, key Key) error {
+	der, err := marshalPrivate(key.Private)
+	if err != nil {
+		return err
+	}
+	value, err := json.Marshal(storedKey{
+		Algorithm: key.Algorithm,
+		Private:   der,
+		CreatedAt: key.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	return s.client.HSet(ctx, redisKey, key.ID, value).Err()
+}
+
+// Remove deletes the keys' fields
+func (s *RedisStore) Remove(ctx context.Context, ids ...string) error {
+	return s.client.HDel(ctx, redisKey, ids...).Err()
+}
@@ -0,0 +1,161 @@
+package keyfilter
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// maxBits is the largest bitmap Redis can hold (512 MB)
+const maxBits = 1 << 32
+
+// warmBatchSize is how many keys are sent to Redis per pipeline while warming
+const warmBatchSize = 1000
+
+// status is the answer of the filter for one key
+type status int
+
+const (
+	statusAbsent   status = iota // definitely not registered
+	statusPresent                // possibly registered
+	statusNotReady               // the filter has not been warmed (or Redis lost it), so it can't answer
+)
+
+// checkScript tests every bit of a key, answering "not ready" until the filter has been warmed
+// The bitmap is checked too: if Redis evicted it, missing bits would wrongly mean "absent".
+var checkScript = redis.NewScript(`
+	local bits_key = KEYS[1]
+	local ready_key = KEYS[2]
+
+	if redis.call('EXISTS', ready_key) == 0 or redis.call('EXISTS', bits_key) == 0 then
+		return 2
+	end
+
+	for i = 1, #ARGV do
+		if redis.call('GETBIT', bits_key, ARGV[i]) == 0 then
+			return 0
+		end
+	end
+	return 1
+`)
+
+// Bloom is a bloom filter of registered keys kept in a Redis bitmap, shared by every replica
+// Keys can only be added: deleted keys stay in the filter and show up as false positives
+// until the filter is rebuilt (delete its Redis keys and restart).
+// Redis keys: key_filter:{name}:{bits}:{hashes}:bits and :ready
+type Bloom struct {
+	client   *redis.Client
+	bitsKey  string
+	readyKey string
+	bits     uint64
+	hashes   int
+}
+
+// NewBloom creates a filter sized for capacity keys at the given false-positive rate
+// name separates filters sharing a Redis instance. The size is part of the Redis keys, so a
+// resized filter starts empty instead of mixing layouts; replicas must not run with different sizes.
+func NewBloom(client *redis.Client, name string, capacity int, fpRate float64) *Bloom {
+	bits, hashes := optimalSize(capacity, fpRate)
+	prefix := fmt.Sprintf("key_filter:%s:%d:%d", name, bits, hashes)
+	return &Bloom{
+		client:   client,
+		bitsKey:  prefix + ":bits",
+		readyKey: prefix + ":ready",
+		bits:     bits,
+		hashes:   hashes,
+	}
+}
+
+// optimalSize returns the bitmap size and hash count that give fpRate once capacity keys are added
+func optimalSize(capacity int, fpRate float64) (uint64, int) {
+	n := float64(max(capacity, 1))
+	m := math.Ceil(-n * math.Log(fpRate) / (math.Ln2 * math.Ln2))
+	m = min(max(m, 64), maxBits)
+
+	k := int(math.Round(m / n * math.Ln2))
+	return uint64(m), max(k, 1)
+}
+
+// offsets returns the bits that represent key, using double hashing over a 128-bit FNV-1a hash
+func (b *Bloom) offsets(key string) []uint64 {
+	h := fnv.New128a()
+	h.Write([]byte(key))
+	sum := h.Sum(nil)
+
+	h1 := binary.BigEndian.Uint64(sum[:8])
+	h2 := binary.BigEndian.Uint64(sum[8:]) | 1 // odd, so the probes never collapse onto one bit
+
+	offsets := make([]uint64, b.hashes)
+	for i := range offsets {
+		offsets[i] = (h1 + uint64(i)*h2) % b.bits
+	}
+	return offsets
+}
+
+// Add records keys in the filter
+func (b *Bloom) Add(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	pipe := b.client.Pipeline()
+	for _, key := range keys {
+		for _, offset := range b.offsets(key) {
+			pipe.SetBit(ctx, b.bitsKey, int64(offset), 1)
+		}
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// check reports whether key may be registered
+func (b *Bloom) check(ctx context.Context, key string) (status, error) {
+	offsets := b.offsets(key)
+	args := make([]any, len(offsets))
+	for i, offset := range offsets {
+		args[i] = strconv.FormatUint(offset, 10)
+	}
+
+	result, err := checkScript.Run(ctx, b.client, []string{b.bitsKey, b.readyKey}, args...).Int()
+	if err != nil {
+		return statusNotReady, err
+	}
+	return status(result), nil
+}
+
+// Warm adds every key returned by forEachKey, then marks the filter ready to answer lookups
+// Adding is idempotent, so replicas warming concurrently (or while others write) is safe.
+// Returns the number of keys added.
+func (b *Bloom) Warm(ctx context.Context, forEachKey func(context.Context, func(string) error) error) (int, error) {
+	// Allocate the whole bitmap so an empty directory still has a filter to answer from
+	if err := b.client.SetBit(ctx, b.bitsKey, int64(b.bits-1), 0).Err(); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	batch := make([]string, 0, warmBatchSize)
+
+	err := forEachKey(ctx, func(key string) error {
+		batch = append(batch, key)
+		count++
+		if len(batch) < warmBatchSize {
+			return nil
+		}
+		err := b.Add(ctx, batch...)
+		batch = batch[:0]
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := b.Add(ctx, batch...); err != nil {
+		return 0, err
+	}
+
+	return count, b.client.Set(ctx, b.readyKey, "1", 0).Err()
+}
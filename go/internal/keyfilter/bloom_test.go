@@ -0,0 +1,78 @@
+package keyfilter
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestOptimalSize(t *testing.T) {
+	tests := []struct {
+		capacity   int
+		fpRate     float64
+		wantBits   uint64
+		wantHashes int
+	}{
+		{capacity: 1_000_000, fpRate: 0.01, wantBits: 9_585_059, wantHashes: 7},
+		{capacity: 1000, fpRate: 0.001, wantBits: 14_378, wantHashes: 10},
+		{capacity: 0, fpRate: 0.01, wantBits: 64, wantHashes: 44}, // minimum bitmap
+	}
+
+	for _, tt := range tests {
+		bits, hashes := optimalSize(tt.capacity, tt.fpRate)
+		if bits != tt.wantBits || hashes != tt.wantHashes {
+			t.Errorf("optimalSize(%d, %v) = (%d, %d), want (%d, %d)",
+				tt.capacity, tt.fpRate, bits, hashes, tt.wantBits, tt.wantHashes)
+		}
+	}
+}
+
+func TestOffsets(t *testing.T) {
+	b := NewBloom(nil, "test", 1000, 0.01)
+
+	first := b.offsets("12345678901")
+	if len(first) != b.hashes {
+		t.Fatalf("len(offsets) = %d, want %d", len(first), b.hashes)
+	}
+	for i, offset := range first {
+		if offset >= b.bits {
+			t.Errorf("offset[%d] = %d, outside the %d-bit filter", i, offset, b.bits)
+		}
+	}
+
+	again := b.offsets("12345678901")
+	for i := range first {
+		if first[i] != again[i] {
+			t.Fatalf("offsets are not deterministic: %v vs %v", first, again)
+		}
+	}
+}
+
+// TestFalsePositiveRate checks the hashing spreads keys well enough to meet the configured rate
+// It simulates the Redis bitmap locally with the same offsets.
+func TestFalsePositiveRate(t *testing.T) {
+	const capacity = 10_000
+	b := NewBloom(nil, "test", capacity, 0.01)
+	bitmap := make([]bool, b.bits)
+
+	for i := range capacity {
+		for _, offset := range b.offsets("key-" + strconv.Itoa(i)) {
+			bitmap[offset] = true
+		}
+	}
+
+	falsePositives := 0
+	const probes = 20_000
+	for i := range probes {
+		present := true
+		for _, offset := range b.offsets("absent-" + strconv.Itoa(i)) {
+			present = present && bitmap[offset]
+		}
+		if present {
+			falsePositives++
+		}
+	}
+
+	if rate := float64(falsePositives) / probes; rate > 0.02 {
+		t.Errorf("false-positive rate = %.4f, want about 0.01", rate)
+	}
+}
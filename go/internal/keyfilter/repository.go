@@ -0,0 +1,98 @@
+package keyfilter
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/dict-simulator/go/internal/logger"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// lookupsTotal counts lookups by how the filter answered them
+// False-positive rate: false_positive / (false_positive + filtered)
+var lookupsTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "dict_key_filter_lookups_total",
+		Help: "Total number of entry lookups through the key filter by result (filtered, found, false_positive, bypassed)",
+	},
+	[]string{"result"},
+)
+
+// EntryRepository answers lookups of unregistered keys from the filter, without a database round trip
+// Keys are added to the filter, normalized, before they are stored, so a key the filter rejects is never registered.
+// When the filter can't answer (not warmed yet, Redis unreachable) lookups go to the database.
+type EntryRepository struct {
+	models.EntryRepository
+	filter *Bloom
+}
+
+// NewEntryRepository wraps repo with filter; the filter must have been warmed from repo
+func NewEntryRepository(repo models.EntryRepository, filter *Bloom) *EntryRepository {
+	return &EntryRepository{
+		EntryRepository: repo,
+		filter:          filter,
+	}
+}
+
+// Create adds the key to the filter, then creates the entry
+// Fails without storing the entry when the key can't be added.
+func (r *EntryRepository) Create(ctx context.Context, req *models.CreateEntryRequest) (*models.Entry, error) {
+	if err := r.filter.Add(ctx, models.NormalizeKey(req.Key)); err != nil {
+		return nil, err
+	}
+	return r.EntryRepository.Create(ctx, req)
+}
+
+// CreateMany adds the keys to the filter, then creates the entries
+func (r *EntryRepository) CreateMany(ctx context.Context, reqs []models.CreateEntryRequest) (int, error) {
+	keys := make([]string, len(reqs))
+	for i := range reqs {
+		keys[i] = models.NormalizeKey(reqs[i].Key)
+	}
+	if err := r.filter.Add(ctx, keys...); err != nil {
+		return 0, err
+	}
+	return r.EntryRepository.CreateMany(ctx, reqs)
+}
+
+// InsertMany adds the keys to the filter, then inserts the entries
+func (r *EntryRepository) InsertMany(ctx context.Context, entries []models.Entry) (int, error) {
+	keys := make([]string, len(entries))
+	for i := range entries {
+		keys[i] = models.NormalizeKey(entries[i].Key)
+	}
+	if err := r.filter.Add(ctx, keys...); err != nil {
+		return 0, err
+	}
+	return r.EntryRepository.InsertMany(ctx, entries)
+}
+
+// FindByKey returns (nil, nil) straight away for keys the filter has never seen
+func (r *EntryRepository) FindByKey(ctx context.Context, key string) (*models.Entry, error) {
+	st, err := r.filter.check(ctx, models.NormalizeKey(key))
+	if err != nil {
+		logger.Warn("Key filter unavailable, looking up in the database", zap.Error(err))
+	}
+	if st == statusAbsent {
+		lookupsTotal.WithLabelValues("filtered").Inc()
+		return nil, nil
+	}
+
+	entry, err := r.EntryRepository.FindByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case st == statusNotReady:
+		lookupsTotal.WithLabelValues("bypassed").Inc()
+	case entry == nil:
+		lookupsTotal.WithLabelValues("false_positive").Inc()
+	default:
+		lookupsTotal.WithLabelValues("found").Inc()
+	}
+	return entry, nil
+}
@@ -0,0 +1,73 @@
+package keylimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// countTTL is how long a day's count is kept, by the wall clock
+// Days follow the simulated clock, which can stay on one day for longer than a day passes.
+const countTTL = 48 * time.Hour
+
+// Store counts the keys each owner has created per day
+// Implemented by RedisStore and MemoryStore (STORAGE=memory).
+type Store interface {
+	// Claim counts one more key created by owner on day and returns the day's count, including it
+	Claim(ctx context.Context, owner, day string) (int, error)
+	// Release uncounts a claim whose key was not created
+	Release(ctx context.Context, owner, day string) error
+	// Forget deletes owner's counts of every day and returns how many it deleted
+	Forget(ctx context.Context, owner string) (int, error)
+}
+
+// creationsKey generates the storage key counting an owner's key creations on a day
+// Format: key_creations:{owner}:{day}
+func creationsKey(owner, day string) string {
+	return "key_creations:" + owner + ":" + day
+}
+
+// RedisStore keeps the counts in Redis, shared by every replica
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a key creation store backed by Redis
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Claim increments the day's count, which expires countTTL after the first claim
+func (s *RedisStore) Claim(ctx context.Context, owner, day string) (int, error) {
+	pipe := s.client.TxPipeline()
+	count := pipe.Incr(ctx, creationsKey(owner, day))
+	pipe.ExpireNX(ctx, creationsKey(owner, day), countTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return int(count.Val()), nil
+}
+
+// Release decrements the day's count
+func (s *RedisStore) Release(ctx context.Context, owner, day string) error {
+	return s.client.Decr(ctx, creationsKey(owner, day)).Err()
+}
+
+// Forget deletes owner's counts of every day
+// Keys are found with SCAN, so a count created while it runs may survive.
+func (s *RedisStore) Forget(ctx context.Context, owner string) (int, error) {
+	var keys []string
+	iter := s.client.Scan(ctx, 0, creationsKey(owner, "*"), 1000).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return 0, err
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	deleted, err := s.client.Del(ctx, keys...).Result()
+	return int(deleted), err
+}
@@ -0,0 +1,56 @@
+package keylimit
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryStore keeps the counts in process memory (STORAGE=memory)
+// They are per process: each replica lets an owner create its own limit of keys.
+type MemoryStore struct {
+	mu     sync.Mutex
+	day    string
+	counts map[string]int // by owner, on day
+}
+
+// NewMemoryStore creates an in-memory key creation store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{counts: map[string]int{}}
+}
+
+// Claim counts one more key created by owner on day
+// Only one day is kept: the counts of the previous one are dropped when another day is claimed.
+func (s *MemoryStore) Claim(ctx context.Context, owner, day string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if day != s.day {
+		s.day = day
+		clear(s.counts)
+	}
+	s.counts[owner]++
+	return s.counts[owner], nil
+}
+
+// Release uncounts a claim of day, unless that day's counts have been dropped
+func (s *MemoryStore) Release(ctx context.Context, owner, day string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if day == s.day && s.counts[owner] > 0 {
+		s.counts[owner]--
+	}
+	return nil
+}
+
+// Forget deletes owner's count; only the current day's is kept
+func (s *MemoryStore) Forget(ctx context.Context, owner string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.counts[owner]; !ok {
+		return 0, nil
+	}
+	delete(s.counts, owner)
+	return 1, nil
+}
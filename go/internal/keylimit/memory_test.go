@@ -0,0 +1,34 @@
+package keylimit
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+
+	for want := 1; want <= 2; want++ {
+		if n, _ := s.Claim(ctx, "11144477735", "2024-01-15"); n != want {
+			t.Fatalf("Claim() = %d, want %d", n, want)
+		}
+	}
+	if n, _ := s.Claim(ctx, "52998224725", "2024-01-15"); n != 1 {
+		t.Errorf("Claim() = %d for another owner, want 1", n)
+	}
+
+	s.Release(ctx, "11144477735", "2024-01-15")
+	if n, _ := s.Claim(ctx, "11144477735", "2024-01-15"); n != 2 {
+		t.Errorf("Claim() = %d after a release, want 2", n)
+	}
+
+	// A new day starts every count over, and releases of the previous one are ignored
+	if n, _ := s.Claim(ctx, "11144477735", "2024-01-16"); n != 1 {
+		t.Errorf("Claim() = %d on the next day, want 1", n)
+	}
+	s.Release(ctx, "11144477735", "2024-01-15")
+	if n, _ := s.Claim(ctx, "11144477735", "2024-01-16"); n != 2 {
+		t.Errorf("Claim() = %d, want the stale release ignored", n)
+	}
+}
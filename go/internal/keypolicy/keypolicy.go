@@ -0,0 +1,50 @@
+package keypolicy
+
+import (
+	"slices"
+	"sync/atomic"
+
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/validation"
+)
+
+// Policies decide which keys may be registered beyond their format, so a test environment can
+// mimic the policies of a production one. Keys already registered are never affected.
+type Policies struct {
+	DisabledKeyTypes    []models.KeyType // key types that can't be created
+	BrazilianPhonesOnly bool             // PHONE keys must be Brazilian (+55) numbers instead of any E.164 one
+}
+
+// Allows reports whether keys of keyType can be created
+func (p Policies) Allows(keyType models.KeyType) bool {
+	return !slices.Contains(p.DisabledKeyTypes, keyType)
+}
+
+// AllowsPhone reports whether phone, a well-formed E.164 number, can be created as a PHONE key
+func (p Policies) AllowsPhone(phone string) bool {
+	return !p.BrazilianPhonesOnly || validation.IsBrazilianPhone(phone)
+}
+
+// Store holds the policies in effect, which admins change while the server runs
+// Set swaps them as a whole, so a creation never sees a mix of old and new policies.
+type Store struct {
+	policies atomic.Pointer[Policies]
+}
+
+// NewStore creates a store holding initial
+func NewStore(initial Policies) *Store {
+	s := &Store{}
+	s.Set(initial)
+	return s
+}
+
+// Get returns the policies in effect
+func (s *Store) Get() Policies {
+	return *s.policies.Load()
+}
+
+// Set replaces the policies in effect
+func (s *Store) Set(policies Policies) {
+	policies.DisabledKeyTypes = slices.Clone(policies.DisabledKeyTypes)
+	s.policies.Store(&policies)
+}
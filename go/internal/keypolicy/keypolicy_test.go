@@ -0,0 +1,38 @@
+package keypolicy
+
+import (
+	"testing"
+
+	"github.com/dict-simulator/go/internal/models"
+)
+
+func TestPolicies(t *testing.T) {
+	var open Policies
+	if !open.Allows(models.KeyTypeEVP) || !open.AllowsPhone("+14155552671") {
+		t.Error("zero Policies refused a key, want every key allowed")
+	}
+
+	strict := Policies{DisabledKeyTypes: []models.KeyType{models.KeyTypeEVP}, BrazilianPhonesOnly: true}
+	if strict.Allows(models.KeyTypeEVP) || !strict.Allows(models.KeyTypePHONE) {
+		t.Error("Allows() doesn't follow DisabledKeyTypes")
+	}
+	if strict.AllowsPhone("+14155552671") || !strict.AllowsPhone("+5511987654321") {
+		t.Error("AllowsPhone() doesn't follow BrazilianPhonesOnly")
+	}
+}
+
+func TestStore(t *testing.T) {
+	disabled := []models.KeyType{models.KeyTypeEVP}
+	store := NewStore(Policies{DisabledKeyTypes: disabled})
+
+	// The store keeps its own copy
+	disabled[0] = models.KeyTypeCPF
+	if got := store.Get(); got.Allows(models.KeyTypeEVP) || !got.Allows(models.KeyTypeCPF) {
+		t.Errorf("Get() = %+v, want EVP disabled", got)
+	}
+
+	store.Set(Policies{BrazilianPhonesOnly: true})
+	if got := store.Get(); !got.Allows(models.KeyTypeEVP) || !got.BrazilianPhonesOnly {
+		t.Errorf("Get() after Set() = %+v, want the new policies", got)
+	}
+}
@@ -0,0 +1,102 @@
+package latency
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+// DefaultRoute is the profile key applied to routes without their own profile
+const DefaultRoute = "*"
+
+// Profile describes a response time distribution by its percentiles
+type Profile struct {
+	P50 time.Duration
+	P95 time.Duration
+	P99 time.Duration
+}
+
+// Sample draws a delay from the profile
+func (p Profile) Sample() time.Duration {
+	return p.quantile(rand.Float64())
+}
+
+// quantile maps u in [0,1) to a delay by interpolating linearly between
+// (0, 0), (0.50, P50), (0.95, P95) and (0.99, P99); the top 1% is capped at P99
+func (p Profile) quantile(u float64) time.Duration {
+	lerp := func(u, u0, u1 float64, d0, d1 time.Duration) time.Duration {
+		return d0 + time.Duration((u-u0)/(u1-u0)*float64(d1-d0))
+	}
+
+	switch {
+	case u < 0.50:
+		return lerp(u, 0, 0.50, 0, p.P50)
+	case u < 0.95:
+		return lerp(u, 0.50, 0.95, p.P50, p.P95)
+	case u < 0.99:
+		return lerp(u, 0.95, 0.99, p.P95, p.P99)
+	default:
+		return p.P99
+	}
+}
+
+// Profiles maps route patterns (e.g. "GET /entries/{key}") to latency profiles
+type Profiles map[string]Profile
+
+// For returns the profile for a route pattern, falling back to the DefaultRoute profile
+func (ps Profiles) For(route string) (Profile, bool) {
+	if p, ok := ps[route]; ok {
+		return p, true
+	}
+	p, ok := ps[DefaultRoute]
+	return p, ok
+}
+
+// ParseProfiles parses profiles in the form
+//
+//	GET /entries/{key}=30ms,80ms,250ms;POST /entries=60ms,150ms,400ms;*=5ms,10ms,20ms
+//
+// where each value lists p50,p95,p99 as Go durations. An empty string yields no profiles.
+func ParseProfiles(s string) (Profiles, error) {
+	profiles := Profiles{}
+
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		route, values, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("latency profile %q: expected route=p50,p95,p99", entry)
+		}
+		route = strings.TrimSpace(route)
+
+		parts := strings.Split(values, ",")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("latency profile %q: expected 3 percentiles, got %d", route, len(parts))
+		}
+
+		var percentiles [3]time.Duration
+		for i, part := range parts {
+			d, err := time.ParseDuration(strings.TrimSpace(part))
+			if err != nil {
+				return nil, fmt.Errorf("latency profile %q: %w", route, err)
+			}
+			if d < 0 {
+				return nil, fmt.Errorf("latency profile %q: negative duration %s", route, d)
+			}
+			percentiles[i] = d
+		}
+
+		p := Profile{P50: percentiles[0], P95: percentiles[1], P99: percentiles[2]}
+		if p.P50 > p.P95 || p.P95 > p.P99 {
+			return nil, fmt.Errorf("latency profile %q: percentiles must be non-decreasing", route)
+		}
+
+		profiles[route] = p
+	}
+
+	return profiles, nil
+}
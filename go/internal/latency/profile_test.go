@@ -0,0 +1,121 @@
+package latency
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseProfiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Profiles
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			input: "",
+			want:  Profiles{},
+		},
+		{
+			name:  "route and default",
+			input: "GET /entries/{key}=30ms,80ms,250ms; *=5ms,10ms,20ms",
+			want: Profiles{
+				"GET /entries/{key}": {P50: 30 * time.Millisecond, P95: 80 * time.Millisecond, P99: 250 * time.Millisecond},
+				DefaultRoute:         {P50: 5 * time.Millisecond, P95: 10 * time.Millisecond, P99: 20 * time.Millisecond},
+			},
+		},
+		{
+			name:    "missing separator",
+			input:   "GET /entries/{key}",
+			wantErr: true,
+		},
+		{
+			name:    "wrong number of percentiles",
+			input:   "POST /entries=10ms,20ms",
+			wantErr: true,
+		},
+		{
+			name:    "invalid duration",
+			input:   "POST /entries=10,20,30",
+			wantErr: true,
+		},
+		{
+			name:    "decreasing percentiles",
+			input:   "POST /entries=100ms,50ms,200ms",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseProfiles(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseProfiles(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseProfiles(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+			for route, p := range tt.want {
+				if got[route] != p {
+					t.Errorf("profile[%q] = %+v, want %+v", route, got[route], p)
+				}
+			}
+		})
+	}
+}
+
+func TestProfilesFor(t *testing.T) {
+	fallback := Profile{P50: time.Millisecond, P95: time.Millisecond, P99: time.Millisecond}
+	profiles := Profiles{
+		"GET /entries/{key}": {P50: 30 * time.Millisecond},
+		DefaultRoute:         fallback,
+	}
+
+	if p, _ := profiles.For("GET /entries/{key}"); p.P50 != 30*time.Millisecond {
+		t.Errorf("For() returned %+v, want the route profile", p)
+	}
+	if p, ok := profiles.For("POST /entries"); !ok || p != fallback {
+		t.Errorf("For() = %+v, %v, want the default profile", p, ok)
+	}
+	if _, ok := (Profiles{}).For("POST /entries"); ok {
+		t.Errorf("For() on empty profiles reported a match")
+	}
+}
+
+func TestProfileSamplePercentiles(t *testing.T) {
+	p := Profile{P50: 30 * time.Millisecond, P95: 80 * time.Millisecond, P99: 250 * time.Millisecond}
+
+	samples := make([]time.Duration, 100000)
+	for i := range samples {
+		samples[i] = p.Sample()
+	}
+	slices.Sort(samples)
+
+	percentile := func(q float64) time.Duration {
+		return samples[int(q*float64(len(samples)))]
+	}
+
+	for _, tc := range []struct {
+		q    float64
+		want time.Duration
+	}{
+		{0.50, p.P50},
+		{0.95, p.P95},
+		{0.99, p.P99},
+	} {
+		got := percentile(tc.q)
+		tolerance := tc.want / 10
+		if got < tc.want-tolerance || got > tc.want+tolerance {
+			t.Errorf("p%.0f = %v, want %v ± %v", tc.q*100, got, tc.want, tolerance)
+		}
+	}
+
+	if maxSample := samples[len(samples)-1]; maxSample > p.P99 {
+		t.Errorf("max sample = %v, want at most %v", maxSample, p.P99)
+	}
+}
@@ -0,0 +1,234 @@
+package listing
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// cursorVersion prefixes every cursor, so the encoding can change without misreading old cursors
+const cursorVersion = "v1"
+
+// Page selects a page of a listing: up to Limit items following After in the listing's order,
+// which Descending reverses. After is the position of the previous page's last item, empty on
+// the first page.
+type Page struct {
+	After      string
+	Limit      int
+	Descending bool
+}
+
+// Options describes the query string a list endpoint accepts
+type Options struct {
+	DefaultLimit int
+	MaxLimit     int
+	// Sorts are the orders the endpoint lists in, the default first: the field ordered by,
+	// prefixed with - for descending
+	Sorts []string
+	// Fields are the item fields fields= can select, usually JSONFields of the item type
+	Fields []string
+	// ValidPosition reports whether a decoded cursor holds a position the repository can compare,
+	// such as an ObjectID in hex; nil accepts any
+	ValidPosition func(position string) bool
+}
+
+// Query is a parsed list query string
+type Query struct {
+	Page
+	Sort   string   // one of Options.Sorts
+	Fields []string // the fields to keep in each item; nil keeps them all
+}
+
+// Parse reads limit, cursor, sort and fields, applying the defaults of opts
+// A cursor is only valid with the sort it was issued for.
+func Parse(q url.Values, opts Options) (Query, error) {
+	query := Query{Page: Page{Limit: opts.DefaultLimit}, Sort: opts.Sorts[0]}
+
+	if raw := q.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > opts.MaxLimit {
+			return Query{}, fmt.Errorf("limit must be between 1 and %d", opts.MaxLimit)
+		}
+		query.Limit = limit
+	}
+
+	if raw := q.Get("sort"); raw != "" {
+		if !slices.Contains(opts.Sorts, raw) {
+			return Query{}, fmt.Errorf("sort must be one of %s", strings.Join(opts.Sorts, ", "))
+		}
+		query.Sort = raw
+	}
+	query.Descending = strings.HasPrefix(query.Sort, "-")
+
+	if raw := q.Get("cursor"); raw != "" {
+		sort, after, ok := decodeCursor(raw)
+		if !ok {
+			return Query{}, fmt.Errorf("cursor is not one this endpoint issued")
+		}
+		if sort != query.Sort {
+			return Query{}, fmt.Errorf("cursor was issued for sort=%s", sort)
+		}
+		if opts.ValidPosition != nil && !opts.ValidPosition(after) {
+			return Query{}, fmt.Errorf("cursor is not one this endpoint issued")
+		}
+		query.After = after
+	}
+
+	if raw := q.Get("fields"); raw != "" {
+		for _, field := range strings.Split(raw, ",") {
+			field = strings.TrimSpace(field)
+			if !slices.Contains(opts.Fields, field) {
+				return Query{}, fmt.Errorf("fields must be a comma-separated list of %s", strings.Join(opts.Fields, ", "))
+			}
+			if !slices.Contains(query.Fields, field) {
+				query.Fields = append(query.Fields, field)
+			}
+		}
+	}
+
+	return query, nil
+}
+
+// Fetch returns the page to ask the repository for: one item more than the limit, which tells
+// whether another page follows
+func (q Query) Fetch() Page {
+	page := q.Page
+	page.Limit++
+	return page
+}
+
+// Trim drops the extra item Fetch asked for and returns the page's items with the cursor of the next
+// page, or "" on the last one. position gives an item's place in the order, as the repository's After
+// compares it.
+func Trim[T any](items []T, query Query, position func(T) string) ([]T, string) {
+	if len(items) <= query.Limit {
+		return items, ""
+	}
+	items = items[:query.Limit]
+	return items, Cursor(query.Sort, position(items[len(items)-1]))
+}
+
+// Cursor encodes the position of a page's last item as an opaque cursor for sort
+func Cursor(sort, position string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorVersion + ":" + sort + ":" + position))
+}
+
+// decodeCursor returns the sort and position a cursor was issued with
+func decodeCursor(cursor string) (sort, position string, ok bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", "", false
+	}
+	parts := strings.SplitN(string(raw), ":", 3)
+	if len(parts) != 3 || parts[0] != cursorVersion || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+// JSONFields returns the JSON field names of a struct, in declaration order
+func JSONFields(v any) []string {
+	t := reflect.TypeOf(v)
+	var fields []string
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// Project returns page as JSON with the items of each of its arrays cut down to fields, in their
+// original order. With no fields, page is returned as it is.
+func Project(page any, fields []string) (any, error) {
+	if len(fields) == 0 {
+		return page, nil
+	}
+	raw, err := json.Marshal(page)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := objectMembers(raw)
+	if err != nil {
+		return nil, err
+	}
+	for i, member := range members {
+		var items []json.RawMessage
+		if bytes.HasPrefix(member.value, []byte("[")) && json.Unmarshal(member.value, &items) == nil {
+			for j, item := range items {
+				if items[j], err = keep(item, fields); err != nil {
+					return nil, err
+				}
+			}
+			if members[i].value, err = json.Marshal(items); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return encodeObject(members), nil
+}
+
+// member is a name and value of a JSON object
+type member struct {
+	name  string
+	value json.RawMessage
+}
+
+// objectMembers splits a JSON object into its members, in order
+func objectMembers(raw []byte) ([]member, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("not a JSON object")
+	}
+	var members []member
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		members = append(members, member{name: token.(string), value: value})
+	}
+	return members, nil
+}
+
+// keep cuts a JSON object down to fields; other values are returned as they are
+func keep(item json.RawMessage, fields []string) (json.RawMessage, error) {
+	if !bytes.HasPrefix(item, []byte("{")) {
+		return item, nil
+	}
+	members, err := objectMembers(item)
+	if err != nil {
+		return nil, err
+	}
+	members = slices.DeleteFunc(members, func(m member) bool { return !slices.Contains(fields, m.name) })
+	return encodeObject(members), nil
+}
+
+// encodeObject joins members into a JSON object
+func encodeObject(members []member) json.RawMessage {
+	var out bytes.Buffer
+	out.WriteByte('{')
+	for i, m := range members {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		name, _ := json.Marshal(m.name)
+		out.Write(name)
+		out.WriteByte(':')
+		out.Write(m.value)
+	}
+	out.WriteByte('}')
+	return out.Bytes()
+}
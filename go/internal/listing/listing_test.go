@@ -0,0 +1,108 @@
+package listing
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+var testOptions = Options{
+	DefaultLimit:  10,
+	MaxLimit:      50,
+	Sorts:         []string{"name", "-name"},
+	Fields:        []string{"id", "name", "status"},
+	ValidPosition: func(position string) bool { return position != "bad" },
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    Query
+		wantErr bool
+	}{
+		{name: "defaults", want: Query{Page: Page{Limit: 10}, Sort: "name"}},
+		{name: "limit", query: "limit=50", want: Query{Page: Page{Limit: 50}, Sort: "name"}},
+		{name: "descending", query: "sort=-name", want: Query{Page: Page{Limit: 10, Descending: true}, Sort: "-name"}},
+		{name: "cursor", query: "sort=-name&cursor=" + Cursor("-name", "b:c"), want: Query{Page: Page{After: "b:c", Limit: 10, Descending: true}, Sort: "-name"}},
+		{name: "fields", query: "fields=status, id,status", want: Query{Page: Page{Limit: 10}, Sort: "name", Fields: []string{"status", "id"}}},
+		{name: "zero limit", query: "limit=0", wantErr: true},
+		{name: "limit too large", query: "limit=51", wantErr: true},
+		{name: "unknown sort", query: "sort=createdAt", wantErr: true},
+		{name: "cursor of another sort", query: "sort=-name&cursor=" + Cursor("name", "b"), wantErr: true},
+		{name: "cursor not base64url", query: "cursor=!!", wantErr: true},
+		{name: "cursor without version", query: "cursor=YWJj", wantErr: true},
+		{name: "cursor with invalid position", query: "cursor=" + Cursor("name", "bad"), wantErr: true},
+		{name: "unknown field", query: "fields=id,secret", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery(%q): %v", tt.query, err)
+			}
+			got, err := Parse(q, testOptions)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, want error %v", tt.query, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrim(t *testing.T) {
+	query := Query{Page: Page{Limit: 2}, Sort: "-name"}
+	position := func(s string) string { return s }
+
+	items, next := Trim([]string{"c", "b", "a"}, query, position)
+	if !reflect.DeepEqual(items, []string{"c", "b"}) {
+		t.Errorf("Trim() items = %v, want [c b]", items)
+	}
+
+	// The next cursor resumes after the page's last item, for the same sort only
+	q, _ := url.ParseQuery("sort=-name&cursor=" + next)
+	resumed, err := Parse(q, testOptions)
+	if err != nil || resumed.After != "b" {
+		t.Errorf("Parse(next cursor) = %+v, %v; want After b", resumed, err)
+	}
+
+	if items, next := Trim([]string{"c", "b"}, query, position); len(items) != 2 || next != "" {
+		t.Errorf("Trim() of the last page = %v, %q; want both items and no cursor", items, next)
+	}
+}
+
+func TestProject(t *testing.T) {
+	type item struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Status string `json:"status,omitempty"`
+	}
+	type page struct {
+		Items      []item `json:"items"`
+		NextCursor string `json:"nextCursor,omitempty"`
+	}
+	p := page{Items: []item{{ID: "1", Name: "a", Status: "ok"}, {ID: "2", Name: "b"}}, NextCursor: "abc"}
+
+	got, err := Project(p, []string{"status", "id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(got)
+	// Fields keep the item's order, whatever the order they were asked in
+	want := `{"items":[{"id":"1","status":"ok"},{"id":"2"}],"nextCursor":"abc"}`
+	if string(raw) != want {
+		t.Errorf("Project() = %s, want %s", raw, want)
+	}
+
+	if got, _ := Project(p, nil); !reflect.DeepEqual(got, p) {
+		t.Errorf("Project() without fields = %v, want the page unchanged", got)
+	}
+
+	if fields := JSONFields(item{}); !reflect.DeepEqual(fields, []string{"id", "name", "status"}) {
+		t.Errorf("JSONFields() = %v, want [id name status]", fields)
+	}
+}
@@ -0,0 +1,92 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Policy is when an account is locked, and for how long
+type Policy struct {
+	MaxFailures int           // failed logins that lock the account
+	Duration    time.Duration // how long it stays locked, and how long failures are counted for
+}
+
+// Store counts failed logins and keeps account locks
+// Implemented by RedisStore and MemoryStore (STORAGE=memory). Counts and locks expire by the
+// wall clock, like a Redis TTL, so moving the simulated clock does not lift them.
+type Store interface {
+	// Fail counts a failed login and returns the failures counted within window of the first one
+	Fail(ctx context.Context, account string, window time.Duration) (int, error)
+	// Lock locks the account for d and forgets its failures
+	Lock(ctx context.Context, account string, d time.Duration) error
+	// LockedFor returns how long the account stays locked, or 0 if it is not
+	LockedFor(ctx context.Context, account string) (time.Duration, error)
+	// Unlock lifts the account's lock and forgets its failures; it returns whether it was locked
+	Unlock(ctx context.Context, account string) (bool, error)
+}
+
+// failuresKey generates the storage key counting an account's failed logins
+// Format: login_failures:{account}
+func failuresKey(account string) string {
+	return "login_failures:" + account
+}
+
+// lockKey generates the storage key of an account's lock
+// Format: login_lock:{account}
+func lockKey(account string) string {
+	return "login_lock:" + account
+}
+
+// RedisStore keeps failures and locks in Redis, shared by every replica
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a lockout store backed by Redis
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Fail increments the failure count, which expires window after the first failure
+func (s *RedisStore) Fail(ctx context.Context, account string, window time.Duration) (int, error) {
+	pipe := s.client.TxPipeline()
+	count := pipe.Incr(ctx, failuresKey(account))
+	pipe.ExpireNX(ctx, failuresKey(account), window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return int(count.Val()), nil
+}
+
+// Lock sets the lock key with a TTL of d and deletes the failure count
+func (s *RedisStore) Lock(ctx context.Context, account string, d time.Duration) error {
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, lockKey(account), 1, d)
+	pipe.Del(ctx, failuresKey(account))
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// LockedFor returns the lock key's remaining TTL
+func (s *RedisStore) LockedFor(ctx context.Context, account string) (time.Duration, error) {
+	ttl, err := s.client.PTTL(ctx, lockKey(account)).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, err
+	}
+	// PTTL answers -2 for a missing key (and -1 for one without a TTL, which Lock never writes)
+	return max(ttl, 0), nil
+}
+
+// Unlock deletes both keys
+func (s *RedisStore) Unlock(ctx context.Context, account string) (bool, error) {
+	pipe := s.client.TxPipeline()
+	locked := pipe.Del(ctx, lockKey(account))
+	pipe.Del(ctx, failuresKey(account))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+	return locked.Val() > 0, nil
+}
@@ -0,0 +1,91 @@
+package lockout
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// failures is an account's failed login count and when it is forgotten
+type failures struct {
+	count     int
+	expiresAt time.Time
+}
+
+// MemoryStore keeps failures and locks in process memory (STORAGE=memory)
+// They are per process: another replica lets a locked account log in.
+type MemoryStore struct {
+	mu       sync.Mutex
+	failures map[string]failures
+	locks    map[string]time.Time
+	now      func() time.Time // wall clock, like a Redis TTL; replaced in tests
+}
+
+// NewMemoryStore creates an in-memory lockout store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		failures: map[string]failures{},
+		locks:    map[string]time.Time{},
+		now:      time.Now,
+	}
+}
+
+// Fail counts a failed login; the count is forgotten window after the first failure
+func (s *MemoryStore) Fail(ctx context.Context, account string, window time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	f, ok := s.failures[account]
+	if !ok || !now.Before(f.expiresAt) {
+		f = failures{expiresAt: now.Add(window)}
+	}
+	f.count++
+	s.failures[account] = f
+	return f.count, nil
+}
+
+// Lock locks the account until d from now and forgets its failures
+// Expired locks are dropped on the way, since locking is rare.
+func (s *MemoryStore) Lock(ctx context.Context, account string, d time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for a, expiresAt := range s.locks {
+		if !now.Before(expiresAt) {
+			delete(s.locks, a)
+		}
+	}
+	for a, f := range s.failures {
+		if !now.Before(f.expiresAt) {
+			delete(s.failures, a)
+		}
+	}
+
+	s.locks[account] = now.Add(d)
+	delete(s.failures, account)
+	return nil
+}
+
+// LockedFor returns the time left on the account's lock
+func (s *MemoryStore) LockedFor(ctx context.Context, account string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if expiresAt, ok := s.locks[account]; ok {
+		return max(expiresAt.Sub(s.now()), 0), nil
+	}
+	return 0, nil
+}
+
+// Unlock lifts the account's lock and forgets its failures
+func (s *MemoryStore) Unlock(ctx context.Context, account string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiresAt, locked := s.locks[account]
+	delete(s.locks, account)
+	delete(s.failures, account)
+	return locked && s.now().Before(expiresAt), nil
+}
@@ -0,0 +1,57 @@
+package lockout
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	wall := time.Now()
+	s.now = func() time.Time { return wall }
+
+	for want := 1; want <= 2; want++ {
+		if n, _ := s.Fail(ctx, "alice", time.Minute); n != want {
+			t.Fatalf("Fail() = %d, want %d", n, want)
+		}
+	}
+
+	// The count is forgotten a window after the first failure, not the last
+	wall = wall.Add(time.Minute)
+	if n, _ := s.Fail(ctx, "alice", time.Minute); n != 1 {
+		t.Errorf("Fail() = %d after the window, want a fresh count of 1", n)
+	}
+
+	s.Lock(ctx, "alice", time.Minute)
+	wall = wall.Add(15 * time.Second)
+	if d, _ := s.LockedFor(ctx, "alice"); d != 45*time.Second {
+		t.Errorf("LockedFor() = %s, want 45s left", d)
+	}
+	if d, _ := s.LockedFor(ctx, "bob"); d != 0 {
+		t.Errorf("another account is locked for %s", d)
+	}
+	if n, _ := s.Fail(ctx, "alice", time.Minute); n != 1 {
+		t.Errorf("Fail() = %d after a lock, want the failures forgotten", n)
+	}
+
+	if unlocked, _ := s.Unlock(ctx, "alice"); !unlocked {
+		t.Error("Unlock() = false for a locked account")
+	}
+	if d, _ := s.LockedFor(ctx, "alice"); d != 0 {
+		t.Errorf("LockedFor() = %s after Unlock, want 0", d)
+	}
+	if unlocked, _ := s.Unlock(ctx, "alice"); unlocked {
+		t.Error("Unlock() = true for an account that is not locked")
+	}
+
+	s.Lock(ctx, "bob", time.Minute)
+	wall = wall.Add(time.Minute)
+	if d, _ := s.LockedFor(ctx, "bob"); d != 0 {
+		t.Errorf("LockedFor() = %s after the lock ran out, want 0", d)
+	}
+	if unlocked, _ := s.Unlock(ctx, "bob"); unlocked {
+		t.Error("Unlock() = true for a lock that ran out")
+	}
+}
@@ -4,17 +4,36 @@ import (
 	otellog "go.opentelemetry.io/otel/log"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+
+	"github.com/dict-simulator/go/internal/redact"
 )
 
 // Log is the global logger instance
 var Log *zap.Logger
 
-// Init initializes the Zap logger with JSON output
-func Init(env string, _ otellog.LoggerProvider) error {
+// Level is the minimum level Log writes; setting it takes effect immediately
+var Level = zap.NewAtomicLevel()
+
+// Levels Init and Level accept
+var Levels = []string{"debug", "info", "warn", "error"}
+
+// Encodings Init accepts
+const (
+	EncodingJSON    = "json"
+	EncodingConsole = "console"
+)
+
+// Init initializes the Zap logger writing entries at level and above, as JSON or console lines
+// Entries are masked by redactor on their way out; nil logs them as they are.
+func Init(env, level, encoding string, redactor *redact.Redactor, _ otellog.LoggerProvider) error {
+	if err := Level.UnmarshalText([]byte(level)); err != nil {
+		return err
+	}
+
 	config := zap.Config{
-		Level:       zap.NewAtomicLevelAt(zap.InfoLevel),
+		Level:       Level,
 		Development: env == "development",
-		Encoding:    "json",
+		Encoding:    encoding,
 		EncoderConfig: zapcore.EncoderConfig{
 			TimeKey:        "timestamp",
 			LevelKey:       "level",
@@ -33,8 +52,15 @@ func Init(env string, _ otellog.LoggerProvider) error {
 		ErrorOutputPaths: []string{"stderr"},
 	}
 
+	var opts []zap.Option
+	if redactor != nil {
+		opts = append(opts, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return redactingCore{Core: core, redactor: redactor}
+		}))
+	}
+
 	var err error
-	Log, err = config.Build()
+	Log, err = config.Build(opts...)
 	if err != nil {
 		return err
 	}
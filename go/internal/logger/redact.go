@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/dict-simulator/go/internal/redact"
+)
+
+// redactingCore masks tax IDs, emails, phone keys and passwords in every entry before the wrapped
+// core encodes it: fields named as redacted are masked by name, other strings and errors where they
+// hold something shaped like personal data
+type redactingCore struct {
+	zapcore.Core
+	redactor *redact.Redactor
+}
+
+func (c redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return redactingCore{Core: c.Core.With(c.redact(fields)), redactor: c.redactor}
+}
+
+func (c redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = c.redactor.Text(entry.Message)
+	return c.Core.Write(entry, c.redact(fields))
+}
+
+// redact returns fields with their values masked; the caller's slice is left alone
+func (c redactingCore) redact(fields []zapcore.Field) []zapcore.Field {
+	out := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		switch field.Type {
+		case zapcore.StringType:
+			field.String = c.redactor.Field(field.Key, field.String)
+		case zapcore.ErrorType:
+			if err, ok := field.Interface.(error); ok {
+				field = zap.String(field.Key, c.redactor.Text(err.Error()))
+			}
+		case zapcore.StringerType:
+			if s, ok := field.Interface.(fmt.Stringer); ok {
+				field = zap.String(field.Key, c.redactor.Field(field.Key, s.String()))
+			}
+		}
+		out[i] = field
+	}
+	return out
+}
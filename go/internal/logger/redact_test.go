@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/dict-simulator/go/internal/redact"
+)
+
+func TestRedactingCore(t *testing.T) {
+	observed, logs := observer.New(zapcore.InfoLevel)
+	log := zap.New(redactingCore{Core: observed, redactor: redact.New(redact.DefaultFields)}).
+		With(zap.String("email", "john@example.com"))
+
+	log.Info("lookup of 52998224725 failed",
+		zap.String("path", "/entries/52998224725"),
+		zap.String("taxIdNumber", "11222333000181"),
+		zap.String("participant", "12345678"),
+		zap.Error(errors.New("duplicate key +5511987654321")),
+	)
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d entries, want 1", len(entries))
+	}
+	if want := "lookup of ***982247** failed"; entries[0].Message != want {
+		t.Errorf("message = %q, want %q", entries[0].Message, want)
+	}
+	want := map[string]any{
+		"email":       "j***@example.com",
+		"path":        "/entries/***982247**",
+		"taxIdNumber": "11222333******",
+		"participant": "12345678",
+		"error":       "duplicate key +55*******4321",
+	}
+	got := entries[0].ContextMap()
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %v, want %v", key, got[key], value)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// AdminTokenHeader carries the shared secret that unlocks the /admin routes
+const AdminTokenHeader = "X-Admin-Token"
+
+// AdminAuth only lets through requests whose X-Admin-Token matches token, or, without one,
+// whose bearer token was granted the admin scope
+// bearer authenticates the bearer token (AuthMiddleware). Only OAuth clients, which only admins
+// register, can hold the admin scope: anyone can register as a user, so a user token says nothing
+// about being allowed to seed, wipe or time-travel the directory.
+func AdminAuth(token string, bearer func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		requireAdmin := bearer(RequireScope(models.ScopeAdmin)(next))
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			provided := r.Header.Get(AdminTokenHeader)
+			if provided == "" && r.Header.Get("Authorization") != "" {
+				requireAdmin.ServeHTTP(w, r)
+				return
+			}
+			if provided == "" {
+				httputil.WriteAPIError(w, r, constants.ErrAdminTokenRequired)
+				return
+			}
+			if !adminTokenMatches(provided, token) {
+				httputil.WriteAPIError(w, r, constants.ErrInvalidAdminToken)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// adminTokenMatches reports whether provided is the admin token; no token is configured when token is empty
+func adminTokenMatches(provided, token string) bool {
+	return token != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1
+}
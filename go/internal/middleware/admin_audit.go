@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/logger"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// maxAuditedBody bounds the request bodies whose fields are kept in the audit record
+// Larger bodies, such as snapshot imports, are passed through without being recorded.
+const maxAuditedBody = 16 << 10
+
+// AdminAudit records every admin action in audits once it has been answered: who took it (see
+// actorOf), the action name actionOf gives the route, its query parameters and JSON body fields,
+// the status and the correlation ID. GET and HEAD requests only read, so they are not recorded.
+// Must sit inside AdminAuth, so only authenticated actions are recorded and the actor is known.
+// A record that can't be stored is logged; the action has already been answered.
+func (m *Manager) AdminAudit(audits models.AdminAuditRepository, actionOf func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			parameters := auditedParameters(r)
+			capture := &responseCapture{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(capture, r)
+
+			record := &models.AdminAuditRecord{
+				Actor:         actorOf(r),
+				Action:        actionOf(r),
+				Method:        r.Method,
+				Path:          auditedPath(r),
+				Parameters:    parameters,
+				Status:        capture.statusCode,
+				CorrelationID: httputil.CorrelationIDFromContext(r.Context()),
+			}
+
+			// Recorded even when the client has gone away, since the action was taken
+			ctx, cancel := m.bookkeepingContext(r)
+			defer cancel()
+			if err := audits.Create(ctx, record); err != nil {
+				logger.Error("Failed to record admin action",
+					zap.String("action", record.Action),
+					zap.String("actor", record.Actor),
+					zap.String("correlation_id", record.CorrelationID),
+					zap.String("request_id", httputil.RequestIDFromContext(r.Context())),
+					zap.Error(err),
+				)
+			}
+		})
+	}
+}
+
+// actorOf names who an admin request was made by: the OAuth client (or user) its bearer token was
+// issued to, or "admin-token" for the shared X-Admin-Token
+func actorOf(r *http.Request) string {
+	id, ok := IdentityFromContext(r.Context())
+	switch {
+	case !ok:
+		return "admin-token"
+	case id.ClientID != "":
+		return "client:" + id.ClientID
+	default:
+		return "user:" + id.UserID
+	}
+}
+
+// auditedPath returns the request path with its {taxId} value, if any, masked: erasure requests
+// name the person whose data they remove, and the record would otherwise keep it
+func auditedPath(r *http.Request) string {
+	if taxID := r.PathValue("taxId"); taxID != "" {
+		return strings.Replace(r.URL.Path, taxID, "***", 1)
+	}
+	return r.URL.Path
+}
+
+// auditedParameters returns the query parameters and the fields of a JSON object body, or nil when
+// there are none, leaving the body for the handler to read
+func auditedParameters(r *http.Request) map[string]any {
+	parameters := map[string]any{}
+	for name, values := range r.URL.Query() {
+		if len(values) == 1 {
+			parameters[name] = values[0]
+		} else {
+			parameters[name] = values
+		}
+	}
+
+	if r.Body != nil && r.Body != http.NoBody {
+		peeked, err := io.ReadAll(io.LimitReader(r.Body, maxAuditedBody+1))
+		if err != nil || len(peeked) > maxAuditedBody {
+			// Hand the handler the whole body, or its read error: what was read, then the rest
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(peeked), r.Body), r.Body}
+		} else {
+			r.Body = io.NopCloser(bytes.NewReader(peeked))
+			var fields map[string]any
+			if json.Unmarshal(peeked, &fields) == nil {
+				for name, value := range fields {
+					parameters[name] = value
+				}
+			}
+		}
+	}
+
+	if len(parameters) == 0 {
+		return nil
+	}
+	return parameters
+}
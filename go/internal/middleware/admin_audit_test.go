@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dict-simulator/go/internal/chaos"
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/listing"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/ratelimit"
+	"github.com/dict-simulator/go/internal/signing"
+)
+
+func TestAdminAuditRecordsActions(t *testing.T) {
+	clk := clock.NewSimulated()
+	audits := models.NewMemoryAdminAuditRepository(clk)
+	m := NewManager(models.NewMemoryIdempotencyRepository(clk), ratelimit.NewMemoryBucket(clk), ratelimit.NewMemoryBanStore(), signing.NewMemoryNonceStore(), chaos.NewInjector(clk), Settings{})
+
+	var handlerBody string
+	handler := m.AdminAudit(audits, func(r *http.Request) string { return "admin.seed" })(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		handlerBody = string(body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/seed?dryRun=true", strings.NewReader(`{"count":10}`))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Reads are not recorded
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/admin/seed", nil))
+
+	// Bearer tokens are recorded as their client; bodies too large to keep still reach the handler
+	large := `{"entries":"` + strings.Repeat("x", maxAuditedBody) + `"}`
+	req = httptest.NewRequest(http.MethodPost, "/admin/seed", strings.NewReader(large))
+	req = req.WithContext(WithIdentity(req.Context(), Identity{ClientID: "c1"}))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if handlerBody != large {
+		t.Errorf("handler read %d bytes of a %d-byte body", len(handlerBody), len(large))
+	}
+
+	records, err := audits.List(context.Background(), models.AdminAuditFilter{Page: listing.Page{Limit: 10, Descending: true}})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("recorded %d actions, want 2", len(records))
+	}
+
+	newest, oldest := records[0], records[1]
+	if newest.Actor != "client:c1" || newest.Parameters != nil {
+		t.Errorf("large body record = actor %q, parameters %v; want client:c1 and none", newest.Actor, newest.Parameters)
+	}
+	if oldest.Actor != "admin-token" || oldest.Action != "admin.seed" || oldest.Status != http.StatusCreated {
+		t.Errorf("record = %+v, want admin-token's admin.seed answered 201", oldest)
+	}
+	if oldest.Parameters["count"] != float64(10) || oldest.Parameters["dryRun"] != "true" {
+		t.Errorf("parameters = %v, want the body's count and the query's dryRun", oldest.Parameters)
+	}
+}
+
+func TestAdminAuditMasksTaxIDs(t *testing.T) {
+	clk := clock.NewSimulated()
+	audits := models.NewMemoryAdminAuditRepository(clk)
+	m := NewManager(models.NewMemoryIdempotencyRepository(clk), ratelimit.NewMemoryBucket(clk), ratelimit.NewMemoryBanStore(), signing.NewMemoryNonceStore(), chaos.NewInjector(clk), Settings{})
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /admin/erasure/{taxId}", m.AdminAudit(audits, func(r *http.Request) string { return "admin.erasure" })(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	))
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/admin/erasure/52998224725", nil))
+
+	records, _ := audits.List(context.Background(), models.AdminAuditFilter{Page: listing.Page{Limit: 10, Descending: true}})
+	if len(records) != 1 || records[0].Path != "/admin/erasure/***" {
+		t.Errorf("records = %+v, want one with the tax ID masked", records)
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dict-simulator/go/internal/models"
+)
+
+func TestAdminAuth(t *testing.T) {
+	// Stands in for AuthMiddleware: "admin" and "user" tokens are valid, with or without the admin scope
+	bearer := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var scopes []models.Scope
+			switch r.Header.Get("Authorization") {
+			case "Bearer admin":
+				scopes = []models.Scope{models.ScopeAdmin}
+			case "Bearer user":
+				scopes = models.UserScopes
+			default:
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), Identity{Scopes: scopes})))
+		})
+	}
+	handler := AdminAuth("s3cret", bearer)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   int
+	}{
+		{name: "missing", want: http.StatusUnauthorized},
+		{name: "wrong token", header: map[string]string{AdminTokenHeader: "guess"}, want: http.StatusUnauthorized},
+		{name: "invalid jwt", header: map[string]string{"Authorization": "Bearer s3cret"}, want: http.StatusUnauthorized},
+		{name: "user jwt", header: map[string]string{"Authorization": "Bearer user"}, want: http.StatusForbidden},
+		{name: "admin scope", header: map[string]string{"Authorization": "Bearer admin"}, want: http.StatusNoContent},
+		{name: "admin token", header: map[string]string{AdminTokenHeader: "s3cret"}, want: http.StatusNoContent},
+		{name: "wrong token with admin scope", header: map[string]string{AdminTokenHeader: "guess", "Authorization": "Bearer admin"}, want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/admin/seed", nil)
+			for name, value := range tt.header {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
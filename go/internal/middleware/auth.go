@@ -1,57 +1,168 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/dict-simulator/go/internal/constants"
 	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/jwtkeys"
+	"github.com/dict-simulator/go/internal/models"
 )
 
 // JWTClaims represents the claims in the JWT token
+// User tokens carry UserID, Email and Name; OAuth client tokens carry ClientID and Participant instead.
+// Both carry the Scope they were granted.
 type JWTClaims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
-	Name   string `json:"name"`
+	UserID      string `json:"user_id,omitempty"`
+	Email       string `json:"email,omitempty"`
+	Name        string `json:"name,omitempty"`
+	ClientID    string `json:"client_id,omitempty"`
+	Participant string `json:"participant,omitempty"` // ISPB the client was registered for
+	Scope       string `json:"scope,omitempty"`       // space-delimited, as granted by POST /auth/login or POST /oauth/token
 	jwt.RegisteredClaims
 }
 
 const Bearer = "Bearer "
 
-// AuthMiddleware validates JWT tokens and sets X-User-Id header for downstream handlers
-func AuthMiddleware(jwtSecret string) func(handler http.Handler) http.Handler {
+// UserFinder looks up the user a token was issued to; models.UserRepository satisfies it
+type UserFinder interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error)
+}
+
+// ClientFinder looks up the OAuth client a token was issued to; models.OAuthClientRepository satisfies it
+type ClientFinder interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.OAuthClient, error)
+}
+
+// AuthMiddleware validates JWT tokens and puts the caller's Identity in the request context
+// Tokens of users that were deleted or disabled since they logged in are refused.
+// OAuth client tokens speak for the client's participant instead; tokens of deleted clients are
+// refused. The scopes of both are checked by RequireScope.
+// Only tokens signed with the key set's algorithm are accepted, so an HS256 token can't pass off a
+// published public key as its secret.
+// User tokens are not tied to a participant: one named in X-Participant-Id is only taken alongside
+// X-Admin-Token matching adminToken, which test suites hold, and refused otherwise.
+func AuthMiddleware(keys *jwtkeys.KeySet, users UserFinder, clients ClientFinder, adminToken string) func(handler http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authorization := r.Header.Get("Authorization")
 
 			if authorization == "" {
-				httputil.WriteError(w, constants.ErrAuthHeaderRequired)
+				httputil.WriteAPIError(w, r, constants.ErrAuthHeaderRequired)
 				return
 			}
 
 			// Remove "Bearer " prefix if present
 			tokenString := strings.TrimPrefix(authorization, Bearer)
 
-			token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (any, error) {
-				return []byte(jwtSecret), nil
-			})
+			token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, keys.Keyfunc, jwt.WithValidMethods([]string{keys.Algorithm()}))
 
 			if err != nil || !token.Valid {
-				httputil.WriteError(w, constants.ErrInvalidToken)
+				httputil.WriteAPIError(w, r, constants.ErrInvalidToken)
 				return
 			}
 
 			claims, ok := token.Claims.(*JWTClaims)
 			if !ok {
-				httputil.WriteError(w, constants.ErrInvalidTokenClaims)
+				httputil.WriteAPIError(w, r, constants.ErrInvalidTokenClaims)
+				return
+			}
+
+			if claims.ClientID != "" {
+				if apiErr, ok := checkClient(r, clients, claims); !ok {
+					httputil.WriteAPIError(w, r, apiErr)
+					return
+				}
+
+				// The participant is whoever the client was registered for, whatever the request says
+				scopes, _ := models.ParseScopes(claims.Scope)
+				next.ServeHTTP(w, setIdentity(r, Identity{
+					ClientID:    claims.ClientID,
+					Participant: claims.Participant,
+					Scopes:      scopes,
+				}))
+				return
+			}
+
+			id, err := primitive.ObjectIDFromHex(claims.UserID)
+			if err != nil {
+				httputil.WriteAPIError(w, r, constants.ErrInvalidTokenClaims)
+				return
+			}
+
+			user, err := users.FindByID(r.Context(), id)
+			if err != nil {
+				httputil.WriteError(w, r, err, constants.ErrFailedToFindUser)
+				return
+			}
+			if user == nil {
+				httputil.WriteAPIError(w, r, constants.ErrUnauthorized)
+				return
+			}
+			if user.Disabled {
+				httputil.WriteAPIError(w, r, constants.ErrUserDisabled)
+				return
+			}
+
+			// Anyone can register, so a user naming a participant would get its rate limits and entries
+			participant := r.Header.Get(IdentifierHeader)
+			if participant != "" && !adminTokenMatches(r.Header.Get(AdminTokenHeader), adminToken) {
+				httputil.WriteAPIError(w, r, constants.ErrParticipantOverride)
 				return
 			}
 
-			// Set user ID in request header for downstream handlers
-			r.Header.Set("X-User-Id", claims.UserID)
+			// Tokens issued before user tokens carried scopes hold every user scope
+			scopes := models.UserScopes
+			if claims.Scope != "" {
+				scopes, _ = models.ParseScopes(claims.Scope)
+			}
+
+			next.ServeHTTP(w, setIdentity(r, Identity{
+				UserID:      claims.UserID,
+				Participant: participant,
+				Scopes:      scopes,
+			}))
+		})
+	}
+}
+
+// checkClient checks that the client of a token still exists and the request speaks for its participant
+func checkClient(r *http.Request, clients ClientFinder, claims *JWTClaims) (constants.APIError, bool) {
+	id, err := primitive.ObjectIDFromHex(claims.ClientID)
+	if err != nil {
+		return constants.ErrInvalidTokenClaims, false
+	}
 
+	client, err := clients.FindByID(r.Context(), id)
+	if err != nil {
+		return constants.ErrFailedToFindClient, false
+	}
+	// Deleted since the token was issued
+	if client == nil || client.Participant != claims.Participant {
+		return constants.ErrInvalidToken, false
+	}
+
+	if participant := r.Header.Get(IdentifierHeader); participant != "" && participant != claims.Participant {
+		return constants.ErrParticipantMismatch, false
+	}
+	return constants.APIError{}, true
+}
+
+// RequireScope refuses tokens that were not granted scope
+// Must run after AuthMiddleware, which puts the token's scopes in the request's Identity.
+func RequireScope(scope models.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if id, _ := IdentityFromContext(r.Context()); !slices.Contains(id.Scopes, scope) {
+				httputil.WriteAPIError(w, r, constants.ErrInsufficientScope)
+				return
+			}
 			next.ServeHTTP(w, r)
 		})
 	}
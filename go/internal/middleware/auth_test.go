@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/jwtkeys"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+func TestAuthParticipantOverride(t *testing.T) {
+	users := models.NewMemoryUserRepository()
+	user, err := users.Create(context.Background(), "user@example.com", "testpassword123", "Test User")
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	keys := jwtkeys.New(jwtkeys.Config{Algorithm: "HS256", Secret: "test-secret"}, jwtkeys.NewMemoryStore())
+	token, err := keys.Sign(&JWTClaims{UserID: user.ID.Hex()})
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	var got string
+	handler := AuthMiddleware(keys, users, models.NewMemoryOAuthClientRepository(clock.NewSimulated()), "s3cret")(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = rateLimitIdentifier(r)
+		}))
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   int
+		bucket string
+	}{
+		{name: "no participant", want: http.StatusOK, bucket: "user:" + user.ID.Hex()},
+		{name: "participant", header: map[string]string{IdentifierHeader: "12345678"}, want: http.StatusForbidden},
+		{name: "participant with a wrong admin token", header: map[string]string{IdentifierHeader: "12345678", AdminTokenHeader: "guess"}, want: http.StatusForbidden},
+		{name: "participant with the admin token", header: map[string]string{IdentifierHeader: "12345678", AdminTokenHeader: "s3cret"}, want: http.StatusOK, bucket: "12345678"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = ""
+			req := httptest.NewRequest(http.MethodGet, "/entries/12345678901", nil)
+			req.Header.Set("Authorization", Bearer+token)
+			for name, value := range tt.header {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if got != tt.bucket {
+				t.Errorf("bucket = %q, want %q", got, tt.bucket)
+			}
+		})
+	}
+}
+
+func TestAuthorizeParticipant(t *testing.T) {
+	tests := []struct {
+		name        string
+		identity    *Identity
+		participant string
+		want        bool
+	}{
+		{"client of the participant", &Identity{ClientID: "c1", Participant: "12345678"}, "12345678", true},
+		{"client of another participant", &Identity{ClientID: "c1", Participant: "12345678"}, "87654321", false},
+		{"user naming a participant", &Identity{UserID: "u1", Participant: "12345678"}, "87654321", false},
+		{"user naming none", &Identity{UserID: "u1"}, "87654321", true},
+		{"unauthenticated", nil, "87654321", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/participants/"+tt.participant+"/entries", nil)
+			if tt.identity != nil {
+				req = req.WithContext(WithIdentity(req.Context(), *tt.identity))
+			}
+			rec := httptest.NewRecorder()
+
+			if got := AuthorizeParticipant(rec, req, tt.participant); got != tt.want {
+				t.Fatalf("AuthorizeParticipant() = %v, want %v", got, tt.want)
+			}
+			if !tt.want && rec.Code != http.StatusForbidden {
+				t.Errorf("refusal answered %d, want 403", rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/dict-simulator/go/internal/httputil"
+)
+
+// BodyLimit caps request bodies at maxBytes so an oversized upload is never buffered whole
+// Bodies declaring a larger Content-Length are refused with a 413 up front; bodies sent without
+// one fail when a reader crosses the limit (see httputil.BodyError).
+// Requests to the exempt routes ("METHOD /path", e.g. "POST /admin/import") are passed through
+// untouched; those routes apply their own limit.
+func BodyLimit(maxBytes int64, exempt ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if slices.Contains(exempt, r.Method+" "+r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if r.ContentLength > maxBytes {
+				httputil.WriteAPIError(w, r, httputil.BodyError(&http.MaxBytesError{Limit: maxBytes}))
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
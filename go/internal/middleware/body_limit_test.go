@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBodyLimitExemptRoutes(t *testing.T) {
+	handler := BodyLimit(8, "POST /admin/import")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		}
+	}))
+	body := strings.Repeat("x", 64)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/entries", strings.NewReader(body)))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("POST /entries: status %d, want 413", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/import", strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Errorf("POST /admin/import: status %d, want 200", rec.Code)
+	}
+}
@@ -9,3 +9,10 @@ func Chain(handler http.Handler, middlewares ...func(http.Handler) http.Handler)
 	}
 	return handler
 }
+
+// serveCopy serves served, a copy of r carrying a new context, and copies the route the ServeMux
+// matched back onto r, so middlewares further out (access log, metrics) can read it off their own request
+func serveCopy(next http.Handler, w http.ResponseWriter, r, served *http.Request) {
+	next.ServeHTTP(w, served)
+	r.Pattern = served.Pattern
+}
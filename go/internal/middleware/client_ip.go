@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/httputil"
+)
+
+// ClientIP settles the address each request came from and stores it in the request context
+// (httputil.ClientIP) for the IP rate limit and the access log. Forwarding headers are only
+// believed when the connection comes from one of the trusted proxies.
+func (m *Manager) ClientIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := clientIP(r, m.settings.Load().TrustedProxies)
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("client.address", ip))
+
+		serveCopy(next, w, r, r.WithContext(httputil.WithClientIP(r.Context(), ip)))
+	})
+}
+
+// clientIP returns the address a request came from
+// That is the connection's peer unless the peer is a trusted proxy. X-Forwarded-For is then read
+// from the right, past the entries of further trusted proxies: the first other entry is the
+// client, and anything to its left was written by the client and can be forged. A trusted proxy
+// that sends no X-Forwarded-For can name the client in X-Real-IP instead.
+func clientIP(r *http.Request, trusted []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	peer, err := netip.ParseAddr(host)
+	if err != nil || !isTrusted(peer, trusted) {
+		return host
+	}
+
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		hops := strings.Split(strings.Join(forwarded, ","), ",")
+		client := peer
+		for _, hop := range slices.Backward(hops) {
+			addr, err := netip.ParseAddr(strings.TrimSpace(hop))
+			if err != nil {
+				// Garbage from here on: the last hop read is as far as the proxies vouch
+				break
+			}
+			client = addr
+			if !isTrusted(addr, trusted) {
+				break
+			}
+		}
+		return client.Unmap().String()
+	}
+
+	if addr, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+		return addr.Unmap().String()
+	}
+	return host
+}
+
+// isTrusted reports whether addr belongs to one of the trusted proxy ranges
+func isTrusted(addr netip.Addr, trusted []netip.Prefix) bool {
+	addr = addr.Unmap()
+	return slices.ContainsFunc(trusted, func(p netip.Prefix) bool { return p.Contains(addr) })
+}
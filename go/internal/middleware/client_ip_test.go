@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/dict-simulator/go/internal/chaos"
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/ratelimit"
+	"github.com/dict-simulator/go/internal/signing"
+)
+
+func TestClientIPResolution(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("2001:db8:ff::/48")}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		realIP     string
+		want       string
+	}{
+		{"remote address", "192.0.2.1:1234", nil, "", "192.0.2.1"},
+		{"IPv6 remote address", "[2001:db8::1]:1234", nil, "", "2001:db8::1"},
+		{"untrusted peer's forwarded header ignored", "192.0.2.1:1234", []string{"203.0.113.9"}, "", "192.0.2.1"},
+		{"untrusted peer's real IP ignored", "192.0.2.1:1234", nil, "203.0.113.9", "192.0.2.1"},
+		{"proxy's entry", "10.0.0.2:1234", []string{"203.0.113.9, 198.51.100.4"}, "", "198.51.100.4"},
+		{"chain of trusted proxies", "10.0.0.2:1234", []string{"203.0.113.9, 198.51.100.4, 10.1.2.3"}, "", "198.51.100.4"},
+		{"last header", "10.0.0.2:1234", []string{"203.0.113.9", "198.51.100.4"}, "", "198.51.100.4"},
+		{"only proxies", "10.0.0.2:1234", []string{"10.9.9.9, 10.1.2.3"}, "", "10.9.9.9"},
+		{"garbage entry", "10.0.0.2:1234", []string{"198.51.100.4, bogus, 10.1.2.3"}, "", "10.1.2.3"},
+		{"IPv6 proxy", "[2001:db8:ff::1]:1234", []string{"2001:db8:1::7"}, "", "2001:db8:1::7"},
+		{"real IP behind proxy", "10.0.0.2:1234", nil, "203.0.113.9", "203.0.113.9"},
+		{"forwarded header wins over real IP", "10.0.0.2:1234", []string{"198.51.100.4"}, "203.0.113.9", "198.51.100.4"},
+		{"no header behind proxy", "10.0.0.2:1234", nil, "", "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", v)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+			if got := clientIP(req, trusted); got != tt.want {
+				t.Errorf("clientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientIPStoresAddressInContext(t *testing.T) {
+	clk := clock.NewSimulated()
+	m := NewManager(models.NewMemoryIdempotencyRepository(clk), ratelimit.NewMemoryBucket(clk), ratelimit.NewMemoryBanStore(), signing.NewMemoryNonceStore(), chaos.NewInjector(clk), Settings{
+		TrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+	})
+
+	var got string
+	handler := m.ClientIP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = httputil.ClientIP(r)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	req.RemoteAddr = "10.0.0.2:1234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.4")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got != "198.51.100.4" {
+		t.Errorf("httputil.ClientIP() = %q, want the forwarded address", got)
+	}
+}
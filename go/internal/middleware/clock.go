@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/httputil"
+)
+
+// Clock stamps responses with clk's time instead of the wall clock, so ResponseTime follows
+// the simulated clock after an /admin/time/advance.
+func Clock(clk clock.Clock) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			serveCopy(next, w, r, r.WithContext(httputil.WithClock(r.Context(), clk)))
+		})
+	}
+}
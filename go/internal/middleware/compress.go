@@ -0,0 +1,250 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Content codings Compression can apply, in order of preference when the client weighs them equally
+const (
+	encodingZstd = "zstd"
+	encodingGzip = "gzip"
+)
+
+var (
+	gzipWriters = sync.Pool{New: func() any {
+		return gzip.NewWriter(io.Discard)
+	}}
+	zstdWriters = sync.Pool{New: func() any {
+		// One goroutine per encoder: responses are small and every request already has its own
+		enc, _ := zstd.NewWriter(io.Discard, zstd.WithEncoderConcurrency(1))
+		return enc
+	}}
+)
+
+// encoder is the part of gzip.Writer and zstd.Encoder used to compress a response
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// compressResponseWriter holds back the first minBytes of a response to decide whether it is
+// worth compressing, then either compresses the rest or passes it through
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding string
+	minBytes int
+
+	status  int
+	buf     bytes.Buffer
+	decided bool
+	enc     encoder
+}
+
+func (cw *compressResponseWriter) WriteHeader(code int) {
+	if cw.status == 0 {
+		cw.status = code
+	}
+}
+
+func (cw *compressResponseWriter) Write(b []byte) (int, error) {
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+	if !cw.decided {
+		cw.buf.Write(b)
+		if cw.buf.Len() < cw.minBytes {
+			return len(b), nil
+		}
+		if err := cw.decide(true); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	if cw.enc != nil {
+		return cw.enc.Write(b)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+// Flush sends what has been written so far, compressing it if it already reached minBytes
+func (cw *compressResponseWriter) Flush() {
+	if !cw.decided {
+		if cw.status == 0 {
+			cw.status = http.StatusOK
+		}
+		if err := cw.decide(cw.buf.Len() >= cw.minBytes); err != nil {
+			return
+		}
+	}
+	if cw.enc != nil {
+		cw.enc.Flush()
+	}
+	http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (cw *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// decide sends the status line and the buffered bytes, compressed when compress is set and the
+// response can be compressed
+func (cw *compressResponseWriter) decide(compress bool) error {
+	cw.decided = true
+
+	header := cw.Header()
+	if compress && compressible(cw.status, header) {
+		header.Set("Content-Encoding", cw.encoding)
+		header.Del("Content-Length")
+		cw.enc = newEncoder(cw.encoding, cw.ResponseWriter)
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	if cw.buf.Len() == 0 {
+		return nil
+	}
+	var err error
+	if cw.enc != nil {
+		_, err = cw.enc.Write(cw.buf.Bytes())
+	} else {
+		_, err = cw.ResponseWriter.Write(cw.buf.Bytes())
+	}
+	cw.buf.Reset()
+	return err
+}
+
+// finish sends whatever is still held back and ends the compressed stream
+func (cw *compressResponseWriter) finish() {
+	if !cw.decided {
+		if cw.status == 0 {
+			// Nothing was written: let the server send its implicit 200
+			return
+		}
+		cw.decide(false)
+	}
+	if cw.enc == nil {
+		return
+	}
+	cw.enc.Close()
+	switch enc := cw.enc.(type) {
+	case *gzip.Writer:
+		enc.Reset(io.Discard)
+		gzipWriters.Put(enc)
+	case *zstd.Encoder:
+		enc.Reset(io.Discard)
+		zstdWriters.Put(enc)
+	}
+}
+
+// newEncoder takes an encoder for encoding from its pool, writing to w
+func newEncoder(encoding string, w io.Writer) encoder {
+	var enc encoder
+	if encoding == encodingZstd {
+		enc = zstdWriters.Get().(*zstd.Encoder)
+	} else {
+		enc = gzipWriters.Get().(*gzip.Writer)
+	}
+	enc.Reset(w)
+	return enc
+}
+
+// compressible reports whether a response with this status and header may be compressed
+// Bodiless statuses, responses the handler already encoded (e.g. /metrics) and formats that are
+// compressed already are passed through.
+func compressible(status int, header http.Header) bool {
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		// Untyped bodies are sniffed as text by net/http
+		return header.Get("Content-Type") == ""
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		mediaType == "application/json",
+		mediaType == "application/x-ndjson",
+		mediaType == "application/xml",
+		mediaType == "application/javascript",
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	return false
+}
+
+// negotiateEncoding picks the content coding for an Accept-Encoding header, or "" for identity
+// The coding with the highest q-value wins; zstd is preferred over gzip on a tie.
+func negotiateEncoding(acceptEncoding string) string {
+	best, bestQ := "", 0.0
+	wildcardQ := -1.0
+	qs := map[string]float64{}
+
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		q := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if coding == "*" {
+			wildcardQ = q
+			continue
+		}
+		qs[coding] = q
+	}
+
+	for _, coding := range []string{encodingZstd, encodingGzip} {
+		q, ok := qs[coding]
+		if !ok {
+			q = wildcardQ
+		}
+		if q > bestQ {
+			best, bestQ = coding, q
+		}
+	}
+	return best
+}
+
+// Compression compresses responses of at least minBytes with zstd or gzip, as negotiated through
+// Accept-Encoding. Smaller responses are sent as they are, since compressing them saves little.
+// Responses always vary by Accept-Encoding, so shared caches keep the codings apart.
+func Compression(minBytes int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressResponseWriter{
+				ResponseWriter: w,
+				encoding:       encoding,
+				minBytes:       minBytes,
+			}
+			next.ServeHTTP(cw, r)
+			cw.finish()
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := map[string]string{
+		"":                       "",
+		"identity":               "",
+		"gzip":                   "gzip",
+		"gzip, deflate, br":      "gzip",
+		"zstd":                   "zstd",
+		"gzip, zstd":             "zstd",
+		"gzip;q=1.0, zstd;q=0.5": "gzip",
+		"GZIP":                   "gzip",
+		"zstd;q=0, gzip":         "gzip",
+		"gzip;q=0":               "",
+		"*":                      "zstd",
+		"*;q=0.5, gzip":          "gzip",
+		"gzip;q=bogus":           "",
+	}
+
+	for header, want := range tests {
+		if got := negotiateEncoding(header); got != want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestCompression(t *testing.T) {
+	large := `{"data":"` + strings.Repeat("a", 2048) + `"}`
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		handler        http.HandlerFunc
+		wantEncoding   string
+		wantBody       string
+	}{
+		{
+			name:           "gzip",
+			acceptEncoding: "gzip",
+			handler:        writeJSON(http.StatusOK, large),
+			wantEncoding:   "gzip",
+			wantBody:       large,
+		},
+		{
+			name:           "zstd",
+			acceptEncoding: "gzip, zstd",
+			handler:        writeJSON(http.StatusOK, large),
+			wantEncoding:   "zstd",
+			wantBody:       large,
+		},
+		{
+			name:     "not accepted",
+			handler:  writeJSON(http.StatusOK, large),
+			wantBody: large,
+		},
+		{
+			name:           "below the threshold",
+			acceptEncoding: "gzip",
+			handler:        writeJSON(http.StatusOK, `{"data":"a"}`),
+			wantBody:       `{"data":"a"}`,
+		},
+		{
+			name:           "error status",
+			acceptEncoding: "gzip",
+			handler:        writeJSON(http.StatusNotFound, large),
+			wantEncoding:   "gzip",
+			wantBody:       large,
+		},
+		{
+			name:           "not modified",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotModified)
+			},
+		},
+		{
+			name:           "already encoded",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Encoding", "br")
+				w.Write([]byte(large))
+			},
+			wantEncoding: "br",
+			wantBody:     large,
+		},
+		{
+			name:           "incompressible type",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "image/png")
+				w.Write([]byte(large))
+			},
+			wantBody: large,
+		},
+		{
+			name:           "written in pieces",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/csv")
+				for range 64 {
+					w.Write([]byte(strings.Repeat("b", 63) + "\n"))
+				}
+			},
+			wantEncoding: "gzip",
+			wantBody:     strings.Repeat(strings.Repeat("b", 63)+"\n", 64),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/participants/12345678/entries", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			Compression(1024)(tt.handler).ServeHTTP(rec, req)
+
+			if got := rec.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Fatalf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", got)
+			}
+
+			body := decodeBody(t, tt.wantEncoding, rec.Body)
+			if body != tt.wantBody {
+				t.Errorf("body = %.40q (%d bytes), want %.40q (%d bytes)", body, len(body), tt.wantBody, len(tt.wantBody))
+			}
+		})
+	}
+}
+
+func TestCompressionFlush(t *testing.T) {
+	handler := Compression(1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Write([]byte(strings.Repeat(`{"key":"k"}`+"\n", 100)))
+		http.NewResponseController(w).Flush()
+		w.Write([]byte(`{"key":"last"}` + "\n"))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/export", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if !rec.Flushed {
+		t.Error("response not flushed")
+	}
+	want := strings.Repeat(`{"key":"k"}`+"\n", 100) + `{"key":"last"}` + "\n"
+	if body := decodeBody(t, "gzip", rec.Body); body != want {
+		t.Errorf("body = %d bytes, want %d", len(body), len(want))
+	}
+}
+
+// writeJSON returns a handler answering with a JSON body and status
+func writeJSON(status int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}
+}
+
+// decodeBody decompresses a recorded body sent with encoding
+func decodeBody(t *testing.T, encoding string, body io.Reader) string {
+	t.Helper()
+
+	var (
+		r   io.Reader = body
+		err error
+	)
+	switch encoding {
+	case "gzip":
+		r, err = gzip.NewReader(body)
+	case "zstd":
+		var dec *zstd.Decoder
+		dec, err = zstd.NewReader(body)
+		if err == nil {
+			defer dec.Close()
+		}
+		r = dec
+	}
+	if err != nil {
+		t.Fatalf("open %s body: %v", encoding, err)
+	}
+
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read %s body: %v", encoding, err)
+	}
+	return string(decoded)
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/httputil"
+)
+
+// CorrelationID settles the request's correlation ID once: the client's X-Correlation-Id, or a
+// generated one. It is stored in the request context for logs and response bodies, stamped on
+// the response header and recorded on the request span.
+func CorrelationID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		correlationID := httputil.NewCorrelationID(r)
+
+		w.Header().Set(httputil.CorrelationIDHeader, correlationID)
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("correlation_id", correlationID))
+
+		serveCopy(next, w, r, r.WithContext(httputil.WithCorrelationID(r.Context(), correlationID)))
+	})
+}
+
+// RequestID gives every request a server-generated ID, distinct from the correlation ID a client
+// may reuse across requests. It is stored in the request context for logs and error bodies,
+// stamped on the X-Request-Id response header and recorded on the request span. A client's
+// X-Request-Id is ignored, so the ID is unique to this server.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := uuid.New().String()
+
+		w.Header().Set(httputil.RequestIDHeader, requestID)
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("request_id", requestID))
+
+		serveCopy(next, w, r, r.WithContext(httputil.WithRequestID(r.Context(), requestID)))
+	})
+}
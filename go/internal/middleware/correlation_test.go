@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+)
+
+func TestCorrelationIDIsGeneratedOnce(t *testing.T) {
+	var seen []string
+	handler := CorrelationID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, httputil.GetCorrelationID(r), httputil.GetCorrelationID(r))
+		httputil.WriteAPIError(w, r, constants.ErrEntryNotFound)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/entries/12345678901", nil))
+
+	var body httputil.APIResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	header := rec.Header().Get(httputil.CorrelationIDHeader)
+	if header == "" {
+		t.Fatal("no correlation ID header")
+	}
+	for _, id := range append(seen, body.CorrelationId) {
+		if id != header {
+			t.Errorf("correlation IDs differ: header %q, got %q", header, id)
+		}
+	}
+}
+
+func TestCorrelationIDKeepsClientID(t *testing.T) {
+	var got string
+	handler := CorrelationID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = httputil.GetCorrelationID(r)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	req.Header.Set(httputil.CorrelationIDHeader, "client-id")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got != "client-id" || rec.Header().Get(httputil.CorrelationIDHeader) != "client-id" {
+		t.Errorf("correlation ID = %q (header %q), want the client's", got, rec.Header().Get(httputil.CorrelationIDHeader))
+	}
+}
+
+func TestRequestIDIsUniquePerRequest(t *testing.T) {
+	handler := CorrelationID(RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httputil.WriteAPIError(w, r, constants.ErrEntryNotFound)
+	})))
+
+	// A client reusing its correlation ID, and sending a request ID of its own, still gets a new ID each time
+	seen := map[string]bool{}
+	for range 2 {
+		req := httptest.NewRequest(http.MethodGet, "/entries/12345678901", nil)
+		req.Header.Set(httputil.CorrelationIDHeader, "client-id")
+		req.Header.Set(httputil.RequestIDHeader, "client-request")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		var body httputil.APIResponse
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		header := rec.Header().Get(httputil.RequestIDHeader)
+		if header == "" || header == "client-request" || body.RequestId != header {
+			t.Errorf("request ID header %q, body %q; want the same server-generated ID", header, body.RequestId)
+		}
+		if body.CorrelationId != "client-id" {
+			t.Errorf("correlation ID = %q, want the client's", body.CorrelationId)
+		}
+		seen[header] = true
+	}
+	if len(seen) != 2 {
+		t.Errorf("request IDs %v, want a different one per request", seen)
+	}
+}
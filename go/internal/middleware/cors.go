@@ -25,16 +25,20 @@ func CORSMiddleware(next http.Handler) http.Handler {
 			"Content-Type",
 			"Authorization",
 			"X-Idempotency-Key",
-			"X-User-Id",
+			"X-Correlation-Id",
 			"Accept",
 			"Origin",
 			"X-Requested-With",
+			// Conditional GET
+			"If-None-Match",
+			"If-Modified-Since",
 			// OpenTelemetry headers
 			"traceparent",
 			"tracestate",
 			"baggage",
 			"sentry-trace",
 		},
+		ExposedHeaders:   []string{"X-Correlation-Id", "X-Request-Id", "ETag", "Last-Modified", "Deprecation", "Sunset", "Link"},
 		AllowCredentials: true,
 	})
 
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/dict-simulator/go/internal/chaos"
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+)
+
+var faultsInjectedTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chaos_faults_injected_total",
+		Help: "Total number of faults injected by the simulator",
+	},
+	[]string{"type", "route"},
+)
+
+// FaultInjection applies the fault rules added via /admin/faults, then those from FAULT_RULES
+// Must run inside the route chain so r.Pattern and the {key} path value are populated.
+// Latency faults are applied first, then the first ERROR or RESET fault ends the request.
+func (m *Manager) FaultInjection(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.PathValue("key")
+		faults := append(m.faults.Triggered(r.Pattern, key), chaos.Trigger(m.settings.Load().FaultRules, r.Pattern, key)...)
+		if len(faults) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		for _, f := range faults {
+			if f.Type != chaos.FaultLatency {
+				continue
+			}
+			faultsInjectedTotal.WithLabelValues(string(f.Type), r.Pattern).Inc()
+
+			select {
+			case <-time.After(f.Latency()):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		for _, f := range faults {
+			switch f.Type {
+			case chaos.FaultError:
+				faultsInjectedTotal.WithLabelValues(string(f.Type), r.Pattern).Inc()
+				httputil.WriteAPIError(w, r, faultError(f.StatusCode))
+				return
+			case chaos.FaultReset:
+				faultsInjectedTotal.WithLabelValues(string(f.Type), r.Pattern).Inc()
+				resetConnection(w)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// faultError builds the error response for an injected 5xx
+func faultError(status int) constants.APIError {
+	apiErr := constants.ErrFaultInjected
+	if status != apiErr.Status {
+		apiErr.Code = constants.CodeInternalError
+		apiErr.Status = status
+	}
+	return apiErr
+}
+
+// resetConnection drops the client connection without writing a response.
+// On HTTP/1.x the socket is hijacked and closed with SO_LINGER=0 so the client sees a TCP RST.
+// Where hijacking isn't possible (e.g. HTTP/2) the handler is aborted instead.
+func resetConnection(w http.ResponseWriter) {
+	conn, _, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		_ = tcpConn.SetLinger(0)
+	}
+	_ = conn.Close()
+}
@@ -2,76 +2,204 @@ package middleware
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
+	"io"
 	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/dict-simulator/go/internal/config"
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/redact"
 )
 
 const IdempotencyKeyHeader = "X-Idempotency-Key"
 
-// responseRecorder captures the response for idempotency storage
+// maxPooledRecorderBuffer bounds the recorder buffers kept for reuse
+const maxPooledRecorderBuffer = 64 << 10
+
+var idempotencyReplaysTotal = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Name: "idempotency_replays_total",
+		Help: "Total number of requests answered with the stored response of an earlier request with the same idempotency key",
+	},
+)
+
+// IdempotencyPolicy says which responses are stored for replay, and for how long
+type IdempotencyPolicy struct {
+	MaxResponseBytes  int              // larger responses are not stored
+	StoreClientErrors bool             // replay 4xx responses instead of letting a retry run again
+	ClientErrorTTL    time.Duration    // how long 4xx responses are replayed when stored
+	Redactor          *redact.Redactor // masks stored responses, and so their replays; nil stores them as they are
+}
+
+// NewIdempotencyPolicy picks the idempotency policy out of a configuration
+func NewIdempotencyPolicy(cfg *config.Config) IdempotencyPolicy {
+	policy := IdempotencyPolicy{
+		MaxResponseBytes:  cfg.IdempotencyMaxResponse,
+		StoreClientErrors: cfg.IdempotencyStore4xx,
+		ClientErrorTTL:    cfg.Idempotency4xxTTL,
+	}
+	if cfg.RedactIdempotency {
+		policy.Redactor = cfg.Redactor
+	}
+	return policy
+}
+
+// ttl returns how long a response with status is replayed, or 0 when it is not stored
+func (p IdempotencyPolicy) ttl(status int) time.Duration {
+	switch {
+	case status >= 200 && status <= 299:
+		return models.IdempotencyTTL
+	case status == http.StatusRequestEntityTooLarge:
+		// The retry is expected to send a smaller body
+		return 0
+	case status >= 400 && status <= 499 && p.StoreClientErrors:
+		return p.ClientErrorTTL
+	default:
+		// 5xx: the failure is ours, and a retry may well succeed
+		return 0
+	}
+}
+
+// recorderBuffers holds the body buffers of finished recorders for reuse
+var recorderBuffers = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// responseRecorder streams the response to the client while capturing a copy for idempotency storage
+// Capture stops, dropping what was kept, once the policy doesn't store the status or the body
+// outgrows its cap: such responses are not stored.
 type responseRecorder struct {
 	http.ResponseWriter
-	statusCode int
-	body       *bytes.Buffer
+	policy      IdempotencyPolicy
+	statusCode  int
+	ttl         time.Duration
+	wroteHeader bool
+	capturing   bool
+	body        *bytes.Buffer
 }
 
-func newResponseRecorder(w http.ResponseWriter) *responseRecorder {
+func newResponseRecorder(w http.ResponseWriter, policy IdempotencyPolicy) *responseRecorder {
+	body := recorderBuffers.Get().(*bytes.Buffer)
+	body.Reset()
 	return &responseRecorder{
 		ResponseWriter: w,
+		policy:         policy,
 		statusCode:     http.StatusOK,
-		body:           &bytes.Buffer{},
+		capturing:      true,
+		body:           body,
 	}
 }
 
+// release returns the body buffer to the pool; the recorder must not be used afterwards
+func (rr *responseRecorder) release() {
+	if rr.body.Cap() <= maxPooledRecorderBuffer {
+		recorderBuffers.Put(rr.body)
+	}
+	rr.body = nil
+}
+
+// stopCapture drops the captured body; the response still reaches the client
+func (rr *responseRecorder) stopCapture() {
+	rr.capturing = false
+	rr.body.Reset()
+}
+
 func (rr *responseRecorder) WriteHeader(code int) {
-	rr.statusCode = code
+	if !rr.wroteHeader {
+		rr.wroteHeader = true
+		rr.statusCode = code
+		// Responses that aren't replayed let a retry run again
+		if rr.ttl = rr.policy.ttl(code); rr.ttl == 0 {
+			rr.stopCapture()
+		}
+	}
 	rr.ResponseWriter.WriteHeader(code)
 }
 
 func (rr *responseRecorder) Write(b []byte) (int, error) {
-	rr.body.Write(b)
+	if !rr.wroteHeader {
+		rr.WriteHeader(http.StatusOK)
+	}
+	if rr.capturing {
+		if rr.body.Len()+len(b) > rr.policy.MaxResponseBytes {
+			rr.stopCapture()
+		} else {
+			rr.body.Write(b)
+		}
+	}
 	return rr.ResponseWriter.Write(b)
 }
 
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rr *responseRecorder) Unwrap() http.ResponseWriter {
+	return rr.ResponseWriter
+}
+
 // Idempotency handles idempotent requests
-func (m *Manager) Idempotency(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		idempotencyKey := r.Header.Get(IdempotencyKeyHeader)
-
-		// If no idempotency key, proceed normally
-		if idempotencyKey == "" {
-			next.ServeHTTP(w, r)
-			return
-		}
+// Responses the policy stores are replayed to later requests with the same key. Other responses
+// are only streamed to the client, and the key is released for a retry. A request arriving while
+// the key's first request is still running is refused with a 409.
+func (m *Manager) Idempotency(policy IdempotencyPolicy) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			idempotencyKey := r.Header.Get(IdempotencyKeyHeader)
 
-		ctx := r.Context()
+			// If no idempotency key, proceed normally
+			if idempotencyKey == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
 
-		// Try to atomically insert a "processing" record to claim this key
-		// This prevents race conditions between concurrent requests
-		claimed, record, err := m.idempotencyRepo.ClaimKey(ctx, idempotencyKey)
-		if err != nil {
-			// On error, proceed with the request
-			next.ServeHTTP(w, r)
-			return
-		}
+			// Try to atomically insert a "processing" record to claim this key
+			// This prevents race conditions between concurrent requests
+			ctx, cancel := m.storeContext(r)
+			claimed, record, err := m.idempotencyRepo.ClaimKey(ctx, idempotencyKey, participantOf(r))
+			cancel()
+			if err != nil {
+				// On error, proceed with the request
+				next.ServeHTTP(w, r)
+				return
+			}
 
-		// If we didn't claim the key, return the existing response
-		if !claimed && record != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(record.StatusCode)
-			w.Write([]byte(record.Response))
-			return
-		}
+			// A claim without a saved response belongs to a request that is still running
+			if !claimed && record != nil && record.StatusCode == 0 {
+				httputil.WriteAPIError(w, r, constants.ErrIdempotencyKeyInUse)
+				return
+			}
 
-		// We claimed the key, process the request
-		recorder := newResponseRecorder(w)
-		next.ServeHTTP(recorder, r)
+			// If we didn't claim the key, return the existing response
+			if !claimed && record != nil {
+				idempotencyReplaysTotal.Inc()
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(record.StatusCode)
+				io.WriteString(w, record.Response)
+				return
+			}
 
-		// Store the response as raw JSON string (fire and forget, but synchronous to avoid data races)
-		responseBody := recorder.body.String()
-		if json.Valid([]byte(responseBody)) {
-			m.idempotencyRepo.Save(context.Background(), idempotencyKey, responseBody, recorder.statusCode)
-		}
-	})
+			// We claimed the key, process the request
+			recorder := newResponseRecorder(w, policy)
+			defer recorder.release()
+			next.ServeHTTP(recorder, r)
+
+			// Store the response as raw JSON string (fire and forget, but synchronous to avoid data races)
+			// The captured bytes are validated in place and copied once, into the stored string.
+			// The key is settled even when the client has gone away, so a retry isn't left waiting on it.
+			ctx, cancel = m.bookkeepingContext(r)
+			defer cancel()
+			if recorder.capturing && json.Valid(recorder.body.Bytes()) {
+				response := recorder.body.String()
+				if policy.Redactor != nil {
+					response = string(policy.Redactor.JSON(recorder.body.Bytes()))
+				}
+				m.idempotencyRepo.Save(ctx, idempotencyKey, response, recorder.statusCode, recorder.ttl)
+				return
+			}
+			m.idempotencyRepo.Release(ctx, idempotencyKey)
+		})
+	}
 }
@@ -0,0 +1,280 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/dict-simulator/go/internal/chaos"
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/ratelimit"
+	"github.com/dict-simulator/go/internal/redact"
+	"github.com/dict-simulator/go/internal/signing"
+	"github.com/dict-simulator/go/internal/testkit"
+)
+
+func TestIdempotencyCountsReplays(t *testing.T) {
+	clk := clock.NewSimulated()
+	m := NewManager(models.NewMemoryIdempotencyRepository(clk), ratelimit.NewMemoryBucket(clk), ratelimit.NewMemoryBanStore(), signing.NewMemoryNonceStore(), chaos.NewInjector(clk), Settings{})
+
+	calls := 0
+	handler := m.Idempotency(IdempotencyPolicy{MaxResponseBytes: 1 << 10})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+
+	before := testutil.ToFloat64(idempotencyReplaysTotal)
+	for range 3 {
+		req := httptest.NewRequest(http.MethodPost, "/entries", nil)
+		req.Header.Set(IdempotencyKeyHeader, "replay-metric")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want the stored 201", rec.Code)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want once", calls)
+	}
+	if got := testutil.ToFloat64(idempotencyReplaysTotal) - before; got != 2 {
+		t.Errorf("idempotency_replays_total grew by %v, want 2", got)
+	}
+}
+
+func TestIdempotencyPolicy(t *testing.T) {
+	capped := IdempotencyPolicy{MaxResponseBytes: 16}
+	storing := IdempotencyPolicy{MaxResponseBytes: 64, StoreClientErrors: true, ClientErrorTTL: time.Minute}
+
+	tests := []struct {
+		name       string
+		policy     IdempotencyPolicy
+		status     int
+		body       string
+		wantStored bool
+	}{
+		{"created", capped, http.StatusCreated, `{"ok":true}`, true},
+		{"implicit 200", capped, 0, `{"ok":true}`, true},
+		{"at the cap", capped, http.StatusCreated, `"` + strings.Repeat("a", 14) + `"`, true},
+		{"over the cap", capped, http.StatusCreated, `"` + strings.Repeat("a", 15) + `"`, false},
+		{"not JSON", capped, http.StatusCreated, `ok`, false},
+		{"conflict", capped, http.StatusConflict, `{"error":"KEY_ALREADY_EXISTS"}`, false},
+		{"conflict with client errors stored", storing, http.StatusConflict, `{"error":"KEY_ALREADY_EXISTS"}`, true},
+		{"payload too large with client errors stored", storing, http.StatusRequestEntityTooLarge, `{"error":"PAYLOAD_TOO_LARGE"}`, false},
+		{"server error with client errors stored", storing, http.StatusInternalServerError, `{"error":"INTERNAL_ERROR"}`, false},
+		{"unavailable with client errors stored", storing, http.StatusServiceUnavailable, `{"error":"SERVICE_UNAVAILABLE"}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := clock.NewSimulated()
+			repo := models.NewMemoryIdempotencyRepository(clk)
+			m := NewManager(repo, ratelimit.NewMemoryBucket(clk), ratelimit.NewMemoryBanStore(), signing.NewMemoryNonceStore(), chaos.NewInjector(clk), Settings{})
+
+			calls := 0
+			handler := m.Idempotency(tt.policy)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				// Two writes, so the cap is checked against the running total
+				half := len(tt.body) / 2
+				w.Write([]byte(tt.body[:half]))
+				w.Write([]byte(tt.body[half:]))
+			}))
+
+			for range 2 {
+				req := httptest.NewRequest(http.MethodPost, "/entries", nil)
+				req.Header.Set(IdempotencyKeyHeader, "k")
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				// Uncaptured responses still reach the client whole
+				if rec.Body.String() != tt.body {
+					t.Fatalf("body = %q, want %q", rec.Body.String(), tt.body)
+				}
+			}
+
+			record, _ := repo.FindByKey(context.Background(), "k")
+			if stored := record != nil; stored != tt.wantStored {
+				t.Errorf("stored = %v, want %v", stored, tt.wantStored)
+			}
+			// A released key lets the retry run again instead of replaying
+			wantCalls := 2
+			if tt.wantStored {
+				wantCalls = 1
+			}
+			if calls != wantCalls {
+				t.Errorf("handler ran %d times, want %d", calls, wantCalls)
+			}
+		})
+	}
+}
+
+func TestIdempotencyClientErrorTTL(t *testing.T) {
+	clk := clock.NewSimulated()
+	repo := models.NewMemoryIdempotencyRepository(clk)
+	m := NewManager(repo, ratelimit.NewMemoryBucket(clk), ratelimit.NewMemoryBanStore(), signing.NewMemoryNonceStore(), chaos.NewInjector(clk), Settings{})
+	policy := IdempotencyPolicy{MaxResponseBytes: 1 << 10, StoreClientErrors: true, ClientErrorTTL: time.Minute}
+
+	status := http.StatusConflict
+	handler := m.Idempotency(policy)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{}`))
+	}))
+	send := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/entries", nil)
+		req.Header.Set(IdempotencyKeyHeader, "k")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	send()
+	status = http.StatusCreated
+	if got := send(); got != http.StatusConflict {
+		t.Fatalf("status within the TTL = %d, want the replayed 409", got)
+	}
+
+	// Past the client error TTL the key runs again, and a success is kept for the full day
+	clk.Advance(time.Minute)
+	if got := send(); got != http.StatusCreated {
+		t.Fatalf("status after the TTL = %d, want 201", got)
+	}
+	clk.Advance(time.Hour)
+	record, _ := repo.FindByKey(context.Background(), "k")
+	if record == nil || record.StatusCode != http.StatusCreated {
+		t.Errorf("record an hour later = %+v, want the stored 201", record)
+	}
+}
+
+func TestIdempotencyRedactsStoredResponses(t *testing.T) {
+	clk := clock.NewSimulated()
+	repo := models.NewMemoryIdempotencyRepository(clk)
+	m := NewManager(repo, ratelimit.NewMemoryBucket(clk), ratelimit.NewMemoryBanStore(), signing.NewMemoryNonceStore(), chaos.NewInjector(clk), Settings{})
+	policy := IdempotencyPolicy{MaxResponseBytes: 1 << 10, Redactor: redact.New(redact.DefaultFields)}
+
+	body := `{"data":{"key":"52998224725","owner":{"name":"John Doe"}}}`
+	handler := m.Idempotency(policy)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(body))
+	}))
+	send := func() string {
+		req := httptest.NewRequest(http.MethodPost, "/entries", nil)
+		req.Header.Set(IdempotencyKeyHeader, "redacted")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Body.String()
+	}
+
+	if got := send(); got != body {
+		t.Errorf("first response = %s, want it unmasked", got)
+	}
+	want := `{"data":{"key":"***982247**","owner":{"name":"John Doe"}}}`
+	if got := send(); got != want {
+		t.Errorf("replayed response = %s, want %s", got, want)
+	}
+}
+
+// deadlineRecorder records the context the response is saved with
+type deadlineRecorder struct {
+	models.IdempotencyRepository
+	saveErr      error
+	saveDeadline bool
+}
+
+func (d *deadlineRecorder) Save(ctx context.Context, key, response string, status int, ttl time.Duration) error {
+	d.saveErr = ctx.Err()
+	_, d.saveDeadline = ctx.Deadline()
+	return d.IdempotencyRepository.Save(ctx, key, response, status, ttl)
+}
+
+func TestIdempotencySavesAfterClientLeaves(t *testing.T) {
+	clk := clock.NewSimulated()
+	repo := &deadlineRecorder{IdempotencyRepository: models.NewMemoryIdempotencyRepository(clk)}
+	m := NewManager(repo, ratelimit.NewMemoryBucket(clk), ratelimit.NewMemoryBanStore(), signing.NewMemoryNonceStore(), chaos.NewInjector(clk), Settings{StoreTimeout: time.Second})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	handler := m.Idempotency(IdempotencyPolicy{MaxResponseBytes: 1 << 10})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{}`))
+		cancel()
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/entries", nil).WithContext(ctx)
+	req.Header.Set(IdempotencyKeyHeader, "k")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if repo.saveErr != nil || !repo.saveDeadline {
+		t.Errorf("saved with context error %v and deadline %v, want a live context bounded by the store timeout", repo.saveErr, repo.saveDeadline)
+	}
+}
+
+func TestIdempotencyRefusesWhileInFlight(t *testing.T) {
+	clk := clock.NewSimulated()
+	m := NewManager(models.NewMemoryIdempotencyRepository(clk), ratelimit.NewMemoryBucket(clk), ratelimit.NewMemoryBanStore(), signing.NewMemoryNonceStore(), chaos.NewInjector(clk), Settings{})
+
+	entered, release := make(chan struct{}), make(chan struct{})
+	var calls atomic.Int32
+	handler := m.Idempotency(IdempotencyPolicy{MaxResponseBytes: 1 << 10})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			close(entered)
+			<-release
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	newRequest := func(int) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/entries", nil)
+		req.Header.Set(IdempotencyKeyHeader, "in-flight")
+		return req
+	}
+
+	first := make(chan testkit.Results)
+	go func() { first <- testkit.Run(testkit.Handler(handler), testkit.Repeat(1, newRequest)) }()
+	<-entered
+
+	// The duplicates arrive while the first request holds the key
+	duplicates := testkit.Run(testkit.Handler(handler), testkit.Repeat(10, newRequest))
+	testkit.Check(t, duplicates, testkit.Count(http.StatusConflict, 10))
+	if body := string(duplicates[0].Body); !strings.Contains(body, constants.CodeIdempotencyKeyInUse) {
+		t.Errorf("duplicate body = %s, want %s", body, constants.CodeIdempotencyKeyInUse)
+	}
+
+	close(release)
+	testkit.Check(t, <-first, testkit.ExactlyOne(http.StatusCreated))
+
+	// Once the first request is done, its response is replayed
+	replays := testkit.Run(testkit.Handler(handler), testkit.Repeat(10, newRequest))
+	testkit.Check(t, replays, testkit.Count(http.StatusCreated, 10), testkit.SameResponse())
+	if got := calls.Load(); got != 1 {
+		t.Errorf("handler ran %d times, want once", got)
+	}
+}
+
+func BenchmarkIdempotency(b *testing.B) {
+	clk := clock.NewSimulated()
+	m := NewManager(models.NewMemoryIdempotencyRepository(clk), ratelimit.NewMemoryBucket(clk), ratelimit.NewMemoryBanStore(), signing.NewMemoryNonceStore(), chaos.NewInjector(clk), Settings{})
+	body := []byte(`{"responseTime":"2024-01-15T10:30:00Z","correlationId":"550e8400-e29b-41d4-a716-446655440000","code":"ENTRY_CREATED","data":{"key":"k@example.com"}}`)
+	handler := m.Idempotency(IdempotencyPolicy{MaxResponseBytes: 1 << 10})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/entries", nil)
+	b.ReportAllocs()
+	i := 0
+	for b.Loop() {
+		req.Header.Set(IdempotencyKeyHeader, strconv.Itoa(i))
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		i++
+	}
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// Identity is who an authenticated request speaks for, as established by AuthMiddleware
+// User tokens fill UserID and OAuth client tokens ClientID. The Participant of a client is the one
+// it was registered for; users speak for the participant they name in X-Participant-Id.
+type Identity struct {
+	UserID      string
+	ClientID    string
+	Participant string
+	Scopes      []models.Scope
+}
+
+// ActsFor reports whether the identity may act for participant, as named in a request's body, path
+// or query. Identities that speak for a participant only act for it; users speaking for none
+// (without X-Participant-Id) are not restricted.
+func (id Identity) ActsFor(participant string) bool {
+	return id.Participant == "" || id.Participant == participant
+}
+
+// AuthorizeParticipant answers 403 and returns false unless the request's identity acts for participant
+// Handlers call it for every participant a request names, since AuthMiddleware only sees headers.
+func AuthorizeParticipant(w http.ResponseWriter, r *http.Request, participant string) bool {
+	if id, _ := IdentityFromContext(r.Context()); id.ActsFor(participant) {
+		return true
+	}
+
+	span := trace.SpanFromContext(r.Context())
+	span.SetStatus(codes.Error, "Participant not allowed")
+	span.SetAttributes(attribute.String("error.type", "forbidden"))
+	httputil.WriteAPIError(w, r, constants.ErrParticipantNotAllowed)
+	return false
+}
+
+// identityKey is the context key under which the request's Identity is stored
+type identityKey struct{}
+
+// WithIdentity returns a copy of ctx carrying the identity
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// IdentityFromContext returns the identity of the request, or false if it was not authenticated
+// Unlike request headers, it can only have been set by middleware.
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
+
+// participantOf returns the participant an authenticated request speaks for, or "" for anonymous requests
+func participantOf(r *http.Request) string {
+	id, _ := IdentityFromContext(r.Context())
+	return id.Participant
+}
+
+// identitySlotKey is the context key of the slot AccessLog and UsageReport read the identity from once
+// the request is served
+type identitySlotKey struct{}
+
+// withIdentitySlot returns a copy of ctx with an empty slot that setIdentity fills
+// AccessLog and UsageReport wrap AuthMiddleware, so they can't see the context the identity is put in.
+func withIdentitySlot(ctx context.Context) (context.Context, *Identity) {
+	slot := &Identity{}
+	return context.WithValue(ctx, identitySlotKey{}, slot), slot
+}
+
+// setIdentity returns r carrying the identity, which is also handed to the access log
+func setIdentity(r *http.Request, id Identity) *http.Request {
+	if slot, ok := r.Context().Value(identitySlotKey{}).(*Identity); ok {
+		*slot = id
+	}
+	return r.WithContext(WithIdentity(r.Context(), id))
+}
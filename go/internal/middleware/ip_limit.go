@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/logger"
+)
+
+var ipRateLimitChecksTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "ip_rate_limit_checks_total",
+		Help: "Total number of per-IP rate limit checks by result (allowed, banned, blocked while banned, error)",
+	},
+	[]string{"result"},
+)
+
+// IPRateLimit limits requests per client address before any credentials are checked, so the
+// auth routes can't be used to brute-force passwords. Its buckets are separate from the DICT
+// policies'. An address that empties its bucket is banned for the ban duration, and every
+// request it sends meanwhile is refused with a 429 and a Retry-After.
+// Storage errors let the request through, like the DICT rate limits.
+func (m *Manager) IPRateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		settings := m.settings.Load()
+		if !settings.IPRateLimitEnabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ip := httputil.ClientIP(r)
+		policy := settings.IPRateLimit
+
+		ctx, cancel := m.storeContext(r)
+		defer cancel()
+
+		banned, err := m.bans.BannedFor(ctx, ip)
+		if err != nil {
+			ipRateLimitChecksTotal.WithLabelValues("error").Inc()
+			next.ServeHTTP(w, r)
+			return
+		}
+		if banned > 0 {
+			ipRateLimitChecksTotal.WithLabelValues("blocked").Inc()
+			rejectBanned(w, r, banned)
+			return
+		}
+
+		state, err := m.rateLimiter.Check(ctx, policy, ip)
+		if err != nil {
+			ipRateLimitChecksTotal.WithLabelValues("error").Inc()
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !state.Allowed {
+			// A client that hangs up as soon as it is refused must still be banned
+			banCtx, cancelBan := m.bookkeepingContext(r)
+			defer cancelBan()
+			if err := m.bans.Ban(banCtx, ip, settings.IPBanDuration); err != nil {
+				ipRateLimitChecksTotal.WithLabelValues("error").Inc()
+				writeRateLimitError(w, r)
+				return
+			}
+			ipRateLimitChecksTotal.WithLabelValues("banned").Inc()
+			logger.Warn("Client address banned after exhausting its rate limit",
+				zap.String("ip", ip),
+				zap.String("path", r.URL.Path),
+				zap.Duration("duration", settings.IPBanDuration),
+			)
+			rejectBanned(w, r, settings.IPBanDuration)
+			return
+		}
+		ipRateLimitChecksTotal.WithLabelValues("allowed").Inc()
+
+		cancel()
+
+		capture := &responseCapture{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(capture, r)
+
+		ctx, cancel = m.bookkeepingContext(r)
+		defer cancel()
+		m.rateLimiter.Consume(ctx, policy, ip, capture.statusCode)
+	})
+}
+
+// rejectBanned refuses a request from a banned address, saying when to retry in whole seconds
+func rejectBanned(w http.ResponseWriter, r *http.Request, remaining time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(remaining.Seconds()))))
+	httputil.WriteAPIError(w, r, constants.ErrIPBanned)
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dict-simulator/go/internal/chaos"
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/ratelimit"
+	"github.com/dict-simulator/go/internal/signing"
+)
+
+func TestIPRateLimitBansExhaustedAddresses(t *testing.T) {
+	clk := clock.NewSimulated()
+	m := NewManager(models.NewMemoryIdempotencyRepository(clk), ratelimit.NewMemoryBucket(clk), ratelimit.NewMemoryBanStore(), signing.NewMemoryNonceStore(), chaos.NewInjector(clk), Settings{
+		IPRateLimitEnabled: true,
+		IPRateLimit:        ratelimit.IPPolicy(1, 2),
+		IPBanDuration:      10 * time.Minute,
+	})
+	handler := m.IPRateLimit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	serve := func(remoteAddr string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+		req.RemoteAddr = remoteAddr
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Failed logins spend the bucket like any other response
+	for i := range 2 {
+		if rec := serve("198.51.100.1:40000"); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("attempt %d answered %d, want it to reach the handler", i+1, rec.Code)
+		}
+	}
+
+	rec := serve("198.51.100.1:40001")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "600" {
+		t.Fatalf("third attempt answered %d retrying after %q, want 429 and the 10 minute ban", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	// The bucket refills, but the ban holds
+	clk.Advance(5 * time.Minute)
+	if rec := serve("198.51.100.1:40002"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("attempt during the ban answered %d, want 429", rec.Code)
+	}
+
+	if rec := serve("198.51.100.2:40000"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("another address answered %d, want its own bucket", rec.Code)
+	}
+}
+
+func TestIPRateLimitDisabled(t *testing.T) {
+	clk := clock.NewSimulated()
+	m := NewManager(models.NewMemoryIdempotencyRepository(clk), ratelimit.NewMemoryBucket(clk), ratelimit.NewMemoryBanStore(), signing.NewMemoryNonceStore(), chaos.NewInjector(clk), Settings{
+		IPRateLimit:   ratelimit.IPPolicy(1, 1),
+		IPBanDuration: time.Minute,
+	})
+	handler := m.IPRateLimit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for range 3 {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/auth/login", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("answered %d with the IP limit disabled", rec.Code)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LatencyProfile delays responses according to the configured per-route latency profiles
+// Must run inside the route chain so r.Pattern is populated.
+// Routes without a profile (and no "*" default) are passed through untouched.
+func (m *Manager) LatencyProfile(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		profile, ok := m.settings.Load().LatencyProfiles.For(r.Pattern)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		delay := profile.Sample()
+		trace.SpanFromContext(r.Context()).SetAttributes(
+			attribute.Int64("latency.simulated_ms", delay.Milliseconds()),
+		)
+
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -1,56 +1,132 @@
 package middleware
 
 import (
+	"io"
+	"math/rand/v2"
 	"net/http"
 	"time"
 
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
+	"github.com/dict-simulator/go/internal/httputil"
 	"github.com/dict-simulator/go/internal/logger"
 )
 
-// LoggingMiddleware logs incoming requests with Zap and includes trace context
-func LoggingMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-
-		// Wrap response writer to capture status code
-		wrapped := &loggingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
-
-		next.ServeHTTP(wrapped, r)
-
-		duration := time.Since(start)
-
-		// Build log fields
-		fields := []zap.Field{
-			zap.String("method", r.Method),
-			zap.String("path", r.URL.Path),
-			zap.Int("status", wrapped.statusCode),
-			zap.Duration("duration", duration),
-			zap.String("remote_addr", r.RemoteAddr),
-		}
-
-		// Add trace context if available
-		span := trace.SpanFromContext(r.Context())
-		if span.SpanContext().IsValid() {
-			fields = append(fields,
-				zap.String("trace_id", span.SpanContext().TraceID().String()),
-				zap.String("span_id", span.SpanContext().SpanID().String()),
-			)
-		}
-
-		logger.Log.Info("request completed", fields...)
-	})
+// AccessLog logs one "request completed" line per request with Zap, including trace context
+// Only sampleRate (0 to 1) of the requests are logged, so high-QPS load tests don't drown in
+// access logs; server errors are always logged. Sampled lines carry the rate in sample_rate.
+func AccessLog(sampleRate float64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			// Wrap the body and response writer to count bytes and capture the status code
+			body := &countingReader{ReadCloser: r.Body}
+			if r.Body != nil {
+				r.Body = body
+			}
+			wrapped := &loggingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			ctx, identity := withIdentitySlot(r.Context())
+			inner := r.WithContext(ctx)
+
+			serveCopy(next, wrapped, r, inner)
+
+			duration := time.Since(start)
+
+			if wrapped.statusCode < http.StatusInternalServerError && sampleRate < 1 && rand.Float64() >= sampleRate {
+				return
+			}
+
+			// Build log fields
+			fields := []zap.Field{
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.Int("status", wrapped.statusCode),
+				zap.Duration("duration", duration),
+				zap.Int64("bytes_in", body.n),
+				zap.Int64("bytes_out", wrapped.bytes),
+				zap.String("remote_addr", r.RemoteAddr),
+				zap.String("client_ip", httputil.ClientIP(r)),
+			}
+
+			// The ServeMux sets the pattern on the request once it has matched a route
+			if inner.Pattern != "" {
+				fields = append(fields, zap.String("route", inner.Pattern))
+			}
+
+			if correlationID := httputil.CorrelationIDFromContext(r.Context()); correlationID != "" {
+				fields = append(fields, zap.String("correlation_id", correlationID))
+			}
+			if requestID := httputil.RequestIDFromContext(r.Context()); requestID != "" {
+				fields = append(fields, zap.String("request_id", requestID))
+			}
+
+			// Filled in by AuthMiddleware on authenticated routes
+			if identity.UserID != "" {
+				fields = append(fields, zap.String("user_id", identity.UserID))
+			}
+			if identity.ClientID != "" {
+				fields = append(fields, zap.String("client_id", identity.ClientID))
+			}
+			if identity.Participant != "" {
+				fields = append(fields, zap.String("participant_id", identity.Participant))
+			}
+
+			// Identify mTLS clients by their certificate
+			if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+				fields = append(fields, zap.String("tls_client", r.TLS.PeerCertificates[0].Subject.String()))
+			}
+
+			// Add trace context if available
+			span := trace.SpanFromContext(r.Context())
+			if span.SpanContext().IsValid() {
+				fields = append(fields,
+					zap.String("trace_id", span.SpanContext().TraceID().String()),
+					zap.String("span_id", span.SpanContext().SpanID().String()),
+				)
+			}
+
+			if sampleRate < 1 {
+				fields = append(fields, zap.Float64("sample_rate", sampleRate))
+			}
+
+			logger.Info("request completed", fields...)
+		})
+	}
 }
 
-// loggingResponseWriter wraps http.ResponseWriter to capture the status code
+// loggingResponseWriter wraps http.ResponseWriter to capture the status code and bytes written
 type loggingResponseWriter struct {
 	http.ResponseWriter
 	statusCode int
+	bytes      int64
 }
 
 func (rw *loggingResponseWriter) WriteHeader(code int) {
 	rw.statusCode = code
 	rw.ResponseWriter.WriteHeader(code)
 }
+
+func (rw *loggingResponseWriter) Write(b []byte) (int, error) {
+	n, err := rw.ResponseWriter.Write(b)
+	rw.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rw *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// countingReader counts the request body bytes the handlers actually read
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.ReadCloser.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
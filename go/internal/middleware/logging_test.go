@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/logger"
+)
+
+func observeLogs(t *testing.T) *observer.ObservedLogs {
+	t.Helper()
+	core, logs := observer.New(zap.InfoLevel)
+	previous := logger.Log
+	logger.Log = zap.New(core)
+	t.Cleanup(func() { logger.Log = previous })
+	return logs
+}
+
+func TestAccessLogFields(t *testing.T) {
+	logs := observeLogs(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /entries", func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		setIdentity(r, Identity{UserID: "user-1", Participant: "12345678"})
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"ok":true}`))
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/entries", strings.NewReader(`{"key":"k"}`))
+	req = req.WithContext(httputil.WithRequestID(req.Context(), "request-1"))
+	AccessLog(1)(mux).ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := logs.FilterMessage("request completed").All()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d access lines, want 1", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	want := map[string]any{
+		"method":         "POST",
+		"route":          "POST /entries",
+		"status":         int64(http.StatusCreated),
+		"bytes_in":       int64(11),
+		"bytes_out":      int64(11),
+		"user_id":        "user-1",
+		"participant_id": "12345678",
+		"request_id":     "request-1",
+	}
+	for key, value := range want {
+		if fields[key] != value {
+			t.Errorf("%s = %v, want %v", key, fields[key], value)
+		}
+	}
+	if _, ok := fields["sample_rate"]; ok {
+		t.Error("unsampled line carries sample_rate")
+	}
+}
+
+func TestAccessLogSampling(t *testing.T) {
+	logs := observeLogs(t)
+
+	status := http.StatusOK
+	handler := AccessLog(0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+
+	for range 10 {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	}
+	if n := logs.Len(); n != 0 {
+		t.Fatalf("logged %d successful requests at sample rate 0, want none", n)
+	}
+
+	status = http.StatusServiceUnavailable
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d server errors, want 1", len(entries))
+	}
+	if rate := entries[0].ContextMap()["sample_rate"]; rate != float64(0) {
+		t.Errorf("sample_rate = %v, want 0", rate)
+	}
+}
@@ -1,20 +1,111 @@
 package middleware
 
 import (
+	"context"
+	"net/http"
+	"net/netip"
+	"sync/atomic"
+	"time"
+
+	"github.com/dict-simulator/go/internal/chaos"
+	"github.com/dict-simulator/go/internal/config"
+	"github.com/dict-simulator/go/internal/latency"
 	"github.com/dict-simulator/go/internal/models"
 	"github.com/dict-simulator/go/internal/ratelimit"
+	"github.com/dict-simulator/go/internal/signing"
 )
 
+// Settings are the parts of the configuration the middlewares pick up without a restart
+// Apply swaps them as a whole, so a middleware never sees a mix of old and new settings.
+type Settings struct {
+	RateLimitEnabled bool
+	LatencyProfiles  latency.Profiles
+	FaultRules       []chaos.Fault // applied after the rules added through /admin/faults
+
+	// Request signing; secrets can be rotated with a reload
+	SigningEnabled bool
+	SigningSecrets signing.Secrets
+	SigningMaxSkew time.Duration
+	// How long a participant's correlation IDs are remembered on signed requests; zero doesn't track them
+	SigningCorrelationWindow time.Duration
+
+	// Per-IP limit applied before authentication; an address that empties its bucket is banned
+	IPRateLimitEnabled bool
+	IPRateLimit        ratelimit.Policy
+	IPBanDuration      time.Duration
+	TrustedProxies     []netip.Prefix // peers whose X-Forwarded-For and X-Real-IP name the client
+
+	// Deadline of each idempotency, rate limit, ban and nonce store call; zero leaves them unbounded
+	StoreTimeout time.Duration
+}
+
+// NewSettings picks the hot-reloadable settings out of a configuration
+func NewSettings(cfg *config.Config) Settings {
+	return Settings{
+		RateLimitEnabled:         cfg.RateLimitEnabled,
+		LatencyProfiles:          cfg.LatencyProfiles,
+		FaultRules:               cfg.FaultRules,
+		SigningEnabled:           cfg.RequestSigningEnabled,
+		SigningSecrets:           cfg.RequestSigningSecrets,
+		SigningMaxSkew:           cfg.RequestSigningMaxSkew,
+		SigningCorrelationWindow: cfg.RequestCorrelationWindow,
+
+		IPRateLimitEnabled: cfg.IPRateLimitEnabled,
+		IPRateLimit:        ratelimit.IPPolicy(cfg.IPRateLimitPerMinute, cfg.IPRateLimitBurst),
+		IPBanDuration:      cfg.IPBanDuration,
+		TrustedProxies:     cfg.TrustedProxies,
+
+		StoreTimeout: cfg.StoreTimeout,
+	}
+}
+
 type Manager struct {
-	idempotencyRepo  *models.IdempotencyRepository
-	rateLimiter      *ratelimit.Bucket
-	rateLimitEnabled bool
+	idempotencyRepo models.IdempotencyRepository
+	rateLimiter     ratelimit.Limiter
+	bans            ratelimit.BanStore
+	nonces          signing.NonceStore
+	faults          *chaos.Injector
+	settings        atomic.Pointer[Settings]
+}
+
+func NewManager(idempotencyRepo models.IdempotencyRepository, rateLimiter ratelimit.Limiter, bans ratelimit.BanStore, nonces signing.NonceStore, faults *chaos.Injector, settings Settings) *Manager {
+	m := &Manager{
+		idempotencyRepo: idempotencyRepo,
+		rateLimiter:     rateLimiter,
+		bans:            bans,
+		nonces:          nonces,
+		faults:          faults,
+	}
+	m.settings.Store(&settings)
+	return m
+}
+
+// Settings returns the settings in effect
+func (m *Manager) Settings() Settings {
+	return *m.settings.Load()
+}
+
+// Apply replaces the settings; requests already past a middleware keep the settings it read
+func (m *Manager) Apply(settings Settings) {
+	m.settings.Store(&settings)
+}
+
+// storeContext bounds one store call made while serving r by the store timeout
+// It ends with the request, so a client that goes away cancels the call.
+func (m *Manager) storeContext(r *http.Request) (context.Context, context.CancelFunc) {
+	return withStoreTimeout(r.Context(), m.settings.Load().StoreTimeout)
+}
+
+// bookkeepingContext bounds a store call made after the response, such as saving it for replay
+// or charging the rate limit, by the store timeout alone: the call still runs when the client
+// has gone away, since the work it accounts for is done.
+func (m *Manager) bookkeepingContext(r *http.Request) (context.Context, context.CancelFunc) {
+	return withStoreTimeout(context.WithoutCancel(r.Context()), m.settings.Load().StoreTimeout)
 }
 
-func NewManager(idempotencyRepo *models.IdempotencyRepository, rateLimiter *ratelimit.Bucket, rateLimitEnabled bool) *Manager {
-	return &Manager{
-		idempotencyRepo:  idempotencyRepo,
-		rateLimiter:      rateLimiter,
-		rateLimitEnabled: rateLimitEnabled,
+func withStoreTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
 	}
+	return context.WithTimeout(ctx, timeout)
 }
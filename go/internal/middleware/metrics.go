@@ -2,12 +2,14 @@ package middleware
 
 import (
 	"net/http"
-	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -40,45 +42,64 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Path labels of requests that are not labelled with their route
+const (
+	unmatchedPath = "unmatched" // no route matched, e.g. a scanner probing random paths
+	otherPath     = "other"     // the route is left out of the allowlist
+)
+
 // MetricsMiddleware records Prometheus metrics for each request
-func MetricsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
+// Requests are labelled with the path of the route they matched ("/entries/{key}"), never the raw
+// path, so load tests querying millions of keys add no series. With routes set, only those route
+// patterns ("GET /entries/{key}") get a label of their own; the rest share "other".
+// Must run inside otelhttp so duration observations can carry a trace_id exemplar.
+func MetricsMiddleware(routes []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
 
-		// Wrap response writer to capture status code
-		wrapped := &responseWriter{
-			ResponseWriter: w,
-			statusCode:     http.StatusOK,
-		}
+			// Wrap response writer to capture status code
+			wrapped := &responseWriter{
+				ResponseWriter: w,
+				statusCode:     http.StatusOK,
+			}
 
-		next.ServeHTTP(wrapped, r)
+			next.ServeHTTP(wrapped, r)
 
-		duration := time.Since(start).Seconds()
-		path := normalizePath(r.URL.Path)
-		status := strconv.Itoa(wrapped.statusCode)
+			duration := time.Since(start).Seconds()
+			path := routePath(r.Pattern, routes)
+			status := strconv.Itoa(wrapped.statusCode)
 
-		httpRequestsTotal.WithLabelValues(r.Method, path, status).Inc()
-		httpRequestDuration.WithLabelValues(r.Method, path, status).Observe(duration)
-	})
-}
+			httpRequestsTotal.WithLabelValues(r.Method, path, status).Inc()
 
-// Regex patterns for path normalization
-var (
-	uuidPattern     = regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
-	cpfPattern      = regexp.MustCompile(`/\d{11}\b`)
-	objectIdPattern = regexp.MustCompile(`[0-9a-f]{24}`)
-	numericPattern  = regexp.MustCompile(`/\d+\b`)
-)
+			// Link the observation to its trace, so a slow bucket leads to an example request.
+			// Unsampled traces never reach the tracing backend, so they make no exemplars.
+			observer := httpRequestDuration.WithLabelValues(r.Method, path, status)
+			if sc := trace.SpanContextFromContext(r.Context()); sc.IsSampled() {
+				observer.(prometheus.ExemplarObserver).ObserveWithExemplar(duration, prometheus.Labels{"trace_id": sc.TraceID().String()})
+			} else {
+				observer.Observe(duration)
+			}
+		})
+	}
+}
 
-// normalizePath replaces dynamic path segments to avoid high cardinality
-func normalizePath(path string) string {
-	// Replace UUIDs
-	path = uuidPattern.ReplaceAllString(path, ":id")
-	// Replace CPF patterns (11 digits)
-	path = cpfPattern.ReplaceAllString(path, "/:key")
-	// Replace MongoDB ObjectIds
-	path = objectIdPattern.ReplaceAllString(path, ":id")
-	// Replace any remaining numeric IDs
-	path = numericPattern.ReplaceAllString(path, "/:id")
-	return path
+// routePath returns the path label of a request that matched pattern ("" when none did)
+// The method is labelled on its own, so only the pattern's path is kept.
+func routePath(pattern string, routes []string) string {
+	if pattern == "" {
+		return unmatchedPath
+	}
+	if len(routes) > 0 && !slices.Contains(routes, pattern) {
+		return otherPath
+	}
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		return path
+	}
+	return pattern
 }
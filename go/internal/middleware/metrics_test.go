@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// durationExemplars returns the trace IDs of the exemplars on the duration histogram for a path
+func durationExemplars(t *testing.T, path string) []string {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+
+	var traceIDs []string
+	for _, family := range families {
+		if family.GetName() != "http_request_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			if !hasLabel(metric, "path", path) {
+				continue
+			}
+			for _, bucket := range metric.GetHistogram().GetBucket() {
+				for _, label := range bucket.GetExemplar().GetLabel() {
+					traceIDs = append(traceIDs, label.GetValue())
+				}
+			}
+		}
+	}
+	return traceIDs
+}
+
+func hasLabel(metric *dto.Metric, name, value string) bool {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == name && label.GetValue() == value {
+			return true
+		}
+	}
+	return false
+}
+
+// metricsMux serves every path under the metrics middleware, with routes as its allowlist
+func metricsMux(routes []string, patterns ...string) http.Handler {
+	mux := http.NewServeMux()
+	for _, pattern := range patterns {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {})
+	}
+	return MetricsMiddleware(routes)(mux)
+}
+
+// requestCount returns the http_requests_total count of GET requests labelled with path
+func requestCount(path string) float64 {
+	return testutil.ToFloat64(httpRequestsTotal.WithLabelValues(http.MethodGet, path, "200"))
+}
+
+func TestMetricsMiddlewareExemplars(t *testing.T) {
+	handler := metricsMux(nil, "GET /exemplar-unsampled", "GET /exemplar-sampled")
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/exemplar-unsampled", nil))
+	if got := durationExemplars(t, "/exemplar-unsampled"); len(got) != 0 {
+		t.Errorf("exemplars without a trace = %v, want none", got)
+	}
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()))
+	ctx, span := tp.Tracer("test").Start(t.Context(), "request")
+	defer span.End()
+
+	req := httptest.NewRequest(http.MethodGet, "/exemplar-sampled", nil).WithContext(ctx)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	got := durationExemplars(t, "/exemplar-sampled")
+	if len(got) != 1 || got[0] != span.SpanContext().TraceID().String() {
+		t.Errorf("exemplars = %v, want the trace ID %s", got, span.SpanContext().TraceID())
+	}
+}
+
+func TestMetricsMiddlewareLabelsRoutes(t *testing.T) {
+	handler := metricsMux(nil, "GET /labels/{key}")
+	for _, key := range []string{"11144477735", "user@example.com", "e0b8d4a2-5c1f-4c4e-9f3a-2b7d6c8e1f00"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/labels/"+key, nil))
+	}
+	if got := requestCount("/labels/{key}"); got != 3 {
+		t.Errorf("requests labelled /labels/{key} = %v, want all 3 keys counted under the route", got)
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/labels", nil))
+	if got := testutil.ToFloat64(httpRequestsTotal.WithLabelValues(http.MethodGet, unmatchedPath, "404")); got == 0 {
+		t.Error("a request matching no route is not labelled unmatched")
+	}
+}
+
+func TestMetricsMiddlewareAllowlist(t *testing.T) {
+	handler := metricsMux([]string{"GET /allowed/{key}"}, "GET /allowed/{key}", "GET /unlisted/{key}")
+	before := requestCount(otherPath)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/allowed/1", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unlisted/1", nil))
+
+	if got := requestCount("/allowed/{key}"); got != 1 {
+		t.Errorf("requests labelled /allowed/{key} = %v, want 1", got)
+	}
+	if got := requestCount("/unlisted/{key}"); got != 0 {
+		t.Errorf("requests labelled /unlisted/{key} = %v, want the route left out of the allowlist", got)
+	}
+	if got := requestCount(otherPath) - before; got != 1 {
+		t.Errorf("requests labelled other = %v, want 1", got)
+	}
+}
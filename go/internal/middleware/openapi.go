@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/logger"
+	"github.com/dict-simulator/go/internal/openapi"
+)
+
+// bufferedResponseWriter holds the response back so it can be checked before reaching the client
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+	hijacked   bool
+}
+
+func (bw *bufferedResponseWriter) WriteHeader(code int) {
+	bw.statusCode = code
+}
+
+func (bw *bufferedResponseWriter) Write(b []byte) (int, error) {
+	return bw.body.Write(b)
+}
+
+// Hijack hands the connection over (used by RESET faults); the buffered response is then dropped
+func (bw *bufferedResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(bw.ResponseWriter).Hijack()
+	if err == nil {
+		bw.hijacked = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (bw *bufferedResponseWriter) Unwrap() http.ResponseWriter {
+	return bw.ResponseWriter
+}
+
+// OpenAPIValidation checks every exchange against the OpenAPI document and fails loudly on drift
+// Must wrap the ServeMux directly so r.Pattern is populated once the route has been served.
+// Drift is a served route missing from the document, a request the document rejects but the
+// handler accepted (2xx), an undocumented non-5xx status, or a body that doesn't match its schema.
+// Drifting responses are logged and replaced with a 500 OPENAPI_DRIFT error listing the violations.
+// Routes in ignore (e.g. the docs themselves), HEAD requests, requests no route matched, 499s
+// (the client is gone) and responses from stubs (X-Stub-Id) are passed through.
+// When next is the ServeMux, ignored routes are not buffered either, so they can stream their bodies.
+func OpenAPIValidation(spec *openapi.Spec, ignore ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		mux, _ := next.(*http.ServeMux)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if mux != nil {
+				if _, pattern := mux.Handler(r); slices.Contains(ignore, pattern) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			var reqBody []byte
+			if r.Body != nil {
+				var err error
+				if reqBody, err = io.ReadAll(r.Body); err != nil {
+					// Answered before the route runs, so there is no exchange to check
+					httputil.WriteAPIError(w, r, httputil.BodyError(err))
+					return
+				}
+				r.Body = io.NopCloser(bytes.NewReader(reqBody))
+			}
+
+			buffered := &bufferedResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(buffered, r)
+
+			if buffered.hijacked {
+				return
+			}
+			checked := r.Pattern != "" && r.Method != http.MethodHead && !slices.Contains(ignore, r.Pattern) &&
+				buffered.statusCode != constants.StatusClientClosedRequest && buffered.Header().Get(StubIDHeader) == ""
+			if checked {
+				if violations := checkExchange(spec, r, reqBody, buffered); len(violations) > 0 {
+					logger.Error("OpenAPI drift detected",
+						zap.String("route", r.Pattern),
+						zap.Int("status", buffered.statusCode),
+						zap.Strings("violations", violations),
+						zap.ByteString("body", buffered.body.Bytes()),
+					)
+					apiErr := constants.ErrOpenAPIDrift
+					httputil.WriteAPIError(w, r, apiErr.WithMessage(apiErr.Message+": "+strings.Join(violations, "; ")))
+					return
+				}
+			}
+
+			w.WriteHeader(buffered.statusCode)
+			w.Write(buffered.body.Bytes())
+		})
+	}
+}
+
+// checkExchange returns the drift between a served request/response pair and the document
+func checkExchange(spec *openapi.Spec, r *http.Request, reqBody []byte, buffered *bufferedResponseWriter) []string {
+	op, ok := spec.Operation(r.Pattern)
+	if !ok {
+		return []string{"route " + r.Pattern + " is not documented"}
+	}
+
+	var violations []string
+	if buffered.statusCode >= 200 && buffered.statusCode < 300 {
+		for _, v := range spec.ValidateRequest(op, r, reqBody) {
+			violations = append(violations, "accepted a request the document rejects: "+v)
+		}
+	}
+	contentType := buffered.Header().Get("Content-Type")
+	return append(violations, spec.ValidateResponse(op, buffered.statusCode, contentType, buffered.body.Bytes())...)
+}
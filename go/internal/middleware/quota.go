@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/quota"
+	"github.com/dict-simulator/go/internal/ratelimit"
+)
+
+var quotaChecksTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "quota_checks_total",
+		Help: "Total number of participant request quota checks by result (allowed, denied, error)",
+	},
+	[]string{"result"},
+)
+
+// RequestQuota holds each participant to the requests per minute its quota allows, on top of the
+// DICT policies, so one team's load can't starve the others sharing the simulator. A refused
+// request gets a 429 REQUEST_QUOTA_EXCEEDED and a Retry-After of one request's share of a minute.
+// Must run after authentication; requests made for no participant, or for one without a request
+// quota, go through. Storage errors let the request through, like the DICT rate limits.
+func (m *Manager) RequestQuota(quotas *quota.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			participant := participantOf(r)
+			if participant == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			perMinute := quotas.For(participant).RequestsPerMinute
+			if perMinute <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			policy := ratelimit.QuotaPolicy(perMinute)
+
+			ctx, cancel := m.storeContext(r)
+			state, err := m.rateLimiter.Check(ctx, policy, participant)
+			cancel()
+			if err != nil {
+				quotaChecksTotal.WithLabelValues("error").Inc()
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !state.Allowed {
+				quotaChecksTotal.WithLabelValues("denied").Inc()
+				interval := time.Minute / time.Duration(perMinute)
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(interval.Seconds()))))
+				httputil.WriteAPIError(w, r, constants.ErrRequestQuotaExceeded)
+				return
+			}
+			quotaChecksTotal.WithLabelValues("allowed").Inc()
+
+			capture := &responseCapture{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(capture, r)
+
+			ctx, cancel = m.bookkeepingContext(r)
+			defer cancel()
+			m.rateLimiter.Consume(ctx, policy, participant, capture.statusCode)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dict-simulator/go/internal/chaos"
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/quota"
+	"github.com/dict-simulator/go/internal/ratelimit"
+	"github.com/dict-simulator/go/internal/signing"
+)
+
+func TestRequestQuota(t *testing.T) {
+	clk := clock.NewSimulated()
+	m := NewManager(models.NewMemoryIdempotencyRepository(clk), ratelimit.NewMemoryBucket(clk), ratelimit.NewMemoryBanStore(), signing.NewMemoryNonceStore(), chaos.NewInjector(clk), Settings{})
+	quotas := quota.NewStore(quota.Quota{})
+	quotas.Set("12345678", quota.Quota{RequestsPerMinute: 2})
+
+	handler := m.RequestQuota(quotas)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(participant string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/entries/12345678909", nil)
+		handler.ServeHTTP(rec, req.WithContext(WithIdentity(req.Context(), Identity{Participant: participant})))
+		return rec
+	}
+
+	for i := range 2 {
+		if rec := serve("12345678"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d answered %d, want it within the quota", i+1, rec.Code)
+		}
+	}
+	rec := serve("12345678")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "30" {
+		t.Fatalf("third request answered %d retrying after %q, want 429 after one request's share of a minute", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	// Participants on the default quota, which sets no cap, are not held back
+	for range 5 {
+		if rec := serve("87654321"); rec.Code != http.StatusOK {
+			t.Fatalf("participant without a request quota answered %d, want 200", rec.Code)
+		}
+	}
+
+	clk.Advance(30 * time.Second)
+	if rec := serve("12345678"); rec.Code != http.StatusOK {
+		t.Errorf("request after 30s answered %d, want a request drained from the bucket", rec.Code)
+	}
+
+	// A larger quota drains the same backlog faster
+	quotas.Set("12345678", quota.Quota{RequestsPerMinute: 600})
+	clk.Advance(time.Second)
+	for i := range 10 {
+		if rec := serve("12345678"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d a second after raising the quota answered %d, want 200", i+1, rec.Code)
+		}
+	}
+}
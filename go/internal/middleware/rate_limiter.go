@@ -3,7 +3,9 @@ package middleware
 import (
 	"net/http"
 	"strconv"
-	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"github.com/dict-simulator/go/internal/constants"
 	"github.com/dict-simulator/go/internal/httputil"
@@ -13,6 +15,33 @@ import (
 // IdentifierHeader is the header name for the identifier user
 const IdentifierHeader = "X-Participant-Id"
 
+var (
+	rateLimitChecksTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rate_limit_checks_total",
+			Help: "Total number of rate limit checks by policy and result (allowed, denied, error)",
+		},
+		[]string{"policy", "result"},
+	)
+
+	rateLimitTokensConsumed = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rate_limit_tokens_consumed_total",
+			Help: "Total number of rate limit tokens deducted by policy and response status class",
+		},
+		[]string{"policy", "status_class"},
+	)
+
+	// Buckets are per participant, so the gauge holds whichever bucket was checked last
+	rateLimitBucketRemaining = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rate_limit_bucket_remaining",
+			Help: "Tokens left in the most recently checked bucket of each policy",
+		},
+		[]string{"policy"},
+	)
+)
+
 // responseCapture wraps http.ResponseWriter to capture the status code
 type responseCapture struct {
 	http.ResponseWriter
@@ -36,6 +65,11 @@ func (r *responseCapture) Write(b []byte) (int, error) {
 	return r.ResponseWriter.Write(b)
 }
 
+// Unwrap exposes the underlying writer to http.ResponseController
+func (r *responseCapture) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
 // RateLimiterWithPolicy creates a rate limiting middleware for a specific policy
 // This middleware:
 // 1. Checks if the request is allowed before processing
@@ -45,22 +79,19 @@ func (m *Manager) RateLimiterWithPolicy(policy ratelimit.Policy) func(handler ht
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Skip rate limiting if disabled
-			if !m.rateLimitEnabled {
+			if !m.settings.Load().RateLimitEnabled {
 				next.ServeHTTP(w, r)
 				return
 			}
 
-			// Get identifier
-			identifier := r.Header.Get(IdentifierHeader)
-			if identifier == "" {
-				identifier = "anonymous"
-			}
-
-			ctx := r.Context()
+			identifier := rateLimitIdentifier(r)
 
 			// Pre-check: verify there's capacity in the bucket
+			ctx, cancel := m.storeContext(r)
 			state, err := m.rateLimiter.Check(ctx, policy, identifier)
+			cancel()
 			if err != nil {
+				rateLimitChecksTotal.WithLabelValues(string(policy.Name), "error").Inc()
 				httputil.WriteAPIError(w, r, constants.ErrRateLimitInternal)
 
 				// Fail open on Redis errors
@@ -70,12 +101,15 @@ func (m *Manager) RateLimiterWithPolicy(policy ratelimit.Policy) func(handler ht
 
 			// Set rate limit headers
 			setRateLimitHeaders(w, policy, state)
+			rateLimitBucketRemaining.WithLabelValues(string(policy.Name)).Set(float64(state.Remaining))
 
 			// If no tokens available, return 429
 			if !state.Allowed {
+				rateLimitChecksTotal.WithLabelValues(string(policy.Name), "denied").Inc()
 				writeRateLimitError(w, r)
 				return
 			}
+			rateLimitChecksTotal.WithLabelValues(string(policy.Name), "allowed").Inc()
 
 			// Wrap response writer to capture status code
 			capture := &responseCapture{
@@ -91,11 +125,38 @@ func (m *Manager) RateLimiterWithPolicy(policy ratelimit.Policy) func(handler ht
 			// - 2xx: subtract SuccessCost (usually 1)
 			// - 404: subtract NotFoundCost (can be 3 for antiscan)
 			// - 5xx: skip deduction if IgnoreOn5xx is true
-			_ = m.rateLimiter.Consume(ctx, policy, identifier, capture.statusCode)
+			// Charged even when the client has gone away, since the request was served.
+			ctx, cancel = m.bookkeepingContext(r)
+			defer cancel()
+			if err := m.rateLimiter.Consume(ctx, policy, identifier, capture.statusCode); err == nil {
+				if cost := policy.CostForStatus(capture.statusCode); cost > 0 {
+					addUsedTokens(r, cost)
+					rateLimitTokensConsumed.WithLabelValues(string(policy.Name), statusClass(capture.statusCode)).Add(float64(cost))
+				}
+			}
 		})
 	}
 }
 
+// rateLimitIdentifier returns whose bucket a request spends, as authenticated, never from a bare header:
+// the participant it speaks for, else the user, else the shared anonymous bucket
+func rateLimitIdentifier(r *http.Request) string {
+	id, _ := IdentityFromContext(r.Context())
+	switch {
+	case id.Participant != "":
+		return id.Participant
+	case id.UserID != "":
+		return "user:" + id.UserID
+	default:
+		return "anonymous"
+	}
+}
+
+// statusClass groups a status code as 2xx, 4xx, 5xx and so on
+func statusClass(code int) string {
+	return strconv.Itoa(code/100) + "xx"
+}
+
 // setRateLimitHeaders adds standard rate limit headers to the response
 func setRateLimitHeaders(w http.ResponseWriter, policy ratelimit.Policy, state *ratelimit.BucketState) {
 	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(policy.BucketSize))
@@ -104,17 +165,7 @@ func setRateLimitHeaders(w http.ResponseWriter, policy ratelimit.Policy, state *
 	w.Header().Set("X-RateLimit-Policy", string(policy.Name))
 }
 
-// writeRateLimitError writes a 429 Too Many Requests response with DICT-compliant format
+// writeRateLimitError writes a 429 Too Many Requests response, stamped with the request's clock
 func writeRateLimitError(w http.ResponseWriter, r *http.Request) {
-	correlationID := httputil.GetCorrelationID(r)
-
-	response := httputil.APIResponse{
-		ResponseTime:  time.Now().UTC(),
-		CorrelationId: correlationID,
-		Error:         "TOO_MANY_REQUESTS",
-		Message:       "Rate limit exceeded. Please try again later.",
-	}
-
-	w.Header().Set(httputil.CorrelationIDHeader, correlationID)
-	httputil.WriteJSON(w, http.StatusTooManyRequests, response)
+	httputil.WriteAPIError(w, r, constants.ErrTooManyRequests)
 }
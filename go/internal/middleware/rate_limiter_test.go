@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/dict-simulator/go/internal/chaos"
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/ratelimit"
+	"github.com/dict-simulator/go/internal/signing"
+)
+
+func TestRateLimiterMetrics(t *testing.T) {
+	clk := clock.NewSimulated()
+	m := NewManager(models.NewMemoryIdempotencyRepository(clk), ratelimit.NewMemoryBucket(clk), ratelimit.NewMemoryBanStore(), signing.NewMemoryNonceStore(), chaos.NewInjector(clk), Settings{RateLimitEnabled: true})
+
+	policy := ratelimit.Policy{Name: "METRICS_TEST", BucketSize: 4, SuccessCost: 1, NotFoundCost: 3}
+	status := http.StatusOK
+	handler := m.RateLimiterWithPolicy(policy)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	serve := func() int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/entries/12345678901", nil)
+		handler.ServeHTTP(rec, req.WithContext(WithIdentity(req.Context(), Identity{Participant: "12345678"})))
+		return rec.Code
+	}
+
+	serve()
+	status = http.StatusNotFound
+	serve()
+	if code := serve(); code != http.StatusTooManyRequests {
+		t.Fatalf("third request answered %d, want 429 with the bucket empty", code)
+	}
+
+	for _, tt := range []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"allowed checks", testutil.ToFloat64(rateLimitChecksTotal.WithLabelValues("METRICS_TEST", "allowed")), 2},
+		{"denied checks", testutil.ToFloat64(rateLimitChecksTotal.WithLabelValues("METRICS_TEST", "denied")), 1},
+		{"2xx tokens", testutil.ToFloat64(rateLimitTokensConsumed.WithLabelValues("METRICS_TEST", "2xx")), 1},
+		{"4xx tokens", testutil.ToFloat64(rateLimitTokensConsumed.WithLabelValues("METRICS_TEST", "4xx")), 3},
+		{"remaining", testutil.ToFloat64(rateLimitBucketRemaining.WithLabelValues("METRICS_TEST")), 0},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestRateLimiterIgnoresUnauthenticatedParticipantHeader(t *testing.T) {
+	clk := clock.NewSimulated()
+	m := NewManager(models.NewMemoryIdempotencyRepository(clk), ratelimit.NewMemoryBucket(clk), ratelimit.NewMemoryBanStore(), signing.NewMemoryNonceStore(), chaos.NewInjector(clk), Settings{RateLimitEnabled: true})
+
+	policy := ratelimit.Policy{Name: "SPOOF_TEST", BucketSize: 1, SuccessCost: 1}
+	handler := m.RateLimiterWithPolicy(policy)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serve := func(participant string) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/entries/12345678901", nil)
+		req.Header.Set(IdentifierHeader, participant)
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	serve("12345678")
+	// A new header value must not buy a fresh bucket; both requests are anonymous
+	if code := serve("87654321"); code != http.StatusTooManyRequests {
+		t.Errorf("second anonymous request answered %d, want 429", code)
+	}
+}
+
+// frozenClock always answers the same instant
+type frozenClock time.Time
+
+func (c frozenClock) Now() time.Time { return time.Time(c) }
+
+func TestRateLimitErrorFollowsClock(t *testing.T) {
+	frozen := time.Date(2031, 5, 6, 7, 8, 9, 0, time.UTC)
+	clk := clock.NewSimulated()
+	m := NewManager(models.NewMemoryIdempotencyRepository(clk), ratelimit.NewMemoryBucket(clk), ratelimit.NewMemoryBanStore(), signing.NewMemoryNonceStore(), chaos.NewInjector(clk), Settings{RateLimitEnabled: true})
+
+	policy := ratelimit.Policy{Name: "CLOCK_TEST", BucketSize: 1, SuccessCost: 1}
+	handler := Clock(frozenClock(frozen))(m.RateLimiterWithPolicy(policy)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	serve := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/entries/12345678901", nil))
+		return rec
+	}
+
+	serve()
+	rec := serve()
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request answered %d, want 429", rec.Code)
+	}
+	var body httputil.APIResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode 429: %v", err)
+	}
+	if !body.ResponseTime.Equal(frozen) {
+		t.Errorf("responseTime = %v, want the clock's %v", body.ResponseTime, frozen)
+	}
+	if body.Error != constants.CodeTooManyRequests {
+		t.Errorf("error = %q, want %q", body.Error, constants.CodeTooManyRequests)
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/logger"
+)
+
+// recoveryResponseWriter records whether the response has started, after which a 500 can't be sent
+type recoveryResponseWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (rw *recoveryResponseWriter) WriteHeader(code int) {
+	rw.started = true
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recoveryResponseWriter) Write(b []byte) (int, error) {
+	rw.started = true
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rw *recoveryResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Recovery turns a handler panic into a 500 INTERNAL_ERROR response
+// The panic and its stack are logged with the correlation ID and recorded on the request span.
+// http.ErrAbortHandler (used to reset connections on purpose) is passed on, and a panic after
+// the response has started aborts it, since the status line has already been sent.
+func Recovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wrapped := &recoveryResponseWriter{ResponseWriter: w}
+
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			err, ok := rec.(error)
+			if !ok {
+				err = fmt.Errorf("%v", rec)
+			}
+
+			logger.Error("Handler panicked",
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.String("correlation_id", httputil.GetCorrelationID(r)),
+				zap.String("request_id", httputil.RequestIDFromContext(r.Context())),
+				zap.Any("panic", rec),
+				zap.ByteString("stack", debug.Stack()),
+			)
+
+			span := trace.SpanFromContext(r.Context())
+			span.RecordError(err, trace.WithStackTrace(true))
+			span.SetStatus(codes.Error, "panic: "+err.Error())
+
+			if wrapped.started {
+				panic(http.ErrAbortHandler)
+			}
+			httputil.WriteAPIError(w, r, constants.ErrInternalError)
+		}()
+
+		next.ServeHTTP(wrapped, r)
+	})
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+)
+
+func TestRecoveryAnswersPanicsWith500(t *testing.T) {
+	handler := Recovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/entries/12345678901", nil)
+	req.Header.Set(httputil.CorrelationIDHeader, "corr-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+	var body httputil.APIResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.Error != constants.CodeInternalError || body.CorrelationId != "corr-1" {
+		t.Errorf("response = %+v, want INTERNAL_ERROR with correlation ID corr-1", body)
+	}
+}
+
+func TestRecoveryAbortsStartedResponses(t *testing.T) {
+	tests := map[string]http.HandlerFunc{
+		"deliberate abort": func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		},
+		"panic after writing": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			panic("boom")
+		},
+	}
+
+	for name, next := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if rec := recover(); rec != http.ErrAbortHandler {
+					t.Errorf("recovered %v, want http.ErrAbortHandler", rec)
+				}
+			}()
+			Recovery(next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+)
+
+// unmatchedResponseWriter keeps the status and headers of the mux's answer to an unmatched request,
+// discarding its plain-text body
+type unmatchedResponseWriter struct {
+	header http.Header
+	status int
+}
+
+func (uw *unmatchedResponseWriter) Header() http.Header {
+	return uw.header
+}
+
+func (uw *unmatchedResponseWriter) WriteHeader(code int) {
+	if uw.status == 0 {
+		uw.status = code
+	}
+}
+
+func (uw *unmatchedResponseWriter) Write(b []byte) (int, error) {
+	uw.WriteHeader(http.StatusOK)
+	return len(b), nil
+}
+
+// RouteFallback answers requests no route of mux matches in the API's error format rather than
+// with net/http's plain-text defaults: 404 NOT_FOUND for an unknown path, and 405
+// METHOD_NOT_ALLOWED, with the Allow header listing the methods the path takes, for a known path
+// called with another method. Matched requests are passed to next, which serves mux.
+func RouteFallback(mux *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handler, pattern := mux.Handler(r)
+			if pattern != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// The mux's own answer tells the two apart and computes Allow
+			unmatched := &unmatchedResponseWriter{header: http.Header{}}
+			handler.ServeHTTP(unmatched, r)
+			if unmatched.status == http.StatusMethodNotAllowed {
+				w.Header().Set("Allow", unmatched.header.Get("Allow"))
+				httputil.WriteAPIError(w, r, constants.ErrMethodNotAllowed)
+				return
+			}
+			httputil.WriteAPIError(w, r, constants.ErrRouteNotFound)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouteFallback(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /entries/{key}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Pattern", r.Pattern)
+	})
+	mux.HandleFunc("PUT /entries/{key}", func(w http.ResponseWriter, r *http.Request) {})
+	handler := RouteFallback(mux)(mux)
+
+	tests := []struct {
+		method, path string
+		status       int
+		code         string
+		allow        string
+	}{
+		{http.MethodGet, "/entries/k1", http.StatusOK, "", ""},
+		{http.MethodDelete, "/entries/k1", http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "GET, HEAD, PUT"},
+		{http.MethodGet, "/claims", http.StatusNotFound, "NOT_FOUND", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if got := rec.Header().Get("Allow"); got != tt.allow {
+				t.Errorf("Allow = %q, want %q", got, tt.allow)
+			}
+			if tt.code == "" {
+				if got := rec.Header().Get("X-Pattern"); got != "GET /entries/{key}" {
+					t.Errorf("served pattern = %q, want the matched route", got)
+				}
+				return
+			}
+
+			var body struct {
+				Error string `json:"error"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("body %q is not JSON: %v", rec.Body.String(), err)
+			}
+			if body.Error != tt.code {
+				t.Errorf("error = %q, want %q", body.Error, tt.code)
+			}
+		})
+	}
+}
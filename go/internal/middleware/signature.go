@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/signing"
+)
+
+var signatureChecksTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "request_signature_checks_total",
+		Help: "Total number of request signature checks by result",
+	},
+	[]string{"result"},
+)
+
+// RequestSignature verifies the HMAC-SHA256 X-Signature of requests when signing is enabled
+// The participant in IdentifierHeader signs with its secret as described by signing.Sign.
+// The timestamp must be within the max skew of the wall clock (not the simulated one), and
+// each nonce is accepted once per participant. With a correlation window, so is each
+// X-Correlation-Id the client sends within the window.
+func (m *Manager) RequestSignature(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		settings := m.settings.Load()
+		if !settings.SigningEnabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		signature := r.Header.Get(signing.SignatureHeader)
+		timestamp := r.Header.Get(signing.TimestampHeader)
+		nonce := r.Header.Get(signing.NonceHeader)
+		if signature == "" || timestamp == "" || nonce == "" {
+			rejectSignature(w, r, "missing", constants.ErrSignatureRequired)
+			return
+		}
+
+		secret, ok := settings.SigningSecrets[participantOf(r)]
+		if !ok {
+			rejectSignature(w, r, "unknown_participant", constants.ErrSignatureParticipant)
+			return
+		}
+
+		if !signing.Fresh(timestamp, time.Now(), settings.SigningMaxSkew) {
+			rejectSignature(w, r, "expired", constants.ErrSignatureExpired)
+			return
+		}
+
+		// The signature covers the body, so read it and hand the handler a copy
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			httputil.WriteAPIError(w, r, httputil.BodyError(err))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// RequestURI is the target as the client sent it, before any routing rewrites
+		if !signing.Verify(secret, signature, timestamp, nonce, r.Method, r.RequestURI, body) {
+			rejectSignature(w, r, "invalid", constants.ErrInvalidSignature)
+			return
+		}
+
+		// Claimed only after the signature checks out, so forged requests can't use up a participant's nonces.
+		// A nonce must outlive every timestamp that is still fresh, which is up to twice the skew.
+		ctx, cancel := m.storeContext(r)
+		claimed, err := m.nonces.Claim(ctx, participantOf(r), nonce, 2*settings.SigningMaxSkew)
+		cancel()
+		if err != nil {
+			rejectSignature(w, r, "error", constants.ErrSignatureNonceInternal)
+			return
+		}
+		if !claimed {
+			rejectSignature(w, r, "replayed", constants.ErrSignatureReplayed)
+			return
+		}
+
+		// Generated correlation IDs are unique anyway, so only the client's own are tracked
+		if correlationID := r.Header.Get(httputil.CorrelationIDHeader); correlationID != "" && settings.SigningCorrelationWindow > 0 {
+			ctx, cancel := m.storeContext(r)
+			claimed, err := m.nonces.ClaimCorrelationID(ctx, participantOf(r), correlationID, settings.SigningCorrelationWindow)
+			cancel()
+			if err != nil {
+				rejectSignature(w, r, "error", constants.ErrSignatureNonceInternal)
+				return
+			}
+			if !claimed {
+				rejectSignature(w, r, "correlation_replayed", constants.ErrCorrelationIDReplayed)
+				return
+			}
+		}
+
+		signatureChecksTotal.WithLabelValues("ok").Inc()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func rejectSignature(w http.ResponseWriter, r *http.Request, result string, apiErr constants.APIError) {
+	signatureChecksTotal.WithLabelValues(result).Inc()
+	httputil.WriteAPIError(w, r, apiErr)
+}
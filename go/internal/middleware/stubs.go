@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/dict-simulator/go/internal/stubs"
+)
+
+// StubIDHeader names the stub that answered a request
+const StubIDHeader = "X-Stub-Id"
+
+var stubsServedTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "stubs_served_total",
+		Help: "Total number of requests answered by a stub instead of their handler",
+	},
+	[]string{"route"},
+)
+
+// Stubs answers the requests a stub added via /admin/stubs matches with its canned response
+// Must run inside the route chain so r.Pattern and the {key} path value are populated.
+// The response carries the stub's ID in X-Stub-Id; OpenAPIValidation doesn't check it, since
+// stubs exist for exchanges the simulator doesn't model.
+func Stubs(registry *stubs.Registry) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			stub, ok := registry.Match(r.Pattern, r.PathValue("key"), r.Header)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			stubsServedTotal.WithLabelValues(r.Pattern).Inc()
+
+			if stub.DelayMs > 0 {
+				select {
+				case <-time.After(stub.Delay()):
+				case <-r.Context().Done():
+					return
+				}
+			}
+
+			if len(stub.Body) > 0 {
+				w.Header().Set("Content-Type", "application/json")
+			}
+			for name, value := range stub.ResponseHeaders {
+				w.Header().Set(name, value)
+			}
+			w.Header().Set(StubIDHeader, stub.ID)
+			w.WriteHeader(stub.StatusCode)
+			w.Write(stub.Body)
+		})
+	}
+}
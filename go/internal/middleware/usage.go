@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dict-simulator/go/internal/usage"
+)
+
+// requestUsageKey is the context key of the request's usage, which the rate limiters add tokens to
+type requestUsageKey struct{}
+
+// requestUsage is what a request consumed besides itself
+type requestUsage struct {
+	tokens int
+}
+
+// UsageReport counts every request made for a participant with recorder: its status class and
+// the DICT rate limit tokens it consumed. Requests without a participant (anonymous, admin) are
+// not counted. Must wrap Recovery, so recovered panics count as the 500s they return.
+func UsageReport(recorder *usage.Recorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// The identity is set further in, so read it from the access log's slot (or our own)
+			ctx := r.Context()
+			identity, ok := ctx.Value(identitySlotKey{}).(*Identity)
+			if !ok {
+				ctx, identity = withIdentitySlot(ctx)
+			}
+			used := &requestUsage{}
+			ctx = context.WithValue(ctx, requestUsageKey{}, used)
+
+			capture := &responseCapture{ResponseWriter: w, statusCode: http.StatusOK}
+			serveCopy(next, capture, r, r.WithContext(ctx))
+
+			if identity.Participant != "" {
+				recorder.Record(identity.Participant, capture.statusCode, used.tokens)
+			}
+		})
+	}
+}
+
+// addUsedTokens counts tokens against the request's usage, when it is reported
+func addUsedTokens(r *http.Request, tokens int) {
+	if used, ok := r.Context().Value(requestUsageKey{}).(*requestUsage); ok {
+		used.tokens += tokens
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/usage"
+)
+
+func TestUsageReportCountsParticipants(t *testing.T) {
+	clk := clock.NewSimulated()
+	reports := models.NewMemoryUsageReportRepository(clk)
+	recorder := usage.NewRecorder(reports, clk, 0)
+
+	status := http.StatusOK
+	handler := AccessLog(1)(UsageReport(recorder)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/anonymous" {
+			setIdentity(r, Identity{UserID: "user-1", Participant: "12345678"})
+		}
+		addUsedTokens(r, 2)
+		w.WriteHeader(status)
+	})))
+
+	for _, s := range []int{http.StatusOK, http.StatusNotFound, http.StatusServiceUnavailable} {
+		status = s
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/entries/k", nil))
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/anonymous", nil))
+
+	got, err := recorder.Report(context.Background(), models.UsageReportFilter{})
+	if err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("reported %d participant days, want 1", len(got))
+	}
+	want := models.UsageCounts{Requests: 3, ClientErrors: 1, ServerErrors: 1, TokensConsumed: 6}
+	if got[0].Participant != "12345678" || got[0].UsageCounts != want {
+		t.Errorf("report = %+v, want participant 12345678 with %+v", got[0], want)
+	}
+}
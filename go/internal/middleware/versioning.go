@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dict-simulator/go/internal/httputil"
+)
+
+// APIPrefix is where the versions are served: /api/v1/entries is the v1 contract of /entries
+const APIPrefix = "/api/"
+
+// APIVersion is an API contract served under APIPrefix
+type APIVersion struct {
+	Name       string    // path segment, e.g. "v1"
+	Deprecated time.Time // when it was deprecated; zero while supported
+	Sunset     time.Time // when it will be removed; zero if no date is set
+}
+
+// Versioning serves the routes under /api/{version} for each of versions, oldest first, and
+// stores the version in the request context (httputil.APIVersion) so handlers can keep each
+// version's contract. The prefix is stripped before routing, so every version shares the routes.
+//
+// Requests without the prefix are served as the unprefixed version describes, which carries
+// the dates the unprefixed paths were deprecated and will be removed. Paths in unversioned
+// (exact, or any path under an entry ending in "/") are infrastructure and are left alone.
+// Deprecated requests get Deprecation (RFC 9745), Sunset (RFC 8594) and a Link to their successor.
+// Unknown versions are passed through unchanged, so they are not found.
+func Versioning(versions []APIVersion, unprefixed APIVersion, unversioned ...string) func(http.Handler) http.Handler {
+	latest := versions[len(versions)-1]
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if rest, ok := strings.CutPrefix(r.URL.Path, APIPrefix); ok {
+				name, path, _ := strings.Cut(rest, "/")
+				i := slices.IndexFunc(versions, func(v APIVersion) bool { return v.Name == name })
+				if i < 0 {
+					next.ServeHTTP(w, r)
+					return
+				}
+
+				deprecate(w.Header(), versions[i], APIPrefix+latest.Name+"/"+path)
+				serveCopy(next, w, r, stripVersion(r, name))
+				return
+			}
+
+			if slices.ContainsFunc(unversioned, func(p string) bool {
+				return r.URL.Path == p || (strings.HasSuffix(p, "/") && strings.HasPrefix(r.URL.Path, p))
+			}) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			deprecate(w.Header(), unprefixed, APIPrefix+unprefixed.Name+r.URL.Path)
+			serveCopy(next, w, r, r.WithContext(httputil.WithAPIVersion(r.Context(), unprefixed.Name)))
+		})
+	}
+}
+
+// deprecate sets the deprecation headers of a deprecated version, pointing at successor
+func deprecate(h http.Header, version APIVersion, successor string) {
+	if version.Deprecated.IsZero() {
+		return
+	}
+	h.Set("Deprecation", "@"+strconv.FormatInt(version.Deprecated.Unix(), 10))
+	if !version.Sunset.IsZero() {
+		h.Set("Sunset", version.Sunset.UTC().Format(http.TimeFormat))
+	}
+	h.Add("Link", "<"+successor+`>; rel="successor-version"`)
+}
+
+// stripVersion returns a copy of r for the version's routes, without the /api/{version} prefix
+func stripVersion(r *http.Request, version string) *http.Request {
+	prefix := APIPrefix + version
+	served := r.WithContext(httputil.WithAPIVersion(r.Context(), version))
+	served.URL = new(url.URL)
+	*served.URL = *r.URL
+	served.URL.Path = strings.TrimPrefix(r.URL.Path, prefix)
+	served.URL.RawPath = strings.TrimPrefix(r.URL.RawPath, prefix)
+	if served.URL.Path == "" {
+		served.URL.Path = "/"
+	}
+	return served
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dict-simulator/go/internal/httputil"
+)
+
+func TestVersioning(t *testing.T) {
+	deprecated := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, time.June, 30, 0, 0, 0, 0, time.UTC)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /entries/{key}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Served", httputil.APIVersion(r)+" "+r.PathValue("key"))
+	})
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {})
+
+	var pattern string
+	handler := Versioning(
+		[]APIVersion{{Name: "v1", Deprecated: deprecated, Sunset: sunset}, {Name: "v2"}},
+		APIVersion{Name: "v1", Deprecated: deprecated},
+		"/health",
+	)(mux)
+	handler = func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+			pattern = r.Pattern
+		})
+	}(handler)
+
+	tests := []struct {
+		path        string
+		status      int
+		served      string
+		deprecation string
+		sunset      string
+		link        string
+	}{
+		{"/api/v2/entries/k1", http.StatusOK, "v2 k1", "", "", ""},
+		{"/api/v1/entries/k1", http.StatusOK, "v1 k1", "@1767225600", "Wed, 30 Jun 2027 00:00:00 GMT", `</api/v2/entries/k1>; rel="successor-version"`},
+		{"/entries/k1", http.StatusOK, "v1 k1", "@1767225600", "", `</api/v1/entries/k1>; rel="successor-version"`},
+		{"/health", http.StatusOK, "", "", "", ""},
+		{"/api/v9/entries/k1", http.StatusNotFound, "", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			for name, want := range map[string]string{"X-Served": tt.served, "Deprecation": tt.deprecation, "Sunset": tt.sunset, "Link": tt.link} {
+				if got := rec.Header().Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+			if tt.served != "" && pattern != "GET /entries/{key}" {
+				t.Errorf("outer request's pattern = %q, want the matched route", pattern)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/db"
+	"github.com/dict-simulator/go/internal/listing"
+)
+
+// AdminAuditRecord is one action taken through the /admin routes: who did what, with which
+// parameters, and how it was answered
+type AdminAuditRecord struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	Actor         string             `bson:"actor"`  // "admin-token", or "client:{id}" for an OAuth client with the admin scope
+	Action        string             `bson:"action"` // e.g. admin.seed
+	Method        string             `bson:"method"`
+	Path          string             `bson:"path"`
+	Parameters    map[string]any     `bson:"parameters,omitempty"` // query parameters and the JSON body's fields
+	Status        int                `bson:"status"`
+	CorrelationID string             `bson:"correlationId"`
+	CreatedAt     time.Time          `bson:"createdAt"`
+}
+
+// AdminAuditResponse represents the API response for an admin audit record
+type AdminAuditResponse struct {
+	ID            string         `json:"id" example:"507f1f77bcf86cd799439011"`
+	Actor         string         `json:"actor" example:"admin-token"`
+	Action        string         `json:"action" example:"admin.seed"`
+	Method        string         `json:"method" example:"POST"`
+	Path          string         `json:"path" example:"/admin/seed"`
+	Parameters    map[string]any `json:"parameters,omitempty"`
+	Status        int            `json:"status" example:"201"`
+	CorrelationID string         `json:"correlationId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	CreatedAt     time.Time      `json:"createdAt"`
+}
+
+// AdminAuditPage is one page of audit records, newest first
+// NextCursor is set when older records follow; pass it back as cursor to read them.
+type AdminAuditPage struct {
+	Records    []AdminAuditResponse `json:"records"`
+	NextCursor string               `json:"nextCursor,omitempty" example:"507f1f77bcf86cd799439011"`
+}
+
+// AdminAuditFilter selects audit records; empty fields match every record
+type AdminAuditFilter struct {
+	Actor  string
+	Action string
+	Page   listing.Page // Page.After is a record ID in hex; Descending lists newest first
+}
+
+// AdminAuditRepository handles storage operations for admin audit records
+type AdminAuditRepository interface {
+	// Create stores a record, stamping its creation time
+	Create(ctx context.Context, record *AdminAuditRecord) error
+	// List returns a page of the records filter selects, in ID (creation) order
+	List(ctx context.Context, filter AdminAuditFilter) ([]AdminAuditRecord, error)
+	// DeleteCreatedBefore deletes the records created at or before cutoff and returns how many
+	DeleteCreatedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// MongoAdminAuditRepository stores audit records in the admin_audit collection
+type MongoAdminAuditRepository struct {
+	collection *mongo.Collection
+	clock      clock.Clock
+}
+
+// NewMongoAdminAuditRepository creates a new MongoDB-backed admin audit repository
+func NewMongoAdminAuditRepository(db *db.Mongo, clk clock.Clock) *MongoAdminAuditRepository {
+	return &MongoAdminAuditRepository{
+		collection: db.Collection("admin_audit"),
+		clock:      clk,
+	}
+}
+
+// IndexSpec declares the indexes of the admin_audit collection
+func (r *MongoAdminAuditRepository) IndexSpec() db.IndexSpec {
+	return db.IndexSpec{
+		Collection: r.collection.Name(),
+		Indexes: []mongo.IndexModel{
+			{Keys: bson.D{{Key: "actor", Value: 1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "action", Value: 1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "createdAt", Value: 1}}},
+		},
+	}
+}
+
+// EnsureIndexes creates the indexes of the admin_audit collection
+func (r *MongoAdminAuditRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, r.IndexSpec().Indexes)
+	return err
+}
+
+// Create stores a record
+func (r *MongoAdminAuditRepository) Create(ctx context.Context, record *AdminAuditRecord) error {
+	record.CreatedAt = r.clock.Now().UTC()
+
+	result, err := r.collection.InsertOne(ctx, record)
+	if err != nil {
+		return err
+	}
+
+	oid, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return errors.New("failed to get inserted ID")
+	}
+	record.ID = oid
+	return nil
+}
+
+// List returns a page of the records filter selects in _id order
+func (r *MongoAdminAuditRepository) List(ctx context.Context, filter AdminAuditFilter) ([]AdminAuditRecord, error) {
+	op, order := "$gt", 1
+	if filter.Page.Descending {
+		op, order = "$lt", -1
+	}
+	query := bson.M{}
+	if filter.Actor != "" {
+		query["actor"] = filter.Actor
+	}
+	if filter.Action != "" {
+		query["action"] = filter.Action
+	}
+	if filter.Page.After != "" {
+		after, err := primitive.ObjectIDFromHex(filter.Page.After)
+		if err != nil {
+			return nil, err
+		}
+		query["_id"] = bson.M{op: after}
+	}
+
+	cursor, err := r.collection.Find(ctx, query,
+		options.Find().SetSort(bson.D{{Key: "_id", Value: order}}).SetLimit(int64(filter.Page.Limit)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []AdminAuditRecord{}
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// DeleteCreatedBefore deletes the records created at or before cutoff
+func (r *MongoAdminAuditRepository) DeleteCreatedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"createdAt": bson.M{"$lte": cutoff.UTC()}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// ToResponse converts AdminAuditRecord to AdminAuditResponse
+func (a *AdminAuditRecord) ToResponse() AdminAuditResponse {
+	return AdminAuditResponse{
+		ID:            a.ID.Hex(),
+		Actor:         a.Actor,
+		Action:        a.Action,
+		Method:        a.Method,
+		Path:          a.Path,
+		Parameters:    a.Parameters,
+		Status:        a.Status,
+		CorrelationID: a.CorrelationID,
+		CreatedAt:     a.CreatedAt,
+	}
+}
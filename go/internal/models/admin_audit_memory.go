@@ -0,0 +1,77 @@
+package models
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/dict-simulator/go/internal/clock"
+)
+
+// MemoryAdminAuditRepository keeps admin audit records in process memory (STORAGE=memory)
+type MemoryAdminAuditRepository struct {
+	mu      sync.RWMutex
+	records []AdminAuditRecord // oldest first
+	clock   clock.Clock
+}
+
+// NewMemoryAdminAuditRepository creates a new in-memory admin audit repository
+func NewMemoryAdminAuditRepository(clk clock.Clock) *MemoryAdminAuditRepository {
+	return &MemoryAdminAuditRepository{
+		clock: clk,
+	}
+}
+
+// Create stores a record
+func (r *MemoryAdminAuditRepository) Create(ctx context.Context, record *AdminAuditRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Assigned under the lock, so records stay in ID order
+	record.ID = primitive.NewObjectID()
+	record.CreatedAt = r.clock.Now().UTC()
+	r.records = append(r.records, *record)
+	return nil
+}
+
+// List returns a page of the records filter selects in ID order
+func (r *MemoryAdminAuditRepository) List(ctx context.Context, filter AdminAuditFilter) ([]AdminAuditRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Records are kept oldest first; order compares IDs in the listing's direction
+	all, order := slices.All(r.records), strings.Compare
+	if filter.Page.Descending {
+		all, order = slices.Backward(r.records), func(a, b string) int { return strings.Compare(b, a) }
+	}
+
+	records := []AdminAuditRecord{}
+	for _, record := range all {
+		if len(records) == filter.Page.Limit {
+			break
+		}
+		if filter.Page.After != "" && order(record.ID.Hex(), filter.Page.After) <= 0 {
+			continue
+		}
+		if (filter.Actor == "" || record.Actor == filter.Actor) && (filter.Action == "" || record.Action == filter.Action) {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// DeleteCreatedBefore deletes the records created at or before cutoff
+func (r *MemoryAdminAuditRepository) DeleteCreatedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := len(r.records)
+	r.records = slices.DeleteFunc(r.records, func(record AdminAuditRecord) bool {
+		return !record.CreatedAt.After(cutoff)
+	})
+	return int64(kept - len(r.records)), nil
+}
@@ -1,8 +1,13 @@
 package models
 
 import (
+	"cmp"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -10,7 +15,9 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"github.com/dict-simulator/go/internal/clock"
 	"github.com/dict-simulator/go/internal/db"
+	"github.com/dict-simulator/go/internal/listing"
 )
 
 // KeyType represents the type of Pix key
@@ -24,21 +31,55 @@ const (
 	KeyTypeEVP   KeyType = "EVP"
 )
 
+// KeyTypes lists every key type
+var KeyTypes = []KeyType{KeyTypeCPF, KeyTypeCNPJ, KeyTypeEMAIL, KeyTypePHONE, KeyTypeEVP}
+
 // AccountType represents the type of bank account
+// Stored entries keep their type as a plain string, so adding types needs no migration.
 type AccountType string
 
+const (
+	AccountTypeCACC AccountType = "CACC" // checking account
+	AccountTypeSVGS AccountType = "SVGS" // savings account
+	AccountTypeSLRY AccountType = "SLRY" // salary account
+	AccountTypePAGA AccountType = "PAGA" // prepaid payment account
+	AccountTypeTRAN AccountType = "TRAN" // transactional account
+)
+
 // OwnerType represents the type of account owner
 type OwnerType string
 
+const (
+	OwnerTypeNaturalPerson OwnerType = "NATURAL_PERSON"
+	OwnerTypeLegalPerson   OwnerType = "LEGAL_PERSON"
+)
+
 // Reason represents the reason for an entry operation
 type Reason string
 
+const (
+	ReasonUserRequested  Reason = "USER_REQUESTED"
+	ReasonAccountClosure Reason = "ACCOUNT_CLOSURE"
+	ReasonBranchTransfer Reason = "BRANCH_TRANSFER"
+	ReasonReconciliation Reason = "RECONCILIATION"
+	ReasonFraud          Reason = "FRAUD"
+	ReasonRFBValidation  Reason = "RFB_VALIDATION"
+)
+
+// EntryStatus represents whether an entry is in good standing with the DICT
+type EntryStatus string
+
+const (
+	EntryStatusActive               EntryStatus = "ACTIVE"
+	EntryStatusPendingRFBValidation EntryStatus = "PENDING_RFB_VALIDATION" // owner irregular at the Receita Federal; an RFB_VALIDATION update clears it
+)
+
 // Account represents bank account information
 type Account struct {
 	Participant   string      `bson:"participant" json:"participant" validate:"required,len=8,numeric" example:"12345678"`
-	Branch        string      `bson:"branch" json:"branch" validate:"required,len=4,numeric" example:"0001"`
+	Branch        string      `bson:"branch" json:"branch" validate:"required" example:"0001"`
 	AccountNumber string      `bson:"accountNumber" json:"accountNumber" validate:"required" example:"123456789"`
-	AccountType   AccountType `bson:"accountType" json:"accountType" validate:"required,oneof=CACC SVGS SLRY" example:"CACC"`
+	AccountType   AccountType `bson:"accountType" json:"accountType" validate:"required,oneof=CACC SVGS SLRY PAGA TRAN" example:"CACC"`
 	OpeningDate   time.Time   `bson:"openingDate" json:"openingDate" validate:"required" example:"2024-01-15T00:00:00Z"`
 }
 
@@ -48,14 +89,15 @@ type Owner struct {
 	TaxIdNumber string    `bson:"taxIdNumber" json:"taxIdNumber" validate:"required" example:"12345678901"`
 	Name        string    `bson:"name" json:"name" validate:"required" example:"John Doe"`
 	TradeName   string    `bson:"tradeName,omitempty" json:"tradeName,omitempty" example:"Doe Enterprises"` // Only for LEGAL_PERSON
+	TaxIdHash   string    `bson:"taxIdHash,omitempty" json:"-"`                                             // Blind index of a sealed tax ID, which lookups by tax ID match; empty in plaintext
 }
 
 // UpdateAccount represents partial account updates (no required validations)
 type UpdateAccount struct {
 	Participant   string      `bson:"participant,omitempty" json:"participant,omitempty" validate:"omitempty,len=8,numeric" example:"12345678"`
-	Branch        string      `bson:"branch,omitempty" json:"branch,omitempty" validate:"omitempty,len=4,numeric" example:"0001"`
+	Branch        string      `bson:"branch,omitempty" json:"branch,omitempty" example:"0001"`
 	AccountNumber string      `bson:"accountNumber,omitempty" json:"accountNumber,omitempty" example:"123456789"`
-	AccountType   AccountType `bson:"accountType,omitempty" json:"accountType,omitempty" validate:"omitempty,oneof=CACC SVGS SLRY" example:"CACC"`
+	AccountType   AccountType `bson:"accountType,omitempty" json:"accountType,omitempty" validate:"omitempty,oneof=CACC SVGS SLRY PAGA TRAN" example:"CACC"`
 	OpeningDate   *time.Time  `bson:"openingDate,omitempty" json:"openingDate,omitempty" example:"2024-01-15T00:00:00Z"`
 }
 
@@ -70,23 +112,29 @@ type UpdateOwner struct {
 type Entry struct {
 	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
 	Key              string             `bson:"key" json:"key"`
+	NormalizedKey    string             `bson:"normalizedKey" json:"-"` // NormalizeKey(Key), which lookups match
 	KeyType          KeyType            `bson:"keyType" json:"keyType"`
 	Account          Account            `bson:"account" json:"account"`
 	Owner            Owner              `bson:"owner" json:"owner"`
 	CreatedAt        time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt        time.Time          `bson:"updatedAt" json:"updatedAt"`
 	KeyOwnershipDate time.Time          `bson:"keyOwnershipDate" json:"keyOwnershipDate"`
+	Status           EntryStatus        `bson:"status,omitempty" json:"status,omitempty"` // Empty for entries stored before statuses, which are active
+	RFBReviewedAt    *time.Time         `bson:"rfbReviewedAt,omitempty" json:"-"`         // Last Receita Federal review; nil if never reviewed
+	Sequence         int64              `bson:"sequence" json:"sequence"`                 // 1 when created, incremented by each change; 0 for entries stored before sequences
 }
 
 // EntryResponse represents the API response for an entry
 type EntryResponse struct {
-	Key              string    `json:"key" example:"+5511999999999"`
-	KeyType          KeyType   `json:"keyType" example:"PHONE"`
-	Account          Account   `json:"account"`
-	Owner            Owner     `json:"owner"`
-	CreatedAt        time.Time `json:"createdAt"`
-	UpdatedAt        time.Time `json:"updatedAt"`
-	KeyOwnershipDate time.Time `json:"keyOwnershipDate"`
+	Key              string      `json:"key" example:"+5511999999999"`
+	KeyType          KeyType     `json:"keyType" example:"PHONE"`
+	Account          Account     `json:"account"`
+	Owner            Owner       `json:"owner"`
+	CreatedAt        time.Time   `json:"createdAt"`
+	UpdatedAt        time.Time   `json:"updatedAt"`
+	KeyOwnershipDate time.Time   `json:"keyOwnershipDate"`
+	Status           EntryStatus `json:"status" example:"ACTIVE"`
+	Sequence         int64       `json:"sequence" example:"1"` // Incremented by each change to the entry, so its events can be ordered; deletion takes the next number
 }
 
 // CreateEntryRequest represents the request body for creating an entry
@@ -103,10 +151,11 @@ type CreateEntryRequest struct {
 // Per DICT spec: Only account info, name, and trade name can be updated
 // EVP keys cannot be updated
 type UpdateEntryRequest struct {
-	Key     string         `json:"key" validate:"required" example:"+5511999999999"`
-	Account *UpdateAccount `json:"account,omitempty" validate:"omitempty"`
-	Owner   *UpdateOwner   `json:"owner,omitempty" validate:"omitempty"`
-	Reason  Reason         `json:"reason" validate:"required,oneof=USER_REQUESTED BRANCH_TRANSFER RECONCILIATION RFB_VALIDATION" example:"USER_REQUESTED"`
+	Key         string         `json:"key" validate:"required" example:"+5511999999999"`
+	Participant string         `json:"participant" validate:"required,len=8,numeric" example:"12345678"` // Must own the entry
+	Account     *UpdateAccount `json:"account,omitempty" validate:"omitempty"`
+	Owner       *UpdateOwner   `json:"owner,omitempty" validate:"omitempty"`
+	Reason      Reason         `json:"reason" validate:"required,oneof=USER_REQUESTED BRANCH_TRANSFER RECONCILIATION RFB_VALIDATION" example:"USER_REQUESTED"`
 }
 
 // DeleteEntryRequest represents the request body for deleting an entry
@@ -118,53 +167,218 @@ type DeleteEntryRequest struct {
 }
 
 // DeleteEntryResponse represents the response for deleting an entry
+// ReleasedKeys lists the account's other keys deleted along with it (ACCOUNT_CLOSURE).
 type DeleteEntryResponse struct {
-	Message string `json:"message" example:"Entry deleted successfully"`
-	Key     string `json:"key" example:"+5511999999999"`
+	Message      string   `json:"message" example:"Entry deleted successfully"`
+	Key          string   `json:"key" example:"+5511999999999"`
+	ReleasedKeys []string `json:"releasedKeys,omitempty" example:"52998224725"`
+}
+
+// CloseAccountRequest represents the request body for closing an account
+// Without TransferTo every key linked to the account is deleted; with it they move to that account.
+type CloseAccountRequest struct {
+	TransferTo *TransferAccount `json:"transferTo,omitempty" validate:"omitempty"`
+}
+
+// TransferAccount is the participant's account that receives the keys of a closed account
+type TransferAccount struct {
+	Branch        string      `json:"branch" validate:"required" example:"0002"`
+	AccountNumber string      `json:"accountNumber" validate:"required" example:"987654321"`
+	AccountType   AccountType `json:"accountType" validate:"required,oneof=CACC SVGS SLRY PAGA TRAN" example:"CACC"`
+	OpeningDate   time.Time   `json:"openingDate" validate:"required" example:"2024-06-01T00:00:00Z"`
+}
+
+// CloseAccountResponse represents the response for closing an account
+// TransferredTo is set when the keys were moved rather than deleted.
+type CloseAccountResponse struct {
+	Participant   string           `json:"participant" example:"12345678"`
+	Branch        string           `json:"branch" example:"0001"`
+	AccountNumber string           `json:"accountNumber" example:"123456789"`
+	Keys          []string         `json:"keys" example:"+5511999999999"`
+	TransferredTo *TransferAccount `json:"transferredTo,omitempty"`
+}
+
+// EntryPage is one page of a participant's entries
+// NextCursor is set when more entries follow; pass it back as cursor to read them.
+type EntryPage struct {
+	Entries    []EntryResponse `json:"entries"`
+	NextCursor string          `json:"nextCursor,omitempty" example:"KzU1MTE5OTk5OTk5OTk"`
 }
 
-// EntryRepository handles database operations for entries
-type EntryRepository struct {
+// ValidateKeyRequest represents the request body for validating a key without registering it
+type ValidateKeyRequest struct {
+	Key     string  `json:"key" validate:"required" example:"+5511999999999"`
+	KeyType KeyType `json:"keyType" validate:"required,oneof=CPF CNPJ EMAIL PHONE EVP" example:"PHONE"`
+}
+
+// KeyValidationResponse is the outcome of validating a key
+// Error and Message are the code and message POST /entries would answer with.
+type KeyValidationResponse struct {
+	Key     string  `json:"key" example:"+5511999999999"`
+	KeyType KeyType `json:"keyType" example:"PHONE"`
+	Valid   bool    `json:"valid" example:"false"`
+	Error   string  `json:"error,omitempty" example:"KEY_ALREADY_EXISTS"`
+	Message string  `json:"message,omitempty" example:"This key is already registered in the directory"`
+}
+
+// ErrEntryKeyExists is returned by EntryRepository.Create when the key is already registered
+var ErrEntryKeyExists error = &Error{Kind: ErrDuplicateKey, Resource: ResourceEntry}
+
+// ErrEntryNotFound reports a key no entry holds
+var ErrEntryNotFound error = &Error{Kind: ErrNotFound, Resource: ResourceEntry}
+
+// EntryRepository handles storage operations for entries
+// Lookups return (nil, nil) when no entry matches. Keys are matched in their NormalizeKey form,
+// so "Test@Example.com" finds the entry registered as "test@example.com".
+type EntryRepository interface {
+	// Create stores a new entry
+	Create(ctx context.Context, req *CreateEntryRequest) (*Entry, error)
+	// CreateMany stores entries in bulk, skipping keys that are already registered
+	CreateMany(ctx context.Context, reqs []CreateEntryRequest) (int, error)
+	// InsertMany stores entries as they are, timestamps included, skipping keys that are already registered
+	// Entries without an ID get a new one. Returns the number of entries inserted.
+	InsertMany(ctx context.Context, entries []Entry) (int, error)
+	// FindByKey finds an entry by its key
+	FindByKey(ctx context.Context, key string) (*Entry, error)
+	// FindByTaxID finds the entries whose owner has taxID, or has it as the blind index of a sealed tax ID,
+	// in normalized key order
+	FindByTaxID(ctx context.Context, taxID string) ([]Entry, error)
+	// DeleteByKeyAndParticipant deletes an entry owned by participant and returns it
+	DeleteByKeyAndParticipant(ctx context.Context, key string, participant string) (*Entry, error)
+	// UpdateByKeyAndParticipant applies a partial update to a non-EVP entry owned by participant and returns the result
+	UpdateByKeyAndParticipant(ctx context.Context, key string, participant string, req *UpdateEntryRequest) (*Entry, error)
+	// ForEachKey calls fn with every registered key, normalized, stopping at the first error
+	ForEachKey(ctx context.Context, fn func(key string) error) error
+	// ForEachEntry calls fn with every entry in key order, stopping at the first error
+	ForEachEntry(ctx context.Context, fn func(entry *Entry) error) error
+	// CountByKeyType returns the number of registered entries of each key type
+	CountByKeyType(ctx context.Context) (map[KeyType]int64, error)
+	// CountByParticipant returns the number of entries owned by participant
+	CountByParticipant(ctx context.Context, participant string) (int64, error)
+	// DeleteByParticipant deletes every entry owned by participant and returns how many were deleted
+	DeleteByParticipant(ctx context.Context, participant string) (int64, error)
+	// DeleteByAccount deletes every entry linked to a participant's account and returns them
+	DeleteByAccount(ctx context.Context, participant, branch, accountNumber string) ([]Entry, error)
+	// ListByParticipant lists a page of the entries owned by participant and updated at or after since,
+	// in normalized key order; page.After is a normalized key
+	ListByParticipant(ctx context.Context, participant string, since time.Time, page listing.Page) ([]Entry, error)
+	// TransferAccount links every entry of a participant's account to the account to instead and returns them updated
+	TransferAccount(ctx context.Context, participant, branch, accountNumber string, to Account) ([]Entry, error)
+	// ReviewRFB reviews the active CPF and CNPJ entries not reviewed since reviewedBefore, moving those whose
+	// owner irregular reports to PENDING_RFB_VALIDATION, and returns the entries it moved
+	ReviewRFB(ctx context.Context, reviewedBefore time.Time, irregular func(owner Owner) bool) ([]Entry, error)
+	// RewriteOwner stores owner's name, tax ID and blind index on the entry with key if its name and tax ID still are old's, and reports
+	// whether it did. The data itself is unchanged (e.g. it is re-encrypted), so updatedAt is left alone.
+	RewriteOwner(ctx context.Context, key string, old, owner Owner) (bool, error)
+}
+
+// MongoEntryRepository stores entries in the entries collection
+type MongoEntryRepository struct {
 	collection *mongo.Collection
+	clock      clock.Clock
 }
 
-// NewEntryRepository creates a new entry repository
-func NewEntryRepository(db *db.Mongo) *EntryRepository {
-	return &EntryRepository{
+// NewMongoEntryRepository creates a new MongoDB-backed entry repository
+func NewMongoEntryRepository(db *db.Mongo, clk clock.Clock) *MongoEntryRepository {
+	return &MongoEntryRepository{
 		collection: db.Collection("entries"),
+		clock:      clk,
 	}
 }
 
-// EnsureIndexes creates necessary indexes for the entries collection
-func (r *EntryRepository) EnsureIndexes(ctx context.Context) error {
-	indexes := []mongo.IndexModel{
-		{
-			Keys:    bson.D{{Key: "key", Value: 1}},
-			Options: options.Index().SetUnique(true),
-		},
-		{
-			Keys: bson.D{{Key: "owner.taxIdNumber", Value: 1}},
+// IndexSpec declares the indexes of the entries collection
+func (r *MongoEntryRepository) IndexSpec() db.IndexSpec {
+	return db.IndexSpec{
+		Collection: r.collection.Name(),
+		Indexes: []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "key", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				Keys:    bson.D{{Key: "normalizedKey", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				// Serves finding an owner's keys, at every participant or at one; replaces owner.taxIdNumber_1,
+				// which the startup index check reports as undeclared until it is dropped
+				Keys: bson.D{{Key: "owner.taxIdNumber", Value: 1}, {Key: "account.participant", Value: 1}},
+			},
+			{
+				// The same, for owners sealed at rest (PII_ENCRYPTION_KEYS), which are found by blind index
+				Keys: bson.D{{Key: "owner.taxIdHash", Value: 1}, {Key: "account.participant", Value: 1}},
+				Options: options.Index().SetPartialFilterExpression(bson.M{
+					"owner.taxIdHash": bson.M{"$exists": true},
+				}),
+			},
+			{
+				// Serves participant resets and the participant's listing, in key order
+				Keys: bson.D{{Key: "account.participant", Value: 1}, {Key: "normalizedKey", Value: 1}},
+			},
+			{
+				// Serves counting and listing a participant's keys of one type
+				Keys: bson.D{{Key: "account.participant", Value: 1}, {Key: "keyType", Value: 1}},
+			},
+			{
+				// Serves finding entries in a status by when they last changed, e.g. those pending RFB validation
+				Keys: bson.D{{Key: "status", Value: 1}, {Key: "updatedAt", Value: 1}},
+			},
 		},
 	}
+}
+
+// EnsureIndexes creates the indexes of the entries collection
+// Entries stored before keys were normalized get their normalizedKey first, so the unique index can be built.
+func (r *MongoEntryRepository) EnsureIndexes(ctx context.Context) error {
+	// Keys were validated in canonical form before, so the raw key is already the normalized one
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"normalizedKey": bson.M{"$exists": false}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"normalizedKey": "$key"}}}},
+	)
+	if err != nil {
+		return err
+	}
 
-	_, err := r.collection.Indexes().CreateMany(ctx, indexes)
+	_, err = r.collection.Indexes().CreateMany(ctx, r.IndexSpec().Indexes)
 	return err
 }
 
-// Create creates a new entry in the database
-func (r *EntryRepository) Create(ctx context.Context, req *CreateEntryRequest) (*Entry, error) {
-	now := time.Now()
-	entry := &Entry{
+// EnablePreImages records the document before each change so change stream consumers
+// can still see an entry's participant after it is deleted (MongoDB 6.0+ replica sets)
+func (r *MongoEntryRepository) EnablePreImages(ctx context.Context) error {
+	return r.collection.Database().RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: r.collection.Name()},
+		{Key: "changeStreamPreAndPostImages", Value: bson.M{"enabled": true}},
+	}).Err()
+}
+
+// duplicateKeyErrorCode is the MongoDB server error code for unique index violations
+const duplicateKeyErrorCode = 11000
+
+// newEntry builds an Entry from a creation request
+func newEntry(req *CreateEntryRequest, now time.Time) *Entry {
+	return &Entry{
 		Key:              req.Key,
+		NormalizedKey:    NormalizeKey(req.Key),
 		KeyType:          req.KeyType,
 		Account:          req.Account,
 		Owner:            req.Owner,
 		CreatedAt:        now,
 		UpdatedAt:        now,
 		KeyOwnershipDate: now, // For new entries, ownership date equals creation date
+		Status:           EntryStatusActive,
+		Sequence:         1,
 	}
+}
+
+// Create creates a new entry in the database
+func (r *MongoEntryRepository) Create(ctx context.Context, req *CreateEntryRequest) (*Entry, error) {
+	entry := newEntry(req, r.clock.Now())
 
 	result, err := r.collection.InsertOne(ctx, entry)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrEntryKeyExists
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -178,10 +392,54 @@ func (r *EntryRepository) Create(ctx context.Context, req *CreateEntryRequest) (
 	return entry, nil
 }
 
+// CreateMany inserts entries in bulk, skipping keys that are already registered
+// Returns the number of entries actually inserted
+func (r *MongoEntryRepository) CreateMany(ctx context.Context, reqs []CreateEntryRequest) (int, error) {
+	now := r.clock.Now()
+	entries := make([]Entry, 0, len(reqs))
+	for i := range reqs {
+		entries = append(entries, *newEntry(&reqs[i], now))
+	}
+	return r.InsertMany(ctx, entries)
+}
+
+// InsertMany inserts entries as they are, skipping keys that are already registered
+// Returns the number of entries actually inserted
+func (r *MongoEntryRepository) InsertMany(ctx context.Context, entries []Entry) (int, error) {
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	docs := make([]any, 0, len(entries))
+	for i := range entries {
+		entries[i].NormalizedKey = NormalizeKey(entries[i].Key)
+		docs = append(docs, &entries[i])
+	}
+
+	// Unordered so a duplicate key does not abort the rest of the batch
+	opts := options.InsertMany().SetOrdered(false)
+	_, err := r.collection.InsertMany(ctx, docs, opts)
+	if err == nil {
+		return len(docs), nil
+	}
+
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) {
+		return 0, err
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Code != duplicateKeyErrorCode {
+			return 0, err
+		}
+	}
+
+	return len(docs) - len(bulkErr.WriteErrors), nil
+}
+
 // FindByKey finds an entry by its key
-func (r *EntryRepository) FindByKey(ctx context.Context, key string) (*Entry, error) {
+func (r *MongoEntryRepository) FindByKey(ctx context.Context, key string) (*Entry, error) {
 	var entry Entry
-	err := r.collection.FindOne(ctx, bson.M{"key": key}).Decode(&entry)
+	err := r.collection.FindOne(ctx, bson.M{"normalizedKey": NormalizeKey(key)}).Decode(&entry)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, nil
@@ -191,12 +449,32 @@ func (r *EntryRepository) FindByKey(ctx context.Context, key string) (*Entry, er
 	return &entry, nil
 }
 
+// FindByTaxID finds the entries whose owner has taxID, in plaintext or as blind index
+func (r *MongoEntryRepository) FindByTaxID(ctx context.Context, taxID string) ([]Entry, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"owner.taxIdNumber": taxID},
+		bson.M{"owner.taxIdHash": taxID},
+	}}
+	opts := options.Find().SetSort(bson.D{{Key: "normalizedKey", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []Entry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
 // DeleteByKeyAndParticipant deletes an entry by its key and participant, and returns the deleted entry
 // This combined operation ensures atomicity and reduces DB calls
-func (r *EntryRepository) DeleteByKeyAndParticipant(ctx context.Context, key string, participant string) (*Entry, error) {
+func (r *MongoEntryRepository) DeleteByKeyAndParticipant(ctx context.Context, key string, participant string) (*Entry, error) {
 	var entry Entry
 	filter := bson.M{
-		"key":                 key,
+		"normalizedKey":       NormalizeKey(key),
 		"account.participant": participant,
 	}
 
@@ -210,14 +488,15 @@ func (r *EntryRepository) DeleteByKeyAndParticipant(ctx context.Context, key str
 	return &entry, nil
 }
 
-// UpdateByKey updates an entry by its key
+// UpdateByKeyAndParticipant updates an entry by its key if participant owns it
 // Only updates the fields that are provided in the request
 // Also ensures that the key is not an EVP key
-func (r *EntryRepository) UpdateByKey(ctx context.Context, key string, req *UpdateEntryRequest) (*Entry, error) {
+func (r *MongoEntryRepository) UpdateByKeyAndParticipant(ctx context.Context, key string, participant string, req *UpdateEntryRequest) (*Entry, error) {
 	update := bson.M{
 		"$set": bson.M{
-			"updatedAt": time.Now(),
+			"updatedAt": r.clock.Now(),
 		},
+		"$inc": bson.M{"sequence": 1},
 	}
 
 	setFields := update["$set"].(bson.M)
@@ -236,12 +515,19 @@ func (r *EntryRepository) UpdateByKey(ctx context.Context, key string, req *Upda
 		}
 	}
 
+	// Validating the owner with the Receita Federal counts as a review
+	if req.Reason == ReasonRFBValidation {
+		setFields["status"] = EntryStatusActive
+		setFields["rfbReviewedAt"] = setFields["updatedAt"]
+	}
+
 	var entry Entry
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 
-	// Filter by key AND ensure KeyType is not EVP
+	// Filter by key and participant AND ensure KeyType is not EVP
 	filter := bson.M{
-		"key": key,
+		"normalizedKey":       NormalizeKey(key),
+		"account.participant": participant,
 		"keyType": bson.M{
 			"$ne": KeyTypeEVP,
 		},
@@ -257,6 +543,104 @@ func (r *EntryRepository) UpdateByKey(ctx context.Context, key string, req *Upda
 	return &entry, nil
 }
 
+// ForEachKey calls fn with every registered key, normalized, stopping at the first error
+func (r *MongoEntryRepository) ForEachKey(ctx context.Context, fn func(key string) error) error {
+	opts := options.Find().SetProjection(bson.M{"_id": 0, "normalizedKey": 1})
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			Key string `bson:"normalizedKey"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+		if err := fn(doc.Key); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// ForEachEntry calls fn with every entry in key order, stopping at the first error
+func (r *MongoEntryRepository) ForEachEntry(ctx context.Context, fn func(entry *Entry) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "key", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var entry Entry
+		if err := cursor.Decode(&entry); err != nil {
+			return err
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// ListByParticipant lists a page of the participant's entries in normalized key order
+func (r *MongoEntryRepository) ListByParticipant(ctx context.Context, participant string, since time.Time, page listing.Page) ([]Entry, error) {
+	op, order := "$gt", 1
+	if page.Descending {
+		op, order = "$lt", -1
+	}
+	filter := bson.M{"account.participant": participant}
+	if page.After != "" {
+		filter["normalizedKey"] = bson.M{op: page.After}
+	}
+	if !since.IsZero() {
+		filter["updatedAt"] = bson.M{"$gte": since}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "normalizedKey", Value: order}}).
+		SetLimit(int64(page.Limit))
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []Entry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// CountByKeyType returns the number of registered entries of each key type
+func (r *MongoEntryRepository) CountByKeyType(ctx context.Context) (map[KeyType]int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$keyType", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []struct {
+		KeyType KeyType `bson:"_id"`
+		Count   int64   `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	counts := map[KeyType]int64{}
+	for _, g := range groups {
+		counts[g.KeyType] = g.Count
+	}
+	return counts, nil
+}
+
 // ToResponse converts Entry to EntryResponse
 func (e *Entry) ToResponse() EntryResponse {
 	return EntryResponse{
@@ -267,5 +651,208 @@ func (e *Entry) ToResponse() EntryResponse {
 		CreatedAt:        e.CreatedAt,
 		UpdatedAt:        e.UpdatedAt,
 		KeyOwnershipDate: e.KeyOwnershipDate,
+		Status:           cmp.Or(e.Status, EntryStatusActive),
+		Sequence:         e.Sequence,
+	}
+}
+
+// DeletedResponse returns the entry's response as of its deletion, the change after its last one
+func (e *Entry) DeletedResponse() EntryResponse {
+	resp := e.ToResponse()
+	resp.Sequence++
+	return resp
+}
+
+// CID returns the entry's content identifier: the hex SHA-256 of the key and the data a participant
+// holds for it. A participant whose copy of the entry yields the same CID agrees with the directory.
+// Timestamps are left out, so only changes to the key, owner or account change the CID.
+func (e *Entry) CID() string {
+	fields := []string{
+		NormalizeKey(e.Key),
+		string(e.KeyType),
+		string(e.Owner.Type),
+		e.Owner.TaxIdNumber,
+		e.Owner.Name,
+		e.Owner.TradeName,
+		e.Account.Participant,
+		e.Account.Branch,
+		e.Account.AccountNumber,
+		string(e.Account.AccountType),
+		e.Account.OpeningDate.UTC().Format(time.RFC3339),
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "&")))
+	return hex.EncodeToString(sum[:])
+}
+
+// ETag returns a weak entity tag for the entry's API representation
+func (e *Entry) ETag() string {
+	return e.ToResponse().ETag()
+}
+
+// ETag returns a weak entity tag for the representation
+// It is weak because the response envelope (responseTime, correlationId) differs on every request.
+// A masked representation gets a tag of its own, so caches never confuse it with the full one.
+func (r EntryResponse) ETag() string {
+	body, _ := json.Marshal(r)
+	sum := sha256.Sum256(body)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// CountByParticipant returns the number of entries owned by participant
+func (r *MongoEntryRepository) CountByParticipant(ctx context.Context, participant string) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"account.participant": participant})
+}
+
+// DeleteByParticipant deletes every entry owned by participant
+func (r *MongoEntryRepository) DeleteByParticipant(ctx context.Context, participant string) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"account.participant": participant})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// DeleteByAccount deletes every entry linked to a participant's account and returns them
+// The entries are read, then deleted by ID, so an entry linked to the account in between is kept.
+func (r *MongoEntryRepository) DeleteByAccount(ctx context.Context, participant, branch, accountNumber string) ([]Entry, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"account.participant":   participant,
+		"account.branch":        branch,
+		"account.accountNumber": accountNumber,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []Entry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return entries, nil
+	}
+
+	ids := make([]primitive.ObjectID, len(entries))
+	for i := range entries {
+		ids[i] = entries[i].ID
+	}
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// TransferAccount links every entry of a participant's account to the account to instead and returns them updated
+// Like DeleteByAccount, the entries are read, then updated by ID.
+func (r *MongoEntryRepository) TransferAccount(ctx context.Context, participant, branch, accountNumber string, to Account) ([]Entry, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"account.participant":   participant,
+		"account.branch":        branch,
+		"account.accountNumber": accountNumber,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []Entry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return entries, nil
+	}
+
+	now := r.clock.Now()
+	ids := make([]primitive.ObjectID, len(entries))
+	for i := range entries {
+		ids[i] = entries[i].ID
+		entries[i].Account = to
+		entries[i].UpdatedAt = now
+		entries[i].Sequence++
+	}
+	_, err = r.collection.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": ids}},
+		bson.M{"$set": bson.M{"account": to, "updatedAt": now}, "$inc": bson.M{"sequence": 1}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// RewriteOwner stores owner's name, tax ID and blind index if the entry still has old's name and tax ID
+func (r *MongoEntryRepository) RewriteOwner(ctx context.Context, key string, old, owner Owner) (bool, error) {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{
+			"normalizedKey":     NormalizeKey(key),
+			"owner.name":        old.Name,
+			"owner.taxIdNumber": old.TaxIdNumber,
+		},
+		bson.M{"$set": bson.M{"owner.name": owner.Name, "owner.taxIdNumber": owner.TaxIdNumber, "owner.taxIdHash": owner.TaxIdHash}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// ReviewRFB reviews the active CPF and CNPJ entries not reviewed since reviewedBefore
+// Every reviewed entry is stamped, so the next run skips it; irregular ones also change status.
+func (r *MongoEntryRepository) ReviewRFB(ctx context.Context, reviewedBefore time.Time, irregular func(owner Owner) bool) ([]Entry, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"keyType":       bson.M{"$in": []KeyType{KeyTypeCPF, KeyTypeCNPJ}},
+		"status":        bson.M{"$ne": EntryStatusPendingRFBValidation},
+		"rfbReviewedAt": bson.M{"$not": bson.M{"$gte": reviewedBefore}}, // also matches entries never reviewed
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	now := r.clock.Now()
+	reviewed := []primitive.ObjectID{}
+	flagged := []Entry{}
+	for cursor.Next(ctx) {
+		var entry Entry
+		if err := cursor.Decode(&entry); err != nil {
+			return nil, err
+		}
+		reviewed = append(reviewed, entry.ID)
+		if irregular(entry.Owner) {
+			entry.Status = EntryStatusPendingRFBValidation
+			entry.UpdatedAt = now
+			entry.RFBReviewedAt = &now
+			entry.Sequence++
+			flagged = append(flagged, entry)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	if len(reviewed) == 0 {
+		return flagged, nil
+	}
+
+	_, err = r.collection.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": reviewed}},
+		bson.M{"$set": bson.M{"rfbReviewedAt": now}},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, len(flagged))
+	for i := range flagged {
+		ids[i] = flagged[i].ID
+	}
+	if len(ids) > 0 {
+		_, err = r.collection.UpdateMany(ctx,
+			bson.M{"_id": bson.M{"$in": ids}},
+			bson.M{"$set": bson.M{"status": EntryStatusPendingRFBValidation, "updatedAt": now}, "$inc": bson.M{"sequence": 1}},
+		)
+		if err != nil {
+			return nil, err
+		}
 	}
+	return flagged, nil
 }
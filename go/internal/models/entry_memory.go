@@ -0,0 +1,349 @@
+package models
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/listing"
+)
+
+// MemoryEntryRepository keeps entries in process memory (STORAGE=memory)
+type MemoryEntryRepository struct {
+	mu      sync.RWMutex
+	entries map[string]Entry // by normalized key
+	clock   clock.Clock
+}
+
+// NewMemoryEntryRepository creates a new in-memory entry repository
+func NewMemoryEntryRepository(clk clock.Clock) *MemoryEntryRepository {
+	return &MemoryEntryRepository{
+		entries: map[string]Entry{},
+		clock:   clk,
+	}
+}
+
+// Create creates a new entry
+func (r *MemoryEntryRepository) Create(ctx context.Context, req *CreateEntryRequest) (*Entry, error) {
+	entry := newEntry(req, r.clock.Now())
+	entry.ID = primitive.NewObjectID()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.entries[entry.NormalizedKey]; ok {
+		return nil, ErrEntryKeyExists
+	}
+	r.entries[entry.NormalizedKey] = *entry
+
+	return entry, nil
+}
+
+// CreateMany inserts entries in bulk, skipping keys that are already registered
+// Returns the number of entries actually inserted
+func (r *MemoryEntryRepository) CreateMany(ctx context.Context, reqs []CreateEntryRequest) (int, error) {
+	now := r.clock.Now()
+	entries := make([]Entry, 0, len(reqs))
+	for i := range reqs {
+		entries = append(entries, *newEntry(&reqs[i], now))
+	}
+	return r.InsertMany(ctx, entries)
+}
+
+// InsertMany inserts entries as they are, skipping keys that are already registered
+// Returns the number of entries actually inserted
+func (r *MemoryEntryRepository) InsertMany(ctx context.Context, entries []Entry) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inserted := 0
+	for _, entry := range entries {
+		entry.NormalizedKey = NormalizeKey(entry.Key)
+		if _, ok := r.entries[entry.NormalizedKey]; ok {
+			continue
+		}
+		if entry.ID.IsZero() {
+			entry.ID = primitive.NewObjectID()
+		}
+		r.entries[entry.NormalizedKey] = entry
+		inserted++
+	}
+
+	return inserted, nil
+}
+
+// FindByKey finds an entry by its key
+func (r *MemoryEntryRepository) FindByKey(ctx context.Context, key string) (*Entry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.entries[NormalizeKey(key)]
+	if !ok {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+// FindByTaxID finds the entries whose owner has taxID, in plaintext or as blind index
+func (r *MemoryEntryRepository) FindByTaxID(ctx context.Context, taxID string) ([]Entry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := []Entry{}
+	for _, entry := range r.entries {
+		if entry.Owner.TaxIdNumber == taxID || entry.Owner.TaxIdHash == taxID {
+			entries = append(entries, entry)
+		}
+	}
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return strings.Compare(a.NormalizedKey, b.NormalizedKey)
+	})
+	return entries, nil
+}
+
+// DeleteByKeyAndParticipant deletes an entry by its key and participant, and returns the deleted entry
+func (r *MemoryEntryRepository) DeleteByKeyAndParticipant(ctx context.Context, key string, participant string) (*Entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key = NormalizeKey(key)
+	entry, ok := r.entries[key]
+	if !ok || entry.Account.Participant != participant {
+		return nil, nil
+	}
+	delete(r.entries, key)
+
+	return &entry, nil
+}
+
+// UpdateByKeyAndParticipant updates an entry by its key if participant owns it
+// Matches the MongoDB repository: a provided account replaces the stored one,
+// while only the owner's name and trade name are merged in. EVP keys are never updated.
+func (r *MemoryEntryRepository) UpdateByKeyAndParticipant(ctx context.Context, key string, participant string, req *UpdateEntryRequest) (*Entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key = NormalizeKey(key)
+	entry, ok := r.entries[key]
+	if !ok || entry.KeyType == KeyTypeEVP || entry.Account.Participant != participant {
+		return nil, nil
+	}
+
+	entry.UpdatedAt = r.clock.Now()
+	entry.Sequence++
+
+	if req.Account != nil {
+		entry.Account = Account{
+			Participant:   req.Account.Participant,
+			Branch:        req.Account.Branch,
+			AccountNumber: req.Account.AccountNumber,
+			AccountType:   req.Account.AccountType,
+		}
+		if req.Account.OpeningDate != nil {
+			entry.Account.OpeningDate = *req.Account.OpeningDate
+		}
+	}
+
+	if req.Owner != nil {
+		if req.Owner.Name != "" {
+			entry.Owner.Name = req.Owner.Name
+		}
+		if req.Owner.TradeName != "" {
+			entry.Owner.TradeName = req.Owner.TradeName
+		}
+	}
+
+	if req.Reason == ReasonRFBValidation {
+		entry.Status = EntryStatusActive
+		entry.RFBReviewedAt = &entry.UpdatedAt
+	}
+
+	r.entries[key] = entry
+	return &entry, nil
+}
+
+// ForEachKey calls fn with every registered key, normalized, stopping at the first error
+// fn runs on a snapshot of the keys, so it may use the repository.
+func (r *MemoryEntryRepository) ForEachKey(ctx context.Context, fn func(key string) error) error {
+	r.mu.RLock()
+	keys := make([]string, 0, len(r.entries))
+	for key := range r.entries {
+		keys = append(keys, key)
+	}
+	r.mu.RUnlock()
+
+	for _, key := range keys {
+		if err := fn(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ForEachEntry calls fn with every entry in key order, stopping at the first error
+// fn runs on a snapshot of the entries, so it may use the repository.
+func (r *MemoryEntryRepository) ForEachEntry(ctx context.Context, fn func(entry *Entry) error) error {
+	r.mu.RLock()
+	entries := slices.Collect(maps.Values(r.entries))
+	r.mu.RUnlock()
+
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	for i := range entries {
+		if err := fn(&entries[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListByParticipant lists a page of the participant's entries in normalized key order
+func (r *MemoryEntryRepository) ListByParticipant(ctx context.Context, participant string, since time.Time, page listing.Page) ([]Entry, error) {
+	// order compares keys in the listing's direction
+	order := strings.Compare
+	if page.Descending {
+		order = func(a, b string) int { return strings.Compare(b, a) }
+	}
+
+	r.mu.RLock()
+	entries := []Entry{}
+	for key, entry := range r.entries {
+		if entry.Account.Participant == participant && (page.After == "" || order(key, page.After) > 0) && !entry.UpdatedAt.Before(since) {
+			entries = append(entries, entry)
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return order(a.NormalizedKey, b.NormalizedKey)
+	})
+	if len(entries) > page.Limit {
+		entries = entries[:page.Limit]
+	}
+	return entries, nil
+}
+
+// CountByKeyType returns the number of registered entries of each key type
+func (r *MemoryEntryRepository) CountByKeyType(ctx context.Context) (map[KeyType]int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := map[KeyType]int64{}
+	for _, entry := range r.entries {
+		counts[entry.KeyType]++
+	}
+	return counts, nil
+}
+
+// CountByParticipant returns the number of entries owned by participant
+func (r *MemoryEntryRepository) CountByParticipant(ctx context.Context, participant string) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, entry := range r.entries {
+		if entry.Account.Participant == participant {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// DeleteByParticipant deletes every entry owned by participant
+func (r *MemoryEntryRepository) DeleteByParticipant(ctx context.Context, participant string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for key, entry := range r.entries {
+		if entry.Account.Participant == participant {
+			delete(r.entries, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// DeleteByAccount deletes every entry linked to a participant's account and returns them
+func (r *MemoryEntryRepository) DeleteByAccount(ctx context.Context, participant, branch, accountNumber string) ([]Entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := []Entry{}
+	for key, entry := range r.entries {
+		if entry.Account.Participant == participant && entry.Account.Branch == branch && entry.Account.AccountNumber == accountNumber {
+			delete(r.entries, key)
+			deleted = append(deleted, entry)
+		}
+	}
+	return deleted, nil
+}
+
+// TransferAccount links every entry of a participant's account to the account to instead and returns them updated
+func (r *MemoryEntryRepository) TransferAccount(ctx context.Context, participant, branch, accountNumber string, to Account) ([]Entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+	transferred := []Entry{}
+	for key, entry := range r.entries {
+		if entry.Account.Participant == participant && entry.Account.Branch == branch && entry.Account.AccountNumber == accountNumber {
+			entry.Account = to
+			entry.UpdatedAt = now
+			entry.Sequence++
+			r.entries[key] = entry
+			transferred = append(transferred, entry)
+		}
+	}
+	return transferred, nil
+}
+
+// RewriteOwner stores owner's name, tax ID and blind index if the entry still has old's name and tax ID
+func (r *MemoryEntryRepository) RewriteOwner(ctx context.Context, key string, old, owner Owner) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key = NormalizeKey(key)
+	entry, ok := r.entries[key]
+	if !ok || entry.Owner.Name != old.Name || entry.Owner.TaxIdNumber != old.TaxIdNumber {
+		return false, nil
+	}
+	entry.Owner.Name = owner.Name
+	entry.Owner.TaxIdNumber = owner.TaxIdNumber
+	entry.Owner.TaxIdHash = owner.TaxIdHash
+	r.entries[key] = entry
+	return true, nil
+}
+
+// ReviewRFB reviews the active CPF and CNPJ entries not reviewed since reviewedBefore
+func (r *MemoryEntryRepository) ReviewRFB(ctx context.Context, reviewedBefore time.Time, irregular func(owner Owner) bool) ([]Entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+	flagged := []Entry{}
+	for key, entry := range r.entries {
+		if entry.KeyType != KeyTypeCPF && entry.KeyType != KeyTypeCNPJ {
+			continue
+		}
+		if entry.Status == EntryStatusPendingRFBValidation || (entry.RFBReviewedAt != nil && !entry.RFBReviewedAt.Before(reviewedBefore)) {
+			continue
+		}
+		entry.RFBReviewedAt = &now
+		if irregular(entry.Owner) {
+			entry.Status = EntryStatusPendingRFBValidation
+			entry.UpdatedAt = now
+			entry.Sequence++
+			flagged = append(flagged, entry)
+		}
+		r.entries[key] = entry
+	}
+	return flagged, nil
+}
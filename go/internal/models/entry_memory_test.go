@@ -0,0 +1,68 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dict-simulator/go/internal/clock"
+)
+
+// benchmarkEntryRequest builds a distinct EMAIL entry request for the i-th benchmark iteration
+func benchmarkEntryRequest(i int) *CreateEntryRequest {
+	return &CreateEntryRequest{
+		Key:     fmt.Sprintf("bench-%d@example.com", i),
+		KeyType: KeyTypeEMAIL,
+		Account: Account{
+			Participant:   "12345678",
+			Branch:        "0001",
+			AccountNumber: fmt.Sprintf("%010d", i),
+			AccountType:   "CACC",
+			OpeningDate:   time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC),
+		},
+		Owner: Owner{
+			Type:        OwnerTypeNaturalPerson,
+			TaxIdNumber: "11144477735",
+			Name:        "Benchmark User",
+		},
+		Reason:    ReasonUserRequested,
+		RequestId: "550e8400-e29b-41d4-a716-446655440000",
+	}
+}
+
+func BenchmarkMemoryEntryRepository(b *testing.B) {
+	ctx := context.Background()
+
+	b.Run("Create", func(b *testing.B) {
+		repo := NewMemoryEntryRepository(clock.System)
+		b.ReportAllocs()
+		i := 0
+		for b.Loop() {
+			if _, err := repo.Create(ctx, benchmarkEntryRequest(i)); err != nil {
+				b.Fatal(err)
+			}
+			i++
+		}
+	})
+
+	b.Run("FindByKey", func(b *testing.B) {
+		repo := NewMemoryEntryRepository(clock.System)
+		const stored = 10000
+		for i := range stored {
+			if _, err := repo.Create(ctx, benchmarkEntryRequest(i)); err != nil {
+				b.Fatal(err)
+			}
+		}
+
+		b.ReportAllocs()
+		i := 0
+		for b.Loop() {
+			entry, err := repo.FindByKey(ctx, fmt.Sprintf("bench-%d@example.com", i%stored))
+			if err != nil || entry == nil {
+				b.Fatalf("FindByKey() = %v, %v", entry, err)
+			}
+			i++
+		}
+	})
+}
@@ -0,0 +1,436 @@
+package models
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/db"
+	"github.com/dict-simulator/go/internal/listing"
+)
+
+// entryColumns is the column list scanned by scanEntry
+const entryColumns = "id, key, normalized_key, key_type, account, owner, created_at, updated_at, key_ownership_date, status, rfb_reviewed_at, sequence"
+
+// PostgresEntryRepository stores entries in the entries table
+type PostgresEntryRepository struct {
+	pg    *db.Postgres
+	clock clock.Clock
+}
+
+// NewPostgresEntryRepository creates a new PostgreSQL-backed entry repository
+func NewPostgresEntryRepository(pg *db.Postgres, clk clock.Clock) *PostgresEntryRepository {
+	return &PostgresEntryRepository{
+		pg:    pg,
+		clock: clk,
+	}
+}
+
+// storedOwner is the owner column: the owner's JSON plus its blind index, which the API never shows
+type storedOwner struct {
+	Owner
+	TaxIdHash string `json:"taxIdHash,omitempty"`
+}
+
+// scanEntry reads a row selected with entryColumns
+func scanEntry(row pgx.Row) (*Entry, error) {
+	var entry Entry
+	var id string
+	var owner storedOwner
+	err := row.Scan(&id, &entry.Key, &entry.NormalizedKey, &entry.KeyType, &entry.Account, &owner,
+		&entry.CreatedAt, &entry.UpdatedAt, &entry.KeyOwnershipDate, &entry.Status, &entry.RFBReviewedAt, &entry.Sequence)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	entry.Owner = owner.Owner
+	entry.Owner.TaxIdHash = owner.TaxIdHash
+
+	entry.ID, err = primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// insertEntrySQL inserts an entry, doing nothing when the key is already registered
+const insertEntrySQL = `INSERT INTO entries (` + entryColumns + `)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	ON CONFLICT DO NOTHING`
+
+// insertEntryArgs returns the insertEntrySQL arguments for an entry
+func insertEntryArgs(entry *Entry) []any {
+	owner := storedOwner{Owner: entry.Owner, TaxIdHash: entry.Owner.TaxIdHash}
+	return []any{entry.ID.Hex(), entry.Key, NormalizeKey(entry.Key), entry.KeyType, entry.Account, owner,
+		entry.CreatedAt, entry.UpdatedAt, entry.KeyOwnershipDate, cmp.Or(entry.Status, EntryStatusActive), entry.RFBReviewedAt, entry.Sequence}
+}
+
+// Create creates a new entry in the database
+func (r *PostgresEntryRepository) Create(ctx context.Context, req *CreateEntryRequest) (*Entry, error) {
+	entry := newEntry(req, r.clock.Now())
+	entry.ID = primitive.NewObjectID()
+
+	tag, err := r.pg.Pool.Exec(ctx, insertEntrySQL, insertEntryArgs(entry)...)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrEntryKeyExists
+	}
+
+	return entry, nil
+}
+
+// CreateMany inserts entries in one batch, skipping keys that are already registered
+// Returns the number of entries actually inserted
+func (r *PostgresEntryRepository) CreateMany(ctx context.Context, reqs []CreateEntryRequest) (int, error) {
+	now := r.clock.Now()
+	entries := make([]Entry, 0, len(reqs))
+	for i := range reqs {
+		entries = append(entries, *newEntry(&reqs[i], now))
+	}
+	return r.InsertMany(ctx, entries)
+}
+
+// InsertMany inserts entries as they are in one batch, skipping keys that are already registered
+// Returns the number of entries actually inserted
+func (r *PostgresEntryRepository) InsertMany(ctx context.Context, entries []Entry) (int, error) {
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	batch := &pgx.Batch{}
+	for i := range entries {
+		entry := entries[i]
+		if entry.ID.IsZero() {
+			entry.ID = primitive.NewObjectID()
+		}
+		batch.Queue(insertEntrySQL, insertEntryArgs(&entry)...)
+	}
+
+	results := r.pg.Pool.SendBatch(ctx, batch)
+
+	inserted := 0
+	for range entries {
+		tag, err := results.Exec()
+		if err != nil {
+			results.Close()
+			return 0, err
+		}
+		inserted += int(tag.RowsAffected())
+	}
+
+	return inserted, results.Close()
+}
+
+// FindByKey finds an entry by its key
+func (r *PostgresEntryRepository) FindByKey(ctx context.Context, key string) (*Entry, error) {
+	return scanEntry(r.pg.Pool.QueryRow(ctx,
+		`SELECT `+entryColumns+` FROM entries WHERE normalized_key = $1`,
+		NormalizeKey(key),
+	))
+}
+
+// FindByTaxID finds the entries whose owner has taxID, in plaintext or as blind index
+func (r *PostgresEntryRepository) FindByTaxID(ctx context.Context, taxID string) ([]Entry, error) {
+	rows, err := r.pg.Pool.Query(ctx,
+		`SELECT `+entryColumns+` FROM entries
+		WHERE owner ->> 'taxIdNumber' = $1 OR owner ->> 'taxIdHash' = $1
+		ORDER BY normalized_key`,
+		taxID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []Entry{}
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, rows.Err()
+}
+
+// DeleteByKeyAndParticipant deletes an entry by its key and participant, and returns the deleted entry
+func (r *PostgresEntryRepository) DeleteByKeyAndParticipant(ctx context.Context, key string, participant string) (*Entry, error) {
+	return scanEntry(r.pg.Pool.QueryRow(ctx,
+		`DELETE FROM entries WHERE normalized_key = $1 AND account ->> 'participant' = $2 RETURNING `+entryColumns,
+		NormalizeKey(key), participant,
+	))
+}
+
+// UpdateByKeyAndParticipant updates an entry by its key if participant owns it
+// Matches the MongoDB repository: a provided account replaces the stored one,
+// while only the owner's name and trade name are merged in. EVP keys are never updated.
+// Reason RFB_VALIDATION also clears a pending Receita Federal validation.
+func (r *PostgresEntryRepository) UpdateByKeyAndParticipant(ctx context.Context, key string, participant string, req *UpdateEntryRequest) (*Entry, error) {
+	var account any
+	if req.Account != nil {
+		account = req.Account
+	}
+
+	owner := map[string]string{}
+	if req.Owner != nil {
+		if req.Owner.Name != "" {
+			owner["name"] = req.Owner.Name
+		}
+		if req.Owner.TradeName != "" {
+			owner["tradeName"] = req.Owner.TradeName
+		}
+	}
+
+	return scanEntry(r.pg.Pool.QueryRow(ctx,
+		`UPDATE entries
+		SET updated_at = $2,
+			sequence = sequence + 1,
+			account = COALESCE($3::jsonb, account),
+			owner = owner || $4::jsonb,
+			status = CASE WHEN $7 THEN $8 ELSE status END,
+			rfb_reviewed_at = CASE WHEN $7 THEN $2 ELSE rfb_reviewed_at END
+		WHERE normalized_key = $1 AND key_type <> $5 AND account ->> 'participant' = $6
+		RETURNING `+entryColumns,
+		NormalizeKey(key), r.clock.Now(), account, owner, KeyTypeEVP, participant,
+		req.Reason == ReasonRFBValidation, EntryStatusActive,
+	))
+}
+
+// ForEachKey calls fn with every registered key, normalized, stopping at the first error
+func (r *PostgresEntryRepository) ForEachKey(ctx context.Context, fn func(key string) error) error {
+	rows, err := r.pg.Pool.Query(ctx, `SELECT normalized_key FROM entries`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return err
+		}
+		if err := fn(key); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ForEachEntry calls fn with every entry in key order, stopping at the first error
+func (r *PostgresEntryRepository) ForEachEntry(ctx context.Context, fn func(entry *Entry) error) error {
+	rows, err := r.pg.Pool.Query(ctx, `SELECT `+entryColumns+` FROM entries ORDER BY key`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ListByParticipant lists a page of the participant's entries in normalized key order
+func (r *PostgresEntryRepository) ListByParticipant(ctx context.Context, participant string, since time.Time, page listing.Page) ([]Entry, error) {
+	op, order := ">", "ASC"
+	if page.Descending {
+		op, order = "<", "DESC"
+	}
+	rows, err := r.pg.Pool.Query(ctx,
+		`SELECT `+entryColumns+` FROM entries
+		WHERE account ->> 'participant' = $1 AND ($2 = '' OR normalized_key `+op+` $2) AND updated_at >= $3
+		ORDER BY normalized_key `+order+`
+		LIMIT $4`,
+		participant, page.After, since, page.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []Entry{}
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, rows.Err()
+}
+
+// CountByKeyType returns the number of registered entries of each key type
+func (r *PostgresEntryRepository) CountByKeyType(ctx context.Context) (map[KeyType]int64, error) {
+	rows, err := r.pg.Pool.Query(ctx, `SELECT key_type, count(*) FROM entries GROUP BY key_type`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[KeyType]int64{}
+	for rows.Next() {
+		var keyType KeyType
+		var count int64
+		if err := rows.Scan(&keyType, &count); err != nil {
+			return nil, err
+		}
+		counts[keyType] = count
+	}
+	return counts, rows.Err()
+}
+
+// CountByParticipant returns the number of entries owned by participant
+func (r *PostgresEntryRepository) CountByParticipant(ctx context.Context, participant string) (int64, error) {
+	var count int64
+	err := r.pg.Pool.QueryRow(ctx,
+		`SELECT count(*) FROM entries WHERE account ->> 'participant' = $1`,
+		participant,
+	).Scan(&count)
+	return count, err
+}
+
+// DeleteByParticipant deletes every entry owned by participant
+func (r *PostgresEntryRepository) DeleteByParticipant(ctx context.Context, participant string) (int64, error) {
+	tag, err := r.pg.Pool.Exec(ctx,
+		`DELETE FROM entries WHERE account ->> 'participant' = $1`,
+		participant,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// DeleteByAccount deletes every entry linked to a participant's account and returns them
+func (r *PostgresEntryRepository) DeleteByAccount(ctx context.Context, participant, branch, accountNumber string) ([]Entry, error) {
+	rows, err := r.pg.Pool.Query(ctx,
+		`DELETE FROM entries
+		WHERE account ->> 'participant' = $1 AND account ->> 'branch' = $2 AND account ->> 'accountNumber' = $3
+		RETURNING `+entryColumns,
+		participant, branch, accountNumber,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []Entry{}
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, rows.Err()
+}
+
+// TransferAccount links every entry of a participant's account to the account to instead and returns them updated
+func (r *PostgresEntryRepository) TransferAccount(ctx context.Context, participant, branch, accountNumber string, to Account) ([]Entry, error) {
+	rows, err := r.pg.Pool.Query(ctx,
+		`UPDATE entries
+		SET account = $4::jsonb, updated_at = $5, sequence = sequence + 1
+		WHERE account ->> 'participant' = $1 AND account ->> 'branch' = $2 AND account ->> 'accountNumber' = $3
+		RETURNING `+entryColumns,
+		participant, branch, accountNumber, to, r.clock.Now(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []Entry{}
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, rows.Err()
+}
+
+// RewriteOwner stores owner's name, tax ID and blind index if the entry still has old's name and tax ID
+func (r *PostgresEntryRepository) RewriteOwner(ctx context.Context, key string, old, owner Owner) (bool, error) {
+	tag, err := r.pg.Pool.Exec(ctx,
+		`UPDATE entries
+		SET owner = owner || jsonb_build_object('name', $4::text, 'taxIdNumber', $5::text, 'taxIdHash', $6::text)
+		WHERE normalized_key = $1 AND owner ->> 'name' = $2 AND owner ->> 'taxIdNumber' = $3`,
+		NormalizeKey(key), old.Name, old.TaxIdNumber, owner.Name, owner.TaxIdNumber, owner.TaxIdHash,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// ReviewRFB reviews the active CPF and CNPJ entries not reviewed since reviewedBefore
+// Every reviewed entry is stamped, so the next run skips it; irregular ones also change status.
+func (r *PostgresEntryRepository) ReviewRFB(ctx context.Context, reviewedBefore time.Time, irregular func(owner Owner) bool) ([]Entry, error) {
+	rows, err := r.pg.Pool.Query(ctx,
+		`SELECT `+entryColumns+` FROM entries
+		WHERE key_type IN ($1, $2) AND status <> $3 AND (rfb_reviewed_at IS NULL OR rfb_reviewed_at < $4)`,
+		KeyTypeCPF, KeyTypeCNPJ, EntryStatusPendingRFBValidation, reviewedBefore,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewed := []string{}
+	irregularIDs := []string{}
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		reviewed = append(reviewed, entry.ID.Hex())
+		if irregular(entry.Owner) {
+			irregularIDs = append(irregularIDs, entry.ID.Hex())
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	now := r.clock.Now()
+	_, err = r.pg.Pool.Exec(ctx, `UPDATE entries SET rfb_reviewed_at = $2 WHERE id = ANY($1)`, reviewed, now)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = r.pg.Pool.Query(ctx,
+		`UPDATE entries SET status = $2, updated_at = $3, sequence = sequence + 1 WHERE id = ANY($1) RETURNING `+entryColumns,
+		irregularIDs, EntryStatusPendingRFBValidation, now,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	flagged := []Entry{}
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		flagged = append(flagged, *entry)
+	}
+	return flagged, rows.Err()
+}
@@ -0,0 +1,350 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/listing"
+)
+
+// Results recorded as entry.result on repository spans
+const (
+	resultHit      = "hit"      // the entry was found (and changed, for updates and deletes)
+	resultMiss     = "miss"     // no entry matched
+	resultCreated  = "created"  // the entry was stored
+	resultConflict = "conflict" // the key is already registered
+	resultOK       = "ok"
+	resultError    = "error"
+)
+
+// tracer resolves the global provider lazily, so spans are no-ops until telemetry is initialized
+var tracer = otel.Tracer("github.com/dict-simulator/go/internal/models")
+
+// TracedEntryRepository records a span per repository operation, named entry.<operation>
+// The database driver's own spans nest under these, so a trace shows which business operation
+// issued each command and how it ended (entry.result). Keys are personal data and are not recorded.
+type TracedEntryRepository struct {
+	repo EntryRepository
+}
+
+// NewTracedEntryRepository wraps repo; it should be the outermost wrapper so its spans cover the others
+func NewTracedEntryRepository(repo EntryRepository) *TracedEntryRepository {
+	return &TracedEntryRepository{repo: repo}
+}
+
+// Create records entry.create with the key type, participant and whether the key was free
+func (r *TracedEntryRepository) Create(ctx context.Context, req *CreateEntryRequest) (*Entry, error) {
+	ctx, span := tracer.Start(ctx, "entry.create", trace.WithAttributes(
+		attribute.String("entry.key_type", string(req.KeyType)),
+		attribute.String("entry.participant", req.Account.Participant),
+	))
+	defer span.End()
+
+	entry, err := r.repo.Create(ctx, req)
+	switch {
+	case errors.Is(err, ErrEntryKeyExists):
+		span.SetAttributes(attribute.String("entry.result", resultConflict))
+	case err != nil:
+		recordFailure(span, err)
+	default:
+		span.SetAttributes(attribute.String("entry.result", resultCreated))
+	}
+	return entry, err
+}
+
+// CreateMany records entry.create_many with how many of the entries were stored
+func (r *TracedEntryRepository) CreateMany(ctx context.Context, reqs []CreateEntryRequest) (int, error) {
+	ctx, span := tracer.Start(ctx, "entry.create_many", trace.WithAttributes(
+		attribute.Int("entry.requested", len(reqs)),
+	))
+	defer span.End()
+
+	created, err := r.repo.CreateMany(ctx, reqs)
+	if err != nil {
+		recordFailure(span, err)
+		return created, err
+	}
+	span.SetAttributes(
+		attribute.Int("entry.created", created),
+		attribute.String("entry.result", resultOK),
+	)
+	return created, nil
+}
+
+// InsertMany records entry.insert_many with how many of the entries were stored
+func (r *TracedEntryRepository) InsertMany(ctx context.Context, entries []Entry) (int, error) {
+	ctx, span := tracer.Start(ctx, "entry.insert_many", trace.WithAttributes(
+		attribute.Int("entry.requested", len(entries)),
+	))
+	defer span.End()
+
+	inserted, err := r.repo.InsertMany(ctx, entries)
+	if err != nil {
+		recordFailure(span, err)
+		return inserted, err
+	}
+	span.SetAttributes(
+		attribute.Int("entry.created", inserted),
+		attribute.String("entry.result", resultOK),
+	)
+	return inserted, nil
+}
+
+// FindByKey records entry.find_by_key as a hit or a miss
+func (r *TracedEntryRepository) FindByKey(ctx context.Context, key string) (*Entry, error) {
+	ctx, span := tracer.Start(ctx, "entry.find_by_key")
+	defer span.End()
+
+	entry, err := r.repo.FindByKey(ctx, key)
+	recordLookup(span, entry, err)
+	return entry, err
+}
+
+// FindByTaxID records entry.find_by_tax_id with the number of entries found
+// The tax ID is personal data, so it is never set on the span.
+func (r *TracedEntryRepository) FindByTaxID(ctx context.Context, taxID string) ([]Entry, error) {
+	ctx, span := tracer.Start(ctx, "entry.find_by_tax_id")
+	defer span.End()
+
+	entries, err := r.repo.FindByTaxID(ctx, taxID)
+	if err != nil {
+		recordFailure(span, err)
+		return nil, err
+	}
+	span.SetAttributes(
+		attribute.Int("entry.found", len(entries)),
+		attribute.String("entry.result", resultOK),
+	)
+	return entries, nil
+}
+
+// DeleteByKeyAndParticipant records entry.delete; a miss is an unknown key or another participant's entry
+func (r *TracedEntryRepository) DeleteByKeyAndParticipant(ctx context.Context, key string, participant string) (*Entry, error) {
+	ctx, span := tracer.Start(ctx, "entry.delete", trace.WithAttributes(
+		attribute.String("entry.participant", participant),
+	))
+	defer span.End()
+
+	entry, err := r.repo.DeleteByKeyAndParticipant(ctx, key, participant)
+	recordLookup(span, entry, err)
+	return entry, err
+}
+
+// UpdateByKeyAndParticipant records entry.update; a miss is an unknown key, an EVP entry or another participant's entry
+func (r *TracedEntryRepository) UpdateByKeyAndParticipant(ctx context.Context, key string, participant string, req *UpdateEntryRequest) (*Entry, error) {
+	ctx, span := tracer.Start(ctx, "entry.update", trace.WithAttributes(
+		attribute.String("entry.participant", participant),
+	))
+	defer span.End()
+
+	entry, err := r.repo.UpdateByKeyAndParticipant(ctx, key, participant, req)
+	recordLookup(span, entry, err)
+	return entry, err
+}
+
+// ForEachKey records entry.for_each_key with the number of keys visited
+func (r *TracedEntryRepository) ForEachKey(ctx context.Context, fn func(key string) error) error {
+	ctx, span := tracer.Start(ctx, "entry.for_each_key")
+	defer span.End()
+
+	visited := 0
+	err := r.repo.ForEachKey(ctx, func(key string) error {
+		visited++
+		return fn(key)
+	})
+	span.SetAttributes(attribute.Int("entry.visited", visited))
+	if err != nil {
+		recordFailure(span, err)
+		return err
+	}
+	span.SetAttributes(attribute.String("entry.result", resultOK))
+	return nil
+}
+
+// ForEachEntry records entry.for_each_entry with the number of entries visited
+func (r *TracedEntryRepository) ForEachEntry(ctx context.Context, fn func(entry *Entry) error) error {
+	ctx, span := tracer.Start(ctx, "entry.for_each_entry")
+	defer span.End()
+
+	visited := 0
+	err := r.repo.ForEachEntry(ctx, func(entry *Entry) error {
+		visited++
+		return fn(entry)
+	})
+	span.SetAttributes(attribute.Int("entry.visited", visited))
+	if err != nil {
+		recordFailure(span, err)
+		return err
+	}
+	span.SetAttributes(attribute.String("entry.result", resultOK))
+	return nil
+}
+
+// CountByKeyType records entry.count_by_key_type
+func (r *TracedEntryRepository) CountByKeyType(ctx context.Context) (map[KeyType]int64, error) {
+	ctx, span := tracer.Start(ctx, "entry.count_by_key_type")
+	defer span.End()
+
+	counts, err := r.repo.CountByKeyType(ctx)
+	if err != nil {
+		recordFailure(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("entry.result", resultOK))
+	return counts, nil
+}
+
+// CountByParticipant records entry.count_by_participant with the number of entries counted
+func (r *TracedEntryRepository) CountByParticipant(ctx context.Context, participant string) (int64, error) {
+	ctx, span := tracer.Start(ctx, "entry.count_by_participant", trace.WithAttributes(
+		attribute.String("entry.participant", participant),
+	))
+	defer span.End()
+
+	count, err := r.repo.CountByParticipant(ctx, participant)
+	if err != nil {
+		recordFailure(span, err)
+		return 0, err
+	}
+	span.SetAttributes(
+		attribute.Int64("entry.count", count),
+		attribute.String("entry.result", resultOK),
+	)
+	return count, nil
+}
+
+// DeleteByParticipant records entry.delete_by_participant with the number of entries deleted
+func (r *TracedEntryRepository) DeleteByParticipant(ctx context.Context, participant string) (int64, error) {
+	ctx, span := tracer.Start(ctx, "entry.delete_by_participant", trace.WithAttributes(
+		attribute.String("entry.participant", participant),
+	))
+	defer span.End()
+
+	deleted, err := r.repo.DeleteByParticipant(ctx, participant)
+	if err != nil {
+		recordFailure(span, err)
+		return 0, err
+	}
+	span.SetAttributes(
+		attribute.Int64("entry.deleted", deleted),
+		attribute.String("entry.result", resultOK),
+	)
+	return deleted, nil
+}
+
+// DeleteByAccount records entry.delete_by_account with the number of entries deleted
+func (r *TracedEntryRepository) DeleteByAccount(ctx context.Context, participant, branch, accountNumber string) ([]Entry, error) {
+	ctx, span := tracer.Start(ctx, "entry.delete_by_account", trace.WithAttributes(
+		attribute.String("entry.participant", participant),
+	))
+	defer span.End()
+
+	entries, err := r.repo.DeleteByAccount(ctx, participant, branch, accountNumber)
+	if err != nil {
+		recordFailure(span, err)
+		return nil, err
+	}
+	span.SetAttributes(
+		attribute.Int("entry.deleted", len(entries)),
+		attribute.String("entry.result", resultOK),
+	)
+	return entries, nil
+}
+
+// ListByParticipant records entry.list_by_participant with the number of entries listed
+func (r *TracedEntryRepository) ListByParticipant(ctx context.Context, participant string, since time.Time, page listing.Page) ([]Entry, error) {
+	ctx, span := tracer.Start(ctx, "entry.list_by_participant", trace.WithAttributes(
+		attribute.String("entry.participant", participant),
+	))
+	defer span.End()
+
+	entries, err := r.repo.ListByParticipant(ctx, participant, since, page)
+	if err != nil {
+		recordFailure(span, err)
+		return nil, err
+	}
+	span.SetAttributes(
+		attribute.Int("entry.listed", len(entries)),
+		attribute.String("entry.result", resultOK),
+	)
+	return entries, nil
+}
+
+// TransferAccount records entry.transfer_account with the number of entries moved
+func (r *TracedEntryRepository) TransferAccount(ctx context.Context, participant, branch, accountNumber string, to Account) ([]Entry, error) {
+	ctx, span := tracer.Start(ctx, "entry.transfer_account", trace.WithAttributes(
+		attribute.String("entry.participant", participant),
+	))
+	defer span.End()
+
+	entries, err := r.repo.TransferAccount(ctx, participant, branch, accountNumber, to)
+	if err != nil {
+		recordFailure(span, err)
+		return nil, err
+	}
+	span.SetAttributes(
+		attribute.Int("entry.transferred", len(entries)),
+		attribute.String("entry.result", resultOK),
+	)
+	return entries, nil
+}
+
+// ReviewRFB records entry.review_rfb with the number of entries flagged
+func (r *TracedEntryRepository) ReviewRFB(ctx context.Context, reviewedBefore time.Time, irregular func(owner Owner) bool) ([]Entry, error) {
+	ctx, span := tracer.Start(ctx, "entry.review_rfb")
+	defer span.End()
+
+	entries, err := r.repo.ReviewRFB(ctx, reviewedBefore, irregular)
+	if err != nil {
+		recordFailure(span, err)
+		return nil, err
+	}
+	span.SetAttributes(
+		attribute.Int("entry.flagged", len(entries)),
+		attribute.String("entry.result", resultOK),
+	)
+	return entries, nil
+}
+
+// RewriteOwner records entry.rewrite_owner
+func (r *TracedEntryRepository) RewriteOwner(ctx context.Context, key string, old, owner Owner) (bool, error) {
+	ctx, span := tracer.Start(ctx, "entry.rewrite_owner")
+	defer span.End()
+
+	rewritten, err := r.repo.RewriteOwner(ctx, key, old, owner)
+	if err != nil {
+		recordFailure(span, err)
+		return false, err
+	}
+	span.SetAttributes(attribute.String("entry.result", resultOK))
+	return rewritten, nil
+}
+
+// recordLookup sets the result of an operation on a single entry, and the entry's type and owner on a hit
+func recordLookup(span trace.Span, entry *Entry, err error) {
+	switch {
+	case err != nil:
+		recordFailure(span, err)
+	case entry == nil:
+		span.SetAttributes(attribute.String("entry.result", resultMiss))
+	default:
+		span.SetAttributes(
+			attribute.String("entry.result", resultHit),
+			attribute.String("entry.key_type", string(entry.KeyType)),
+			attribute.String("entry.participant", entry.Account.Participant),
+		)
+	}
+}
+
+// recordFailure marks the span as failed
+func recordFailure(span trace.Span, err error) {
+	span.SetAttributes(attribute.String("entry.result", resultError))
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
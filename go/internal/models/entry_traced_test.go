@@ -0,0 +1,71 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/dict-simulator/go/internal/clock"
+)
+
+func TestTracedEntryRepositorySpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	repo := NewTracedEntryRepository(NewMemoryEntryRepository(clock.NewSimulated()))
+	ctx := context.Background()
+	req := &CreateEntryRequest{
+		Key:     "52998224725",
+		KeyType: KeyTypeCPF,
+		Account: Account{Participant: "12345678"},
+	}
+
+	if _, err := repo.Create(ctx, req); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := repo.Create(ctx, req); !errors.Is(err, ErrEntryKeyExists) {
+		t.Fatalf("second Create() error = %v, want ErrEntryKeyExists", err)
+	}
+	repo.FindByKey(ctx, req.Key)
+	repo.FindByKey(ctx, "unknown@example.com")
+	repo.DeleteByKeyAndParticipant(ctx, req.Key, "87654321")
+
+	want := []struct {
+		name   string
+		result string
+	}{
+		{"entry.create", resultCreated},
+		{"entry.create", resultConflict},
+		{"entry.find_by_key", resultHit},
+		{"entry.find_by_key", resultMiss},
+		{"entry.delete", resultMiss},
+	}
+	spans := recorder.Ended()
+	if len(spans) != len(want) {
+		t.Fatalf("recorded %d spans, want %d", len(spans), len(want))
+	}
+	for i, w := range want {
+		attrs := attribute.NewSet(spans[i].Attributes()...)
+		result, _ := attrs.Value("entry.result")
+		if spans[i].Name() != w.name || result.AsString() != w.result {
+			t.Errorf("span %d = %s %s, want %s %s", i, spans[i].Name(), result.AsString(), w.name, w.result)
+		}
+	}
+
+	hit := attribute.NewSet(spans[2].Attributes()...)
+	if keyType, _ := hit.Value("entry.key_type"); keyType.AsString() != string(KeyTypeCPF) {
+		t.Errorf("hit entry.key_type = %q, want CPF", keyType.AsString())
+	}
+	for _, span := range spans {
+		for _, kv := range span.Attributes() {
+			if kv.Value.AsString() == req.Key {
+				t.Errorf("%s records the key in %s", span.Name(), kv.Key)
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Kinds of failure reported by repositories and handlers, whatever the transport
+// Errors of a kind match it with errors.Is; errors.As to *Error tells what they concern.
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrDuplicateKey  = errors.New("duplicate key")
+	ErrLocked        = errors.New("locked")
+	ErrLimitExceeded = errors.New("limit exceeded")
+)
+
+// Resources domain errors concern
+const (
+	ResourceEntry              = "entry"
+	ResourceUser               = "user"
+	ResourceAccount            = "account"
+	ResourceSettlement         = "settlement"
+	ResourceReconciliationFile = "reconciliation file"
+	ResourceOwner              = "owner"
+	ResourceRequestID          = "request ID"
+	ResourceParticipant        = "participant"
+)
+
+// Error is a failure of one Kind concerning one Resource
+type Error struct {
+	Kind     error
+	Resource string
+	// RetryAfter is how long an ErrLocked or ErrLimitExceeded resource stays refused, when known
+	RetryAfter time.Duration
+}
+
+// NotFound returns an ErrNotFound error for resource
+func NotFound(resource string) error {
+	return &Error{Kind: ErrNotFound, Resource: resource}
+}
+
+// Locked returns an ErrLocked error for resource, which unlocks after retryAfter
+func Locked(resource string, retryAfter time.Duration) error {
+	return &Error{Kind: ErrLocked, Resource: resource, RetryAfter: retryAfter}
+}
+
+// LimitExceeded returns an ErrLimitExceeded error for resource, whose limit resets after retryAfter
+func LimitExceeded(resource string, retryAfter time.Duration) error {
+	return &Error{Kind: ErrLimitExceeded, Resource: resource, RetryAfter: retryAfter}
+}
+
+func (e *Error) Error() string {
+	return e.Resource + ": " + e.Kind.Error()
+}
+
+// Unwrap returns the kind, so errors.Is(err, ErrNotFound) matches every resource
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// Is matches any *Error of the same kind and resource, so sentinels like ErrEntryKeyExists also
+// match errors built elsewhere
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind && t.Resource == e.Resource
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorMatchesKindAndResource(t *testing.T) {
+	err := fmt.Errorf("create: %w", &Error{Kind: ErrDuplicateKey, Resource: ResourceEntry})
+
+	if !errors.Is(err, ErrDuplicateKey) {
+		t.Error("error does not match its kind")
+	}
+	if !errors.Is(err, ErrEntryKeyExists) {
+		t.Error("error does not match the sentinel of its kind and resource")
+	}
+	if errors.Is(err, ErrUserEmailExists) {
+		t.Error("error matches the sentinel of another resource")
+	}
+	if errors.Is(err, ErrEntryNotFound) {
+		t.Error("error matches the sentinel of another kind")
+	}
+
+	var domainErr *Error
+	if !errors.As(err, &domainErr) || domainErr.Resource != ResourceEntry {
+		t.Errorf("errors.As = %v, want the entry error", domainErr)
+	}
+}
@@ -0,0 +1,149 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/dict-simulator/go/internal/db"
+)
+
+// FraudMarker records that a key was deleted for fraud, so it can be flagged if it comes back
+// It outlives the entry: markers stay after the key is released or registered again.
+type FraudMarker struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	Key           string             `bson:"key"`
+	NormalizedKey string             `bson:"normalizedKey"`
+	KeyType       KeyType            `bson:"keyType"`
+	TaxIdNumber   string             `bson:"taxIdNumber"`
+	TaxIdHash     string             `bson:"taxIdHash,omitempty"` // Blind index of a sealed tax ID, which DeleteByTaxID matches; empty in plaintext
+	Participant   string             `bson:"participant"`
+	CreatedAt     time.Time          `bson:"createdAt"`
+}
+
+// FraudMarkerResponse represents the API response for a fraud marker
+type FraudMarkerResponse struct {
+	Key         string    `json:"key" example:"+5511999999999"`
+	KeyType     KeyType   `json:"keyType" example:"PHONE"`
+	TaxIdNumber string    `json:"taxIdNumber" example:"12345678901"`
+	Participant string    `json:"participant" example:"12345678"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// NewFraudMarker builds the marker left by deleting entry for fraud
+func NewFraudMarker(entry *Entry, now time.Time) *FraudMarker {
+	return &FraudMarker{
+		Key:           entry.Key,
+		NormalizedKey: NormalizeKey(entry.Key),
+		KeyType:       entry.KeyType,
+		TaxIdNumber:   entry.Owner.TaxIdNumber,
+		Participant:   entry.Account.Participant,
+		CreatedAt:     now,
+	}
+}
+
+// FraudMarkerRepository handles storage operations for fraud markers
+type FraudMarkerRepository interface {
+	// Create stores a fraud marker
+	Create(ctx context.Context, marker *FraudMarker) error
+	// FindByKey returns the markers left on a key, newest first
+	FindByKey(ctx context.Context, key string) ([]FraudMarker, error)
+	// DeleteByTaxID deletes the markers left on the entries of an owner, whose tax ID or its blind index is taxID,
+	// and returns how many
+	DeleteByTaxID(ctx context.Context, taxID string) (int64, error)
+	// DeleteCreatedBefore deletes the markers created at or before cutoff and returns how many
+	DeleteCreatedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// MongoFraudMarkerRepository stores fraud markers in the fraud_markers collection
+type MongoFraudMarkerRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoFraudMarkerRepository creates a new MongoDB-backed fraud marker repository
+func NewMongoFraudMarkerRepository(db *db.Mongo) *MongoFraudMarkerRepository {
+	return &MongoFraudMarkerRepository{
+		collection: db.Collection("fraud_markers"),
+	}
+}
+
+// IndexSpec declares the indexes of the fraud_markers collection
+func (r *MongoFraudMarkerRepository) IndexSpec() db.IndexSpec {
+	return db.IndexSpec{
+		Collection: r.collection.Name(),
+		Indexes: []mongo.IndexModel{
+			{Keys: bson.D{{Key: "normalizedKey", Value: 1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "createdAt", Value: 1}}},
+		},
+	}
+}
+
+// EnsureIndexes creates the indexes of the fraud_markers collection
+func (r *MongoFraudMarkerRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, r.IndexSpec().Indexes)
+	return err
+}
+
+// Create stores a fraud marker
+func (r *MongoFraudMarkerRepository) Create(ctx context.Context, marker *FraudMarker) error {
+	result, err := r.collection.InsertOne(ctx, marker)
+	if err != nil {
+		return err
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		marker.ID = oid
+	}
+	return nil
+}
+
+// FindByKey returns the markers left on a key, newest first
+func (r *MongoFraudMarkerRepository) FindByKey(ctx context.Context, key string) ([]FraudMarker, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"normalizedKey": NormalizeKey(key)}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	markers := []FraudMarker{}
+	if err := cursor.All(ctx, &markers); err != nil {
+		return nil, err
+	}
+	return markers, nil
+}
+
+// DeleteByTaxID deletes the markers left on the entries of an owner
+func (r *MongoFraudMarkerRepository) DeleteByTaxID(ctx context.Context, taxID string) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"$or": bson.A{
+		bson.M{"taxIdNumber": taxID},
+		bson.M{"taxIdHash": taxID},
+	}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// DeleteCreatedBefore deletes the markers created at or before cutoff
+func (r *MongoFraudMarkerRepository) DeleteCreatedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"createdAt": bson.M{"$lte": cutoff.UTC()}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// ToResponse converts FraudMarker to FraudMarkerResponse
+func (m *FraudMarker) ToResponse() FraudMarkerResponse {
+	return FraudMarkerResponse{
+		Key:         m.Key,
+		KeyType:     m.KeyType,
+		TaxIdNumber: m.TaxIdNumber,
+		Participant: m.Participant,
+		CreatedAt:   m.CreatedAt,
+	}
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryFraudMarkerRepository keeps fraud markers in process memory (STORAGE=memory)
+type MemoryFraudMarkerRepository struct {
+	mu      sync.RWMutex
+	markers map[string][]FraudMarker // by normalized key, oldest first
+}
+
+// NewMemoryFraudMarkerRepository creates a new in-memory fraud marker repository
+func NewMemoryFraudMarkerRepository() *MemoryFraudMarkerRepository {
+	return &MemoryFraudMarkerRepository{
+		markers: map[string][]FraudMarker{},
+	}
+}
+
+// Create stores a fraud marker
+func (r *MemoryFraudMarkerRepository) Create(ctx context.Context, marker *FraudMarker) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	marker.ID = primitive.NewObjectID()
+	r.markers[marker.NormalizedKey] = append(r.markers[marker.NormalizedKey], *marker)
+	return nil
+}
+
+// FindByKey returns the markers left on a key, newest first
+func (r *MemoryFraudMarkerRepository) FindByKey(ctx context.Context, key string) ([]FraudMarker, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	markers := slices.Clone(r.markers[NormalizeKey(key)])
+	slices.Reverse(markers)
+	if markers == nil {
+		markers = []FraudMarker{}
+	}
+	return markers, nil
+}
+
+// DeleteByTaxID deletes the markers left on the entries of an owner
+func (r *MemoryFraudMarkerRepository) DeleteByTaxID(ctx context.Context, taxID string) (int64, error) {
+	return r.deleteWhere(func(marker FraudMarker) bool {
+		return marker.TaxIdNumber == taxID || marker.TaxIdHash == taxID
+	}), nil
+}
+
+// DeleteCreatedBefore deletes the markers created at or before cutoff
+func (r *MemoryFraudMarkerRepository) DeleteCreatedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return r.deleteWhere(func(marker FraudMarker) bool {
+		return !marker.CreatedAt.After(cutoff)
+	}), nil
+}
+
+// deleteWhere deletes the markers match selects and returns how many
+func (r *MemoryFraudMarkerRepository) deleteWhere(match func(marker FraudMarker) bool) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for key, markers := range r.markers {
+		kept := slices.DeleteFunc(markers, match)
+		deleted += int64(len(markers) - len(kept))
+		if len(kept) == 0 {
+			delete(r.markers, key)
+		} else {
+			r.markers[key] = kept
+		}
+	}
+	return deleted
+}
@@ -2,56 +2,107 @@ package models
 
 import (
 	"context"
+	"regexp"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"github.com/dict-simulator/go/internal/clock"
 	"github.com/dict-simulator/go/internal/db"
 )
 
+// IdempotencyTTL is how long a claim or a stored successful response can be replayed
+// Save may keep other responses for less (see middleware.IdempotencyPolicy).
+const IdempotencyTTL = 24 * time.Hour
+
 // IdempotencyRecord represents a stored idempotent request response
 type IdempotencyRecord struct {
-	Key        string    `bson:"key"`
-	Response   string    `bson:"response"` // Store as raw JSON string to preserve format
-	StatusCode int       `bson:"statusCode"`
-	CreatedAt  time.Time `bson:"createdAt"`
+	Key         string    `bson:"key"`
+	Participant string    `bson:"participant,omitempty"` // who claimed the key (X-Participant-Id); kept when the response is saved
+	Response    string    `bson:"response"`              // Store as raw JSON string to preserve format
+	StatusCode  int       `bson:"statusCode"`
+	CreatedAt   time.Time `bson:"createdAt"`
+	ExpiresAt   time.Time `bson:"expiresAt"` // by the injected clock; the record is treated as absent from then on
+}
+
+// IdempotencyRepository handles storage operations for idempotency records
+// Records past their ExpiresAt (by the injected clock) are treated as absent.
+type IdempotencyRepository interface {
+	// FindByKey finds an unexpired record, or returns (nil, nil)
+	FindByKey(ctx context.Context, key string) (*IdempotencyRecord, error)
+	// ClaimKey atomically claims a key for participant; when it is already taken the existing record is returned
+	ClaimKey(ctx context.Context, key string, participant string) (bool, *IdempotencyRecord, error)
+	// Save stores the response for a claimed key, to be replayed for ttl
+	Save(ctx context.Context, key string, response string, statusCode int, ttl time.Duration) error
+	// Release deletes a claim whose response won't be saved, so a retry with the key runs again.
+	// Saved responses are left alone.
+	Release(ctx context.Context, key string) error
+	// DeleteUnfinished deletes claims created at or before cutoff whose response was never saved
+	// (StatusCode 0, e.g. the process died mid-request), so the key can be used again.
+	// Returns the number of records deleted.
+	DeleteUnfinished(ctx context.Context, cutoff time.Time) (int64, error)
+	// DeleteExpired deletes records that stopped being replayed at or before cutoff and returns how many
+	DeleteExpired(ctx context.Context, cutoff time.Time) (int64, error)
+	// DeleteMentioning deletes the records whose saved response contains text, finished or not,
+	// and returns the number of records deleted
+	DeleteMentioning(ctx context.Context, text string) (int64, error)
+	// DeleteByParticipant deletes every record claimed by participant, finished or not,
+	// and returns the number of records deleted
+	DeleteByParticipant(ctx context.Context, participant string) (int64, error)
 }
 
-// IdempotencyRepository handles database operations for idempotency records
-type IdempotencyRepository struct {
+// MongoIdempotencyRepository stores idempotency records in the idempotency collection
+type MongoIdempotencyRepository struct {
 	collection *mongo.Collection
+	clock      clock.Clock
 }
 
-// NewIdempotencyRepository creates a new idempotency repository
-func NewIdempotencyRepository(db *db.Mongo) *IdempotencyRepository {
-	return &IdempotencyRepository{
+// NewMongoIdempotencyRepository creates a new MongoDB-backed idempotency repository
+func NewMongoIdempotencyRepository(db *db.Mongo, clk clock.Clock) *MongoIdempotencyRepository {
+	return &MongoIdempotencyRepository{
 		collection: db.Collection("idempotency"),
+		clock:      clk,
 	}
 }
 
-// EnsureIndexes creates necessary indexes for the idempotency collection
-func (r *IdempotencyRepository) EnsureIndexes(ctx context.Context) error {
-	indexes := []mongo.IndexModel{
-		{
-			Keys:    bson.D{{Key: "key", Value: 1}},
-			Options: options.Index().SetUnique(true),
-		},
-		{
-			Keys:    bson.D{{Key: "createdAt", Value: 1}},
-			Options: options.Index().SetExpireAfterSeconds(86400), // TTL: 24 hours
+// IndexSpec declares the indexes of the idempotency collection
+func (r *MongoIdempotencyRepository) IndexSpec() db.IndexSpec {
+	return db.IndexSpec{
+		Collection: r.collection.Name(),
+		Indexes: []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "key", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				Keys: bson.D{{Key: "createdAt", Value: 1}},
+				// TTL index only garbage-collects by wall clock, once no record can still be live;
+				// expiry itself is enforced against the injected clock in FindByKey/ClaimKey
+				Options: options.Index().SetExpireAfterSeconds(int32(IdempotencyTTL.Seconds())),
+			},
+			{
+				Keys: bson.D{{Key: "participant", Value: 1}},
+			},
 		},
 	}
+}
 
-	_, err := r.collection.Indexes().CreateMany(ctx, indexes)
+// EnsureIndexes creates the indexes of the idempotency collection
+func (r *MongoIdempotencyRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, r.IndexSpec().Indexes)
 	return err
 }
 
 // FindByKey finds an existing idempotency record
-func (r *IdempotencyRepository) FindByKey(ctx context.Context, key string) (*IdempotencyRecord, error) {
+func (r *MongoIdempotencyRepository) FindByKey(ctx context.Context, key string) (*IdempotencyRecord, error) {
 	var record IdempotencyRecord
-	err := r.collection.FindOne(ctx, bson.M{"key": key}).Decode(&record)
+	filter := bson.M{
+		"key":       key,
+		"expiresAt": bson.M{"$gt": r.clock.Now().UTC()},
+	}
+	err := r.collection.FindOne(ctx, filter).Decode(&record)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, nil
@@ -64,7 +115,7 @@ func (r *IdempotencyRepository) FindByKey(ctx context.Context, key string) (*Ide
 // ClaimKey attempts to atomically claim an idempotency key
 // Returns (true, nil, nil) if claimed (newly inserted)
 // Returns (false, record, nil) if already exists
-func (r *IdempotencyRepository) ClaimKey(ctx context.Context, key string) (bool, *IdempotencyRecord, error) {
+func (r *MongoIdempotencyRepository) ClaimKey(ctx context.Context, key string, participant string) (bool, *IdempotencyRecord, error) {
 	// First, check if a completed record exists
 	record, err := r.FindByKey(ctx, key)
 	if err == nil && record != nil {
@@ -75,10 +126,23 @@ func (r *IdempotencyRepository) ClaimKey(ctx context.Context, key string) (bool,
 		return false, nil, err
 	}
 
+	// Drop an expired record the TTL monitor hasn't removed yet so the key can be claimed again
+	// ($not also matches records saved before expiresAt was stored)
+	now := r.clock.Now().UTC()
+	_, err = r.collection.DeleteOne(ctx, bson.M{
+		"key":       key,
+		"expiresAt": bson.M{"$not": bson.M{"$gt": now}},
+	})
+	if err != nil {
+		return false, nil, err
+	}
+
 	record = &IdempotencyRecord{
-		Key:        key,
-		StatusCode: 0,
-		CreatedAt:  time.Now().UTC(),
+		Key:         key,
+		Participant: participant,
+		StatusCode:  0,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(IdempotencyTTL),
 	}
 
 	filter := bson.M{"key": key}
@@ -104,12 +168,14 @@ func (r *IdempotencyRepository) ClaimKey(ctx context.Context, key string) (bool,
 }
 
 // Save saves or updates an idempotency record
-func (r *IdempotencyRepository) Save(ctx context.Context, key string, response string, statusCode int) error {
+func (r *MongoIdempotencyRepository) Save(ctx context.Context, key string, response string, statusCode int, ttl time.Duration) error {
+	now := r.clock.Now().UTC()
 	record := IdempotencyRecord{
 		Key:        key,
 		Response:   response,
 		StatusCode: statusCode,
-		CreatedAt:  time.Now().UTC(),
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(ttl),
 	}
 
 	opts := options.Update().SetUpsert(true)
@@ -121,3 +187,48 @@ func (r *IdempotencyRepository) Save(ctx context.Context, key string, response s
 	)
 	return err
 }
+
+// Release deletes the claim on key unless its response was saved
+func (r *MongoIdempotencyRepository) Release(ctx context.Context, key string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"key": key, "statusCode": 0})
+	return err
+}
+
+// DeleteUnfinished deletes claims created at or before cutoff whose response was never saved
+func (r *MongoIdempotencyRepository) DeleteUnfinished(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{
+		"statusCode": 0,
+		"createdAt":  bson.M{"$lte": cutoff.UTC()},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// DeleteExpired deletes records that expired at or before cutoff
+func (r *MongoIdempotencyRepository) DeleteExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"expiresAt": bson.M{"$lte": cutoff.UTC()}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// DeleteMentioning deletes the records whose saved response contains text
+func (r *MongoIdempotencyRepository) DeleteMentioning(ctx context.Context, text string) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"response": bson.M{"$regex": regexp.QuoteMeta(text)}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// DeleteByParticipant deletes every record claimed by participant
+func (r *MongoIdempotencyRepository) DeleteByParticipant(ctx context.Context, participant string) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"participant": participant})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
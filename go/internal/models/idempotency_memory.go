@@ -0,0 +1,152 @@
+package models
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dict-simulator/go/internal/clock"
+)
+
+// MemoryIdempotencyRepository keeps idempotency records in process memory (STORAGE=memory)
+// Expired records are never swept; they are ignored and replaced when the key is claimed again.
+type MemoryIdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[string]IdempotencyRecord
+	clock   clock.Clock
+}
+
+// NewMemoryIdempotencyRepository creates a new in-memory idempotency repository
+func NewMemoryIdempotencyRepository(clk clock.Clock) *MemoryIdempotencyRepository {
+	return &MemoryIdempotencyRepository{
+		records: map[string]IdempotencyRecord{},
+		clock:   clk,
+	}
+}
+
+// find returns the unexpired record for key; the caller must hold r.mu
+func (r *MemoryIdempotencyRepository) find(key string) *IdempotencyRecord {
+	record, ok := r.records[key]
+	if !ok || !record.ExpiresAt.After(r.clock.Now().UTC()) {
+		return nil
+	}
+	return &record
+}
+
+// FindByKey finds an existing idempotency record
+func (r *MemoryIdempotencyRepository) FindByKey(ctx context.Context, key string) (*IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.find(key), nil
+}
+
+// ClaimKey attempts to atomically claim an idempotency key
+// Returns (true, nil, nil) if claimed (newly inserted)
+// Returns (false, record, nil) if already exists
+func (r *MemoryIdempotencyRepository) ClaimKey(ctx context.Context, key string, participant string) (bool, *IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if record := r.find(key); record != nil {
+		return false, record, nil
+	}
+
+	now := r.clock.Now().UTC()
+	r.records[key] = IdempotencyRecord{
+		Key:         key,
+		Participant: participant,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(IdempotencyTTL),
+	}
+	return true, nil, nil
+}
+
+// Save saves or updates an idempotency record
+func (r *MemoryIdempotencyRepository) Save(ctx context.Context, key string, response string, statusCode int, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now().UTC()
+	r.records[key] = IdempotencyRecord{
+		Key:         key,
+		Participant: r.records[key].Participant,
+		Response:    response,
+		StatusCode:  statusCode,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+	}
+	return nil
+}
+
+// Release deletes the claim on key unless its response was saved
+func (r *MemoryIdempotencyRepository) Release(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if record, ok := r.records[key]; ok && record.StatusCode == 0 {
+		delete(r.records, key)
+	}
+	return nil
+}
+
+// DeleteUnfinished deletes claims created at or before cutoff whose response was never saved
+func (r *MemoryIdempotencyRepository) DeleteUnfinished(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for key, record := range r.records {
+		if record.StatusCode == 0 && !record.CreatedAt.After(cutoff) {
+			delete(r.records, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// DeleteExpired deletes records that expired at or before cutoff
+func (r *MemoryIdempotencyRepository) DeleteExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for key, record := range r.records {
+		if !record.ExpiresAt.After(cutoff) {
+			delete(r.records, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// DeleteMentioning deletes the records whose saved response contains text
+func (r *MemoryIdempotencyRepository) DeleteMentioning(ctx context.Context, text string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for key, record := range r.records {
+		if strings.Contains(record.Response, text) {
+			delete(r.records, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// DeleteByParticipant deletes every record claimed by participant
+func (r *MemoryIdempotencyRepository) DeleteByParticipant(ctx context.Context, participant string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for key, record := range r.records {
+		if record.Participant == participant {
+			delete(r.records, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
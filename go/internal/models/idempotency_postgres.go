@@ -0,0 +1,136 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/db"
+)
+
+// PostgresIdempotencyRepository stores idempotency records in the idempotency table
+type PostgresIdempotencyRepository struct {
+	pg    *db.Postgres
+	clock clock.Clock
+}
+
+// NewPostgresIdempotencyRepository creates a new PostgreSQL-backed idempotency repository
+func NewPostgresIdempotencyRepository(pg *db.Postgres, clk clock.Clock) *PostgresIdempotencyRepository {
+	return &PostgresIdempotencyRepository{
+		pg:    pg,
+		clock: clk,
+	}
+}
+
+// FindByKey finds an existing idempotency record
+func (r *PostgresIdempotencyRepository) FindByKey(ctx context.Context, key string) (*IdempotencyRecord, error) {
+	return scanIdempotencyRecord(r.pg.Pool.QueryRow(ctx,
+		`SELECT key, participant, response, status_code, created_at, expires_at FROM idempotency WHERE key = $1 AND expires_at > $2`,
+		key, r.clock.Now().UTC(),
+	))
+}
+
+// ClaimKey attempts to atomically claim an idempotency key
+// Returns (true, nil, nil) if claimed (newly inserted)
+// Returns (false, record, nil) if already exists
+func (r *PostgresIdempotencyRepository) ClaimKey(ctx context.Context, key string, participant string) (bool, *IdempotencyRecord, error) {
+	now := r.clock.Now().UTC()
+
+	// Insert, or take over a record that expired; a live record is left untouched
+	tag, err := r.pg.Pool.Exec(ctx,
+		`INSERT INTO idempotency (key, participant, response, status_code, created_at, expires_at) VALUES ($1, $2, '', 0, $3, $4)
+		ON CONFLICT (key) DO UPDATE SET participant = EXCLUDED.participant, response = '', status_code = 0, created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+		WHERE idempotency.expires_at <= $3`,
+		key, participant, now, now.Add(IdempotencyTTL),
+	)
+	if err != nil {
+		return false, nil, err
+	}
+	if tag.RowsAffected() == 1 {
+		return true, nil, nil
+	}
+
+	record, err := r.FindByKey(ctx, key)
+	if err != nil {
+		return false, nil, err
+	}
+	return false, record, nil
+}
+
+// Save saves or updates an idempotency record
+func (r *PostgresIdempotencyRepository) Save(ctx context.Context, key string, response string, statusCode int, ttl time.Duration) error {
+	now := r.clock.Now().UTC()
+	_, err := r.pg.Pool.Exec(ctx,
+		`INSERT INTO idempotency (key, response, status_code, created_at, expires_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (key) DO UPDATE SET response = EXCLUDED.response, status_code = EXCLUDED.status_code, created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at`,
+		key, response, statusCode, now, now.Add(ttl),
+	)
+	return err
+}
+
+// Release deletes the claim on key unless its response was saved
+func (r *PostgresIdempotencyRepository) Release(ctx context.Context, key string) error {
+	_, err := r.pg.Pool.Exec(ctx,
+		`DELETE FROM idempotency WHERE key = $1 AND status_code = 0`,
+		key,
+	)
+	return err
+}
+
+// scanIdempotencyRecord reads a single idempotency row
+func scanIdempotencyRecord(row pgx.Row) (*IdempotencyRecord, error) {
+	var record IdempotencyRecord
+	err := row.Scan(&record.Key, &record.Participant, &record.Response, &record.StatusCode, &record.CreatedAt, &record.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+// DeleteUnfinished deletes claims created at or before cutoff whose response was never saved
+func (r *PostgresIdempotencyRepository) DeleteUnfinished(ctx context.Context, cutoff time.Time) (int64, error) {
+	tag, err := r.pg.Pool.Exec(ctx,
+		`DELETE FROM idempotency WHERE status_code = 0 AND created_at <= $1`,
+		cutoff.UTC(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// DeleteExpired deletes records that expired at or before cutoff
+func (r *PostgresIdempotencyRepository) DeleteExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	tag, err := r.pg.Pool.Exec(ctx, `DELETE FROM idempotency WHERE expires_at <= $1`, cutoff.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// DeleteMentioning deletes the records whose saved response contains text
+func (r *PostgresIdempotencyRepository) DeleteMentioning(ctx context.Context, text string) (int64, error) {
+	tag, err := r.pg.Pool.Exec(ctx, `DELETE FROM idempotency WHERE strpos(response, $1) > 0`, text)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// DeleteByParticipant deletes every record claimed by participant
+func (r *PostgresIdempotencyRepository) DeleteByParticipant(ctx context.Context, participant string) (int64, error) {
+	tag, err := r.pg.Pool.Exec(ctx,
+		`DELETE FROM idempotency WHERE participant = $1`,
+		participant,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
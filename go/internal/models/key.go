@@ -0,0 +1,21 @@
+package models
+
+import "strings"
+
+// phoneFormatting is stripped from phone keys: "+55 (11) 99999-9999" is "+5511999999999"
+var phoneFormatting = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "")
+
+// NormalizeKey returns the canonical form of a key, under which entries are stored and looked up
+// Surrounding whitespace is trimmed, emails are lowercased and phones (which start with +) lose
+// their formatting. Other keys are only trimmed; EVPs keep their dashes.
+func NormalizeKey(key string) string {
+	key = strings.TrimSpace(key)
+	switch {
+	case strings.Contains(key, "@"):
+		return strings.ToLower(key)
+	case strings.HasPrefix(key, "+"):
+		return phoneFormatting.Replace(key)
+	default:
+		return key
+	}
+}
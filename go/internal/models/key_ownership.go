@@ -0,0 +1,215 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/dict-simulator/go/internal/db"
+)
+
+// KeyOwnership is a period during which a key was held by one participant for one owner
+// A period is current until To is set: when the key is deleted, or moves to another participant or owner.
+// Like fraud markers, periods outlive the entry.
+type KeyOwnership struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	Key           string             `bson:"key"`
+	NormalizedKey string             `bson:"normalizedKey"`
+	KeyType       KeyType            `bson:"keyType"`
+	Participant   string             `bson:"participant"`
+	Owner         Owner              `bson:"owner"` // Name and tax ID sealed with PII_ENCRYPTION_KEYS
+	From          time.Time          `bson:"from"`
+	To            *time.Time         `bson:"to,omitempty"`
+}
+
+// KeyOwnershipResponse represents the API response for a period of a key's ownership
+type KeyOwnershipResponse struct {
+	Key         string     `json:"key" example:"+5511999999999"`
+	KeyType     KeyType    `json:"keyType" example:"PHONE"`
+	Participant string     `json:"participant" example:"12345678"`
+	Owner       Owner      `json:"owner"`
+	From        time.Time  `json:"from"`
+	To          *time.Time `json:"to,omitempty"` // absent while the period is current
+}
+
+// NewKeyOwnership builds the period starting at from in which entry's participant holds its key
+func NewKeyOwnership(entry EntryResponse, from time.Time) *KeyOwnership {
+	return &KeyOwnership{
+		Key:           entry.Key,
+		NormalizedKey: NormalizeKey(entry.Key),
+		KeyType:       entry.KeyType,
+		Participant:   entry.Account.Participant,
+		Owner:         entry.Owner,
+		From:          from.UTC(),
+	}
+}
+
+// KeyOwnershipRepository handles storage operations for the ownership history of keys
+type KeyOwnershipRepository interface {
+	// Open stores a period, which stays current until Close ends it
+	Open(ctx context.Context, period *KeyOwnership) error
+	// FindCurrent returns the current period of a key, or (nil, nil) when there is none
+	FindCurrent(ctx context.Context, key string) (*KeyOwnership, error)
+	// Close ends the current period of a key at at; a key without one is left alone
+	Close(ctx context.Context, key string, at time.Time) error
+	// FindByKey returns the periods of a key, newest first
+	FindByKey(ctx context.Context, key string) ([]KeyOwnership, error)
+	// DeleteByTaxID deletes the periods in which an owner, whose tax ID or its blind index is taxID, held keys
+	// and returns how many
+	DeleteByTaxID(ctx context.Context, taxID string) (int64, error)
+	// ForEachPeriod calls fn with every period, stopping at the first error
+	ForEachPeriod(ctx context.Context, fn func(period *KeyOwnership) error) error
+	// RewriteOwner stores owner's name, tax ID and blind index on the period of key with id if its name and
+	// tax ID still are old's, and reports whether it did
+	RewriteOwner(ctx context.Context, key string, id primitive.ObjectID, old, owner Owner) (bool, error)
+}
+
+// MongoKeyOwnershipRepository stores ownership periods in the key_ownership collection
+type MongoKeyOwnershipRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoKeyOwnershipRepository creates a new MongoDB-backed key ownership repository
+func NewMongoKeyOwnershipRepository(db *db.Mongo) *MongoKeyOwnershipRepository {
+	return &MongoKeyOwnershipRepository{
+		collection: db.Collection("key_ownership"),
+	}
+}
+
+// IndexSpec declares the indexes of the key_ownership collection
+func (r *MongoKeyOwnershipRepository) IndexSpec() db.IndexSpec {
+	return db.IndexSpec{
+		Collection: r.collection.Name(),
+		Indexes: []mongo.IndexModel{
+			{Keys: bson.D{{Key: "normalizedKey", Value: 1}, {Key: "from", Value: -1}}},
+			{Keys: bson.D{{Key: "owner.taxIdNumber", Value: 1}}},
+			{
+				// Owners sealed at rest (PII_ENCRYPTION_KEYS) are erased by the blind index of their tax ID
+				Keys: bson.D{{Key: "owner.taxIdHash", Value: 1}},
+				Options: options.Index().SetPartialFilterExpression(bson.M{
+					"owner.taxIdHash": bson.M{"$exists": true},
+				}),
+			},
+		},
+	}
+}
+
+// EnsureIndexes creates the indexes of the key_ownership collection
+func (r *MongoKeyOwnershipRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, r.IndexSpec().Indexes)
+	return err
+}
+
+// Open stores a period
+func (r *MongoKeyOwnershipRepository) Open(ctx context.Context, period *KeyOwnership) error {
+	result, err := r.collection.InsertOne(ctx, period)
+	if err != nil {
+		return err
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		period.ID = oid
+	}
+	return nil
+}
+
+// FindCurrent returns the current period of a key
+func (r *MongoKeyOwnershipRepository) FindCurrent(ctx context.Context, key string) (*KeyOwnership, error) {
+	// A missing field matches nil: current periods have no to
+	filter := bson.M{"normalizedKey": NormalizeKey(key), "to": nil}
+	opts := options.FindOne().SetSort(bson.D{{Key: "from", Value: -1}})
+
+	var period KeyOwnership
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&period)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &period, nil
+}
+
+// Close ends the current period of a key
+func (r *MongoKeyOwnershipRepository) Close(ctx context.Context, key string, at time.Time) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"normalizedKey": NormalizeKey(key), "to": nil},
+		bson.M{"$set": bson.M{"to": at.UTC()}},
+	)
+	return err
+}
+
+// FindByKey returns the periods of a key, newest first
+func (r *MongoKeyOwnershipRepository) FindByKey(ctx context.Context, key string) ([]KeyOwnership, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "from", Value: -1}, {Key: "_id", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"normalizedKey": NormalizeKey(key)}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	periods := []KeyOwnership{}
+	if err := cursor.All(ctx, &periods); err != nil {
+		return nil, err
+	}
+	return periods, nil
+}
+
+// DeleteByTaxID deletes the periods in which an owner held keys
+func (r *MongoKeyOwnershipRepository) DeleteByTaxID(ctx context.Context, taxID string) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"$or": bson.A{
+		bson.M{"owner.taxIdNumber": taxID},
+		bson.M{"owner.taxIdHash": taxID},
+	}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// ForEachPeriod calls fn with every period, stopping at the first error
+func (r *MongoKeyOwnershipRepository) ForEachPeriod(ctx context.Context, fn func(period *KeyOwnership) error) error {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var period KeyOwnership
+		if err := cursor.Decode(&period); err != nil {
+			return err
+		}
+		if err := fn(&period); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// RewriteOwner stores owner's name, tax ID and blind index if the period still has old's name and tax ID
+func (r *MongoKeyOwnershipRepository) RewriteOwner(ctx context.Context, key string, id primitive.ObjectID, old, owner Owner) (bool, error) {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "normalizedKey": NormalizeKey(key), "owner.name": old.Name, "owner.taxIdNumber": old.TaxIdNumber},
+		bson.M{"$set": bson.M{"owner.name": owner.Name, "owner.taxIdNumber": owner.TaxIdNumber, "owner.taxIdHash": owner.TaxIdHash}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// ToResponse converts KeyOwnership to KeyOwnershipResponse
+func (p *KeyOwnership) ToResponse() KeyOwnershipResponse {
+	return KeyOwnershipResponse{
+		Key:         p.Key,
+		KeyType:     p.KeyType,
+		Participant: p.Participant,
+		Owner:       p.Owner,
+		From:        p.From,
+		To:          p.To,
+	}
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryKeyOwnershipRepository keeps ownership periods in process memory (STORAGE=memory)
+type MemoryKeyOwnershipRepository struct {
+	mu      sync.RWMutex
+	periods map[string][]KeyOwnership // by normalized key, oldest first
+}
+
+// NewMemoryKeyOwnershipRepository creates a new in-memory key ownership repository
+func NewMemoryKeyOwnershipRepository() *MemoryKeyOwnershipRepository {
+	return &MemoryKeyOwnershipRepository{
+		periods: map[string][]KeyOwnership{},
+	}
+}
+
+// Open stores a period
+func (r *MemoryKeyOwnershipRepository) Open(ctx context.Context, period *KeyOwnership) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	period.ID = primitive.NewObjectID()
+	r.periods[period.NormalizedKey] = append(r.periods[period.NormalizedKey], *period)
+	return nil
+}
+
+// FindCurrent returns the current period of a key
+func (r *MemoryKeyOwnershipRepository) FindCurrent(ctx context.Context, key string) (*KeyOwnership, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	periods := r.periods[NormalizeKey(key)]
+	for i := len(periods) - 1; i >= 0; i-- {
+		if periods[i].To == nil {
+			period := periods[i]
+			return &period, nil
+		}
+	}
+	return nil, nil
+}
+
+// Close ends the current period of a key
+func (r *MemoryKeyOwnershipRepository) Close(ctx context.Context, key string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	periods := r.periods[NormalizeKey(key)]
+	for i := range periods {
+		if periods[i].To == nil {
+			to := at.UTC()
+			periods[i].To = &to
+		}
+	}
+	return nil
+}
+
+// FindByKey returns the periods of a key, newest first
+func (r *MemoryKeyOwnershipRepository) FindByKey(ctx context.Context, key string) ([]KeyOwnership, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	periods := slices.Clone(r.periods[NormalizeKey(key)])
+	slices.Reverse(periods)
+	if periods == nil {
+		periods = []KeyOwnership{}
+	}
+	return periods, nil
+}
+
+// DeleteByTaxID deletes the periods in which an owner held keys
+func (r *MemoryKeyOwnershipRepository) DeleteByTaxID(ctx context.Context, taxID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for key, periods := range r.periods {
+		kept := slices.DeleteFunc(periods, func(period KeyOwnership) bool {
+			return period.Owner.TaxIdNumber == taxID || period.Owner.TaxIdHash == taxID
+		})
+		deleted += int64(len(periods) - len(kept))
+		if len(kept) == 0 {
+			delete(r.periods, key)
+		} else {
+			r.periods[key] = kept
+		}
+	}
+	return deleted, nil
+}
+
+// ForEachPeriod calls fn with every period
+// fn runs on a snapshot of the periods, so it may use the repository.
+func (r *MemoryKeyOwnershipRepository) ForEachPeriod(ctx context.Context, fn func(period *KeyOwnership) error) error {
+	r.mu.RLock()
+	var periods []KeyOwnership
+	for _, held := range r.periods {
+		periods = append(periods, held...)
+	}
+	r.mu.RUnlock()
+
+	for i := range periods {
+		if err := fn(&periods[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RewriteOwner stores owner's name, tax ID and blind index if the period still has old's name and tax ID
+func (r *MemoryKeyOwnershipRepository) RewriteOwner(ctx context.Context, key string, id primitive.ObjectID, old, owner Owner) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	periods := r.periods[NormalizeKey(key)]
+	for i := range periods {
+		stored := &periods[i].Owner
+		if periods[i].ID != id || stored.Name != old.Name || stored.TaxIdNumber != old.TaxIdNumber {
+			continue
+		}
+		stored.Name, stored.TaxIdNumber, stored.TaxIdHash = owner.Name, owner.TaxIdNumber, owner.TaxIdHash
+		return true, nil
+	}
+	return false, nil
+}
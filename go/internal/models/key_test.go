@@ -0,0 +1,24 @@
+package models
+
+import "testing"
+
+func TestNormalizeKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"Test@Example.com", "test@example.com"},
+		{"  dev@example.com\n", "dev@example.com"},
+		{"+55 (11) 99999-9999", "+5511999999999"},
+		{"+55.11.99999.9999", "+5511999999999"},
+		{"+5511999999999", "+5511999999999"},
+		{"52998224725", "52998224725"},
+		{" 123e4567-e89b-42d3-a456-426614174000 ", "123e4567-e89b-42d3-a456-426614174000"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeKey(tt.key); got != tt.want {
+			t.Errorf("NormalizeKey(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Masked returns the representation shown to participants other than the entry's own
+// Natural persons are protected as personal data (LGPD): the CPF keeps only its middle six digits,
+// as on Pix receipts, and the name is cut to initials. Companies' CNPJ and names are public, so
+// legal persons are returned as they are.
+func (r EntryResponse) Masked() EntryResponse {
+	r.Owner = maskOwner(r.Owner)
+	return r
+}
+
+// Masked returns the period as shown to participants other than the one that held the key in it,
+// the owner masked as in EntryResponse.Masked
+func (r KeyOwnershipResponse) Masked() KeyOwnershipResponse {
+	r.Owner = maskOwner(r.Owner)
+	return r
+}
+
+// maskOwner masks the CPF and name of a natural person
+func maskOwner(owner Owner) Owner {
+	if owner.Type != OwnerTypeNaturalPerson {
+		return owner
+	}
+	owner.TaxIdNumber = maskCPF(owner.TaxIdNumber)
+	owner.Name = initials(owner.Name)
+	return owner
+}
+
+// maskCPF hides the first three and last two digits of an 11-digit CPF, as in ***982247**
+// Anything else is hidden completely.
+func maskCPF(cpf string) string {
+	if len(cpf) != 11 {
+		return strings.Repeat("*", len(cpf))
+	}
+	return "***" + cpf[3:9] + "**"
+}
+
+// initials shortens each word of a name to its first letter, as in "J. D." for "John Doe"
+func initials(name string) string {
+	words := strings.Fields(name)
+	short := make([]string, 0, len(words))
+	for _, word := range words {
+		first, _ := utf8.DecodeRuneInString(word)
+		short = append(short, string(unicode.ToUpper(first))+".")
+	}
+	return strings.Join(short, " ")
+}
@@ -0,0 +1,18 @@
+package models
+
+import "testing"
+
+func TestMasked(t *testing.T) {
+	person := EntryResponse{Owner: Owner{Type: OwnerTypeNaturalPerson, TaxIdNumber: "52998224725", Name: "álvaro  de Souza"}}
+	if got := person.Masked().Owner; got.TaxIdNumber != "***982247**" || got.Name != "Á. D. S." {
+		t.Errorf("masked natural person = %+v, want ***982247** and Á. D. S.", got)
+	}
+
+	company := EntryResponse{Owner: Owner{Type: OwnerTypeLegalPerson, TaxIdNumber: "11222333000181", Name: "Acme Ltda"}}
+	if got := company.Masked().Owner; got != company.Owner {
+		t.Errorf("masked legal person = %+v, want it unchanged", got)
+	}
+	if person.ETag() == person.Masked().ETag() {
+		t.Error("masked representation has the full one's ETag")
+	}
+}
@@ -0,0 +1,230 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/db"
+)
+
+// Scope is a group of DICT operations a token can be granted
+type Scope string
+
+const (
+	ScopeEntriesRead    Scope = "entries:read"   // getEntry, fraud markers and key validation
+	ScopeEntriesWrite   Scope = "entries:write"  // createEntry, updateEntry, deleteEntry and account closure
+	ScopeReconciliation Scope = "reconciliation" // participant listing and reconciliation files
+	ScopeWebhooks       Scope = "webhooks"
+	ScopeSettlements    Scope = "settlements"
+	ScopeClaimsManage   Scope = "claims:manage" // portability and ownership claims
+	ScopeAdmin          Scope = "admin"         // the /admin routes, in place of X-Admin-Token
+)
+
+// Scopes lists every scope, in the order they are documented
+var Scopes = []Scope{ScopeEntriesRead, ScopeEntriesWrite, ScopeReconciliation, ScopeWebhooks, ScopeSettlements, ScopeClaimsManage, ScopeAdmin}
+
+// UserScopes are the scopes a user can be granted: all but admin, since anyone can register
+var UserScopes = []Scope{ScopeEntriesRead, ScopeEntriesWrite, ScopeReconciliation, ScopeWebhooks, ScopeSettlements, ScopeClaimsManage}
+
+// ParseScopes splits a space-delimited OAuth scope string, as sent to and returned by the token endpoint
+// It returns false if any scope is unknown.
+func ParseScopes(s string) ([]Scope, bool) {
+	scopes := []Scope{}
+	for _, field := range strings.Fields(s) {
+		scope := Scope(field)
+		if !slices.Contains(Scopes, scope) {
+			return nil, false
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, true
+}
+
+// FormatScopes joins scopes into a space-delimited OAuth scope string
+func FormatScopes(scopes []Scope) string {
+	fields := make([]string, len(scopes))
+	for i, scope := range scopes {
+		fields[i] = string(scope)
+	}
+	return strings.Join(fields, " ")
+}
+
+// OAuthClient is a machine client a participant authenticates with through the client credentials grant
+// Its ID is the OAuth client_id; only a hash of the secret is kept.
+type OAuthClient struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	Participant string             `bson:"participant"`
+	Name        string             `bson:"name"`
+	Secret      string             `bson:"secret"` // bcrypt hash
+	Scopes      []Scope            `bson:"scopes"`
+	CreatedAt   time.Time          `bson:"createdAt"`
+}
+
+// OAuthClientResponse represents the API response for an OAuth client
+// ClientSecret is only returned when the client is created
+type OAuthClientResponse struct {
+	ClientID     string    `json:"clientId" example:"507f1f77bcf86cd799439011"`
+	Participant  string    `json:"participant" example:"12345678"`
+	Name         string    `json:"name" example:"PSP reconciliation service"`
+	Scopes       []Scope   `json:"scopes" example:"entries:read,entries:write"`
+	ClientSecret string    `json:"clientSecret,omitempty" example:"8c6976e5b5410415bde908bd4dee15dfb167a9c873fc4bb8a81f6f2ab448a918"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// CreateOAuthClientRequest represents the request body for registering an OAuth client
+type CreateOAuthClientRequest struct {
+	Participant string  `json:"participant" validate:"required,len=8,numeric" example:"12345678"`
+	Name        string  `json:"name" validate:"required" example:"PSP reconciliation service"`
+	Scopes      []Scope `json:"scopes" validate:"required,min=1,dive,oneof=entries:read entries:write reconciliation webhooks settlements claims:manage admin" example:"entries:read,entries:write"`
+}
+
+// OAuthClientRepository handles storage operations for OAuth clients
+// Lookups return (nil, nil) when no client matches.
+type OAuthClientRepository interface {
+	// Create registers a new client, storing a hash of secret
+	Create(ctx context.Context, req *CreateOAuthClientRequest, secret string) (*OAuthClient, error)
+	// FindByID finds a client by its ID (the client_id)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*OAuthClient, error)
+	// List returns the clients of a participant, or of every participant when it is empty, oldest first
+	List(ctx context.Context, participant string) ([]OAuthClient, error)
+	// DeleteByID removes a client and reports whether it existed
+	DeleteByID(ctx context.Context, id primitive.ObjectID) (bool, error)
+}
+
+// MongoOAuthClientRepository stores OAuth clients in the oauth_clients collection
+type MongoOAuthClientRepository struct {
+	collection *mongo.Collection
+	clock      clock.Clock
+}
+
+// NewMongoOAuthClientRepository creates a new MongoDB-backed OAuth client repository
+func NewMongoOAuthClientRepository(db *db.Mongo, clk clock.Clock) *MongoOAuthClientRepository {
+	return &MongoOAuthClientRepository{
+		collection: db.Collection("oauth_clients"),
+		clock:      clk,
+	}
+}
+
+// IndexSpec declares the indexes of the oauth_clients collection
+func (r *MongoOAuthClientRepository) IndexSpec() db.IndexSpec {
+	return db.IndexSpec{
+		Collection: r.collection.Name(),
+		Indexes: []mongo.IndexModel{
+			{
+				Keys: bson.D{{Key: "participant", Value: 1}, {Key: "createdAt", Value: 1}},
+			},
+		},
+	}
+}
+
+// EnsureIndexes creates the indexes of the oauth_clients collection
+func (r *MongoOAuthClientRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, r.IndexSpec().Indexes)
+	return err
+}
+
+// Create registers a new client
+func (r *MongoOAuthClientRepository) Create(ctx context.Context, req *CreateOAuthClientRequest, secret string) (*OAuthClient, error) {
+	client, err := newOAuthClient(req, secret, r.clock)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.collection.InsertOne(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	oid, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, errors.New("failed to get inserted ID")
+	}
+	client.ID = oid
+
+	return client, nil
+}
+
+// FindByID finds a client by its ID
+func (r *MongoOAuthClientRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*OAuthClient, error) {
+	var client OAuthClient
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&client)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &client, nil
+}
+
+// List returns the clients of a participant, or every client
+func (r *MongoOAuthClientRepository) List(ctx context.Context, participant string) ([]OAuthClient, error) {
+	filter := bson.M{}
+	if participant != "" {
+		filter["participant"] = participant
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	clients := []OAuthClient{}
+	if err := cursor.All(ctx, &clients); err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+// DeleteByID removes a client and reports whether it existed
+func (r *MongoOAuthClientRepository) DeleteByID(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// newOAuthClient builds an OAuthClient, hashing the secret
+func newOAuthClient(req *CreateOAuthClientRequest, secret string, clk clock.Clock) (*OAuthClient, error) {
+	hashedSecret, err := hashPassword(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OAuthClient{
+		Participant: req.Participant,
+		Name:        req.Name,
+		Secret:      hashedSecret,
+		Scopes:      slices.Clone(req.Scopes),
+		CreatedAt:   clk.Now().UTC(),
+	}, nil
+}
+
+// CheckSecret compares the provided secret with the stored hash
+func (c *OAuthClient) CheckSecret(secret string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(c.Secret), []byte(secret)) == nil
+}
+
+// ToResponse converts OAuthClient to OAuthClientResponse without the secret
+func (c *OAuthClient) ToResponse() OAuthClientResponse {
+	return OAuthClientResponse{
+		ClientID:    c.ID.Hex(),
+		Participant: c.Participant,
+		Name:        c.Name,
+		Scopes:      c.Scopes,
+		CreatedAt:   c.CreatedAt,
+	}
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/dict-simulator/go/internal/clock"
+)
+
+// MemoryOAuthClientRepository keeps OAuth clients in process memory (STORAGE=memory)
+type MemoryOAuthClientRepository struct {
+	mu      sync.RWMutex
+	clients []OAuthClient // in registration order
+	clock   clock.Clock
+}
+
+// NewMemoryOAuthClientRepository creates a new in-memory OAuth client repository
+func NewMemoryOAuthClientRepository(clk clock.Clock) *MemoryOAuthClientRepository {
+	return &MemoryOAuthClientRepository{
+		clock: clk,
+	}
+}
+
+// Create registers a new client
+func (r *MemoryOAuthClientRepository) Create(ctx context.Context, req *CreateOAuthClientRequest, secret string) (*OAuthClient, error) {
+	client, err := newOAuthClient(req, secret, r.clock)
+	if err != nil {
+		return nil, err
+	}
+	client.ID = primitive.NewObjectID()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clients = append(r.clients, *client)
+	return client, nil
+}
+
+// FindByID finds a client by its ID
+func (r *MemoryOAuthClientRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*OAuthClient, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, client := range r.clients {
+		if client.ID == id {
+			client.Scopes = slices.Clone(client.Scopes)
+			return &client, nil
+		}
+	}
+	return nil, nil
+}
+
+// List returns the clients of a participant, or every client
+func (r *MemoryOAuthClientRepository) List(ctx context.Context, participant string) ([]OAuthClient, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	clients := []OAuthClient{}
+	for _, client := range r.clients {
+		if participant == "" || client.Participant == participant {
+			client.Scopes = slices.Clone(client.Scopes)
+			clients = append(clients, client)
+		}
+	}
+	return clients, nil
+}
+
+// DeleteByID removes a client and reports whether it existed
+func (r *MemoryOAuthClientRepository) DeleteByID(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, client := range r.clients {
+		if client.ID == id {
+			r.clients = slices.Delete(r.clients, i, i+1)
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseScopes(t *testing.T) {
+	tests := []struct {
+		scope string
+		want  []Scope
+		ok    bool
+	}{
+		{"", []Scope{}, true},
+		{"entries:read", []Scope{ScopeEntriesRead}, true},
+		{"  entries:read   webhooks ", []Scope{ScopeEntriesRead, ScopeWebhooks}, true},
+		{"settlements settlements", []Scope{ScopeSettlements}, true},
+		{"claims:manage admin", []Scope{ScopeClaimsManage, ScopeAdmin}, true},
+		{"entries:read root", nil, false},
+		{"ENTRIES:READ", nil, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseScopes(tt.scope)
+		if ok != tt.ok || !slices.Equal(got, tt.want) {
+			t.Errorf("ParseScopes(%q) = %v, %v, want %v, %v", tt.scope, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFormatScopes(t *testing.T) {
+	scopes := []Scope{ScopeEntriesRead, ScopeReconciliation}
+	if got := FormatScopes(scopes); got != "entries:read reconciliation" {
+		t.Errorf("FormatScopes() = %q, want %q", got, "entries:read reconciliation")
+	}
+	if parsed, _ := ParseScopes(FormatScopes(scopes)); !slices.Equal(parsed, scopes) {
+		t.Errorf("ParseScopes(FormatScopes()) = %v, want %v", parsed, scopes)
+	}
+}
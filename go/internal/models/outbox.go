@@ -0,0 +1,180 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/dict-simulator/go/internal/db"
+	"github.com/dict-simulator/go/internal/events"
+)
+
+// outboxRetention is how long published messages are kept before MongoDB removes them
+const outboxRetention = 7 * 24 * time.Hour
+
+// OutboxMessage is an event waiting to be published to the message broker
+type OutboxMessage struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	EventID        string             `bson:"eventId"`
+	EventType      events.Type        `bson:"eventType"`
+	Participant    string             `bson:"participant"`
+	Payload        string             `bson:"payload"` // Event JSON, published as-is
+	Attempts       int                `bson:"attempts"`
+	LastError      string             `bson:"lastError,omitempty"`
+	NextAttemptAt  time.Time          `bson:"nextAttemptAt"`
+	CreatedAt      time.Time          `bson:"createdAt"`
+	PublishedAt    *time.Time         `bson:"publishedAt,omitempty"`
+	DeadLetteredAt *time.Time         `bson:"deadLetteredAt,omitempty"` // Set when the relay gives up; kept but no longer retried
+}
+
+// OutboxRepository handles storage operations for the event outbox
+type OutboxRepository interface {
+	// Enqueue stores an event for publishing; an event that is already queued is left untouched
+	Enqueue(ctx context.Context, event events.Event) error
+	// FindDue returns up to limit unpublished messages whose next attempt is due, oldest first
+	FindDue(ctx context.Context, now time.Time, limit int64) ([]OutboxMessage, error)
+	// MarkPublished records that the broker accepted the message
+	MarkPublished(ctx context.Context, id primitive.ObjectID, at time.Time) error
+	// MarkFailed records a failed attempt and when to try again
+	MarkFailed(ctx context.Context, id primitive.ObjectID, cause error, next time.Time) error
+	// MarkDeadLettered records a last failed attempt; the message is no longer returned by FindDue
+	MarkDeadLettered(ctx context.Context, id primitive.ObjectID, cause error, at time.Time) error
+	// FindByEventID finds the outbox message for an event, or returns (nil, nil)
+	FindByEventID(ctx context.Context, eventID string) (*OutboxMessage, error)
+}
+
+// MongoOutboxRepository stores outbox messages in the event_outbox collection
+type MongoOutboxRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoOutboxRepository creates a new MongoDB-backed outbox repository
+func NewMongoOutboxRepository(db *db.Mongo) *MongoOutboxRepository {
+	return &MongoOutboxRepository{
+		collection: db.Collection("event_outbox"),
+	}
+}
+
+// IndexSpec declares the indexes of the event_outbox collection
+func (r *MongoOutboxRepository) IndexSpec() db.IndexSpec {
+	return db.IndexSpec{
+		Collection: r.collection.Name(),
+		Indexes: []mongo.IndexModel{
+			{
+				// Redelivered change stream events keep their ID, so they are stored once
+				Keys:    bson.D{{Key: "eventId", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				// Pending messages only; publishedAt is absent until the broker accepts the message
+				Keys: bson.D{{Key: "nextAttemptAt", Value: 1}, {Key: "createdAt", Value: 1}},
+				Options: options.Index().SetPartialFilterExpression(bson.M{
+					"publishedAt": bson.M{"$exists": false},
+				}),
+			},
+			{
+				Keys:    bson.D{{Key: "publishedAt", Value: 1}},
+				Options: options.Index().SetExpireAfterSeconds(int32(outboxRetention.Seconds())),
+			},
+		},
+	}
+}
+
+// EnsureIndexes creates the indexes of the event_outbox collection
+func (r *MongoOutboxRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, r.IndexSpec().Indexes)
+	return err
+}
+
+// Enqueue stores an event for publishing; an event that is already queued is left untouched
+func (r *MongoOutboxRepository) Enqueue(ctx context.Context, event events.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	message := OutboxMessage{
+		EventID:       event.ID,
+		EventType:     event.Type,
+		Participant:   event.Participant,
+		Payload:       string(payload),
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+
+	_, err = r.collection.UpdateOne(ctx,
+		bson.M{"eventId": event.ID},
+		bson.M{"$setOnInsert": message},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// FindDue returns up to limit unpublished messages whose next attempt is due, oldest first
+func (r *MongoOutboxRepository) FindDue(ctx context.Context, now time.Time, limit int64) ([]OutboxMessage, error) {
+	filter := bson.M{
+		"publishedAt":    bson.M{"$exists": false},
+		"deadLetteredAt": bson.M{"$exists": false},
+		"nextAttemptAt":  bson.M{"$lte": now},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(limit)
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []OutboxMessage{}
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// MarkPublished records that the broker accepted the message
+func (r *MongoOutboxRepository) MarkPublished(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"publishedAt": at.UTC()}, "$inc": bson.M{"attempts": 1}, "$unset": bson.M{"lastError": ""}},
+	)
+	return err
+}
+
+// MarkFailed records a failed attempt and when to try again
+func (r *MongoOutboxRepository) MarkFailed(ctx context.Context, id primitive.ObjectID, cause error, next time.Time) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"lastError": cause.Error(), "nextAttemptAt": next.UTC()}, "$inc": bson.M{"attempts": 1}},
+	)
+	return err
+}
+
+// MarkDeadLettered records a last failed attempt; the message is no longer returned by FindDue
+func (r *MongoOutboxRepository) MarkDeadLettered(ctx context.Context, id primitive.ObjectID, cause error, at time.Time) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"lastError": cause.Error(), "deadLetteredAt": at.UTC()}, "$inc": bson.M{"attempts": 1}},
+	)
+	return err
+}
+
+// FindByEventID finds the outbox message for an event
+func (r *MongoOutboxRepository) FindByEventID(ctx context.Context, eventID string) (*OutboxMessage, error) {
+	var message OutboxMessage
+	err := r.collection.FindOne(ctx, bson.M{"eventId": eventID}).Decode(&message)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &message, nil
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/dict-simulator/go/internal/events"
+)
+
+// MemoryOutboxRepository keeps the event outbox in process memory (STORAGE=memory)
+// Pending messages are lost on restart, so publishing is only at-least-once within a process.
+type MemoryOutboxRepository struct {
+	mu       sync.Mutex
+	messages []OutboxMessage // in creation order
+}
+
+// NewMemoryOutboxRepository creates a new in-memory outbox repository
+func NewMemoryOutboxRepository() *MemoryOutboxRepository {
+	return &MemoryOutboxRepository{}
+}
+
+// Enqueue stores an event for publishing; an event that is already queued is left untouched
+// Messages published more than outboxRetention ago are dropped at the same time.
+func (r *MemoryOutboxRepository) Enqueue(ctx context.Context, event events.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.messages = slices.DeleteFunc(r.messages, func(m OutboxMessage) bool {
+		return m.PublishedAt != nil && now.Sub(*m.PublishedAt) > outboxRetention
+	})
+	if r.index(func(m *OutboxMessage) bool { return m.EventID == event.ID }) >= 0 {
+		return nil
+	}
+
+	r.messages = append(r.messages, OutboxMessage{
+		ID:            primitive.NewObjectID(),
+		EventID:       event.ID,
+		EventType:     event.Type,
+		Participant:   event.Participant,
+		Payload:       string(payload),
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	})
+	return nil
+}
+
+// FindDue returns up to limit unpublished messages whose next attempt is due, oldest first
+func (r *MemoryOutboxRepository) FindDue(ctx context.Context, now time.Time, limit int64) ([]OutboxMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	messages := []OutboxMessage{}
+	for _, message := range r.messages {
+		if int64(len(messages)) == limit {
+			break
+		}
+		if message.PublishedAt == nil && message.DeadLetteredAt == nil && !message.NextAttemptAt.After(now) {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+// MarkPublished records that the broker accepted the message
+func (r *MemoryOutboxRepository) MarkPublished(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	r.update(id, func(m *OutboxMessage) {
+		publishedAt := at.UTC()
+		m.PublishedAt = &publishedAt
+		m.Attempts++
+		m.LastError = ""
+	})
+	return nil
+}
+
+// MarkFailed records a failed attempt and when to try again
+func (r *MemoryOutboxRepository) MarkFailed(ctx context.Context, id primitive.ObjectID, cause error, next time.Time) error {
+	r.update(id, func(m *OutboxMessage) {
+		m.LastError = cause.Error()
+		m.NextAttemptAt = next.UTC()
+		m.Attempts++
+	})
+	return nil
+}
+
+// MarkDeadLettered records a last failed attempt; the message is no longer returned by FindDue
+func (r *MemoryOutboxRepository) MarkDeadLettered(ctx context.Context, id primitive.ObjectID, cause error, at time.Time) error {
+	r.update(id, func(m *OutboxMessage) {
+		deadLetteredAt := at.UTC()
+		m.DeadLetteredAt = &deadLetteredAt
+		m.LastError = cause.Error()
+		m.Attempts++
+	})
+	return nil
+}
+
+// FindByEventID finds the outbox message for an event
+func (r *MemoryOutboxRepository) FindByEventID(ctx context.Context, eventID string) (*OutboxMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.index(func(m *OutboxMessage) bool { return m.EventID == eventID })
+	if i < 0 {
+		return nil, nil
+	}
+	message := r.messages[i]
+	return &message, nil
+}
+
+// update applies fn to the message with the given ID, if it still exists
+func (r *MemoryOutboxRepository) update(id primitive.ObjectID, fn func(*OutboxMessage)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i := r.index(func(m *OutboxMessage) bool { return m.ID == id }); i >= 0 {
+		fn(&r.messages[i])
+	}
+}
+
+// index returns the position of the first message matching fn, or -1; the caller must hold r.mu
+func (r *MemoryOutboxRepository) index(fn func(*OutboxMessage) bool) int {
+	for i := range r.messages {
+		if fn(&r.messages[i]) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,217 @@
+package models
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/dict-simulator/go/internal/db"
+)
+
+// ReconciliationDateLayout is the layout of ReconciliationFile.Date
+const ReconciliationDateLayout = "2006-01-02"
+
+// ReconciliationFile is a participant's daily verification file: every key it held when the file
+// was generated, with the entry's CID and last modification, in key order
+// Participants compare it with their own base to find entries that drifted from the directory.
+type ReconciliationFile struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty"`
+	Participant string              `bson:"participant"`
+	Date        string              `bson:"date"` // ReconciliationDateLayout, on the simulated clock
+	Entries     int                 `bson:"entries"`
+	Rows        []ReconciliationRow `bson:"rows"`
+	CreatedAt   time.Time           `bson:"createdAt"`
+}
+
+// ReconciliationRow is one entry of a reconciliation file
+type ReconciliationRow struct {
+	Key          string    `bson:"key" xml:"Key"`
+	CID          string    `bson:"cid" xml:"Cid"`
+	LastModified time.Time `bson:"lastModified" xml:"LastModified"`
+}
+
+// ReconciliationFileResponse represents the API response for a reconciliation file, without its rows
+type ReconciliationFileResponse struct {
+	ID          string    `json:"id" example:"65a4f0c2e4b0a1b2c3d4e5f6"`
+	Participant string    `json:"participant" example:"12345678"`
+	Date        string    `json:"date" example:"2024-01-15"`
+	Entries     int       `json:"entries" example:"1500"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// NewReconciliationRow builds the row of an entry
+func NewReconciliationRow(entry *Entry) ReconciliationRow {
+	return ReconciliationRow{
+		Key:          entry.Key,
+		CID:          entry.CID(),
+		LastModified: entry.UpdatedAt,
+	}
+}
+
+// ToResponse converts ReconciliationFile to ReconciliationFileResponse
+func (f *ReconciliationFile) ToResponse() ReconciliationFileResponse {
+	return ReconciliationFileResponse{
+		ID:          f.ID.Hex(),
+		Participant: f.Participant,
+		Date:        f.Date,
+		Entries:     f.Entries,
+		CreatedAt:   f.CreatedAt,
+	}
+}
+
+// WriteCSV writes the file as CSV: a key,cid,lastModified header, then one row per entry
+func (f *ReconciliationFile) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"key", "cid", "lastModified"}); err != nil {
+		return err
+	}
+	for _, row := range f.Rows {
+		if err := cw.Write([]string{row.Key, row.CID, row.LastModified.UTC().Format(time.RFC3339Nano)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// reconciliationXML is the XML document of a reconciliation file, modeled on the DICT file format
+type reconciliationXML struct {
+	XMLName     xml.Name            `xml:"ReconciliationFile"`
+	Participant string              `xml:"Participant,attr"`
+	Date        string              `xml:"Date,attr"`
+	Entries     []ReconciliationRow `xml:"Entries>Entry"`
+}
+
+// WriteXML writes the file as an XML document
+func (f *ReconciliationFile) WriteXML(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(reconciliationXML{
+		Participant: f.Participant,
+		Date:        f.Date,
+		Entries:     f.Rows,
+	})
+}
+
+// ErrReconciliationFileExists is returned by ReconciliationFileRepository.Create when the
+// participant already has a file for the date
+var ErrReconciliationFileExists error = &Error{Kind: ErrDuplicateKey, Resource: ResourceReconciliationFile}
+
+// ReconciliationFileRepository handles storage operations for reconciliation files
+// Lookups return (nil, nil) when no file matches.
+type ReconciliationFileRepository interface {
+	// Create stores a file, failing with ErrReconciliationFileExists if the participant has one for the date
+	Create(ctx context.Context, file *ReconciliationFile) error
+	// HasDate reports whether any participant has a file for the date
+	HasDate(ctx context.Context, date string) (bool, error)
+	// FindByID finds a file by its ID, rows included
+	FindByID(ctx context.Context, id primitive.ObjectID) (*ReconciliationFile, error)
+	// FindByParticipant lists a participant's files, newest first, without their rows
+	FindByParticipant(ctx context.Context, participant string) ([]ReconciliationFile, error)
+}
+
+// MongoReconciliationFileRepository stores reconciliation files in the reconciliation_files collection
+// Rows are embedded in the file's document, which caps a file at MongoDB's 16 MB document size.
+type MongoReconciliationFileRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoReconciliationFileRepository creates a new MongoDB-backed reconciliation file repository
+func NewMongoReconciliationFileRepository(db *db.Mongo) *MongoReconciliationFileRepository {
+	return &MongoReconciliationFileRepository{
+		collection: db.Collection("reconciliation_files"),
+	}
+}
+
+// IndexSpec declares the indexes of the reconciliation_files collection
+func (r *MongoReconciliationFileRepository) IndexSpec() db.IndexSpec {
+	return db.IndexSpec{
+		Collection: r.collection.Name(),
+		Indexes: []mongo.IndexModel{
+			{
+				// One file per participant and day; also answers HasDate
+				Keys:    bson.D{{Key: "date", Value: 1}, {Key: "participant", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				Keys: bson.D{{Key: "participant", Value: 1}, {Key: "date", Value: -1}},
+			},
+		},
+	}
+}
+
+// EnsureIndexes creates the indexes of the reconciliation_files collection
+func (r *MongoReconciliationFileRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, r.IndexSpec().Indexes)
+	return err
+}
+
+// Create stores a reconciliation file
+func (r *MongoReconciliationFileRepository) Create(ctx context.Context, file *ReconciliationFile) error {
+	result, err := r.collection.InsertOne(ctx, file)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrReconciliationFileExists
+	}
+	if err != nil {
+		return err
+	}
+
+	oid, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return errors.New("failed to get inserted ID")
+	}
+	file.ID = oid
+
+	return nil
+}
+
+// HasDate reports whether any participant has a file for the date
+func (r *MongoReconciliationFileRepository) HasDate(ctx context.Context, date string) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"date": date}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindByID finds a reconciliation file by its ID
+func (r *MongoReconciliationFileRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*ReconciliationFile, error) {
+	var file ReconciliationFile
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&file)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &file, nil
+}
+
+// FindByParticipant lists a participant's files, newest first, without their rows
+func (r *MongoReconciliationFileRepository) FindByParticipant(ctx context.Context, participant string) ([]ReconciliationFile, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "date", Value: -1}}).
+		SetProjection(bson.M{"rows": 0})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"participant": participant}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []ReconciliationFile{}
+	if err := cursor.All(ctx, &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
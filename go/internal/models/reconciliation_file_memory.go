@@ -0,0 +1,79 @@
+package models
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryReconciliationFileRepository keeps reconciliation files in process memory (STORAGE=memory)
+type MemoryReconciliationFileRepository struct {
+	mu    sync.RWMutex
+	files []ReconciliationFile
+}
+
+// NewMemoryReconciliationFileRepository creates a new in-memory reconciliation file repository
+func NewMemoryReconciliationFileRepository() *MemoryReconciliationFileRepository {
+	return &MemoryReconciliationFileRepository{}
+}
+
+// Create stores a reconciliation file
+func (r *MemoryReconciliationFileRepository) Create(ctx context.Context, file *ReconciliationFile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.files {
+		if r.files[i].Date == file.Date && r.files[i].Participant == file.Participant {
+			return ErrReconciliationFileExists
+		}
+	}
+
+	file.ID = primitive.NewObjectID()
+	r.files = append(r.files, *file)
+	return nil
+}
+
+// HasDate reports whether any participant has a file for the date
+func (r *MemoryReconciliationFileRepository) HasDate(ctx context.Context, date string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.ContainsFunc(r.files, func(f ReconciliationFile) bool {
+		return f.Date == date
+	}), nil
+}
+
+// FindByID finds a reconciliation file by its ID
+func (r *MemoryReconciliationFileRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*ReconciliationFile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := range r.files {
+		if r.files[i].ID == id {
+			file := r.files[i]
+			return &file, nil
+		}
+	}
+	return nil, nil
+}
+
+// FindByParticipant lists a participant's files, newest first, without their rows
+func (r *MemoryReconciliationFileRepository) FindByParticipant(ctx context.Context, participant string) ([]ReconciliationFile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	files := []ReconciliationFile{}
+	for _, file := range r.files {
+		if file.Participant == participant {
+			file.Rows = nil
+			files = append(files, file)
+		}
+	}
+	slices.SortFunc(files, func(a, b ReconciliationFile) int {
+		return strings.Compare(b.Date, a.Date)
+	})
+	return files, nil
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEntryCID(t *testing.T) {
+	entry := Entry{
+		Key:     "Test@Example.com",
+		KeyType: KeyTypeEMAIL,
+		Account: Account{Participant: "12345678", Branch: "0001", AccountNumber: "0007654321", AccountType: "CACC"},
+		Owner:   Owner{Type: OwnerTypeNaturalPerson, TaxIdNumber: "52998224725", Name: "Test User"},
+	}
+	cid := entry.CID()
+	if len(cid) != 64 {
+		t.Fatalf("CID() = %q, want 64 hex characters", cid)
+	}
+
+	// Timestamps and key formatting don't change the CID
+	same := entry
+	same.Key = "test@example.com"
+	same.UpdatedAt = time.Now()
+	if got := same.CID(); got != cid {
+		t.Errorf("CID() after touching timestamps = %q, want %q", got, cid)
+	}
+
+	renamed := entry
+	renamed.Owner.Name = "Renamed User"
+	if renamed.CID() == cid {
+		t.Error("CID() unchanged after renaming the owner")
+	}
+}
+
+func TestReconciliationFileWrite(t *testing.T) {
+	file := ReconciliationFile{
+		Participant: "12345678",
+		Date:        "2024-01-15",
+		Rows: []ReconciliationRow{
+			{Key: "52998224725", CID: "abc", LastModified: time.Date(2024, 1, 14, 10, 30, 0, 0, time.UTC)},
+			{Key: "a,b@example.com", CID: "def", LastModified: time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	var csv bytes.Buffer
+	if err := file.WriteCSV(&csv); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	wantCSV := "key,cid,lastModified\n" +
+		"52998224725,abc,2024-01-14T10:30:00Z\n" +
+		`"a,b@example.com",def,2024-01-15T08:00:00Z` + "\n"
+	if csv.String() != wantCSV {
+		t.Errorf("WriteCSV =\n%s\nwant\n%s", csv.String(), wantCSV)
+	}
+
+	var xml bytes.Buffer
+	if err := file.WriteXML(&xml); err != nil {
+		t.Fatalf("WriteXML: %v", err)
+	}
+	for _, want := range []string{
+		`<ReconciliationFile Participant="12345678" Date="2024-01-15">`,
+		`<Key>52998224725</Key>`,
+		`<Cid>def</Cid>`,
+		`<LastModified>2024-01-15T08:00:00Z</LastModified>`,
+	} {
+		if !strings.Contains(xml.String(), want) {
+			t.Errorf("WriteXML output lacks %s:\n%s", want, xml.String())
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/dict-simulator/go/internal/db"
+)
+
+// UsedRequestID records a requestId a participant created an entry with
+// Unlike X-Idempotency-Key, which replays the first response, a used requestId is refused.
+type UsedRequestID struct {
+	Participant string    `bson:"participant"`
+	RequestId   string    `bson:"requestId"`
+	CreatedAt   time.Time `bson:"createdAt"`
+}
+
+// ErrRequestIDUsed is returned by RequestIDRepository.Claim when the participant already used the requestId
+var ErrRequestIDUsed error = &Error{Kind: ErrDuplicateKey, Resource: ResourceRequestID}
+
+// RequestIDRepository handles storage operations for the requestIds participants have used
+type RequestIDRepository interface {
+	// Claim records requestID as used by participant at at, failing with ErrRequestIDUsed if it already is
+	Claim(ctx context.Context, participant, requestID string, at time.Time) error
+	// Release forgets a claim whose entry was not created, so the requestId can be sent again
+	Release(ctx context.Context, participant, requestID string) error
+	// DeleteByParticipant deletes every requestId participant used and returns how many
+	DeleteByParticipant(ctx context.Context, participant string) (int64, error)
+}
+
+// MongoRequestIDRepository stores used requestIds in the request_ids collection
+type MongoRequestIDRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRequestIDRepository creates a new MongoDB-backed requestId repository
+func NewMongoRequestIDRepository(db *db.Mongo) *MongoRequestIDRepository {
+	return &MongoRequestIDRepository{
+		collection: db.Collection("request_ids"),
+	}
+}
+
+// IndexSpec declares the indexes of the request_ids collection
+func (r *MongoRequestIDRepository) IndexSpec() db.IndexSpec {
+	return db.IndexSpec{
+		Collection: r.collection.Name(),
+		Indexes: []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "participant", Value: 1}, {Key: "requestId", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		},
+	}
+}
+
+// EnsureIndexes creates the indexes of the request_ids collection
+func (r *MongoRequestIDRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, r.IndexSpec().Indexes)
+	return err
+}
+
+// Claim records requestID as used by participant
+func (r *MongoRequestIDRepository) Claim(ctx context.Context, participant, requestID string, at time.Time) error {
+	_, err := r.collection.InsertOne(ctx, UsedRequestID{
+		Participant: participant,
+		RequestId:   requestID,
+		CreatedAt:   at.UTC(),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrRequestIDUsed
+	}
+	return err
+}
+
+// Release forgets a claim
+func (r *MongoRequestIDRepository) Release(ctx context.Context, participant, requestID string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"participant": participant, "requestId": requestID})
+	return err
+}
+
+// DeleteByParticipant deletes every requestId participant used
+func (r *MongoRequestIDRepository) DeleteByParticipant(ctx context.Context, participant string) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"participant": participant})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryRequestIDRepository keeps used requestIds in process memory (STORAGE=memory)
+type MemoryRequestIDRepository struct {
+	mu   sync.Mutex
+	used map[string]map[string]time.Time // by participant, then requestId
+}
+
+// NewMemoryRequestIDRepository creates a new in-memory requestId repository
+func NewMemoryRequestIDRepository() *MemoryRequestIDRepository {
+	return &MemoryRequestIDRepository{
+		used: map[string]map[string]time.Time{},
+	}
+}
+
+// Claim records requestID as used by participant
+func (r *MemoryRequestIDRepository) Claim(ctx context.Context, participant, requestID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.used[participant][requestID]; ok {
+		return ErrRequestIDUsed
+	}
+	if r.used[participant] == nil {
+		r.used[participant] = map[string]time.Time{}
+	}
+	r.used[participant][requestID] = at.UTC()
+	return nil
+}
+
+// Release forgets a claim
+func (r *MemoryRequestIDRepository) Release(ctx context.Context, participant, requestID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.used[participant], requestID)
+	return nil
+}
+
+// DeleteByParticipant deletes every requestId participant used
+func (r *MemoryRequestIDRepository) DeleteByParticipant(ctx context.Context, participant string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := int64(len(r.used[participant]))
+	delete(r.used, participant)
+	return deleted, nil
+}
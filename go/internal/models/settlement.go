@@ -0,0 +1,135 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/dict-simulator/go/internal/db"
+)
+
+// Settlement is a test payment settled between two keys, referenced by its end-to-end ID
+// Amounts are in centavos.
+type Settlement struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty"`
+	EndToEndID       string             `bson:"endToEndId"`
+	Amount           int64              `bson:"amount"`
+	PayerKey         string             `bson:"payerKey"`
+	PayerParticipant string             `bson:"payerParticipant"`
+	PayeeKey         string             `bson:"payeeKey"`
+	PayeeParticipant string             `bson:"payeeParticipant"`
+	SettledAt        time.Time          `bson:"settledAt"`
+}
+
+// SettlementResponse represents the API response for a settlement
+type SettlementResponse struct {
+	EndToEndID       string    `json:"endToEndId" example:"E1234567820240115103000000000001"`
+	Amount           int64     `json:"amount" example:"15075"`
+	PayerKey         string    `json:"payerKey" example:"52998224725"`
+	PayerParticipant string    `json:"payerParticipant" example:"12345678"`
+	PayeeKey         string    `json:"payeeKey" example:"+5511999999999"`
+	PayeeParticipant string    `json:"payeeParticipant" example:"87654321"`
+	SettledAt        time.Time `json:"settledAt"`
+}
+
+// CreateSettlementRequest represents the request body for posting a settlement
+// The end-to-end ID starts with the payer participant's ISPB, as in the SPI.
+type CreateSettlementRequest struct {
+	EndToEndID string `json:"endToEndId" validate:"required,end_to_end_id" example:"E1234567820240115103000000000001"`
+	Amount     int64  `json:"amount" validate:"required,min=1" example:"15075"` // in centavos
+	PayerKey   string `json:"payerKey" validate:"required" example:"52998224725"`
+	PayeeKey   string `json:"payeeKey" validate:"required" example:"+5511999999999"`
+}
+
+// ErrSettlementExists is returned by SettlementRepository.Create when the end-to-end ID is already settled
+var ErrSettlementExists error = &Error{Kind: ErrDuplicateKey, Resource: ResourceSettlement}
+
+// SettlementRepository handles storage operations for settlements
+// Lookups return (nil, nil) when no settlement matches.
+type SettlementRepository interface {
+	// Create stores a settlement, failing with ErrSettlementExists if its end-to-end ID is taken
+	Create(ctx context.Context, settlement *Settlement) error
+	// FindByEndToEndID finds a settlement by its end-to-end ID
+	FindByEndToEndID(ctx context.Context, endToEndID string) (*Settlement, error)
+}
+
+// MongoSettlementRepository stores settlements in the settlements collection
+type MongoSettlementRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoSettlementRepository creates a new MongoDB-backed settlement repository
+func NewMongoSettlementRepository(db *db.Mongo) *MongoSettlementRepository {
+	return &MongoSettlementRepository{
+		collection: db.Collection("settlements"),
+	}
+}
+
+// IndexSpec declares the indexes of the settlements collection
+func (r *MongoSettlementRepository) IndexSpec() db.IndexSpec {
+	return db.IndexSpec{
+		Collection: r.collection.Name(),
+		Indexes: []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "endToEndId", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		},
+	}
+}
+
+// EnsureIndexes creates the indexes of the settlements collection
+func (r *MongoSettlementRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, r.IndexSpec().Indexes)
+	return err
+}
+
+// Create stores a settlement
+func (r *MongoSettlementRepository) Create(ctx context.Context, settlement *Settlement) error {
+	result, err := r.collection.InsertOne(ctx, settlement)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrSettlementExists
+	}
+	if err != nil {
+		return err
+	}
+
+	oid, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return errors.New("failed to get inserted ID")
+	}
+	settlement.ID = oid
+
+	return nil
+}
+
+// FindByEndToEndID finds a settlement by its end-to-end ID
+func (r *MongoSettlementRepository) FindByEndToEndID(ctx context.Context, endToEndID string) (*Settlement, error) {
+	var settlement Settlement
+	err := r.collection.FindOne(ctx, bson.M{"endToEndId": endToEndID}).Decode(&settlement)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &settlement, nil
+}
+
+// ToResponse converts Settlement to SettlementResponse
+func (s *Settlement) ToResponse() SettlementResponse {
+	return SettlementResponse{
+		EndToEndID:       s.EndToEndID,
+		Amount:           s.Amount,
+		PayerKey:         s.PayerKey,
+		PayerParticipant: s.PayerParticipant,
+		PayeeKey:         s.PayeeKey,
+		PayeeParticipant: s.PayeeParticipant,
+		SettledAt:        s.SettledAt,
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemorySettlementRepository keeps settlements in process memory (STORAGE=memory)
+type MemorySettlementRepository struct {
+	mu          sync.RWMutex
+	settlements map[string]Settlement // by end-to-end ID
+}
+
+// NewMemorySettlementRepository creates a new in-memory settlement repository
+func NewMemorySettlementRepository() *MemorySettlementRepository {
+	return &MemorySettlementRepository{
+		settlements: map[string]Settlement{},
+	}
+}
+
+// Create stores a settlement
+func (r *MemorySettlementRepository) Create(ctx context.Context, settlement *Settlement) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.settlements[settlement.EndToEndID]; exists {
+		return ErrSettlementExists
+	}
+
+	settlement.ID = primitive.NewObjectID()
+	r.settlements[settlement.EndToEndID] = *settlement
+	return nil
+}
+
+// FindByEndToEndID finds a settlement by its end-to-end ID
+func (r *MemorySettlementRepository) FindByEndToEndID(ctx context.Context, endToEndID string) (*Settlement, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	settlement, ok := r.settlements[endToEndID]
+	if !ok {
+		return nil, nil
+	}
+	return &settlement, nil
+}
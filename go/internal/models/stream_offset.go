@@ -0,0 +1,60 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/dict-simulator/go/internal/db"
+)
+
+// StreamOffset is the last change stream position processed by a consumer
+type StreamOffset struct {
+	Stream      string    `bson:"_id"`
+	ResumeToken bson.Raw  `bson:"resumeToken"`
+	UpdatedAt   time.Time `bson:"updatedAt"`
+}
+
+// StreamOffsetRepository persists change stream resume tokens so consumers survive restarts
+type StreamOffsetRepository struct {
+	collection *mongo.Collection
+}
+
+// NewStreamOffsetRepository creates a new stream offset repository
+func NewStreamOffsetRepository(db *db.Mongo) *StreamOffsetRepository {
+	return &StreamOffsetRepository{
+		collection: db.Collection("stream_offsets"),
+	}
+}
+
+// Find returns the stored resume token for a stream, or nil when the stream has never been consumed
+func (r *StreamOffsetRepository) Find(ctx context.Context, stream string) (bson.Raw, error) {
+	var offset StreamOffset
+	err := r.collection.FindOne(ctx, bson.M{"_id": stream}).Decode(&offset)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return offset.ResumeToken, nil
+}
+
+// Save stores the resume token for a stream
+func (r *StreamOffsetRepository) Save(ctx context.Context, stream string, token bson.Raw) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": stream},
+		bson.M{"$set": bson.M{"resumeToken": token, "updatedAt": time.Now().UTC()}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// Delete forgets the stream position so the next consumer starts from the current head
+func (r *StreamOffsetRepository) Delete(ctx context.Context, stream string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": stream})
+	return err
+}
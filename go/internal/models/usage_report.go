@@ -0,0 +1,170 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/db"
+)
+
+// UsageDayLayout formats the days usage is reported by (UTC, simulated clock)
+const UsageDayLayout = "2006-01-02"
+
+// UsageCounts are the requests a participant made, and the rate limit tokens they consumed
+type UsageCounts struct {
+	Requests       int64 `bson:"requests"`
+	ClientErrors   int64 `bson:"clientErrors"` // 4xx answers
+	ServerErrors   int64 `bson:"serverErrors"` // 5xx answers
+	TokensConsumed int64 `bson:"tokensConsumed"`
+}
+
+// UsageReport is one participant's usage on one day
+type UsageReport struct {
+	Participant string `bson:"participant"`
+	Day         string `bson:"day"` // UsageDayLayout
+	UsageCounts `bson:",inline"`
+	UpdatedAt   time.Time `bson:"updatedAt"`
+}
+
+// UsageReportResponse represents the API response for a participant's usage on one day
+type UsageReportResponse struct {
+	Participant    string    `json:"participant" example:"12345678"`
+	Day            string    `json:"day" example:"2024-01-15"`
+	Requests       int64     `json:"requests" example:"1200"`
+	ClientErrors   int64     `json:"clientErrors" example:"30"`
+	ServerErrors   int64     `json:"serverErrors" example:"2"`
+	ErrorRate      float64   `json:"errorRate" example:"0.0267"` // (clientErrors + serverErrors) / requests
+	TokensConsumed int64     `json:"tokensConsumed" example:"1290"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// UsageReportFilter selects usage reports; empty fields match every report
+type UsageReportFilter struct {
+	Participant string
+	From        string // first day, inclusive (UsageDayLayout)
+	To          string // last day, inclusive (UsageDayLayout)
+}
+
+// UsageReportRepository handles storage operations for usage reports
+type UsageReportRepository interface {
+	// Add adds counts to a participant's report for day, creating it if needed
+	Add(ctx context.Context, participant, day string, counts UsageCounts) error
+	// List returns the reports filter selects, by day and then participant
+	List(ctx context.Context, filter UsageReportFilter) ([]UsageReport, error)
+}
+
+// MongoUsageReportRepository stores usage reports in the usage_reports collection
+type MongoUsageReportRepository struct {
+	collection *mongo.Collection
+	clock      clock.Clock
+}
+
+// NewMongoUsageReportRepository creates a new MongoDB-backed usage report repository
+func NewMongoUsageReportRepository(db *db.Mongo, clk clock.Clock) *MongoUsageReportRepository {
+	return &MongoUsageReportRepository{
+		collection: db.Collection("usage_reports"),
+		clock:      clk,
+	}
+}
+
+// IndexSpec declares the indexes of the usage_reports collection
+func (r *MongoUsageReportRepository) IndexSpec() db.IndexSpec {
+	return db.IndexSpec{
+		Collection: r.collection.Name(),
+		Indexes: []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "day", Value: 1}, {Key: "participant", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		},
+	}
+}
+
+// EnsureIndexes creates the indexes of the usage_reports collection
+func (r *MongoUsageReportRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, r.IndexSpec().Indexes)
+	return err
+}
+
+// Add adds counts to a participant's report for day
+// Replicas add their own counts, so the increments are atomic.
+func (r *MongoUsageReportRepository) Add(ctx context.Context, participant, day string, counts UsageCounts) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"participant": participant, "day": day},
+		bson.M{
+			"$inc": bson.M{
+				"requests":       counts.Requests,
+				"clientErrors":   counts.ClientErrors,
+				"serverErrors":   counts.ServerErrors,
+				"tokensConsumed": counts.TokensConsumed,
+			},
+			"$set": bson.M{"updatedAt": r.clock.Now().UTC()},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// List returns the reports filter selects, by day and then participant
+func (r *MongoUsageReportRepository) List(ctx context.Context, filter UsageReportFilter) ([]UsageReport, error) {
+	query := bson.M{}
+	if filter.Participant != "" {
+		query["participant"] = filter.Participant
+	}
+	days := bson.M{}
+	if filter.From != "" {
+		days["$gte"] = filter.From
+	}
+	if filter.To != "" {
+		days["$lte"] = filter.To
+	}
+	if len(days) > 0 {
+		query["day"] = days
+	}
+
+	cursor, err := r.collection.Find(ctx, query,
+		options.Find().SetSort(bson.D{{Key: "day", Value: 1}, {Key: "participant", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := []UsageReport{}
+	if err := cursor.All(ctx, &reports); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// Add returns the sum of two counts
+func (c UsageCounts) Add(other UsageCounts) UsageCounts {
+	return UsageCounts{
+		Requests:       c.Requests + other.Requests,
+		ClientErrors:   c.ClientErrors + other.ClientErrors,
+		ServerErrors:   c.ServerErrors + other.ServerErrors,
+		TokensConsumed: c.TokensConsumed + other.TokensConsumed,
+	}
+}
+
+// ToResponse converts UsageReport to UsageReportResponse, working out the error rate
+func (u *UsageReport) ToResponse() UsageReportResponse {
+	var errorRate float64
+	if u.Requests > 0 {
+		errorRate = float64(u.ClientErrors+u.ServerErrors) / float64(u.Requests)
+	}
+	return UsageReportResponse{
+		Participant:    u.Participant,
+		Day:            u.Day,
+		Requests:       u.Requests,
+		ClientErrors:   u.ClientErrors,
+		ServerErrors:   u.ServerErrors,
+		ErrorRate:      errorRate,
+		TokensConsumed: u.TokensConsumed,
+		UpdatedAt:      u.UpdatedAt,
+	}
+}
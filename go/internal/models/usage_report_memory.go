@@ -0,0 +1,69 @@
+package models
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/dict-simulator/go/internal/clock"
+)
+
+// usageReportKey identifies a participant's report for one day
+type usageReportKey struct {
+	participant string
+	day         string
+}
+
+// MemoryUsageReportRepository keeps usage reports in process memory (STORAGE=memory)
+type MemoryUsageReportRepository struct {
+	mu      sync.RWMutex
+	reports map[usageReportKey]UsageReport
+	clock   clock.Clock
+}
+
+// NewMemoryUsageReportRepository creates a new in-memory usage report repository
+func NewMemoryUsageReportRepository(clk clock.Clock) *MemoryUsageReportRepository {
+	return &MemoryUsageReportRepository{
+		reports: make(map[usageReportKey]UsageReport),
+		clock:   clk,
+	}
+}
+
+// Add adds counts to a participant's report for day
+func (r *MemoryUsageReportRepository) Add(ctx context.Context, participant, day string, counts UsageCounts) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := usageReportKey{participant: participant, day: day}
+	report := r.reports[key]
+	report.Participant = participant
+	report.Day = day
+	report.UsageCounts = report.UsageCounts.Add(counts)
+	report.UpdatedAt = r.clock.Now().UTC()
+	r.reports[key] = report
+	return nil
+}
+
+// List returns the reports filter selects, by day and then participant
+func (r *MemoryUsageReportRepository) List(ctx context.Context, filter UsageReportFilter) ([]UsageReport, error) {
+	r.mu.RLock()
+	all := slices.Collect(maps.Values(r.reports))
+	r.mu.RUnlock()
+
+	reports := []UsageReport{}
+	for _, report := range all {
+		if filter.Participant != "" && report.Participant != filter.Participant {
+			continue
+		}
+		if (filter.From != "" && report.Day < filter.From) || (filter.To != "" && report.Day > filter.To) {
+			continue
+		}
+		reports = append(reports, report)
+	}
+	slices.SortFunc(reports, func(a, b UsageReport) int {
+		return cmp.Or(cmp.Compare(a.Day, b.Day), cmp.Compare(a.Participant, b.Participant))
+	})
+	return reports, nil
+}
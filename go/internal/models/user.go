@@ -12,6 +12,7 @@ import (
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/dict-simulator/go/internal/db"
+	"github.com/dict-simulator/go/internal/listing"
 )
 
 type User struct {
@@ -19,56 +20,127 @@ type User struct {
 	Email     string             `bson:"email" json:"email"`
 	Password  string             `bson:"password" json:"-"`
 	Name      string             `bson:"name" json:"name"`
+	Disabled  bool               `bson:"disabled" json:"disabled"`
 	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
 }
 
 type UserResponse struct {
-	ID    string `json:"id" example:"507f1f77bcf86cd799439011"`
-	Email string `json:"email" example:"user@example.com"`
-	Name  string `json:"name" example:"John Doe"`
+	ID        string    `json:"id" example:"507f1f77bcf86cd799439011"`
+	Email     string    `json:"email" example:"user@example.com"`
+	Name      string    `json:"name" example:"John Doe"`
+	Disabled  bool      `json:"disabled" example:"false"` // disabled users can't log in and their tokens are refused
+	CreatedAt time.Time `json:"createdAt" example:"2024-01-15T10:30:00Z"`
 }
 
-// UserRepository handles database operations for users
-type UserRepository struct {
+// UpdateProfileRequest represents the request body for updating the caller's profile
+// Fields left out keep their current value.
+type UpdateProfileRequest struct {
+	Email string `json:"email,omitempty" validate:"omitempty,email" example:"new@example.com"`
+	Name  string `json:"name,omitempty" example:"Jane Doe"`
+}
+
+// UserPage is one page of users
+// NextCursor is set when more users follow; pass it back as cursor to read them.
+type UserPage struct {
+	Users      []UserResponse `json:"users"`
+	NextCursor string         `json:"nextCursor,omitempty" example:"507f1f77bcf86cd799439011"`
+}
+
+// ErrUserEmailExists is returned by UserRepository.Create and UpdateProfile when another user has the email
+var ErrUserEmailExists error = &Error{Kind: ErrDuplicateKey, Resource: ResourceUser}
+
+// UserRepository handles storage operations for users
+// Lookups return (nil, nil) when no user matches.
+type UserRepository interface {
+	// Create stores a new user with a hashed password, or returns ErrUserEmailExists if the email is taken
+	Create(ctx context.Context, email, password, name string) (*User, error)
+	// FindByEmail finds a user by email
+	FindByEmail(ctx context.Context, email string) (*User, error)
+	// FindByID finds a user by ID
+	FindByID(ctx context.Context, id primitive.ObjectID) (*User, error)
+	// UpdatePassword replaces a user's password with a hash of password
+	// It returns false if no user has this ID.
+	UpdatePassword(ctx context.Context, id primitive.ObjectID, password string) (bool, error)
+	// UpdateProfile sets a user's email and name, keeping the current value of empty ones
+	// It returns the updated user, or ErrUserEmailExists if another user has the email.
+	UpdateProfile(ctx context.Context, id primitive.ObjectID, email, name string) (*User, error)
+	// SetDisabled disables or re-enables a user; it returns false if no user has this ID
+	SetDisabled(ctx context.Context, id primitive.ObjectID, disabled bool) (bool, error)
+	// DeleteByID removes a user; it returns false if no user has this ID
+	DeleteByID(ctx context.Context, id primitive.ObjectID) (bool, error)
+	// List returns a page of users in ID (creation) order; page.After is an ID in hex
+	List(ctx context.Context, page listing.Page) ([]User, error)
+}
+
+// MongoUserRepository stores users in the users collection
+type MongoUserRepository struct {
 	collection *mongo.Collection
 }
 
-// NewUserRepository creates a new user repository
-func NewUserRepository(db *db.Mongo) *UserRepository {
-	return &UserRepository{
+// NewMongoUserRepository creates a new MongoDB-backed user repository
+func NewMongoUserRepository(db *db.Mongo) *MongoUserRepository {
+	return &MongoUserRepository{
 		collection: db.Collection("users"),
 	}
 }
 
-// EnsureIndexes creates necessary indexes for the users collection
-func (r *UserRepository) EnsureIndexes(ctx context.Context) error {
-	indexModel := mongo.IndexModel{
-		Keys:    bson.D{{Key: "email", Value: 1}},
-		Options: options.Index().SetUnique(true),
+// IndexSpec declares the indexes of the users collection
+func (r *MongoUserRepository) IndexSpec() db.IndexSpec {
+	return db.IndexSpec{
+		Collection: r.collection.Name(),
+		Indexes: []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "email", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		},
 	}
+}
 
-	_, err := r.collection.Indexes().CreateOne(ctx, indexModel)
+// EnsureIndexes creates the indexes of the users collection
+func (r *MongoUserRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, r.IndexSpec().Indexes)
 	return err
 }
 
-// Create creates a new user with hashed password
-func (r *UserRepository) Create(ctx context.Context, email, password, name string) (*User, error) {
+// hashPassword hashes a password for storage
+func hashPassword(password string) (string, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashedPassword), nil
+}
+
+// newUser builds a User, hashing the password
+func newUser(email, password, name string) (*User, error) {
+	hashedPassword, err := hashPassword(password)
 	if err != nil {
 		return nil, err
 	}
 
-	now := time.Now()
-	user := &User{
+	now := time.Now().UTC()
+	return &User{
 		Email:     email,
-		Password:  string(hashedPassword),
+		Password:  hashedPassword,
 		Name:      name,
 		CreatedAt: now,
 		UpdatedAt: now,
+	}, nil
+}
+
+// Create creates a new user with hashed password
+func (r *MongoUserRepository) Create(ctx context.Context, email, password, name string) (*User, error) {
+	user, err := newUser(email, password, name)
+	if err != nil {
+		return nil, err
 	}
 
 	result, err := r.collection.InsertOne(ctx, user)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrUserEmailExists
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -83,7 +155,7 @@ func (r *UserRepository) Create(ctx context.Context, email, password, name strin
 }
 
 // FindByEmail finds a user by email
-func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*User, error) {
+func (r *MongoUserRepository) FindByEmail(ctx context.Context, email string) (*User, error) {
 	var user User
 	err := r.collection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
 	if err != nil {
@@ -95,6 +167,115 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*User,
 	return &user, nil
 }
 
+// FindByID finds a user by ID
+func (r *MongoUserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*User, error) {
+	var user User
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdatePassword replaces a user's password with a hash of password
+func (r *MongoUserRepository) UpdatePassword(ctx context.Context, id primitive.ObjectID, password string) (bool, error) {
+	hashedPassword, err := hashPassword(password)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"password": hashedPassword, "updatedAt": time.Now().UTC()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// UpdateProfile sets a user's email and name, keeping the current value of empty ones
+// The unique email index turns a taken email into ErrUserEmailExists.
+func (r *MongoUserRepository) UpdateProfile(ctx context.Context, id primitive.ObjectID, email, name string) (*User, error) {
+	set := bson.M{"updatedAt": time.Now().UTC()}
+	if email != "" {
+		set["email"] = email
+	}
+	if name != "" {
+		set["name"] = name
+	}
+
+	var user User
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrUserEmailExists
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// SetDisabled disables or re-enables a user
+func (r *MongoUserRepository) SetDisabled(ctx context.Context, id primitive.ObjectID, disabled bool) (bool, error) {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"disabled": disabled, "updatedAt": time.Now().UTC()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// DeleteByID removes a user
+func (r *MongoUserRepository) DeleteByID(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// List returns a page of users in _id order
+func (r *MongoUserRepository) List(ctx context.Context, page listing.Page) ([]User, error) {
+	op, order := "$gt", 1
+	if page.Descending {
+		op, order = "$lt", -1
+	}
+	filter := bson.M{}
+	if page.After != "" {
+		after, err := primitive.ObjectIDFromHex(page.After)
+		if err != nil {
+			return nil, err
+		}
+		filter["_id"] = bson.M{op: after}
+	}
+
+	cursor, err := r.collection.Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "_id", Value: order}}).SetLimit(int64(page.Limit)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	users := []User{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 // CheckPassword compares the provided password with the stored hash
 func (u *User) CheckPassword(password string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
@@ -104,8 +285,10 @@ func (u *User) CheckPassword(password string) bool {
 // ToResponse converts User to UserResponse
 func (u *User) ToResponse() UserResponse {
 	return UserResponse{
-		ID:    u.ID.Hex(),
-		Email: u.Email,
-		Name:  u.Name,
+		ID:        u.ID.Hex(),
+		Email:     u.Email,
+		Name:      u.Name,
+		Disabled:  u.Disabled,
+		CreatedAt: u.CreatedAt,
 	}
 }
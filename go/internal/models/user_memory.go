@@ -0,0 +1,176 @@
+package models
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/dict-simulator/go/internal/listing"
+)
+
+// MemoryUserRepository keeps users in process memory (STORAGE=memory)
+type MemoryUserRepository struct {
+	mu    sync.RWMutex
+	users map[string]User // by email
+}
+
+// NewMemoryUserRepository creates a new in-memory user repository
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{
+		users: map[string]User{},
+	}
+}
+
+// Create creates a new user with hashed password
+func (r *MemoryUserRepository) Create(ctx context.Context, email, password, name string) (*User, error) {
+	user, err := newUser(email, password, name)
+	if err != nil {
+		return nil, err
+	}
+	user.ID = primitive.NewObjectID()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[email]; ok {
+		return nil, ErrUserEmailExists
+	}
+	r.users[email] = *user
+
+	return user, nil
+}
+
+// FindByEmail finds a user by email
+func (r *MemoryUserRepository) FindByEmail(ctx context.Context, email string) (*User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[email]
+	if !ok {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+// FindByID finds a user by ID
+// Users are kept by email, so this scans them all; the memory backend only holds test data.
+func (r *MemoryUserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.ID == id {
+			return &user, nil
+		}
+	}
+	return nil, nil
+}
+
+// UpdatePassword replaces a user's password with a hash of password
+func (r *MemoryUserRepository) UpdatePassword(ctx context.Context, id primitive.ObjectID, password string) (bool, error) {
+	hashedPassword, err := hashPassword(password)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for email, user := range r.users {
+		if user.ID == id {
+			user.Password = hashedPassword
+			user.UpdatedAt = time.Now().UTC()
+			r.users[email] = user
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// UpdateProfile sets a user's email and name, keeping the current value of empty ones
+func (r *MemoryUserRepository) UpdateProfile(ctx context.Context, id primitive.ObjectID, email, name string) (*User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for current, user := range r.users {
+		if user.ID != id {
+			continue
+		}
+		if email != "" && email != current {
+			if _, taken := r.users[email]; taken {
+				return nil, ErrUserEmailExists
+			}
+			delete(r.users, current)
+			user.Email = email
+		}
+		if name != "" {
+			user.Name = name
+		}
+		user.UpdatedAt = time.Now().UTC()
+		r.users[user.Email] = user
+		return &user, nil
+	}
+	return nil, nil
+}
+
+// SetDisabled disables or re-enables a user
+func (r *MemoryUserRepository) SetDisabled(ctx context.Context, id primitive.ObjectID, disabled bool) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for email, user := range r.users {
+		if user.ID == id {
+			user.Disabled = disabled
+			user.UpdatedAt = time.Now().UTC()
+			r.users[email] = user
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// DeleteByID removes a user
+func (r *MemoryUserRepository) DeleteByID(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for email, user := range r.users {
+		if user.ID == id {
+			delete(r.users, email)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// List returns a page of users in ID order
+func (r *MemoryUserRepository) List(ctx context.Context, page listing.Page) ([]User, error) {
+	// order compares IDs in the listing's direction
+	order := strings.Compare
+	if page.Descending {
+		order = func(a, b string) int { return strings.Compare(b, a) }
+	}
+
+	r.mu.RLock()
+	users := slices.Collect(maps.Values(r.users))
+	r.mu.RUnlock()
+
+	slices.SortFunc(users, func(a, b User) int {
+		return order(a.ID.Hex(), b.ID.Hex())
+	})
+	if page.After != "" {
+		start, _ := slices.BinarySearchFunc(users, page.After, func(u User, hex string) int {
+			return order(u.ID.Hex(), hex)
+		})
+		for start < len(users) && users[start].ID.Hex() == page.After {
+			start++
+		}
+		users = users[start:]
+	}
+	return users[:min(page.Limit, len(users))], nil
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/dict-simulator/go/internal/listing"
+)
+
+func TestMemoryUserRepositoryList(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryUserRepository()
+	var ids []primitive.ObjectID
+	for i := range 5 {
+		user, err := repo.Create(ctx, fmt.Sprintf("user%d@example.com", i), "password123", "User")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, user.ID)
+	}
+
+	// Pages follow creation order, or its reverse, and pick up after the cursor
+	list := func(descending bool) []primitive.ObjectID {
+		var listed []primitive.ObjectID
+		page := listing.Page{Limit: 2, Descending: descending}
+		for {
+			users, err := repo.List(ctx, page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(users) == 0 {
+				return listed
+			}
+			for _, user := range users {
+				listed = append(listed, user.ID)
+			}
+			page.After = users[len(users)-1].ID.Hex()
+		}
+	}
+	if listed := list(false); fmt.Sprint(listed) != fmt.Sprint(ids) {
+		t.Errorf("listed %v, want %v", listed, ids)
+	}
+	slices.Reverse(ids)
+	if listed := list(true); fmt.Sprint(listed) != fmt.Sprint(ids) {
+		t.Errorf("listed descending %v, want %v", listed, ids)
+	}
+}
+
+func TestMemoryUserRepositoryUpdateProfile(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryUserRepository()
+	first, _ := repo.Create(ctx, "first@example.com", "password123", "First")
+	if _, err := repo.Create(ctx, "second@example.com", "password123", "Second"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.UpdateProfile(ctx, first.ID, "second@example.com", ""); !errors.Is(err, ErrUserEmailExists) {
+		t.Fatalf("update to a taken email error = %v, want ErrUserEmailExists", err)
+	}
+
+	updated, err := repo.UpdateProfile(ctx, first.ID, "renamed@example.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Email != "renamed@example.com" || updated.Name != "First" {
+		t.Errorf("updated user = %s %q, want renamed@example.com with the name kept", updated.Email, updated.Name)
+	}
+	if user, _ := repo.FindByEmail(ctx, "first@example.com"); user != nil {
+		t.Error("old email still finds the user")
+	}
+	if user, _ := repo.FindByEmail(ctx, "renamed@example.com"); user == nil || user.ID != first.ID {
+		t.Errorf("new email finds %v, want the updated user", user)
+	}
+
+	if user, err := repo.UpdateProfile(ctx, primitive.NewObjectID(), "", "Nobody"); user != nil || err != nil {
+		t.Errorf("update of an unknown user = %v, %v, want nil, nil", user, err)
+	}
+}
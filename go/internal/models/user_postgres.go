@@ -0,0 +1,181 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/dict-simulator/go/internal/db"
+	"github.com/dict-simulator/go/internal/listing"
+)
+
+// PostgresUserRepository stores users in the users table
+type PostgresUserRepository struct {
+	pg *db.Postgres
+}
+
+// NewPostgresUserRepository creates a new PostgreSQL-backed user repository
+func NewPostgresUserRepository(pg *db.Postgres) *PostgresUserRepository {
+	return &PostgresUserRepository{
+		pg: pg,
+	}
+}
+
+// Create creates a new user with hashed password
+func (r *PostgresUserRepository) Create(ctx context.Context, email, password, name string) (*User, error) {
+	user, err := newUser(email, password, name)
+	if err != nil {
+		return nil, err
+	}
+	user.ID = primitive.NewObjectID()
+
+	_, err = r.pg.Pool.Exec(ctx,
+		`INSERT INTO users (id, email, password, name, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)`,
+		user.ID.Hex(), user.Email, user.Password, user.Name, user.CreatedAt, user.UpdatedAt,
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+		return nil, ErrUserEmailExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// FindByEmail finds a user by email
+func (r *PostgresUserRepository) FindByEmail(ctx context.Context, email string) (*User, error) {
+	var user User
+	var id string
+	err := r.pg.Pool.QueryRow(ctx,
+		`SELECT id, email, password, name, disabled, created_at, updated_at FROM users WHERE email = $1`,
+		email,
+	).Scan(&id, &user.Email, &user.Password, &user.Name, &user.Disabled, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	user.ID, err = primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByID finds a user by ID
+func (r *PostgresUserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*User, error) {
+	user := User{ID: id}
+	err := r.pg.Pool.QueryRow(ctx,
+		`SELECT email, password, name, disabled, created_at, updated_at FROM users WHERE id = $1`,
+		id.Hex(),
+	).Scan(&user.Email, &user.Password, &user.Name, &user.Disabled, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdatePassword replaces a user's password with a hash of password
+func (r *PostgresUserRepository) UpdatePassword(ctx context.Context, id primitive.ObjectID, password string) (bool, error) {
+	hashedPassword, err := hashPassword(password)
+	if err != nil {
+		return false, err
+	}
+
+	tag, err := r.pg.Pool.Exec(ctx,
+		`UPDATE users SET password = $2, updated_at = $3 WHERE id = $1`,
+		id.Hex(), hashedPassword, time.Now().UTC(),
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// UpdateProfile sets a user's email and name, keeping the current value of empty ones
+// The unique email constraint turns a taken email into ErrUserEmailExists.
+func (r *PostgresUserRepository) UpdateProfile(ctx context.Context, id primitive.ObjectID, email, name string) (*User, error) {
+	user := User{ID: id}
+	err := r.pg.Pool.QueryRow(ctx,
+		`UPDATE users SET email = COALESCE(NULLIF($2, ''), email), name = COALESCE(NULLIF($3, ''), name), updated_at = $4
+		WHERE id = $1
+		RETURNING email, password, name, disabled, created_at, updated_at`,
+		id.Hex(), email, name, time.Now().UTC(),
+	).Scan(&user.Email, &user.Password, &user.Name, &user.Disabled, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+			return nil, ErrUserEmailExists
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// SetDisabled disables or re-enables a user
+func (r *PostgresUserRepository) SetDisabled(ctx context.Context, id primitive.ObjectID, disabled bool) (bool, error) {
+	tag, err := r.pg.Pool.Exec(ctx,
+		`UPDATE users SET disabled = $2, updated_at = $3 WHERE id = $1`,
+		id.Hex(), disabled, time.Now().UTC(),
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// DeleteByID removes a user
+func (r *PostgresUserRepository) DeleteByID(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	tag, err := r.pg.Pool.Exec(ctx, `DELETE FROM users WHERE id = $1`, id.Hex())
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// List returns a page of users in id order
+// IDs are ObjectID hex strings, so text order is creation order.
+func (r *PostgresUserRepository) List(ctx context.Context, page listing.Page) ([]User, error) {
+	op, order := ">", "ASC"
+	if page.Descending {
+		op, order = "<", "DESC"
+	}
+
+	rows, err := r.pg.Pool.Query(ctx,
+		`SELECT id, email, password, name, disabled, created_at, updated_at FROM users
+		WHERE ($1 = '' OR id `+op+` $1) ORDER BY id `+order+` LIMIT $2`,
+		page.After, page.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []User{}
+	for rows.Next() {
+		var user User
+		var id string
+		if err := rows.Scan(&id, &user.Email, &user.Password, &user.Name, &user.Disabled, &user.CreatedAt, &user.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if user.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
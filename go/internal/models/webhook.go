@@ -0,0 +1,262 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/db"
+	"github.com/dict-simulator/go/internal/events"
+)
+
+// webhookDeliveriesLimit caps how many delivery attempts are returned per webhook
+const webhookDeliveriesLimit = 100
+
+// Webhook is a participant's callback registration
+type Webhook struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	Participant string             `bson:"participant"`
+	URL         string             `bson:"url"`
+	Secret      string             `bson:"secret"`
+	Events      []events.Type      `bson:"events"`
+	CreatedAt   time.Time          `bson:"createdAt"`
+}
+
+// WebhookResponse represents the API response for a webhook
+// Secret is only returned when the webhook is created
+type WebhookResponse struct {
+	ID          string        `json:"id" example:"507f1f77bcf86cd799439011"`
+	Participant string        `json:"participant" example:"12345678"`
+	URL         string        `json:"url" example:"https://psp.example.com/dict/callbacks"`
+	Events      []events.Type `json:"events" example:"ENTRY_CREATED,ENTRY_DELETED"`
+	Secret      string        `json:"secret,omitempty" example:"3f9a6c1e..."`
+	CreatedAt   time.Time     `json:"createdAt"`
+}
+
+// CreateWebhookRequest represents the request body for registering a webhook
+type CreateWebhookRequest struct {
+	Participant string        `json:"participant" validate:"required,len=8,numeric" example:"12345678"`
+	URL         string        `json:"url" validate:"required,http_url" example:"https://psp.example.com/dict/callbacks"`
+	Events      []events.Type `json:"events" validate:"required,min=1,dive,oneof=ENTRY_CREATED ENTRY_UPDATED ENTRY_DELETED CLAIM_OPENED CLAIM_COMPLETED INFRACTION_CREATED" example:"ENTRY_CREATED,ENTRY_DELETED"`
+	Secret      string        `json:"secret,omitempty" validate:"omitempty,min=16" example:"my-shared-signing-secret"` // generated when omitted
+}
+
+// WebhookDelivery records a single delivery attempt
+type WebhookDelivery struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	WebhookID  primitive.ObjectID `bson:"webhookId" json:"-"`
+	EventID    string             `bson:"eventId" json:"eventId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	EventType  events.Type        `bson:"eventType" json:"eventType" example:"ENTRY_CREATED"`
+	URL        string             `bson:"url" json:"url" example:"https://psp.example.com/dict/callbacks"`
+	Attempt    int                `bson:"attempt" json:"attempt" example:"1"`
+	StatusCode int                `bson:"statusCode,omitempty" json:"statusCode,omitempty" example:"200"`
+	Error      string             `bson:"error,omitempty" json:"error,omitempty"`
+	Success    bool               `bson:"success" json:"success" example:"true"`
+	DurationMs int64              `bson:"durationMs" json:"durationMs" example:"42"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// WebhookRepository handles storage operations for webhooks
+// Lookups return (nil, nil) when no webhook matches.
+type WebhookRepository interface {
+	// Create registers a new webhook
+	Create(ctx context.Context, req *CreateWebhookRequest) (*Webhook, error)
+	// FindByID finds a webhook by its ID
+	FindByID(ctx context.Context, id primitive.ObjectID) (*Webhook, error)
+	// FindByParticipant lists the webhooks registered by a participant, oldest first
+	FindByParticipant(ctx context.Context, participant string) ([]Webhook, error)
+	// FindSubscribers lists the participant's webhooks subscribed to the event type, oldest first
+	FindSubscribers(ctx context.Context, participant string, eventType events.Type) ([]Webhook, error)
+	// DeleteByID removes a webhook and reports whether it existed
+	DeleteByID(ctx context.Context, id primitive.ObjectID) (bool, error)
+}
+
+// MongoWebhookRepository stores webhooks in the webhooks collection
+type MongoWebhookRepository struct {
+	collection *mongo.Collection
+	clock      clock.Clock
+}
+
+// NewMongoWebhookRepository creates a new MongoDB-backed webhook repository
+func NewMongoWebhookRepository(db *db.Mongo, clk clock.Clock) *MongoWebhookRepository {
+	return &MongoWebhookRepository{
+		collection: db.Collection("webhooks"),
+		clock:      clk,
+	}
+}
+
+// IndexSpec declares the indexes of the webhooks collection
+func (r *MongoWebhookRepository) IndexSpec() db.IndexSpec {
+	return db.IndexSpec{
+		Collection: r.collection.Name(),
+		Indexes: []mongo.IndexModel{
+			{
+				Keys: bson.D{{Key: "participant", Value: 1}, {Key: "events", Value: 1}},
+			},
+		},
+	}
+}
+
+// EnsureIndexes creates the indexes of the webhooks collection
+func (r *MongoWebhookRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, r.IndexSpec().Indexes)
+	return err
+}
+
+// Create registers a new webhook
+func (r *MongoWebhookRepository) Create(ctx context.Context, req *CreateWebhookRequest) (*Webhook, error) {
+	webhook := &Webhook{
+		Participant: req.Participant,
+		URL:         req.URL,
+		Secret:      req.Secret,
+		Events:      req.Events,
+		CreatedAt:   r.clock.Now().UTC(),
+	}
+
+	result, err := r.collection.InsertOne(ctx, webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	oid, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, errors.New("failed to get inserted ID")
+	}
+	webhook.ID = oid
+
+	return webhook, nil
+}
+
+// FindByID finds a webhook by its ID
+func (r *MongoWebhookRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*Webhook, error) {
+	var webhook Webhook
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&webhook)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// FindByParticipant lists the webhooks registered by a participant
+func (r *MongoWebhookRepository) FindByParticipant(ctx context.Context, participant string) ([]Webhook, error) {
+	return r.find(ctx, bson.M{"participant": participant})
+}
+
+// FindSubscribers lists the participant's webhooks subscribed to the event type
+func (r *MongoWebhookRepository) FindSubscribers(ctx context.Context, participant string, eventType events.Type) ([]Webhook, error) {
+	return r.find(ctx, bson.M{"participant": participant, "events": eventType})
+}
+
+func (r *MongoWebhookRepository) find(ctx context.Context, filter bson.M) ([]Webhook, error) {
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := []Webhook{}
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// DeleteByID removes a webhook and reports whether it existed
+func (r *MongoWebhookRepository) DeleteByID(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// ToResponse converts Webhook to WebhookResponse without the secret
+func (w *Webhook) ToResponse() WebhookResponse {
+	return WebhookResponse{
+		ID:          w.ID.Hex(),
+		Participant: w.Participant,
+		URL:         w.URL,
+		Events:      w.Events,
+		CreatedAt:   w.CreatedAt,
+	}
+}
+
+// WebhookDeliveryRepository handles storage operations for the delivery log
+type WebhookDeliveryRepository interface {
+	// Create appends a delivery attempt to the log
+	Create(ctx context.Context, delivery *WebhookDelivery) error
+	// FindByWebhookID returns the most recent delivery attempts for a webhook, newest first
+	FindByWebhookID(ctx context.Context, webhookID primitive.ObjectID) ([]WebhookDelivery, error)
+	// DeleteCreatedBefore deletes the attempts made at or before cutoff and returns how many
+	DeleteCreatedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// MongoWebhookDeliveryRepository stores delivery attempts in the webhook_deliveries collection
+type MongoWebhookDeliveryRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoWebhookDeliveryRepository creates a new MongoDB-backed webhook delivery repository
+func NewMongoWebhookDeliveryRepository(db *db.Mongo) *MongoWebhookDeliveryRepository {
+	return &MongoWebhookDeliveryRepository{
+		collection: db.Collection("webhook_deliveries"),
+	}
+}
+
+// IndexSpec declares the indexes of the webhook_deliveries collection
+func (r *MongoWebhookDeliveryRepository) IndexSpec() db.IndexSpec {
+	return db.IndexSpec{
+		Collection: r.collection.Name(),
+		Indexes: []mongo.IndexModel{
+			{Keys: bson.D{{Key: "webhookId", Value: 1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "createdAt", Value: 1}}},
+		},
+	}
+}
+
+// EnsureIndexes creates the indexes of the webhook_deliveries collection
+func (r *MongoWebhookDeliveryRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, r.IndexSpec().Indexes)
+	return err
+}
+
+// Create appends a delivery attempt to the log
+func (r *MongoWebhookDeliveryRepository) Create(ctx context.Context, delivery *WebhookDelivery) error {
+	_, err := r.collection.InsertOne(ctx, delivery)
+	return err
+}
+
+// FindByWebhookID returns the most recent delivery attempts for a webhook, newest first
+func (r *MongoWebhookDeliveryRepository) FindByWebhookID(ctx context.Context, webhookID primitive.ObjectID) ([]WebhookDelivery, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(webhookDeliveriesLimit)
+
+	cursor, err := r.collection.Find(ctx, bson.M{"webhookId": webhookID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []WebhookDelivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// DeleteCreatedBefore deletes the attempts made at or before cutoff
+func (r *MongoWebhookDeliveryRepository) DeleteCreatedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"createdAt": bson.M{"$lte": cutoff.UTC()}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
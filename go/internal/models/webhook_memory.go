@@ -0,0 +1,165 @@
+package models
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/events"
+)
+
+// MemoryWebhookRepository keeps webhooks in process memory (STORAGE=memory)
+type MemoryWebhookRepository struct {
+	mu       sync.RWMutex
+	webhooks []Webhook // in registration order
+	clock    clock.Clock
+}
+
+// NewMemoryWebhookRepository creates a new in-memory webhook repository
+func NewMemoryWebhookRepository(clk clock.Clock) *MemoryWebhookRepository {
+	return &MemoryWebhookRepository{
+		clock: clk,
+	}
+}
+
+// Create registers a new webhook
+func (r *MemoryWebhookRepository) Create(ctx context.Context, req *CreateWebhookRequest) (*Webhook, error) {
+	webhook := &Webhook{
+		ID:          primitive.NewObjectID(),
+		Participant: req.Participant,
+		URL:         req.URL,
+		Secret:      req.Secret,
+		Events:      slices.Clone(req.Events),
+		CreatedAt:   r.clock.Now().UTC(),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.webhooks = append(r.webhooks, *webhook)
+	return webhook, nil
+}
+
+// FindByID finds a webhook by its ID
+func (r *MemoryWebhookRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, webhook := range r.webhooks {
+		if webhook.ID == id {
+			webhook.Events = slices.Clone(webhook.Events)
+			return &webhook, nil
+		}
+	}
+	return nil, nil
+}
+
+// FindByParticipant lists the webhooks registered by a participant
+func (r *MemoryWebhookRepository) FindByParticipant(ctx context.Context, participant string) ([]Webhook, error) {
+	return r.find(func(w *Webhook) bool {
+		return w.Participant == participant
+	}), nil
+}
+
+// FindSubscribers lists the participant's webhooks subscribed to the event type
+func (r *MemoryWebhookRepository) FindSubscribers(ctx context.Context, participant string, eventType events.Type) ([]Webhook, error) {
+	return r.find(func(w *Webhook) bool {
+		return w.Participant == participant && slices.Contains(w.Events, eventType)
+	}), nil
+}
+
+func (r *MemoryWebhookRepository) find(match func(*Webhook) bool) []Webhook {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhooks := []Webhook{}
+	for _, webhook := range r.webhooks {
+		if match(&webhook) {
+			webhook.Events = slices.Clone(webhook.Events)
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks
+}
+
+// DeleteByID removes a webhook and reports whether it existed
+func (r *MemoryWebhookRepository) DeleteByID(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before := len(r.webhooks)
+	r.webhooks = slices.DeleteFunc(r.webhooks, func(w Webhook) bool {
+		return w.ID == id
+	})
+	return len(r.webhooks) < before, nil
+}
+
+// MemoryWebhookDeliveryRepository keeps the delivery log in process memory (STORAGE=memory)
+// Only the most recent attempts per webhook are kept, since no more are ever listed.
+type MemoryWebhookDeliveryRepository struct {
+	mu         sync.RWMutex
+	deliveries map[primitive.ObjectID][]WebhookDelivery // by webhook, newest first
+}
+
+// NewMemoryWebhookDeliveryRepository creates a new in-memory webhook delivery repository
+func NewMemoryWebhookDeliveryRepository() *MemoryWebhookDeliveryRepository {
+	return &MemoryWebhookDeliveryRepository{
+		deliveries: map[primitive.ObjectID][]WebhookDelivery{},
+	}
+}
+
+// Create appends a delivery attempt to the log
+func (r *MemoryWebhookDeliveryRepository) Create(ctx context.Context, delivery *WebhookDelivery) error {
+	record := *delivery
+	if record.ID.IsZero() {
+		record.ID = primitive.NewObjectID()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	log := append([]WebhookDelivery{record}, r.deliveries[record.WebhookID]...)
+	// Attempts can be recorded out of order when the clock is rewound
+	sort.SliceStable(log, func(i, j int) bool {
+		return log[i].CreatedAt.After(log[j].CreatedAt)
+	})
+	if len(log) > webhookDeliveriesLimit {
+		log = log[:webhookDeliveriesLimit]
+	}
+	r.deliveries[record.WebhookID] = log
+
+	return nil
+}
+
+// FindByWebhookID returns the most recent delivery attempts for a webhook, newest first
+func (r *MemoryWebhookDeliveryRepository) FindByWebhookID(ctx context.Context, webhookID primitive.ObjectID) ([]WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]WebhookDelivery{}, r.deliveries[webhookID]...), nil
+}
+
+// DeleteCreatedBefore deletes the attempts made at or before cutoff
+func (r *MemoryWebhookDeliveryRepository) DeleteCreatedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for id, log := range r.deliveries {
+		kept := slices.DeleteFunc(log, func(delivery WebhookDelivery) bool {
+			return !delivery.CreatedAt.After(cutoff)
+		})
+		deleted += int64(len(log) - len(kept))
+		if len(kept) == 0 {
+			delete(r.deliveries, id)
+		} else {
+			r.deliveries[id] = kept
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,91 @@
+package admin
+
+import (
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/listing"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// auditListOptions are the page sizes, orders and fields of GET /admin/audit
+// Records are ordered by ID, which follows creation time, newest first by default; the cursor carries the ID.
+var auditListOptions = listing.Options{
+	DefaultLimit:  100,
+	MaxLimit:      1000,
+	Sorts:         []string{"-createdAt", "createdAt"},
+	Fields:        listing.JSONFields(models.AdminAuditResponse{}),
+	ValidPosition: primitive.IsValidObjectID,
+}
+
+// ListAudit handles listing the recorded admin actions
+//
+//	@Summary		List admin actions
+//	@Description	Returns the actions taken through the admin routes, newest first, a page at a time: who took each one, its parameters, status and correlation ID. Reads (GET) are not recorded. Pass nextCursor back as cursor, with the same sort, to read older actions; it is absent on the last page. sort=createdAt lists oldest first, and fields cuts each action down to the named fields.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			actor	query		string												false	"Only actions by this actor (admin-token or client:{id})"
+//	@Param			action	query		string												false	"Only this action (e.g. admin.seed)"
+//	@Param			limit	query		int													false	"Page size (1-1000, default 100)"
+//	@Param			cursor	query		string												false	"nextCursor of the previous page"
+//	@Param			sort	query		string												false	"-createdAt (default) or createdAt for oldest first"
+//	@Param			fields	query		string												false	"Comma-separated record fields to return, e.g. action,status"
+//	@Success		200		{object}	httputil.APIResponse{data=models.AdminAuditPage}	"Page of admin actions"
+//	@Failure		400		{object}	httputil.APIResponse								"Invalid limit, cursor, sort or fields"
+//	@Failure		401		{object}	httputil.APIResponse								"Unauthorized"
+//	@Failure		403		{object}	httputil.APIResponse								"Bearer token without the admin scope"
+//	@Failure		500		{object}	httputil.APIResponse								"Internal server error"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/audit [get]
+func (h *Handler) ListAudit(w http.ResponseWriter, r *http.Request) {
+	span := trace.SpanFromContext(r.Context())
+
+	query, err := listing.Parse(r.URL.Query(), auditListOptions)
+	if err != nil {
+		span.SetStatus(codes.Error, "Invalid list query")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", err.Error()),
+		)
+		httputil.WriteAPIError(w, r, constants.ErrInvalidAdminAuditQuery)
+		return
+	}
+
+	records, err := h.auditRepo.List(r.Context(), models.AdminAuditFilter{
+		Actor:  r.URL.Query().Get("actor"),
+		Action: r.URL.Query().Get("action"),
+		Page:   query.Fetch(),
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to list admin actions")
+		span.SetAttributes(
+			attribute.String("error.type", "repository"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToListAdminActions)
+		return
+	}
+
+	records, next := listing.Trim(records, query, func(a models.AdminAuditRecord) string { return a.ID.Hex() })
+	page := models.AdminAuditPage{Records: []models.AdminAuditResponse{}, NextCursor: next}
+	for i := range records {
+		page.Records = append(page.Records, records[i].ToResponse())
+	}
+
+	projected, err := listing.Project(page, query.Fields)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to project admin actions")
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToListAdminActions)
+		return
+	}
+	httputil.WriteAPISuccess(w, r, constants.SuccessAdminAuditListed, projected)
+}
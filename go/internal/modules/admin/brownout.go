@@ -0,0 +1,175 @@
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/chaos"
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/validation"
+)
+
+// BrownoutRequest represents the request body for starting a brownout
+type BrownoutRequest struct {
+	ErrorRate   float64 `json:"errorRate" validate:"required_without=LatencyRate,gte=0,lte=1" example:"0.3"`
+	LatencyRate float64 `json:"latencyRate" validate:"gte=0,lte=1" example:"0.5"`
+	LatencyMs   int     `json:"latencyMs,omitempty" validate:"required_with=LatencyRate,omitempty,min=1,max=60000" example:"1500"`
+	RampUp      string  `json:"rampUp,omitempty" example:"1m"`   // Go duration; empty starts at the peak
+	Hold        string  `json:"hold,omitempty" example:"5m"`     // Go duration; empty holds the peak until DELETE /admin/brownout
+	RampDown    string  `json:"rampDown,omitempty" example:"1m"` // Go duration; empty ends the brownout right after the hold
+}
+
+// BrownoutResponse represents a brownout and how far along it is
+type BrownoutResponse struct {
+	ErrorRate   float64     `json:"errorRate" example:"0.3"`
+	LatencyRate float64     `json:"latencyRate" example:"0.5"`
+	LatencyMs   int         `json:"latencyMs" example:"1500"`
+	RampUp      string      `json:"rampUp" example:"1m0s"`
+	Hold        string      `json:"hold" example:"5m0s"`
+	RampDown    string      `json:"rampDown" example:"1m0s"`
+	StartedAt   time.Time   `json:"startedAt"`
+	Phase       chaos.Phase `json:"phase" example:"RAMPING_UP"`
+	Intensity   float64     `json:"intensity" example:"0.5"` // share of the peak rates applied now, from 0 to 1
+}
+
+// brownoutResponse describes b at the simulated time
+func (h *Handler) brownoutResponse(b chaos.Brownout) BrownoutResponse {
+	phase, intensity := b.At(h.clock.Now())
+	return BrownoutResponse{
+		ErrorRate:   b.ErrorRate,
+		LatencyRate: b.LatencyRate,
+		LatencyMs:   int(b.Latency.Milliseconds()),
+		RampUp:      b.RampUp.String(),
+		Hold:        b.Hold.String(),
+		RampDown:    b.RampDown.String(),
+		StartedAt:   b.StartedAt,
+		Phase:       phase,
+		Intensity:   intensity,
+	}
+}
+
+// parseRamp reads one of a brownout's durations, where empty means none
+func parseRamp(s string) (time.Duration, bool) {
+	if s == "" {
+		return 0, true
+	}
+	d, err := time.ParseDuration(s)
+	return d, err == nil && d >= 0
+}
+
+// GetBrownout handles reading the brownout
+//
+//	@Summary		Get the brownout
+//	@Description	Returns the brownout last started, its phase and its intensity on the simulated clock. An ended brownout is still returned, in the ENDED phase.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	httputil.APIResponse{data=BrownoutResponse}	"Brownout"
+//	@Failure		401	{object}	httputil.APIResponse						"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse						"Bearer token without the admin scope"
+//	@Failure		404	{object}	httputil.APIResponse						"No brownout started"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/brownout [get]
+func (h *Handler) GetBrownout(w http.ResponseWriter, r *http.Request) {
+	b, ok := h.faults.Brownout()
+	if !ok {
+		httputil.WriteAPIError(w, r, constants.ErrBrownoutNotFound)
+		return
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessBrownoutFound, h.brownoutResponse(b))
+}
+
+// StartBrownout handles starting a progressive degradation of the DICT routes
+//
+//	@Summary		Start a brownout
+//	@Description	Degrades the DICT routes progressively, to exercise client circuit breakers and adaptive retries: the share of requests answered 503 (errorRate) and delayed by latencyMs (latencyRate) grows linearly from none to these peaks over rampUp, stays there for hold, then shrinks back to none over rampDown. The ramp follows the simulated clock, so POST /admin/time/advance moves it along. Starting a brownout replaces the one in progress; fault rules keep applying alongside.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		BrownoutRequest								true	"Peak rates and ramp"
+//	@Success		200		{object}	httputil.APIResponse{data=BrownoutResponse}	"Brownout started"
+//	@Failure		400		{object}	httputil.APIResponse						"Invalid rates or durations"
+//	@Failure		401		{object}	httputil.APIResponse						"Unauthorized"
+//	@Failure		403		{object}	httputil.APIResponse						"Bearer token without the admin scope"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/brownout [put]
+func (h *Handler) StartBrownout(w http.ResponseWriter, r *http.Request) {
+	span := trace.SpanFromContext(r.Context())
+
+	var req BrownoutRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		span.SetStatus(codes.Error, "JSON decode failed")
+		span.SetAttributes(
+			attribute.String("error.type", "json_decode"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
+		return
+	}
+
+	if err := validation.Validate(&req); err != nil {
+		span.SetStatus(codes.Error, "Validation failed")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, constants.ErrInvalidRequestBody)
+		return
+	}
+
+	rampUp, okUp := parseRamp(req.RampUp)
+	hold, okHold := parseRamp(req.Hold)
+	rampDown, okDown := parseRamp(req.RampDown)
+	if !okUp || !okHold || !okDown {
+		span.SetStatus(codes.Error, "Invalid duration")
+		span.SetAttributes(attribute.String("error.type", "validation"))
+		httputil.WriteAPIError(w, r, constants.ErrInvalidBrownout)
+		return
+	}
+
+	b := h.faults.StartBrownout(chaos.Brownout{
+		ErrorRate:   req.ErrorRate,
+		LatencyRate: req.LatencyRate,
+		Latency:     time.Duration(req.LatencyMs) * time.Millisecond,
+		RampUp:      rampUp,
+		Hold:        hold,
+		RampDown:    rampDown,
+	})
+	span.SetAttributes(
+		attribute.Float64("brownout.error_rate", b.ErrorRate),
+		attribute.Float64("brownout.latency_rate", b.LatencyRate),
+	)
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessBrownoutStarted, h.brownoutResponse(b))
+}
+
+// StopBrownout handles ending the brownout
+//
+//	@Summary		Stop the brownout
+//	@Description	Ends the brownout at once, without ramping down
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	httputil.APIResponse	"Brownout stopped"
+//	@Failure		401	{object}	httputil.APIResponse	"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse	"Bearer token without the admin scope"
+//	@Failure		404	{object}	httputil.APIResponse	"No brownout started"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/brownout [delete]
+func (h *Handler) StopBrownout(w http.ResponseWriter, r *http.Request) {
+	if !h.faults.StopBrownout() {
+		httputil.WriteAPIError(w, r, constants.ErrBrownoutNotFound)
+		return
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessBrownoutStopped, nil)
+}
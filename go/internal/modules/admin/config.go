@@ -0,0 +1,84 @@
+package admin
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/chaos"
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/hotreload"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/middleware"
+)
+
+// ConfigReloader re-reads the configuration and applies its hot-reloadable settings
+type ConfigReloader interface {
+	Reload(trigger string) (middleware.Settings, error)
+}
+
+// ConfigResponse represents the hot-reloadable settings in effect
+type ConfigResponse struct {
+	RateLimitEnabled bool          `json:"rateLimitEnabled" example:"true"`
+	LatencyRoutes    []string      `json:"latencyRoutes" example:"GET /entries/{key}"` // routes with a latency profile ("*" covers the rest)
+	FaultRules       []chaos.Fault `json:"faultRules"`                                 // from FAULT_RULES; rules added through /admin/faults are listed there
+}
+
+// ReloadConfig handles re-reading the configuration without a restart
+//
+//	@Summary		Reload the configuration
+//	@Description	Re-reads the environment and CONFIG_FILE and applies rate limiting, latency profiles and FAULT_RULES without a restart, as SIGHUP does. Other settings need a restart. An invalid configuration is rejected as a whole.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	httputil.APIResponse{data=ConfigResponse}	"Configuration reloaded"
+//	@Failure		401	{object}	httputil.APIResponse						"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse						"Bearer token without the admin scope"
+//	@Failure		422	{object}	httputil.APIResponse						"Invalid configuration; nothing was applied"
+//	@Failure		501	{object}	httputil.APIResponse						"No configuration to reload (embedded simulator)"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/config/reload [post]
+func (h *Handler) ReloadConfig(w http.ResponseWriter, r *http.Request) {
+	span := trace.SpanFromContext(r.Context())
+
+	if h.reloader == nil {
+		httputil.WriteAPIError(w, r, constants.ErrConfigReloadUnavailable)
+		return
+	}
+
+	settings, err := h.reloader.Reload(hotreload.TriggerAdmin)
+	if err != nil {
+		span.SetStatus(codes.Error, "Invalid configuration")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+
+		apiErr := constants.ErrConfigReloadFailed
+		apiErr.Message += ": " + strings.ReplaceAll(err.Error(), "\n", "; ")
+		httputil.WriteAPIError(w, r, apiErr)
+		return
+	}
+
+	routes := make([]string, 0, len(settings.LatencyProfiles))
+	for route := range settings.LatencyProfiles {
+		routes = append(routes, route)
+	}
+	slices.Sort(routes)
+
+	faultRules := settings.FaultRules
+	if faultRules == nil {
+		faultRules = []chaos.Fault{}
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessConfigReloaded, ConfigResponse{
+		RateLimitEnabled: settings.RateLimitEnabled,
+		LatencyRoutes:    routes,
+		FaultRules:       faultRules,
+	})
+}
@@ -0,0 +1,128 @@
+package admin
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/events"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/validation"
+)
+
+// ErasedEntry identifies an entry an erasure deleted
+type ErasedEntry struct {
+	Key         string         `json:"key" example:"52998224725"`
+	KeyType     models.KeyType `json:"keyType" example:"CPF"`
+	Participant string         `json:"participant" example:"12345678"`
+}
+
+// ErasureResponse represents what an erasure removed
+type ErasureResponse struct {
+	TaxIdNumber               string        `json:"taxIdNumber" example:"52998224725"`
+	Entries                   []ErasedEntry `json:"entries"`
+	FraudMarkersDeleted       int64         `json:"fraudMarkersDeleted" example:"1"`
+	OwnershipPeriodsDeleted   int64         `json:"ownershipPeriodsDeleted" example:"2"`   // periods of the key ownership history
+	IdempotencyRecordsDeleted int64         `json:"idempotencyRecordsDeleted" example:"2"` // cached responses naming the tax ID
+	KeyCreationCountsDeleted  int           `json:"keyCreationCountsDeleted" example:"1"`  // days with keys counted against the daily limit
+}
+
+// Erase handles removing everything the simulator keeps about a person or company, as a data
+// subject's erasure request under the LGPD would
+//
+//	@Summary		Erase a tax ID's data
+//	@Description	Deletes the entries owned by the CPF or CNPJ, the fraud markers left on its deleted entries, the periods in which it held keys in their ownership history, the cached idempotent responses that name it and its key creation counts, and reports what was removed. Entries are deleted one by one through the usual delete path and reported with an ENTRY_DELETED event each, whatever the EVENT_SOURCE. The audit record of the erasure masks the tax ID. The simulator has no claims, so there are none to erase.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			taxId	path		string										true	"CPF (11 digits) or CNPJ (14 digits)"
+//	@Success		200		{object}	httputil.APIResponse{data=ErasureResponse}	"Subject erased"
+//	@Failure		400		{object}	httputil.APIResponse						"taxId is not a valid CPF or CNPJ"
+//	@Failure		401		{object}	httputil.APIResponse						"Unauthorized"
+//	@Failure		403		{object}	httputil.APIResponse						"Bearer token without the admin scope"
+//	@Failure		500		{object}	httputil.APIResponse						"Internal server error"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/erasure/{taxId} [post]
+func (h *Handler) Erase(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+
+	// The tax ID is personal data, so it is never set on the span
+	taxID := r.PathValue("taxId")
+	if !validation.IsValidCPF(taxID) && !validation.IsValidCNPJ(taxID) {
+		span.SetStatus(codes.Error, "Validation failed")
+		span.SetAttributes(attribute.String("error.type", "validation"))
+		httputil.WriteAPIError(w, r, constants.ErrInvalidErasureTaxID)
+		return
+	}
+
+	// Owners encrypted at rest are found by the blind index of their tax ID
+	owned, err := h.entryRepo.FindByTaxID(ctx, taxID)
+	if err != nil {
+		h.erasureFailed(w, r, span, "entries", err)
+		return
+	}
+
+	// A failed step can simply be retried: every step deletes whatever is left
+	resp := ErasureResponse{TaxIdNumber: taxID, Entries: []ErasedEntry{}}
+	for _, entry := range owned {
+		deleted, err := h.entryRepo.DeleteByKeyAndParticipant(ctx, entry.Key, entry.Account.Participant)
+		if err != nil {
+			h.erasureFailed(w, r, span, "entries", err)
+			return
+		}
+		// Deleted or moved to another participant since the lookup
+		if deleted == nil {
+			continue
+		}
+		// With EVENT_SOURCE=outbox or changestream the deletion itself is published, and this is discarded
+		h.publisher.Publish(ctx, events.New(events.EntryDeleted, deleted.Account.Participant, deleted.DeletedResponse(), h.clock.Now()))
+		resp.Entries = append(resp.Entries, ErasedEntry{
+			Key:         deleted.Key,
+			KeyType:     deleted.KeyType,
+			Participant: deleted.Account.Participant,
+		})
+	}
+	if resp.FraudMarkersDeleted, err = h.fraudMarkerRepo.DeleteByTaxID(ctx, taxID); err != nil {
+		h.erasureFailed(w, r, span, "fraud_markers", err)
+		return
+	}
+	if resp.OwnershipPeriodsDeleted, err = h.ownershipRepo.DeleteByTaxID(ctx, taxID); err != nil {
+		h.erasureFailed(w, r, span, "key_ownership", err)
+		return
+	}
+	if resp.IdempotencyRecordsDeleted, err = h.idempotencyRepo.DeleteMentioning(ctx, taxID); err != nil {
+		h.erasureFailed(w, r, span, "idempotency", err)
+		return
+	}
+	if resp.KeyCreationCountsDeleted, err = h.creations.Forget(ctx, taxID); err != nil {
+		h.erasureFailed(w, r, span, "key_creations", err)
+		return
+	}
+
+	span.SetAttributes(
+		attribute.Int("erasure.entries_deleted", len(resp.Entries)),
+		attribute.Int64("erasure.fraud_markers_deleted", resp.FraudMarkersDeleted),
+		attribute.Int64("erasure.ownership_periods_deleted", resp.OwnershipPeriodsDeleted),
+		attribute.Int64("erasure.idempotency_records_deleted", resp.IdempotencyRecordsDeleted),
+		attribute.Int("erasure.key_creation_counts_deleted", resp.KeyCreationCountsDeleted),
+	)
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessSubjectErased, resp)
+}
+
+// erasureFailed records which step of an erasure failed and answers 500
+func (h *Handler) erasureFailed(w http.ResponseWriter, r *http.Request, span trace.Span, step string, err error) {
+	span.SetStatus(codes.Error, "Failed to erase subject")
+	span.SetAttributes(
+		attribute.String("error.type", "repository"),
+		attribute.String("error.message", err.Error()),
+		attribute.String("erasure.step", step),
+	)
+	span.RecordError(err)
+	httputil.WriteError(w, r, err, constants.ErrFailedToEraseSubject)
+}
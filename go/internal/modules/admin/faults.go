@@ -0,0 +1,125 @@
+package admin
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/chaos"
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/validation"
+)
+
+// DeleteFaultResponse represents the response for removing a fault rule
+type DeleteFaultResponse struct {
+	ID string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
+
+// ListFaults handles listing the active fault rules
+//
+//	@Summary		List fault injection rules
+//	@Description	Returns the fault rules currently applied to DICT routes
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	httputil.APIResponse{data=[]chaos.Fault}	"Active fault rules"
+//	@Failure		401	{object}	httputil.APIResponse						"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse						"Bearer token without the admin scope"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/faults [get]
+func (h *Handler) ListFaults(w http.ResponseWriter, r *http.Request) {
+	httputil.WriteAPISuccess(w, r, constants.SuccessFaultsFound, h.faults.List())
+}
+
+// CreateFault handles registering a new fault rule
+//
+//	@Summary		Create a fault injection rule
+//	@Description	Injects latency, 5xx errors or connection resets on matching routes and keys. Rules match when both route and keySuffix match (empty fields match everything) and then fire with the given probability (default 1).
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		chaos.Fault								true	"Fault rule (id and createdAt are ignored)"
+//	@Success		201		{object}	httputil.APIResponse{data=chaos.Fault}	"Fault rule created"
+//	@Failure		400		{object}	httputil.APIResponse					"Invalid request body"
+//	@Failure		401		{object}	httputil.APIResponse					"Unauthorized"
+//	@Failure		403		{object}	httputil.APIResponse					"Bearer token without the admin scope"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/faults [post]
+func (h *Handler) CreateFault(w http.ResponseWriter, r *http.Request) {
+	span := trace.SpanFromContext(r.Context())
+
+	var req chaos.Fault
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		span.SetStatus(codes.Error, "JSON decode failed")
+		span.SetAttributes(
+			attribute.String("error.type", "json_decode"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
+		return
+	}
+
+	// Validate request using validator library
+	if err := validation.Validate(&req); err != nil {
+		span.SetStatus(codes.Error, "Validation failed")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, constants.ErrInvalidRequestBody)
+		return
+	}
+
+	fault := h.faults.Add(req)
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessFaultCreated, fault)
+}
+
+// DeleteFault handles removing a single fault rule
+//
+//	@Summary		Delete a fault injection rule
+//	@Description	Removes a fault rule by ID
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		string										true	"Fault rule ID"
+//	@Success		200	{object}	httputil.APIResponse{data=DeleteFaultResponse}	"Fault rule deleted"
+//	@Failure		401	{object}	httputil.APIResponse							"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse							"Bearer token without the admin scope"
+//	@Failure		404	{object}	httputil.APIResponse							"Fault rule not found"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/faults/{id} [delete]
+func (h *Handler) DeleteFault(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if !h.faults.Remove(id) {
+		httputil.WriteAPIError(w, r, constants.ErrFaultNotFound)
+		return
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessFaultDeleted, DeleteFaultResponse{ID: id})
+}
+
+// ClearFaults handles removing all fault rules
+//
+//	@Summary		Clear all fault injection rules
+//	@Description	Removes every active fault rule, restoring normal behaviour
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	httputil.APIResponse	"Fault rules cleared"
+//	@Failure		401	{object}	httputil.APIResponse	"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse	"Bearer token without the admin scope"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/faults [delete]
+func (h *Handler) ClearFaults(w http.ResponseWriter, r *http.Request) {
+	h.faults.Clear()
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessFaultsCleared, nil)
+}
@@ -0,0 +1,183 @@
+package admin
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/chaos"
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/events"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/keylimit"
+	"github.com/dict-simulator/go/internal/keypolicy"
+	"github.com/dict-simulator/go/internal/lockout"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/passwordreset"
+	"github.com/dict-simulator/go/internal/quota"
+	"github.com/dict-simulator/go/internal/ratelimit"
+	"github.com/dict-simulator/go/internal/seed"
+	"github.com/dict-simulator/go/internal/stubs"
+	"github.com/dict-simulator/go/internal/usage"
+	"github.com/dict-simulator/go/internal/validation"
+)
+
+// seedBatchSize caps how many entries are sent to MongoDB in a single insert
+const seedBatchSize = 1000
+
+// SeedRequest represents the request body for seeding entries
+type SeedRequest struct {
+	Count        int                     `json:"count" validate:"required,min=1,max=100000" example:"1000"`
+	KeyTypes     map[models.KeyType]int  `json:"keyTypes,omitempty" validate:"omitempty,dive,keys,oneof=CPF CNPJ EMAIL PHONE EVP,endkeys,min=0"`
+	Participants []seed.ParticipantShare `json:"participants,omitempty" validate:"omitempty,dive"`
+	Seed         uint64                  `json:"seed,omitempty" example:"42"`
+}
+
+// SeedResponse represents the result of a seeding run
+type SeedResponse struct {
+	Requested int    `json:"requested" example:"1000"`
+	Created   int    `json:"created" example:"998"`
+	Skipped   int    `json:"skipped" example:"2"` // keys that already existed in the directory
+	Seed      uint64 `json:"seed" example:"42"`   // pass back on the same (simulated) day to reproduce the same dataset
+}
+
+// Handler handles admin-only HTTP requests used by test and demo environments
+type Handler struct {
+	entryRepo       models.EntryRepository
+	fraudMarkerRepo models.FraudMarkerRepository
+	ownershipRepo   models.KeyOwnershipRepository
+	requestIDRepo   models.RequestIDRepository
+	userRepo        models.UserRepository
+	clientRepo      models.OAuthClientRepository
+	auditRepo       models.AdminAuditRepository
+	usage           *usage.Recorder
+	idempotencyRepo models.IdempotencyRepository
+	rateLimiter     ratelimit.Limiter
+	creations       keylimit.Store
+	logins          lockout.Store
+	resets          passwordreset.Store
+	faults          *chaos.Injector
+	stubs           *stubs.Registry
+	keyPolicies     *keypolicy.Store
+	quotas          *quota.Store
+	publisher       events.Publisher
+	clock           *clock.Simulated
+	reloader        ConfigReloader
+}
+
+// NewHandler creates a new admin handler
+// idempotencyRepo and rateLimiter must be the ones the middlewares use, so resets reach their data,
+// creations the one the entries handler counts key creations in, logins and resets the ones the
+// auth handler locks accounts and keeps reset tokens in, stubRegistry the one the router serves
+// stubs from, keyPolicies the one the entries handler checks new keys against, and quotas the one
+// the entries handler and the RequestQuota middleware hold participants to, and publisher the one the
+// entries handler publishes to, so erased entries are reported like deleted ones.
+// reloader may be nil, in which case POST /admin/config/reload answers 501.
+func NewHandler(entryRepo models.EntryRepository, fraudMarkerRepo models.FraudMarkerRepository, ownershipRepo models.KeyOwnershipRepository, requestIDRepo models.RequestIDRepository, userRepo models.UserRepository, clientRepo models.OAuthClientRepository, auditRepo models.AdminAuditRepository, usage *usage.Recorder, idempotencyRepo models.IdempotencyRepository, rateLimiter ratelimit.Limiter, creations keylimit.Store, logins lockout.Store, resets passwordreset.Store, faults *chaos.Injector, stubRegistry *stubs.Registry, keyPolicies *keypolicy.Store, quotas *quota.Store, publisher events.Publisher, clk *clock.Simulated, reloader ConfigReloader) *Handler {
+	return &Handler{
+		entryRepo:       entryRepo,
+		fraudMarkerRepo: fraudMarkerRepo,
+		ownershipRepo:   ownershipRepo,
+		requestIDRepo:   requestIDRepo,
+		userRepo:        userRepo,
+		clientRepo:      clientRepo,
+		auditRepo:       auditRepo,
+		usage:           usage,
+		idempotencyRepo: idempotencyRepo,
+		rateLimiter:     rateLimiter,
+		creations:       creations,
+		logins:          logins,
+		resets:          resets,
+		faults:          faults,
+		stubs:           stubRegistry,
+		keyPolicies:     keyPolicies,
+		quotas:          quotas,
+		publisher:       publisher,
+		clock:           clk,
+		reloader:        reloader,
+	}
+}
+
+// Seed handles generating and inserting realistic entries
+//
+//	@Summary		Seed the directory with generated entries
+//	@Description	Generates N entries with valid CPFs/CNPJs, random key types and a configurable participant distribution. Keys that already exist are skipped.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		SeedRequest									true	"Seeding options"
+//	@Success		201		{object}	httputil.APIResponse{data=SeedResponse}	"Entries seeded"
+//	@Failure		400		{object}	httputil.APIResponse						"Invalid request body"
+//	@Failure		401		{object}	httputil.APIResponse						"Unauthorized"
+//	@Failure		403		{object}	httputil.APIResponse						"Bearer token without the admin scope"
+//	@Failure		500		{object}	httputil.APIResponse						"Internal server error"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/seed [post]
+func (h *Handler) Seed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+
+	var req SeedRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		span.SetStatus(codes.Error, "JSON decode failed")
+		span.SetAttributes(
+			attribute.String("error.type", "json_decode"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
+		return
+	}
+
+	// Validate request using validator library
+	if err := validation.Validate(&req); err != nil {
+		span.SetStatus(codes.Error, "Validation failed")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, constants.ErrInvalidRequestBody)
+		return
+	}
+
+	generator := seed.NewGenerator(seed.Options{
+		KeyTypes:     req.KeyTypes,
+		Participants: req.Participants,
+		Seed:         req.Seed,
+		Now:          h.clock.Now(),
+	})
+	span.SetAttributes(
+		attribute.Int("seed.count", req.Count),
+		attribute.Int64("seed.value", int64(generator.Seed())),
+	)
+
+	created := 0
+	for remaining := req.Count; remaining > 0; remaining -= seedBatchSize {
+		batch := generator.Generate(min(remaining, seedBatchSize))
+
+		inserted, err := h.entryRepo.CreateMany(ctx, batch)
+		if err != nil {
+			span.SetStatus(codes.Error, "Failed to seed entries")
+			span.SetAttributes(
+				attribute.String("error.type", "repository"),
+				attribute.String("error.message", err.Error()),
+			)
+			span.RecordError(err)
+			httputil.WriteError(w, r, err, constants.ErrFailedToSeedEntries)
+			return
+		}
+		created += inserted
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessEntriesSeeded, SeedResponse{
+		Requested: req.Count,
+		Created:   created,
+		Skipped:   req.Count - created,
+		Seed:      generator.Seed(),
+	})
+}
@@ -0,0 +1,113 @@
+package admin
+
+import (
+	"net/http"
+	"slices"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/keypolicy"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/validation"
+)
+
+// KeyPoliciesRequest represents the request body for replacing the key policies
+type KeyPoliciesRequest struct {
+	DisabledKeyTypes    []models.KeyType `json:"disabledKeyTypes" validate:"dive,oneof=CPF CNPJ EMAIL PHONE EVP" example:"EVP"`
+	BrazilianPhonesOnly bool             `json:"brazilianPhonesOnly" example:"true"`
+}
+
+// KeyPoliciesResponse represents the key policies in effect
+type KeyPoliciesResponse struct {
+	DisabledKeyTypes    []models.KeyType `json:"disabledKeyTypes" example:"EVP"`      // key types that can't be created
+	BrazilianPhonesOnly bool             `json:"brazilianPhonesOnly" example:"false"` // PHONE keys must be +55 numbers instead of any E.164 one
+}
+
+func newKeyPoliciesResponse(policies keypolicy.Policies) KeyPoliciesResponse {
+	disabled := policies.DisabledKeyTypes
+	if disabled == nil {
+		disabled = []models.KeyType{}
+	}
+	return KeyPoliciesResponse{DisabledKeyTypes: disabled, BrazilianPhonesOnly: policies.BrazilianPhonesOnly}
+}
+
+// GetKeyPolicies handles reading the key policies
+//
+//	@Summary		Get the key policies
+//	@Description	Returns the key types that can't be created and whether PHONE keys must be Brazilian numbers: KEY_TYPES_DISABLED and PHONE_KEYS_BRAZIL_ONLY unless changed through PUT /admin/key-policies
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	httputil.APIResponse{data=KeyPoliciesResponse}	"Key policies"
+//	@Failure		401	{object}	httputil.APIResponse							"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse							"Bearer token without the admin scope"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/key-policies [get]
+func (h *Handler) GetKeyPolicies(w http.ResponseWriter, r *http.Request) {
+	httputil.WriteAPISuccess(w, r, constants.SuccessKeyPoliciesFound, newKeyPoliciesResponse(h.keyPolicies.Get()))
+}
+
+// SetKeyPolicies handles replacing the key policies while the server runs
+//
+//	@Summary		Change the key policies
+//	@Description	Replaces the key policies at once, so a test environment can mimic another production policy: creations of a disabled key type are refused with KEY_TYPE_DISABLED, and with brazilianPhonesOnly PHONE keys that aren't +55 mobile or landline numbers with INVALID_PHONE. POST /keys/validate reports the same. Registered keys are not affected. The change lasts until the next change or restart, in this instance only; a config reload leaves it alone.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		KeyPoliciesRequest								true	"New key policies"
+//	@Success		200		{object}	httputil.APIResponse{data=KeyPoliciesResponse}	"Key policies changed"
+//	@Failure		400		{object}	httputil.APIResponse							"Unknown key type"
+//	@Failure		401		{object}	httputil.APIResponse							"Unauthorized"
+//	@Failure		403		{object}	httputil.APIResponse							"Bearer token without the admin scope"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/key-policies [put]
+func (h *Handler) SetKeyPolicies(w http.ResponseWriter, r *http.Request) {
+	span := trace.SpanFromContext(r.Context())
+
+	var req KeyPoliciesRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		span.SetStatus(codes.Error, "JSON decode failed")
+		span.SetAttributes(
+			attribute.String("error.type", "json_decode"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
+		return
+	}
+
+	if err := validation.Validate(&req); err != nil {
+		span.SetStatus(codes.Error, "Validation failed")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, constants.ErrInvalidKeyPolicies)
+		return
+	}
+
+	policies := keypolicy.Policies{BrazilianPhonesOnly: req.BrazilianPhonesOnly}
+	for _, keyType := range req.DisabledKeyTypes {
+		if !slices.Contains(policies.DisabledKeyTypes, keyType) {
+			policies.DisabledKeyTypes = append(policies.DisabledKeyTypes, keyType)
+		}
+	}
+	h.keyPolicies.Set(policies)
+
+	disabled := make([]string, len(policies.DisabledKeyTypes))
+	for i, keyType := range policies.DisabledKeyTypes {
+		disabled[i] = string(keyType)
+	}
+	span.SetAttributes(
+		attribute.StringSlice("key_policies.disabled_key_types", disabled),
+		attribute.Bool("key_policies.brazilian_phones_only", policies.BrazilianPhonesOnly),
+	)
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessKeyPoliciesChanged, newKeyPoliciesResponse(policies))
+}
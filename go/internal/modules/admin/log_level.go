@@ -0,0 +1,92 @@
+package admin
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/logger"
+	"github.com/dict-simulator/go/internal/validation"
+)
+
+// LogLevelRequest represents the request body for changing the log level
+type LogLevelRequest struct {
+	Level string `json:"level" validate:"required,oneof=debug info warn error" example:"debug"`
+}
+
+// LogLevelResponse represents the log level in effect
+type LogLevelResponse struct {
+	Level string `json:"level" example:"info"`
+}
+
+// GetLogLevel handles reading the log level
+//
+//	@Summary		Get the log level
+//	@Description	Returns the minimum level the server logs, LOG_LEVEL unless changed through PUT /admin/log-level
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	httputil.APIResponse{data=LogLevelResponse}	"Log level"
+//	@Failure		401	{object}	httputil.APIResponse						"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse						"Bearer token without the admin scope"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/log-level [get]
+func (h *Handler) GetLogLevel(w http.ResponseWriter, r *http.Request) {
+	httputil.WriteAPISuccess(w, r, constants.SuccessLogLevelFound, LogLevelResponse{Level: logger.Level.String()})
+}
+
+// SetLogLevel handles changing the log level while the server runs
+//
+//	@Summary		Change the log level
+//	@Description	Changes the minimum level the server logs at once, e.g. to debug while chasing an issue. The change lasts until the next change or restart; a config reload leaves it alone.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		LogLevelRequest								true	"New level"
+//	@Success		200		{object}	httputil.APIResponse{data=LogLevelResponse}	"Log level changed"
+//	@Failure		400		{object}	httputil.APIResponse						"Unknown level"
+//	@Failure		401		{object}	httputil.APIResponse						"Unauthorized"
+//	@Failure		403		{object}	httputil.APIResponse						"Bearer token without the admin scope"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/log-level [put]
+func (h *Handler) SetLogLevel(w http.ResponseWriter, r *http.Request) {
+	span := trace.SpanFromContext(r.Context())
+
+	var req LogLevelRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		span.SetStatus(codes.Error, "JSON decode failed")
+		span.SetAttributes(
+			attribute.String("error.type", "json_decode"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
+		return
+	}
+
+	if err := validation.Validate(&req); err != nil {
+		span.SetStatus(codes.Error, "Validation failed")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, constants.ErrInvalidLogLevel)
+		return
+	}
+
+	previous := logger.Level.String()
+	// Validated above, so the level always parses
+	_ = logger.Level.UnmarshalText([]byte(req.Level))
+	span.SetAttributes(
+		attribute.String("log.level.previous", previous),
+		attribute.String("log.level", req.Level),
+	)
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessLogLevelChanged, LogLevelResponse{Level: logger.Level.String()})
+}
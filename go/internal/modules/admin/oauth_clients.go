@@ -0,0 +1,170 @@
+package admin
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/validation"
+)
+
+// DeleteOAuthClientResponse represents the result of deleting an OAuth client
+type DeleteOAuthClientResponse struct {
+	ClientID string `json:"clientId" example:"507f1f77bcf86cd799439011"`
+}
+
+// CreateOAuthClient handles registering a machine client for a participant
+//
+//	@Summary		Register an OAuth client
+//	@Description	Registers a client that gets access tokens from POST /oauth/token with the client credentials grant. Its tokens act for the participant and only reach the routes of the granted scopes; the admin scope reaches the /admin routes in place of X-Admin-Token. The client secret is generated and returned only in this response.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.CreateOAuthClientRequest							true	"Participant, name and scopes"
+//	@Success		201		{object}	httputil.APIResponse{data=models.OAuthClientResponse}	"Client registered"
+//	@Failure		400		{object}	httputil.APIResponse									"Invalid request body"
+//	@Failure		401		{object}	httputil.APIResponse									"Unauthorized"
+//	@Failure		403		{object}	httputil.APIResponse									"Bearer token without the admin scope"
+//	@Failure		500		{object}	httputil.APIResponse									"Internal server error"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/oauth/clients [post]
+func (h *Handler) CreateOAuthClient(w http.ResponseWriter, r *http.Request) {
+	span := trace.SpanFromContext(r.Context())
+
+	var req models.CreateOAuthClientRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		span.SetStatus(codes.Error, "JSON decode failed")
+		span.SetAttributes(
+			attribute.String("error.type", "json_decode"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
+		return
+	}
+
+	if err := validation.Validate(&req); err != nil {
+		span.SetStatus(codes.Error, "Validation failed")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, constants.ErrInvalidRequestBody)
+		return
+	}
+
+	secret := generateClientSecret()
+	client, err := h.clientRepo.Create(r.Context(), &req, secret)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to create client")
+		span.SetAttributes(
+			attribute.String("error.type", "repository"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToCreateClient)
+		return
+	}
+
+	response := client.ToResponse()
+	response.ClientSecret = secret
+	httputil.WriteAPISuccess(w, r, constants.SuccessClientCreated, response)
+}
+
+// ListOAuthClients handles listing the registered OAuth clients
+//
+//	@Summary		List OAuth clients
+//	@Description	Lists the registered clients, oldest first, without their secrets.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			participant	query		string													false	"Only the clients of this participant ISPB"
+//	@Success		200			{object}	httputil.APIResponse{data=[]models.OAuthClientResponse}	"Clients found"
+//	@Failure		401			{object}	httputil.APIResponse									"Unauthorized"
+//	@Failure		403			{object}	httputil.APIResponse									"Bearer token without the admin scope"
+//	@Failure		500			{object}	httputil.APIResponse									"Internal server error"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/oauth/clients [get]
+func (h *Handler) ListOAuthClients(w http.ResponseWriter, r *http.Request) {
+	span := trace.SpanFromContext(r.Context())
+
+	clients, err := h.clientRepo.List(r.Context(), r.URL.Query().Get("participant"))
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to list clients")
+		span.SetAttributes(
+			attribute.String("error.type", "repository"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToListClients)
+		return
+	}
+
+	response := make([]models.OAuthClientResponse, len(clients))
+	for i := range clients {
+		response[i] = clients[i].ToResponse()
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessClientsFound, response)
+}
+
+// DeleteOAuthClient handles removing an OAuth client
+//
+//	@Summary		Delete an OAuth client
+//	@Description	Removes the client. Access tokens already issued to it are refused from then on.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		string												true	"Client ID"
+//	@Success		200	{object}	httputil.APIResponse{data=DeleteOAuthClientResponse}	"Client deleted"
+//	@Failure		401	{object}	httputil.APIResponse								"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse								"Bearer token without the admin scope"
+//	@Failure		404	{object}	httputil.APIResponse								"Client not found"
+//	@Failure		500	{object}	httputil.APIResponse								"Internal server error"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/oauth/clients/{id} [delete]
+func (h *Handler) DeleteOAuthClient(w http.ResponseWriter, r *http.Request) {
+	span := trace.SpanFromContext(r.Context())
+
+	id, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		httputil.WriteAPIError(w, r, constants.ErrClientNotFound)
+		return
+	}
+	span.SetAttributes(attribute.String("oauth.client_id", id.Hex()))
+
+	found, err := h.clientRepo.DeleteByID(r.Context(), id)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to delete client")
+		span.SetAttributes(
+			attribute.String("error.type", "repository"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToDeleteClient)
+		return
+	}
+	if !found {
+		httputil.WriteAPIError(w, r, constants.ErrClientNotFound)
+		return
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessClientDeleted, DeleteOAuthClientResponse{ClientID: id.Hex()})
+}
+
+// generateClientSecret creates a random client secret
+func generateClientSecret() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,225 @@
+package admin
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/quota"
+	"github.com/dict-simulator/go/internal/validation"
+)
+
+// QuotasResponse represents the default quota and the participants' own
+type QuotasResponse struct {
+	Defaults     quota.Quota            `json:"defaults"`
+	Participants map[string]quota.Quota `json:"participants"` // by ISPB
+}
+
+// ParticipantQuotaResponse represents the quota a participant is held to and what it uses of it
+type ParticipantQuotaResponse struct {
+	Participant string      `json:"participant" example:"12345678"`
+	Quota       quota.Quota `json:"quota"`
+	Own         bool        `json:"own" example:"true"`     // false when the participant is on the default quota
+	Entries     int64       `json:"entries" example:"9120"` // entries the participant holds
+}
+
+func (h *Handler) quotasResponse() QuotasResponse {
+	return QuotasResponse{Defaults: h.quotas.Defaults(), Participants: h.quotas.Overrides()}
+}
+
+// ListQuotas handles reading the quotas
+//
+//	@Summary		List the quotas
+//	@Description	Returns the default quota, QUOTA_MAX_ENTRIES and QUOTA_REQUESTS_PER_MINUTE unless changed through PUT /admin/quotas, and the participants given a quota of their own. Zero fields set no cap.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	httputil.APIResponse{data=QuotasResponse}	"Quotas"
+//	@Failure		401	{object}	httputil.APIResponse						"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse						"Bearer token without the admin scope"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/quotas [get]
+func (h *Handler) ListQuotas(w http.ResponseWriter, r *http.Request) {
+	httputil.WriteAPISuccess(w, r, constants.SuccessQuotasFound, h.quotasResponse())
+}
+
+// SetDefaultQuota handles replacing the default quota while the server runs
+//
+//	@Summary		Change the default quota
+//	@Description	Replaces the quota of every participant without one of its own. Creations by a participant already holding maxEntries entries are refused with ENTRY_QUOTA_EXCEEDED, and DICT requests beyond requestsPerMinute with REQUEST_QUOTA_EXCEEDED; zero sets no cap. Entries already stored are kept. The change lasts until the next change or restart, in this instance only; a config reload leaves it alone.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		quota.Quota									true	"New default quota"
+//	@Success		200		{object}	httputil.APIResponse{data=QuotasResponse}	"Default quota changed"
+//	@Failure		400		{object}	httputil.APIResponse						"Invalid request body"
+//	@Failure		401		{object}	httputil.APIResponse						"Unauthorized"
+//	@Failure		403		{object}	httputil.APIResponse						"Bearer token without the admin scope"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/quotas [put]
+func (h *Handler) SetDefaultQuota(w http.ResponseWriter, r *http.Request) {
+	q, ok := decodeQuota(w, r)
+	if !ok {
+		return
+	}
+
+	h.quotas.SetDefaults(q)
+	httputil.WriteAPISuccess(w, r, constants.SuccessQuotaChanged, h.quotasResponse())
+}
+
+// GetQuota handles reading a participant's quota
+//
+//	@Summary		Get a participant's quota
+//	@Description	Returns the quota the participant is held to, its own or the default, and how many entries it holds
+//	@Tags			admin
+//	@Produce		json
+//	@Param			participant	path		string											true	"Participant ISPB"
+//	@Success		200			{object}	httputil.APIResponse{data=ParticipantQuotaResponse}	"Participant's quota"
+//	@Failure		400			{object}	httputil.APIResponse								"Participant is not an 8-digit ISPB"
+//	@Failure		401			{object}	httputil.APIResponse								"Unauthorized"
+//	@Failure		403			{object}	httputil.APIResponse								"Bearer token without the admin scope"
+//	@Failure		500			{object}	httputil.APIResponse								"Internal server error"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/quotas/{participant} [get]
+func (h *Handler) GetQuota(w http.ResponseWriter, r *http.Request) {
+	participant, ok := quotaParticipant(w, r)
+	if !ok {
+		return
+	}
+
+	h.writeParticipantQuota(w, r, participant, constants.SuccessQuotaFound)
+}
+
+// SetQuota handles giving a participant a quota of its own
+//
+//	@Summary		Change a participant's quota
+//	@Description	Gives the participant a quota of its own in place of the default, e.g. to let one team's load test through or to stop its seeding job from filling the shared database. Zero fields set no cap. Entries already stored are kept, even beyond maxEntries. The change lasts until the next change or restart, in this instance only.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			participant	path		string											true	"Participant ISPB"
+//	@Param			request		body		quota.Quota										true	"Participant's quota"
+//	@Success		200			{object}	httputil.APIResponse{data=ParticipantQuotaResponse}	"Participant's quota changed"
+//	@Failure		400			{object}	httputil.APIResponse								"Invalid participant or request body"
+//	@Failure		401			{object}	httputil.APIResponse								"Unauthorized"
+//	@Failure		403			{object}	httputil.APIResponse								"Bearer token without the admin scope"
+//	@Failure		500			{object}	httputil.APIResponse								"Internal server error"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/quotas/{participant} [put]
+func (h *Handler) SetQuota(w http.ResponseWriter, r *http.Request) {
+	participant, ok := quotaParticipant(w, r)
+	if !ok {
+		return
+	}
+	q, ok := decodeQuota(w, r)
+	if !ok {
+		return
+	}
+
+	h.quotas.Set(participant, q)
+	h.writeParticipantQuota(w, r, participant, constants.SuccessQuotaChanged)
+}
+
+// DeleteQuota handles putting a participant back on the default quota
+//
+//	@Summary		Remove a participant's quota
+//	@Description	Removes the participant's own quota, so the default applies to it again
+//	@Tags			admin
+//	@Produce		json
+//	@Param			participant	path		string											true	"Participant ISPB"
+//	@Success		200			{object}	httputil.APIResponse{data=ParticipantQuotaResponse}	"Participant back on the default quota"
+//	@Failure		400			{object}	httputil.APIResponse								"Participant is not an 8-digit ISPB"
+//	@Failure		401			{object}	httputil.APIResponse								"Unauthorized"
+//	@Failure		403			{object}	httputil.APIResponse								"Bearer token without the admin scope"
+//	@Failure		404			{object}	httputil.APIResponse								"Participant has no quota of its own"
+//	@Failure		500			{object}	httputil.APIResponse								"Internal server error"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/quotas/{participant} [delete]
+func (h *Handler) DeleteQuota(w http.ResponseWriter, r *http.Request) {
+	participant, ok := quotaParticipant(w, r)
+	if !ok {
+		return
+	}
+
+	if !h.quotas.Remove(participant) {
+		httputil.WriteAPIError(w, r, constants.ErrQuotaNotFound)
+		return
+	}
+	h.writeParticipantQuota(w, r, participant, constants.SuccessQuotaRemoved)
+}
+
+// quotaParticipant returns the ISPB in the path, or answers 400 when it isn't one
+func quotaParticipant(w http.ResponseWriter, r *http.Request) (string, bool) {
+	participant := r.PathValue("participant")
+	if !validation.IsDigits(participant, 8) {
+		httputil.WriteAPIError(w, r, constants.ErrInvalidQuotaParticipant)
+		return "", false
+	}
+	trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("quota.participant", participant))
+	return participant, true
+}
+
+// decodeQuota reads a quota from the request body, or answers 400 when it is invalid
+func decodeQuota(w http.ResponseWriter, r *http.Request) (quota.Quota, bool) {
+	span := trace.SpanFromContext(r.Context())
+
+	var q quota.Quota
+	if err := httputil.DecodeJSON(r, &q); err != nil {
+		span.SetStatus(codes.Error, "JSON decode failed")
+		span.SetAttributes(
+			attribute.String("error.type", "json_decode"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
+		return q, false
+	}
+
+	if err := validation.Validate(&q); err != nil {
+		span.SetStatus(codes.Error, "Validation failed")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, constants.ErrInvalidRequestBody)
+		return q, false
+	}
+
+	span.SetAttributes(
+		attribute.Int("quota.max_entries", q.MaxEntries),
+		attribute.Int("quota.requests_per_minute", q.RequestsPerMinute),
+	)
+	return q, true
+}
+
+// writeParticipantQuota answers with the quota participant is held to and the entries it holds
+func (h *Handler) writeParticipantQuota(w http.ResponseWriter, r *http.Request, participant string, success constants.APISuccess) {
+	entries, err := h.entryRepo.CountByParticipant(r.Context(), participant)
+	if err != nil {
+		span := trace.SpanFromContext(r.Context())
+		span.SetStatus(codes.Error, "Failed to count entries")
+		span.SetAttributes(
+			attribute.String("error.type", "repository"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToCountEntries)
+		return
+	}
+
+	httputil.WriteAPISuccess(w, r, success, ParticipantQuotaResponse{
+		Participant: participant,
+		Quota:       h.quotas.For(participant),
+		Own:         h.quotas.Has(participant),
+		Entries:     entries,
+	})
+}
@@ -0,0 +1,115 @@
+package admin
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/validation"
+)
+
+// ResetRequest represents the request body for resetting a participant's data
+type ResetRequest struct {
+	Participant string `json:"participant" validate:"required,len=8,numeric" example:"12345678"` // ISPB, as sent in X-Participant-Id
+}
+
+// ResetResponse represents what a reset removed
+type ResetResponse struct {
+	Participant            string `json:"participant" example:"12345678"`
+	EntriesDeleted         int64  `json:"entriesDeleted" example:"25"`
+	RequestIdsDeleted      int64  `json:"requestIdsDeleted" example:"25"` // requestIds its entries were created with
+	IdempotencyKeysDeleted int64  `json:"idempotencyKeysDeleted" example:"3"`
+	RateLimitKeysDeleted   int    `json:"rateLimitKeysDeleted" example:"4"`
+}
+
+// Reset handles dropping everything a participant left behind, so a shared environment
+// can be reused by the next test suite without redeploying
+//
+//	@Summary		Reset a participant's data
+//	@Description	Deletes the participant's entries, the requestIds it created them with and the idempotency records it claimed, and flushes its rate limit buckets. No ENTRY_DELETED events are published for the entries, except with EVENT_SOURCE=changestream, which reports every deletion. Other participants are not affected.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		ResetRequest								true	"Participant to reset"
+//	@Success		200		{object}	httputil.APIResponse{data=ResetResponse}	"Participant reset"
+//	@Failure		400		{object}	httputil.APIResponse						"Invalid request body"
+//	@Failure		401		{object}	httputil.APIResponse						"Unauthorized"
+//	@Failure		403		{object}	httputil.APIResponse						"Bearer token without the admin scope"
+//	@Failure		500		{object}	httputil.APIResponse						"Internal server error"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/reset [post]
+func (h *Handler) Reset(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+
+	var req ResetRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		span.SetStatus(codes.Error, "JSON decode failed")
+		span.SetAttributes(
+			attribute.String("error.type", "json_decode"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
+		return
+	}
+
+	// Validate request using validator library
+	if err := validation.Validate(&req); err != nil {
+		span.SetStatus(codes.Error, "Validation failed")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, constants.ErrInvalidRequestBody)
+		return
+	}
+	span.SetAttributes(attribute.String("reset.participant", req.Participant))
+
+	// A failed step can simply be retried: every step deletes whatever is left
+	resp := ResetResponse{Participant: req.Participant}
+	var err error
+	if resp.EntriesDeleted, err = h.entryRepo.DeleteByParticipant(ctx, req.Participant); err != nil {
+		h.resetFailed(w, r, span, "entries", err)
+		return
+	}
+	if resp.RequestIdsDeleted, err = h.requestIDRepo.DeleteByParticipant(ctx, req.Participant); err != nil {
+		h.resetFailed(w, r, span, "request_ids", err)
+		return
+	}
+	if resp.IdempotencyKeysDeleted, err = h.idempotencyRepo.DeleteByParticipant(ctx, req.Participant); err != nil {
+		h.resetFailed(w, r, span, "idempotency", err)
+		return
+	}
+	if resp.RateLimitKeysDeleted, err = h.rateLimiter.Flush(ctx, req.Participant); err != nil {
+		h.resetFailed(w, r, span, "rate_limit", err)
+		return
+	}
+
+	span.SetAttributes(
+		attribute.Int64("reset.entries_deleted", resp.EntriesDeleted),
+		attribute.Int64("reset.request_ids_deleted", resp.RequestIdsDeleted),
+		attribute.Int64("reset.idempotency_keys_deleted", resp.IdempotencyKeysDeleted),
+		attribute.Int("reset.rate_limit_keys_deleted", resp.RateLimitKeysDeleted),
+	)
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessParticipantReset, resp)
+}
+
+// resetFailed records which step of a reset failed and answers 500
+func (h *Handler) resetFailed(w http.ResponseWriter, r *http.Request, span trace.Span, step string, err error) {
+	span.SetStatus(codes.Error, "Failed to reset participant")
+	span.SetAttributes(
+		attribute.String("error.type", "repository"),
+		attribute.String("error.message", err.Error()),
+		attribute.String("reset.step", step),
+	)
+	span.RecordError(err)
+	httputil.WriteError(w, r, err, constants.ErrFailedToResetParticipant)
+}
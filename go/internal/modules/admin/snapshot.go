@@ -0,0 +1,320 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/modules/entries"
+	"github.com/dict-simulator/go/internal/validation"
+)
+
+// SnapshotVersion is the snapshot format written by Export and the only one Import reads
+const SnapshotVersion = 1
+
+// ndjsonContentType selects the one-entry-per-line snapshot format
+const ndjsonContentType = "application/x-ndjson"
+
+// Snapshot is a portable copy of the directory, as written by GET /admin/export
+type Snapshot struct {
+	Version      int                    `json:"version" example:"1"`
+	ExportedAt   time.Time              `json:"exportedAt" example:"2024-01-15T10:30:00Z"`
+	Participants []SnapshotParticipant  `json:"participants"` // derived from the entries; ignored on import
+	Entries      []models.EntryResponse `json:"entries"`
+}
+
+// SnapshotParticipant summarizes the entries a participant owns in a snapshot
+type SnapshotParticipant struct {
+	ISPB    string `json:"ispb" example:"12345678"`
+	Entries int    `json:"entries" example:"25"`
+}
+
+// ImportResponse represents the result of an import
+type ImportResponse struct {
+	Requested int `json:"requested" example:"1000"`
+	Created   int `json:"created" example:"998"`
+	Skipped   int `json:"skipped" example:"2"` // keys that were already registered
+}
+
+// Export handles writing every entry as a snapshot that Import can restore
+//
+//	@Summary		Export the directory
+//	@Description	Writes every entry, in key order, with its timestamps. The default JSON document lists the participants owning entries and can be posted back to /admin/import as is; format=ndjson streams one entry per line instead, without holding the directory in memory. Claims are not part of the snapshot, as the simulator has none.
+//	@Tags			admin
+//	@Produce		json,application/x-ndjson
+//	@Param			format	query		string					false	"json (default) or ndjson"
+//	@Success		200		{object}	Snapshot				"Directory snapshot"
+//	@Failure		400		{object}	httputil.APIResponse	"Unknown format"
+//	@Failure		401		{object}	httputil.APIResponse	"Unauthorized"
+//	@Failure		403		{object}	httputil.APIResponse	"Bearer token without the admin scope"
+//	@Failure		500		{object}	httputil.APIResponse	"Internal server error"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/export [get]
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "ndjson" {
+		span.SetStatus(codes.Error, "Invalid format")
+		span.SetAttributes(attribute.String("error.type", "validation"))
+		httputil.WriteAPIError(w, r, constants.ErrInvalidSnapshotFormat)
+		return
+	}
+
+	var (
+		exported []models.EntryResponse
+		owned    = map[string]int{}
+		written  int
+		enc      *json.Encoder
+	)
+	err := h.entryRepo.ForEachEntry(ctx, func(entry *models.Entry) error {
+		if format != "ndjson" {
+			exported = append(exported, entry.ToResponse())
+			owned[entry.Account.Participant]++
+			return nil
+		}
+
+		// NDJSON is written as it is read; the status line goes out with the first entry
+		if enc == nil {
+			enc = startNDJSON(w)
+		}
+		written++
+		resp := entry.ToResponse()
+		return enc.Encode(&resp)
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to export entries")
+		span.SetAttributes(
+			attribute.String("error.type", "repository"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		if enc != nil {
+			// Part of the snapshot is out, so abort the response rather than let it pass as complete
+			panic(http.ErrAbortHandler)
+		}
+		httputil.WriteError(w, r, err, constants.ErrFailedToExportSnapshot)
+		return
+	}
+
+	if format == "ndjson" {
+		span.SetAttributes(attribute.Int("snapshot.entries", written))
+		if enc == nil {
+			startNDJSON(w)
+			w.WriteHeader(http.StatusOK)
+		}
+		return
+	}
+	span.SetAttributes(attribute.Int("snapshot.entries", len(exported)))
+
+	snapshot := Snapshot{
+		Version:      SnapshotVersion,
+		ExportedAt:   h.clock.Now().UTC(),
+		Participants: make([]SnapshotParticipant, 0, len(owned)),
+		Entries:      exported,
+	}
+	if snapshot.Entries == nil {
+		snapshot.Entries = []models.EntryResponse{}
+	}
+	for ispb, count := range owned {
+		snapshot.Participants = append(snapshot.Participants, SnapshotParticipant{ISPB: ispb, Entries: count})
+	}
+	slices.SortFunc(snapshot.Participants, func(a, b SnapshotParticipant) int {
+		return strings.Compare(a.ISPB, b.ISPB)
+	})
+
+	w.Header().Set("Content-Disposition", `attachment; filename="dict-snapshot.json"`)
+	httputil.WriteJSON(w, http.StatusOK, snapshot)
+}
+
+// Import handles restoring entries from a snapshot written by Export
+//
+//	@Summary		Import a directory snapshot
+//	@Description	Restores the entries of a snapshot from /admin/export, timestamps included, so the same dataset can be loaded in any environment. Send the JSON document, or one entry per line with Content-Type application/x-ndjson; NDJSON is read and inserted in batches as it arrives. Snapshots may be up to MAX_IMPORT_BYTES. Keys that are already registered are skipped; nothing is deleted first (see /admin/reset). Entries are validated like new ones, and missing timestamps default to the simulated time. An invalid NDJSON line stops the import, leaving the batches before it imported. No events are published, except with EVENT_SOURCE=changestream.
+//	@Tags			admin
+//	@Accept			json,application/x-ndjson
+//	@Produce		json
+//	@Param			request	body		Snapshot									true	"Snapshot to restore"
+//	@Success		201		{object}	httputil.APIResponse{data=ImportResponse}	"Snapshot imported"
+//	@Failure		400		{object}	httputil.APIResponse						"Invalid snapshot"
+//	@Failure		401		{object}	httputil.APIResponse						"Unauthorized"
+//	@Failure		403		{object}	httputil.APIResponse						"Bearer token without the admin scope"
+//	@Failure		413		{object}	httputil.APIResponse						"Snapshot larger than MAX_IMPORT_BYTES"
+//	@Failure		500		{object}	httputil.APIResponse						"Internal server error"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/import [post]
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+
+	now := h.clock.Now()
+	batch := make([]models.Entry, 0, seedBatchSize)
+	result := ImportResponse{}
+
+	// flush inserts the pending batch, answering the request itself when that fails
+	flush := func() bool {
+		if len(batch) == 0 {
+			return true
+		}
+		inserted, err := h.entryRepo.InsertMany(ctx, batch)
+		if err != nil {
+			span.SetStatus(codes.Error, "Failed to import entries")
+			span.SetAttributes(
+				attribute.String("error.type", "repository"),
+				attribute.String("error.message", err.Error()),
+			)
+			span.RecordError(err)
+			httputil.WriteError(w, r, err, constants.ErrFailedToImportSnapshot)
+			return false
+		}
+		result.Requested += len(batch)
+		result.Created += inserted
+		batch = batch[:0]
+		return true
+	}
+
+	// add validates an entry and queues it, answering the request itself when it is invalid
+	add := func(resp *models.EntryResponse) bool {
+		entry, err := restoreEntry(resp, now)
+		if err != nil {
+			span.SetStatus(codes.Error, "Validation failed")
+			span.SetAttributes(
+				attribute.String("error.type", "validation"),
+				attribute.String("error.message", err.Error()),
+			)
+			invalid := constants.ErrInvalidRequestBody
+			httputil.WriteAPIError(w, r, invalid.WithMessage(fmt.Sprintf("Entry %d: %s", result.Requested+len(batch), err)))
+			return false
+		}
+		batch = append(batch, entry)
+		return len(batch) < seedBatchSize || flush()
+	}
+
+	// fail answers a body that can't be read or decoded
+	fail := func(apiErr constants.APIError) {
+		span.SetStatus(codes.Error, "Invalid snapshot")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", apiErr.Message),
+		)
+		httputil.WriteAPIError(w, r, apiErr)
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == ndjsonContentType {
+		dec := json.NewDecoder(r.Body)
+		dec.DisallowUnknownFields()
+		for {
+			var resp models.EntryResponse
+			err := dec.Decode(&resp)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				apiErr := httputil.BodyError(err)
+				if apiErr.Status == http.StatusBadRequest {
+					apiErr = apiErr.WithMessage(fmt.Sprintf("Entry %d: %s", result.Requested+len(batch), apiErr.Message))
+				}
+				fail(apiErr)
+				return
+			}
+			if !add(&resp) {
+				return
+			}
+		}
+	} else {
+		var snapshot Snapshot
+		if err := httputil.DecodeJSON(r, &snapshot); err != nil {
+			fail(httputil.BodyError(err))
+			return
+		}
+		if snapshot.Version != SnapshotVersion {
+			fail(constants.ErrUnsupportedSnapshotVersion)
+			return
+		}
+		// Validated up front, so an invalid entry leaves the directory untouched
+		restored := make([]models.Entry, 0, len(snapshot.Entries))
+		for i := range snapshot.Entries {
+			entry, err := restoreEntry(&snapshot.Entries[i], now)
+			if err != nil {
+				invalid := constants.ErrInvalidRequestBody
+				fail(invalid.WithMessage(fmt.Sprintf("Entry %d: %s", i, err)))
+				return
+			}
+			restored = append(restored, entry)
+		}
+		for chunk := range slices.Chunk(restored, seedBatchSize) {
+			batch = append(batch, chunk...)
+			if !flush() {
+				return
+			}
+		}
+	}
+	if !flush() {
+		return
+	}
+	span.SetAttributes(attribute.Int("snapshot.entries", result.Requested))
+
+	result.Skipped = result.Requested - result.Created
+	httputil.WriteAPISuccess(w, r, constants.SuccessSnapshotImported, result)
+}
+
+// startNDJSON sets the headers of an NDJSON export and returns the encoder to write entries with
+func startNDJSON(w http.ResponseWriter) *json.Encoder {
+	w.Header().Set("Content-Type", ndjsonContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="dict-snapshot.ndjson"`)
+	return json.NewEncoder(w)
+}
+
+// restoreEntry validates an exported entry like a new one and fills in missing timestamps
+func restoreEntry(resp *models.EntryResponse, now time.Time) (models.Entry, error) {
+	if result := entries.ValidateKey(models.NormalizeKey(resp.Key), resp.KeyType); !result.Success {
+		return models.Entry{}, errors.New(result.Error.Message)
+	}
+	if err := validation.Validate(&resp.Account); err != nil {
+		return models.Entry{}, errors.New("invalid account")
+	}
+	// Only the formats: the entry may predate the deployment's other account rules
+	if violations := (entries.AccountRules{}).ValidateAccountNumber("account", resp.Account.Branch, resp.Account.AccountNumber); violations != nil {
+		return models.Entry{}, errors.New("invalid account")
+	}
+	if err := validation.Validate(&resp.Owner); err != nil {
+		return models.Entry{}, errors.New("invalid owner")
+	}
+
+	entry := models.Entry{
+		Key:              resp.Key,
+		KeyType:          resp.KeyType,
+		Account:          resp.Account,
+		Owner:            resp.Owner,
+		CreatedAt:        resp.CreatedAt,
+		UpdatedAt:        resp.UpdatedAt,
+		KeyOwnershipDate: resp.KeyOwnershipDate,
+		Sequence:         max(resp.Sequence, 1),
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = now
+	}
+	if entry.UpdatedAt.IsZero() {
+		entry.UpdatedAt = entry.CreatedAt
+	}
+	if entry.KeyOwnershipDate.IsZero() {
+		entry.KeyOwnershipDate = entry.CreatedAt
+	}
+	return entry, nil
+}
@@ -0,0 +1,130 @@
+package admin
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/stubs"
+	"github.com/dict-simulator/go/internal/validation"
+)
+
+// DeleteStubResponse represents the response for removing a stub
+type DeleteStubResponse struct {
+	ID string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
+
+// ListStubs handles listing the stubs
+//
+//	@Summary		List stubs
+//	@Description	Returns the stubs currently answering DICT requests, in registration order
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	httputil.APIResponse{data=[]stubs.Stub}	"Stubs"
+//	@Failure		401	{object}	httputil.APIResponse					"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse					"Bearer token without the admin scope"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/stubs [get]
+func (h *Handler) ListStubs(w http.ResponseWriter, r *http.Request) {
+	httputil.WriteAPISuccess(w, r, constants.SuccessStubsFound, h.stubs.List())
+}
+
+// CreateStub handles registering a new stub
+//
+//	@Summary		Create a stub
+//	@Description	Answers matching requests with a canned status, JSON body and headers, after an optional delay, instead of running the route. A stub matches when its route (a pattern such as GET /entries/{key}), key (the {key} path value) and request headers all match; empty fields match everything. When several match, the most recently added wins. Stubs answer before authentication, rate limiting and idempotency, but after fault injection; responses carry the stub's ID in X-Stub-Id and are not checked against the OpenAPI document.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		stubs.Stub								true	"Stub (id and createdAt are ignored)"
+//	@Success		201		{object}	httputil.APIResponse{data=stubs.Stub}	"Stub created"
+//	@Failure		400		{object}	httputil.APIResponse					"Invalid request body"
+//	@Failure		401		{object}	httputil.APIResponse					"Unauthorized"
+//	@Failure		403		{object}	httputil.APIResponse					"Bearer token without the admin scope"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/stubs [post]
+func (h *Handler) CreateStub(w http.ResponseWriter, r *http.Request) {
+	span := trace.SpanFromContext(r.Context())
+
+	var req stubs.Stub
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		span.SetStatus(codes.Error, "JSON decode failed")
+		span.SetAttributes(
+			attribute.String("error.type", "json_decode"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
+		return
+	}
+
+	// Validate request using validator library
+	if err := validation.Validate(&req); err != nil {
+		span.SetStatus(codes.Error, "Validation failed")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, constants.ErrInvalidRequestBody)
+		return
+	}
+
+	stub := h.stubs.Add(req)
+	span.SetAttributes(
+		attribute.String("stub.id", stub.ID),
+		attribute.String("stub.route", stub.Route),
+		attribute.Int("stub.status_code", stub.StatusCode),
+	)
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessStubCreated, stub)
+}
+
+// DeleteStub handles removing a single stub
+//
+//	@Summary		Delete a stub
+//	@Description	Removes a stub by ID
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		string										true	"Stub ID"
+//	@Success		200	{object}	httputil.APIResponse{data=DeleteStubResponse}	"Stub deleted"
+//	@Failure		401	{object}	httputil.APIResponse						"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse						"Bearer token without the admin scope"
+//	@Failure		404	{object}	httputil.APIResponse						"Stub not found"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/stubs/{id} [delete]
+func (h *Handler) DeleteStub(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if !h.stubs.Remove(id) {
+		httputil.WriteAPIError(w, r, constants.ErrStubNotFound)
+		return
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessStubDeleted, DeleteStubResponse{ID: id})
+}
+
+// ClearStubs handles removing all stubs
+//
+//	@Summary		Clear all stubs
+//	@Description	Removes every stub, so all requests reach their handlers again
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	httputil.APIResponse	"Stubs cleared"
+//	@Failure		401	{object}	httputil.APIResponse	"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse	"Bearer token without the admin scope"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/stubs [delete]
+func (h *Handler) ClearStubs(w http.ResponseWriter, r *http.Request) {
+	h.stubs.Clear()
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessStubsCleared, nil)
+}
@@ -0,0 +1,127 @@
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/validation"
+)
+
+// AdvanceTimeRequest represents the request body for moving the simulated clock forward
+type AdvanceTimeRequest struct {
+	Duration string `json:"duration" validate:"required" example:"168h"` // Go duration, e.g. 168h for 7 days
+}
+
+// TimeResponse represents the state of the simulated clock
+type TimeResponse struct {
+	Now           time.Time `json:"now" example:"2024-01-22T10:30:00Z"`
+	Offset        string    `json:"offset" example:"168h0m0s"`
+	OffsetSeconds int64     `json:"offsetSeconds" example:"604800"`
+}
+
+// timeResponse builds the response for the given simulated time
+func (h *Handler) timeResponse(now time.Time) TimeResponse {
+	offset := h.clock.Offset()
+	return TimeResponse{
+		Now:           now.UTC(),
+		Offset:        offset.String(),
+		OffsetSeconds: int64(offset.Seconds()),
+	}
+}
+
+// GetTime handles reading the simulated clock
+//
+//	@Summary		Get the simulated time
+//	@Description	Returns the time seen by entries, idempotency expiry and rate limit refills, and how far it is ahead of the wall clock
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	httputil.APIResponse{data=TimeResponse}	"Simulated time"
+//	@Failure		401	{object}	httputil.APIResponse					"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse					"Bearer token without the admin scope"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/time [get]
+func (h *Handler) GetTime(w http.ResponseWriter, r *http.Request) {
+	httputil.WriteAPISuccess(w, r, constants.SuccessTimeFound, h.timeResponse(h.clock.Now()))
+}
+
+// AdvanceTime handles moving the simulated clock forward
+//
+//	@Summary		Advance the simulated time
+//	@Description	Moves the simulated clock forward so expiries and bucket refills can be exercised without waiting. Time keeps flowing from the new point.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		AdvanceTimeRequest						true	"How far to advance"
+//	@Success		200		{object}	httputil.APIResponse{data=TimeResponse}	"Clock advanced"
+//	@Failure		400		{object}	httputil.APIResponse					"Invalid duration"
+//	@Failure		401		{object}	httputil.APIResponse					"Unauthorized"
+//	@Failure		403		{object}	httputil.APIResponse					"Bearer token without the admin scope"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/time/advance [post]
+func (h *Handler) AdvanceTime(w http.ResponseWriter, r *http.Request) {
+	span := trace.SpanFromContext(r.Context())
+
+	var req AdvanceTimeRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		span.SetStatus(codes.Error, "JSON decode failed")
+		span.SetAttributes(
+			attribute.String("error.type", "json_decode"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
+		return
+	}
+
+	// Validate request using validator library
+	if err := validation.Validate(&req); err != nil {
+		span.SetStatus(codes.Error, "Validation failed")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, constants.ErrInvalidRequestBody)
+		return
+	}
+
+	// Only forward moves are accepted; DELETE /admin/time returns to the wall clock
+	d, err := time.ParseDuration(req.Duration)
+	if err != nil || d <= 0 {
+		span.SetStatus(codes.Error, "Invalid duration")
+		span.SetAttributes(attribute.String("error.type", "validation"))
+		httputil.WriteAPIError(w, r, constants.ErrInvalidTimeAdvance)
+		return
+	}
+
+	now := h.clock.Advance(d)
+	span.SetAttributes(attribute.String("clock.advance", d.String()))
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessTimeAdvanced, h.timeResponse(now))
+}
+
+// ResetTime handles bringing the simulated clock back to the wall clock
+//
+//	@Summary		Reset the simulated time
+//	@Description	Removes any offset so the simulated clock matches the wall clock again
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	httputil.APIResponse{data=TimeResponse}	"Clock reset"
+//	@Failure		401	{object}	httputil.APIResponse					"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse					"Bearer token without the admin scope"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/time [delete]
+func (h *Handler) ResetTime(w http.ResponseWriter, r *http.Request) {
+	h.clock.Reset()
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessTimeReset, h.timeResponse(h.clock.Now()))
+}
@@ -0,0 +1,72 @@
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// UsageReport handles reporting each participant's usage per day
+//
+//	@Summary		Report usage by participant and day
+//	@Description	Returns each participant's requests, 4xx and 5xx answers, error rate and consumed rate limit tokens per day (UTC, on the simulated clock), by day and then participant. Requests made with the admin token or without a participant are not counted. Counts are current as of the request.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			participant	query		string												false	"Only this participant's usage (ISPB)"
+//	@Param			from		query		string												false	"First day, inclusive (YYYY-MM-DD)"
+//	@Param			to			query		string												false	"Last day, inclusive (YYYY-MM-DD)"
+//	@Success		200			{object}	httputil.APIResponse{data=[]models.UsageReportResponse}	"Usage per participant and day"
+//	@Failure		400			{object}	httputil.APIResponse								"Invalid from or to"
+//	@Failure		401			{object}	httputil.APIResponse								"Unauthorized"
+//	@Failure		403			{object}	httputil.APIResponse								"Bearer token without the admin scope"
+//	@Failure		500			{object}	httputil.APIResponse								"Internal server error"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/reports/usage [get]
+func (h *Handler) UsageReport(w http.ResponseWriter, r *http.Request) {
+	span := trace.SpanFromContext(r.Context())
+	query := r.URL.Query()
+
+	filter := models.UsageReportFilter{
+		Participant: query.Get("participant"),
+		From:        query.Get("from"),
+		To:          query.Get("to"),
+	}
+	for _, day := range []string{filter.From, filter.To} {
+		if _, err := time.Parse(models.UsageDayLayout, day); day != "" && err != nil {
+			httputil.WriteAPIError(w, r, constants.ErrInvalidUsageReportQuery)
+			return
+		}
+	}
+	// UsageDayLayout days sort as strings
+	if filter.From != "" && filter.To != "" && filter.From > filter.To {
+		httputil.WriteAPIError(w, r, constants.ErrInvalidUsageReportQuery)
+		return
+	}
+
+	reports, err := h.usage.Report(r.Context(), filter)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to report usage")
+		span.SetAttributes(
+			attribute.String("error.type", "repository"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToReportUsage)
+		return
+	}
+
+	response := make([]models.UsageReportResponse, 0, len(reports))
+	for i := range reports {
+		response = append(response, reports[i].ToResponse())
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessUsageReported, response)
+}
@@ -0,0 +1,319 @@
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/listing"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// userListOptions are the page sizes, orders and fields of GET /admin/users
+// Users are ordered by ID, which follows creation time; the cursor carries the ID.
+var userListOptions = listing.Options{
+	DefaultLimit:  100,
+	MaxLimit:      1000,
+	Sorts:         []string{"createdAt", "-createdAt"},
+	Fields:        listing.JSONFields(models.UserResponse{}),
+	ValidPosition: primitive.IsValidObjectID,
+}
+
+// UnlockResponse represents the result of unlocking an account
+type UnlockResponse struct {
+	UserID   string `json:"userId" example:"507f1f77bcf86cd799439011"`
+	Unlocked bool   `json:"unlocked" example:"true"` // false when the account was not locked
+}
+
+// PasswordResetResponse represents a user's pending password reset token
+type PasswordResetResponse struct {
+	UserID    string    `json:"userId" example:"507f1f77bcf86cd799439011"`
+	Token     string    `json:"token" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	ExpiresAt time.Time `json:"expiresAt" example:"2024-01-15T11:30:00Z"`
+}
+
+// UserStatusResponse represents the result of disabling or enabling a user
+type UserStatusResponse struct {
+	UserID   string `json:"userId" example:"507f1f77bcf86cd799439011"`
+	Disabled bool   `json:"disabled" example:"true"`
+}
+
+// DeleteUserResponse represents the result of deleting a user
+type DeleteUserResponse struct {
+	UserID string `json:"userId" example:"507f1f77bcf86cd799439011"`
+}
+
+// UnlockUser handles lifting a lockout before it runs out
+//
+//	@Summary		Unlock a user account
+//	@Description	Lifts the lock placed on an account after too many failed logins and forgets its failures, so the user can log in again at once. Unlocking an account that is not locked only forgets its failures.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		string										true	"User ID"
+//	@Success		200	{object}	httputil.APIResponse{data=UnlockResponse}	"Account unlocked"
+//	@Failure		401	{object}	httputil.APIResponse						"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse						"Bearer token without the admin scope"
+//	@Failure		404	{object}	httputil.APIResponse						"Malformed user ID"
+//	@Failure		500	{object}	httputil.APIResponse						"Internal server error"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/users/{id}/unlock [post]
+func (h *Handler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	span := trace.SpanFromContext(r.Context())
+
+	id, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		httputil.WriteAPIError(w, r, constants.ErrUserNotFound)
+		return
+	}
+	span.SetAttributes(attribute.String("user.id", id.Hex()))
+
+	unlocked, err := h.logins.Unlock(r.Context(), id.Hex())
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to unlock account")
+		span.SetAttributes(
+			attribute.String("error.type", "lockout"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToUnlockAccount)
+		return
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessAccountUnlocked, UnlockResponse{UserID: id.Hex(), Unlocked: unlocked})
+}
+
+// GetPasswordReset handles reading a user's pending password reset token
+// The simulator sends no email, so this is how a test gets hold of the token.
+//
+//	@Summary		Read a pending password reset token
+//	@Description	Returns the token issued by the user's latest POST /auth/password-reset, as the reset email would carry it. Tokens expire by the wall clock, not the simulated one.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		string											true	"User ID"
+//	@Success		200	{object}	httputil.APIResponse{data=PasswordResetResponse}	"Pending reset token"
+//	@Failure		401	{object}	httputil.APIResponse							"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse							"Bearer token without the admin scope"
+//	@Failure		404	{object}	httputil.APIResponse							"Malformed user ID, or no reset pending"
+//	@Failure		500	{object}	httputil.APIResponse							"Internal server error"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/users/{id}/password-reset [get]
+func (h *Handler) GetPasswordReset(w http.ResponseWriter, r *http.Request) {
+	span := trace.SpanFromContext(r.Context())
+
+	id, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		httputil.WriteAPIError(w, r, constants.ErrUserNotFound)
+		return
+	}
+	span.SetAttributes(attribute.String("user.id", id.Hex()))
+
+	token, ttl, err := h.resets.Pending(r.Context(), id.Hex())
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to find reset token")
+		span.SetAttributes(
+			attribute.String("error.type", "password_reset"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToFindResetToken)
+		return
+	}
+	if token == "" {
+		httputil.WriteAPIError(w, r, constants.ErrResetTokenNotFound)
+		return
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessResetTokenFound, PasswordResetResponse{
+		UserID:    id.Hex(),
+		Token:     token,
+		ExpiresAt: time.Now().Add(ttl).UTC(),
+	})
+}
+
+// ListUsers handles listing the registered users
+//
+//	@Summary		List users
+//	@Description	Returns the registered users in creation order, a page at a time. Pass nextCursor back as cursor, with the same sort, to read the next page; it is absent on the last one. fields cuts each user down to the named fields.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			limit	query		int											false	"Page size (1-1000, default 100)"
+//	@Param			cursor	query		string										false	"nextCursor of the previous page"
+//	@Param			sort	query		string										false	"createdAt (default) or -createdAt for newest first"
+//	@Param			fields	query		string										false	"Comma-separated user fields to return, e.g. id,email"
+//	@Success		200		{object}	httputil.APIResponse{data=models.UserPage}	"Page of users"
+//	@Failure		400		{object}	httputil.APIResponse						"Invalid limit, cursor, sort or fields"
+//	@Failure		401		{object}	httputil.APIResponse						"Unauthorized"
+//	@Failure		403		{object}	httputil.APIResponse						"Bearer token without the admin scope"
+//	@Failure		500		{object}	httputil.APIResponse						"Internal server error"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/users [get]
+func (h *Handler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	span := trace.SpanFromContext(r.Context())
+
+	query, err := listing.Parse(r.URL.Query(), userListOptions)
+	if err != nil {
+		span.SetStatus(codes.Error, "Invalid list query")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", err.Error()),
+		)
+		httputil.WriteAPIError(w, r, constants.ErrInvalidUserListQuery)
+		return
+	}
+
+	users, err := h.userRepo.List(r.Context(), query.Fetch())
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to list users")
+		span.SetAttributes(
+			attribute.String("error.type", "repository"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToListUsers)
+		return
+	}
+
+	users, next := listing.Trim(users, query, func(u models.User) string { return u.ID.Hex() })
+	page := models.UserPage{Users: []models.UserResponse{}, NextCursor: next}
+	for i := range users {
+		page.Users = append(page.Users, users[i].ToResponse())
+	}
+
+	projected, err := listing.Project(page, query.Fields)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to project users")
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToListUsers)
+		return
+	}
+	httputil.WriteAPISuccess(w, r, constants.SuccessUsersListed, projected)
+}
+
+// DisableUser handles disabling a user
+//
+//	@Summary		Disable a user
+//	@Description	Refuses the user's logins and every token already issued to them, until the user is enabled again. Their data is kept.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		string											true	"User ID"
+//	@Success		200	{object}	httputil.APIResponse{data=UserStatusResponse}	"User disabled"
+//	@Failure		401	{object}	httputil.APIResponse							"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse							"Bearer token without the admin scope"
+//	@Failure		404	{object}	httputil.APIResponse							"User not found"
+//	@Failure		500	{object}	httputil.APIResponse							"Internal server error"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/users/{id}/disable [post]
+func (h *Handler) DisableUser(w http.ResponseWriter, r *http.Request) {
+	h.setDisabled(w, r, true)
+}
+
+// EnableUser handles re-enabling a disabled user
+//
+//	@Summary		Enable a user
+//	@Description	Lets a disabled user log in again. Tokens issued before they were disabled are accepted again while they last.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		string											true	"User ID"
+//	@Success		200	{object}	httputil.APIResponse{data=UserStatusResponse}	"User enabled"
+//	@Failure		401	{object}	httputil.APIResponse							"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse							"Bearer token without the admin scope"
+//	@Failure		404	{object}	httputil.APIResponse							"User not found"
+//	@Failure		500	{object}	httputil.APIResponse							"Internal server error"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/users/{id}/enable [post]
+func (h *Handler) EnableUser(w http.ResponseWriter, r *http.Request) {
+	h.setDisabled(w, r, false)
+}
+
+// setDisabled disables or enables the user in the path
+func (h *Handler) setDisabled(w http.ResponseWriter, r *http.Request, disabled bool) {
+	span := trace.SpanFromContext(r.Context())
+
+	id, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		httputil.WriteAPIError(w, r, constants.ErrUserNotFound)
+		return
+	}
+	span.SetAttributes(
+		attribute.String("user.id", id.Hex()),
+		attribute.Bool("user.disabled", disabled),
+	)
+
+	found, err := h.userRepo.SetDisabled(r.Context(), id, disabled)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to update user")
+		span.SetAttributes(
+			attribute.String("error.type", "repository"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToUpdateUser)
+		return
+	}
+	if !found {
+		httputil.WriteAPIError(w, r, constants.ErrUserNotFound)
+		return
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessUserStatusUpdated, UserStatusResponse{UserID: id.Hex(), Disabled: disabled})
+}
+
+// DeleteUser handles removing a user
+//
+//	@Summary		Delete a user
+//	@Description	Removes the user and forgets their failed logins. Tokens already issued to them are refused from then on; the entries they registered are kept.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		string											true	"User ID"
+//	@Success		200	{object}	httputil.APIResponse{data=DeleteUserResponse}	"User deleted"
+//	@Failure		401	{object}	httputil.APIResponse							"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse							"Bearer token without the admin scope"
+//	@Failure		404	{object}	httputil.APIResponse							"User not found"
+//	@Failure		500	{object}	httputil.APIResponse							"Internal server error"
+//	@Security		AdminToken
+//	@Security		BearerAuth
+//	@Router			/admin/users/{id} [delete]
+func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	span := trace.SpanFromContext(r.Context())
+
+	id, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		httputil.WriteAPIError(w, r, constants.ErrUserNotFound)
+		return
+	}
+	span.SetAttributes(attribute.String("user.id", id.Hex()))
+
+	found, err := h.userRepo.DeleteByID(r.Context(), id)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to delete user")
+		span.SetAttributes(
+			attribute.String("error.type", "repository"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToDeleteUser)
+		return
+	}
+	if !found {
+		httputil.WriteAPIError(w, r, constants.ErrUserNotFound)
+		return
+	}
+
+	// The user is gone either way, so a lockout that can't be cleared only runs out on its own
+	if _, err := h.logins.Unlock(r.Context(), id.Hex()); err != nil {
+		span.RecordError(err)
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessUserDeleted, DeleteUserResponse{UserID: id.Hex()})
+}
@@ -0,0 +1,47 @@
+package apidocs
+
+import (
+	"net/http"
+
+	httpSwagger "github.com/swaggo/http-swagger"
+
+	"github.com/dict-simulator/go/docs"
+	"github.com/dict-simulator/go/internal/openapi"
+)
+
+// SpecPath is where the machine-readable API document is served
+const SpecPath = "/openapi.json"
+
+// Handler serves the generated API document and Swagger UI
+// The document is produced by swag from the handler annotations (go generate ./cmd/server)
+type Handler struct {
+	spec []byte
+}
+
+// NewHandler creates a new API docs handler
+func NewHandler() *Handler {
+	return &Handler{
+		spec: []byte(docs.SwaggerInfo.ReadDoc()),
+	}
+}
+
+// LoadSpec parses the generated document for request/response validation
+func LoadSpec() (*openapi.Spec, error) {
+	return openapi.Load([]byte(docs.SwaggerInfo.ReadDoc()))
+}
+
+// Spec returns the Swagger 2.0 (OpenAPI 2) document
+func (h *Handler) Spec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(h.spec)
+}
+
+// UI returns the Swagger UI, loading the document from SpecPath
+func (h *Handler) UI() http.Handler {
+	return httpSwagger.Handler(
+		httpSwagger.URL(SpecPath),
+		httpSwagger.DeepLinking(true),
+		httpSwagger.DocExpansion("none"),
+		httpSwagger.DomID("swagger-ui"),
+	)
+}
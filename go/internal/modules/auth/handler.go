@@ -1,8 +1,9 @@
 package auth
 
 import (
-	"encoding/json"
+	"context"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -10,10 +11,15 @@ import (
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/dict-simulator/go/internal/clock"
 	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/events"
 	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/jwtkeys"
+	"github.com/dict-simulator/go/internal/lockout"
 	"github.com/dict-simulator/go/internal/middleware"
 	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/passwordreset"
 	"github.com/dict-simulator/go/internal/validation"
 )
 
@@ -28,6 +34,7 @@ type RegisterRequest struct {
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email" example:"user@example.com"`
 	Password string `json:"password" validate:"required" example:"password123"`
+	Scope    string `json:"scope,omitempty" example:"entries:read"` // space-delimited; every user scope when omitted
 }
 
 // AuthResponse represents the authentication response
@@ -36,17 +43,45 @@ type AuthResponse struct {
 	User  models.UserResponse `json:"user"`
 }
 
+// AccountLockedEvent is the data of an ACCOUNT_LOCKED event
+type AccountLockedEvent struct {
+	UserID   string `json:"userId" example:"507f1f77bcf86cd799439011"`
+	Email    string `json:"email" example:"user@example.com"`
+	Failures int    `json:"failures" example:"5"`
+	Duration string `json:"duration" example:"15m0s"` // how long logins are refused
+}
+
+// TokenLifetime is how long the tokens issued on registration and login are valid
+const TokenLifetime = 7 * 24 * time.Hour
+
 // Handler handles auth-related HTTP requests
 type Handler struct {
-	repo      *models.UserRepository
-	jwtSecret string
+	repo      models.UserRepository
+	keys      *jwtkeys.KeySet
+	logins    lockout.Store
+	lockout   lockout.Policy
+	resets    passwordreset.Store
+	resetTTL  time.Duration
+	publisher events.Publisher
+	clock     clock.Clock
 }
 
 // NewHandler creates a new auth handler
-func NewHandler(repo *models.UserRepository, jwtSecret string) *Handler {
+// Accounts are locked after policy.MaxFailures wrong passwords without a successful login in
+// between, within policy.Duration of the first; 0 never locks them.
+// Password reset tokens are kept in resets and stay valid for resetTTL.
+// ACCOUNT_LOCKED events are published to publisher, stamped by clk.
+// Tokens are signed by keys and last TokenLifetime.
+func NewHandler(repo models.UserRepository, keys *jwtkeys.KeySet, logins lockout.Store, policy lockout.Policy, resets passwordreset.Store, resetTTL time.Duration, publisher events.Publisher, clk clock.Clock) *Handler {
 	return &Handler{
 		repo:      repo,
-		jwtSecret: jwtSecret,
+		keys:      keys,
+		logins:    logins,
+		lockout:   policy,
+		resets:    resets,
+		resetTTL:  resetTTL,
+		publisher: publisher,
+		clock:     clk,
 	}
 }
 
@@ -61,6 +96,7 @@ func NewHandler(repo *models.UserRepository, jwtSecret string) *Handler {
 //	@Success		201		{object}	httputil.APIResponse{data=AuthResponse}		"User registered successfully"
 //	@Failure		400		{object}	httputil.APIResponse							"Invalid request body"
 //	@Failure		409		{object}	httputil.APIResponse							"User already exists"
+//	@Failure		429		{object}	httputil.APIResponse							"Too many attempts from this address"
 //	@Failure		500		{object}	httputil.APIResponse							"Internal server error"
 //	@Router			/auth/register [post]
 func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
@@ -68,14 +104,14 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	span := trace.SpanFromContext(ctx)
 
 	var req RegisterRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := httputil.DecodeJSON(r, &req); err != nil {
 		span.SetStatus(codes.Error, "JSON decode failed")
 		span.SetAttributes(
 			attribute.String("error.type", "json_decode"),
 			attribute.String("error.message", err.Error()),
 		)
 		span.RecordError(err)
-		httputil.WriteAPIError(w, r, constants.ErrInvalidRequestBody)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
 		return
 	}
 
@@ -100,7 +136,7 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 			attribute.String("error.message", err.Error()),
 		)
 		span.RecordError(err)
-		httputil.WriteAPIError(w, r, constants.ErrFailedToCheckUser)
+		httputil.WriteError(w, r, err, constants.ErrFailedToCheckUser)
 		return
 	}
 
@@ -114,7 +150,7 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Create user
+	// Create user; an email registered since the check above is reported as taken too
 	user, err := h.repo.Create(ctx, req.Email, req.Password, req.Name)
 	if err != nil {
 		span.SetStatus(codes.Error, "Failed to create user")
@@ -123,12 +159,12 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 			attribute.String("error.message", err.Error()),
 		)
 		span.RecordError(err)
-		httputil.WriteAPIError(w, r, constants.ErrFailedToCreateUser)
+		httputil.WriteError(w, r, err, constants.ErrFailedToCreateUser)
 		return
 	}
 
 	// Generate JWT
-	token, err := h.generateToken(user)
+	token, err := h.generateToken(user, models.UserScopes)
 	if err != nil {
 		span.SetStatus(codes.Error, "Failed to generate token")
 		span.SetAttributes(
@@ -136,7 +172,7 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 			attribute.String("error.message", err.Error()),
 		)
 		span.RecordError(err)
-		httputil.WriteAPIError(w, r, constants.ErrFailedToGenerateToken)
+		httputil.WriteError(w, r, err, constants.ErrFailedToGenerateToken)
 		return
 	}
 
@@ -149,14 +185,16 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 // Login handles user login
 //
 //	@Summary		User login
-//	@Description	Authenticate a user with email and password. Returns a JWT token on success.
+//	@Description	Authenticate a user with email and password. Returns a JWT token on success, carrying the requested scopes, or every scope a user can hold (all but admin) when scope is omitted. Ask for entries:read alone for a monitoring token that can't change the directory.
 //	@Tags			auth
 //	@Accept			json
 //	@Produce		json
 //	@Param			request	body		LoginRequest								true	"User login credentials"
 //	@Success		200		{object}	httputil.APIResponse{data=AuthResponse}	"Login successful"
-//	@Failure		400		{object}	httputil.APIResponse						"Invalid request body"
-//	@Failure		401		{object}	httputil.APIResponse						"Invalid credentials"
+//	@Failure		400		{object}	httputil.APIResponse						"Invalid request body or scope"
+//	@Failure		401		{object}	httputil.APIResponse						"Invalid credentials, or disabled account"
+//	@Failure		423		{object}	httputil.APIResponse						"Account locked after too many failed logins"
+//	@Failure		429		{object}	httputil.APIResponse						"Too many attempts from this address"
 //	@Failure		500		{object}	httputil.APIResponse						"Internal server error"
 //	@Router			/auth/login [post]
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
@@ -164,14 +202,14 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	span := trace.SpanFromContext(ctx)
 
 	var req LoginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := httputil.DecodeJSON(r, &req); err != nil {
 		span.SetStatus(codes.Error, "JSON decode failed")
 		span.SetAttributes(
 			attribute.String("error.type", "json_decode"),
 			attribute.String("error.message", err.Error()),
 		)
 		span.RecordError(err)
-		httputil.WriteAPIError(w, r, constants.ErrInvalidRequestBody)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
 		return
 	}
 
@@ -187,6 +225,18 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	scopes := models.UserScopes
+	if req.Scope != "" {
+		requested, ok := models.ParseScopes(req.Scope)
+		if !ok || slices.ContainsFunc(requested, func(s models.Scope) bool { return !slices.Contains(models.UserScopes, s) }) {
+			span.SetStatus(codes.Error, "Invalid scope")
+			httputil.WriteAPIError(w, r, constants.ErrInvalidScope)
+			return
+		}
+		scopes = requested
+	}
+	span.SetAttributes(attribute.String("auth.scope", models.FormatScopes(scopes)))
+
 	// Find user
 	user, err := h.repo.FindByEmail(ctx, req.Email)
 	if err != nil {
@@ -196,7 +246,7 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 			attribute.String("error.message", err.Error()),
 		)
 		span.RecordError(err)
-		httputil.WriteAPIError(w, r, constants.ErrFailedToFindUser)
+		httputil.WriteError(w, r, err, constants.ErrFailedToFindUser)
 		return
 	}
 
@@ -210,6 +260,17 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A locked account is refused before its password is checked, so guessing on gains nothing
+	if lockedFor := h.lockedFor(ctx, span, user); lockedFor > 0 {
+		span.SetStatus(codes.Error, "Account locked")
+		span.SetAttributes(
+			attribute.String("error.type", "authentication"),
+			attribute.String("error.message", "Account locked"),
+		)
+		httputil.WriteError(w, r, models.Locked(models.ResourceAccount, lockedFor), constants.ErrAccountLocked)
+		return
+	}
+
 	// Check password
 	if !user.CheckPassword(req.Password) {
 		span.SetStatus(codes.Error, "Invalid credentials")
@@ -217,12 +278,30 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 			attribute.String("error.type", "authentication"),
 			attribute.String("error.message", "Invalid password"),
 		)
+		if h.recordFailure(ctx, span, user) {
+			httputil.WriteError(w, r, models.Locked(models.ResourceAccount, h.lockout.Duration), constants.ErrAccountLocked)
+			return
+		}
 		httputil.WriteAPIError(w, r, constants.ErrInvalidCredentials)
 		return
 	}
 
+	// Checked after the password, so the status of an account is only told to its owner
+	if user.Disabled {
+		span.SetStatus(codes.Error, "User disabled")
+		span.SetAttributes(
+			attribute.String("error.type", "authentication"),
+			attribute.String("error.message", "User disabled"),
+		)
+		httputil.WriteAPIError(w, r, constants.ErrUserDisabled)
+		return
+	}
+
+	// The failures before a successful login are forgiven
+	h.forgetFailures(ctx, span, user.ID.Hex())
+
 	// Generate JWT
-	token, err := h.generateToken(user)
+	token, err := h.generateToken(user, scopes)
 	if err != nil {
 		span.SetStatus(codes.Error, "Failed to generate token")
 		span.SetAttributes(
@@ -230,7 +309,7 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 			attribute.String("error.message", err.Error()),
 		)
 		span.RecordError(err)
-		httputil.WriteAPIError(w, r, constants.ErrFailedToGenerateToken)
+		httputil.WriteError(w, r, err, constants.ErrFailedToGenerateToken)
 		return
 	}
 
@@ -240,17 +319,72 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func (h *Handler) generateToken(user *models.User) (string, error) {
+// lockedFor returns how long the user's account stays locked
+// Lockout storage errors let the login through, like the rate limits, and are recorded on the span.
+func (h *Handler) lockedFor(ctx context.Context, span trace.Span, user *models.User) time.Duration {
+	if h.lockout.MaxFailures <= 0 {
+		return 0
+	}
+	lockedFor, err := h.logins.LockedFor(ctx, user.ID.Hex())
+	if err != nil {
+		span.RecordError(err)
+		return 0
+	}
+	return lockedFor
+}
+
+// recordFailure counts a wrong password against the user and locks the account once the failures
+// reach the policy's limit, publishing ACCOUNT_LOCKED. It reports whether this failure locked it.
+func (h *Handler) recordFailure(ctx context.Context, span trace.Span, user *models.User) bool {
+	if h.lockout.MaxFailures <= 0 {
+		return false
+	}
+
+	account := user.ID.Hex()
+	failures, err := h.logins.Fail(ctx, account, h.lockout.Duration)
+	if err != nil {
+		span.RecordError(err)
+		return false
+	}
+	span.SetAttributes(attribute.Int("auth.failures", failures))
+	if failures < h.lockout.MaxFailures {
+		return false
+	}
+
+	if err := h.logins.Lock(ctx, account, h.lockout.Duration); err != nil {
+		span.RecordError(err)
+		return false
+	}
+	h.publisher.Publish(ctx, events.New(events.AccountLocked, "", AccountLockedEvent{
+		UserID:   account,
+		Email:    user.Email,
+		Failures: failures,
+		Duration: h.lockout.Duration.String(),
+	}, h.clock.Now()))
+	return true
+}
+
+// forgetFailures clears an account's failed logins, and any lock, once its user proved who they are
+func (h *Handler) forgetFailures(ctx context.Context, span trace.Span, account string) {
+	if h.lockout.MaxFailures <= 0 {
+		return
+	}
+	if _, err := h.logins.Unlock(ctx, account); err != nil {
+		span.RecordError(err)
+	}
+}
+
+func (h *Handler) generateToken(user *models.User, scopes []models.Scope) (string, error) {
 	claims := middleware.JWTClaims{
 		UserID: user.ID.Hex(),
 		Email:  user.Email,
 		Name:   user.Name,
+		Scope:  models.FormatScopes(scopes),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(7 * 24 * time.Hour)), // 7 days
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(TokenLifetime)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(h.jwtSecret))
+	return h.keys.Sign(claims)
 }
@@ -0,0 +1,299 @@
+package auth
+
+import (
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/middleware"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/passwordreset"
+	"github.com/dict-simulator/go/internal/validation"
+)
+
+// PasswordResetRequest represents the request body for starting a password reset
+type PasswordResetRequest struct {
+	Email string `json:"email" validate:"required,email" example:"user@example.com"`
+}
+
+// ConfirmPasswordResetRequest represents the request body for completing a password reset
+type ConfirmPasswordResetRequest struct {
+	Token       string `json:"token" validate:"required" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	NewPassword string `json:"newPassword" validate:"required,min=6" example:"newpassword123"`
+}
+
+// ChangePasswordRequest represents the request body for changing a password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" validate:"required" example:"password123"`
+	NewPassword     string `json:"newPassword" validate:"required,min=6" example:"newpassword123"`
+}
+
+// RequestPasswordReset handles issuing a password reset token
+//
+//	@Summary		Request a password reset
+//	@Description	Issues a single-use reset token for the account with this email, replacing any earlier one. The simulator sends no email: read the token with GET /admin/users/{id}/password-reset. The answer is the same whether or not the email is registered, so it can't be used to find accounts.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		PasswordResetRequest	true	"Account email"
+//	@Success		202		{object}	httputil.APIResponse	"Reset requested"
+//	@Failure		400		{object}	httputil.APIResponse	"Invalid request body"
+//	@Failure		429		{object}	httputil.APIResponse	"Too many attempts from this address"
+//	@Failure		500		{object}	httputil.APIResponse	"Internal server error"
+//	@Router			/auth/password-reset [post]
+func (h *Handler) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+
+	var req PasswordResetRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		span.SetStatus(codes.Error, "JSON decode failed")
+		span.SetAttributes(
+			attribute.String("error.type", "json_decode"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
+		return
+	}
+
+	if err := validation.Validate(&req); err != nil {
+		span.SetStatus(codes.Error, "Validation failed")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, constants.ErrInvalidRequestBody)
+		return
+	}
+
+	user, err := h.repo.FindByEmail(ctx, req.Email)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to find user")
+		span.SetAttributes(
+			attribute.String("error.type", "repository"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToFindUser)
+		return
+	}
+
+	if user != nil {
+		if err := h.resets.Save(ctx, user.ID.Hex(), passwordreset.NewToken(), h.resetTTL); err != nil {
+			span.SetStatus(codes.Error, "Failed to issue reset token")
+			span.SetAttributes(
+				attribute.String("error.type", "password_reset"),
+				attribute.String("error.message", err.Error()),
+			)
+			span.RecordError(err)
+			httputil.WriteError(w, r, err, constants.ErrFailedToIssueResetToken)
+			return
+		}
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessPasswordResetRequested, nil)
+}
+
+// ConfirmPasswordReset handles setting a new password with a reset token
+//
+//	@Summary		Complete a password reset
+//	@Description	Sets a new password for the account the reset token was issued to. The token can be used once, and the account's failed logins and lock are cleared.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		ConfirmPasswordResetRequest	true	"Reset token and new password"
+//	@Success		200		{object}	httputil.APIResponse		"Password reset"
+//	@Failure		400		{object}	httputil.APIResponse		"Invalid request body, or invalid, expired or used token"
+//	@Failure		429		{object}	httputil.APIResponse		"Too many attempts from this address"
+//	@Failure		500		{object}	httputil.APIResponse		"Internal server error"
+//	@Router			/auth/password-reset/confirm [post]
+func (h *Handler) ConfirmPasswordReset(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+
+	var req ConfirmPasswordResetRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		span.SetStatus(codes.Error, "JSON decode failed")
+		span.SetAttributes(
+			attribute.String("error.type", "json_decode"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
+		return
+	}
+
+	if err := validation.Validate(&req); err != nil {
+		span.SetStatus(codes.Error, "Validation failed")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, constants.ErrInvalidRequestBody)
+		return
+	}
+
+	userID, err := h.resets.Redeem(ctx, req.Token)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to redeem reset token")
+		span.SetAttributes(
+			attribute.String("error.type", "password_reset"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToResetPassword)
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		span.SetStatus(codes.Error, "Invalid reset token")
+		httputil.WriteAPIError(w, r, constants.ErrInvalidResetToken)
+		return
+	}
+	span.SetAttributes(attribute.String("user.id", userID))
+
+	found, err := h.repo.UpdatePassword(ctx, id, req.NewPassword)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to update password")
+		span.SetAttributes(
+			attribute.String("error.type", "repository"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToResetPassword)
+		return
+	}
+
+	// A user deleted since the token was issued has nothing left to reset
+	if !found {
+		span.SetStatus(codes.Error, "Invalid reset token")
+		httputil.WriteAPIError(w, r, constants.ErrInvalidResetToken)
+		return
+	}
+
+	h.forgetFailures(ctx, span, userID)
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessPasswordReset, nil)
+}
+
+// ChangePassword handles replacing the caller's password
+//
+//	@Summary		Change password
+//	@Description	Replaces the authenticated user's password. The current password is checked like a login: wrong guesses count towards the account lockout.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		ChangePasswordRequest	true	"Current and new password"
+//	@Success		200		{object}	httputil.APIResponse	"Password changed"
+//	@Failure		400		{object}	httputil.APIResponse	"Invalid request body"
+//	@Failure		401		{object}	httputil.APIResponse	"Unauthorized, or wrong current password"
+//	@Failure		404		{object}	httputil.APIResponse	"User no longer exists"
+//	@Failure		423		{object}	httputil.APIResponse	"Account locked after too many failed logins"
+//	@Failure		429		{object}	httputil.APIResponse	"Too many attempts from this address"
+//	@Failure		500		{object}	httputil.APIResponse	"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/auth/change-password [post]
+func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+
+	var req ChangePasswordRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		span.SetStatus(codes.Error, "JSON decode failed")
+		span.SetAttributes(
+			attribute.String("error.type", "json_decode"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
+		return
+	}
+
+	if err := validation.Validate(&req); err != nil {
+		span.SetStatus(codes.Error, "Validation failed")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, constants.ErrInvalidRequestBody)
+		return
+	}
+
+	// Set by AuthMiddleware from the token's claims; client tokens carry no user ID
+	identity, _ := middleware.IdentityFromContext(ctx)
+	id, err := primitive.ObjectIDFromHex(identity.UserID)
+	if err != nil {
+		httputil.WriteAPIError(w, r, constants.ErrInvalidTokenClaims)
+		return
+	}
+	span.SetAttributes(attribute.String("user.id", id.Hex()))
+
+	user, err := h.repo.FindByID(ctx, id)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to find user")
+		span.SetAttributes(
+			attribute.String("error.type", "repository"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToFindUser)
+		return
+	}
+	if user == nil {
+		span.SetStatus(codes.Error, "User not found")
+		httputil.WriteAPIError(w, r, constants.ErrUserNotFound)
+		return
+	}
+
+	// The current password is a password guess like any login, so the lockout applies to it
+	if lockedFor := h.lockedFor(ctx, span, user); lockedFor > 0 {
+		span.SetStatus(codes.Error, "Account locked")
+		httputil.WriteError(w, r, models.Locked(models.ResourceAccount, lockedFor), constants.ErrAccountLocked)
+		return
+	}
+
+	if !user.CheckPassword(req.CurrentPassword) {
+		span.SetStatus(codes.Error, "Invalid current password")
+		span.SetAttributes(
+			attribute.String("error.type", "authentication"),
+			attribute.String("error.message", "Invalid current password"),
+		)
+		if h.recordFailure(ctx, span, user) {
+			httputil.WriteError(w, r, models.Locked(models.ResourceAccount, h.lockout.Duration), constants.ErrAccountLocked)
+			return
+		}
+		httputil.WriteAPIError(w, r, constants.ErrInvalidCurrentPassword)
+		return
+	}
+
+	found, err := h.repo.UpdatePassword(ctx, id, req.NewPassword)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to update password")
+		span.SetAttributes(
+			attribute.String("error.type", "repository"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToChangePassword)
+		return
+	}
+	if !found {
+		span.SetStatus(codes.Error, "User not found")
+		httputil.WriteAPIError(w, r, constants.ErrUserNotFound)
+		return
+	}
+
+	h.forgetFailures(ctx, span, id.Hex())
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessPasswordChanged, nil)
+}
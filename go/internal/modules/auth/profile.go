@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/middleware"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/validation"
+)
+
+// Me handles reading the caller's profile
+//
+//	@Summary		Get the current user
+//	@Description	Returns the profile of the user the bearer token was issued to.
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{object}	httputil.APIResponse{data=models.UserResponse}	"Current user"
+//	@Failure		401	{object}	httputil.APIResponse							"Unauthorized, or disabled account"
+//	@Failure		500	{object}	httputil.APIResponse							"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/auth/me [get]
+func (h *Handler) Me(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+
+	// Set by AuthMiddleware from the token's claims; client tokens carry no user ID
+	identity, _ := middleware.IdentityFromContext(ctx)
+	id, err := primitive.ObjectIDFromHex(identity.UserID)
+	if err != nil {
+		httputil.WriteAPIError(w, r, constants.ErrInvalidTokenClaims)
+		return
+	}
+	span.SetAttributes(attribute.String("user.id", id.Hex()))
+
+	user, err := h.repo.FindByID(ctx, id)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to find user")
+		span.SetAttributes(
+			attribute.String("error.type", "repository"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToFindUser)
+		return
+	}
+	// Deleted between the middleware's check and this lookup
+	if user == nil {
+		span.SetStatus(codes.Error, "User not found")
+		httputil.WriteAPIError(w, r, constants.ErrUnauthorized)
+		return
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessUserFound, user.ToResponse())
+}
+
+// UpdateMe handles changing the caller's email or name
+//
+//	@Summary		Update the current user
+//	@Description	Changes the email and/or name of the user the bearer token was issued to; fields left out keep their value. Tokens already issued stay valid but carry the old email and name until the next login.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.UpdateProfileRequest						true	"New email and/or name"
+//	@Success		200		{object}	httputil.APIResponse{data=models.UserResponse}	"Profile updated"
+//	@Failure		400		{object}	httputil.APIResponse							"Invalid request body, or nothing to update"
+//	@Failure		401		{object}	httputil.APIResponse							"Unauthorized, or disabled account"
+//	@Failure		409		{object}	httputil.APIResponse							"Email used by another user"
+//	@Failure		500		{object}	httputil.APIResponse							"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/auth/me [put]
+func (h *Handler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+
+	var req models.UpdateProfileRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		span.SetStatus(codes.Error, "JSON decode failed")
+		span.SetAttributes(
+			attribute.String("error.type", "json_decode"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
+		return
+	}
+
+	if err := validation.Validate(&req); err != nil {
+		span.SetStatus(codes.Error, "Validation failed")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, constants.ErrInvalidRequestBody)
+		return
+	}
+	if req.Email == "" && req.Name == "" {
+		span.SetStatus(codes.Error, "Empty profile update")
+		httputil.WriteAPIError(w, r, constants.ErrEmptyProfileUpdate)
+		return
+	}
+
+	// Set by AuthMiddleware from the token's claims; client tokens carry no user ID
+	identity, _ := middleware.IdentityFromContext(ctx)
+	id, err := primitive.ObjectIDFromHex(identity.UserID)
+	if err != nil {
+		httputil.WriteAPIError(w, r, constants.ErrInvalidTokenClaims)
+		return
+	}
+	span.SetAttributes(attribute.String("user.id", id.Hex()))
+
+	user, err := h.repo.UpdateProfile(ctx, id, req.Email, req.Name)
+	if err != nil {
+		if errors.Is(err, models.ErrDuplicateKey) {
+			span.SetStatus(codes.Error, "Email already exists")
+		} else {
+			span.SetStatus(codes.Error, "Failed to update user")
+			span.SetAttributes(
+				attribute.String("error.type", "repository"),
+				attribute.String("error.message", err.Error()),
+			)
+			span.RecordError(err)
+		}
+		httputil.WriteError(w, r, err, constants.ErrFailedToUpdateUser)
+		return
+	}
+	if user == nil {
+		span.SetStatus(codes.Error, "User not found")
+		httputil.WriteAPIError(w, r, constants.ErrUnauthorized)
+		return
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessProfileUpdated, user.ToResponse())
+}
@@ -1,45 +1,81 @@
 package entries
 
 import (
-	"encoding/json"
+	"context"
 	"net/http"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/dict-simulator/go/internal/clock"
 	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/events"
 	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/keylimit"
+	"github.com/dict-simulator/go/internal/keypolicy"
+	"github.com/dict-simulator/go/internal/middleware"
 	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/quota"
 	"github.com/dict-simulator/go/internal/validation"
 )
 
+// PayerIDHeader names the payer (CPF or CNPJ) a lookup is made for, as in the DICT's getEntry
+// A lookup made for a payment sees the owner's data in full.
+const PayerIDHeader = "PI-PayerId"
+
 // Handler handles entry-related HTTP requests
 type Handler struct {
-	repo *models.EntryRepository
+	repo          models.EntryRepository
+	fraudRepo     models.FraudMarkerRepository
+	ownershipRepo models.KeyOwnershipRepository
+	requestIDs    models.RequestIDRepository
+	creations     keylimit.Store
+	creationLimit int
+	accountRules  AccountRules
+	policies      *keypolicy.Store
+	quotas        *quota.Store
+	publisher     events.Publisher
+	clock         clock.Clock
 }
 
 // NewHandler creates a new entries handler
-func NewHandler(repo *models.EntryRepository) *Handler {
+// Each owner (tax ID) can create creationLimit keys per day of clk, counted in creations; 0 sets no limit.
+// Accounts of new, updated and transferred entries must pass accountRules, and new keys the policies in effect.
+// Participants can't hold more entries than their quota in quotas allows.
+func NewHandler(repo models.EntryRepository, fraudRepo models.FraudMarkerRepository, ownershipRepo models.KeyOwnershipRepository, requestIDs models.RequestIDRepository, creations keylimit.Store, creationLimit int, accountRules AccountRules, policies *keypolicy.Store, quotas *quota.Store, publisher events.Publisher, clk clock.Clock) *Handler {
 	return &Handler{
-		repo: repo,
+		repo:          repo,
+		fraudRepo:     fraudRepo,
+		ownershipRepo: ownershipRepo,
+		requestIDs:    requestIDs,
+		creations:     creations,
+		creationLimit: creationLimit,
+		accountRules:  accountRules,
+		policies:      policies,
+		quotas:        quotas,
+		publisher:     publisher,
+		clock:         clk,
 	}
 }
 
 // Create handles creating a new entry
 //
 //	@Summary		Create a new DICT entry
-//	@Description	Register a new Pix key entry in the DICT system. The key must be unique and valid for its type.
+//	@Description	Register a new Pix key entry in the DICT system. The key must be unique and valid for its type, and the owner consistent with it: NATURAL_PERSON owners have an 11-digit CPF, LEGAL_PERSON owners a 14-digit CNPJ (and are the only ones with a trade name), and CPF/CNPJ keys are the owner's tax ID. Inconsistent owners are rejected with INCONSISTENT_OWNERSHIP and a violation per field. An account whose branch is not 4 digits or whose number is not up to 20 digits (ending with its check digit when ACCOUNT_NUMBER_CHECK_DIGIT is set) is rejected with INVALID_ACCOUNT, and one opened in the future or before ACCOUNT_MIN_OPENING_DATE with INVALID_OPENING_DATE, each with a violation per field. An owner that has created its daily limit of keys (KEY_CREATION_DAILY_LIMIT) is refused with ENTRY_LIMIT_EXCEEDED and a Retry-After until the next day. A requestId the account's participant already created an entry with is refused with REQUEST_ID_ALREADY_USED, whatever X-Idempotency-Key says; one whose request was refused can be sent again. Key types disabled through PUT /admin/key-policies (or KEY_TYPES_DISABLED) are refused with KEY_TYPE_DISABLED, and while only Brazilian phones are accepted, a PHONE key that isn't a +55 mobile or landline number with INVALID_PHONE. A participant already holding the entries its quota allows (see /admin/quotas) is refused with ENTRY_QUOTA_EXCEEDED until it deletes some.
 //	@Tags			entries
 //	@Accept			json
 //	@Produce		json
 //	@Param			X-Idempotency-Key	header		string					true	"Idempotency key for request deduplication"
 //	@Param			request				body		models.CreateEntryRequest	true	"Entry creation request"
 //	@Success		201					{object}	httputil.APIResponse{data=models.EntryResponse}	"Entry created successfully"
-//	@Failure		400					{object}	httputil.APIResponse								"Invalid request body or key format"
+//	@Failure		400					{object}	httputil.APIResponse								"Invalid request body, key format, owner or account"
 //	@Failure		401					{object}	httputil.APIResponse								"Unauthorized"
-//	@Failure		409					{object}	httputil.APIResponse								"Key already exists"
-//	@Failure		429					{object}	httputil.APIResponse								"Rate limit exceeded"
+//	@Failure		403					{object}	httputil.APIResponse								"Missing scope, participant mismatch or entry quota exceeded"
+//	@Failure		409					{object}	httputil.APIResponse								"Key already exists, requestId already used, or idempotency key in use"
+//	@Failure		422					{object}	httputil.APIResponse								"Key type disabled by the key policies"
+//	@Failure		429					{object}	httputil.APIResponse								"Rate limit or owner's daily key limit exceeded"
 //	@Failure		500					{object}	httputil.APIResponse								"Internal server error"
 //	@Security		BearerAuth
 //	@Router			/entries [post]
@@ -48,14 +84,14 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	span := trace.SpanFromContext(ctx)
 
 	var req models.CreateEntryRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := httputil.DecodeJSON(r, &req); err != nil {
 		span.SetStatus(codes.Error, "JSON decode failed")
 		span.SetAttributes(
 			attribute.String("error.type", "json_decode"),
 			attribute.String("error.message", err.Error()),
 		)
 		span.RecordError(err)
-		httputil.WriteAPIError(w, r, constants.ErrInvalidRequestBody)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
 		return
 	}
 
@@ -71,55 +107,188 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate key format based on key type
-	validationResult := ValidateKey(req.Key, req.KeyType)
-	if !validationResult.Success {
-		span.SetStatus(codes.Error, "Key validation failed")
-		span.SetAttributes(
-			attribute.String("error.type", "key_validation"),
-			attribute.String("error.message", validationResult.Error.Message),
-		)
-		httputil.WriteAPIError(w, r, constants.APIError{
-			Code:    validationResult.Error.Type,
-			Message: validationResult.Error.Message,
-			Status:  http.StatusBadRequest,
-		})
+	if !middleware.AuthorizeParticipant(w, r, req.Account.Participant) {
 		return
 	}
 
-	// Check if key already exists
-	existing, err := h.repo.FindByKey(ctx, req.Key)
-	if err != nil {
-		httputil.WriteAPIError(w, r, constants.ErrFailedToCheckEntry)
+	// Checked first, as a resent request would otherwise find its own key taken. Only a created
+	// entry keeps its requestId, so a refused request can be sent again as it was.
+	if err := h.requestIDs.Claim(ctx, req.Account.Participant, req.RequestId, h.clock.Now()); err != nil {
+		span.SetStatus(codes.Error, "Duplicate requestId")
+		span.SetAttributes(attribute.String("error.type", "request_id"))
+		httputil.WriteError(w, r, err, constants.ErrFailedToCreateEntry)
 		return
 	}
+	created := false
+	defer func() {
+		if !created {
+			h.releaseRequestID(ctx, span, req.Account.Participant, req.RequestId)
+		}
+	}()
 
-	if existing != nil {
-		httputil.WriteAPIError(w, r, constants.ErrKeyAlreadyExists)
+	// Key format and availability, shared with POST /keys/validate
+	if err := h.checkNewKey(ctx, req.Key, req.KeyType); err != nil {
+		apiErr := httputil.APIErrorFor(err, constants.ErrFailedToCheckEntry)
+		if apiErr.Status == http.StatusBadRequest {
+			span.SetStatus(codes.Error, "Key validation failed")
+			span.SetAttributes(
+				attribute.String("error.type", "key_validation"),
+				attribute.String("error.message", apiErr.Message),
+			)
+		}
+		httputil.WriteAPIError(w, r, apiErr)
+		return
+	}
+
+	if violations := ValidateOwnership(models.NormalizeKey(req.Key), req.KeyType, req.Owner); violations != nil {
+		span.SetStatus(codes.Error, "Ownership validation failed")
+		span.SetAttributes(attribute.String("error.type", "ownership_validation"))
+		httputil.WriteAPIError(w, r, constants.ErrInconsistentOwnership.WithViolations(violations))
+		return
+	}
+
+	if h.rejectAccount(w, r, span, "account", req.Account.Branch, req.Account.AccountNumber, &req.Account.OpeningDate) {
+		return
+	}
+	req.Account.OpeningDate = req.Account.OpeningDate.UTC()
+
+	if err := h.checkQuota(ctx, span, req.Account.Participant); err != nil {
+		if apiErr := httputil.APIErrorFor(err, constants.ErrFailedToCheckEntry); apiErr.Status == http.StatusForbidden {
+			span.SetStatus(codes.Error, "Participant's entry quota exceeded")
+			span.SetAttributes(attribute.String("error.type", "entry_quota"))
+		}
+		httputil.WriteError(w, r, err, constants.ErrFailedToCheckEntry)
+		return
+	}
+
+	day, err := h.claimCreation(ctx, span, req.Owner.TaxIdNumber)
+	if err != nil {
+		span.SetStatus(codes.Error, "Owner's daily key limit exceeded")
+		span.SetAttributes(attribute.String("error.type", "entry_limit"))
+		httputil.WriteError(w, r, err, constants.ErrEntryLimitExceeded)
 		return
 	}
 
 	// Create entry
+	// A key registered since checkNewKey looked is reported as taken, like one registered before
 	entry, err := h.repo.Create(ctx, &req)
 	if err != nil {
-		httputil.WriteAPIError(w, r, constants.ErrFailedToCreateEntry)
+		h.releaseCreation(ctx, span, req.Owner.TaxIdNumber, day)
+		httputil.WriteError(w, r, err, constants.ErrFailedToCreateEntry)
 		return
 	}
+	created = true
+
+	h.publisher.Publish(ctx, events.New(events.EntryCreated, entry.Account.Participant, entry.ToResponse(), entry.CreatedAt))
+	entriesCreatedTotal.WithLabelValues(string(entry.KeyType)).Inc()
 
 	httputil.WriteAPISuccess(w, r, constants.SuccessEntryCreated, entry.ToResponse())
 }
 
+// checkQuota returns an ErrLimitExceeded error when participant already holds as many entries as its
+// quota allows. It counts before creating, so concurrent creations can take a participant a few
+// entries over its quota.
+func (h *Handler) checkQuota(ctx context.Context, span trace.Span, participant string) error {
+	limit := h.quotas.For(participant).MaxEntries
+	if limit <= 0 {
+		return nil
+	}
+
+	count, err := h.repo.CountByParticipant(ctx, participant)
+	if err != nil {
+		return err
+	}
+	span.SetAttributes(attribute.Int64("entry.participant_entries", count))
+	if count < int64(limit) {
+		return nil
+	}
+	return models.LimitExceeded(models.ResourceParticipant, 0)
+}
+
+// claimCreation counts a key creation against owner on the clock's (UTC) day and returns that day.
+// Once the owner has created its daily limit of keys it returns an ErrLimitExceeded error instead,
+// lasting until the next day. Store errors let the creation through, like the rate limits, and are
+// recorded on the span.
+func (h *Handler) claimCreation(ctx context.Context, span trace.Span, owner string) (string, error) {
+	if h.creationLimit <= 0 {
+		return "", nil
+	}
+
+	now := h.clock.Now().UTC()
+	day := now.Format(time.DateOnly)
+	count, err := h.creations.Claim(ctx, owner, day)
+	if err != nil {
+		span.RecordError(err)
+		return "", nil
+	}
+	span.SetAttributes(attribute.Int("entry.owner_creations", count))
+	if count <= h.creationLimit {
+		return day, nil
+	}
+
+	// Refused creations don't count, so the owner's count stays the keys it has created
+	h.releaseCreation(ctx, span, owner, day)
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	return "", models.LimitExceeded(models.ResourceOwner, tomorrow.Sub(now))
+}
+
+// releaseCreation uncounts a creation claimCreation on day whose key was not created
+func (h *Handler) releaseCreation(ctx context.Context, span trace.Span, owner, day string) {
+	if day == "" {
+		return
+	}
+	if err := h.creations.Release(context.WithoutCancel(ctx), owner, day); err != nil {
+		span.RecordError(err)
+	}
+}
+
+// rejectAccount answers the request with an error if the account reported under prefix breaks the
+// account rules, checking its branch and number first and then its opening date, unless nil
+// Reports whether it did.
+func (h *Handler) rejectAccount(w http.ResponseWriter, r *http.Request, span trace.Span, prefix, branch, accountNumber string, openingDate *time.Time) bool {
+	if violations := h.accountRules.ValidateAccountNumber(prefix, branch, accountNumber); violations != nil {
+		span.SetStatus(codes.Error, "Account validation failed")
+		span.SetAttributes(attribute.String("error.type", "account_validation"))
+		httputil.WriteAPIError(w, r, constants.ErrInvalidAccount.WithViolations(violations))
+		return true
+	}
+	if openingDate == nil {
+		return false
+	}
+	if violations := h.accountRules.ValidateOpeningDate(prefix+".openingDate", *openingDate, h.clock.Now()); violations != nil {
+		span.SetStatus(codes.Error, "Opening date validation failed")
+		span.SetAttributes(attribute.String("error.type", "opening_date_validation"))
+		httputil.WriteAPIError(w, r, constants.ErrInvalidOpeningDate.WithViolations(violations))
+		return true
+	}
+	return false
+}
+
+// releaseRequestID forgets participant's claim of a requestId whose entry was not created
+func (h *Handler) releaseRequestID(ctx context.Context, span trace.Span, participant, requestID string) {
+	if err := h.requestIDs.Release(context.WithoutCancel(ctx), participant, requestID); err != nil {
+		span.RecordError(err)
+	}
+}
+
 // Get handles getting an entry by key
 //
 //	@Summary		Get a DICT entry by key
-//	@Description	Retrieve a Pix key entry from the DICT system using the key value
+//	@Description	Retrieve a Pix key entry from the DICT system using the key value. Participants other than the entry's own see a natural person owner masked (CPF as ***982247**, name as initials) unless they name the payer in PI-PayerId. Responses carry ETag and Last-Modified; send them back in If-None-Match or If-Modified-Since to get a bodiless 304 while the entry is unchanged.
 //	@Tags			entries
 //	@Accept			json
 //	@Produce		json
-//	@Param			key	path		string	true	"The Pix key to retrieve (CPF, CNPJ, EMAIL, PHONE, or EVP)"
+//	@Param			key					path		string	true	"The Pix key to retrieve (CPF, CNPJ, EMAIL, PHONE, or EVP)"
+//	@Param			PI-PayerId			header		string	false	"CPF or CNPJ of the payer the lookup is made for; shows the owner unmasked"
+//	@Param			If-None-Match		header		string	false	"ETag of a previous response"
+//	@Param			If-Modified-Since	header		string	false	"Last-Modified of a previous response"
 //	@Success		200	{object}	httputil.APIResponse{data=models.EntryResponse}	"Entry found"
-//	@Failure		400	{object}	httputil.APIResponse								"Key is required"
+//	@Header			200	{string}	ETag			"Weak entity tag of the entry"
+//	@Header			200	{string}	Last-Modified	"Entry's last update"
+//	@Success		304	"Entry not modified"
+//	@Failure		400	{object}	httputil.APIResponse								"Key is required or invalid PI-PayerId"
 //	@Failure		401	{object}	httputil.APIResponse								"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse								"Missing scope or participant mismatch"
 //	@Failure		404	{object}	httputil.APIResponse								"Entry not found"
 //	@Failure		429	{object}	httputil.APIResponse								"Rate limit exceeded"
 //	@Failure		500	{object}	httputil.APIResponse								"Internal server error"
@@ -132,11 +301,17 @@ func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	payer := r.Header.Get(PayerIDHeader)
+	if payer != "" && !validation.IsValidCPF(payer) && !validation.IsValidCNPJ(payer) {
+		httputil.WriteAPIError(w, r, constants.ErrInvalidPayerID)
+		return
+	}
+
 	ctx := r.Context()
 
 	entry, err := h.repo.FindByKey(ctx, key)
 	if err != nil {
-		httputil.WriteAPIError(w, r, constants.ErrFailedToFindEntry)
+		httputil.WriteError(w, r, err, constants.ErrFailedToFindEntry)
 		return
 	}
 
@@ -145,15 +320,31 @@ func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httputil.WriteAPISuccess(w, r, constants.SuccessEntryFound, entry.ToResponse())
+	// Other participants only see the owner in full when looking the key up for a payment
+	response := entry.ToResponse()
+	identity, _ := middleware.IdentityFromContext(ctx)
+	if identity.Participant != entry.Account.Participant && payer == "" {
+		response = response.Masked()
+	}
+
+	// Clients polling a key revalidate with If-None-Match or If-Modified-Since
+	etag := response.ETag()
+	httputil.SetValidators(w, etag, entry.UpdatedAt)
+	if httputil.NotModified(r, etag, entry.UpdatedAt) {
+		httputil.WriteNotModified(w, r)
+		return
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessEntryFound, response)
 }
 
 // Delete handles deleting an entry by key
 // Per DICT spec: POST /entries/{key}/delete with request body
 // The participant in the request must match the entry's participant
+// FRAUD deletions leave a fraud marker; ACCOUNT_CLOSURE deletions release the account's other keys
 //
 //	@Summary		Delete a DICT entry
-//	@Description	Delete a Pix key entry from the DICT system. The requesting participant must own the entry.
+//	@Description	Delete a Pix key entry from the DICT system. The requesting participant must own the entry. With reason FRAUD a fraud marker is left on the key (see GET /entries/{key}/fraud-markers); with ACCOUNT_CLOSURE every other key linked to the same account is released too and listed in releasedKeys.
 //	@Tags			entries
 //	@Accept			json
 //	@Produce		json
@@ -162,7 +353,7 @@ func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 //	@Success		200		{object}	httputil.APIResponse{data=models.DeleteEntryResponse}	"Entry deleted successfully"
 //	@Failure		400		{object}	httputil.APIResponse										"Invalid request body or key mismatch"
 //	@Failure		401		{object}	httputil.APIResponse										"Unauthorized"
-//	@Failure		403		{object}	httputil.APIResponse										"Forbidden - participant mismatch"
+//	@Failure		403		{object}	httputil.APIResponse										"Forbidden - participant mismatch or missing scope"
 //	@Failure		404		{object}	httputil.APIResponse										"Entry not found"
 //	@Failure		429		{object}	httputil.APIResponse										"Rate limit exceeded"
 //	@Failure		500		{object}	httputil.APIResponse										"Internal server error"
@@ -179,14 +370,14 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req models.DeleteEntryRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := httputil.DecodeJSON(r, &req); err != nil {
 		span.SetStatus(codes.Error, "JSON decode failed")
 		span.SetAttributes(
 			attribute.String("error.type", "json_decode"),
 			attribute.String("error.message", err.Error()),
 		)
 		span.RecordError(err)
-		httputil.WriteAPIError(w, r, constants.ErrInvalidRequestBody)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
 		return
 	}
 
@@ -209,6 +400,10 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !middleware.AuthorizeParticipant(w, r, req.Participant) {
+		return
+	}
+
 	entry, err := h.repo.DeleteByKeyAndParticipant(ctx, key, req.Participant)
 	if err != nil {
 		span.SetStatus(codes.Error, "Failed to delete entry")
@@ -217,7 +412,7 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 			attribute.String("error.message", err.Error()),
 		)
 		span.RecordError(err)
-		httputil.WriteAPIError(w, r, constants.ErrFailedToDeleteEntry)
+		httputil.WriteError(w, r, err, constants.ErrFailedToDeleteEntry)
 		return
 	}
 
@@ -232,28 +427,246 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httputil.WriteAPISuccess(w, r, constants.SuccessEntryDeleted, models.DeleteEntryResponse{
+	now := h.clock.Now()
+	h.publisher.Publish(ctx, events.New(events.EntryDeleted, entry.Account.Participant, entry.DeletedResponse(), now))
+	entriesDeletedTotal.WithLabelValues(string(req.Reason)).Inc()
+
+	resp := models.DeleteEntryResponse{
 		Message: "Entry deleted successfully",
 		Key:     entry.Key,
-	})
+	}
+
+	// RECONCILIATION needs nothing extra: no lock is ever held on an entry here, so there is none to bypass
+	switch req.Reason {
+	case models.ReasonFraud:
+		// Flag the key for whoever registers it next
+		if err := h.fraudRepo.Create(ctx, models.NewFraudMarker(entry, now)); err != nil {
+			span.SetStatus(codes.Error, "Failed to record fraud marker")
+			span.RecordError(err)
+			httputil.WriteError(w, r, err, constants.ErrFailedToMarkFraud)
+			return
+		}
+	case models.ReasonAccountClosure:
+		// A closed account can't receive payments, so every key linked to it goes with it
+		released, err := h.repo.DeleteByAccount(ctx, entry.Account.Participant, entry.Account.Branch, entry.Account.AccountNumber)
+		if err != nil {
+			span.SetStatus(codes.Error, "Failed to release account keys")
+			span.RecordError(err)
+			httputil.WriteError(w, r, err, constants.ErrFailedToReleaseKeys)
+			return
+		}
+		for i := range released {
+			h.publisher.Publish(ctx, events.New(events.EntryDeleted, released[i].Account.Participant, released[i].DeletedResponse(), now))
+			resp.ReleasedKeys = append(resp.ReleasedKeys, released[i].Key)
+		}
+		entriesDeletedTotal.WithLabelValues(string(req.Reason)).Add(float64(len(released)))
+		span.SetAttributes(attribute.Int("entries.released", len(released)))
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessEntryDeleted, resp)
+}
+
+// FraudMarkers handles listing the fraud markers left on a key
+//
+//	@Summary		List fraud markers of a key
+//	@Description	Returns the markers left each time the key was deleted with reason FRAUD, newest first. Markers outlive the entry, so a key registered again still shows them.
+//	@Tags			entries
+//	@Produce		json
+//	@Param			key	path		string														true	"The Pix key"
+//	@Success		200	{object}	httputil.APIResponse{data=[]models.FraudMarkerResponse}	"Fraud markers (possibly none)"
+//	@Failure		401	{object}	httputil.APIResponse										"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse										"Missing scope or participant mismatch"
+//	@Failure		429	{object}	httputil.APIResponse										"Rate limit exceeded"
+//	@Failure		500	{object}	httputil.APIResponse										"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/entries/{key}/fraud-markers [get]
+func (h *Handler) FraudMarkers(w http.ResponseWriter, r *http.Request) {
+	markers, err := h.fraudRepo.FindByKey(r.Context(), r.PathValue("key"))
+	if err != nil {
+		httputil.WriteError(w, r, err, constants.ErrFailedToFindMarkers)
+		return
+	}
+
+	resp := make([]models.FraudMarkerResponse, len(markers))
+	for i := range markers {
+		resp[i] = markers[i].ToResponse()
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessFraudMarkersFound, resp)
+}
+
+// OwnershipHistory handles listing the participants and owners that held a key
+//
+//	@Summary		List the ownership history of a key
+//	@Description	Returns each period in which a participant held the key for an owner, newest first, as the directory's statistics do for fraud analysis. A period starts when the key is registered, or moves to another participant (portability) or owner, and ends when it is deleted or moves again; the current one has no to. Account and name changes don't start a period. The history outlives the entry, so a released key still shows it. It is built from the entry events, so changes no event is published for, such as participant resets, don't show. Owners of periods held by other participants are masked as in GET /entries/{key}.
+//	@Tags			entries
+//	@Produce		json
+//	@Param			key	path		string														true	"The Pix key"
+//	@Success		200	{object}	httputil.APIResponse{data=[]models.KeyOwnershipResponse}	"Ownership periods (possibly none)"
+//	@Failure		401	{object}	httputil.APIResponse										"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse										"Missing scope or participant mismatch"
+//	@Failure		429	{object}	httputil.APIResponse										"Rate limit exceeded"
+//	@Failure		500	{object}	httputil.APIResponse										"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/entries/{key}/ownership-history [get]
+func (h *Handler) OwnershipHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	periods, err := h.ownershipRepo.FindByKey(ctx, r.PathValue("key"))
+	if err != nil {
+		httputil.WriteError(w, r, err, constants.ErrFailedToFindOwnership)
+		return
+	}
+
+	identity, _ := middleware.IdentityFromContext(ctx)
+	resp := make([]models.KeyOwnershipResponse, len(periods))
+	for i := range periods {
+		resp[i] = periods[i].ToResponse()
+		if periods[i].Participant != identity.Participant {
+			resp[i] = resp[i].Masked()
+		}
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessOwnershipHistoryFound, resp)
+}
+
+// CloseAccount handles closing an account: every key linked to it is deleted or moved in one call
+// The participant in the path must hold the account, so only its own keys are touched.
+//
+//	@Summary		Close an account
+//	@Description	Deletes every key linked to the account (reason ACCOUNT_CLOSURE), or moves them all to another account of the same participant when transferTo is given. Each key gets its own ENTRY_DELETED or ENTRY_UPDATED event.
+//	@Tags			entries
+//	@Accept			json
+//	@Produce		json
+//	@Param			participant		path		string														true	"ISPB of the participant holding the account"
+//	@Param			branch			path		string														true	"Branch"
+//	@Param			accountNumber	path		string														true	"Account number"
+//	@Param			request			body		models.CloseAccountRequest									true	"Where to move the keys, if anywhere"
+//	@Success		200				{object}	httputil.APIResponse{data=models.CloseAccountResponse}	"Account closed"
+//	@Failure		400				{object}	httputil.APIResponse										"Invalid request body or account"
+//	@Failure		401				{object}	httputil.APIResponse										"Unauthorized"
+//	@Failure		403				{object}	httputil.APIResponse										"Missing scope or participant mismatch"
+//	@Failure		404				{object}	httputil.APIResponse										"No entry linked to the account"
+//	@Failure		429				{object}	httputil.APIResponse										"Rate limit exceeded"
+//	@Failure		500				{object}	httputil.APIResponse										"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/accounts/{participant}/{branch}/{accountNumber}/close [post]
+func (h *Handler) CloseAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+
+	participant := r.PathValue("participant")
+	branch := r.PathValue("branch")
+	accountNumber := r.PathValue("accountNumber")
+	if !middleware.AuthorizeParticipant(w, r, participant) {
+		return
+	}
+
+	var req models.CloseAccountRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		span.SetStatus(codes.Error, "JSON decode failed")
+		span.SetAttributes(
+			attribute.String("error.type", "json_decode"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
+		return
+	}
+
+	if err := validation.Validate(&req); err != nil {
+		span.SetStatus(codes.Error, "Validation failed")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, constants.ErrInvalidRequestBody)
+		return
+	}
+
+	if req.TransferTo != nil {
+		if h.rejectAccount(w, r, span, "transferTo", req.TransferTo.Branch, req.TransferTo.AccountNumber, &req.TransferTo.OpeningDate) {
+			return
+		}
+		req.TransferTo.OpeningDate = req.TransferTo.OpeningDate.UTC()
+	}
+
+	var (
+		entries []models.Entry
+		err     error
+	)
+	if req.TransferTo != nil {
+		entries, err = h.repo.TransferAccount(ctx, participant, branch, accountNumber, models.Account{
+			Participant:   participant,
+			Branch:        req.TransferTo.Branch,
+			AccountNumber: req.TransferTo.AccountNumber,
+			AccountType:   req.TransferTo.AccountType,
+			OpeningDate:   req.TransferTo.OpeningDate,
+		})
+	} else {
+		entries, err = h.repo.DeleteByAccount(ctx, participant, branch, accountNumber)
+	}
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to close account")
+		span.SetAttributes(
+			attribute.String("error.type", "repository"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToCloseAccount)
+		return
+	}
+
+	if len(entries) == 0 {
+		httputil.WriteAPIError(w, r, constants.ErrAccountHasNoEntries)
+		return
+	}
+
+	resp := models.CloseAccountResponse{
+		Participant:   participant,
+		Branch:        branch,
+		AccountNumber: accountNumber,
+		Keys:          make([]string, len(entries)),
+		TransferredTo: req.TransferTo,
+	}
+
+	now := h.clock.Now()
+	for i := range entries {
+		resp.Keys[i] = entries[i].Key
+		if req.TransferTo != nil {
+			h.publisher.Publish(ctx, events.New(events.EntryUpdated, participant, entries[i].ToResponse(), entries[i].UpdatedAt))
+		} else {
+			h.publisher.Publish(ctx, events.New(events.EntryDeleted, participant, entries[i].DeletedResponse(), now))
+		}
+	}
+	if req.TransferTo == nil {
+		entriesDeletedTotal.WithLabelValues(string(models.ReasonAccountClosure)).Add(float64(len(entries)))
+	}
+	span.SetAttributes(attribute.Int("entries.affected", len(entries)))
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessAccountClosed, resp)
 }
 
 // Update handles updating an entry by key
 // Per DICT spec:
 // - EVP keys cannot be updated
+// - Only the participant that owns the entry can update it
 // - Only account info, name, and trade name can be updated
 // - Valid reasons: USER_REQUESTED, BRANCH_TRANSFER, RECONCILIATION, RFB_VALIDATION
+// - RFB_VALIDATION clears a pending Receita Federal validation
 //
 //	@Summary		Update a DICT entry
-//	@Description	Update an existing Pix key entry. EVP keys cannot be updated. Only account info, name, and trade name can be modified.
+//	@Description	Update an existing Pix key entry. The request's participant must own the entry and EVP keys cannot be updated. Only account info, name, and trade name can be modified. Reason RFB_VALIDATION also clears a PENDING_RFB_VALIDATION status set by the periodic Receita Federal review.
 //	@Tags			entries
 //	@Accept			json
 //	@Produce		json
 //	@Param			key		path		string						true	"The Pix key to update"
 //	@Param			request	body		models.UpdateEntryRequest	true	"Update entry request"
 //	@Success		200		{object}	httputil.APIResponse{data=models.EntryResponse}	"Entry updated successfully"
-//	@Failure		400		{object}	httputil.APIResponse								"Invalid request body, key mismatch, or EVP key update attempt"
+//	@Failure		400		{object}	httputil.APIResponse								"Invalid request body, account, key mismatch, or EVP key update attempt"
 //	@Failure		401		{object}	httputil.APIResponse								"Unauthorized"
+//	@Failure		403		{object}	httputil.APIResponse								"Entry owned by another participant, missing scope or participant mismatch"
 //	@Failure		404		{object}	httputil.APIResponse								"Entry not found"
 //	@Failure		429		{object}	httputil.APIResponse								"Rate limit exceeded"
 //	@Failure		500		{object}	httputil.APIResponse								"Internal server error"
@@ -270,14 +683,14 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req models.UpdateEntryRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := httputil.DecodeJSON(r, &req); err != nil {
 		span.SetStatus(codes.Error, "JSON decode failed")
 		span.SetAttributes(
 			attribute.String("error.type", "json_decode"),
 			attribute.String("error.message", err.Error()),
 		)
 		span.RecordError(err)
-		httputil.WriteAPIError(w, r, constants.ErrInvalidRequestBody)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
 		return
 	}
 
@@ -300,9 +713,27 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Neither someone else's entry nor an account at another participant
+	if !middleware.AuthorizeParticipant(w, r, req.Participant) {
+		return
+	}
+	if req.Account != nil && req.Account.Participant != "" && !middleware.AuthorizeParticipant(w, r, req.Account.Participant) {
+		return
+	}
+
+	if req.Account != nil {
+		if h.rejectAccount(w, r, span, "account", req.Account.Branch, req.Account.AccountNumber, req.Account.OpeningDate) {
+			return
+		}
+		if req.Account.OpeningDate != nil {
+			openingDate := req.Account.OpeningDate.UTC()
+			req.Account.OpeningDate = &openingDate
+		}
+	}
+
 	// Optimistic update: try to update immediately
-	// The repository method now filters out EVP keys automatically
-	entry, err := h.repo.UpdateByKey(ctx, key, &req)
+	// The repository method filters out EVP keys and other participants' entries
+	entry, err := h.repo.UpdateByKeyAndParticipant(ctx, key, req.Participant, &req)
 	if err != nil {
 		span.SetStatus(codes.Error, "Failed to update entry")
 		span.SetAttributes(
@@ -310,21 +741,22 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 			attribute.String("error.message", err.Error()),
 		)
 		span.RecordError(err)
-		httputil.WriteAPIError(w, r, constants.ErrFailedToUpdateEntry)
+		httputil.WriteError(w, r, err, constants.ErrFailedToUpdateEntry)
 		return
 	}
 
 	// If entry is nil, it means no document was updated.
 	// This could mean:
 	// 1. The key does not exist
-	// 2. The key exists but is an EVP key (which we can't update)
+	// 2. The key belongs to another participant
+	// 3. The key exists but is an EVP key (which we can't update)
 	// We need to check which case it is to return the correct error.
 	if entry == nil {
 		existing, err := h.repo.FindByKey(ctx, key)
 		if err != nil {
 			span.SetStatus(codes.Error, "Failed to check entry existence")
 			span.RecordError(err)
-			httputil.WriteAPIError(w, r, constants.ErrFailedToFindEntry)
+			httputil.WriteError(w, r, err, constants.ErrFailedToFindEntry)
 			return
 		}
 
@@ -338,8 +770,18 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		// If we found it, it MUST be an EVP key because the UpdateByKey query
-		// only excluded EVP keys.
+		if existing.Account.Participant != req.Participant {
+			span.SetStatus(codes.Error, "Participant mismatch")
+			span.SetAttributes(
+				attribute.String("error.type", "forbidden"),
+				attribute.String("error.message", "Entry owned by another participant"),
+			)
+			httputil.WriteAPIError(w, r, constants.ErrForbiddenParticipant)
+			return
+		}
+
+		// Otherwise it MUST be an EVP key because the UpdateByKeyAndParticipant query
+		// only excluded EVP keys and other participants' entries.
 		if existing.KeyType == models.KeyTypeEVP {
 			span.SetStatus(codes.Error, "EVP key not updatable")
 			span.SetAttributes(
@@ -355,5 +797,94 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.publisher.Publish(ctx, events.New(events.EntryUpdated, entry.Account.Participant, entry.ToResponse(), entry.UpdatedAt))
+
 	httputil.WriteAPISuccess(w, r, constants.SuccessEntryUpdated, entry.ToResponse())
 }
+
+// Validate handles checking a key without registering it
+//
+//	@Summary		Validate a key
+//	@Description	Runs the checks POST /entries applies to a key (format for its type, allowed by the key policies, not already registered) without creating anything, so clients can give feedback before submitting. Invalid keys are reported in the result, not as an error status.
+//	@Tags			entries
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.ValidateKeyRequest								true	"Key to validate"
+//	@Success		200		{object}	httputil.APIResponse{data=models.KeyValidationResponse}	"Validation result"
+//	@Failure		400		{object}	httputil.APIResponse									"Invalid request body"
+//	@Failure		401		{object}	httputil.APIResponse									"Unauthorized"
+//	@Failure		403		{object}	httputil.APIResponse									"Missing scope or participant mismatch"
+//	@Failure		429		{object}	httputil.APIResponse									"Rate limit exceeded"
+//	@Failure		500		{object}	httputil.APIResponse									"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/keys/validate [post]
+func (h *Handler) Validate(w http.ResponseWriter, r *http.Request) {
+	var req models.ValidateKeyRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
+		return
+	}
+
+	if err := validation.Validate(&req); err != nil {
+		httputil.WriteAPIError(w, r, constants.ErrInvalidRequestBody)
+		return
+	}
+
+	result := models.KeyValidationResponse{
+		Key:     req.Key,
+		KeyType: req.KeyType,
+		Valid:   true,
+	}
+
+	if err := h.checkNewKey(r.Context(), req.Key, req.KeyType); err != nil {
+		// A failed lookup says nothing about the key, so it stays an error
+		apiErr := httputil.APIErrorFor(err, constants.ErrFailedToCheckEntry)
+		if apiErr.Status >= http.StatusInternalServerError {
+			httputil.WriteAPIError(w, r, apiErr)
+			return
+		}
+		result.Valid = false
+		result.Error = apiErr.Code
+		result.Message = apiErr.Message
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessKeyValidated, result)
+}
+
+// checkNewKey runs the checks a key must pass to be registered: its format for the key type, the
+// key policies in effect and that no entry holds it yet. Returns nil when the key could be created,
+// a constants.APIError for a malformed or refused key, models.ErrEntryKeyExists for a taken one, or
+// the failure of the lookup.
+// The format is checked on the normalized key, so "Test@Example.com" and "+55 11 99999-9999" are accepted.
+func (h *Handler) checkNewKey(ctx context.Context, key string, keyType models.KeyType) error {
+	// Validate key format based on key type
+	validationResult := ValidateKey(models.NormalizeKey(key), keyType)
+	if !validationResult.Success {
+		return constants.APIError{
+			Code:    validationResult.Error.Type,
+			Message: validationResult.Error.Message,
+			Status:  http.StatusBadRequest,
+		}
+	}
+
+	// Policies are checked on well-formed keys only, so a malformed one is reported as such
+	policies := h.policies.Get()
+	if !policies.Allows(keyType) {
+		return constants.ErrKeyTypeDisabled
+	}
+	if keyType == models.KeyTypePHONE && !policies.AllowsPhone(models.NormalizeKey(key)) {
+		return constants.ErrPhoneNotBrazilian
+	}
+
+	// Check if key already exists
+	existing, err := h.repo.FindByKey(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		return models.ErrEntryKeyExists
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package entries
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/listing"
+	"github.com/dict-simulator/go/internal/middleware"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// listOptions are the page sizes, orders and fields of GET /participants/{ispb}/entries
+var listOptions = listing.Options{
+	DefaultLimit: 100,
+	MaxLimit:     1000,
+	Sorts:        []string{"key", "-key"},
+	Fields:       listing.JSONFields(models.EntryResponse{}),
+}
+
+// listQuery is the parsed query string of GET /participants/{ispb}/entries
+type listQuery struct {
+	listing.Query
+	since time.Time
+}
+
+// parseListQuery reads the listing parameters and since
+// Entries are ordered by normalized key, which is what the cursor carries.
+func parseListQuery(q url.Values) (listQuery, error) {
+	parsed, err := listing.Parse(q, listOptions)
+	if err != nil {
+		return listQuery{}, err
+	}
+	query := listQuery{Query: parsed}
+
+	if raw := q.Get("since"); raw != "" {
+		since, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return listQuery{}, fmt.Errorf("since must be an RFC 3339 timestamp")
+		}
+		query.since = since
+	}
+
+	return query, nil
+}
+
+// ListByParticipant handles listing the entries registered under a participant
+// Participants page through it to reconcile their own base against the directory.
+//
+//	@Summary		List a participant's entries
+//	@Description	Returns the entries registered under the ISPB in key order, a page at a time. With since, only entries created or updated at or after that instant are listed. Pass nextCursor back as cursor, with the same sort, to read the next page; it is absent on the last one. fields cuts each entry down to the named fields. A token speaking for a participant can only list its own entries; users speaking for none see natural person owners masked.
+//	@Tags			entries
+//	@Produce		json
+//	@Param			ispb	path		string											true	"Participant ISPB"
+//	@Param			limit	query		int												false	"Page size (1-1000, default 100)"
+//	@Param			cursor	query		string											false	"nextCursor of the previous page"
+//	@Param			sort	query		string											false	"key (default) or -key for descending"
+//	@Param			fields	query		string											false	"Comma-separated entry fields to return, e.g. key,keyType"
+//	@Param			since	query		string											false	"RFC 3339 timestamp; only entries modified at or after it"
+//	@Success		200		{object}	httputil.APIResponse{data=models.EntryPage}	"Page of entries"
+//	@Failure		400		{object}	httputil.APIResponse							"Invalid limit, cursor, sort, fields or since"
+//	@Failure		401		{object}	httputil.APIResponse							"Unauthorized"
+//	@Failure		403		{object}	httputil.APIResponse							"Missing scope or participant mismatch"
+//	@Failure		429		{object}	httputil.APIResponse							"Rate limit exceeded"
+//	@Failure		500		{object}	httputil.APIResponse							"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/participants/{ispb}/entries [get]
+func (h *Handler) ListByParticipant(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+
+	query, err := parseListQuery(r.URL.Query())
+	if err != nil {
+		span.SetStatus(codes.Error, "Invalid list query")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", err.Error()),
+		)
+		httputil.WriteAPIError(w, r, constants.ErrInvalidListQuery)
+		return
+	}
+
+	ispb := r.PathValue("ispb")
+	if !middleware.AuthorizeParticipant(w, r, ispb) {
+		return
+	}
+
+	entries, err := h.repo.ListByParticipant(ctx, ispb, query.since, query.Fetch())
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to list entries")
+		span.SetAttributes(
+			attribute.String("error.type", "repository"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToListEntries)
+		return
+	}
+
+	entries, next := listing.Trim(entries, query.Query, func(e models.Entry) string { return e.NormalizedKey })
+	page := models.EntryPage{Entries: []models.EntryResponse{}, NextCursor: next}
+	// Users speaking for no participant see the owners masked, as on GET /entries/{key}
+	identity, _ := middleware.IdentityFromContext(ctx)
+	for i := range entries {
+		response := entries[i].ToResponse()
+		if identity.Participant != ispb {
+			response = response.Masked()
+		}
+		page.Entries = append(page.Entries, response)
+	}
+
+	projected, err := listing.Project(page, query.Fields)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to project entries")
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToListEntries)
+		return
+	}
+	httputil.WriteAPISuccess(w, r, constants.SuccessEntriesListed, projected)
+}
@@ -0,0 +1,52 @@
+package entries
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dict-simulator/go/internal/listing"
+)
+
+func TestParseListQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		wantOK bool
+		want   listing.Query
+		since  time.Time
+	}{
+		{"defaults", "", true, listing.Query{Page: listing.Page{Limit: 100}, Sort: "key"}, time.Time{}},
+		{"limit", "limit=10", true, listing.Query{Page: listing.Page{Limit: 10}, Sort: "key"}, time.Time{}},
+		{"largest limit", "limit=1000", true, listing.Query{Page: listing.Page{Limit: 1000}, Sort: "key"}, time.Time{}},
+		{"cursor", "cursor=" + listing.Cursor("key", "a@b.com"), true, listing.Query{Page: listing.Page{After: "a@b.com", Limit: 100}, Sort: "key"}, time.Time{}},
+		{"descending", "sort=-key", true, listing.Query{Page: listing.Page{Limit: 100, Descending: true}, Sort: "-key"}, time.Time{}},
+		{"fields", "fields=key,keyType", true, listing.Query{Page: listing.Page{Limit: 100}, Sort: "key", Fields: []string{"key", "keyType"}}, time.Time{}},
+		{"since", "since=2024-01-15T10:30:00Z", true, listing.Query{Page: listing.Page{Limit: 100}, Sort: "key"}, time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)},
+		{"zero limit", "limit=0", false, listing.Query{}, time.Time{}},
+		{"limit too large", "limit=1001", false, listing.Query{}, time.Time{}},
+		{"limit not a number", "limit=ten", false, listing.Query{}, time.Time{}},
+		{"cursor not base64url", "cursor=!!", false, listing.Query{}, time.Time{}},
+		{"cursor of another sort", "sort=-key&cursor=" + listing.Cursor("key", "a@b.com"), false, listing.Query{}, time.Time{}},
+		{"unknown field", "fields=key,secret", false, listing.Query{}, time.Time{}},
+		{"since not RFC 3339", "since=2024-01-15", false, listing.Query{}, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery(%q): %v", tt.query, err)
+			}
+
+			got, err := parseListQuery(q)
+			if (err == nil) != tt.wantOK {
+				t.Fatalf("parseListQuery(%q) error = %v, want ok %v", tt.query, err, tt.wantOK)
+			}
+			if !reflect.DeepEqual(got.Query, tt.want) || !got.since.Equal(tt.since) {
+				t.Errorf("parseListQuery(%q) = %+v, want %+v since %v", tt.query, got, tt.want, tt.since)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package entries
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Counted when the API answers, whichever EVENT_SOURCE publishes the matching event.
+// The number of entries that exist right now is the dict_entries gauge (see jobs.EntryStatistics).
+var (
+	entriesCreatedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dict_entries_created_total",
+			Help: "Total number of entries created through the API by key type",
+		},
+		[]string{"key_type"},
+	)
+
+	entriesDeletedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dict_entries_deleted_total",
+			Help: "Total number of entries deleted through the API by reason",
+		},
+		[]string{"reason"},
+	)
+)
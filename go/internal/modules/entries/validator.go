@@ -3,7 +3,9 @@ package entries
 import (
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/dict-simulator/go/internal/constants"
 	"github.com/dict-simulator/go/internal/models"
 	"github.com/dict-simulator/go/internal/validation"
 )
@@ -20,6 +22,9 @@ type ValidationResult struct {
 	Error   *ValidationError
 }
 
+// emailPattern is the DICT spec email regex; only lowercase allowed
+var emailPattern = regexp.MustCompile(`^[a-z0-9.!#$%&'*+/=?^_` + "`" + `{|}~-]+@[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?(?:\.[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?)*$`)
+
 // ValidateKey validates a key based on its type
 func ValidateKey(key string, keyType models.KeyType) ValidationResult {
 	switch keyType {
@@ -34,33 +39,26 @@ func ValidateKey(key string, keyType models.KeyType) ValidationResult {
 	case models.KeyTypeEVP:
 		return validateEVP(key)
 	default:
-		return ValidationResult{
-			Success: false,
-			Error: &ValidationError{
-				Type:    "INVALID_KEY_TYPE",
-				Message: "Invalid key type",
-			},
-		}
+		return invalid("INVALID_KEY_TYPE", "Invalid key type")
 	}
 }
 
-// validateCPF validates a CPF using Módulo 11 algorithm
-func validateCPF(cpf string) ValidationResult {
-	invalidResult := ValidationResult{
+// invalid builds a failed result; valid keys take the allocation-free path
+func invalid(errType, message string) ValidationResult {
+	return ValidationResult{
 		Success: false,
 		Error: &ValidationError{
-			Type:    "INVALID_CPF",
-			Message: "Invalid CPF format",
+			Type:    errType,
+			Message: message,
 		},
 	}
+}
 
-	// Must be 11 digits
-	if matched, _ := regexp.MatchString(`^\d{11}$`, cpf); !matched {
-		return invalidResult
-	}
-
+// validateCPF validates a CPF using Módulo 11 algorithm
+func validateCPF(cpf string) ValidationResult {
+	// Must be 11 digits with valid check digits
 	if !validation.IsValidCPF(cpf) {
-		return invalidResult
+		return invalid("INVALID_CPF", "Invalid CPF format")
 	}
 
 	return ValidationResult{Success: true}
@@ -68,21 +66,9 @@ func validateCPF(cpf string) ValidationResult {
 
 // validateCNPJ validates a CNPJ using Módulo 11 algorithm
 func validateCNPJ(cnpj string) ValidationResult {
-	invalidResult := ValidationResult{
-		Success: false,
-		Error: &ValidationError{
-			Type:    "INVALID_CNPJ",
-			Message: "Invalid CNPJ format",
-		},
-	}
-
-	// Must be 14 digits
-	if matched, _ := regexp.MatchString(`^\d{14}$`, cnpj); !matched {
-		return invalidResult
-	}
-
+	// Must be 14 digits with valid check digits
 	if !validation.IsValidCNPJ(cnpj) {
-		return invalidResult
+		return invalid("INVALID_CNPJ", "Invalid CNPJ format")
 	}
 
 	return ValidationResult{Success: true}
@@ -92,34 +78,18 @@ func validateCNPJ(cnpj string) ValidationResult {
 // DICT spec: ^[a-z0-9.!#$&'*+/=?^_`{|}~-]+@[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?(?:\.[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?)*$
 // Note: Email must be lowercase and max 77 characters
 func validateEmail(email string) ValidationResult {
-	invalidResult := ValidationResult{
-		Success: false,
-		Error: &ValidationError{
-			Type:    "INVALID_EMAIL",
-			Message: "Invalid email format",
-		},
-	}
-
 	// Max 77 chars as per DICT spec
 	if len(email) > 77 {
-		return invalidResult
+		return invalid("INVALID_EMAIL", "Invalid email format")
 	}
 
 	// DICT spec requires lowercase emails
 	if email != strings.ToLower(email) {
-		return ValidationResult{
-			Success: false,
-			Error: &ValidationError{
-				Type:    "INVALID_EMAIL",
-				Message: "Email must be lowercase",
-			},
-		}
+		return invalid("INVALID_EMAIL", "Email must be lowercase")
 	}
 
-	// DICT spec regex - only lowercase allowed
-	emailRegex := regexp.MustCompile(`^[a-z0-9.!#$%&'*+/=?^_` + "`" + `{|}~-]+@[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?(?:\.[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?)*$`)
-	if !emailRegex.MatchString(email) {
-		return invalidResult
+	if !emailPattern.MatchString(email) {
+		return invalid("INVALID_EMAIL", "Invalid email format")
 	}
 
 	return ValidationResult{Success: true}
@@ -130,20 +100,12 @@ func validateEmail(email string) ValidationResult {
 // Supports international E.164 format (not just Brazil)
 // E.164 requires minimum 8 digits total for valid phone numbers
 func validatePhone(phone string) ValidationResult {
-	invalidResult := ValidationResult{
-		Success: false,
-		Error: &ValidationError{
-			Type:    "INVALID_PHONE",
-			Message: "Invalid phone format",
-		},
-	}
-
 	// DICT spec: E.164 international format
 	// Must start with + followed by country code (1-9) and up to 14 more digits
 	// Minimum length: +XX (country) + NNNNNN (subscriber) = at least 8 chars total
-	phoneRegex := regexp.MustCompile(`^\+[1-9]\d{6,14}$`)
-	if !phoneRegex.MatchString(phone) {
-		return invalidResult
+	if len(phone) < 8 || len(phone) > 16 || phone[0] != '+' || phone[1] == '0' ||
+		!validation.IsDigits(phone[1:], len(phone)-1) {
+		return invalid("INVALID_PHONE", "Invalid phone format")
 	}
 
 	return ValidationResult{Success: true}
@@ -151,19 +113,93 @@ func validatePhone(phone string) ValidationResult {
 
 // validateEVP validates an EVP (UUID v4)
 func validateEVP(evp string) ValidationResult {
-	invalidResult := ValidationResult{
-		Success: false,
-		Error: &ValidationError{
-			Type:    "INVALID_EVP",
-			Message: "Invalid EVP format",
-		},
+	if !validation.IsEVP(strings.ToLower(evp)) {
+		return invalid("INVALID_EVP", "Invalid EVP format")
 	}
 
-	evp = strings.ToLower(evp)
-	uuidRegex := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
-	if !uuidRegex.MatchString(evp) {
-		return invalidResult
+	return ValidationResult{Success: true}
+}
+
+// ValidateOwnership checks that the owner of a new entry is consistent with its key, as DICT does:
+// NATURAL_PERSON owners have an 11-digit CPF and LEGAL_PERSON owners a 14-digit CNPJ, only
+// LEGAL_PERSON owners have a trade name and CPF/CNPJ keys are the owner's own tax ID.
+// Returns one violation per inconsistent field, or nil.
+func ValidateOwnership(key string, keyType models.KeyType, owner models.Owner) []constants.FieldViolation {
+	var violations []constants.FieldViolation
+	violate := func(field, message string) {
+		violations = append(violations, constants.FieldViolation{Field: field, Message: message})
 	}
 
-	return ValidationResult{Success: true}
+	switch owner.Type {
+	case models.OwnerTypeNaturalPerson:
+		if !validation.IsDigits(owner.TaxIdNumber, 11) {
+			violate("owner.taxIdNumber", "Must be an 11-digit CPF for NATURAL_PERSON")
+		}
+		if owner.TradeName != "" {
+			violate("owner.tradeName", "Only allowed for LEGAL_PERSON")
+		}
+	case models.OwnerTypeLegalPerson:
+		if !validation.IsDigits(owner.TaxIdNumber, 14) {
+			violate("owner.taxIdNumber", "Must be a 14-digit CNPJ for LEGAL_PERSON")
+		}
+	}
+
+	switch keyType {
+	case models.KeyTypeCPF:
+		if owner.Type != models.OwnerTypeNaturalPerson {
+			violate("owner.type", "Must be NATURAL_PERSON for CPF keys")
+		}
+		if key != owner.TaxIdNumber {
+			violate("key", "Must equal owner.taxIdNumber for CPF keys")
+		}
+	case models.KeyTypeCNPJ:
+		if owner.Type != models.OwnerTypeLegalPerson {
+			violate("owner.type", "Must be LEGAL_PERSON for CNPJ keys")
+		}
+		if key != owner.TaxIdNumber {
+			violate("key", "Must equal owner.taxIdNumber for CNPJ keys")
+		}
+	}
+
+	return violations
+}
+
+// maxAccountNumberDigits is the longest account number the DICT accepts
+const maxAccountNumberDigits = 20
+
+// AccountRules are the checks an account must pass besides its schema, set per deployment
+type AccountRules struct {
+	MinOpeningDate time.Time // Accounts opened earlier are refused; zero sets no earliest date
+	CheckDigit     bool      // Account numbers end with their Modulo 11 check digit
+}
+
+// ValidateAccountNumber checks the branch and number of an account reported under prefix (e.g. "account"):
+// a 4-digit branch and an account number of up to 20 digits, ending with its check digit if the rules
+// ask for one. Empty values, which updates leave unchanged, are skipped.
+// Returns one violation per invalid field, or nil.
+func (rules AccountRules) ValidateAccountNumber(prefix, branch, accountNumber string) []constants.FieldViolation {
+	var violations []constants.FieldViolation
+	if branch != "" && !validation.IsDigits(branch, 4) {
+		violations = append(violations, constants.FieldViolation{Field: prefix + ".branch", Message: "Must be 4 digits"})
+	}
+	switch {
+	case accountNumber == "":
+	case len(accountNumber) > maxAccountNumberDigits || !validation.IsDigits(accountNumber, len(accountNumber)):
+		violations = append(violations, constants.FieldViolation{Field: prefix + ".accountNumber", Message: "Must be up to 20 digits"})
+	case rules.CheckDigit && !validation.IsValidAccountCheckDigit(accountNumber):
+		violations = append(violations, constants.FieldViolation{Field: prefix + ".accountNumber", Message: "Must end with its Modulo 11 check digit"})
+	}
+	return violations
+}
+
+// ValidateOpeningDate checks that an account reported at field was opened between the rules' earliest date and now
+// Returns its violation, or nil.
+func (rules AccountRules) ValidateOpeningDate(field string, openingDate, now time.Time) []constants.FieldViolation {
+	if openingDate.After(now) {
+		return []constants.FieldViolation{{Field: field, Message: "Must not be in the future"}}
+	}
+	if openingDate.Before(rules.MinOpeningDate) {
+		return []constants.FieldViolation{{Field: field, Message: "Must not be before " + rules.MinOpeningDate.Format(time.DateOnly)}}
+	}
+	return nil
 }
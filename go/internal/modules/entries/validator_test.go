@@ -1,9 +1,12 @@
 package entries
 
 import (
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/validation"
 )
 
 func TestValidateEmail(t *testing.T) {
@@ -162,3 +165,160 @@ func TestValidateKey(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateOwnership(t *testing.T) {
+	natural := models.Owner{Type: models.OwnerTypeNaturalPerson, TaxIdNumber: "11144477735", Name: "Test"}
+	legal := models.Owner{Type: models.OwnerTypeLegalPerson, TaxIdNumber: "11222333000181", Name: "Test", TradeName: "Test Store"}
+
+	withTradeName := natural
+	withTradeName.TradeName = "Test Store"
+	shortCNPJ := legal
+	shortCNPJ.TaxIdNumber = "11144477735"
+
+	tests := []struct {
+		name       string
+		key        string
+		keyType    models.KeyType
+		owner      models.Owner
+		wantFields []string
+	}{
+		{"CPF key of its owner", "11144477735", models.KeyTypeCPF, natural, nil},
+		{"CNPJ key of its owner", "11222333000181", models.KeyTypeCNPJ, legal, nil},
+		{"email of a company", "test@example.com", models.KeyTypeEMAIL, legal, nil},
+		{"CPF key of someone else", "52998224725", models.KeyTypeCPF, natural, []string{"key"}},
+		{"CPF key of a company", "11144477735", models.KeyTypeCPF, legal, []string{"owner.type", "key"}},
+		{"CNPJ key of a person", "11222333000181", models.KeyTypeCNPJ, natural, []string{"owner.type", "key"}},
+		{"person with a trade name", "test@example.com", models.KeyTypeEMAIL, withTradeName, []string{"owner.tradeName"}},
+		{"company with a CPF", "test@example.com", models.KeyTypeEMAIL, shortCNPJ, []string{"owner.taxIdNumber"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields []string
+			for _, v := range ValidateOwnership(tt.key, tt.keyType, tt.owner) {
+				fields = append(fields, v.Field)
+			}
+			if !slices.Equal(fields, tt.wantFields) {
+				t.Errorf("ValidateOwnership() fields = %v, want %v", fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestValidateOpeningDate(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	rules := AccountRules{MinOpeningDate: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	if violations := rules.ValidateOpeningDate("account.openingDate", now, now); violations != nil {
+		t.Errorf("ValidateOpeningDate(now) = %v, want none", violations)
+	}
+	// The same instant in another zone is not in the future
+	saoPaulo := time.FixedZone("BRT", -3*60*60)
+	if violations := rules.ValidateOpeningDate("account.openingDate", now.In(saoPaulo), now); violations != nil {
+		t.Errorf("ValidateOpeningDate(now in BRT) = %v, want none", violations)
+	}
+	if violations := rules.ValidateOpeningDate("account.openingDate", rules.MinOpeningDate, now); violations != nil {
+		t.Errorf("ValidateOpeningDate(earliest date) = %v, want none", violations)
+	}
+
+	for name, openingDate := range map[string]time.Time{
+		"a second later":           now.Add(time.Second),
+		"before the earliest date": rules.MinOpeningDate.Add(-time.Second),
+	} {
+		violations := rules.ValidateOpeningDate("account.openingDate", openingDate, now)
+		if len(violations) != 1 || violations[0].Field != "account.openingDate" {
+			t.Errorf("ValidateOpeningDate(%s) = %v, want an account.openingDate violation", name, violations)
+		}
+	}
+
+	if violations := (AccountRules{}).ValidateOpeningDate("account.openingDate", time.Time{}.Add(time.Hour), now); violations != nil {
+		t.Errorf("ValidateOpeningDate() without an earliest date = %v, want none", violations)
+	}
+}
+
+func TestValidateAccountNumber(t *testing.T) {
+	tests := []struct {
+		name          string
+		rules         AccountRules
+		branch        string
+		accountNumber string
+		wantFields    []string
+	}{
+		{"valid", AccountRules{}, "0001", "0007654321", nil},
+		{"unchanged by an update", AccountRules{}, "", "", nil},
+		{"short branch", AccountRules{}, "001", "0007654321", []string{"account.branch"}},
+		{"branch with letters", AccountRules{}, "00A1", "0007654321", []string{"account.branch"}},
+		{"account number with a dash", AccountRules{}, "0001", "000765432-1", []string{"account.accountNumber"}},
+		{"account number too long", AccountRules{}, "0001", "123456789012345678901", []string{"account.accountNumber"}},
+		{"both invalid", AccountRules{}, "1", "x", []string{"account.branch", "account.accountNumber"}},
+		{"check digit", AccountRules{CheckDigit: true}, "0001", "0007654324", nil},
+		{"wrong check digit", AccountRules{CheckDigit: true}, "0001", "0007654321", []string{"account.accountNumber"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields []string
+			for _, v := range tt.rules.ValidateAccountNumber("account", tt.branch, tt.accountNumber) {
+				fields = append(fields, v.Field)
+			}
+			if !slices.Equal(fields, tt.wantFields) {
+				t.Errorf("ValidateAccountNumber() fields = %v, want %v", fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func BenchmarkValidateKey(b *testing.B) {
+	keys := []struct {
+		key     string
+		keyType models.KeyType
+	}{
+		{"11144477735", models.KeyTypeCPF},
+		{"11222333000181", models.KeyTypeCNPJ},
+		{"test.user+tag@example.com", models.KeyTypeEMAIL},
+		{"+5511999999999", models.KeyTypePHONE},
+		{"123e4567-e89b-42d3-a456-426614174000", models.KeyTypeEVP},
+	}
+
+	for _, k := range keys {
+		b.Run(string(k.keyType), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if !ValidateKey(k.key, k.keyType).Success {
+					b.Fatalf("ValidateKey(%q) failed", k.key)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkValidateCreateEntryRequest(b *testing.B) {
+	req := models.CreateEntryRequest{
+		Key:     "11144477735",
+		KeyType: models.KeyTypeCPF,
+		Account: models.Account{
+			Participant:   "12345678",
+			Branch:        "0001",
+			AccountNumber: "0007654321",
+			AccountType:   "CACC",
+			OpeningDate:   time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC),
+		},
+		Owner: models.Owner{
+			Type:        models.OwnerTypeNaturalPerson,
+			TaxIdNumber: "11144477735",
+			Name:        "Benchmark User",
+		},
+		Reason:    models.ReasonUserRequested,
+		RequestId: "550e8400-e29b-41d4-a716-446655440000",
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := validation.Validate(&req); err != nil {
+			b.Fatalf("Validate() error = %v", err)
+		}
+		if violations := ValidateOwnership(req.Key, req.KeyType, req.Owner); len(violations) > 0 {
+			b.Fatalf("ValidateOwnership() = %v", violations)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package files
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/middleware"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// Handler handles reconciliation file requests
+// The files themselves are generated by the scheduler (jobs.ReconciliationFiles), once a day per participant.
+type Handler struct {
+	fileRepo models.ReconciliationFileRepository
+}
+
+// NewHandler creates a new files handler
+func NewHandler(fileRepo models.ReconciliationFileRepository) *Handler {
+	return &Handler{
+		fileRepo: fileRepo,
+	}
+}
+
+// List handles listing a participant's reconciliation files
+//
+//	@Summary		List reconciliation files
+//	@Description	Lists the daily reconciliation files generated for a participant, newest first. Each covers every key the participant held when it was generated.
+//	@Tags			files
+//	@Produce		json
+//	@Param			participant	query		string															true	"Participant ISPB"
+//	@Success		200			{object}	httputil.APIResponse{data=[]models.ReconciliationFileResponse}	"Files found"
+//	@Failure		400			{object}	httputil.APIResponse											"Participant is required"
+//	@Failure		401			{object}	httputil.APIResponse											"Unauthorized"
+//	@Failure		403			{object}	httputil.APIResponse											"Missing scope or participant mismatch"
+//	@Failure		500			{object}	httputil.APIResponse											"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/files [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	participant := r.URL.Query().Get("participant")
+	if participant == "" {
+		httputil.WriteAPIError(w, r, constants.ErrParticipantRequired)
+		return
+	}
+	if !middleware.AuthorizeParticipant(w, r, participant) {
+		return
+	}
+
+	files, err := h.fileRepo.FindByParticipant(r.Context(), participant)
+	if err != nil {
+		httputil.WriteError(w, r, err, constants.ErrFailedToFindFiles)
+		return
+	}
+
+	resp := make([]models.ReconciliationFileResponse, 0, len(files))
+	for i := range files {
+		resp = append(resp, files[i].ToResponse())
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessFilesFound, resp)
+}
+
+// Download handles downloading a reconciliation file
+//
+//	@Summary		Download a reconciliation file
+//	@Description	Returns the file's rows (key, CID and last modification, in key order) as CSV, or as XML with format=xml
+//	@Tags			files
+//	@Produce		text/csv,application/xml,json
+//	@Param			id		path		string					true	"File ID"
+//	@Param			format	query		string					false	"csv (default) or xml"
+//	@Success		200		{string}	string					"Reconciliation file"
+//	@Failure		400		{object}	httputil.APIResponse	"Unknown format"
+//	@Failure		401		{object}	httputil.APIResponse	"Unauthorized"
+//	@Failure		403		{object}	httputil.APIResponse	"Missing scope or participant mismatch"
+//	@Failure		404		{object}	httputil.APIResponse	"File not found"
+//	@Failure		500		{object}	httputil.APIResponse	"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/files/{id} [get]
+func (h *Handler) Download(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "xml" {
+		httputil.WriteAPIError(w, r, constants.ErrInvalidFileFormat)
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		httputil.WriteAPIError(w, r, constants.ErrFileNotFound)
+		return
+	}
+
+	file, err := h.fileRepo.FindByID(r.Context(), id)
+	if err != nil {
+		httputil.WriteError(w, r, err, constants.ErrFailedToFindFiles)
+		return
+	}
+
+	if file == nil {
+		httputil.WriteAPIError(w, r, constants.ErrFileNotFound)
+		return
+	}
+	if !middleware.AuthorizeParticipant(w, r, file.Participant) {
+		return
+	}
+
+	// Rendered in full first, so a failure can still be answered with an error
+	var buf bytes.Buffer
+	contentType := "text/csv; charset=utf-8"
+	if format == "xml" {
+		contentType = "application/xml; charset=utf-8"
+		err = file.WriteXML(&buf)
+	} else {
+		err = file.WriteCSV(&buf)
+	}
+	if err != nil {
+		httputil.WriteError(w, r, err, constants.ErrFailedToWriteFile)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="dict-%s-%s.%s"`, file.Participant, file.Date, format))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
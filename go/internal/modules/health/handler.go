@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
@@ -34,7 +35,7 @@ func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(HealthResponse{
 		Status:    "ok",
-		Timestamp: time.Now().Format(time.RFC3339),
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
 	})
 }
 
@@ -47,5 +48,8 @@ func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 //	@Success		200	{string}	string	"Prometheus metrics in text format"
 //	@Router			/metrics [get]
 func (h *Handler) Metrics() http.Handler {
-	return promhttp.Handler()
+	// Exemplars are only part of the OpenMetrics format, which scrapers ask for in Accept
+	return promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true}),
+	)
 }
@@ -0,0 +1,222 @@
+package oauth
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/jwtkeys"
+	"github.com/dict-simulator/go/internal/middleware"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// GrantClientCredentials is the only grant type the token endpoint issues tokens for
+const GrantClientCredentials = "client_credentials"
+
+// TokenResponse represents an access token response (RFC 6749 section 5.1)
+type TokenResponse struct {
+	AccessToken string `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TokenType   string `json:"token_type" example:"Bearer"`
+	ExpiresIn   int    `json:"expires_in" example:"3600"` // seconds
+	Scope       string `json:"scope" example:"entries:read entries:write"`
+}
+
+// ErrorResponse represents a token endpoint error (RFC 6749 section 5.2)
+type ErrorResponse struct {
+	Error            string `json:"error" example:"invalid_client"`
+	ErrorDescription string `json:"error_description" example:"Unknown client or wrong client secret"`
+}
+
+// Handler handles the OAuth 2.0 token endpoint
+type Handler struct {
+	clients models.OAuthClientRepository
+	keys    *jwtkeys.KeySet
+	ttl     time.Duration
+}
+
+// NewHandler creates a new OAuth handler
+// Access tokens are signed by keys, like user tokens, and last ttl.
+func NewHandler(clients models.OAuthClientRepository, keys *jwtkeys.KeySet, ttl time.Duration) *Handler {
+	return &Handler{
+		clients: clients,
+		keys:    keys,
+		ttl:     ttl,
+	}
+}
+
+// Token handles issuing access tokens to machine clients
+//
+//	@Summary		Issue an access token
+//	@Description	Client credentials grant (RFC 6749 section 4.4) for participants' service-to-service calls. Authenticate with HTTP Basic (client ID and secret) or with client_id and client_secret in the body, not both. The token carries the client's participant ISPB and the granted scopes: all of the client's scopes when scope is omitted. Errors of the grant itself use the OAuth error format instead of the DICT envelope.
+//	@Tags			oauth
+//	@Accept			x-www-form-urlencoded
+//	@Produce		json
+//	@Param			grant_type		formData	string					true	"Must be client_credentials"
+//	@Param			scope			formData	string					false	"Space-delimited scopes, a subset of the client's"
+//	@Param			client_id		formData	string					false	"Client ID, when not sent with HTTP Basic"
+//	@Param			client_secret	formData	string					false	"Client secret, when not sent with HTTP Basic"
+//	@Success		200				{object}	TokenResponse			"Access token"
+//	@Failure		400				{object}	ErrorResponse			"invalid_request, unsupported_grant_type or invalid_scope"
+//	@Failure		401				{object}	ErrorResponse			"invalid_client"
+//	@Failure		429				{object}	httputil.APIResponse	"Too many attempts from this address"
+//	@Failure		500				{object}	httputil.APIResponse	"Internal server error"
+//	@Router			/oauth/token [post]
+func (h *Handler) Token(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+
+	if err := r.ParseForm(); err != nil {
+		span.SetStatus(codes.Error, "Form parse failed")
+		span.RecordError(err)
+		writeError(w, r, constants.ErrOAuthInvalidRequest)
+		return
+	}
+
+	// Clients authenticate with exactly one method
+	clientID, secret, basic := r.BasicAuth()
+	if basic && (r.PostForm.Has("client_id") || r.PostForm.Has("client_secret")) {
+		span.SetStatus(codes.Error, "Client credentials sent twice")
+		writeError(w, r, constants.ErrOAuthInvalidRequest)
+		return
+	}
+	if !basic {
+		clientID, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+
+	switch grantType := r.PostForm.Get("grant_type"); grantType {
+	case GrantClientCredentials:
+	case "":
+		span.SetStatus(codes.Error, "Missing grant type")
+		writeError(w, r, constants.ErrOAuthInvalidRequest)
+		return
+	default:
+		span.SetStatus(codes.Error, "Unsupported grant type")
+		span.SetAttributes(attribute.String("oauth.grant_type", grantType))
+		writeError(w, r, constants.ErrOAuthUnsupportedGrantType)
+		return
+	}
+	span.SetAttributes(attribute.String("oauth.client_id", clientID))
+
+	client, err := h.authenticate(r, clientID, secret)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to find client")
+		span.SetAttributes(
+			attribute.String("error.type", "repository"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToFindClient)
+		return
+	}
+	if client == nil {
+		span.SetStatus(codes.Error, "Invalid client")
+		// RFC 6749 asks for a challenge naming the scheme the client tried
+		if basic {
+			w.Header().Set("WWW-Authenticate", `Basic realm="dict"`)
+		}
+		writeError(w, r, constants.ErrOAuthInvalidClient)
+		return
+	}
+	span.SetAttributes(attribute.String("oauth.participant", client.Participant))
+
+	scopes := client.Scopes
+	if raw := r.PostForm.Get("scope"); raw != "" {
+		requested, ok := models.ParseScopes(raw)
+		if !ok || slices.ContainsFunc(requested, func(s models.Scope) bool { return !slices.Contains(client.Scopes, s) }) {
+			span.SetStatus(codes.Error, "Invalid scope")
+			writeError(w, r, constants.ErrOAuthInvalidScope)
+			return
+		}
+		scopes = requested
+	}
+
+	token, err := h.generateToken(client, scopes)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to generate token")
+		span.SetAttributes(
+			attribute.String("error.type", "token_generation"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToGenerateToken)
+		return
+	}
+
+	// Tokens must not be cached by anything between the client and the simulator
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set(httputil.CorrelationIDHeader, httputil.GetCorrelationID(r))
+	httputil.WriteJSON(w, http.StatusOK, TokenResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(h.ttl.Seconds()),
+		Scope:       models.FormatScopes(scopes),
+	})
+}
+
+// JWKS handles publishing the public keys that verify access tokens
+//
+//	@Summary		JSON Web Key Set
+//	@Description	Public keys that verify the tokens issued by the simulator, user and client tokens alike (RFC 7517). Tokens name their key in the kid header; keys are rotated every JWT_KEY_ROTATION_INTERVAL and stay published until the tokens they signed have expired. Verifiers should fetch the set again when a token names a key they have not seen. Empty when tokens are signed with the shared HS256 secret.
+//	@Tags			oauth
+//	@Produce		json
+//	@Success		200	{object}	jwtkeys.JWKS	"Public keys, newest first"
+//	@Router			/.well-known/jwks.json [get]
+func (h *Handler) JWKS(w http.ResponseWriter, r *http.Request) {
+	set := h.keys.JWKS()
+	trace.SpanFromContext(r.Context()).SetAttributes(attribute.Int("jwks.keys", len(set.Keys)))
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	httputil.WriteJSON(w, http.StatusOK, set)
+}
+
+// authenticate returns the client if the secret is its own, or nil if either is wrong
+func (h *Handler) authenticate(r *http.Request, clientID, secret string) (*models.OAuthClient, error) {
+	id, err := primitive.ObjectIDFromHex(clientID)
+	if err != nil {
+		return nil, nil
+	}
+
+	client, err := h.clients.FindByID(r.Context(), id)
+	if err != nil || client == nil {
+		return nil, err
+	}
+	if !client.CheckSecret(secret) {
+		return nil, nil
+	}
+	return client, nil
+}
+
+// generateToken signs an access token carrying the client's participant and the granted scopes
+func (h *Handler) generateToken(client *models.OAuthClient, scopes []models.Scope) (string, error) {
+	now := time.Now()
+	claims := middleware.JWTClaims{
+		ClientID:    client.ID.Hex(),
+		Participant: client.Participant,
+		Scope:       models.FormatScopes(scopes),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   client.ID.Hex(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(h.ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	return h.keys.Sign(claims)
+}
+
+// writeError answers with an RFC 6749 error body, which OAuth client libraries understand
+func writeError(w http.ResponseWriter, r *http.Request, apiErr constants.APIError) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set(httputil.CorrelationIDHeader, httputil.GetCorrelationID(r))
+	httputil.WriteJSON(w, apiErr.Status, ErrorResponse{
+		Error:            apiErr.Code,
+		ErrorDescription: apiErr.Message,
+	})
+}
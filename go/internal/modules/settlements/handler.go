@@ -0,0 +1,171 @@
+package settlements
+
+import (
+	"errors"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/middleware"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/validation"
+)
+
+// Handler handles settlement requests
+// Settlements stand in for the SPI: test payments between registered keys that other flows can reference.
+type Handler struct {
+	settlementRepo models.SettlementRepository
+	entryRepo      models.EntryRepository
+	clock          clock.Clock
+}
+
+// NewHandler creates a new settlements handler
+func NewHandler(settlementRepo models.SettlementRepository, entryRepo models.EntryRepository, clk clock.Clock) *Handler {
+	return &Handler{
+		settlementRepo: settlementRepo,
+		entryRepo:      entryRepo,
+		clock:          clk,
+	}
+}
+
+// Create handles posting a settlement
+//
+//	@Summary		Post a settlement
+//	@Description	Records a test payment between two registered keys. The participants are taken from the keys' entries and the end-to-end ID must start with the payer participant's ISPB. A token speaking for a participant can only settle as the payer. Amounts are in centavos.
+//	@Tags			settlements
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.CreateSettlementRequest							true	"Settlement"
+//	@Success		201		{object}	httputil.APIResponse{data=models.SettlementResponse}	"Settlement recorded"
+//	@Failure		400		{object}	httputil.APIResponse									"Invalid request body or end-to-end ID"
+//	@Failure		401		{object}	httputil.APIResponse									"Unauthorized"
+//	@Failure		403		{object}	httputil.APIResponse									"Missing scope or participant mismatch"
+//	@Failure		409		{object}	httputil.APIResponse									"End-to-end ID already settled"
+//	@Failure		422		{object}	httputil.APIResponse									"Payer or payee key not registered"
+//	@Failure		500		{object}	httputil.APIResponse									"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/settlements [post]
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+
+	var req models.CreateSettlementRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		span.SetStatus(codes.Error, "JSON decode failed")
+		span.SetAttributes(
+			attribute.String("error.type", "json_decode"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
+		return
+	}
+
+	// Validate request using validator library
+	if err := validation.Validate(&req); err != nil {
+		span.SetStatus(codes.Error, "Validation failed")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, constants.ErrInvalidRequestBody)
+		return
+	}
+
+	payer, err := h.entryRepo.FindByKey(ctx, req.PayerKey)
+	if err != nil {
+		httputil.WriteError(w, r, err, constants.ErrFailedToFindEntry)
+		return
+	}
+	if payer == nil {
+		httputil.WriteAPIError(w, r, constants.ErrPayerKeyNotFound)
+		return
+	}
+
+	payee, err := h.entryRepo.FindByKey(ctx, req.PayeeKey)
+	if err != nil {
+		httputil.WriteError(w, r, err, constants.ErrFailedToFindEntry)
+		return
+	}
+	if payee == nil {
+		httputil.WriteAPIError(w, r, constants.ErrPayeeKeyNotFound)
+		return
+	}
+
+	// Only the paying participant settles
+	if !middleware.AuthorizeParticipant(w, r, payer.Account.Participant) {
+		return
+	}
+
+	// The SPI assigns end-to-end IDs per paying participant: E + ISPB + timestamp + sequence
+	if req.EndToEndID[1:9] != payer.Account.Participant {
+		httputil.WriteAPIError(w, r, constants.ErrEndToEndIDParticipant)
+		return
+	}
+
+	settlement := &models.Settlement{
+		EndToEndID:       req.EndToEndID,
+		Amount:           req.Amount,
+		PayerKey:         payer.Key,
+		PayerParticipant: payer.Account.Participant,
+		PayeeKey:         payee.Key,
+		PayeeParticipant: payee.Account.Participant,
+		SettledAt:        h.clock.Now().UTC(),
+	}
+
+	if err := h.settlementRepo.Create(ctx, settlement); err != nil {
+		if !errors.Is(err, models.ErrDuplicateKey) {
+			span.SetStatus(codes.Error, "Failed to create settlement")
+			span.SetAttributes(
+				attribute.String("error.type", "repository"),
+				attribute.String("error.message", err.Error()),
+			)
+			span.RecordError(err)
+		}
+		httputil.WriteError(w, r, err, constants.ErrFailedToCreateSettlement)
+		return
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessSettlementCreated, settlement.ToResponse())
+}
+
+// Get handles reading a settlement
+//
+//	@Summary		Get a settlement
+//	@Description	Returns a settlement by its end-to-end ID, to the payer's or payee's participant
+//	@Tags			settlements
+//	@Produce		json
+//	@Param			endToEndId	path		string													true	"End-to-end ID"
+//	@Success		200			{object}	httputil.APIResponse{data=models.SettlementResponse}	"Settlement found"
+//	@Failure		401			{object}	httputil.APIResponse									"Unauthorized"
+//	@Failure		403			{object}	httputil.APIResponse									"Missing scope or participant mismatch"
+//	@Failure		404			{object}	httputil.APIResponse									"Settlement not found"
+//	@Failure		500			{object}	httputil.APIResponse									"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/settlements/{endToEndId} [get]
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	settlement, err := h.settlementRepo.FindByEndToEndID(r.Context(), r.PathValue("endToEndId"))
+	if err != nil {
+		httputil.WriteError(w, r, err, constants.ErrFailedToFindSettlement)
+		return
+	}
+
+	if settlement == nil {
+		httputil.WriteAPIError(w, r, constants.ErrSettlementNotFound)
+		return
+	}
+	// Visible to both sides of the payment
+	identity, _ := middleware.IdentityFromContext(r.Context())
+	if !identity.ActsFor(settlement.PayerParticipant) && !identity.ActsFor(settlement.PayeeParticipant) {
+		httputil.WriteAPIError(w, r, constants.ErrParticipantNotAllowed)
+		return
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessSettlementFound, settlement.ToResponse())
+}
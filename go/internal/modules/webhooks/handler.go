@@ -0,0 +1,242 @@
+package webhooks
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/constants"
+	"github.com/dict-simulator/go/internal/httputil"
+	"github.com/dict-simulator/go/internal/middleware"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/validation"
+)
+
+// Handler handles webhook registration and delivery log requests
+type Handler struct {
+	webhookRepo  models.WebhookRepository
+	deliveryRepo models.WebhookDeliveryRepository
+}
+
+// NewHandler creates a new webhooks handler
+func NewHandler(webhookRepo models.WebhookRepository, deliveryRepo models.WebhookDeliveryRepository) *Handler {
+	return &Handler{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+	}
+}
+
+// Create handles registering a webhook
+//
+//	@Summary		Register a webhook
+//	@Description	Registers a callback URL that receives signed POSTs for the participant's events. The signing secret is only returned in this response; it is generated when omitted.
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.CreateWebhookRequest							true	"Webhook registration"
+//	@Success		201		{object}	httputil.APIResponse{data=models.WebhookResponse}	"Webhook registered"
+//	@Failure		400		{object}	httputil.APIResponse								"Invalid request body"
+//	@Failure		401		{object}	httputil.APIResponse								"Unauthorized"
+//	@Failure		403		{object}	httputil.APIResponse								"Missing scope or participant mismatch"
+//	@Failure		500		{object}	httputil.APIResponse								"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/webhooks [post]
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+
+	var req models.CreateWebhookRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		span.SetStatus(codes.Error, "JSON decode failed")
+		span.SetAttributes(
+			attribute.String("error.type", "json_decode"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, httputil.BodyError(err))
+		return
+	}
+
+	// Validate request using validator library
+	if err := validation.Validate(&req); err != nil {
+		span.SetStatus(codes.Error, "Validation failed")
+		span.SetAttributes(
+			attribute.String("error.type", "validation"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteAPIError(w, r, constants.ErrInvalidRequestBody)
+		return
+	}
+
+	if !middleware.AuthorizeParticipant(w, r, req.Participant) {
+		return
+	}
+
+	if req.Secret == "" {
+		req.Secret = generateSecret()
+	}
+
+	webhook, err := h.webhookRepo.Create(ctx, &req)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to create webhook")
+		span.SetAttributes(
+			attribute.String("error.type", "repository"),
+			attribute.String("error.message", err.Error()),
+		)
+		span.RecordError(err)
+		httputil.WriteError(w, r, err, constants.ErrFailedToCreateWebhook)
+		return
+	}
+
+	resp := webhook.ToResponse()
+	resp.Secret = webhook.Secret
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessWebhookCreated, resp)
+}
+
+// List handles listing a participant's webhooks
+//
+//	@Summary		List webhooks
+//	@Description	Lists the webhooks registered by a participant. Secrets are not returned.
+//	@Tags			webhooks
+//	@Produce		json
+//	@Param			participant	query		string												true	"Participant ISPB"
+//	@Success		200			{object}	httputil.APIResponse{data=[]models.WebhookResponse}	"Webhooks found"
+//	@Failure		400			{object}	httputil.APIResponse								"Participant is required"
+//	@Failure		401			{object}	httputil.APIResponse								"Unauthorized"
+//	@Failure		403			{object}	httputil.APIResponse								"Missing scope or participant mismatch"
+//	@Failure		500			{object}	httputil.APIResponse								"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/webhooks [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	participant := r.URL.Query().Get("participant")
+	if participant == "" {
+		httputil.WriteAPIError(w, r, constants.ErrParticipantRequired)
+		return
+	}
+	if !middleware.AuthorizeParticipant(w, r, participant) {
+		return
+	}
+
+	webhooks, err := h.webhookRepo.FindByParticipant(r.Context(), participant)
+	if err != nil {
+		httputil.WriteError(w, r, err, constants.ErrFailedToFindWebhooks)
+		return
+	}
+
+	resp := make([]models.WebhookResponse, 0, len(webhooks))
+	for i := range webhooks {
+		resp = append(resp, webhooks[i].ToResponse())
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessWebhooksFound, resp)
+}
+
+// Delete handles removing a webhook
+//
+//	@Summary		Delete a webhook
+//	@Description	Removes a webhook registration. Its delivery log is kept.
+//	@Tags			webhooks
+//	@Produce		json
+//	@Param			id	path		string					true	"Webhook ID"
+//	@Success		200	{object}	httputil.APIResponse	"Webhook deleted"
+//	@Failure		401	{object}	httputil.APIResponse	"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse	"Missing scope or participant mismatch"
+//	@Failure		404	{object}	httputil.APIResponse	"Webhook not found"
+//	@Failure		500	{object}	httputil.APIResponse	"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/webhooks/{id} [delete]
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		httputil.WriteAPIError(w, r, constants.ErrWebhookNotFound)
+		return
+	}
+
+	webhook, err := h.webhookRepo.FindByID(ctx, id)
+	if err != nil {
+		httputil.WriteError(w, r, err, constants.ErrFailedToFindWebhooks)
+		return
+	}
+	if webhook == nil {
+		httputil.WriteAPIError(w, r, constants.ErrWebhookNotFound)
+		return
+	}
+	if !middleware.AuthorizeParticipant(w, r, webhook.Participant) {
+		return
+	}
+
+	deleted, err := h.webhookRepo.DeleteByID(ctx, id)
+	if err != nil {
+		httputil.WriteError(w, r, err, constants.ErrFailedToDeleteWebhook)
+		return
+	}
+
+	if !deleted {
+		httputil.WriteAPIError(w, r, constants.ErrWebhookNotFound)
+		return
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessWebhookDeleted, nil)
+}
+
+// Deliveries handles reading a webhook's delivery log
+//
+//	@Summary		List webhook deliveries
+//	@Description	Returns the most recent delivery attempts (up to 100, newest first), including retries
+//	@Tags			webhooks
+//	@Produce		json
+//	@Param			id	path		string												true	"Webhook ID"
+//	@Success		200	{object}	httputil.APIResponse{data=[]models.WebhookDelivery}	"Delivery attempts"
+//	@Failure		401	{object}	httputil.APIResponse								"Unauthorized"
+//	@Failure		403	{object}	httputil.APIResponse								"Missing scope or participant mismatch"
+//	@Failure		404	{object}	httputil.APIResponse								"Webhook not found"
+//	@Failure		500	{object}	httputil.APIResponse								"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/webhooks/{id}/deliveries [get]
+func (h *Handler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		httputil.WriteAPIError(w, r, constants.ErrWebhookNotFound)
+		return
+	}
+
+	webhook, err := h.webhookRepo.FindByID(ctx, id)
+	if err != nil {
+		httputil.WriteError(w, r, err, constants.ErrFailedToFindWebhooks)
+		return
+	}
+
+	if webhook == nil {
+		httputil.WriteAPIError(w, r, constants.ErrWebhookNotFound)
+		return
+	}
+	if !middleware.AuthorizeParticipant(w, r, webhook.Participant) {
+		return
+	}
+
+	deliveries, err := h.deliveryRepo.FindByWebhookID(ctx, id)
+	if err != nil {
+		httputil.WriteError(w, r, err, constants.ErrFailedToFindDeliveries)
+		return
+	}
+
+	httputil.WriteAPISuccess(w, r, constants.SuccessWebhookDeliveriesFound, deliveries)
+}
+
+// generateSecret creates a random signing secret
+func generateSecret() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
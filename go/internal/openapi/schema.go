@@ -0,0 +1,220 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema is the subset of a Swagger 2.0 schema object that swag generates
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Enum                 []any              `json:"enum"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*Schema `json:"properties"`
+	AdditionalProperties *Schema            `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	AllOf                []*Schema          `json:"allOf"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	MinItems             *int               `json:"minItems"`
+	MaxItems             *int               `json:"maxItems"`
+}
+
+// shape is a schema with its $ref and allOf members flattened
+type shape struct {
+	nodes      []*Schema
+	properties map[string][]*Schema
+	required   []string
+	additional *Schema
+	open       bool
+}
+
+// flatten collects every schema that applies to a value
+func (s *Spec) flatten(schema *Schema, sh *shape, depth int) {
+	if schema == nil || depth > 32 {
+		return
+	}
+	if schema.Ref != "" {
+		s.flatten(s.Definitions[strings.TrimPrefix(schema.Ref, "#/definitions/")], sh, depth+1)
+		return
+	}
+
+	sh.nodes = append(sh.nodes, schema)
+	for name, prop := range schema.Properties {
+		sh.properties[name] = append(sh.properties[name], prop)
+	}
+	sh.required = append(sh.required, schema.Required...)
+	if schema.AdditionalProperties != nil {
+		sh.additional = schema.AdditionalProperties
+	}
+	for _, sub := range schema.AllOf {
+		s.flatten(sub, sh, depth+1)
+	}
+}
+
+// validate checks value against schema, returning one message per violation
+// Null is accepted for any value: Go encodes nil slices, maps and pointers as null.
+// In strict mode (responses) properties missing from the document are violations,
+// since the document is generated from the same structs the handlers encode.
+func (s *Spec) validate(schema *Schema, value any, path string, strict bool) []string {
+	if value == nil {
+		return nil
+	}
+
+	sh := &shape{properties: map[string][]*Schema{}}
+	s.flatten(schema, sh, 0)
+	if len(sh.nodes) == 0 {
+		return nil
+	}
+
+	var violations []string
+	fail := func(format string, args ...any) {
+		violations = append(violations, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	for _, node := range sh.nodes {
+		if node.Type != "" && !hasType(value, node.Type) {
+			fail("expected %s, got %s", node.Type, typeOf(value))
+			return violations
+		}
+		if len(node.Enum) > 0 && !inEnum(value, node.Enum) {
+			fail("%v is not one of %v", value, node.Enum)
+		}
+		checkBounds(node, value, fail)
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for _, name := range sh.required {
+			if v[name] == nil {
+				fail("missing required property %q", name)
+			}
+		}
+		for _, name := range sortedKeys(v) {
+			props, ok := sh.properties[name]
+			switch {
+			case ok:
+				for _, prop := range props {
+					violations = append(violations, s.validate(prop, v[name], path+"."+name, strict)...)
+				}
+			case sh.additional != nil:
+				violations = append(violations, s.validate(sh.additional, v[name], path+"."+name, strict)...)
+			case strict && len(sh.properties) > 0:
+				fail("property %q is not documented", name)
+			}
+		}
+	case []any:
+		for _, node := range sh.nodes {
+			if node.Items == nil {
+				continue
+			}
+			for i, item := range v {
+				violations = append(violations, s.validate(node.Items, item, fmt.Sprintf("%s[%d]", path, i), strict)...)
+			}
+		}
+	}
+
+	return violations
+}
+
+// checkBounds applies the numeric, length and item count limits of a single schema
+func checkBounds(node *Schema, value any, fail func(string, ...any)) {
+	switch v := value.(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		if node.Minimum != nil && f < *node.Minimum {
+			fail("%v is less than the minimum %v", v, *node.Minimum)
+		}
+		if node.Maximum != nil && f > *node.Maximum {
+			fail("%v is greater than the maximum %v", v, *node.Maximum)
+		}
+	case string:
+		n := utf8.RuneCountInString(v)
+		if node.MinLength != nil && n < *node.MinLength {
+			fail("length %d is less than the minimum %d", n, *node.MinLength)
+		}
+		if node.MaxLength != nil && n > *node.MaxLength {
+			fail("length %d is greater than the maximum %d", n, *node.MaxLength)
+		}
+	case []any:
+		if node.MinItems != nil && len(v) < *node.MinItems {
+			fail("%d items is less than the minimum %d", len(v), *node.MinItems)
+		}
+		if node.MaxItems != nil && len(v) > *node.MaxItems {
+			fail("%d items is greater than the maximum %d", len(v), *node.MaxItems)
+		}
+	}
+}
+
+// hasType reports whether a decoded JSON value matches a schema type
+func hasType(value any, typ string) bool {
+	switch typ {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	}
+	return true
+}
+
+// typeOf names the JSON type of a decoded value for error messages
+func typeOf(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	}
+	return "null"
+}
+
+// inEnum compares a decoded value against enum members, which decode as float64 for numbers
+func inEnum(value any, enum []any) bool {
+	if n, ok := value.(json.Number); ok {
+		f, _ := n.Float64()
+		value = f
+	}
+	return slices.Contains(enum, value)
+}
+
+// sortedKeys returns map keys in order so violations are reported deterministically
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,188 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Spec is the subset of a Swagger 2.0 (OpenAPI 2) document needed to validate traffic
+type Spec struct {
+	Paths       map[string]map[string]*Operation `json:"paths"`
+	Definitions map[string]*Schema               `json:"definitions"`
+}
+
+// Operation is a single method on a documented path
+type Operation struct {
+	Produces   []string            `json:"produces"`
+	Parameters []Parameter         `json:"parameters"`
+	Responses  map[string]Response `json:"responses"`
+}
+
+// Parameter describes a path, query, header, formData or body parameter
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Type     string  `json:"type"`
+	Schema   *Schema `json:"schema"`
+}
+
+// Response describes the body returned with one status code
+type Response struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Load parses a Swagger 2.0 JSON document
+func Load(doc []byte) (*Spec, error) {
+	var spec Spec
+	if err := json.Unmarshal(doc, &spec); err != nil {
+		return nil, fmt.Errorf("parse openapi document: %w", err)
+	}
+	return &spec, nil
+}
+
+// Operation returns the operation documented for a ServeMux pattern such as "GET /entries/{key}"
+// Path templates use the same {name} syntax as ServeMux, so patterns map onto paths directly.
+func (s *Spec) Operation(pattern string) (*Operation, bool) {
+	method, path, ok := strings.Cut(pattern, " ")
+	if !ok {
+		return nil, false
+	}
+	op, ok := s.Paths[path][strings.ToLower(method)]
+	return op, ok
+}
+
+// ValidateRequest checks the parameters and body of a request against the operation
+// body is the raw request body, since r.Body has usually been consumed by the handler.
+// Only JSON bodies are checked against the schema (e.g. /admin/import also takes NDJSON);
+// a body sent without a Content-Type is taken as JSON.
+// formData parameters are read from URL-encoded bodies.
+func (s *Spec) ValidateRequest(op *Operation, r *http.Request, body []byte) []string {
+	var violations []string
+	var form url.Values
+
+	for _, p := range op.Parameters {
+		if p.In == "body" {
+			if len(bytes.TrimSpace(body)) == 0 {
+				if p.Required {
+					violations = append(violations, "request body is required")
+				}
+				continue
+			}
+			if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "" && mediaType != "application/json" {
+				continue
+			}
+			value, err := decode(body)
+			if err != nil {
+				violations = append(violations, "request body is not valid JSON")
+				continue
+			}
+			violations = append(violations, s.validate(p.Schema, value, "body", false)...)
+			continue
+		}
+
+		var value string
+		var present bool
+		switch p.In {
+		case "path":
+			value = r.PathValue(p.Name)
+			present = value != ""
+		case "query":
+			present = r.URL.Query().Has(p.Name)
+			value = r.URL.Query().Get(p.Name)
+		case "header":
+			value = r.Header.Get(p.Name)
+			present = value != ""
+		case "formData":
+			if form == nil {
+				form = parseForm(r, body)
+			}
+			present = form.Has(p.Name)
+			value = form.Get(p.Name)
+		}
+
+		where := p.In + " parameter " + p.Name
+		if !present {
+			if p.Required {
+				violations = append(violations, where+" is required")
+			}
+			continue
+		}
+		if msg := checkParamType(p.Type, value); msg != "" {
+			violations = append(violations, where+": "+msg)
+		}
+	}
+
+	return violations
+}
+
+// ValidateResponse checks a response status and body against the operation
+// Undocumented 5xx statuses are accepted: fault injection and outages can produce them on any route.
+func (s *Spec) ValidateResponse(op *Operation, status int, contentType string, body []byte) []string {
+	resp, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		if status >= 500 {
+			return nil
+		}
+		return []string{fmt.Sprintf("response status %d is not documented", status)}
+	}
+
+	// Only JSON bodies are validated (e.g. /metrics documents a text/plain string)
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if resp.Schema == nil || mediaType != "application/json" {
+		return nil
+	}
+
+	value, err := decode(body)
+	if err != nil {
+		return []string{"response body is not valid JSON"}
+	}
+	return s.validate(resp.Schema, value, "response", true)
+}
+
+// parseForm decodes a URL-encoded body; any other body has no form fields
+func parseForm(r *http.Request, body []byte) url.Values {
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/x-www-form-urlencoded" {
+		return url.Values{}
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return url.Values{}
+	}
+	return form
+}
+
+// checkParamType checks a non-body parameter value against its declared primitive type
+func checkParamType(typ, value string) string {
+	var err error
+	switch typ {
+	case "integer":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "number":
+		_, err = strconv.ParseFloat(value, 64)
+	case "boolean":
+		_, err = strconv.ParseBool(value)
+	}
+	if err != nil {
+		return fmt.Sprintf("%q is not a valid %s", value, typ)
+	}
+	return ""
+}
+
+// decode parses JSON keeping numbers as json.Number so integers can be told apart
+func decode(body []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
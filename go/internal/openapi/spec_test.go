@@ -0,0 +1,247 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testDoc = `{
+	"swagger": "2.0",
+	"paths": {
+		"/entries": {
+			"post": {
+				"parameters": [
+					{"type": "string", "name": "X-Idempotency-Key", "in": "header"},
+					{"name": "request", "in": "body", "required": true, "schema": {"$ref": "#/definitions/CreateEntryRequest"}}
+				],
+				"responses": {
+					"201": {"schema": {"allOf": [
+						{"$ref": "#/definitions/APIResponse"},
+						{"type": "object", "properties": {"data": {"$ref": "#/definitions/Entry"}}}
+					]}},
+					"400": {"schema": {"$ref": "#/definitions/APIResponse"}}
+				}
+			}
+		},
+		"/webhooks": {
+			"get": {
+				"parameters": [{"type": "string", "name": "participant", "in": "query", "required": true}],
+				"responses": {
+					"200": {"schema": {"allOf": [
+						{"$ref": "#/definitions/APIResponse"},
+						{"type": "object", "properties": {"data": {"type": "array", "items": {"$ref": "#/definitions/Entry"}}}}
+					]}}
+				}
+			}
+		},
+		"/oauth/token": {
+			"post": {
+				"consumes": ["application/x-www-form-urlencoded"],
+				"parameters": [
+					{"type": "string", "name": "grant_type", "in": "formData", "required": true},
+					{"type": "string", "name": "scope", "in": "formData"}
+				],
+				"responses": {"200": {"schema": {"type": "object"}}}
+			}
+		},
+		"/metrics": {
+			"get": {
+				"produces": ["text/plain"],
+				"responses": {"200": {"schema": {"type": "string"}}}
+			}
+		}
+	},
+	"definitions": {
+		"APIResponse": {
+			"type": "object",
+			"properties": {
+				"code": {"type": "string"},
+				"data": {},
+				"error": {"type": "string"}
+			}
+		},
+		"KeyType": {"type": "string", "enum": ["CPF", "EMAIL"]},
+		"CreateEntryRequest": {
+			"type": "object",
+			"required": ["key", "keyType"],
+			"properties": {
+				"key": {"type": "string", "maxLength": 5},
+				"keyType": {"$ref": "#/definitions/KeyType"},
+				"branch": {"type": "integer", "minimum": 1}
+			}
+		},
+		"Entry": {
+			"type": "object",
+			"properties": {
+				"key": {"type": "string"},
+				"keyType": {"$ref": "#/definitions/KeyType"},
+				"tags": {"type": "array", "items": {"type": "string"}}
+			}
+		}
+	}
+}`
+
+func loadTestSpec(t *testing.T) *Spec {
+	t.Helper()
+	spec, err := Load([]byte(testDoc))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return spec
+}
+
+func TestSpecOperation(t *testing.T) {
+	spec := loadTestSpec(t)
+
+	if _, ok := spec.Operation("POST /entries"); !ok {
+		t.Error("Operation(POST /entries) not found")
+	}
+	if _, ok := spec.Operation("DELETE /entries"); ok {
+		t.Error("Operation(DELETE /entries) found, want undocumented")
+	}
+	if _, ok := spec.Operation("/entries"); ok {
+		t.Error("Operation(/entries) without method found")
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	spec := loadTestSpec(t)
+	op, _ := spec.Operation("POST /entries")
+
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{name: "valid", body: `{"key":"abc","keyType":"CPF","branch":2}`},
+		{name: "unknown properties allowed", body: `{"key":"abc","keyType":"CPF","extra":true}`},
+		{name: "missing body", body: "", want: []string{"request body is required"}},
+		{name: "invalid JSON", body: `{"key":`, want: []string{"request body is not valid JSON"}},
+		{
+			name: "missing required",
+			body: `{"key":"abc"}`,
+			want: []string{`body: missing required property "keyType"`},
+		},
+		{
+			name: "enum, length and minimum",
+			body: `{"key":"abcdef","keyType":"PHONE","branch":0}`,
+			want: []string{
+				"body.branch: 0 is less than the minimum 1",
+				"body.key: length 6 is greater than the maximum 5",
+				"body.keyType: PHONE is not one of [CPF EMAIL]",
+			},
+		},
+		{
+			name: "wrong types",
+			body: `{"key":1,"keyType":"CPF","branch":1.5}`,
+			want: []string{
+				"body.branch: expected integer, got number",
+				"body.key: expected string, got number",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/entries", strings.NewReader(tt.body))
+			got := spec.ValidateRequest(op, r, []byte(tt.body))
+			assertViolations(t, got, tt.want)
+		})
+	}
+}
+
+func TestValidateRequest_QueryParameter(t *testing.T) {
+	spec := loadTestSpec(t)
+	op, _ := spec.Operation("GET /webhooks")
+
+	r := httptest.NewRequest(http.MethodGet, "/webhooks", nil)
+	assertViolations(t, spec.ValidateRequest(op, r, nil), []string{"query parameter participant is required"})
+
+	r = httptest.NewRequest(http.MethodGet, "/webhooks?participant=12345678", nil)
+	assertViolations(t, spec.ValidateRequest(op, r, nil), nil)
+}
+
+func TestValidateRequest_FormParameter(t *testing.T) {
+	spec := loadTestSpec(t)
+	op, _ := spec.Operation("POST /oauth/token")
+
+	body := "scope=entries%3Aread"
+	r := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	assertViolations(t, spec.ValidateRequest(op, r, []byte(body)), []string{"formData parameter grant_type is required"})
+
+	body = "grant_type=client_credentials&scope=entries%3Aread"
+	assertViolations(t, spec.ValidateRequest(op, r, []byte(body)), nil)
+
+	// Fields are only read from form bodies
+	r.Header.Set("Content-Type", "application/json")
+	assertViolations(t, spec.ValidateRequest(op, r, []byte(body)), []string{"formData parameter grant_type is required"})
+}
+
+func TestValidateRequest_SkipsNonJSON(t *testing.T) {
+	spec := loadTestSpec(t)
+	op, _ := spec.Operation("POST /entries")
+
+	body := "{\"key\":\"abc\"}\n{\"key\":\"def\"}\n"
+	r := httptest.NewRequest(http.MethodPost, "/entries", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-ndjson")
+	assertViolations(t, spec.ValidateRequest(op, r, []byte(body)), nil)
+
+	r.Header.Set("Content-Type", "application/json; charset=utf-8")
+	assertViolations(t, spec.ValidateRequest(op, r, []byte(body)), []string{`body: missing required property "keyType"`})
+}
+
+func TestValidateResponse(t *testing.T) {
+	spec := loadTestSpec(t)
+	op, _ := spec.Operation("POST /entries")
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   []string
+	}{
+		{name: "valid", status: 201, body: `{"code":"ENTRY_CREATED","data":{"key":"abc","keyType":"CPF","tags":["a"]}}`},
+		{name: "null collections", status: 201, body: `{"data":{"key":"abc","tags":null}}`},
+		{name: "documented error", status: 400, body: `{"error":"INVALID_REQUEST"}`},
+		{name: "undocumented 5xx", status: 503, body: `{"error":"SERVICE_UNAVAILABLE"}`},
+		{name: "undocumented status", status: 404, body: `{}`, want: []string{"response status 404 is not documented"}},
+		{
+			name:   "undocumented property",
+			status: 201,
+			body:   `{"data":{"key":"abc","internalId":"x"}}`,
+			want:   []string{`response.data: property "internalId" is not documented`},
+		},
+		{
+			name:   "nested type mismatch",
+			status: 201,
+			body:   `{"data":{"key":"abc","tags":[1]}}`,
+			want:   []string{"response.data.tags[0]: expected string, got number"},
+		},
+		{name: "not JSON", status: 201, body: `oops`, want: []string{"response body is not valid JSON"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := spec.ValidateResponse(op, tt.status, "application/json", []byte(tt.body))
+			assertViolations(t, got, tt.want)
+		})
+	}
+}
+
+func TestValidateResponse_SkipsNonJSON(t *testing.T) {
+	spec := loadTestSpec(t)
+	op, _ := spec.Operation("GET /metrics")
+
+	got := spec.ValidateResponse(op, 200, "text/plain; version=0.0.4", []byte("# HELP up"))
+	assertViolations(t, got, nil)
+}
+
+func assertViolations(t *testing.T, got, want []string) {
+	t.Helper()
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("violations = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,51 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/dict-simulator/go/internal/broker"
+	"github.com/dict-simulator/go/internal/events"
+)
+
+// BusBroker is the relay's destination when the outbox is the event source (EVENT_SOURCE=outbox)
+// Each message goes to the message broker first, when one is configured, and is handed to the event
+// bus (webhooks, metrics, audit) only once the broker has accepted it, so a retry never repeats
+// a bus delivery.
+type BusBroker struct {
+	bus  events.Publisher
+	next broker.Broker // nil without EVENT_BROKER
+}
+
+// NewBusBroker creates a destination publishing to bus, after next when it is not nil
+func NewBusBroker(bus events.Publisher, next broker.Broker) *BusBroker {
+	return &BusBroker{
+		bus:  bus,
+		next: next,
+	}
+}
+
+// Publish decodes the event in msg and publishes it
+func (b *BusBroker) Publish(ctx context.Context, msg broker.Message) error {
+	var event events.Event
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		return err
+	}
+
+	if b.next != nil {
+		if err := b.next.Publish(ctx, msg); err != nil {
+			return err
+		}
+	}
+
+	b.bus.Publish(ctx, event)
+	return nil
+}
+
+// Close closes the message broker, if any
+func (b *BusBroker) Close() error {
+	if b.next == nil {
+		return nil
+	}
+	return b.next.Close()
+}
@@ -0,0 +1,131 @@
+package outbox
+
+import (
+	"context"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/db"
+	"github.com/dict-simulator/go/internal/events"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// EntryRepository writes each entry change and its event to the outbox in one MongoDB transaction
+// (EVENT_SOURCE=outbox), so a committed change always has an event and a rolled back one never does.
+// The relay publishes the events afterwards; handlers publish nothing themselves.
+type EntryRepository struct {
+	models.EntryRepository
+	mongo  *db.Mongo
+	outbox models.OutboxRepository
+	clock  clock.Clock
+}
+
+// NewEntryRepository wraps repo, which must store entries in mongo, with outbox writes
+func NewEntryRepository(repo models.EntryRepository, mongo *db.Mongo, outbox models.OutboxRepository, clk clock.Clock) *EntryRepository {
+	return &EntryRepository{
+		EntryRepository: repo,
+		mongo:           mongo,
+		outbox:          outbox,
+		clock:           clk,
+	}
+}
+
+// enqueue writes the event to the outbox with the trace of the request that caused it
+func (r *EntryRepository) enqueue(ctx context.Context, event events.Event) error {
+	return r.outbox.Enqueue(ctx, events.WithTrace(ctx, event))
+}
+
+// Create creates the entry and enqueues its ENTRY_CREATED event
+func (r *EntryRepository) Create(ctx context.Context, req *models.CreateEntryRequest) (*models.Entry, error) {
+	var entry *models.Entry
+	err := r.mongo.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		entry, err = r.EntryRepository.Create(ctx, req)
+		if err != nil {
+			return err
+		}
+		return r.enqueue(ctx, events.New(events.EntryCreated, entry.Account.Participant, entry.ToResponse(), entry.CreatedAt))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// DeleteByKeyAndParticipant deletes the entry and enqueues its ENTRY_DELETED event
+func (r *EntryRepository) DeleteByKeyAndParticipant(ctx context.Context, key string, participant string) (*models.Entry, error) {
+	var entry *models.Entry
+	err := r.mongo.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		entry, err = r.EntryRepository.DeleteByKeyAndParticipant(ctx, key, participant)
+		if err != nil || entry == nil {
+			return err
+		}
+		return r.enqueue(ctx, events.New(events.EntryDeleted, entry.Account.Participant, entry.DeletedResponse(), r.clock.Now()))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// UpdateByKeyAndParticipant updates the entry and enqueues its ENTRY_UPDATED event
+func (r *EntryRepository) UpdateByKeyAndParticipant(ctx context.Context, key string, participant string, req *models.UpdateEntryRequest) (*models.Entry, error) {
+	var entry *models.Entry
+	err := r.mongo.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		entry, err = r.EntryRepository.UpdateByKeyAndParticipant(ctx, key, participant, req)
+		if err != nil || entry == nil {
+			return err
+		}
+		return r.enqueue(ctx, events.New(events.EntryUpdated, entry.Account.Participant, entry.ToResponse(), entry.UpdatedAt))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// DeleteByAccount deletes the account's entries and enqueues an ENTRY_DELETED event for each
+func (r *EntryRepository) DeleteByAccount(ctx context.Context, participant, branch, accountNumber string) ([]models.Entry, error) {
+	var entries []models.Entry
+	err := r.mongo.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		entries, err = r.EntryRepository.DeleteByAccount(ctx, participant, branch, accountNumber)
+		if err != nil {
+			return err
+		}
+		now := r.clock.Now()
+		for i := range entries {
+			if err := r.enqueue(ctx, events.New(events.EntryDeleted, entries[i].Account.Participant, entries[i].DeletedResponse(), now)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// TransferAccount moves the account's entries and enqueues an ENTRY_UPDATED event for each
+func (r *EntryRepository) TransferAccount(ctx context.Context, participant, branch, accountNumber string, to models.Account) ([]models.Entry, error) {
+	var entries []models.Entry
+	err := r.mongo.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		entries, err = r.EntryRepository.TransferAccount(ctx, participant, branch, accountNumber, to)
+		if err != nil {
+			return err
+		}
+		for i := range entries {
+			if err := r.enqueue(ctx, events.New(events.EntryUpdated, entries[i].Account.Participant, entries[i].ToResponse(), entries[i].UpdatedAt)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
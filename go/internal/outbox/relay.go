@@ -0,0 +1,176 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/dict-simulator/go/internal/broker"
+	"github.com/dict-simulator/go/internal/events"
+	"github.com/dict-simulator/go/internal/logger"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+var publishedTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "event_outbox_publish_total",
+		Help: "Total number of outbox publish attempts by result",
+	},
+	[]string{"result"},
+)
+
+// Config controls how the relay drains the outbox
+type Config struct {
+	Topic        string        // Kafka topic; NATS subjects are <Topic>.<event type>
+	Broker       string        // broker.KindNATS or broker.KindKafka, decides how Topic is used
+	PollInterval time.Duration // How often pending messages are looked up
+	BatchSize    int64
+	MaxBackoff   time.Duration // Upper bound for the wait after repeated failures
+	MaxAttempts  int           // Failed attempts before a message is dead-lettered; 0 retries forever
+}
+
+// Relay moves events from the outbox collection to the message broker.
+// Events are first written to the outbox (Enqueue, subscribed to the event bus), then published
+// in creation order by Run. A message is only marked published once the broker accepts it, so a
+// crash or broker outage delays events but never drops them (at-least-once; consumers
+// deduplicate on the event ID). With MaxAttempts set, a message that keeps failing is
+// dead-lettered instead, so it stops holding back the events queued after it.
+type Relay struct {
+	outbox models.OutboxRepository
+	broker broker.Broker
+	cfg    Config
+}
+
+// NewRelay creates a relay publishing to b
+func NewRelay(outbox models.OutboxRepository, b broker.Broker, cfg Config) *Relay {
+	return &Relay{
+		outbox: outbox,
+		broker: b,
+		cfg:    cfg,
+	}
+}
+
+// Enqueue writes the event to the outbox; it is an event bus subscriber
+func (r *Relay) Enqueue(ctx context.Context, event events.Event) {
+	if err := r.outbox.Enqueue(context.WithoutCancel(ctx), event); err != nil {
+		logger.Error("Failed to enqueue event in outbox",
+			zap.String("eventId", event.ID),
+			zap.String("eventType", string(event.Type)),
+			zap.Error(err),
+		)
+	}
+}
+
+// Run publishes due outbox messages until ctx is cancelled
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain publishes due messages batch by batch until none are left or one fails
+func (r *Relay) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		messages, err := r.outbox.FindDue(ctx, time.Now().UTC(), r.cfg.BatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Error("Failed to read outbox", zap.Error(err))
+			}
+			return
+		}
+
+		for i := range messages {
+			// Stop at the first failure so later events don't overtake it
+			if !r.publish(ctx, &messages[i]) {
+				return
+			}
+		}
+
+		if int64(len(messages)) < r.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// publish sends one message and records the outcome, reporting whether it was accepted
+func (r *Relay) publish(ctx context.Context, message *models.OutboxMessage) bool {
+	err := r.broker.Publish(ctx, broker.Message{
+		Topic: r.topic(message.EventType),
+		Key:   message.Participant,
+		Value: []byte(message.Payload),
+	})
+	if err != nil {
+		if r.cfg.MaxAttempts > 0 && message.Attempts+1 >= r.cfg.MaxAttempts {
+			return r.deadLetter(ctx, message, err)
+		}
+
+		publishedTotal.WithLabelValues("error").Inc()
+		logger.Warn("Failed to publish outbox message",
+			zap.String("eventId", message.EventID),
+			zap.Int("attempts", message.Attempts+1),
+			zap.Error(err),
+		)
+
+		next := time.Now().UTC().Add(r.backoff(message.Attempts + 1))
+		if err := r.outbox.MarkFailed(context.WithoutCancel(ctx), message.ID, err, next); err != nil {
+			logger.Error("Failed to record outbox failure", zap.String("eventId", message.EventID), zap.Error(err))
+		}
+		return false
+	}
+
+	publishedTotal.WithLabelValues("success").Inc()
+	if err := r.outbox.MarkPublished(context.WithoutCancel(ctx), message.ID, time.Now().UTC()); err != nil {
+		// The message stays pending and is published again on the next poll
+		logger.Error("Failed to mark outbox message published", zap.String("eventId", message.EventID), zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// deadLetter gives up on a message, reporting whether the relay can move on to the next one
+func (r *Relay) deadLetter(ctx context.Context, message *models.OutboxMessage, cause error) bool {
+	publishedTotal.WithLabelValues("dead_letter").Inc()
+	logger.Error("Dead-lettering outbox message",
+		zap.String("eventId", message.EventID),
+		zap.String("eventType", string(message.EventType)),
+		zap.Int("attempts", message.Attempts+1),
+		zap.Error(cause),
+	)
+
+	if err := r.outbox.MarkDeadLettered(context.WithoutCancel(ctx), message.ID, cause, time.Now().UTC()); err != nil {
+		logger.Error("Failed to dead-letter outbox message", zap.String("eventId", message.EventID), zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// topic returns where an event type is published
+// Kafka uses one topic keyed by participant; NATS subjects carry the type so consumers can filter
+func (r *Relay) topic(eventType events.Type) string {
+	if r.cfg.Broker == broker.KindNATS {
+		return r.cfg.Topic + "." + string(eventType)
+	}
+	return r.cfg.Topic
+}
+
+// backoff returns the wait before retrying after the given number of failed attempts
+// Doubles from PollInterval up to MaxBackoff
+func (r *Relay) backoff(attempts int) time.Duration {
+	wait := r.cfg.PollInterval
+	for i := 1; i < attempts && wait < r.cfg.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, r.cfg.MaxBackoff)
+}
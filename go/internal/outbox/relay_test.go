@@ -0,0 +1,77 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dict-simulator/go/internal/broker"
+	"github.com/dict-simulator/go/internal/events"
+)
+
+func TestRelayBackoff(t *testing.T) {
+	r := &Relay{cfg: Config{PollInterval: time.Second, MaxBackoff: 10 * time.Second}}
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 4, want: 8 * time.Second},
+		{attempts: 5, want: 10 * time.Second},
+		{attempts: 50, want: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := r.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestRelayTopic(t *testing.T) {
+	nats := &Relay{cfg: Config{Broker: broker.KindNATS, Topic: "dict.events"}}
+	if got := nats.topic(events.EntryCreated); got != "dict.events.ENTRY_CREATED" {
+		t.Errorf("NATS topic = %q, want dict.events.ENTRY_CREATED", got)
+	}
+
+	kafka := &Relay{cfg: Config{Broker: broker.KindKafka, Topic: "dict.events"}}
+	if got := kafka.topic(events.EntryCreated); got != "dict.events" {
+		t.Errorf("Kafka topic = %q, want dict.events", got)
+	}
+}
+
+// failingBroker rejects every message
+type failingBroker struct{}
+
+func (failingBroker) Publish(context.Context, broker.Message) error {
+	return errors.New("broker unavailable")
+}
+func (failingBroker) Close() error { return nil }
+
+func TestBusBrokerPublishesAfterNextBroker(t *testing.T) {
+	bus := events.NewBus()
+	var got []string
+	bus.Subscribe("test", func(_ context.Context, e events.Event) { got = append(got, e.ID) })
+
+	event := events.New(events.EntryCreated, "12345678", nil, time.Now())
+	payload, _ := json.Marshal(event)
+	msg := broker.Message{Topic: "dict.events", Key: "12345678", Value: payload}
+
+	if err := NewBusBroker(bus, failingBroker{}).Publish(context.Background(), msg); err == nil {
+		t.Fatalf("Publish succeeded although the broker rejected the message")
+	}
+	if len(got) != 0 {
+		t.Fatalf("bus received %v before the broker accepted the message", got)
+	}
+
+	if err := NewBusBroker(bus, nil).Publish(context.Background(), msg); err != nil {
+		t.Fatalf("Publish without a broker: %v", err)
+	}
+	if len(got) != 1 || got[0] != event.ID {
+		t.Errorf("bus received %v, want [%s]", got, event.ID)
+	}
+}
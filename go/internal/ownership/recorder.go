@@ -0,0 +1,97 @@
+package ownership
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/dict-simulator/go/internal/events"
+	"github.com/dict-simulator/go/internal/logger"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// Recorder keeps the ownership history of keys, following the entry events on the bus
+// Whichever source feeds the bus (handlers, the outbox or the change stream), each registration,
+// deletion and change of participant or owner is seen once it is published. Events are seen at
+// least once, so a repeated one changes nothing. Changes made without an event, such as a
+// participant reset, don't show.
+type Recorder struct {
+	repo models.KeyOwnershipRepository
+}
+
+// NewRecorder creates a recorder storing periods in repo
+func NewRecorder(repo models.KeyOwnershipRepository) *Recorder {
+	return &Recorder{repo: repo}
+}
+
+// Record is a subscriber that opens and closes periods on ENTRY_CREATED, ENTRY_UPDATED and ENTRY_DELETED
+// Failures are logged: the history misses the change, but the event still reaches the other subscribers.
+func (r *Recorder) Record(ctx context.Context, event events.Event) {
+	switch event.Type {
+	case events.EntryCreated, events.EntryUpdated, events.EntryDeleted:
+	default:
+		return
+	}
+
+	entry, err := entryOf(event)
+	if err == nil {
+		err = r.record(ctx, event.Type, entry, event.OccurredAt)
+	}
+	if err != nil {
+		logger.Error("Failed to record key ownership",
+			zap.String("eventId", event.ID),
+			zap.String("eventType", string(event.Type)),
+			zap.Error(err),
+		)
+	}
+}
+
+// record applies one entry event to the key's history
+func (r *Recorder) record(ctx context.Context, eventType events.Type, entry models.EntryResponse, at time.Time) error {
+	current, err := r.repo.FindCurrent(ctx, entry.Key)
+	if err != nil {
+		return err
+	}
+
+	if eventType == events.EntryDeleted {
+		if current == nil {
+			return nil
+		}
+		return r.repo.Close(ctx, entry.Key, at)
+	}
+
+	// A key registered before the history was kept (or seeded) starts when its owner took it
+	from := entry.KeyOwnershipDate
+	if current != nil {
+		// Account and name changes keep the period: it is the same participant holding the key for the same owner
+		if current.Participant == entry.Account.Participant && current.Owner.TaxIdNumber == entry.Owner.TaxIdNumber {
+			return nil
+		}
+		if err := r.repo.Close(ctx, entry.Key, at); err != nil {
+			return err
+		}
+		from = at
+	}
+	if from.IsZero() {
+		from = at
+	}
+	return r.repo.Open(ctx, models.NewKeyOwnership(entry, from))
+}
+
+// entryOf returns the entry an event is about
+// Events relayed from the outbox were decoded from JSON, so their data is read back from it.
+func entryOf(event events.Event) (models.EntryResponse, error) {
+	if entry, ok := event.Data.(models.EntryResponse); ok {
+		return entry, nil
+	}
+
+	var entry models.EntryResponse
+	raw, err := json.Marshal(event.Data)
+	if err != nil {
+		return entry, err
+	}
+	err = json.Unmarshal(raw, &entry)
+	return entry, err
+}
@@ -0,0 +1,74 @@
+package ownership
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dict-simulator/go/internal/events"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+	repo := models.NewMemoryKeyOwnershipRepository()
+	recorder := NewRecorder(repo)
+
+	created := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	entry := models.EntryResponse{
+		Key:              "+5511987654321",
+		KeyType:          models.KeyTypePHONE,
+		Account:          models.Account{Participant: "12345678", Branch: "0001", AccountNumber: "123456"},
+		Owner:            models.Owner{Type: models.OwnerTypeNaturalPerson, TaxIdNumber: "52998224725", Name: "John Doe"},
+		KeyOwnershipDate: created,
+	}
+	publish := func(eventType events.Type, entry models.EntryResponse, at time.Time) {
+		recorder.Record(ctx, events.New(eventType, entry.Account.Participant, entry, at))
+	}
+
+	publish(events.EntryCreated, entry, created)
+	publish(events.EntryCreated, entry, created) // redelivered
+
+	// A branch transfer keeps the period
+	moved := entry
+	moved.Account.Branch = "0002"
+	publish(events.EntryUpdated, moved, created.Add(time.Hour))
+
+	// Outbox events carry their data decoded from JSON
+	portedAt := created.Add(24 * time.Hour)
+	ported := moved
+	ported.Account.Participant = "87654321"
+	raw, _ := json.Marshal(events.New(events.EntryUpdated, ported.Account.Participant, ported, portedAt))
+	var relayed events.Event
+	if err := json.Unmarshal(raw, &relayed); err != nil {
+		t.Fatal(err)
+	}
+	recorder.Record(ctx, relayed)
+
+	deletedAt := created.Add(48 * time.Hour)
+	publish(events.EntryDeleted, ported, deletedAt)
+	publish(events.EntryDeleted, ported, deletedAt.Add(time.Hour)) // redelivered
+
+	periods, err := repo.FindByKey(ctx, "+5511987654321")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(periods) != 2 {
+		t.Fatalf("got %d periods, want 2: %+v", len(periods), periods)
+	}
+
+	latest, first := periods[0], periods[1]
+	if latest.Participant != "87654321" || !latest.From.Equal(portedAt) || latest.To == nil || !latest.To.Equal(deletedAt) {
+		t.Errorf("latest period = %+v, want 87654321 from %v to %v", latest, portedAt, deletedAt)
+	}
+	if first.Participant != "12345678" || !first.From.Equal(created) || first.To == nil || !first.To.Equal(portedAt) {
+		t.Errorf("first period = %+v, want 12345678 from %v to %v", first, created, portedAt)
+	}
+
+	// A key registered again starts a new period
+	publish(events.EntryCreated, entry, deletedAt.Add(24*time.Hour))
+	if current, _ := repo.FindCurrent(ctx, entry.Key); current == nil || current.Participant != "12345678" {
+		t.Errorf("FindCurrent() after registering again = %+v, want a period of 12345678", current)
+	}
+}
@@ -0,0 +1,83 @@
+package passwordreset
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pending is a user's reset token and when it expires
+type pending struct {
+	token     string
+	expiresAt time.Time
+}
+
+// MemoryStore keeps reset tokens in process memory (STORAGE=memory)
+// They are per process: a token issued by one replica can't be redeemed on another.
+type MemoryStore struct {
+	mu     sync.Mutex
+	users  map[string]pending // by user ID
+	tokens map[string]string  // user ID by token
+	now    func() time.Time   // wall clock, like a Redis TTL; replaced in tests
+}
+
+// NewMemoryStore creates an in-memory reset token store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		users:  map[string]pending{},
+		tokens: map[string]string{},
+		now:    time.Now,
+	}
+}
+
+// Save makes token the user's reset token until ttl from now, dropping expired tokens on the way
+func (s *MemoryStore) Save(ctx context.Context, userID, token string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for id, p := range s.users {
+		if id == userID || !now.Before(p.expiresAt) {
+			delete(s.tokens, p.token)
+			delete(s.users, id)
+		}
+	}
+
+	s.users[userID] = pending{token: token, expiresAt: now.Add(ttl)}
+	s.tokens[token] = userID
+	return nil
+}
+
+// Pending returns the user's unexpired token and the time left on it
+func (s *MemoryStore) Pending(ctx context.Context, userID string) (string, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.users[userID]
+	if !ok {
+		return "", 0, nil
+	}
+	left := p.expiresAt.Sub(s.now())
+	if left <= 0 {
+		return "", 0, nil
+	}
+	return p.token, left, nil
+}
+
+// Redeem removes the token and returns its user if it has not expired
+func (s *MemoryStore) Redeem(ctx context.Context, token string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userID, ok := s.tokens[token]
+	if !ok {
+		return "", nil
+	}
+	p := s.users[userID]
+	delete(s.tokens, token)
+	delete(s.users, userID)
+	if !s.now().Before(p.expiresAt) {
+		return "", nil
+	}
+	return userID, nil
+}
@@ -0,0 +1,48 @@
+package passwordreset
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	wall := time.Now()
+	s.now = func() time.Time { return wall }
+
+	if token, _, _ := s.Pending(ctx, "alice"); token != "" {
+		t.Fatalf("Pending() = %q before any reset, want none", token)
+	}
+
+	s.Save(ctx, "alice", "first", time.Hour)
+	s.Save(ctx, "alice", "second", time.Hour)
+	wall = wall.Add(15 * time.Minute)
+	if token, left, _ := s.Pending(ctx, "alice"); token != "second" || left != 45*time.Minute {
+		t.Errorf("Pending() = %q, %s, want the latest token with 45m left", token, left)
+	}
+
+	// Saving a token revokes the previous one
+	if userID, _ := s.Redeem(ctx, "first"); userID != "" {
+		t.Errorf("Redeem() of a revoked token = %q, want none", userID)
+	}
+	if userID, _ := s.Redeem(ctx, "second"); userID != "alice" {
+		t.Errorf("Redeem() = %q, want alice", userID)
+	}
+	if userID, _ := s.Redeem(ctx, "second"); userID != "" {
+		t.Errorf("Redeem() of a used token = %q, want none", userID)
+	}
+	if token, _, _ := s.Pending(ctx, "alice"); token != "" {
+		t.Errorf("Pending() = %q after the token was redeemed, want none", token)
+	}
+
+	s.Save(ctx, "bob", "third", time.Hour)
+	wall = wall.Add(time.Hour)
+	if token, _, _ := s.Pending(ctx, "bob"); token != "" {
+		t.Errorf("Pending() = %q after the token expired, want none", token)
+	}
+	if userID, _ := s.Redeem(ctx, "third"); userID != "" {
+		t.Errorf("Redeem() of an expired token = %q, want none", userID)
+	}
+}
@@ -0,0 +1,98 @@
+package passwordreset
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Store keeps pending password reset tokens, at most one per user
+// Implemented by RedisStore and MemoryStore (STORAGE=memory). Tokens expire by the wall clock,
+// like a Redis TTL, so moving the simulated clock does not expire them.
+type Store interface {
+	// Save makes token the user's reset token for ttl, revoking the one it had
+	Save(ctx context.Context, userID, token string, ttl time.Duration) error
+	// Pending returns the user's reset token and how long it stays valid, or "" if it has none
+	Pending(ctx context.Context, userID string) (string, time.Duration, error)
+	// Redeem consumes a token and returns the user it was issued to, or "" if it is unknown or expired
+	Redeem(ctx context.Context, token string) (string, error)
+}
+
+// NewToken generates a random reset token
+func NewToken() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// tokenKey generates the storage key mapping a token to its user
+// Format: password_reset:{token}
+func tokenKey(token string) string {
+	return "password_reset:" + token
+}
+
+// userKey generates the storage key holding a user's pending token
+// Format: password_reset_user:{userID}
+func userKey(userID string) string {
+	return "password_reset_user:" + userID
+}
+
+// RedisStore keeps reset tokens in Redis, shared by every replica
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a reset token store backed by Redis
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Save sets both keys with a TTL of ttl and deletes the previous token's key
+func (s *RedisStore) Save(ctx context.Context, userID, token string, ttl time.Duration) error {
+	previous, err := s.client.SetArgs(ctx, userKey(userID), token, redis.SetArgs{TTL: ttl, Get: true}).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, tokenKey(token), userID, ttl)
+	if previous != "" {
+		pipe.Del(ctx, tokenKey(previous))
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// Pending reads the user's key and its remaining TTL
+func (s *RedisStore) Pending(ctx context.Context, userID string) (string, time.Duration, error) {
+	pipe := s.client.Pipeline()
+	token := pipe.Get(ctx, userKey(userID))
+	ttl := pipe.PTTL(ctx, userKey(userID))
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return "", 0, err
+	}
+	if token.Val() == "" {
+		return "", 0, nil
+	}
+	return token.Val(), max(ttl.Val(), 0), nil
+}
+
+// Redeem deletes the token's key, then the user's
+// Saving a newer token deletes the older one's key, so a token that is found is still the user's.
+func (s *RedisStore) Redeem(ctx context.Context, token string) (string, error) {
+	userID, err := s.client.GetDel(ctx, tokenKey(token)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := s.client.Del(ctx, userKey(userID)).Err(); err != nil {
+		return "", err
+	}
+	return userID, nil
+}
@@ -0,0 +1,252 @@
+package pii
+
+import (
+	"context"
+	"time"
+
+	"github.com/dict-simulator/go/internal/listing"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// Owner fields that are sealed, named as in the stored document
+const (
+	fieldName  = "owner.name"
+	fieldTaxID = "owner.taxIdNumber"
+)
+
+// EntryRepository seals the owner's name and tax ID before they are stored and opens them on the way out,
+// so the layers above only ever see plaintext. It must wrap the storage repository directly: the key
+// filter and outbox wrappers, and the events they publish, work on opened entries.
+type EntryRepository struct {
+	models.EntryRepository
+	keys *Keyring
+}
+
+// NewEntryRepository wraps repo with owner encryption under keys
+func NewEntryRepository(repo models.EntryRepository, keys *Keyring) *EntryRepository {
+	return &EntryRepository{EntryRepository: repo, keys: keys}
+}
+
+// SealOwner returns owner with its name and tax ID sealed, and the blind index of its tax ID
+func (k *Keyring) SealOwner(owner models.Owner) (models.Owner, error) {
+	owner.TaxIdHash = k.BlindIndex(fieldTaxID, owner.TaxIdNumber)
+	var err error
+	if owner.Name, err = k.Seal(fieldName, owner.Name); err != nil {
+		return models.Owner{}, err
+	}
+	if owner.TaxIdNumber, err = k.Seal(fieldTaxID, owner.TaxIdNumber); err != nil {
+		return models.Owner{}, err
+	}
+	return owner, nil
+}
+
+// OpenOwner decrypts the name and tax ID of owner in place and drops the blind index
+func (k *Keyring) OpenOwner(owner *models.Owner) error {
+	name, err := k.Open(fieldName, owner.Name)
+	if err != nil {
+		return err
+	}
+	taxID, err := k.Open(fieldTaxID, owner.TaxIdNumber)
+	if err != nil {
+		return err
+	}
+	owner.Name, owner.TaxIdNumber, owner.TaxIdHash = name, taxID, ""
+	return nil
+}
+
+// openEntry opens the owner of an entry a repository call returned, which may be nil
+func (r *EntryRepository) openEntry(entry *models.Entry, err error) (*models.Entry, error) {
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	if err := r.keys.OpenOwner(&entry.Owner); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// openEntries opens the owner of every entry a repository call returned
+func (r *EntryRepository) openEntries(entries []models.Entry, err error) ([]models.Entry, error) {
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if err := r.keys.OpenOwner(&entries[i].Owner); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// Create seals the owner and creates the entry
+func (r *EntryRepository) Create(ctx context.Context, req *models.CreateEntryRequest) (*models.Entry, error) {
+	sealed := *req
+	owner, err := r.keys.SealOwner(req.Owner)
+	if err != nil {
+		return nil, err
+	}
+	sealed.Owner = owner
+	return r.openEntry(r.EntryRepository.Create(ctx, &sealed))
+}
+
+// CreateMany seals the owners and creates the entries
+func (r *EntryRepository) CreateMany(ctx context.Context, reqs []models.CreateEntryRequest) (int, error) {
+	sealed := make([]models.CreateEntryRequest, len(reqs))
+	for i := range reqs {
+		sealed[i] = reqs[i]
+		owner, err := r.keys.SealOwner(reqs[i].Owner)
+		if err != nil {
+			return 0, err
+		}
+		sealed[i].Owner = owner
+	}
+	return r.EntryRepository.CreateMany(ctx, sealed)
+}
+
+// InsertMany seals the owners and inserts the entries
+func (r *EntryRepository) InsertMany(ctx context.Context, entries []models.Entry) (int, error) {
+	sealed := make([]models.Entry, len(entries))
+	for i := range entries {
+		sealed[i] = entries[i]
+		owner, err := r.keys.SealOwner(entries[i].Owner)
+		if err != nil {
+			return 0, err
+		}
+		sealed[i].Owner = owner
+	}
+	return r.EntryRepository.InsertMany(ctx, sealed)
+}
+
+// FindByKey finds the entry and opens its owner
+func (r *EntryRepository) FindByKey(ctx context.Context, key string) (*models.Entry, error) {
+	return r.openEntry(r.EntryRepository.FindByKey(ctx, key))
+}
+
+// FindByTaxID finds the entries by the blind index of taxID under every key, and returns them opened
+func (r *EntryRepository) FindByTaxID(ctx context.Context, taxID string) ([]models.Entry, error) {
+	found := []models.Entry{}
+	for _, index := range r.keys.BlindIndexes(fieldTaxID, taxID) {
+		entries, err := r.openEntries(r.EntryRepository.FindByTaxID(ctx, index))
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, entries...)
+	}
+	return found, nil
+}
+
+// DeleteByKeyAndParticipant deletes the entry and returns it opened
+func (r *EntryRepository) DeleteByKeyAndParticipant(ctx context.Context, key string, participant string) (*models.Entry, error) {
+	return r.openEntry(r.EntryRepository.DeleteByKeyAndParticipant(ctx, key, participant))
+}
+
+// UpdateByKeyAndParticipant seals a new owner name, updates the entry and returns it opened
+func (r *EntryRepository) UpdateByKeyAndParticipant(ctx context.Context, key string, participant string, req *models.UpdateEntryRequest) (*models.Entry, error) {
+	sealed := *req
+	if req.Owner != nil && req.Owner.Name != "" {
+		name, err := r.keys.Seal(fieldName, req.Owner.Name)
+		if err != nil {
+			return nil, err
+		}
+		sealed.Owner = &models.UpdateOwner{Name: name, TradeName: req.Owner.TradeName}
+	}
+	return r.openEntry(r.EntryRepository.UpdateByKeyAndParticipant(ctx, key, participant, &sealed))
+}
+
+// ForEachEntry calls fn with every entry, opened
+func (r *EntryRepository) ForEachEntry(ctx context.Context, fn func(entry *models.Entry) error) error {
+	return r.EntryRepository.ForEachEntry(ctx, func(entry *models.Entry) error {
+		if err := r.keys.OpenOwner(&entry.Owner); err != nil {
+			return err
+		}
+		return fn(entry)
+	})
+}
+
+// DeleteByAccount deletes the account's entries and returns them opened
+func (r *EntryRepository) DeleteByAccount(ctx context.Context, participant, branch, accountNumber string) ([]models.Entry, error) {
+	return r.openEntries(r.EntryRepository.DeleteByAccount(ctx, participant, branch, accountNumber))
+}
+
+// ListByParticipant lists the participant's entries, opened
+func (r *EntryRepository) ListByParticipant(ctx context.Context, participant string, since time.Time, page listing.Page) ([]models.Entry, error) {
+	return r.openEntries(r.EntryRepository.ListByParticipant(ctx, participant, since, page))
+}
+
+// TransferAccount moves the account's entries and returns them opened
+func (r *EntryRepository) TransferAccount(ctx context.Context, participant, branch, accountNumber string, to models.Account) ([]models.Entry, error) {
+	return r.openEntries(r.EntryRepository.TransferAccount(ctx, participant, branch, accountNumber, to))
+}
+
+// ReviewRFB reviews entries with irregular seeing their owners opened, and returns the flagged ones opened
+// An owner that fails to open is not found irregular.
+func (r *EntryRepository) ReviewRFB(ctx context.Context, reviewedBefore time.Time, irregular func(owner models.Owner) bool) ([]models.Entry, error) {
+	return r.openEntries(r.EntryRepository.ReviewRFB(ctx, reviewedBefore, func(owner models.Owner) bool {
+		if err := r.keys.OpenOwner(&owner); err != nil {
+			return false
+		}
+		return irregular(owner)
+	}))
+}
+
+// RewriteOwner stores owner sealed if the entry's opened owner still is old
+func (r *EntryRepository) RewriteOwner(ctx context.Context, key string, old, owner models.Owner) (bool, error) {
+	stored, err := r.EntryRepository.FindByKey(ctx, key)
+	if err != nil || stored == nil {
+		return false, err
+	}
+	opened := stored.Owner
+	if err := r.keys.OpenOwner(&opened); err != nil {
+		return false, err
+	}
+	if opened.Name != old.Name || opened.TaxIdNumber != old.TaxIdNumber {
+		return false, nil
+	}
+	sealed, err := r.keys.SealOwner(owner)
+	if err != nil {
+		return false, err
+	}
+	return r.EntryRepository.RewriteOwner(ctx, key, stored.Owner, sealed)
+}
+
+// Migrate seals the owners repo stores in plaintext, under a key other than the current one, or without
+// a blind index, and returns how many entries it rewrote. repo must be the storage repository, not an EntryRepository.
+// Entries whose owner changed meanwhile are left for the next run.
+func Migrate(ctx context.Context, repo models.EntryRepository, keys *Keyring) (int, error) {
+	type stale struct {
+		key   string
+		owner models.Owner
+	}
+
+	// Collected first: rewriting while iterating could visit entries twice
+	var pending []stale
+	err := repo.ForEachEntry(ctx, func(entry *models.Entry) error {
+		if !keys.Current(entry.Owner.Name) || !keys.Current(entry.Owner.TaxIdNumber) || entry.Owner.TaxIdHash == "" {
+			pending = append(pending, stale{key: entry.Key, owner: entry.Owner})
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	rewritten := 0
+	for _, p := range pending {
+		opened := p.owner
+		if err := keys.OpenOwner(&opened); err != nil {
+			return rewritten, err
+		}
+		sealed, err := keys.SealOwner(opened)
+		if err != nil {
+			return rewritten, err
+		}
+		ok, err := repo.RewriteOwner(ctx, p.key, p.owner, sealed)
+		if err != nil {
+			return rewritten, err
+		}
+		if ok {
+			rewritten++
+		}
+	}
+	return rewritten, nil
+}
@@ -0,0 +1,129 @@
+package pii
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+const testKey = "52998224725"
+
+// createEntry registers a CPF key for owner through repo
+func createEntry(t *testing.T, repo models.EntryRepository, owner models.Owner) {
+	t.Helper()
+
+	_, err := repo.Create(context.Background(), &models.CreateEntryRequest{
+		Key:     testKey,
+		KeyType: models.KeyTypeCPF,
+		Account: models.Account{Participant: "12345678", Branch: "0001", AccountNumber: "0007654321", AccountType: "CACC"},
+		Owner:   owner,
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+}
+
+func TestEntryRepositorySealsOwners(t *testing.T) {
+	ctx := context.Background()
+	storage := models.NewMemoryEntryRepository(clock.NewSimulated())
+	ring := mustParse(t, newKey(t))
+	repo := NewEntryRepository(storage, ring)
+	owner := models.Owner{Type: models.OwnerTypeNaturalPerson, TaxIdNumber: "52998224725", Name: "Test User"}
+
+	createEntry(t, repo, owner)
+
+	stored, _ := storage.FindByKey(ctx, testKey)
+	if !ring.Current(stored.Owner.Name) || !ring.Current(stored.Owner.TaxIdNumber) {
+		t.Errorf("stored owner = %+v, want the name and tax ID sealed", stored.Owner)
+	}
+
+	entry, err := repo.FindByKey(ctx, testKey)
+	if err != nil {
+		t.Fatalf("FindByKey: %v", err)
+	}
+	if entry.Owner != owner {
+		t.Errorf("owner = %+v, want %+v", entry.Owner, owner)
+	}
+
+	updated, err := repo.UpdateByKeyAndParticipant(ctx, testKey, "12345678", &models.UpdateEntryRequest{
+		Owner:  &models.UpdateOwner{Name: "New Name"},
+		Reason: models.ReasonUserRequested,
+	})
+	if err != nil {
+		t.Fatalf("UpdateByKeyAndParticipant: %v", err)
+	}
+	if updated.Owner.Name != "New Name" {
+		t.Errorf("updated name = %q, want New Name", updated.Owner.Name)
+	}
+	stored, _ = storage.FindByKey(ctx, testKey)
+	if !ring.Current(stored.Owner.Name) {
+		t.Errorf("stored name %q is not sealed after the update", stored.Owner.Name)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	storage := models.NewMemoryEntryRepository(clock.NewSimulated())
+	owner := models.Owner{Type: models.OwnerTypeNaturalPerson, TaxIdNumber: "52998224725", Name: "Test User"}
+	createEntry(t, storage, owner)
+
+	oldKey := newKey(t)
+	ring := mustParse(t, oldKey)
+	if n, err := Migrate(ctx, storage, ring); err != nil || n != 1 {
+		t.Fatalf("Migrate plaintext = (%d, %v), want 1 entry rewritten", n, err)
+	}
+	if n, _ := Migrate(ctx, storage, ring); n != 0 {
+		t.Errorf("second Migrate rewrote %d entries, want 0", n)
+	}
+
+	// Rotating re-seals under the new key
+	rotated := mustParse(t, newKey(t)+","+oldKey)
+	if n, err := Migrate(ctx, storage, rotated); err != nil || n != 1 {
+		t.Fatalf("Migrate after rotation = (%d, %v), want 1 entry rewritten", n, err)
+	}
+	stored, _ := storage.FindByKey(ctx, testKey)
+	if !rotated.Current(stored.Owner.Name) || !rotated.Current(stored.Owner.TaxIdNumber) {
+		t.Errorf("stored owner = %+v, want it sealed with the new key", stored.Owner)
+	}
+
+	entry, _ := NewEntryRepository(storage, rotated).FindByKey(ctx, testKey)
+	if entry.Owner != owner {
+		t.Errorf("owner = %+v, want %+v", entry.Owner, owner)
+	}
+}
+
+func TestEntryRepositoryFindsByTaxID(t *testing.T) {
+	ctx := context.Background()
+	storage := models.NewMemoryEntryRepository(clock.NewSimulated())
+	oldKey := newKey(t)
+	owner := models.Owner{Type: models.OwnerTypeNaturalPerson, TaxIdNumber: "52998224725", Name: "Test User"}
+	createEntry(t, NewEntryRepository(storage, mustParse(t, oldKey)), owner)
+
+	if found, _ := storage.FindByTaxID(ctx, owner.TaxIdNumber); len(found) != 0 {
+		t.Errorf("storage found %d entries by the plaintext tax ID, want it sealed", len(found))
+	}
+
+	// Found through the retired key's blind index until the startup migration re-seals the owner
+	rotated := mustParse(t, newKey(t)+","+oldKey)
+	repo := NewEntryRepository(storage, rotated)
+	found, err := repo.FindByTaxID(ctx, owner.TaxIdNumber)
+	if err != nil {
+		t.Fatalf("FindByTaxID: %v", err)
+	}
+	if len(found) != 1 || found[0].Owner != owner {
+		t.Fatalf("FindByTaxID = %+v, want the entry with its owner opened", found)
+	}
+
+	if _, err := Migrate(ctx, storage, rotated); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	stored, _ := storage.FindByKey(ctx, testKey)
+	if stored.Owner.TaxIdHash != rotated.BlindIndex(fieldTaxID, owner.TaxIdNumber) {
+		t.Errorf("stored blind index = %q, want the new key's", stored.Owner.TaxIdHash)
+	}
+	if found, _ := repo.FindByTaxID(ctx, "11144477735"); len(found) != 0 {
+		t.Errorf("FindByTaxID of another tax ID = %+v, want none", found)
+	}
+}
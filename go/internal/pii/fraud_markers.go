@@ -0,0 +1,64 @@
+package pii
+
+import (
+	"context"
+
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// FraudMarkerRepository seals the tax ID of fraud markers before they are stored and opens it on the way out.
+// Markers are found by the blind index of their tax ID; those stored in plaintext before encryption are kept
+// as they are until their retention removes them.
+type FraudMarkerRepository struct {
+	models.FraudMarkerRepository
+	keys *Keyring
+}
+
+// NewFraudMarkerRepository wraps repo with tax ID encryption under keys
+func NewFraudMarkerRepository(repo models.FraudMarkerRepository, keys *Keyring) *FraudMarkerRepository {
+	return &FraudMarkerRepository{FraudMarkerRepository: repo, keys: keys}
+}
+
+// Create seals the marker's tax ID and stores the marker
+func (r *FraudMarkerRepository) Create(ctx context.Context, marker *models.FraudMarker) error {
+	sealed := *marker
+	var err error
+	if sealed.TaxIdNumber, err = r.keys.Seal(fieldTaxID, marker.TaxIdNumber); err != nil {
+		return err
+	}
+	sealed.TaxIdHash = r.keys.BlindIndex(fieldTaxID, marker.TaxIdNumber)
+
+	if err := r.FraudMarkerRepository.Create(ctx, &sealed); err != nil {
+		return err
+	}
+	marker.ID = sealed.ID
+	return nil
+}
+
+// FindByKey returns the markers left on a key, opened
+func (r *FraudMarkerRepository) FindByKey(ctx context.Context, key string) ([]models.FraudMarker, error) {
+	markers, err := r.FraudMarkerRepository.FindByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	for i := range markers {
+		if markers[i].TaxIdNumber, err = r.keys.Open(fieldTaxID, markers[i].TaxIdNumber); err != nil {
+			return nil, err
+		}
+		markers[i].TaxIdHash = ""
+	}
+	return markers, nil
+}
+
+// DeleteByTaxID deletes the markers by the blind index of taxID under every key, then those stored in plaintext
+func (r *FraudMarkerRepository) DeleteByTaxID(ctx context.Context, taxID string) (int64, error) {
+	var deleted int64
+	for _, match := range append(r.keys.BlindIndexes(fieldTaxID, taxID), taxID) {
+		n, err := r.FraudMarkerRepository.DeleteByTaxID(ctx, match)
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+	return deleted, nil
+}
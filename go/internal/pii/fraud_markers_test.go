@@ -0,0 +1,44 @@
+package pii
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dict-simulator/go/internal/models"
+)
+
+func TestFraudMarkerRepositorySealsTaxIDs(t *testing.T) {
+	ctx := context.Background()
+	storage := models.NewMemoryFraudMarkerRepository()
+	ring := mustParse(t, newKey(t))
+	repo := NewFraudMarkerRepository(storage, ring)
+
+	marker := &models.FraudMarker{Key: testKey, NormalizedKey: testKey, KeyType: models.KeyTypeCPF, TaxIdNumber: testKey, Participant: "12345678", CreatedAt: time.Now()}
+	if err := repo.Create(ctx, marker); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	// Stored before encryption was enabled
+	if err := storage.Create(ctx, &models.FraudMarker{Key: testKey, NormalizedKey: testKey, TaxIdNumber: testKey, CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("Create plaintext: %v", err)
+	}
+
+	stored, _ := storage.FindByKey(ctx, testKey)
+	if !ring.Current(stored[1].TaxIdNumber) || stored[1].TaxIdHash == "" {
+		t.Errorf("stored marker = %+v, want its tax ID sealed and indexed", stored[1])
+	}
+
+	markers, err := repo.FindByKey(ctx, testKey)
+	if err != nil {
+		t.Fatalf("FindByKey: %v", err)
+	}
+	for _, m := range markers {
+		if m.TaxIdNumber != testKey || m.TaxIdHash != "" {
+			t.Errorf("marker = %+v, want its tax ID opened", m)
+		}
+	}
+
+	if n, err := repo.DeleteByTaxID(ctx, testKey); err != nil || n != 2 {
+		t.Errorf("DeleteByTaxID = (%d, %v), want the sealed and the plaintext marker deleted", n, err)
+	}
+}
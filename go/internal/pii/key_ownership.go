@@ -0,0 +1,151 @@
+package pii
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// KeyOwnershipRepository seals the owner of ownership periods before they are stored and opens it on the
+// way out, as EntryRepository does for entries. Periods are erased by the blind index of their owner's tax ID.
+type KeyOwnershipRepository struct {
+	models.KeyOwnershipRepository
+	keys *Keyring
+}
+
+// NewKeyOwnershipRepository wraps repo with owner encryption under keys
+func NewKeyOwnershipRepository(repo models.KeyOwnershipRepository, keys *Keyring) *KeyOwnershipRepository {
+	return &KeyOwnershipRepository{KeyOwnershipRepository: repo, keys: keys}
+}
+
+// Open seals the period's owner and stores the period
+func (r *KeyOwnershipRepository) Open(ctx context.Context, period *models.KeyOwnership) error {
+	sealed := *period
+	owner, err := r.keys.SealOwner(period.Owner)
+	if err != nil {
+		return err
+	}
+	sealed.Owner = owner
+
+	if err := r.KeyOwnershipRepository.Open(ctx, &sealed); err != nil {
+		return err
+	}
+	period.ID = sealed.ID
+	return nil
+}
+
+// FindCurrent returns the current period of a key, opened
+func (r *KeyOwnershipRepository) FindCurrent(ctx context.Context, key string) (*models.KeyOwnership, error) {
+	period, err := r.KeyOwnershipRepository.FindCurrent(ctx, key)
+	if err != nil || period == nil {
+		return nil, err
+	}
+	if err := r.keys.OpenOwner(&period.Owner); err != nil {
+		return nil, err
+	}
+	return period, nil
+}
+
+// FindByKey returns the periods of a key, opened
+func (r *KeyOwnershipRepository) FindByKey(ctx context.Context, key string) ([]models.KeyOwnership, error) {
+	periods, err := r.KeyOwnershipRepository.FindByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	for i := range periods {
+		if err := r.keys.OpenOwner(&periods[i].Owner); err != nil {
+			return nil, err
+		}
+	}
+	return periods, nil
+}
+
+// DeleteByTaxID deletes the periods by the blind index of taxID under every key
+func (r *KeyOwnershipRepository) DeleteByTaxID(ctx context.Context, taxID string) (int64, error) {
+	var deleted int64
+	for _, index := range r.keys.BlindIndexes(fieldTaxID, taxID) {
+		n, err := r.KeyOwnershipRepository.DeleteByTaxID(ctx, index)
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+	return deleted, nil
+}
+
+// ForEachPeriod calls fn with every period, opened
+func (r *KeyOwnershipRepository) ForEachPeriod(ctx context.Context, fn func(period *models.KeyOwnership) error) error {
+	return r.KeyOwnershipRepository.ForEachPeriod(ctx, func(period *models.KeyOwnership) error {
+		if err := r.keys.OpenOwner(&period.Owner); err != nil {
+			return err
+		}
+		return fn(period)
+	})
+}
+
+// RewriteOwner stores owner sealed if the period's opened owner still is old
+func (r *KeyOwnershipRepository) RewriteOwner(ctx context.Context, key string, id primitive.ObjectID, old, owner models.Owner) (bool, error) {
+	periods, err := r.KeyOwnershipRepository.FindByKey(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	var stored *models.Owner
+	for i := range periods {
+		if periods[i].ID == id {
+			stored = &periods[i].Owner
+		}
+	}
+	if stored == nil {
+		return false, nil
+	}
+	opened := *stored
+	if err := r.keys.OpenOwner(&opened); err != nil {
+		return false, err
+	}
+	if opened.Name != old.Name || opened.TaxIdNumber != old.TaxIdNumber {
+		return false, nil
+	}
+	sealed, err := r.keys.SealOwner(owner)
+	if err != nil {
+		return false, err
+	}
+	return r.KeyOwnershipRepository.RewriteOwner(ctx, key, id, *stored, sealed)
+}
+
+// MigrateOwnership seals the period owners repo stores in plaintext, under a key other than the current one,
+// or without a blind index, and returns how many periods it rewrote. repo must be the storage repository.
+func MigrateOwnership(ctx context.Context, repo models.KeyOwnershipRepository, keys *Keyring) (int, error) {
+	// Collected first: rewriting while iterating could visit periods twice
+	var pending []models.KeyOwnership
+	err := repo.ForEachPeriod(ctx, func(period *models.KeyOwnership) error {
+		if !keys.Current(period.Owner.Name) || !keys.Current(period.Owner.TaxIdNumber) || period.Owner.TaxIdHash == "" {
+			pending = append(pending, *period)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	rewritten := 0
+	for _, p := range pending {
+		opened := p.Owner
+		if err := keys.OpenOwner(&opened); err != nil {
+			return rewritten, err
+		}
+		sealed, err := keys.SealOwner(opened)
+		if err != nil {
+			return rewritten, err
+		}
+		ok, err := repo.RewriteOwner(ctx, p.Key, p.ID, p.Owner, sealed)
+		if err != nil {
+			return rewritten, err
+		}
+		if ok {
+			rewritten++
+		}
+	}
+	return rewritten, nil
+}
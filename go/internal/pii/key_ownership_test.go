@@ -0,0 +1,75 @@
+package pii
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// newPeriod returns the period in which participant holds testKey for owner
+func newPeriod(owner models.Owner) *models.KeyOwnership {
+	return &models.KeyOwnership{
+		Key:           testKey,
+		NormalizedKey: testKey,
+		KeyType:       models.KeyTypeCPF,
+		Participant:   "12345678",
+		Owner:         owner,
+		From:          time.Now().UTC(),
+	}
+}
+
+func TestKeyOwnershipRepositorySealsOwners(t *testing.T) {
+	ctx := context.Background()
+	storage := models.NewMemoryKeyOwnershipRepository()
+	ring := mustParse(t, newKey(t))
+	repo := NewKeyOwnershipRepository(storage, ring)
+	owner := models.Owner{Type: models.OwnerTypeNaturalPerson, TaxIdNumber: testKey, Name: "Test User"}
+
+	if err := repo.Open(ctx, newPeriod(owner)); err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	stored, _ := storage.FindCurrent(ctx, testKey)
+	if !ring.Current(stored.Owner.Name) || !ring.Current(stored.Owner.TaxIdNumber) || stored.Owner.TaxIdHash == "" {
+		t.Errorf("stored owner = %+v, want it sealed and indexed", stored.Owner)
+	}
+
+	current, err := repo.FindCurrent(ctx, testKey)
+	if err != nil {
+		t.Fatalf("FindCurrent: %v", err)
+	}
+	if current.Owner != owner {
+		t.Errorf("owner = %+v, want %+v", current.Owner, owner)
+	}
+
+	if n, err := repo.DeleteByTaxID(ctx, testKey); err != nil || n != 1 {
+		t.Errorf("DeleteByTaxID = (%d, %v), want the period deleted", n, err)
+	}
+}
+
+func TestMigrateOwnership(t *testing.T) {
+	ctx := context.Background()
+	storage := models.NewMemoryKeyOwnershipRepository()
+	owner := models.Owner{Type: models.OwnerTypeNaturalPerson, TaxIdNumber: testKey, Name: "Test User"}
+	if err := storage.Open(ctx, newPeriod(owner)); err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	ring := mustParse(t, newKey(t))
+	if n, err := MigrateOwnership(ctx, storage, ring); err != nil || n != 1 {
+		t.Fatalf("MigrateOwnership plaintext = (%d, %v), want 1 period rewritten", n, err)
+	}
+	if n, _ := MigrateOwnership(ctx, storage, ring); n != 0 {
+		t.Errorf("second MigrateOwnership rewrote %d periods, want 0", n)
+	}
+
+	periods, err := NewKeyOwnershipRepository(storage, ring).FindByKey(ctx, testKey)
+	if err != nil {
+		t.Fatalf("FindByKey: %v", err)
+	}
+	if len(periods) != 1 || periods[0].Owner != owner {
+		t.Errorf("periods = %+v, want the one period with its owner opened", periods)
+	}
+}
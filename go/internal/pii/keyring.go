@@ -0,0 +1,213 @@
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// sealedPrefix starts every sealed value; values without it are plaintext stored before encryption
+const sealedPrefix = "pii:v1:"
+
+// keySize is the size of key-encryption and data keys (AES-256)
+const keySize = 32
+
+// kek is a key-encryption key, named by an ID derived from the key itself
+type kek struct {
+	id    string
+	aead  cipher.AEAD
+	index []byte // HMAC key of blind indexes, derived so it never doubles as an encryption key
+}
+
+// Keyring seals values with envelope encryption: each value is encrypted with a fresh data key,
+// which is in turn encrypted with the current key-encryption key and stored alongside it.
+// Older key-encryption keys stay on the ring to open the values they sealed until re-sealed.
+type Keyring struct {
+	keys []kek // the first one seals
+}
+
+// ParseKeys parses base64 encoded 256-bit key-encryption keys in the form
+//
+//	current-key,older-key
+//
+// The first key seals new values; every key opens. An empty string yields no keyring (nil).
+func ParseKeys(s string) (*Keyring, error) {
+	ring := &Keyring{}
+	seen := map[string]bool{}
+
+	for i, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		// The item is a key, so it is not quoted in errors
+		raw, err := base64.StdEncoding.DecodeString(item)
+		if err != nil {
+			return nil, fmt.Errorf("key %d is not base64", i+1)
+		}
+		if len(raw) != keySize {
+			return nil, fmt.Errorf("key %d is %d bytes, want %d", i+1, len(raw), keySize)
+		}
+
+		aead, err := newAEAD(raw)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(raw)
+		id := hex.EncodeToString(sum[:4])
+		if seen[id] {
+			return nil, fmt.Errorf("key %d is given twice", i+1)
+		}
+		seen[id] = true
+		index := hmac.New(sha256.New, raw)
+		index.Write([]byte("blind index"))
+		ring.keys = append(ring.keys, kek{id: id, aead: aead, index: index.Sum(nil)})
+	}
+
+	if len(ring.keys) == 0 {
+		return nil, nil
+	}
+	return ring, nil
+}
+
+// newAEAD returns AES-256-GCM with key
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Seal encrypts plaintext for field, in the form pii:v1:{key ID}:{wrapped data key}:{ciphertext}
+// The field is authenticated with the value, so a sealed name can't be passed off as a tax ID.
+func (k *Keyring) Seal(field, plaintext string) (string, error) {
+	current := k.keys[0]
+
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	data, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	wrapped, err := seal(current.aead, dataKey, []byte(current.id))
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(data, []byte(plaintext), []byte(field))
+	if err != nil {
+		return "", err
+	}
+
+	return sealedPrefix + current.id + ":" +
+		base64.RawURLEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawURLEncoding.EncodeToString(ciphertext), nil
+}
+
+// Open decrypts a value Seal produced for field; plaintext values are returned as they are
+func (k *Keyring) Open(field, value string) (string, error) {
+	if !strings.HasPrefix(value, sealedPrefix) {
+		return value, nil
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, sealedPrefix), ":")
+	if len(parts) != 3 {
+		return "", errors.New("malformed sealed value")
+	}
+	id := parts[0]
+	wrapped, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.New("malformed sealed value")
+	}
+	ciphertext, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", errors.New("malformed sealed value")
+	}
+
+	key := k.key(id)
+	if key == nil {
+		return "", fmt.Errorf("value sealed with key %s, which is not in PII_ENCRYPTION_KEYS", id)
+	}
+	dataKey, err := open(key.aead, wrapped, []byte(id))
+	if err != nil {
+		return "", fmt.Errorf("unwrap data key: %w", err)
+	}
+	data, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(data, ciphertext, []byte(field))
+	if err != nil {
+		return "", fmt.Errorf("decrypt %s: %w", field, err)
+	}
+	return string(plaintext), nil
+}
+
+// BlindIndex returns the blind index of plaintext for field under the current key, in the form {key ID}:{HMAC}
+// Equal values share their blind index, so values sealed at random can still be looked up by it.
+func (k *Keyring) BlindIndex(field, plaintext string) string {
+	return blindIndex(&k.keys[0], field, plaintext)
+}
+
+// BlindIndexes returns the blind index of plaintext for field under every key, the current one first,
+// so lookups also find values indexed under an older key
+func (k *Keyring) BlindIndexes(field, plaintext string) []string {
+	indexes := make([]string, len(k.keys))
+	for i := range k.keys {
+		indexes[i] = blindIndex(&k.keys[i], field, plaintext)
+	}
+	return indexes
+}
+
+// blindIndex returns the blind index of plaintext for field under key
+func blindIndex(key *kek, field, plaintext string) string {
+	mac := hmac.New(sha256.New, key.index)
+	mac.Write([]byte(field))
+	mac.Write([]byte{0})
+	mac.Write([]byte(plaintext))
+	return key.id + ":" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Current reports whether value is sealed with the current key, so it needs no re-sealing
+func (k *Keyring) Current(value string) bool {
+	return strings.HasPrefix(value, sealedPrefix+k.keys[0].id+":")
+}
+
+// key returns the key-encryption key named id, or nil
+func (k *Keyring) key(id string) *kek {
+	for i := range k.keys {
+		if k.keys[i].id == id {
+			return &k.keys[i]
+		}
+	}
+	return nil
+}
+
+// seal encrypts plaintext with a random nonce, which is prepended to the result
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open decrypts what seal produced
+func open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
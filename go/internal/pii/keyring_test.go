@@ -0,0 +1,138 @@
+package pii
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+// newKey returns a random base64 encoded key-encryption key
+func newKey(t *testing.T) string {
+	t.Helper()
+
+	raw := make([]byte, keySize)
+	if _, err := rand.Read(raw); err != nil {
+		t.Fatalf("rand: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+// mustParse parses keys, failing the test on error
+func mustParse(t *testing.T, keys string) *Keyring {
+	t.Helper()
+
+	ring, err := ParseKeys(keys)
+	if err != nil {
+		t.Fatalf("ParseKeys: %v", err)
+	}
+	return ring
+}
+
+func TestSealOpen(t *testing.T) {
+	ring := mustParse(t, newKey(t))
+
+	sealed, err := ring.Seal(fieldName, "João da Silva")
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if !strings.HasPrefix(sealed, sealedPrefix) || strings.Contains(sealed, "João") {
+		t.Errorf("sealed value %q does not hide the plaintext", sealed)
+	}
+	again, _ := ring.Seal(fieldName, "João da Silva")
+	if again == sealed {
+		t.Error("sealing the same value twice gave the same ciphertext")
+	}
+
+	opened, err := ring.Open(fieldName, sealed)
+	if err != nil || opened != "João da Silva" {
+		t.Errorf("Open = (%q, %v), want the plaintext", opened, err)
+	}
+	if _, err := ring.Open(fieldTaxID, sealed); err == nil {
+		t.Error("a sealed name opened as a tax ID")
+	}
+	if got, err := ring.Open(fieldName, "Plain Name"); err != nil || got != "Plain Name" {
+		t.Errorf("Open(plaintext) = (%q, %v), want it returned as is", got, err)
+	}
+}
+
+func TestRotation(t *testing.T) {
+	oldKey, newerKey := newKey(t), newKey(t)
+	old := mustParse(t, oldKey)
+	sealed, err := old.Seal(fieldTaxID, "52998224725")
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	rotated := mustParse(t, newerKey+","+oldKey)
+	if rotated.Current(sealed) {
+		t.Error("a value sealed with the retired key is reported current")
+	}
+	if got, err := rotated.Open(fieldTaxID, sealed); err != nil || got != "52998224725" {
+		t.Errorf("Open with the retired key = (%q, %v), want the plaintext", got, err)
+	}
+	resealed, _ := rotated.Seal(fieldTaxID, "52998224725")
+	if !rotated.Current(resealed) {
+		t.Error("a value sealed with the current key is not reported current")
+	}
+
+	if _, err := mustParse(t, newerKey).Open(fieldTaxID, sealed); err == nil {
+		t.Error("opened a value whose key was dropped")
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	oldKey, newerKey := newKey(t), newKey(t)
+	old := mustParse(t, oldKey)
+	index := old.BlindIndex(fieldTaxID, "52998224725")
+
+	if again := old.BlindIndex(fieldTaxID, "52998224725"); again != index {
+		t.Errorf("blind index of the same value = %q, want %q", again, index)
+	}
+	if strings.Contains(index, "52998224725") {
+		t.Errorf("blind index %q shows the plaintext", index)
+	}
+	if other := old.BlindIndex(fieldTaxID, "11222333000181"); other == index {
+		t.Error("two values share a blind index")
+	}
+	if other := old.BlindIndex(fieldName, "52998224725"); other == index {
+		t.Error("the same value in two fields shares a blind index")
+	}
+
+	rotated := mustParse(t, newerKey+","+oldKey)
+	indexes := rotated.BlindIndexes(fieldTaxID, "52998224725")
+	if len(indexes) != 2 || indexes[0] != rotated.BlindIndex(fieldTaxID, "52998224725") || indexes[1] != index {
+		t.Errorf("BlindIndexes = %v, want the current key's index then %q", indexes, index)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	key := newKey(t)
+	tests := []struct {
+		name    string
+		keys    string
+		wantErr bool
+		wantNil bool
+	}{
+		{name: "empty", keys: "", wantNil: true},
+		{name: "one key", keys: key},
+		{name: "not base64", keys: "not-a-key!", wantErr: true},
+		{name: "short key", keys: base64.StdEncoding.EncodeToString([]byte("short")), wantErr: true},
+		{name: "duplicate", keys: key + "," + key, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ring, err := ParseKeys(tt.keys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseKeys error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (ring == nil) != tt.wantNil {
+				t.Errorf("ParseKeys = %v, want nil %v", ring, tt.wantNil)
+			}
+			if err != nil && strings.Contains(err.Error(), key) {
+				t.Errorf("error %q leaks the key", err)
+			}
+		})
+	}
+}
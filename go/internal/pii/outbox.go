@@ -0,0 +1,88 @@
+package pii
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/dict-simulator/go/internal/events"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// fieldEventData names the sealed data of queued events
+const fieldEventData = "event.data"
+
+// OutboxRepository seals the data of queued events, which carries entries and their owners, and opens it
+// on the way out, so the relay publishes each event as it was enqueued. The rest of the event (ID, type,
+// participant) stays readable. Payloads queued before encryption are returned as they are.
+type OutboxRepository struct {
+	models.OutboxRepository
+	keys *Keyring
+}
+
+// NewOutboxRepository wraps repo with event data encryption under keys
+func NewOutboxRepository(repo models.OutboxRepository, keys *Keyring) *OutboxRepository {
+	return &OutboxRepository{OutboxRepository: repo, keys: keys}
+}
+
+// Enqueue seals the event's data and queues the event
+func (r *OutboxRepository) Enqueue(ctx context.Context, event events.Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	sealed, err := r.keys.Seal(fieldEventData, string(data))
+	if err != nil {
+		return err
+	}
+	event.Data = sealed
+	return r.OutboxRepository.Enqueue(ctx, event)
+}
+
+// FindDue returns the due messages with their payloads opened
+func (r *OutboxRepository) FindDue(ctx context.Context, now time.Time, limit int64) ([]models.OutboxMessage, error) {
+	messages, err := r.OutboxRepository.FindDue(ctx, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range messages {
+		if messages[i].Payload, err = r.openPayload(messages[i].Payload); err != nil {
+			return nil, err
+		}
+	}
+	return messages, nil
+}
+
+// FindByEventID finds the outbox message for an event with its payload opened
+func (r *OutboxRepository) FindByEventID(ctx context.Context, eventID string) (*models.OutboxMessage, error) {
+	message, err := r.OutboxRepository.FindByEventID(ctx, eventID)
+	if err != nil || message == nil {
+		return nil, err
+	}
+	if message.Payload, err = r.openPayload(message.Payload); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// openPayload puts the opened data back in place of the sealed string, which yields the payload the event
+// would have had unsealed
+func (r *OutboxRepository) openPayload(payload string) (string, error) {
+	var event struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		return "", err
+	}
+	var sealed string
+	if json.Unmarshal(event.Data, &sealed) != nil || !strings.HasPrefix(sealed, sealedPrefix) {
+		return payload, nil
+	}
+
+	data, err := r.keys.Open(fieldEventData, sealed)
+	if err != nil {
+		return "", err
+	}
+	return strings.Replace(payload, `"data":`+string(event.Data), `"data":`+data, 1), nil
+}
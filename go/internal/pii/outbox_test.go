@@ -0,0 +1,56 @@
+package pii
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dict-simulator/go/internal/events"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+func TestOutboxRepositorySealsEventData(t *testing.T) {
+	ctx := context.Background()
+	storage := models.NewMemoryOutboxRepository()
+	ring := mustParse(t, newKey(t))
+	repo := NewOutboxRepository(storage, ring)
+
+	data := models.EntryResponse{Key: testKey, Owner: models.Owner{TaxIdNumber: testKey, Name: "João <Silva>"}}
+	event := events.New(events.EntryCreated, "12345678", data, time.Now())
+	if err := repo.Enqueue(ctx, event); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	// Queued before encryption was enabled
+	plain := events.New(events.EntryDeleted, "12345678", data, time.Now())
+	if err := storage.Enqueue(ctx, plain); err != nil {
+		t.Fatalf("Enqueue plaintext: %v", err)
+	}
+
+	stored, _ := storage.FindByEventID(ctx, event.ID)
+	if strings.Contains(stored.Payload, testKey) || !strings.Contains(stored.Payload, event.ID) {
+		t.Errorf("stored payload %s, want the data sealed and the event ID readable", stored.Payload)
+	}
+
+	due, err := repo.FindDue(ctx, time.Now().Add(time.Minute), 10)
+	if err != nil {
+		t.Fatalf("FindDue: %v", err)
+	}
+	if len(due) != 2 {
+		t.Fatalf("FindDue returned %d messages, want 2", len(due))
+	}
+
+	// The relay gets the payloads an unsealed outbox would have stored
+	unsealed := models.NewMemoryOutboxRepository()
+	_ = unsealed.Enqueue(ctx, event)
+	_ = unsealed.Enqueue(ctx, plain)
+	want, _ := unsealed.FindDue(ctx, time.Now().Add(time.Minute), 10)
+	for i := range due {
+		if due[i].Payload != want[i].Payload {
+			t.Errorf("payload = %s, want %s", due[i].Payload, want[i].Payload)
+		}
+	}
+	if opened, _ := repo.FindByEventID(ctx, event.ID); opened.Payload != want[0].Payload {
+		t.Errorf("FindByEventID payload = %s, want %s", opened.Payload, want[0].Payload)
+	}
+}
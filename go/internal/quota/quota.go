@@ -0,0 +1,83 @@
+package quota
+
+import (
+	"maps"
+	"sync"
+)
+
+// Quota caps what one participant can take of a simulator shared by several teams
+// Zero fields set no cap.
+type Quota struct {
+	MaxEntries        int `json:"maxEntries" validate:"min=0" example:"10000"`      // entries the participant can hold at once
+	RequestsPerMinute int `json:"requestsPerMinute" validate:"min=0" example:"600"` // DICT requests across all routes
+}
+
+// Store holds the default quota and the participants' own, which admins change while the server runs
+type Store struct {
+	mu        sync.RWMutex
+	defaults  Quota
+	overrides map[string]Quota
+}
+
+// NewStore creates a store applying defaults to every participant
+func NewStore(defaults Quota) *Store {
+	return &Store{defaults: defaults, overrides: map[string]Quota{}}
+}
+
+// For returns the quota participant is held to: its own, else the default
+func (s *Store) For(participant string) Quota {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if q, ok := s.overrides[participant]; ok {
+		return q
+	}
+	return s.defaults
+}
+
+// Has reports whether participant has a quota of its own
+func (s *Store) Has(participant string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.overrides[participant]
+	return ok
+}
+
+// Defaults returns the quota of the participants without one of their own
+func (s *Store) Defaults() Quota {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.defaults
+}
+
+// SetDefaults replaces the quota of the participants without one of their own
+func (s *Store) SetDefaults(q Quota) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.defaults = q
+}
+
+// Overrides returns a snapshot of the participants' own quotas, by ISPB
+func (s *Store) Overrides() map[string]Quota {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return maps.Clone(s.overrides)
+}
+
+// Set gives participant a quota of its own, replacing the default for it
+func (s *Store) Set(participant string, q Quota) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.overrides[participant] = q
+}
+
+// Remove puts participant back on the default quota and reports whether it had one of its own
+func (s *Store) Remove(participant string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.overrides[participant]
+	delete(s.overrides, participant)
+	return ok
+}
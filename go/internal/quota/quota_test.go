@@ -0,0 +1,45 @@
+package quota
+
+import "testing"
+
+func TestStore(t *testing.T) {
+	store := NewStore(Quota{MaxEntries: 100})
+
+	if got := store.For("12345678"); got.MaxEntries != 100 || got.RequestsPerMinute != 0 {
+		t.Errorf("For() without an override = %+v, want the defaults", got)
+	}
+
+	store.Set("12345678", Quota{MaxEntries: 10, RequestsPerMinute: 60})
+	if got := store.For("12345678"); got.MaxEntries != 10 || got.RequestsPerMinute != 60 {
+		t.Errorf("For() with an override = %+v, want the override", got)
+	}
+	if got := store.For("87654321"); got.MaxEntries != 100 {
+		t.Errorf("For() another participant = %+v, want the defaults", got)
+	}
+	if !store.Has("12345678") || store.Has("87654321") {
+		t.Error("Has() should only report the participant with an override")
+	}
+
+	// The defaults don't replace an override
+	store.SetDefaults(Quota{MaxEntries: 50})
+	if got := store.For("12345678"); got.MaxEntries != 10 {
+		t.Errorf("For() after SetDefaults() = %+v, want the override", got)
+	}
+	if got := store.For("87654321"); got.MaxEntries != 50 {
+		t.Errorf("For() another participant after SetDefaults() = %+v, want the new defaults", got)
+	}
+
+	// The snapshot is a copy
+	overrides := store.Overrides()
+	delete(overrides, "12345678")
+	if len(store.Overrides()) != 1 {
+		t.Error("Overrides() returned the store's own map")
+	}
+
+	if !store.Remove("12345678") || store.Remove("12345678") {
+		t.Error("Remove() should succeed once")
+	}
+	if got := store.For("12345678"); got.MaxEntries != 50 {
+		t.Errorf("For() after Remove() = %+v, want the defaults", got)
+	}
+}
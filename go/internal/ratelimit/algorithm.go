@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Algorithm names the throttling model a policy's buckets follow
+type Algorithm string
+
+const (
+	// AlgorithmTokenBucket refills tokens at RefillRate up to BucketSize (the DICT model, and the default)
+	AlgorithmTokenBucket Algorithm = "token_bucket"
+
+	// AlgorithmSlidingWindow allows BucketSize tokens per window, the time an empty token bucket takes
+	// to refill, weighting the previous window's count by how much of it the sliding window still covers
+	AlgorithmSlidingWindow Algorithm = "sliding_window"
+
+	// AlgorithmGCRA is the generic cell rate algorithm, a leaky bucket that drains one token every
+	// minute/RefillRate and holds at most BucketSize
+	AlgorithmGCRA Algorithm = "gcra"
+)
+
+// DefaultPolicy is the Algorithms key applied to policies without their own algorithm
+const DefaultPolicy PolicyName = "*"
+
+// Strategy is one algorithm's bucket arithmetic over one storage backend
+// Bucket and MemoryBucket each hold a Strategy per Algorithm and pick one by the policy.
+type Strategy interface {
+	// Remaining returns the tokens left in the bucket, applying refills
+	Remaining(ctx context.Context, policy Policy, identifier string) (int, error)
+	// Deduct takes cost tokens from the bucket, stopping at empty
+	Deduct(ctx context.Context, policy Policy, identifier string, cost int) error
+	// Fill refills the bucket to BucketSize
+	Fill(ctx context.Context, policy Policy, identifier string) error
+}
+
+// strategies holds a backend's Strategy for each Algorithm
+type strategies map[Algorithm]Strategy
+
+// forPolicy returns the strategy of policy's algorithm; the token bucket unless one is set
+func (s strategies) forPolicy(policy Policy) Strategy {
+	if strategy, ok := s[policy.Algorithm]; ok {
+		return strategy
+	}
+	return s[AlgorithmTokenBucket]
+}
+
+// check builds the state Check reports from the strategy's remaining tokens
+func (s strategies) check(ctx context.Context, policy Policy, identifier string, now time.Time) (*BucketState, error) {
+	tokens, err := s.forPolicy(policy).Remaining(ctx, policy, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BucketState{
+		Allowed:   tokens > 0,
+		Remaining: tokens,
+		Reset:     now.Add(time.Minute).Unix(),
+		Policy:    policy.Name,
+	}, nil
+}
+
+// consume deducts the cost of a response with statusCode
+func (s strategies) consume(ctx context.Context, policy Policy, identifier string, statusCode int) error {
+	cost := policy.CostForStatus(statusCode)
+	if cost == 0 {
+		return nil
+	}
+	return s.forPolicy(policy).Deduct(ctx, policy, identifier, cost)
+}
+
+// Algorithms maps policy names to the algorithm their buckets follow
+type Algorithms map[PolicyName]Algorithm
+
+// For returns the algorithm for a policy, falling back to the DefaultPolicy entry
+func (as Algorithms) For(name PolicyName) (Algorithm, bool) {
+	if a, ok := as[name]; ok {
+		return a, true
+	}
+	a, ok := as[DefaultPolicy]
+	return a, ok
+}
+
+// ParseAlgorithms parses per-policy algorithms in the form
+//
+//	ENTRIES_READ_PARTICIPANT_ANTISCAN=gcra;*=sliding_window
+//
+// where * applies to the policies not listed. An empty string yields no algorithms.
+func ParseAlgorithms(s string) (Algorithms, error) {
+	algorithms := Algorithms{}
+	policies := DefaultPolicies()
+
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("rate limit algorithm %q: expected policy=algorithm", entry)
+		}
+		policy := PolicyName(strings.TrimSpace(name))
+		if _, known := policies[policy]; !known && policy != DefaultPolicy {
+			return nil, fmt.Errorf("rate limit algorithm %q: unknown policy %s", entry, policy)
+		}
+
+		switch algorithm := Algorithm(strings.TrimSpace(value)); algorithm {
+		case AlgorithmTokenBucket, AlgorithmSlidingWindow, AlgorithmGCRA:
+			algorithms[policy] = algorithm
+		default:
+			return nil, fmt.Errorf("rate limit algorithm %q: must be %s, %s or %s", entry, AlgorithmTokenBucket, AlgorithmSlidingWindow, AlgorithmGCRA)
+		}
+	}
+
+	return algorithms, nil
+}
+
+// WithAlgorithms returns a copy of policies following algorithms; unlisted policies keep theirs
+func WithAlgorithms(policies map[PolicyName]Policy, algorithms Algorithms) map[PolicyName]Policy {
+	selected := make(map[PolicyName]Policy, len(policies))
+	for name, policy := range policies {
+		if algorithm, ok := algorithms.For(name); ok {
+			policy.Algorithm = algorithm
+		}
+		selected[name] = policy
+	}
+	return selected
+}
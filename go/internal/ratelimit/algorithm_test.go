@@ -0,0 +1,168 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// manualClock only moves when told to, so tests can start on a window boundary
+type manualClock struct{ now time.Time }
+
+func (c *manualClock) Now() time.Time { return c.now }
+
+// newWindowTestBucket returns a bucket whose clock stands at the start of a testPolicy window
+func newWindowTestBucket() (*MemoryBucket, *manualClock) {
+	window := testPolicy.window().Milliseconds()
+	clk := &manualClock{now: time.UnixMilli(time.Now().UnixMilli() / window * window)}
+	return NewMemoryBucket(clk), clk
+}
+
+func TestParseAlgorithms(t *testing.T) {
+	algorithms, err := ParseAlgorithms(" ENTRIES_READ_PARTICIPANT_ANTISCAN = gcra ; *=sliding_window;")
+	if err != nil {
+		t.Fatalf("ParseAlgorithms() error = %v", err)
+	}
+	if a, _ := algorithms.For(PolicyEntriesReadParticipant); a != AlgorithmGCRA {
+		t.Errorf("antiscan algorithm = %q, want gcra", a)
+	}
+	if a, _ := algorithms.For(PolicyEntriesWrite); a != AlgorithmSlidingWindow {
+		t.Errorf("write algorithm = %q, want the * entry", a)
+	}
+
+	if algorithms, err := ParseAlgorithms(""); err != nil || len(algorithms) != 0 {
+		t.Errorf("ParseAlgorithms(\"\") = %v, %v, want none", algorithms, err)
+	}
+
+	for input, want := range map[string]string{
+		"gcra":                   "expected policy=algorithm",
+		"ENTRIES_DELETE=gcra":    "unknown policy",
+		"ENTRIES_WRITE=fixed":    "must be token_bucket, sliding_window or gcra",
+		"ENTRIES_WRITE=":         "must be token_bucket",
+		"*=gcra;ENTRIES_LIST=xx": "ENTRIES_LIST=xx",
+	} {
+		if _, err := ParseAlgorithms(input); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseAlgorithms(%q) error = %v, want it to mention %q", input, err, want)
+		}
+	}
+}
+
+func TestWithAlgorithms(t *testing.T) {
+	policies := WithAlgorithms(DefaultPolicies(), Algorithms{PolicyEntriesList: AlgorithmGCRA})
+	if got := policies[PolicyEntriesList].Algorithm; got != AlgorithmGCRA {
+		t.Errorf("ENTRIES_LIST algorithm = %q, want gcra", got)
+	}
+	if got := policies[PolicyEntriesWrite].Algorithm; got != "" {
+		t.Errorf("ENTRIES_WRITE algorithm = %q, want the token bucket default", got)
+	}
+	if DefaultPolicies()[PolicyEntriesList].Algorithm != "" {
+		t.Error("WithAlgorithms modified the policies it was given")
+	}
+}
+
+func TestMemorySlidingWindow(t *testing.T) {
+	b, clk := newWindowTestBucket()
+	ctx := context.Background()
+	policy := testPolicy // 3 tokens per 90s window
+	policy.Algorithm = AlgorithmSlidingWindow
+
+	steps := []struct {
+		advance time.Duration
+		consume int // status to consume, 0 for none
+		want    int
+	}{
+		{0, 0, 3},
+		{0, http.StatusNotFound, 0},
+		{30 * time.Second, 0, 0},               // still the same window
+		{60 * time.Second, 0, 0},               // next window: all of the previous one weighs in
+		{45 * time.Second, 0, 1},               // half of it: 1.5 tokens counted
+		{0, http.StatusOK, 0},                  // 2.5 counted
+		{45 * time.Second, 0, 2},               // the window after: only the 1 token spent in the last one
+		{3 * time.Minute, 0, 3},                // both windows slid out
+		{0, http.StatusInternalServerError, 3}, // ignored
+	}
+
+	for i, step := range steps {
+		clk.now = clk.now.Add(step.advance)
+		if step.consume != 0 {
+			b.Consume(ctx, policy, "p1", step.consume)
+		}
+		if state, _ := b.Check(ctx, policy, "p1"); state.Remaining != step.want || state.Allowed != (step.want > 0) {
+			t.Fatalf("step %d: state = %+v, want %d tokens", i, state, step.want)
+		}
+	}
+}
+
+func TestMemoryGCRA(t *testing.T) {
+	b, clk := newWindowTestBucket()
+	ctx := context.Background()
+	policy := testPolicy // 3 tokens, one drained every 30s
+	policy.Algorithm = AlgorithmGCRA
+
+	steps := []struct {
+		advance time.Duration
+		consume int
+		want    int
+	}{
+		{0, 0, 3},
+		{0, http.StatusOK, 2},
+		{0, http.StatusNotFound, 0}, // stops at empty
+		{29 * time.Second, 0, 0},
+		{time.Second, 0, 1},
+		{45 * time.Second, 0, 2},
+		{10 * time.Minute, 0, 3}, // capped at the bucket size
+	}
+
+	for i, step := range steps {
+		clk.now = clk.now.Add(step.advance)
+		if step.consume != 0 {
+			b.Consume(ctx, policy, "p1", step.consume)
+		}
+		if state, _ := b.Check(ctx, policy, "p1"); state.Remaining != step.want || state.Allowed != (step.want > 0) {
+			t.Fatalf("step %d: state = %+v, want %d tokens", i, state, step.want)
+		}
+	}
+
+	// A TAT left ahead of a rewound clock holds one empty bucket, not more
+	clk.now = clk.now.Add(time.Hour)
+	b.Consume(ctx, policy, "p1", http.StatusNotFound)
+	clk.now = clk.now.Add(-time.Hour)
+	if state, _ := b.Check(ctx, policy, "p1"); state.Remaining != 0 {
+		t.Errorf("after rewinding, remaining = %d, want 0", state.Remaining)
+	}
+	clk.now = clk.now.Add(30 * time.Second)
+	if state, _ := b.Check(ctx, policy, "p1"); state.Remaining != 1 {
+		t.Errorf("30s after rewinding, remaining = %d, want 1", state.Remaining)
+	}
+}
+
+func TestMemoryAlgorithmsShareBucketOperations(t *testing.T) {
+	for _, algorithm := range []Algorithm{AlgorithmTokenBucket, AlgorithmSlidingWindow, AlgorithmGCRA} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			b, _ := newWindowTestBucket()
+			ctx := context.Background()
+			policy := testPolicy
+			policy.Algorithm = algorithm
+			policy.StartDrained = 1.0 / 3
+
+			if state, _ := b.Check(ctx, policy, "p1"); state.Remaining != 2 {
+				t.Errorf("new bucket remaining = %d, want 2 with two thirds filled", state.Remaining)
+			}
+
+			b.Consume(ctx, policy, "p1", http.StatusNotFound)
+			b.Reset(ctx, policy, "p1")
+			if state, _ := b.Check(ctx, policy, "p1"); state.Remaining != 3 {
+				t.Errorf("after Reset, remaining = %d, want 3", state.Remaining)
+			}
+
+			if deleted, _ := b.Flush(ctx, "p1"); deleted == 0 {
+				t.Error("Flush() deleted no keys")
+			}
+			if state, _ := b.Check(ctx, policy, "p1"); state.Remaining != 2 {
+				t.Errorf("after Flush, remaining = %d, want a new bucket", state.Remaining)
+			}
+		})
+	}
+}
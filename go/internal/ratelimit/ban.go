@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// BanStore keeps temporary bans of client addresses
+// Implemented by RedisBanStore and MemoryBanStore (STORAGE=memory). Bans expire by the wall
+// clock, like a Redis TTL, so moving the simulated clock does not lift them.
+type BanStore interface {
+	// Ban blocks ip for d, replacing any ban it already has
+	Ban(ctx context.Context, ip string, d time.Duration) error
+	// BannedFor returns how long ip stays banned, or 0 if it is not
+	BannedFor(ctx context.Context, ip string) (time.Duration, error)
+}
+
+// banKey generates the storage key for an address's ban
+// Format: ip_ban:{ip}
+func banKey(ip string) string {
+	return "ip_ban:" + ip
+}
+
+// RedisBanStore keeps bans in Redis, shared by every replica
+type RedisBanStore struct {
+	client *redis.Client
+}
+
+// NewRedisBanStore creates a ban store backed by Redis
+func NewRedisBanStore(client *redis.Client) *RedisBanStore {
+	return &RedisBanStore{client: client}
+}
+
+// Ban sets the ban key with a TTL of d
+func (s *RedisBanStore) Ban(ctx context.Context, ip string, d time.Duration) error {
+	return s.client.Set(ctx, banKey(ip), 1, d).Err()
+}
+
+// BannedFor returns the ban key's remaining TTL
+func (s *RedisBanStore) BannedFor(ctx context.Context, ip string) (time.Duration, error) {
+	ttl, err := s.client.PTTL(ctx, banKey(ip)).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, err
+	}
+	// PTTL answers -2 for a missing key (and -1 for one without a TTL, which Ban never writes)
+	return max(ttl, 0), nil
+}
+
+// MemoryBanStore keeps bans in process memory (STORAGE=memory)
+// Bans are per process: an address banned by one replica can still reach the others.
+type MemoryBanStore struct {
+	mu     sync.Mutex
+	expiry map[string]time.Time
+	now    func() time.Time // wall clock, like a Redis TTL; replaced in tests
+}
+
+// NewMemoryBanStore creates an in-memory ban store
+func NewMemoryBanStore() *MemoryBanStore {
+	return &MemoryBanStore{
+		expiry: map[string]time.Time{},
+		now:    time.Now,
+	}
+}
+
+// Ban blocks ip until d from now, dropping bans that have run out
+// Only addresses that exhausted their bucket are banned, so sweeping on each ban is cheap.
+func (s *MemoryBanStore) Ban(ctx context.Context, ip string, d time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for key, expiresAt := range s.expiry {
+		if !now.Before(expiresAt) {
+			delete(s.expiry, key)
+		}
+	}
+	s.expiry[banKey(ip)] = now.Add(d)
+	return nil
+}
+
+// BannedFor returns the time left on ip's ban
+func (s *MemoryBanStore) BannedFor(ctx context.Context, ip string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if expiresAt, ok := s.expiry[banKey(ip)]; ok {
+		return max(expiresAt.Sub(s.now()), 0), nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,38 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryBanStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryBanStore()
+	wall := time.Now()
+	s.now = func() time.Time { return wall }
+
+	if d, _ := s.BannedFor(ctx, "203.0.113.7"); d != 0 {
+		t.Fatalf("BannedFor() = %s before any ban, want 0", d)
+	}
+
+	s.Ban(ctx, "203.0.113.7", time.Minute)
+	wall = wall.Add(20 * time.Second)
+	if d, _ := s.BannedFor(ctx, "203.0.113.7"); d != 40*time.Second {
+		t.Errorf("BannedFor() = %s, want 40s left", d)
+	}
+	if d, _ := s.BannedFor(ctx, "203.0.113.8"); d != 0 {
+		t.Errorf("another address is banned for %s", d)
+	}
+
+	wall = wall.Add(time.Minute)
+	if d, _ := s.BannedFor(ctx, "203.0.113.7"); d != 0 {
+		t.Errorf("BannedFor() = %s after the ban ran out, want 0", d)
+	}
+
+	// Expired bans are dropped when the next one is set
+	s.Ban(ctx, "203.0.113.8", time.Minute)
+	if len(s.expiry) != 1 {
+		t.Errorf("%d bans kept, want the expired one dropped", len(s.expiry))
+	}
+}
@@ -5,9 +5,24 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/redis/go-redis/v9"
+
+	"github.com/dict-simulator/go/internal/clock"
+)
+
+// scriptDuration times the Lua scripts, which every rate limited request waits on twice
+var scriptDuration = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "rate_limit_redis_script_duration_seconds",
+		Help:    "Rate limit Redis script duration in seconds by script (refill and deduct for token buckets, window, window_deduct, gcra, gcra_deduct)",
+		Buckets: []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+	},
+	[]string{"script"},
 )
 
 // Lua scripts for atomic operations - defined at package level for SHA caching
@@ -19,11 +34,19 @@ var (
 		local bucket_size = tonumber(ARGV[1])
 		local refill_rate = tonumber(ARGV[2])
 		local now = tonumber(ARGV[3])
+		local initial_tokens = tonumber(ARGV[4])
+		local ttl = tonumber(ARGV[5])
 
 		-- Get current values
-		local tokens = tonumber(redis.call('GET', tokens_key) or bucket_size)
+		local tokens = tonumber(redis.call('GET', tokens_key) or initial_tokens)
 		local last_refill = tonumber(redis.call('GET', last_refill_key) or now)
 
+		-- An anchor ahead of the clock (it was rewound, e.g. by a restart) refills from now on
+		if last_refill > now then
+			last_refill = now
+			redis.call('SET', last_refill_key, now)
+		end
+
 		-- Calculate refill
 		local elapsed_minutes = (now - last_refill) / 60
 		local refill_amount = math.floor(elapsed_minutes * refill_rate)
@@ -34,8 +57,7 @@ var (
 			redis.call('SET', last_refill_key, now)
 		end
 
-		-- Set TTL to prevent stale keys (2x refill period)
-		local ttl = 120
+		-- Set TTL to prevent stale keys; by then an empty bucket would have refilled
 		redis.call('EXPIRE', tokens_key, ttl)
 		redis.call('EXPIRE', last_refill_key, ttl)
 
@@ -46,20 +68,38 @@ var (
 	deductTokensScript = redis.NewScript(`
 		local tokens_key = KEYS[1]
 		local cost = tonumber(ARGV[1])
-		local bucket_size = tonumber(ARGV[2])
+		local initial_tokens = tonumber(ARGV[2])
+		local ttl = tonumber(ARGV[3])
 
-		local tokens = tonumber(redis.call('GET', tokens_key) or bucket_size)
+		local tokens = tonumber(redis.call('GET', tokens_key) or initial_tokens)
 		tokens = math.max(0, tokens - cost)
 		redis.call('SET', tokens_key, tokens)
-		redis.call('EXPIRE', tokens_key, 120)
+		redis.call('EXPIRE', tokens_key, ttl)
 
 		return tokens
 	`)
 )
 
-// Bucket implements a token bucket rate limiter using Redis
+// Limiter checks and deducts rate limit tokens
+// Implemented by Bucket (Redis) and MemoryBucket (STORAGE=memory).
+type Limiter interface {
+	// Check verifies if a request is allowed without deducting tokens
+	Check(ctx context.Context, policy Policy, identifier string) (*BucketState, error)
+	// Consume deducts the cost of a response from the bucket
+	Consume(ctx context.Context, policy Policy, identifier string, statusCode int) error
+	// Flush deletes the buckets of identifier under every policy, so they start over full,
+	// and returns the number of keys deleted
+	Flush(ctx context.Context, identifier string) (int, error)
+}
+
+// bucketTTL is the shortest time an untouched bucket is kept (see Policy.stateTTL); it then starts over
+const bucketTTL = 2 * time.Minute
+
+// Bucket implements the rate limiter using Redis, with the algorithm each policy selects
 type Bucket struct {
-	client *redis.Client
+	client     *redis.Client
+	clock      clock.Clock
+	strategies strategies
 }
 
 // BucketState represents the current state of a rate limit bucket
@@ -71,64 +111,113 @@ type BucketState struct {
 }
 
 // NewBucket creates a new rate limiter bucket backed by Redis
-func NewBucket(client *redis.Client) *Bucket {
-	return &Bucket{client: client}
+// Refills are computed from clk rather than Redis server time so they can be simulated
+func NewBucket(client *redis.Client, clk clock.Clock) *Bucket {
+	return &Bucket{
+		client: client,
+		clock:  clk,
+		strategies: strategies{
+			AlgorithmTokenBucket:   redisTokenBucket{client: client, clock: clk},
+			AlgorithmSlidingWindow: redisSlidingWindow{client: client, clock: clk},
+			AlgorithmGCRA:          redisGCRA{client: client, clock: clk},
+		},
+	}
 }
 
-// key generates the Redis key for a specific policy and identifier
+// LoadScripts loads the Lua scripts into Redis, so the first requests don't pay for a NOSCRIPT
+// round trip. It is an optimisation only: a script Redis lacks is sent with EVAL when first run.
+func (b *Bucket) LoadScripts(ctx context.Context) error {
+	return loadScripts(ctx, b.client)
+}
+
+// key generates the storage key for a specific policy and identifier
 // Format: rate_limit:{policy}:{identifier}
-func (b *Bucket) key(policy PolicyName, identifier string) string {
+func key(policy PolicyName, identifier string) string {
 	return fmt.Sprintf("rate_limit:%s:%s", policy, identifier)
 }
 
 // tokensKey stores the current token count
-func (b *Bucket) tokensKey(policy PolicyName, identifier string) string {
-	return b.key(policy, identifier) + ":tokens"
+func tokensKey(policy PolicyName, identifier string) string {
+	return key(policy, identifier) + ":tokens"
 }
 
 // lastRefillKey stores the last refill timestamp
-func (b *Bucket) lastRefillKey(policy PolicyName, identifier string) string {
-	return b.key(policy, identifier) + ":last_refill"
+func lastRefillKey(policy PolicyName, identifier string) string {
+	return key(policy, identifier) + ":last_refill"
+}
+
+// keyFields are the suffixes of every algorithm's storage keys
+var keyFields = []string{"tokens", "last_refill", "window", "count", "previous", "tat"}
+
+// isIdentifierKey reports whether a storage key belongs to identifier, under any policy
+func isIdentifierKey(k, identifier string) bool {
+	for _, field := range keyFields {
+		if strings.HasSuffix(k, ":"+identifier+":"+field) {
+			return true
+		}
+	}
+	return false
 }
 
 // Check verifies if a request is allowed (pre-request check)
 // This does NOT deduct tokens - use Consume for that
 func (b *Bucket) Check(ctx context.Context, policy Policy, identifier string) (*BucketState, error) {
-	tokens, err := b.getTokensWithRefill(ctx, policy, identifier)
-	if err != nil {
-		return nil, err
-	}
-
-	// Calculate reset time (next minute boundary)
-	resetTime := time.Now().Add(time.Minute).Unix()
-
-	return &BucketState{
-		Allowed:   tokens > 0,
-		Remaining: tokens,
-		Reset:     resetTime,
-		Policy:    policy.Name,
-	}, nil
+	return b.strategies.check(ctx, policy, identifier, b.clock.Now())
 }
 
 // Consume deducts tokens from the bucket after the response is known
 // The cost depends on the HTTP status code per DICT spec
 func (b *Bucket) Consume(ctx context.Context, policy Policy, identifier string, statusCode int) error {
-	cost := policy.CostForStatus(statusCode)
-	if cost == 0 {
-		return nil
+	return b.strategies.consume(ctx, policy, identifier, statusCode)
+}
+
+// GetState returns the current bucket state without modifying it
+func (b *Bucket) GetState(ctx context.Context, policy Policy, identifier string) (*BucketState, error) {
+	return b.Check(ctx, policy, identifier)
+}
+
+// Reset resets a bucket to full capacity
+func (b *Bucket) Reset(ctx context.Context, policy Policy, identifier string) error {
+	return b.strategies.forPolicy(policy).Fill(ctx, policy, identifier)
+}
+
+// Flush deletes the buckets of identifier under every policy
+// Keys are found with SCAN, so buckets created while it runs may survive.
+func (b *Bucket) Flush(ctx context.Context, identifier string) (int, error) {
+	var keys []string
+	iter := b.client.Scan(ctx, 0, "rate_limit:*", 1000).Iterator()
+	for iter.Next(ctx) {
+		if isIdentifierKey(iter.Val(), identifier) {
+			keys = append(keys, iter.Val())
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return 0, err
 	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	deleted, err := b.client.Del(ctx, keys...).Result()
+	return int(deleted), err
+}
 
-	return b.deduct(ctx, policy, identifier, cost)
+// redisTokenBucket is the token bucket kept in Redis
+type redisTokenBucket struct {
+	client *redis.Client
+	clock  clock.Clock
 }
 
-// getTokensWithRefill gets current tokens, applying refill if needed
-func (b *Bucket) getTokensWithRefill(ctx context.Context, policy Policy, identifier string) (int, error) {
-	tokensKey := b.tokensKey(policy.Name, identifier)
-	lastRefillKey := b.lastRefillKey(policy.Name, identifier)
+// Remaining gets current tokens, applying refill if needed
+func (s redisTokenBucket) Remaining(ctx context.Context, policy Policy, identifier string) (int, error) {
+	tk := tokensKey(policy.Name, identifier)
+	lk := lastRefillKey(policy.Name, identifier)
 
-	now := time.Now().Unix()
-	result, err := getTokensScript.Run(ctx, b.client, []string{tokensKey, lastRefillKey},
-		policy.BucketSize, policy.RefillRate, now).Int()
+	now := s.clock.Now().Unix()
+	start := time.Now()
+	result, err := runScript(ctx, s.client, getTokensScript, []string{tk, lk},
+		policy.BucketSize, policy.RefillRate, now, policy.initialTokens(), int(policy.stateTTL().Seconds())).Int()
+	scriptDuration.WithLabelValues("refill").Observe(time.Since(start).Seconds())
 
 	if err != nil && !errors.Is(err, redis.Nil) {
 		return 0, err
@@ -137,27 +226,24 @@ func (b *Bucket) getTokensWithRefill(ctx context.Context, policy Policy, identif
 	return result, nil
 }
 
-// deduct removes tokens from the bucket
-func (b *Bucket) deduct(ctx context.Context, policy Policy, identifier string, cost int) error {
-	tokensKey := b.tokensKey(policy.Name, identifier)
+// Deduct removes tokens from the bucket
+func (s redisTokenBucket) Deduct(ctx context.Context, policy Policy, identifier string, cost int) error {
+	tk := tokensKey(policy.Name, identifier)
 
-	_, err := deductTokensScript.Run(ctx, b.client, []string{tokensKey}, cost, policy.BucketSize).Int()
+	start := time.Now()
+	_, err := runScript(ctx, s.client, deductTokensScript, []string{tk}, cost, policy.initialTokens(), int(policy.stateTTL().Seconds())).Int()
+	scriptDuration.WithLabelValues("deduct").Observe(time.Since(start).Seconds())
 	return err
 }
 
-// GetState returns the current bucket state without modifying it
-func (b *Bucket) GetState(ctx context.Context, policy Policy, identifier string) (*BucketState, error) {
-	return b.Check(ctx, policy, identifier)
-}
-
-// Reset resets a bucket to full capacity
-func (b *Bucket) Reset(ctx context.Context, policy Policy, identifier string) error {
-	tokensKey := b.tokensKey(policy.Name, identifier)
-	lastRefillKey := b.lastRefillKey(policy.Name, identifier)
+// Fill sets the bucket to full capacity, refilling from now
+func (s redisTokenBucket) Fill(ctx context.Context, policy Policy, identifier string) error {
+	tk := tokensKey(policy.Name, identifier)
+	lk := lastRefillKey(policy.Name, identifier)
 
-	pipe := b.client.Pipeline()
-	pipe.Set(ctx, tokensKey, strconv.Itoa(policy.BucketSize), 2*time.Minute)
-	pipe.Set(ctx, lastRefillKey, strconv.FormatInt(time.Now().Unix(), 10), 2*time.Minute)
+	pipe := s.client.Pipeline()
+	pipe.Set(ctx, tk, strconv.Itoa(policy.BucketSize), policy.stateTTL())
+	pipe.Set(ctx, lk, strconv.FormatInt(s.clock.Now().Unix(), 10), policy.stateTTL())
 	_, err := pipe.Exec(ctx)
 
 	return err
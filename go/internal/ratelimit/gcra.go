@@ -0,0 +1,148 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/dict-simulator/go/internal/clock"
+)
+
+// gcraRead is shared by the GCRA scripts: it reads the bucket's theoretical arrival time (TAT), the
+// time by which it will have drained empty, in milliseconds
+const gcraRead = `
+	local tat_key = KEYS[1]
+	local bucket_size = tonumber(ARGV[1])
+	local tolerance = tonumber(ARGV[2])
+	local now = tonumber(ARGV[3])
+	local initial_delay = tonumber(ARGV[4])
+	local ttl = tonumber(ARGV[5])
+	local interval = tolerance / bucket_size
+
+	local tat = tonumber(redis.call('GET', tat_key) or math.floor(now + initial_delay))
+
+	-- A drained bucket stays empty, not below; a TAT beyond the tolerance (it was set ahead of a
+	-- rewound clock) holds a full bucket's worth at most
+	tat = math.min(math.max(tat, now), now + tolerance)
+`
+
+var (
+	// gcraScript returns the tokens the bucket can still take before its TAT passes the tolerance
+	gcraScript = redis.NewScript(gcraRead + `
+		redis.call('SET', tat_key, tat, 'EX', ttl)
+		return math.floor(bucket_size * (now + tolerance - tat) / tolerance)
+	`)
+
+	// gcraDeductScript pushes the TAT back by an interval per token, stopping at the tolerance
+	gcraDeductScript = redis.NewScript(gcraRead + `
+		local cost = tonumber(ARGV[6])
+		tat = math.floor(math.min(tat + cost * interval, now + tolerance))
+		redis.call('SET', tat_key, tat, 'EX', ttl)
+		return tat
+	`)
+)
+
+// tatKey stores the theoretical arrival time in Unix milliseconds
+func tatKey(policy PolicyName, identifier string) string {
+	return key(policy, identifier) + ":tat"
+}
+
+// gcraParams returns the time each token takes to drain (the emission interval) and the bucket's
+// burst tolerance, in milliseconds
+func gcraParams(policy Policy) (interval, tolerance float64) {
+	tolerance = float64(policy.window().Milliseconds())
+	return tolerance / float64(policy.BucketSize), tolerance
+}
+
+// gcraArgs are the script arguments gcraRead reads
+func gcraArgs(policy Policy, now time.Time) []any {
+	interval, tolerance := gcraParams(policy)
+	return []any{
+		policy.BucketSize,
+		tolerance,
+		now.UnixMilli(),
+		interval * float64(policy.BucketSize-policy.initialTokens()),
+		int(policy.stateTTL().Seconds()),
+	}
+}
+
+// redisGCRA is the GCRA leaky bucket kept in Redis
+type redisGCRA struct {
+	client *redis.Client
+	clock  clock.Clock
+}
+
+// Remaining returns the tokens that fit between the TAT and the tolerance
+func (s redisGCRA) Remaining(ctx context.Context, policy Policy, identifier string) (int, error) {
+	start := time.Now()
+	result, err := runScript(ctx, s.client, gcraScript, []string{tatKey(policy.Name, identifier)}, gcraArgs(policy, s.clock.Now())...).Int()
+	scriptDuration.WithLabelValues("gcra").Observe(time.Since(start).Seconds())
+
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, err
+	}
+	return result, nil
+}
+
+// Deduct pushes the TAT back by cost tokens
+func (s redisGCRA) Deduct(ctx context.Context, policy Policy, identifier string, cost int) error {
+	start := time.Now()
+	_, err := runScript(ctx, s.client, gcraDeductScript, []string{tatKey(policy.Name, identifier)}, append(gcraArgs(policy, s.clock.Now()), cost)...).Int()
+	scriptDuration.WithLabelValues("gcra_deduct").Observe(time.Since(start).Seconds())
+	return err
+}
+
+// Fill moves the TAT to now: nothing is left to drain, so BucketSize tokens fit
+func (s redisGCRA) Fill(ctx context.Context, policy Policy, identifier string) error {
+	return s.client.Set(ctx, tatKey(policy.Name, identifier), s.clock.Now().UnixMilli(), policy.stateTTL()).Err()
+}
+
+// memoryGCRA mirrors redisGCRA in a MemoryBucket
+type memoryGCRA struct {
+	b *MemoryBucket
+}
+
+// read returns the bucket's TAT, the current time and the GCRA parameters (gcraRead)
+// The caller holds the lock.
+func (s memoryGCRA) read(policy Policy, identifier string) (tat, now, interval, tolerance float64) {
+	interval, tolerance = gcraParams(policy)
+	now = float64(s.b.clock.Now().UnixMilli())
+	initialDelay := interval * float64(policy.BucketSize-policy.initialTokens())
+
+	tat = float64(s.b.get(tatKey(policy.Name, identifier), int64(math.Floor(now+initialDelay))))
+	tat = min(max(tat, now), now+tolerance)
+	return tat, now, interval, tolerance
+}
+
+// Remaining returns the tokens that fit between the TAT and the tolerance (gcraScript)
+func (s memoryGCRA) Remaining(ctx context.Context, policy Policy, identifier string) (int, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+
+	tat, now, _, tolerance := s.read(policy, identifier)
+	s.b.set(tatKey(policy.Name, identifier), int64(tat), policy.stateTTL())
+	return int(math.Floor(float64(policy.BucketSize) * (now + tolerance - tat) / tolerance)), nil
+}
+
+// Deduct pushes the TAT back by cost tokens (gcraDeductScript)
+func (s memoryGCRA) Deduct(ctx context.Context, policy Policy, identifier string, cost int) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+
+	tat, now, interval, tolerance := s.read(policy, identifier)
+	tat = math.Floor(min(tat+float64(cost)*interval, now+tolerance))
+	s.b.set(tatKey(policy.Name, identifier), int64(tat), policy.stateTTL())
+	return nil
+}
+
+// Fill moves the TAT to now: nothing is left to drain, so BucketSize tokens fit
+func (s memoryGCRA) Fill(ctx context.Context, policy Policy, identifier string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+
+	s.b.set(tatKey(policy.Name, identifier), s.b.clock.Now().UnixMilli(), policy.stateTTL())
+	return nil
+}
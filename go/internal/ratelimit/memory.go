@@ -0,0 +1,228 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/dict-simulator/go/internal/clock"
+)
+
+// memoryValue is a stored counter and when it expires (by the wall clock, like a Redis TTL)
+type memoryValue struct {
+	n         int64
+	expiresAt time.Time
+}
+
+// MemoryBucket implements the same algorithms as Bucket in process memory (STORAGE=memory)
+// It mirrors the Redis scripts step by step, keys and TTLs included, so limits behave identically.
+// Limits are per process: replicas do not share buckets.
+type MemoryBucket struct {
+	mu         sync.Mutex
+	values     map[string]memoryValue
+	lastSweep  time.Time
+	clock      clock.Clock
+	now        func() time.Time // wall clock for expiry, replaced in tests
+	strategies strategies
+}
+
+// NewMemoryBucket creates a new in-memory rate limiter bucket
+// Refills are computed from clk so they can be simulated
+func NewMemoryBucket(clk clock.Clock) *MemoryBucket {
+	b := &MemoryBucket{
+		values: map[string]memoryValue{},
+		clock:  clk,
+		now:    time.Now,
+	}
+	b.strategies = strategies{
+		AlgorithmTokenBucket:   memoryTokenBucket{b},
+		AlgorithmSlidingWindow: memorySlidingWindow{b},
+		AlgorithmGCRA:          memoryGCRA{b},
+	}
+	return b
+}
+
+// Check verifies if a request is allowed (pre-request check)
+// This does NOT deduct tokens - use Consume for that
+func (b *MemoryBucket) Check(ctx context.Context, policy Policy, identifier string) (*BucketState, error) {
+	return b.strategies.check(ctx, policy, identifier, b.clock.Now())
+}
+
+// Consume deducts tokens from the bucket after the response is known
+// The cost depends on the HTTP status code per DICT spec
+func (b *MemoryBucket) Consume(ctx context.Context, policy Policy, identifier string, statusCode int) error {
+	return b.strategies.consume(ctx, policy, identifier, statusCode)
+}
+
+// GetState returns the current bucket state without modifying it
+func (b *MemoryBucket) GetState(ctx context.Context, policy Policy, identifier string) (*BucketState, error) {
+	return b.Check(ctx, policy, identifier)
+}
+
+// Reset resets a bucket to full capacity
+func (b *MemoryBucket) Reset(ctx context.Context, policy Policy, identifier string) error {
+	return b.strategies.forPolicy(policy).Fill(ctx, policy, identifier)
+}
+
+// Flush deletes the buckets of identifier under every policy
+func (b *MemoryBucket) Flush(ctx context.Context, identifier string) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	deleted := 0
+	for key, v := range b.values {
+		if !isIdentifierKey(key, identifier) {
+			continue
+		}
+		if b.now().Before(v.expiresAt) {
+			deleted++
+		}
+		delete(b.values, key)
+	}
+	return deleted, nil
+}
+
+// savedValue is a stored counter as written by Save
+type savedValue struct {
+	N         int64     `json:"n"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Save writes the unexpired buckets to w as JSON, refill anchors included
+// Loading them after a restart keeps each bucket's budget instead of starting everyone over.
+func (b *MemoryBucket) Save(w io.Writer) error {
+	b.mu.Lock()
+	saved := make(map[string]savedValue, len(b.values))
+	for key, v := range b.values {
+		if b.now().Before(v.expiresAt) {
+			saved[key] = savedValue{N: v.n, ExpiresAt: v.expiresAt}
+		}
+	}
+	b.mu.Unlock()
+
+	return json.NewEncoder(w).Encode(saved)
+}
+
+// Load restores buckets written by Save, skipping those that expired in the meantime
+// Anchors ahead of the clock, saved before a simulated clock advance was lost, refill from now on.
+func (b *MemoryBucket) Load(r io.Reader) error {
+	var saved map[string]savedValue
+	if err := json.NewDecoder(r).Decode(&saved); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for key, v := range saved {
+		if b.now().Before(v.ExpiresAt) {
+			b.values[key] = memoryValue{n: v.N, expiresAt: v.ExpiresAt}
+		}
+	}
+	return nil
+}
+
+// memoryTokenBucket mirrors redisTokenBucket in a MemoryBucket
+type memoryTokenBucket struct {
+	b *MemoryBucket
+}
+
+// Remaining gets current tokens, applying refill if needed (getTokensScript)
+func (s memoryTokenBucket) Remaining(ctx context.Context, policy Policy, identifier string) (int, error) {
+	b := s.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tk := tokensKey(policy.Name, identifier)
+	lk := lastRefillKey(policy.Name, identifier)
+	now := b.clock.Now().Unix()
+
+	ttl := policy.stateTTL()
+	tokens := b.get(tk, int64(policy.initialTokens()))
+	lastRefill := b.get(lk, now)
+
+	// An anchor ahead of the clock (it was rewound, e.g. by a restart) refills from now on
+	if lastRefill > now {
+		lastRefill = now
+		b.set(lk, now, ttl)
+	}
+
+	elapsedMinutes := float64(now-lastRefill) / 60
+	refillAmount := int64(math.Floor(elapsedMinutes * float64(policy.RefillRate)))
+	if refillAmount > 0 {
+		tokens = min(int64(policy.BucketSize), tokens+refillAmount)
+		b.set(tk, tokens, ttl)
+		b.set(lk, now, ttl)
+	}
+
+	b.touch(tk, ttl)
+	b.touch(lk, ttl)
+
+	return int(tokens), nil
+}
+
+// Deduct removes tokens from the bucket (deductTokensScript)
+func (s memoryTokenBucket) Deduct(ctx context.Context, policy Policy, identifier string, cost int) error {
+	b := s.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := tokensKey(policy.Name, identifier)
+	tokens := b.get(key, int64(policy.initialTokens()))
+	b.set(key, max(0, tokens-int64(cost)), policy.stateTTL())
+	return nil
+}
+
+// Fill sets the bucket to full capacity, refilling from now
+func (s memoryTokenBucket) Fill(ctx context.Context, policy Policy, identifier string) error {
+	b := s.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.set(tokensKey(policy.Name, identifier), int64(policy.BucketSize), policy.stateTTL())
+	b.set(lastRefillKey(policy.Name, identifier), b.clock.Now().Unix(), policy.stateTTL())
+	return nil
+}
+
+// get returns the unexpired value for key, or def (GET ... or default)
+func (b *MemoryBucket) get(key string, def int64) int64 {
+	v, ok := b.values[key]
+	if !ok || !b.now().Before(v.expiresAt) {
+		return def
+	}
+	return v.n
+}
+
+// set stores a value with a fresh TTL (SET + EXPIRE)
+func (b *MemoryBucket) set(key string, n int64, ttl time.Duration) {
+	b.sweep()
+	b.values[key] = memoryValue{n: n, expiresAt: b.now().Add(ttl)}
+}
+
+// touch renews the TTL of an existing, unexpired key (EXPIRE)
+func (b *MemoryBucket) touch(key string, ttl time.Duration) {
+	v, ok := b.values[key]
+	if !ok || !b.now().Before(v.expiresAt) {
+		return
+	}
+	v.expiresAt = b.now().Add(ttl)
+	b.values[key] = v
+}
+
+// sweep drops expired keys, at most once per shortest TTL period
+func (b *MemoryBucket) sweep() {
+	now := b.now()
+	if now.Sub(b.lastSweep) < bucketTTL {
+		return
+	}
+	b.lastSweep = now
+
+	for key, v := range b.values {
+		if !now.Before(v.expiresAt) {
+			delete(b.values, key)
+		}
+	}
+}
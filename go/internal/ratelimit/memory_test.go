@@ -0,0 +1,217 @@
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dict-simulator/go/internal/clock"
+)
+
+func newTestMemoryBucket() (*MemoryBucket, *clock.Simulated) {
+	clk := clock.NewSimulated()
+	return NewMemoryBucket(clk), clk
+}
+
+var testPolicy = Policy{
+	Name:         "TEST",
+	BucketSize:   3,
+	RefillRate:   2, // per minute
+	SuccessCost:  1,
+	NotFoundCost: 3,
+	DefaultCost:  1,
+	IgnoreOn5xx:  true,
+}
+
+func TestMemoryBucketConsume(t *testing.T) {
+	b, _ := newTestMemoryBucket()
+	ctx := context.Background()
+
+	state, _ := b.Check(ctx, testPolicy, "p1")
+	if !state.Allowed || state.Remaining != 3 {
+		t.Fatalf("new bucket = %+v, want allowed with 3 tokens", state)
+	}
+
+	b.Consume(ctx, testPolicy, "p1", http.StatusOK)
+	b.Consume(ctx, testPolicy, "p1", http.StatusInternalServerError) // ignored
+	if state, _ := b.Check(ctx, testPolicy, "p1"); state.Remaining != 2 {
+		t.Errorf("after one success, remaining = %d, want 2", state.Remaining)
+	}
+
+	b.Consume(ctx, testPolicy, "p1", http.StatusNotFound)
+	state, _ = b.Check(ctx, testPolicy, "p1")
+	if state.Allowed || state.Remaining != 0 {
+		t.Errorf("after a 404, state = %+v, want denied with 0 tokens", state)
+	}
+
+	if state, _ := b.Check(ctx, testPolicy, "p2"); state.Remaining != 3 {
+		t.Errorf("other identifier remaining = %d, want 3", state.Remaining)
+	}
+}
+
+func TestMemoryBucketRefill(t *testing.T) {
+	b, clk := newTestMemoryBucket()
+	ctx := context.Background()
+
+	// Refills are measured from the stored last refill, which Reset records
+	b.Reset(ctx, testPolicy, "p1")
+	b.Consume(ctx, testPolicy, "p1", http.StatusNotFound)
+
+	clk.Advance(30 * time.Second)
+	if state, _ := b.Check(ctx, testPolicy, "p1"); state.Remaining != 1 {
+		t.Errorf("after 30s, remaining = %d, want 1", state.Remaining)
+	}
+
+	clk.Advance(10 * time.Minute)
+	if state, _ := b.Check(ctx, testPolicy, "p1"); state.Remaining != 3 {
+		t.Errorf("after 10m, remaining = %d, want capped at 3", state.Remaining)
+	}
+}
+
+func TestMemoryBucketExpiry(t *testing.T) {
+	b, _ := newTestMemoryBucket()
+	ctx := context.Background()
+
+	wall := time.Now()
+	b.now = func() time.Time { return wall }
+
+	b.Consume(ctx, testPolicy, "p1", http.StatusNotFound)
+
+	wall = wall.Add(bucketTTL - time.Second)
+	if state, _ := b.Check(ctx, testPolicy, "p1"); state.Remaining != 0 {
+		t.Errorf("before TTL, remaining = %d, want 0", state.Remaining)
+	}
+
+	wall = wall.Add(bucketTTL)
+	if state, _ := b.Check(ctx, testPolicy, "p1"); state.Remaining != 3 {
+		t.Errorf("after TTL, remaining = %d, want a full bucket", state.Remaining)
+	}
+}
+
+func TestMemoryBucketFlush(t *testing.T) {
+	b, _ := newTestMemoryBucket()
+	ctx := context.Background()
+	other := testPolicy
+	other.Name = "OTHER"
+
+	for _, policy := range []Policy{testPolicy, other} {
+		b.Check(ctx, policy, "p1")
+		b.Consume(ctx, policy, "p1", http.StatusNotFound)
+	}
+	b.Consume(ctx, testPolicy, "p2", http.StatusNotFound)
+
+	deleted, err := b.Flush(ctx, "p1")
+	if err != nil || deleted != 2 {
+		t.Fatalf("Flush() = %d, %v, want the tokens keys of both policies", deleted, err)
+	}
+	for _, policy := range []Policy{testPolicy, other} {
+		if state, _ := b.Check(ctx, policy, "p1"); state.Remaining != 3 {
+			t.Errorf("%s remaining after flush = %d, want a full bucket", policy.Name, state.Remaining)
+		}
+	}
+	if state, _ := b.Check(ctx, testPolicy, "p2"); state.Remaining != 0 {
+		t.Errorf("other identifier remaining = %d, want its bucket untouched", state.Remaining)
+	}
+}
+
+func TestMemoryBucketInitialFill(t *testing.T) {
+	b, _ := newTestMemoryBucket()
+	ctx := context.Background()
+
+	for fill, want := range map[float64]int{1: 3, 0.5: 2, 0: 0} {
+		policy := WithInitialFill(map[PolicyName]Policy{testPolicy.Name: testPolicy}, fill)[testPolicy.Name]
+		policy.Name = PolicyName(fmt.Sprintf("TEST_%v", fill))
+		state, _ := b.Check(ctx, policy, "p1")
+		if state.Remaining != want || state.Allowed != (want > 0) {
+			t.Errorf("fill %v: new bucket = %+v, want %d tokens", fill, state, want)
+		}
+	}
+
+	// Reset still fills the bucket up
+	policy := WithInitialFill(map[PolicyName]Policy{testPolicy.Name: testPolicy}, 0)[testPolicy.Name]
+	b.Reset(ctx, policy, "p1")
+	if state, _ := b.Check(ctx, policy, "p1"); state.Remaining != 3 {
+		t.Errorf("after Reset, remaining = %d, want 3", state.Remaining)
+	}
+}
+
+func TestMemoryBucketSlowRefillOutlivesDefaultTTL(t *testing.T) {
+	b, _ := newTestMemoryBucket()
+	ctx := context.Background()
+	policy := DefaultPolicies()[PolicyEntriesReadParticipant] // 50 tokens at 2 per minute
+
+	wall := time.Now()
+	b.now = func() time.Time { return wall }
+
+	for range 17 {
+		b.Consume(ctx, policy, "p1", http.StatusNotFound)
+	}
+
+	wall = wall.Add(2 * bucketTTL)
+	if state, _ := b.Check(ctx, policy, "p1"); state.Remaining != 0 {
+		t.Errorf("untouched for %s, remaining = %d, want the bucket kept until it could have refilled", 2*bucketTTL, state.Remaining)
+	}
+}
+
+func TestMemoryBucketSaveLoad(t *testing.T) {
+	saved, clk := newTestMemoryBucket()
+	ctx := context.Background()
+
+	// The anchor is recorded ahead of where a restarted clock will be
+	clk.Advance(10 * time.Minute)
+	saved.Reset(ctx, testPolicy, "p1")
+	saved.Consume(ctx, testPolicy, "p1", http.StatusNotFound)
+
+	var buf bytes.Buffer
+	if err := saved.Save(&buf); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	state := buf.String()
+
+	b, restarted := newTestMemoryBucket()
+	if err := b.Load(strings.NewReader(state)); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if state, _ := b.Check(ctx, testPolicy, "p1"); state.Remaining != 0 {
+		t.Errorf("after restart, remaining = %d, want the saved 0", state.Remaining)
+	}
+	restarted.Advance(30 * time.Second)
+	if state, _ := b.Check(ctx, testPolicy, "p1"); state.Remaining != 1 {
+		t.Errorf("30s after restart, remaining = %d, want 1 refilled from the restart", state.Remaining)
+	}
+
+	// Buckets that expired while the process was down are not restored
+	b, _ = newTestMemoryBucket()
+	b.now = func() time.Time { return time.Now().Add(time.Hour) }
+	if err := b.Load(strings.NewReader(state)); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if state, _ := b.Check(ctx, testPolicy, "p1"); state.Remaining != 3 {
+		t.Errorf("expired bucket remaining = %d, want a full bucket", state.Remaining)
+	}
+
+	if err := b.Load(strings.NewReader("not json")); err == nil {
+		t.Error("Load() accepted a corrupt state file")
+	}
+}
+
+func BenchmarkMemoryBucket(b *testing.B) {
+	bucket, _ := newTestMemoryBucket()
+	ctx := context.Background()
+	policy := testPolicy
+	policy.BucketSize = 1 << 30
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := bucket.Check(ctx, policy, "p1"); err != nil {
+			b.Fatal(err)
+		}
+		if err := bucket.Consume(ctx, policy, "p1", http.StatusOK); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,5 +1,10 @@
 package ratelimit
 
+import (
+	"math"
+	"time"
+)
+
 // PolicyName identifies a rate limiting policy as defined in DICT API spec
 type PolicyName string
 
@@ -12,6 +17,17 @@ const (
 
 	// PolicyEntriesReadParticipant applies to getEntry operations (participant antiscan)
 	PolicyEntriesReadParticipant PolicyName = "ENTRIES_READ_PARTICIPANT_ANTISCAN"
+
+	// PolicyEntriesList applies to listing a participant's entries for reconciliation
+	PolicyEntriesList PolicyName = "ENTRIES_LIST"
+
+	// PolicyIP is the coarse per-client-IP limit applied before authentication (see IPPolicy)
+	// It is not a DICT policy: its buckets are keyed by address and never collide with participants'.
+	PolicyIP PolicyName = "IP"
+
+	// PolicyQuota is a participant's request quota set through /admin/quotas (see QuotaPolicy)
+	// It is not a DICT policy either: it caps a participant's share of a shared simulator.
+	PolicyQuota PolicyName = "QUOTA"
 )
 
 // Scope defines who the rate limit applies to
@@ -23,6 +39,9 @@ const (
 
 	// ScopeUser limits are per end-user (PI-PayerId)
 	ScopeUser Scope = "USER"
+
+	// ScopeIP limits are per client address, whoever is authenticated
+	ScopeIP Scope = "IP"
 )
 
 // Policy defines the configuration for a rate limiting bucket
@@ -36,6 +55,48 @@ type Policy struct {
 	NotFoundCost int  // tokens consumed on 404 response
 	DefaultCost  int  // tokens consumed on other non-5xx responses
 	IgnoreOn5xx  bool // whether to skip token deduction on 5xx errors
+
+	// Algorithm is the throttling model of the policy's buckets; empty means AlgorithmTokenBucket
+	Algorithm Algorithm
+
+	// StartDrained is the share of BucketSize a new bucket starts without: 0 (the default) starts
+	// full, 1 empty. Starting below full lets tests reach exhaustion without spending the whole budget.
+	StartDrained float64
+}
+
+// initialTokens returns the tokens a new bucket starts with
+func (p Policy) initialTokens() int {
+	drained := min(max(p.StartDrained, 0), 1)
+	return int(math.Round(float64(p.BucketSize) * (1 - drained)))
+}
+
+// stateTTL is how long an untouched bucket is kept: at least as long as an empty bucket takes to
+// refill, so forgetting it is the same as refilling it and a restart doesn't hand out a full budget early
+func (p Policy) stateTTL() time.Duration {
+	if p.RefillRate <= 0 {
+		return bucketTTL
+	}
+	minutes := (p.BucketSize + p.RefillRate - 1) / p.RefillRate
+	return max(bucketTTL, time.Duration(minutes)*time.Minute)
+}
+
+// window is how long an empty bucket takes to refill completely: the length of a sliding window,
+// and the GCRA's burst tolerance
+func (p Policy) window() time.Duration {
+	if p.RefillRate <= 0 {
+		return bucketTTL
+	}
+	return time.Duration(p.BucketSize) * time.Minute / time.Duration(p.RefillRate)
+}
+
+// WithInitialFill returns a copy of policies whose new buckets start with fill (0 to 1) of their size
+func WithInitialFill(policies map[PolicyName]Policy, fill float64) map[PolicyName]Policy {
+	filled := make(map[PolicyName]Policy, len(policies))
+	for name, policy := range policies {
+		policy.StartDrained = 1 - fill
+		filled[name] = policy
+	}
+	return filled
 }
 
 // CostForStatus returns the token cost based on HTTP status code
@@ -89,6 +150,51 @@ func DefaultPolicies() map[PolicyName]Policy {
 			DefaultCost:  1,
 			IgnoreOn5xx:  true,
 		},
+		PolicyEntriesList: {
+			Name:         PolicyEntriesList,
+			Scope:        ScopePSP,
+			RefillRate:   40, // 40 pages per minute, like the spec's listing operations
+			BucketSize:   200,
+			SuccessCost:  1,
+			NotFoundCost: 1,
+			DefaultCost:  1,
+			IgnoreOn5xx:  true,
+		},
+	}
+}
+
+// IPPolicy returns the per-IP policy: burst requests at once, then perMinute
+// Every response costs a token except server errors, so failed logins count as much as successful
+// ones. It drains as a GCRA leaky bucket, which spaces a steady client's tokens out evenly.
+func IPPolicy(perMinute, burst int) Policy {
+	return Policy{
+		Name:         PolicyIP,
+		Scope:        ScopeIP,
+		RefillRate:   perMinute,
+		BucketSize:   burst,
+		SuccessCost:  1,
+		NotFoundCost: 1,
+		DefaultCost:  1,
+		IgnoreOn5xx:  true,
+		Algorithm:    AlgorithmGCRA,
+	}
+}
+
+// QuotaPolicy returns the policy of a participant's request quota: perMinute requests, a minute's
+// worth of them at once. Like the per-IP policy it drains as a GCRA leaky bucket, which keeps the
+// time the requests take to drain rather than a token count, so a quota changed while the bucket is
+// in use applies from the next request: the same backlog drains at the new rate.
+func QuotaPolicy(perMinute int) Policy {
+	return Policy{
+		Name:         PolicyQuota,
+		Scope:        ScopePSP,
+		RefillRate:   perMinute,
+		BucketSize:   perMinute,
+		SuccessCost:  1,
+		NotFoundCost: 1,
+		DefaultCost:  1,
+		IgnoreOn5xx:  true,
+		Algorithm:    AlgorithmGCRA,
 	}
 }
 
@@ -2,6 +2,7 @@ package ratelimit
 
 import (
 	"testing"
+	"time"
 )
 
 func TestPolicyCostForStatus(t *testing.T) {
@@ -130,6 +131,18 @@ func TestDefaultPolicies(t *testing.T) {
 	if entriesRead.NotFoundCost != 3 {
 		t.Errorf("ENTRIES_READ NotFoundCost = %d, want 3 (antiscan penalty)", entriesRead.NotFoundCost)
 	}
+
+	// Test ENTRIES_LIST policy
+	entriesList, ok := policies[PolicyEntriesList]
+	if !ok {
+		t.Fatal("ENTRIES_LIST policy not found")
+	}
+	if entriesList.RefillRate != 40 {
+		t.Errorf("ENTRIES_LIST RefillRate = %d, want 40", entriesList.RefillRate)
+	}
+	if entriesList.BucketSize != 200 {
+		t.Errorf("ENTRIES_LIST BucketSize = %d, want 200", entriesList.BucketSize)
+	}
 }
 
 func TestGetPolicy(t *testing.T) {
@@ -148,3 +161,48 @@ func TestGetPolicy(t *testing.T) {
 		t.Error("GetPolicy(NON_EXISTENT) should return nil")
 	}
 }
+
+func TestPolicyInitialTokens(t *testing.T) {
+	policy := Policy{BucketSize: 50, RefillRate: 2}
+	tests := []struct {
+		fill float64
+		want int
+	}{
+		{1, 50},
+		{0.5, 25},
+		{0.01, 1}, // rounded, not truncated
+		{0, 0},
+		{1.5, 50}, // clamped
+		{-1, 0},
+	}
+
+	for _, tt := range tests {
+		p := WithInitialFill(map[PolicyName]Policy{"P": policy}, tt.fill)["P"]
+		if got := p.initialTokens(); got != tt.want {
+			t.Errorf("fill %v: initialTokens() = %d, want %d", tt.fill, got, tt.want)
+		}
+	}
+
+	if got := policy.initialTokens(); got != 50 {
+		t.Errorf("zero value initialTokens() = %d, want a full bucket", got)
+	}
+}
+
+func TestPolicyStateTTL(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		want   time.Duration
+	}{
+		{"refills within the default", Policy{BucketSize: 600, RefillRate: 600}, bucketTTL},
+		{"slow refill", Policy{BucketSize: 50, RefillRate: 2}, 25 * time.Minute},
+		{"rounded up", Policy{BucketSize: 200, RefillRate: 30}, 7 * time.Minute},
+		{"no refill", Policy{BucketSize: 50}, bucketTTL},
+	}
+
+	for _, tt := range tests {
+		if got := tt.policy.stateTTL(); got != tt.want {
+			t.Errorf("%s: stateTTL() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
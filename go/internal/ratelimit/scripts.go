@@ -0,0 +1,47 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+)
+
+// scriptReloads counts the scripts Redis didn't have, e.g. after a restart, a failover or SCRIPT FLUSH
+var scriptReloads = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Name: "rate_limit_redis_script_reloads_total",
+		Help: "Total number of rate limit Lua scripts sent again with EVAL after Redis answered NOSCRIPT",
+	},
+)
+
+// scripts are the Lua scripts of every Redis algorithm, loaded into Redis's script cache at startup
+var scripts = []*redis.Script{
+	getTokensScript, deductTokensScript,
+	windowScript, windowDeductScript,
+	gcraScript, gcraDeductScript,
+}
+
+// loadScripts sends every script's text to Redis (SCRIPT LOAD), so no request meets a NOSCRIPT
+func loadScripts(ctx context.Context, client redis.Scripter) error {
+	for _, script := range scripts {
+		if err := script.Load(ctx, client).Err(); err != nil {
+			return fmt.Errorf("load rate limit script %s: %w", script.Hash(), err)
+		}
+	}
+	return nil
+}
+
+// runScript runs script as redis.Script.Run does, by its SHA1 (EVALSHA) and with its text (EVAL)
+// when Redis doesn't have it, which caches it again; it also counts those reloads.
+func runScript(ctx context.Context, client redis.Scripter, script *redis.Script, keys []string, args ...any) *redis.Cmd {
+	cmd := script.EvalSha(ctx, client, keys, args...)
+	if !redis.HasErrorPrefix(cmd.Err(), "NOSCRIPT") {
+		return cmd
+	}
+
+	scriptReloads.Inc()
+	return script.Eval(ctx, client, keys, args...)
+}
@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+)
+
+// redisError is an error reply from Redis
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+func (e redisError) RedisError()   {}
+
+// scriptCache is a Redis that only knows the scripts loaded into it
+type scriptCache struct {
+	redis.Scripter
+	loaded  map[string]bool
+	loads   int
+	evals   int
+	loadErr error
+}
+
+func (c *scriptCache) ScriptLoad(ctx context.Context, script string) *redis.StringCmd {
+	cmd := redis.NewStringCmd(ctx, "script", "load", script)
+	if c.loadErr != nil {
+		cmd.SetErr(c.loadErr)
+		return cmd
+	}
+	sha := redis.NewScript(script).Hash()
+	c.loaded[sha] = true
+	c.loads++
+	cmd.SetVal(sha)
+	return cmd
+}
+
+func (c *scriptCache) Eval(ctx context.Context, script string, keys []string, args ...any) *redis.Cmd {
+	c.loaded[redis.NewScript(script).Hash()] = true
+	c.evals++
+	cmd := redis.NewCmd(ctx, "eval", script)
+	cmd.SetVal(int64(1))
+	return cmd
+}
+
+func (c *scriptCache) EvalSha(ctx context.Context, sha1 string, keys []string, args ...any) *redis.Cmd {
+	cmd := redis.NewCmd(ctx, "evalsha", sha1)
+	if !c.loaded[sha1] {
+		cmd.SetErr(redisError("NOSCRIPT No matching script. Please use EVAL."))
+		return cmd
+	}
+	cmd.SetVal(int64(1))
+	return cmd
+}
+
+func TestLoadScripts(t *testing.T) {
+	ctx := context.Background()
+	cache := &scriptCache{loaded: map[string]bool{}}
+
+	if err := loadScripts(ctx, cache); err != nil {
+		t.Fatalf("loadScripts() error = %v", err)
+	}
+	if cache.loads != len(scripts) {
+		t.Errorf("loads = %d, want %d", cache.loads, len(scripts))
+	}
+
+	cache.loadErr = errors.New("connection refused")
+	if err := loadScripts(ctx, cache); !errors.Is(err, cache.loadErr) {
+		t.Errorf("loadScripts() error = %v, want %v", err, cache.loadErr)
+	}
+}
+
+func TestRunScriptResendsForgottenScript(t *testing.T) {
+	ctx := context.Background()
+	cache := &scriptCache{loaded: map[string]bool{}}
+	reloads := testutil.ToFloat64(scriptReloads)
+
+	// Redis restarted since startup, so it no longer has the script
+	if got, err := runScript(ctx, cache, gcraScript, []string{"tat"}).Int(); err != nil || got != 1 {
+		t.Fatalf("runScript() = %d, %v, want 1", got, err)
+	}
+	if cache.evals != 1 {
+		t.Errorf("evals = %d, want 1", cache.evals)
+	}
+
+	// EVAL cached it again, so it runs by SHA1 alone
+	if _, err := runScript(ctx, cache, gcraScript, []string{"tat"}).Int(); err != nil {
+		t.Fatalf("runScript() error = %v", err)
+	}
+	if cache.evals != 1 {
+		t.Errorf("evals = %d, want still 1", cache.evals)
+	}
+	if got := testutil.ToFloat64(scriptReloads) - reloads; got != 1 {
+		t.Errorf("reloads = %v, want 1", got)
+	}
+}
@@ -0,0 +1,210 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/dict-simulator/go/internal/clock"
+)
+
+// windowRoll is shared by the sliding window scripts: it reads the bucket and rolls it over to the
+// window now falls in. Windows are numbered from the Unix epoch, so every replica agrees on them.
+const windowRoll = `
+	local window_key = KEYS[1]
+	local count_key = KEYS[2]
+	local previous_key = KEYS[3]
+	local window = tonumber(ARGV[1])
+	local now = tonumber(ARGV[2])
+	local initial_count = tonumber(ARGV[3])
+	local ttl = tonumber(ARGV[4])
+	local bucket_size = tonumber(ARGV[5])
+
+	local current = math.floor(now / window)
+	local start = tonumber(redis.call('GET', window_key) or current)
+	local count = tonumber(redis.call('GET', count_key) or initial_count)
+	local previous = tonumber(redis.call('GET', previous_key) or 0)
+
+	-- Only the window just before the current one still counts; any other (or one ahead of a
+	-- rewound clock) has slid out entirely
+	if start ~= current then
+		if start == current - 1 then
+			previous = count
+		else
+			previous = 0
+		end
+		count = 0
+	end
+`
+
+var (
+	// windowScript returns the tokens left in a sliding window
+	windowScript = redis.NewScript(windowRoll + `
+		redis.call('SET', window_key, current, 'EX', ttl)
+		redis.call('SET', count_key, count, 'EX', ttl)
+		redis.call('SET', previous_key, previous, 'EX', ttl)
+
+		-- The previous window counts for the share of it the sliding window still covers
+		local estimate = previous * (1 - (now - current * window) / window) + count
+		return math.max(0, math.floor(bucket_size - estimate))
+	`)
+
+	// windowDeductScript adds a cost to the current window, stopping at a full window
+	windowDeductScript = redis.NewScript(windowRoll + `
+		local cost = tonumber(ARGV[6])
+		count = math.min(bucket_size, count + cost)
+
+		redis.call('SET', window_key, current, 'EX', ttl)
+		redis.call('SET', count_key, count, 'EX', ttl)
+		redis.call('SET', previous_key, previous, 'EX', ttl)
+
+		return count
+	`)
+)
+
+// windowKey stores the number of the current window
+func windowKey(policy PolicyName, identifier string) string {
+	return key(policy, identifier) + ":window"
+}
+
+// countKey stores the tokens spent in the current window
+func countKey(policy PolicyName, identifier string) string {
+	return key(policy, identifier) + ":count"
+}
+
+// previousKey stores the tokens spent in the window before it
+func previousKey(policy PolicyName, identifier string) string {
+	return key(policy, identifier) + ":previous"
+}
+
+// windowTTL keeps a window's count for as long as the next window still weighs it
+func windowTTL(policy Policy) time.Duration {
+	return 2 * policy.stateTTL()
+}
+
+// windowArgs are the script arguments windowRoll reads
+func windowArgs(policy Policy, now time.Time) []any {
+	return []any{
+		policy.window().Milliseconds(),
+		now.UnixMilli(),
+		policy.BucketSize - policy.initialTokens(),
+		int(windowTTL(policy).Seconds()),
+		policy.BucketSize,
+	}
+}
+
+// redisSlidingWindow is the sliding window counter kept in Redis
+type redisSlidingWindow struct {
+	client *redis.Client
+	clock  clock.Clock
+}
+
+// Remaining returns the bucket size less the weighted count of the sliding window
+func (s redisSlidingWindow) Remaining(ctx context.Context, policy Policy, identifier string) (int, error) {
+	keys := []string{windowKey(policy.Name, identifier), countKey(policy.Name, identifier), previousKey(policy.Name, identifier)}
+
+	start := time.Now()
+	result, err := runScript(ctx, s.client, windowScript, keys, windowArgs(policy, s.clock.Now())...).Int()
+	scriptDuration.WithLabelValues("window").Observe(time.Since(start).Seconds())
+
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, err
+	}
+	return result, nil
+}
+
+// Deduct counts cost against the current window
+func (s redisSlidingWindow) Deduct(ctx context.Context, policy Policy, identifier string, cost int) error {
+	keys := []string{windowKey(policy.Name, identifier), countKey(policy.Name, identifier), previousKey(policy.Name, identifier)}
+
+	start := time.Now()
+	_, err := runScript(ctx, s.client, windowDeductScript, keys, append(windowArgs(policy, s.clock.Now()), cost)...).Int()
+	scriptDuration.WithLabelValues("window_deduct").Observe(time.Since(start).Seconds())
+	return err
+}
+
+// Fill starts a current window with nothing counted in it or the one before
+func (s redisSlidingWindow) Fill(ctx context.Context, policy Policy, identifier string) error {
+	ttl := windowTTL(policy)
+	current := s.clock.Now().UnixMilli() / policy.window().Milliseconds()
+
+	pipe := s.client.Pipeline()
+	pipe.Set(ctx, windowKey(policy.Name, identifier), current, ttl)
+	pipe.Set(ctx, countKey(policy.Name, identifier), 0, ttl)
+	pipe.Set(ctx, previousKey(policy.Name, identifier), 0, ttl)
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+// memorySlidingWindow mirrors redisSlidingWindow in a MemoryBucket
+type memorySlidingWindow struct {
+	b *MemoryBucket
+}
+
+// roll reads the bucket and rolls it over to the current window (windowRoll)
+// It returns the window number, the counts of that window and the one before, and the elapsed
+// share of the window. The caller holds the lock.
+func (s memorySlidingWindow) roll(policy Policy, identifier string) (current, count, previous int64, elapsed float64) {
+	b := s.b
+	window := policy.window().Milliseconds()
+	now := b.clock.Now().UnixMilli()
+
+	current = now / window
+	start := b.get(windowKey(policy.Name, identifier), current)
+	count = b.get(countKey(policy.Name, identifier), int64(policy.BucketSize-policy.initialTokens()))
+	previous = b.get(previousKey(policy.Name, identifier), 0)
+
+	if start != current {
+		if start == current-1 {
+			previous = count
+		} else {
+			previous = 0
+		}
+		count = 0
+	}
+
+	return current, count, previous, float64(now-current*window) / float64(window)
+}
+
+// store writes the rolled bucket back with a fresh TTL
+func (s memorySlidingWindow) store(policy Policy, identifier string, current, count, previous int64) {
+	ttl := windowTTL(policy)
+	s.b.set(windowKey(policy.Name, identifier), current, ttl)
+	s.b.set(countKey(policy.Name, identifier), count, ttl)
+	s.b.set(previousKey(policy.Name, identifier), previous, ttl)
+}
+
+// Remaining returns the bucket size less the weighted count of the sliding window (windowScript)
+func (s memorySlidingWindow) Remaining(ctx context.Context, policy Policy, identifier string) (int, error) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+
+	current, count, previous, elapsed := s.roll(policy, identifier)
+	s.store(policy, identifier, current, count, previous)
+
+	estimate := float64(previous)*(1-elapsed) + float64(count)
+	return int(max(0, math.Floor(float64(policy.BucketSize)-estimate))), nil
+}
+
+// Deduct counts cost against the current window (windowDeductScript)
+func (s memorySlidingWindow) Deduct(ctx context.Context, policy Policy, identifier string, cost int) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+
+	current, count, previous, _ := s.roll(policy, identifier)
+	s.store(policy, identifier, current, min(int64(policy.BucketSize), count+int64(cost)), previous)
+	return nil
+}
+
+// Fill starts a current window with nothing counted in it or the one before
+func (s memorySlidingWindow) Fill(ctx context.Context, policy Policy, identifier string) error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+
+	s.store(policy, identifier, s.b.clock.Now().UnixMilli()/policy.window().Milliseconds(), 0, 0)
+	return nil
+}
@@ -0,0 +1,241 @@
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// DefaultFields are the fields masked when REDACT_FIELDS is not set
+var DefaultFields = []string{"taxIdNumber", "key", "email", "phone", "password"}
+
+// Hidden replaces values that are masked completely
+const Hidden = "[REDACTED]"
+
+var (
+	// Shapes of the personal data found in free text, such as a URL path or an error message.
+	// EVP keys are random UUIDs and say nothing about their owner, so they are left alone.
+	// A URL path may carry the @ and + escaped.
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._+\-]+(@|%40)[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	phonePattern = regexp.MustCompile(`(\+|%2[Bb])[1-9]\d{10,13}\b`)
+	taxIDPattern = regexp.MustCompile(`\b(\d{14}|\d{11})\b`)
+)
+
+// Redactor masks tax IDs, emails, phone keys and passwords before they reach logs, traces or stored responses
+// Fields it was built with are masked by name whatever their content; free text is masked where it holds
+// something shaped like a CPF, CNPJ, email or phone key. A nil Redactor leaves everything as it is.
+type Redactor struct {
+	fields map[string]bool
+}
+
+// New returns a Redactor masking the named fields; names match case-insensitively, as JSON field names
+// and log field keys are not written alike (taxIdNumber, tax_id_number)
+func New(fields []string) *Redactor {
+	r := &Redactor{fields: make(map[string]bool, len(fields))}
+	for _, field := range fields {
+		r.fields[normalize(field)] = true
+	}
+	return r
+}
+
+// normalize folds a field name so taxIdNumber, tax_id_number and TaxIDNumber match
+func normalize(field string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "", ".", "").Replace(field))
+}
+
+// Masks reports whether field is masked by name
+func (r *Redactor) Masks(field string) bool {
+	if r == nil {
+		return false
+	}
+	// Dotted keys such as owner.taxIdNumber are matched on their last part
+	if i := strings.LastIndex(field, "."); i >= 0 {
+		field = field[i+1:]
+	}
+	return r.fields[normalize(field)]
+}
+
+// Field masks value when field is one of the redacted fields, and the sensitive parts of it otherwise
+func (r *Redactor) Field(field, value string) string {
+	if r == nil {
+		return value
+	}
+	if r.Masks(field) {
+		return r.value(field, value)
+	}
+	return r.Text(value)
+}
+
+// value masks a value of a redacted field, keeping enough of a key to tell keys apart while debugging
+func (r *Redactor) value(field, value string) string {
+	if strings.Contains(normalize(field), "password") || strings.Contains(normalize(field), "secret") {
+		return Hidden
+	}
+	switch {
+	case whole(emailPattern, value):
+		return maskEmail(value)
+	case whole(phonePattern, value):
+		return maskPhone(value)
+	case whole(taxIDPattern, value):
+		return maskTaxID(value)
+	case isUUID(value):
+		return value
+	}
+	return Hidden
+}
+
+// whole reports whether pattern matches all of s
+func whole(pattern *regexp.Regexp, s string) bool {
+	loc := pattern.FindStringIndex(s)
+	return loc != nil && loc[0] == 0 && loc[1] == len(s)
+}
+
+// Text masks everything in s shaped like a CPF, CNPJ, email or phone key
+func (r *Redactor) Text(s string) string {
+	if r == nil || s == "" {
+		return s
+	}
+	s = emailPattern.ReplaceAllStringFunc(s, maskEmail)
+	s = phonePattern.ReplaceAllStringFunc(s, maskPhone)
+	return taxIDPattern.ReplaceAllStringFunc(s, maskTaxID)
+}
+
+// JSON masks the redacted fields of a JSON document, at any depth, and the sensitive parts of its
+// other strings. Fields keep their order, so a masked response reads like the original. A body that
+// is not JSON is returned as it is.
+func (r *Redactor) JSON(body []byte) []byte {
+	if r == nil {
+		return body
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	// Each open object or array keeps whether a comma is due, for objects whether the next token
+	// is a name, and the name its values are under
+	type level struct {
+		object, comma, name bool
+		field               string
+	}
+	var (
+		out   bytes.Buffer
+		stack []level
+		field string
+	)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return body
+		}
+
+		top := len(stack) - 1
+		isName := top >= 0 && stack[top].object && stack[top].name
+		closing := token == json.Delim('}') || token == json.Delim(']')
+		if top >= 0 && !closing {
+			if stack[top].comma && (isName || !stack[top].object) {
+				out.WriteByte(',')
+			}
+			stack[top].comma = true
+			if stack[top].object {
+				stack[top].name = !isName
+			}
+			// Array items are under the name the array is
+			if !isName {
+				field = stack[top].field
+			}
+		}
+
+		switch token := token.(type) {
+		case json.Delim:
+			switch token {
+			case '{', '[':
+				stack = append(stack, level{object: token == '{', name: token == '{', field: field})
+			default:
+				stack = stack[:top]
+			}
+			out.WriteRune(rune(token))
+			if token == '{' || token == '[' {
+				continue
+			}
+		case string:
+			if isName {
+				stack[top].field = token
+				writeJSON(&out, token)
+				out.WriteByte(':')
+				continue
+			}
+			writeJSON(&out, r.Field(field, token))
+		case json.Number:
+			// Numbers under a redacted name, such as a tax ID sent unquoted, are masked as text
+			if r.Masks(field) {
+				writeJSON(&out, r.value(field, token.String()))
+			} else {
+				out.WriteString(token.String())
+			}
+		default:
+			writeJSON(&out, token)
+		}
+	}
+	return out.Bytes()
+}
+
+// writeJSON writes v encoded without escaping HTML, as the API writes its responses
+func writeJSON(out *bytes.Buffer, v any) {
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(v)
+	out.Truncate(out.Len() - 1) // Encode ends with a newline
+}
+
+// maskTaxID keeps the middle six digits of a CPF, as on Pix receipts, and the root of a CNPJ
+func maskTaxID(id string) string {
+	if len(id) == 14 {
+		return id[:8] + "******"
+	}
+	return "***" + id[3:9] + "**"
+}
+
+// maskEmail keeps the first letter of the mailbox and the domain, as in j***@example.com
+func maskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		at = strings.LastIndex(email, "%40")
+	}
+	if at < 1 {
+		return Hidden
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// maskPhone keeps the country code and the last four digits, as in +55*******4321
+func maskPhone(phone string) string {
+	prefix := len("+55")
+	if strings.HasPrefix(phone, "%") {
+		prefix = len("%2B55")
+	}
+	return phone[:prefix] + strings.Repeat("*", len(phone)-prefix-4) + phone[len(phone)-4:]
+}
+
+// isUUID reports whether s is shaped like an EVP key
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i, c := range s {
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package redact
+
+import "testing"
+
+func TestRedactorField(t *testing.T) {
+	r := New(DefaultFields)
+
+	tests := []struct {
+		field, value, want string
+	}{
+		{field: "taxIdNumber", value: "52998224725", want: "***982247**"},
+		{field: "tax_id_number", value: "11222333000181", want: "11222333******"},
+		{field: "key", value: "john.doe@example.com", want: "j***@example.com"},
+		{field: "key", value: "+5511987654321", want: "+55*******4321"},
+		{field: "key", value: "123e4567-e89b-12d3-a456-426614174000", want: "123e4567-e89b-12d3-a456-426614174000"},
+		{field: "owner.taxIdNumber", value: "52998224725", want: "***982247**"},
+		{field: "password", value: "52998224725", want: Hidden},
+		{field: "name", value: "John Doe", want: "John Doe"},
+		{field: "path", value: "/entries/52998224725", want: "/entries/***982247**"},
+		{field: "path", value: "/entries/%2B5511987654321", want: "/entries/%2B55*******4321"},
+		{field: "path", value: "/entries/john%40example.com", want: "/entries/j***%40example.com"},
+		{field: "participant", value: "12345678", want: "12345678"},
+	}
+	for _, tt := range tests {
+		if got := r.Field(tt.field, tt.value); got != tt.want {
+			t.Errorf("Field(%q, %q) = %q, want %q", tt.field, tt.value, got, tt.want)
+		}
+	}
+
+	var off *Redactor
+	if got := off.Field("taxIdNumber", "52998224725"); got != "52998224725" {
+		t.Errorf("nil Redactor Field() = %q, want the value unchanged", got)
+	}
+}
+
+func TestRedactorJSON(t *testing.T) {
+	r := New(DefaultFields)
+
+	body := `{"code":"ENTRY_CREATED","data":{"key":"+5511987654321","owner":{"name":"John Doe","taxIdNumber":"52998224725"},"tags":["a","b"],"count":2,"ok":true,"none":null},"keys":[{"key":"john@example.com"},"x"],"password":12345678901}`
+	want := `{"code":"ENTRY_CREATED","data":{"key":"+55*******4321","owner":{"name":"John Doe","taxIdNumber":"***982247**"},"tags":["a","b"],"count":2,"ok":true,"none":null},"keys":[{"key":"j***@example.com"},"x"],"password":"[REDACTED]"}`
+	if got := string(r.JSON([]byte(body))); got != want {
+		t.Errorf("JSON() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := string(r.JSON([]byte("not json"))); got != "not json" {
+		t.Errorf("JSON() of a non-JSON body = %q, want it unchanged", got)
+	}
+}
@@ -2,41 +2,142 @@ package router
 
 import (
 	"net/http"
+	"time"
 
-	httpSwagger "github.com/swaggo/http-swagger"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.uber.org/zap"
 
+	"github.com/dict-simulator/go/internal/clock"
 	"github.com/dict-simulator/go/internal/config"
+	"github.com/dict-simulator/go/internal/jwtkeys"
+	"github.com/dict-simulator/go/internal/logger"
 	"github.com/dict-simulator/go/internal/middleware"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/modules/admin"
+	"github.com/dict-simulator/go/internal/modules/apidocs"
 	"github.com/dict-simulator/go/internal/modules/auth"
 	"github.com/dict-simulator/go/internal/modules/entries"
+	"github.com/dict-simulator/go/internal/modules/files"
 	"github.com/dict-simulator/go/internal/modules/health"
+	"github.com/dict-simulator/go/internal/modules/oauth"
+	"github.com/dict-simulator/go/internal/modules/settlements"
+	"github.com/dict-simulator/go/internal/modules/webhooks"
+	"github.com/dict-simulator/go/internal/quota"
 	"github.com/dict-simulator/go/internal/ratelimit"
+	"github.com/dict-simulator/go/internal/stubs"
 	"github.com/dict-simulator/go/internal/telemetry"
-
-	// Import generated docs for Swagger
-	_ "github.com/dict-simulator/go/docs"
+	"github.com/dict-simulator/go/internal/usage"
 )
 
 // spanNames maps route patterns to custom span names (preserving current naming convention)
 var spanNames = map[string]string{
-	"GET /health":                "health",
-	"GET /swagger/":              "swagger",
-	"POST /auth/register":        "auth.register",
-	"POST /auth/login":           "auth.login",
-	"POST /entries":              "entries.create",
-	"GET /entries/{key}":         "entries.get",
-	"PUT /entries/{key}":         "entries.update",
-	"POST /entries/{key}/delete": "entries.delete",
+	"GET /health":                          "health",
+	"GET /openapi.json":                    "docs.spec",
+	"GET /docs/":                           "docs.ui",
+	"GET /swagger/":                        "docs.legacy",
+	"POST /auth/register":                  "auth.register",
+	"POST /auth/login":                     "auth.login",
+	"POST /auth/password-reset":            "auth.password_reset",
+	"POST /auth/password-reset/confirm":    "auth.password_reset.confirm",
+	"POST /auth/change-password":           "auth.change_password",
+	"POST /oauth/token":                    "oauth.token",
+	"GET /.well-known/jwks.json":           "oauth.jwks",
+	"GET /auth/me":                         "auth.me",
+	"PUT /auth/me":                         "auth.me.update",
+	"POST /entries":                        "entries.create",
+	"GET /entries/{key}":                   "entries.get",
+	"PUT /entries/{key}":                   "entries.update",
+	"POST /entries/{key}/delete":           "entries.delete",
+	"GET /entries/{key}/fraud-markers":     "entries.fraud_markers",
+	"GET /entries/{key}/ownership-history": "entries.ownership_history",
+	"POST /keys/validate":                  "keys.validate",
+	"POST /accounts/{participant}/{branch}/{accountNumber}/close": "accounts.close",
+	"GET /participants/{ispb}/entries":                            "participants.entries",
+	"POST /webhooks":                                              "webhooks.create",
+	"GET /webhooks":                                               "webhooks.list",
+	"DELETE /webhooks/{id}":                                       "webhooks.delete",
+	"GET /webhooks/{id}/deliveries":                               "webhooks.deliveries",
+	"POST /settlements":                                           "settlements.create",
+	"GET /settlements/{endToEndId}":                               "settlements.get",
+	"GET /files":                                                  "files.list",
+	"GET /files/{id}":                                             "files.download",
+	"POST /admin/seed":                                            "admin.seed",
+	"POST /admin/reset":                                           "admin.reset",
+	"POST /admin/erasure/{taxId}":                                 "admin.erasure",
+	"GET /admin/export":                                           "admin.export",
+	"POST /admin/import":                                          "admin.import",
+	"GET /admin/faults":                                           "admin.faults.list",
+	"POST /admin/faults":                                          "admin.faults.create",
+	"DELETE /admin/faults":                                        "admin.faults.clear",
+	"DELETE /admin/faults/{id}":                                   "admin.faults.delete",
+	"GET /admin/stubs":                                            "admin.stubs.list",
+	"POST /admin/stubs":                                           "admin.stubs.create",
+	"DELETE /admin/stubs":                                         "admin.stubs.clear",
+	"DELETE /admin/stubs/{id}":                                    "admin.stubs.delete",
+	"GET /admin/brownout":                                         "admin.brownout.get",
+	"PUT /admin/brownout":                                         "admin.brownout.start",
+	"DELETE /admin/brownout":                                      "admin.brownout.stop",
+	"GET /admin/time":                                             "admin.time.get",
+	"POST /admin/time/advance":                                    "admin.time.advance",
+	"DELETE /admin/time":                                          "admin.time.reset",
+	"POST /admin/config/reload":                                   "admin.config.reload",
+	"GET /admin/log-level":                                        "admin.log_level.get",
+	"PUT /admin/log-level":                                        "admin.log_level.set",
+	"GET /admin/key-policies":                                     "admin.key_policies.get",
+	"PUT /admin/key-policies":                                     "admin.key_policies.set",
+	"GET /admin/quotas":                                           "admin.quotas.list",
+	"PUT /admin/quotas":                                           "admin.quotas.set_default",
+	"GET /admin/quotas/{participant}":                             "admin.quotas.get",
+	"PUT /admin/quotas/{participant}":                             "admin.quotas.set",
+	"DELETE /admin/quotas/{participant}":                          "admin.quotas.delete",
+	"POST /admin/users/{id}/unlock":                               "admin.users.unlock",
+	"GET /admin/users/{id}/password-reset":                        "admin.users.password_reset",
+	"GET /admin/users":                                            "admin.users.list",
+	"POST /admin/users/{id}/disable":                              "admin.users.disable",
+	"POST /admin/users/{id}/enable":                               "admin.users.enable",
+	"DELETE /admin/users/{id}":                                    "admin.users.delete",
+	"GET /admin/oauth/clients":                                    "admin.oauth_clients.list",
+	"POST /admin/oauth/clients":                                   "admin.oauth_clients.create",
+	"DELETE /admin/oauth/clients/{id}":                            "admin.oauth_clients.delete",
+	"GET /admin/audit":                                            "admin.audit.list",
+	"GET /admin/reports/usage":                                    "admin.reports.usage",
 }
 
+// routeName returns the span name of the route r was matched to, or its pattern when it has none
+func routeName(r *http.Request) string {
+	if name, ok := spanNames[r.Pattern]; ok {
+		return name
+	}
+	return r.Pattern
+}
+
+// unprefixedDeprecated is when the routes outside /api/{version} were deprecated
+var unprefixedDeprecated = time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC)
+
 // Setup creates and configures the HTTP router with all routes
+// clk stamps ResponseTime; keys verify bearer tokens; users and clients are checked for each bearer token, so deleted and disabled users
+// and deleted OAuth clients are refused; audits records the actions taken through the admin routes;
+// usage counts each participant's requests for the usage reports; stubRegistry holds the stubs served instead of the handlers;
+// quotas caps each participant's requests on the DICT routes;
 // policies parameter allows injecting custom rate limiting policies for testing
 func Setup(
 	cfg *config.Config,
+	clk clock.Clock,
+	keys *jwtkeys.KeySet,
+	users models.UserRepository,
+	clients models.OAuthClientRepository,
+	audits models.AdminAuditRepository,
+	usage *usage.Recorder,
 	authHandler *auth.Handler,
+	oauthHandler *oauth.Handler,
 	entriesHandler *entries.Handler,
+	webhooksHandler *webhooks.Handler,
+	settlementsHandler *settlements.Handler,
+	filesHandler *files.Handler,
+	adminHandler *admin.Handler,
 	mwManager *middleware.Manager,
+	stubRegistry *stubs.Registry,
+	quotas *quota.Store,
 	policies map[ratelimit.PolicyName]ratelimit.Policy,
 ) http.Handler {
 	mux := http.NewServeMux()
@@ -46,41 +147,143 @@ func Setup(
 
 	// Health and metrics endpoints
 	mux.HandleFunc("GET /health", healthHandler.Health)
-	mux.Handle("GET /metrics", healthHandler.Metrics())
+	// With METRICS_EXPORTER=otlp metrics are pushed instead of scraped (an empty exporter means Prometheus)
+	if cfg.MetricsExporter != config.MetricsExporterOTLP {
+		mux.Handle("GET /metrics", healthHandler.Metrics())
+	}
 
-	// Swagger documentation endpoint
-	mux.Handle("GET /swagger/", httpSwagger.Handler(
-		httpSwagger.URL("/swagger/doc.json"), // The url pointing to API definition
-		httpSwagger.DeepLinking(true),
-		httpSwagger.DocExpansion("none"),
-		httpSwagger.DomID("swagger-ui"),
-	))
+	// API documentation - the generated spec and Swagger UI, off by default in production
+	if cfg.DocsEnabled {
+		docsHandler := apidocs.NewHandler()
+		mux.HandleFunc("GET "+apidocs.SpecPath, docsHandler.Spec)
+		mux.Handle("GET /docs/", docsHandler.UI())
+
+		// Old location of the UI, kept so existing links keep working
+		mux.Handle("GET /swagger/", http.RedirectHandler("/docs/", http.StatusMovedPermanently))
+	}
+
+	// Bearer token check shared by the participant routes
+	// It accepts user tokens and OAuth client tokens; RequireScope then limits client tokens to their granted scopes
+	requireUser := middleware.AuthMiddleware(keys, users, clients, cfg.AdminToken)
+	requireEntriesRead := middleware.RequireScope(models.ScopeEntriesRead)
+	requireEntriesWrite := middleware.RequireScope(models.ScopeEntriesWrite)
+	requireReconciliation := middleware.RequireScope(models.ScopeReconciliation)
+	requireWebhooks := middleware.RequireScope(models.ScopeWebhooks)
+	requireSettlements := middleware.RequireScope(models.ScopeSettlements)
+
+	// Stubs added via /admin/stubs answer before authentication, so they can stand in for any response
+	serveStubs := middleware.Stubs(stubRegistry)
+
+	// Participants' request quotas (/admin/quotas) apply on top of the DICT policies, once the participant is known
+	requestQuota := mwManager.RequestQuota(quotas)
 
 	// Auth routes (no auth middleware)
-	mux.HandleFunc("POST /auth/register", authHandler.Register)
-	mux.HandleFunc("POST /auth/login", authHandler.Login)
+	// Limited per client address first, so brute force is refused before any password is checked
+	mux.Handle("POST /auth/register", middleware.Chain(
+		http.HandlerFunc(authHandler.Register),
+		mwManager.IPRateLimit,
+		mwManager.LatencyProfile,
+		mwManager.FaultInjection,
+		serveStubs,
+	))
+	mux.Handle("POST /auth/login", middleware.Chain(
+		http.HandlerFunc(authHandler.Login),
+		mwManager.IPRateLimit,
+		mwManager.LatencyProfile,
+		mwManager.FaultInjection,
+		serveStubs,
+	))
+	mux.Handle("POST /auth/password-reset", middleware.Chain(
+		http.HandlerFunc(authHandler.RequestPasswordReset),
+		mwManager.IPRateLimit,
+		mwManager.LatencyProfile,
+		mwManager.FaultInjection,
+		serveStubs,
+	))
+	mux.Handle("POST /auth/password-reset/confirm", middleware.Chain(
+		http.HandlerFunc(authHandler.ConfirmPasswordReset),
+		mwManager.IPRateLimit,
+		mwManager.LatencyProfile,
+		mwManager.FaultInjection,
+		serveStubs,
+	))
+	// Checks the current password, so it is limited like a login even though it needs a token
+	mux.Handle("POST /auth/change-password", middleware.Chain(
+		http.HandlerFunc(authHandler.ChangePassword),
+		mwManager.IPRateLimit,
+		mwManager.LatencyProfile,
+		mwManager.FaultInjection,
+		serveStubs,
+		requireUser,
+	))
+
+	// OAuth2 token endpoint for machine clients - client credentials are checked like a password
+	mux.Handle("POST /oauth/token", middleware.Chain(
+		http.HandlerFunc(oauthHandler.Token),
+		mwManager.IPRateLimit,
+		mwManager.LatencyProfile,
+		mwManager.FaultInjection,
+		serveStubs,
+	))
+	// Public keys for verifying tokens without the simulator; fetched by resource servers, so never faulted
+	mux.HandleFunc("GET /.well-known/jwks.json", oauthHandler.JWKS)
+
+	// The caller's own profile
+	mux.Handle("GET /auth/me", middleware.Chain(
+		http.HandlerFunc(authHandler.Me),
+		mwManager.LatencyProfile,
+		mwManager.FaultInjection,
+		serveStubs,
+		requireUser,
+	))
+	mux.Handle("PUT /auth/me", middleware.Chain(
+		http.HandlerFunc(authHandler.UpdateMe),
+		mwManager.LatencyProfile,
+		mwManager.FaultInjection,
+		serveStubs,
+		requireUser,
+	))
 
 	// Entries routes with per-method rate limiting policies
+	// Request signatures (REQUEST_SIGNING_ENABLED) are checked after the bearer token, before rate limiting
 	// POST /entries - createEntry uses ENTRIES_WRITE policy (1200/min, 36000 bucket)
 	mux.Handle("POST /entries", middleware.Chain(
 		http.HandlerFunc(entriesHandler.Create),
-		middleware.AuthMiddleware(cfg.JWTSecret),
+		mwManager.LatencyProfile,
+		mwManager.FaultInjection,
+		serveStubs,
+		requireUser,
+		requireEntriesWrite,
+		mwManager.RequestSignature,
+		requestQuota,
 		mwManager.RateLimiterWithPolicy(policies[ratelimit.PolicyEntriesWrite]),
-		mwManager.Idempotency,
+		mwManager.Idempotency(middleware.NewIdempotencyPolicy(cfg)),
 	))
 
 	// GET /entries/{key} - getEntry uses ENTRIES_READ_PARTICIPANT_ANTISCAN policy
 	// Category H: 2/min, 50 bucket, 404 costs 3 tokens
 	mux.Handle("GET /entries/{key}", middleware.Chain(
 		http.HandlerFunc(entriesHandler.Get),
-		middleware.AuthMiddleware(cfg.JWTSecret),
+		mwManager.LatencyProfile,
+		mwManager.FaultInjection,
+		serveStubs,
+		requireUser,
+		requireEntriesRead,
+		mwManager.RequestSignature,
+		requestQuota,
 		mwManager.RateLimiterWithPolicy(policies[ratelimit.PolicyEntriesReadParticipant]),
 	))
 
 	// PUT /entries/{key} - updateEntry uses ENTRIES_UPDATE policy (600/min, 600 bucket)
 	mux.Handle("PUT /entries/{key}", middleware.Chain(
 		http.HandlerFunc(entriesHandler.Update),
-		middleware.AuthMiddleware(cfg.JWTSecret),
+		mwManager.LatencyProfile,
+		mwManager.FaultInjection,
+		serveStubs,
+		requireUser,
+		requireEntriesWrite,
+		mwManager.RequestSignature,
+		requestQuota,
 		mwManager.RateLimiterWithPolicy(policies[ratelimit.PolicyEntriesUpdate]),
 	))
 
@@ -88,14 +291,401 @@ func Setup(
 	// Per DICT spec: uses POST method with request body instead of DELETE
 	mux.Handle("POST /entries/{key}/delete", middleware.Chain(
 		http.HandlerFunc(entriesHandler.Delete),
-		middleware.AuthMiddleware(cfg.JWTSecret),
+		mwManager.LatencyProfile,
+		mwManager.FaultInjection,
+		serveStubs,
+		requireUser,
+		requireEntriesWrite,
+		mwManager.RequestSignature,
+		requestQuota,
 		mwManager.RateLimiterWithPolicy(policies[ratelimit.PolicyEntriesWrite]),
 	))
 
-	// Wrap with global middlewares: metrics -> logging -> CORS -> routes
-	innerHandler := middleware.MetricsMiddleware(
-		middleware.LoggingMiddleware(
-			middleware.CORSMiddleware(mux),
+	// GET /entries/{key}/fraud-markers - reads like getEntry, so it shares the antiscan read bucket
+	mux.Handle("GET /entries/{key}/fraud-markers", middleware.Chain(
+		http.HandlerFunc(entriesHandler.FraudMarkers),
+		mwManager.LatencyProfile,
+		mwManager.FaultInjection,
+		serveStubs,
+		requireUser,
+		requireEntriesRead,
+		mwManager.RequestSignature,
+		requestQuota,
+		mwManager.RateLimiterWithPolicy(policies[ratelimit.PolicyEntriesReadParticipant]),
+	))
+
+	// GET /entries/{key}/ownership-history - reads like getEntry, so it shares the antiscan read bucket
+	mux.Handle("GET /entries/{key}/ownership-history", middleware.Chain(
+		http.HandlerFunc(entriesHandler.OwnershipHistory),
+		mwManager.LatencyProfile,
+		mwManager.FaultInjection,
+		serveStubs,
+		requireUser,
+		requireEntriesRead,
+		mwManager.RequestSignature,
+		requestQuota,
+		mwManager.RateLimiterWithPolicy(policies[ratelimit.PolicyEntriesReadParticipant]),
+	))
+
+	// POST /keys/validate - dry run of the createEntry key checks
+	// It reveals whether a key is registered, so it shares the antiscan read bucket
+	mux.Handle("POST /keys/validate", middleware.Chain(
+		http.HandlerFunc(entriesHandler.Validate),
+		mwManager.LatencyProfile,
+		mwManager.FaultInjection,
+		serveStubs,
+		requireUser,
+		requireEntriesRead,
+		mwManager.RequestSignature,
+		requestQuota,
+		mwManager.RateLimiterWithPolicy(policies[ratelimit.PolicyEntriesReadParticipant]),
+	))
+
+	// POST /accounts/{participant}/{branch}/{accountNumber}/close - bulk delete or transfer of an account's keys
+	// It deletes like POST /entries/{key}/delete, so it shares the ENTRIES_WRITE bucket
+	mux.Handle("POST /accounts/{participant}/{branch}/{accountNumber}/close", middleware.Chain(
+		http.HandlerFunc(entriesHandler.CloseAccount),
+		mwManager.LatencyProfile,
+		mwManager.FaultInjection,
+		serveStubs,
+		requireUser,
+		requireEntriesWrite,
+		mwManager.RequestSignature,
+		requestQuota,
+		mwManager.RateLimiterWithPolicy(policies[ratelimit.PolicyEntriesWrite]),
+	))
+
+	// GET /participants/{ispb}/entries - paged listing for reconciliation, one token per page
+	mux.Handle("GET /participants/{ispb}/entries", middleware.Chain(
+		http.HandlerFunc(entriesHandler.ListByParticipant),
+		mwManager.LatencyProfile,
+		mwManager.FaultInjection,
+		serveStubs,
+		requireUser,
+		requireReconciliation,
+		mwManager.RequestSignature,
+		requestQuota,
+		mwManager.RateLimiterWithPolicy(policies[ratelimit.PolicyEntriesList]),
+	))
+
+	// Webhook routes - participants register callbacks for directory events
+	mux.Handle("POST /webhooks", middleware.Chain(
+		http.HandlerFunc(webhooksHandler.Create),
+		requireUser,
+		requireWebhooks,
+	))
+	mux.Handle("GET /webhooks", middleware.Chain(
+		http.HandlerFunc(webhooksHandler.List),
+		requireUser,
+		requireWebhooks,
+	))
+	mux.Handle("DELETE /webhooks/{id}", middleware.Chain(
+		http.HandlerFunc(webhooksHandler.Delete),
+		requireUser,
+		requireWebhooks,
+	))
+	mux.Handle("GET /webhooks/{id}/deliveries", middleware.Chain(
+		http.HandlerFunc(webhooksHandler.Deliveries),
+		requireUser,
+		requireWebhooks,
+	))
+
+	// Settlement routes - test payments that other flows reference by end-to-end ID
+	mux.Handle("POST /settlements", middleware.Chain(
+		http.HandlerFunc(settlementsHandler.Create),
+		requireUser,
+		requireSettlements,
+	))
+	mux.Handle("GET /settlements/{endToEndId}", middleware.Chain(
+		http.HandlerFunc(settlementsHandler.Get),
+		requireUser,
+		requireSettlements,
+	))
+
+	// Reconciliation file routes - daily files generated by the scheduler
+	mux.Handle("GET /files", middleware.Chain(
+		http.HandlerFunc(filesHandler.List),
+		requireUser,
+		requireReconciliation,
+	))
+	mux.Handle("GET /files/{id}", middleware.Chain(
+		http.HandlerFunc(filesHandler.Download),
+		requireUser,
+		requireReconciliation,
+	))
+
+	// Admin routes - only mounted when enabled, since they can mass-mutate the directory
+	if cfg.AdminEnabled {
+		// Admin routes take the shared ADMIN_TOKEN rather than a user JWT, since anyone can register
+		if cfg.AdminToken == "" {
+			logger.Warn("ADMIN_TOKEN is not set; the admin routes will refuse every request")
+		}
+		// Every action is recorded once authenticated, under the route's span name
+		requireAdmin := middleware.AdminAuth(cfg.AdminToken, requireUser)
+		audit := mwManager.AdminAudit(audits, routeName)
+		adminAuth := func(next http.Handler) http.Handler { return requireAdmin(audit(next)) }
+
+		// POST /admin/seed - bulk-generate realistic entries for load tests and demos
+		mux.Handle("POST /admin/seed", middleware.Chain(
+			http.HandlerFunc(adminHandler.Seed),
+			adminAuth,
+		))
+
+		// POST /admin/reset - drop one participant's data between test suites
+		mux.Handle("POST /admin/reset", middleware.Chain(
+			http.HandlerFunc(adminHandler.Reset),
+			adminAuth,
+		))
+
+		// POST /admin/erasure/{taxId} - remove a person's or company's data, as an LGPD erasure request would
+		mux.Handle("POST /admin/erasure/{taxId}", middleware.Chain(
+			http.HandlerFunc(adminHandler.Erase),
+			adminAuth,
+		))
+
+		// Snapshots of the directory, for loading the same dataset in any environment
+		mux.Handle("GET /admin/export", middleware.Chain(
+			http.HandlerFunc(adminHandler.Export),
+			adminAuth,
+		))
+		// Snapshots are far larger than other payloads, so imports are capped by MAX_IMPORT_BYTES instead of MAX_BODY_BYTES
+		importMiddlewares := []func(http.Handler) http.Handler{adminAuth}
+		if cfg.MaxImportBytes > 0 {
+			importMiddlewares = append(importMiddlewares, middleware.BodyLimit(int64(cfg.MaxImportBytes)))
+		}
+		mux.Handle("POST /admin/import", middleware.Chain(http.HandlerFunc(adminHandler.Import), importMiddlewares...))
+
+		// POST /admin/users/{id}/unlock - lift an account lockout before it runs out
+		mux.Handle("POST /admin/users/{id}/unlock", middleware.Chain(
+			http.HandlerFunc(adminHandler.UnlockUser),
+			adminAuth,
+		))
+
+		// GET /admin/users/{id}/password-reset - the token a reset email would carry, since none is sent
+		mux.Handle("GET /admin/users/{id}/password-reset", middleware.Chain(
+			http.HandlerFunc(adminHandler.GetPasswordReset),
+			adminAuth,
+		))
+
+		// User management - list accounts, disable them without losing their data, or delete them
+		mux.Handle("GET /admin/users", middleware.Chain(
+			http.HandlerFunc(adminHandler.ListUsers),
+			adminAuth,
+		))
+		mux.Handle("POST /admin/users/{id}/disable", middleware.Chain(
+			http.HandlerFunc(adminHandler.DisableUser),
+			adminAuth,
+		))
+		mux.Handle("POST /admin/users/{id}/enable", middleware.Chain(
+			http.HandlerFunc(adminHandler.EnableUser),
+			adminAuth,
+		))
+		mux.Handle("DELETE /admin/users/{id}", middleware.Chain(
+			http.HandlerFunc(adminHandler.DeleteUser),
+			adminAuth,
+		))
+
+		// OAuth clients - machine credentials for the client_credentials grant, one participant each
+		mux.Handle("GET /admin/oauth/clients", middleware.Chain(
+			http.HandlerFunc(adminHandler.ListOAuthClients),
+			adminAuth,
+		))
+		mux.Handle("POST /admin/oauth/clients", middleware.Chain(
+			http.HandlerFunc(adminHandler.CreateOAuthClient),
+			adminAuth,
+		))
+		mux.Handle("DELETE /admin/oauth/clients/{id}", middleware.Chain(
+			http.HandlerFunc(adminHandler.DeleteOAuthClient),
+			adminAuth,
+		))
+
+		// Fault injection rules applied to the DICT routes above
+		// Admin routes never get FaultInjection so faults can always be removed
+		mux.Handle("GET /admin/faults", middleware.Chain(
+			http.HandlerFunc(adminHandler.ListFaults),
+			adminAuth,
+		))
+		mux.Handle("POST /admin/faults", middleware.Chain(
+			http.HandlerFunc(adminHandler.CreateFault),
+			adminAuth,
+		))
+		mux.Handle("DELETE /admin/faults", middleware.Chain(
+			http.HandlerFunc(adminHandler.ClearFaults),
+			adminAuth,
+		))
+		mux.Handle("DELETE /admin/faults/{id}", middleware.Chain(
+			http.HandlerFunc(adminHandler.DeleteFault),
+			adminAuth,
+		))
+
+		// Stubs answering matching DICT requests with canned responses, through serveStubs
+		// Admin routes are never stubbed, so stubs can always be removed
+		mux.Handle("GET /admin/stubs", middleware.Chain(
+			http.HandlerFunc(adminHandler.ListStubs),
+			adminAuth,
+		))
+		mux.Handle("POST /admin/stubs", middleware.Chain(
+			http.HandlerFunc(adminHandler.CreateStub),
+			adminAuth,
+		))
+		mux.Handle("DELETE /admin/stubs", middleware.Chain(
+			http.HandlerFunc(adminHandler.ClearStubs),
+			adminAuth,
+		))
+		mux.Handle("DELETE /admin/stubs/{id}", middleware.Chain(
+			http.HandlerFunc(adminHandler.DeleteStub),
+			adminAuth,
+		))
+
+		// Brownout ramping a share of the DICT routes' requests into 503s and delays, through FaultInjection
+		mux.Handle("GET /admin/brownout", middleware.Chain(
+			http.HandlerFunc(adminHandler.GetBrownout),
+			adminAuth,
+		))
+		mux.Handle("PUT /admin/brownout", middleware.Chain(
+			http.HandlerFunc(adminHandler.StartBrownout),
+			adminAuth,
+		))
+		mux.Handle("DELETE /admin/brownout", middleware.Chain(
+			http.HandlerFunc(adminHandler.StopBrownout),
+			adminAuth,
+		))
+
+		// Simulated clock used by entries, idempotency expiry and rate limit refills, off by default in production
+		if cfg.TimeTravelEnabled {
+			mux.Handle("GET /admin/time", middleware.Chain(
+				http.HandlerFunc(adminHandler.GetTime),
+				adminAuth,
+			))
+			mux.Handle("POST /admin/time/advance", middleware.Chain(
+				http.HandlerFunc(adminHandler.AdvanceTime),
+				adminAuth,
+			))
+			mux.Handle("DELETE /admin/time", middleware.Chain(
+				http.HandlerFunc(adminHandler.ResetTime),
+				adminAuth,
+			))
+		}
+
+		// Applies rate limiting, latency profiles and FAULT_RULES from the current configuration
+		mux.Handle("POST /admin/config/reload", middleware.Chain(
+			http.HandlerFunc(adminHandler.ReloadConfig),
+			adminAuth,
+		))
+
+		// Minimum log level, raised or lowered at runtime while chasing an issue
+		mux.Handle("GET /admin/log-level", middleware.Chain(
+			http.HandlerFunc(adminHandler.GetLogLevel),
+			adminAuth,
+		))
+		mux.Handle("PUT /admin/log-level", middleware.Chain(
+			http.HandlerFunc(adminHandler.SetLogLevel),
+			adminAuth,
+		))
+
+		// Key types that can be created and whether phones must be Brazilian, mimicking a production policy
+		mux.Handle("GET /admin/key-policies", middleware.Chain(
+			http.HandlerFunc(adminHandler.GetKeyPolicies),
+			adminAuth,
+		))
+		mux.Handle("PUT /admin/key-policies", middleware.Chain(
+			http.HandlerFunc(adminHandler.SetKeyPolicies),
+			adminAuth,
+		))
+
+		// Participants' quotas of stored entries and requests, so one team can't fill a shared simulator
+		mux.Handle("GET /admin/quotas", middleware.Chain(
+			http.HandlerFunc(adminHandler.ListQuotas),
+			adminAuth,
+		))
+		mux.Handle("PUT /admin/quotas", middleware.Chain(
+			http.HandlerFunc(adminHandler.SetDefaultQuota),
+			adminAuth,
+		))
+		mux.Handle("GET /admin/quotas/{participant}", middleware.Chain(
+			http.HandlerFunc(adminHandler.GetQuota),
+			adminAuth,
+		))
+		mux.Handle("PUT /admin/quotas/{participant}", middleware.Chain(
+			http.HandlerFunc(adminHandler.SetQuota),
+			adminAuth,
+		))
+		mux.Handle("DELETE /admin/quotas/{participant}", middleware.Chain(
+			http.HandlerFunc(adminHandler.DeleteQuota),
+			adminAuth,
+		))
+
+		// GET /admin/audit - who did what through the routes above
+		mux.Handle("GET /admin/audit", middleware.Chain(
+			http.HandlerFunc(adminHandler.ListAudit),
+			adminAuth,
+		))
+
+		// GET /admin/reports/usage - each participant's requests, errors and tokens per day
+		mux.Handle("GET /admin/reports/usage", middleware.Chain(
+			http.HandlerFunc(adminHandler.UsageReport),
+			adminAuth,
+		))
+	}
+
+	// Contract checks against the published document - wraps the mux directly so r.Pattern is set
+	var routes http.Handler = mux
+	if cfg.OpenAPIValidation {
+		spec, err := apidocs.LoadSpec()
+		if err != nil {
+			logger.Fatal("Failed to load OpenAPI document", zap.Error(err))
+		}
+		// Snapshots are streamed, so buffering them for the checks would defeat the point
+		routes = middleware.OpenAPIValidation(spec,
+			"GET "+apidocs.SpecPath,
+			"GET /docs/",
+			"GET /swagger/",
+			"GET /admin/export",
+			"POST /admin/import",
+		)(mux)
+	}
+
+	// Unknown paths and wrong methods answer in the API's error format, before the contract checks
+	routes = middleware.RouteFallback(mux)(routes)
+
+	// Bodies are capped before anything reads them, including the contract checks
+	// Configurations built in code (rather than parsed) may leave the limit at 0, meaning none.
+	// /admin/import is capped by its own route instead.
+	if cfg.MaxBodyBytes > 0 {
+		routes = middleware.BodyLimit(int64(cfg.MaxBodyBytes), "POST /admin/import")(routes)
+	}
+
+	// Compression sits outside the contract checks, which must see the uncompressed body
+	if cfg.CompressionEnabled {
+		routes = middleware.Compression(cfg.CompressionMinBytes)(routes)
+	}
+
+	// Every API route is also served under /api/v1, which is where clients should call it; the
+	// unprefixed paths keep the v1 contract but announce their deprecation. A breaking change
+	// ships as a new version here, with handlers branching on httputil.APIVersion.
+	routes = middleware.Versioning(
+		[]middleware.APIVersion{{Name: "v1"}},
+		middleware.APIVersion{Name: "v1", Deprecated: unprefixedDeprecated, Sunset: cfg.UnversionedSunset},
+		"/health", "/metrics", apidocs.SpecPath, "/docs/", "/swagger/", "/.well-known/jwks.json",
+	)(routes)
+
+	// Wrap with global middlewares: metrics -> correlation ID -> request ID -> client IP -> clock -> logging -> usage -> recovery -> CORS -> compression -> body limit -> route fallback -> OpenAPI validation -> routes
+	// Recovery sits inside logging, usage and metrics so recovered panics are counted as the 500s they return
+	innerHandler := middleware.MetricsMiddleware(cfg.MetricsRoutes)(
+		middleware.CorrelationID(
+			middleware.RequestID(
+				mwManager.ClientIP(
+					middleware.Clock(clk)(
+						middleware.AccessLog(cfg.AccessLogSampleRate)(
+							middleware.UsageReport(usage)(
+								middleware.Recovery(
+									middleware.CORSMiddleware(routes),
+								),
+							),
+						),
+					),
+				),
+			),
 		),
 	)
 
@@ -121,6 +711,9 @@ func Setup(
 	if telemetry.TracerProvider != nil {
 		otelOptions = append(otelOptions, otelhttp.WithTracerProvider(telemetry.TracerProvider))
 	}
+	if telemetry.MeterProvider != nil {
+		otelOptions = append(otelOptions, otelhttp.WithMeterProvider(telemetry.MeterProvider))
+	}
 
 	handler := otelhttp.NewHandler(
 		innerHandler,
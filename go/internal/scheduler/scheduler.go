@@ -0,0 +1,140 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/dict-simulator/go/internal/logger"
+)
+
+var (
+	runsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "scheduler_job_runs_total",
+			Help: "Total number of background job runs by job and result (success, error)",
+		},
+		[]string{"job", "result"},
+	)
+
+	runDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "scheduler_job_duration_seconds",
+			Help:    "Background job run duration in seconds",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"job"},
+	)
+
+	lastSuccess = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "scheduler_job_last_success_timestamp_seconds",
+			Help: "Unix time of the last successful run of each background job",
+		},
+		[]string{"job"},
+	)
+)
+
+// Func is the work of a job; an error is logged and counted, and the job runs again on its next tick
+type Func func(ctx context.Context) error
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      Func
+}
+
+// Scheduler runs background jobs at fixed intervals
+// Each job runs once when the scheduler starts and then every interval, on its own goroutine, so a
+// slow job delays only itself; ticks missed while a run is in progress are dropped rather than queued.
+// Intervals follow the wall clock, not the simulated clock.
+// Jobs run on every replica and must be safe to run concurrently with other replicas.
+type Scheduler struct {
+	mu   sync.Mutex
+	jobs []job
+}
+
+// New creates a scheduler with no jobs
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every registers fn to run every interval
+// Panics if interval is not positive, as time.NewTicker would.
+func (s *Scheduler) Every(name string, interval time.Duration, fn Func) {
+	if interval <= 0 {
+		panic(fmt.Sprintf("scheduler: job %q needs a positive interval", name))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, job{name: name, interval: interval, run: fn})
+}
+
+// Run runs the registered jobs until ctx is cancelled, then waits for in-progress runs to return
+// Jobs registered after Run is called are not started.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.mu.Lock()
+	jobs := s.jobs
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, j := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, j)
+		}()
+	}
+	wg.Wait()
+}
+
+// loop runs one job on its interval until ctx is cancelled
+func (s *Scheduler) loop(ctx context.Context, j job) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		s.runOnce(ctx, j)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce runs a job and records the outcome; a panicking job is logged and counted as an error
+func (s *Scheduler) runOnce(ctx context.Context, j job) {
+	start := time.Now()
+
+	err := func() (err error) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				err = fmt.Errorf("panic: %v", rec)
+			}
+		}()
+		return j.run(ctx)
+	}()
+
+	runDuration.WithLabelValues(j.name).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		// Runs interrupted by shutdown are not failures
+		if ctx.Err() != nil {
+			return
+		}
+		runsTotal.WithLabelValues(j.name, "error").Inc()
+		logger.Error("Background job failed", zap.String("job", j.name), zap.Error(err))
+		return
+	}
+
+	runsTotal.WithLabelValues(j.name, "success").Inc()
+	lastSuccess.WithLabelValues(j.name).SetToCurrentTime()
+}
@@ -0,0 +1,72 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSchedulerRunsJobsUntilCancelled(t *testing.T) {
+	s := New()
+
+	var fast, failing, panicking atomic.Int32
+	s.Every("fast", 10*time.Millisecond, func(context.Context) error {
+		fast.Add(1)
+		return nil
+	})
+	s.Every("failing", 10*time.Millisecond, func(context.Context) error {
+		failing.Add(1)
+		return errors.New("boom")
+	})
+	s.Every("panicking", 10*time.Millisecond, func(context.Context) error {
+		panicking.Add(1)
+		panic("boom")
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	s.Run(ctx) // returns once ctx is done and every job has stopped
+
+	// Failures and panics don't stop a job from running on its next tick
+	for name, runs := range map[string]int32{"fast": fast.Load(), "failing": failing.Load(), "panicking": panicking.Load()} {
+		if runs < 3 {
+			t.Errorf("job %s ran %d times, want at least 3", name, runs)
+		}
+	}
+}
+
+func TestSchedulerRunsJobOnStart(t *testing.T) {
+	s := New()
+
+	ran := make(chan struct{}, 1)
+	s.Every("hourly", time.Hour, func(context.Context) error {
+		ran <- struct{}{}
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Run(ctx)
+	}()
+
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Errorf("job did not run when the scheduler started")
+	}
+	cancel()
+	<-done
+}
+
+func TestEveryRejectsNonPositiveInterval(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Every accepted a zero interval")
+		}
+	}()
+	New().Every("broken", 0, func(context.Context) error { return nil })
+}
@@ -0,0 +1,375 @@
+package seed
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// ParticipantShare assigns a relative weight to a participant (ISPB) in generated data
+type ParticipantShare struct {
+	ISPB   string `json:"ispb" validate:"required,len=8,numeric" example:"60701190"`
+	Weight int    `json:"weight" validate:"required,min=1" example:"3"`
+}
+
+// Options configures the entry generator
+type Options struct {
+	KeyTypes     map[models.KeyType]int // relative weight per key type, defaults to DefaultKeyTypes
+	Participants []ParticipantShare     // participant distribution, defaults to DefaultParticipants
+	Seed         uint64                 // zero picks a random seed
+	Now          time.Time              // opening dates are drawn from the ten years before it; zero means the current time
+}
+
+// keyTypeOrder fixes iteration order so the same seed always yields the same data
+var keyTypeOrder = []models.KeyType{
+	models.KeyTypeCPF,
+	models.KeyTypeCNPJ,
+	models.KeyTypeEMAIL,
+	models.KeyTypePHONE,
+	models.KeyTypeEVP,
+}
+
+// DefaultKeyTypes returns a key type mix loosely based on the real directory
+func DefaultKeyTypes() map[models.KeyType]int {
+	return map[models.KeyType]int{
+		models.KeyTypeCPF:   35,
+		models.KeyTypeCNPJ:  5,
+		models.KeyTypeEMAIL: 20,
+		models.KeyTypePHONE: 25,
+		models.KeyTypeEVP:   15,
+	}
+}
+
+// DefaultParticipants returns a handful of large participants with uneven market share
+func DefaultParticipants() []ParticipantShare {
+	return []ParticipantShare{
+		{ISPB: "00000000", Weight: 25}, // Banco do Brasil
+		{ISPB: "60701190", Weight: 20}, // Itaú Unibanco
+		{ISPB: "60746948", Weight: 20}, // Bradesco
+		{ISPB: "00360305", Weight: 15}, // Caixa Econômica Federal
+		{ISPB: "90400888", Weight: 10}, // Santander
+		{ISPB: "18236120", Weight: 10}, // Nu Pagamentos
+	}
+}
+
+var (
+	firstNames = []string{
+		"Ana", "Bruno", "Carla", "Daniel", "Eduarda", "Felipe", "Gabriela", "Henrique",
+		"Isabela", "João", "Larissa", "Marcos", "Natália", "Otávio", "Paula", "Rafael",
+		"Sofia", "Thiago", "Vitória", "William",
+	}
+	lastNames = []string{
+		"Silva", "Santos", "Oliveira", "Souza", "Rodrigues", "Ferreira", "Alves", "Pereira",
+		"Lima", "Gomes", "Costa", "Ribeiro", "Martins", "Carvalho", "Almeida", "Lopes",
+	}
+	companySuffixes = []string{"Comercio Ltda", "Servicos S.A.", "Industria Ltda", "Tecnologia Ltda", "Transportes ME"}
+	emailDomains    = []string{"gmail.com", "hotmail.com", "outlook.com", "yahoo.com.br", "uol.com.br", "example.com"}
+	phoneAreaCodes  = []int{11, 12, 13, 19, 21, 27, 31, 41, 47, 48, 51, 61, 62, 71, 81, 85, 91, 92}
+	accountTypes    = []weighted[models.AccountType]{
+		{value: models.AccountTypeCACC, weight: 65},
+		{value: models.AccountTypeSVGS, weight: 20},
+		{value: models.AccountTypeSLRY, weight: 10},
+		{value: models.AccountTypePAGA, weight: 3},
+		{value: models.AccountTypeTRAN, weight: 2},
+	}
+)
+
+// weighted pairs a value with its relative weight for random selection
+type weighted[T any] struct {
+	value  T
+	weight int
+}
+
+// pick selects a value with probability proportional to its weight
+func pick[T any](rng *rand.Rand, items []weighted[T]) T {
+	total := 0
+	for _, it := range items {
+		total += it.weight
+	}
+	n := rng.IntN(total)
+	for _, it := range items {
+		if n < it.weight {
+			return it.value
+		}
+		n -= it.weight
+	}
+	return items[len(items)-1].value
+}
+
+// Generator produces realistic, valid CreateEntryRequest values
+// A Generator is not safe for concurrent use
+type Generator struct {
+	seed         uint64
+	src          *rand.ChaCha8
+	rng          *rand.Rand
+	keyTypes     []weighted[models.KeyType]
+	participants []weighted[string]
+	today        time.Time
+	seen         map[string]struct{}
+}
+
+// NewGenerator creates a generator with the given options
+func NewGenerator(opts Options) *Generator {
+	seed := opts.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+
+	var chachaSeed [32]byte
+	binary.LittleEndian.PutUint64(chachaSeed[:8], seed)
+	src := rand.NewChaCha8(chachaSeed)
+
+	keyTypeWeights := opts.KeyTypes
+	if totalWeight(keyTypeWeights) == 0 {
+		keyTypeWeights = DefaultKeyTypes()
+	}
+	keyTypes := make([]weighted[models.KeyType], 0, len(keyTypeOrder))
+	for _, kt := range keyTypeOrder {
+		if w := keyTypeWeights[kt]; w > 0 {
+			keyTypes = append(keyTypes, weighted[models.KeyType]{value: kt, weight: w})
+		}
+	}
+
+	participants := participantWeights(opts.Participants)
+	if len(participants) == 0 {
+		participants = participantWeights(DefaultParticipants())
+	}
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+
+	return &Generator{
+		seed:         seed,
+		src:          src,
+		rng:          rand.New(src),
+		keyTypes:     keyTypes,
+		participants: participants,
+		today:        now.UTC().Truncate(24 * time.Hour),
+		seen:         make(map[string]struct{}),
+	}
+}
+
+func participantWeights(shares []ParticipantShare) []weighted[string] {
+	participants := make([]weighted[string], 0, len(shares))
+	for _, s := range shares {
+		if s.Weight > 0 {
+			participants = append(participants, weighted[string]{value: s.ISPB, weight: s.Weight})
+		}
+	}
+	return participants
+}
+
+func totalWeight(weights map[models.KeyType]int) int {
+	total := 0
+	for _, kt := range keyTypeOrder {
+		if w := weights[kt]; w > 0 {
+			total += w
+		}
+	}
+	return total
+}
+
+// Seed returns the seed in use, so a generated dataset can be reproduced
+func (g *Generator) Seed() uint64 {
+	return g.seed
+}
+
+// Generate returns n entries with keys unique within this generator
+func (g *Generator) Generate(n int) []models.CreateEntryRequest {
+	entries := make([]models.CreateEntryRequest, 0, n)
+	for range n {
+		entries = append(entries, g.Next())
+	}
+	return entries
+}
+
+// Next returns a single entry whose key has not been produced before by this generator
+func (g *Generator) Next() models.CreateEntryRequest {
+	keyType := pick(g.rng, g.keyTypes)
+	owner := g.owner(keyType)
+
+	var key string
+	for {
+		key = g.key(keyType, owner)
+		if _, dup := g.seen[key]; !dup {
+			break
+		}
+		// CPF/CNPJ keys must match the owner's tax ID, so a new owner is needed on collision
+		owner = g.owner(keyType)
+	}
+	g.seen[key] = struct{}{}
+
+	return models.CreateEntryRequest{
+		Key:       key,
+		KeyType:   keyType,
+		Account:   g.account(),
+		Owner:     owner,
+		Reason:    "USER_REQUESTED",
+		RequestId: g.uuid(),
+	}
+}
+
+// key builds a key of the given type, reusing the owner's tax ID for CPF/CNPJ keys
+func (g *Generator) key(keyType models.KeyType, owner models.Owner) string {
+	switch keyType {
+	case models.KeyTypeCPF, models.KeyTypeCNPJ:
+		return owner.TaxIdNumber
+	case models.KeyTypeEMAIL:
+		return g.Email(owner.Name)
+	case models.KeyTypePHONE:
+		return g.Phone()
+	default:
+		return g.uuid()
+	}
+}
+
+// owner builds an owner consistent with the key type
+func (g *Generator) owner(keyType models.KeyType) models.Owner {
+	legal := keyType == models.KeyTypeCNPJ || (keyType != models.KeyTypeCPF && g.rng.IntN(100) < 15)
+	if !legal {
+		return models.Owner{
+			Type:        models.OwnerTypeNaturalPerson,
+			TaxIdNumber: g.CPF(),
+			Name:        g.personName(),
+		}
+	}
+
+	last := lastNames[g.rng.IntN(len(lastNames))]
+	return models.Owner{
+		Type:        models.OwnerTypeLegalPerson,
+		TaxIdNumber: g.CNPJ(),
+		Name:        last + " " + companySuffixes[g.rng.IntN(len(companySuffixes))],
+		TradeName:   last + " Store",
+	}
+}
+
+// account builds a bank account for a participant drawn from the distribution
+func (g *Generator) account() models.Account {
+	daysOpen := g.rng.IntN(10 * 365)
+	openingDate := g.today.AddDate(0, 0, -daysOpen)
+
+	return models.Account{
+		Participant:   pick(g.rng, g.participants),
+		Branch:        fmt.Sprintf("%04d", g.rng.IntN(9999)+1),
+		AccountNumber: fmt.Sprintf("%010d", g.rng.Int64N(10_000_000_000)),
+		AccountType:   pick(g.rng, accountTypes),
+		OpeningDate:   openingDate,
+	}
+}
+
+func (g *Generator) personName() string {
+	return firstNames[g.rng.IntN(len(firstNames))] + " " + lastNames[g.rng.IntN(len(lastNames))]
+}
+
+func (g *Generator) uuid() string {
+	return uuid.Must(uuid.NewRandomFromReader(g.src)).String()
+}
+
+// CPF returns a random CPF with valid Módulo 11 check digits
+func (g *Generator) CPF() string {
+	digits := make([]int, 11)
+	for {
+		for i := range 9 {
+			digits[i] = g.rng.IntN(10)
+		}
+		if !allSame(digits[:9]) {
+			break
+		}
+	}
+
+	for pos := 9; pos < 11; pos++ {
+		sum := 0
+		for i := range pos {
+			sum += digits[i] * (pos + 1 - i)
+		}
+		remainder := (sum * 10) % 11
+		if remainder == 10 {
+			remainder = 0
+		}
+		digits[pos] = remainder
+	}
+
+	return digitsToString(digits)
+}
+
+// CNPJ returns a random headquarters CNPJ (branch 0001) with valid Módulo 11 check digits
+func (g *Generator) CNPJ() string {
+	digits := make([]int, 14)
+	for {
+		for i := range 8 {
+			digits[i] = g.rng.IntN(10)
+		}
+		if !allSame(digits[:8]) {
+			break
+		}
+	}
+	digits[8], digits[9], digits[10], digits[11] = 0, 0, 0, 1
+
+	weights := []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+	for pos := 12; pos < 14; pos++ {
+		// The first check digit uses the weights shifted by one position
+		w := weights[13-pos:]
+		sum := 0
+		for i := range pos {
+			sum += digits[i] * w[i]
+		}
+		remainder := sum % 11
+		if remainder < 2 {
+			digits[pos] = 0
+		} else {
+			digits[pos] = 11 - remainder
+		}
+	}
+
+	return digitsToString(digits)
+}
+
+// Email returns a lowercase email address derived from the owner's name
+func (g *Generator) Email(name string) string {
+	local := strings.ToLower(strings.Join(strings.Fields(asciiFold(name)), "."))
+	domain := emailDomains[g.rng.IntN(len(emailDomains))]
+	return fmt.Sprintf("%s%d@%s", local, g.rng.IntN(10000), domain)
+}
+
+// Phone returns a Brazilian mobile number in E.164 format
+func (g *Generator) Phone() string {
+	areaCode := phoneAreaCodes[g.rng.IntN(len(phoneAreaCodes))]
+	return fmt.Sprintf("+55%d9%08d", areaCode, g.rng.IntN(100_000_000))
+}
+
+func allSame(digits []int) bool {
+	for _, d := range digits[1:] {
+		if d != digits[0] {
+			return false
+		}
+	}
+	return true
+}
+
+func digitsToString(digits []int) string {
+	b := make([]byte, len(digits))
+	for i, d := range digits {
+		b[i] = byte('0' + d)
+	}
+	return string(b)
+}
+
+// asciiFold strips the Portuguese diacritics used in the name lists
+func asciiFold(s string) string {
+	return strings.NewReplacer(
+		"á", "a", "â", "a", "ã", "a", "à", "a",
+		"é", "e", "ê", "e",
+		"í", "i",
+		"ó", "o", "ô", "o", "õ", "o",
+		"ú", "u",
+		"ç", "c",
+	).Replace(s)
+}
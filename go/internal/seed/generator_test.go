@@ -0,0 +1,91 @@
+package seed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/modules/entries"
+	"github.com/dict-simulator/go/internal/validation"
+)
+
+func TestGeneratorCPFAndCNPJAreValid(t *testing.T) {
+	g := NewGenerator(Options{Seed: 1})
+
+	for range 500 {
+		if cpf := g.CPF(); !validation.IsValidCPF(cpf) {
+			t.Fatalf("CPF() = %q, not a valid CPF", cpf)
+		}
+		if cnpj := g.CNPJ(); !validation.IsValidCNPJ(cnpj) {
+			t.Fatalf("CNPJ() = %q, not a valid CNPJ", cnpj)
+		}
+	}
+}
+
+func TestGeneratorProducesValidEntries(t *testing.T) {
+	g := NewGenerator(Options{Seed: 42})
+
+	for _, req := range g.Generate(1000) {
+		if err := validation.Validate(&req); err != nil {
+			t.Fatalf("generated entry %q failed struct validation: %v", req.Key, err)
+		}
+		if result := entries.ValidateKey(req.Key, req.KeyType); !result.Success {
+			t.Fatalf("generated key %q (%s) failed key validation: %s", req.Key, req.KeyType, result.Error.Message)
+		}
+		if req.KeyType == models.KeyTypeCPF || req.KeyType == models.KeyTypeCNPJ {
+			if req.Key != req.Owner.TaxIdNumber {
+				t.Errorf("key %q does not match owner tax ID %q", req.Key, req.Owner.TaxIdNumber)
+			}
+		}
+	}
+}
+
+func TestGeneratorKeysAreUnique(t *testing.T) {
+	g := NewGenerator(Options{
+		Seed:     7,
+		KeyTypes: map[models.KeyType]int{models.KeyTypePHONE: 1},
+	})
+
+	seen := make(map[string]bool)
+	for _, req := range g.Generate(2000) {
+		if seen[req.Key] {
+			t.Fatalf("duplicate key generated: %q", req.Key)
+		}
+		seen[req.Key] = true
+	}
+}
+
+func TestGeneratorIsDeterministicForSeed(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	a := NewGenerator(Options{Seed: 99, Now: now}).Generate(50)
+	b := NewGenerator(Options{Seed: 99, Now: now.Add(time.Hour)}).Generate(50)
+
+	for i := range a {
+		if a[i].Key != b[i].Key || a[i].RequestId != b[i].RequestId || a[i].Account.Participant != b[i].Account.Participant {
+			t.Fatalf("entry %d differs between runs with the same seed: %q vs %q", i, a[i].Key, b[i].Key)
+		}
+		if !a[i].Account.OpeningDate.Equal(b[i].Account.OpeningDate) {
+			t.Fatalf("entry %d opening date differs on the same day: %v vs %v", i, a[i].Account.OpeningDate, b[i].Account.OpeningDate)
+		}
+		if a[i].Account.OpeningDate.After(now) {
+			t.Fatalf("entry %d opened on %v, after %v", i, a[i].Account.OpeningDate, now)
+		}
+	}
+}
+
+func TestGeneratorRespectsDistribution(t *testing.T) {
+	g := NewGenerator(Options{
+		Seed:         3,
+		KeyTypes:     map[models.KeyType]int{models.KeyTypeEVP: 1, models.KeyTypeEMAIL: 0},
+		Participants: []ParticipantShare{{ISPB: "11111111", Weight: 1}},
+	})
+
+	for _, req := range g.Generate(100) {
+		if req.KeyType != models.KeyTypeEVP {
+			t.Errorf("KeyType = %s, want EVP", req.KeyType)
+		}
+		if req.Account.Participant != "11111111" {
+			t.Errorf("Participant = %s, want 11111111", req.Account.Participant)
+		}
+	}
+}
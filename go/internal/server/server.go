@@ -2,10 +2,12 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
@@ -16,12 +18,23 @@ import (
 
 // Server wraps the HTTP server with graceful shutdown support
 type Server struct {
-	httpServer *http.Server
-	port       int
+	httpServer      *http.Server
+	port            int
+	shutdownTimeout time.Duration
+
+	mu    sync.Mutex
+	hooks []hook
+}
+
+// hook stops a background component during shutdown
+type hook struct {
+	name string
+	fn   func(ctx context.Context) error
 }
 
 // New creates a new Server instance
-func New(handler http.Handler, port int) *Server {
+// shutdownTimeout bounds the whole graceful shutdown: draining requests and running every hook.
+func New(handler http.Handler, port int, shutdownTimeout time.Duration) *Server {
 	return &Server{
 		httpServer: &http.Server{
 			Addr:         fmt.Sprintf(":%d", port),
@@ -30,24 +43,70 @@ func New(handler http.Handler, port int) *Server {
 			WriteTimeout: 15 * time.Second,
 			IdleTimeout:  60 * time.Second,
 		},
-		port: port,
+		port:            port,
+		shutdownTimeout: shutdownTimeout,
+	}
+}
+
+// OnShutdown registers fn to stop a background component once the server stops accepting requests
+// Hooks run one at a time in registration order, so components that feed others should be registered
+// first. They share the shutdown deadline: fn must give up and return when ctx is done.
+func (s *Server) OnShutdown(name string, fn func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, hook{name: name, fn: fn})
+}
+
+// UseTLS serves HTTPS with cfg, which must hold the server certificate (see LoadTLSConfig)
+func (s *Server) UseTLS(cfg *tls.Config) {
+	s.httpServer.TLSConfig = cfg
+}
+
+// scheme is the URL scheme the server is reachable on
+func (s *Server) scheme() string {
+	if s.httpServer.TLSConfig != nil {
+		return "https"
 	}
+	return "http"
 }
 
 // Start begins listening and serving requests (blocks until server stops)
 func (s *Server) Start() error {
-	logger.Info("server starting", zap.Int("port", s.port))
-
-	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	logger.Info("server starting", zap.Int("port", s.port), zap.String("scheme", s.scheme()))
+
+	var err error
+	if s.httpServer.TLSConfig != nil {
+		// The certificate comes from TLSConfig
+		err = s.httpServer.ListenAndServeTLS("", "")
+	} else {
+		err = s.httpServer.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
 		return err
 	}
 	return nil
 }
 
-// Shutdown gracefully stops the server with the given context
+// Shutdown gracefully stops the server with the given context, then runs the shutdown hooks
+// Every hook runs even after the deadline, so each can still release what it holds.
+// Returns the HTTP shutdown error, if any; hook errors are logged.
 func (s *Server) Shutdown(ctx context.Context) error {
 	logger.Info("server shutting down")
-	return s.httpServer.Shutdown(ctx)
+	err := s.httpServer.Shutdown(ctx)
+
+	s.mu.Lock()
+	hooks := s.hooks
+	s.mu.Unlock()
+
+	for _, h := range hooks {
+		start := time.Now()
+		if hookErr := h.fn(ctx); hookErr != nil {
+			logger.Error("shutdown hook failed", zap.String("hook", h.name), zap.Error(hookErr))
+			continue
+		}
+		logger.Info("shutdown hook completed", zap.String("hook", h.name), zap.Duration("duration", time.Since(start)))
+	}
+	return err
 }
 
 // ListenAndServeWithGracefulShutdown starts the server and handles OS signals for graceful shutdown
@@ -61,7 +120,7 @@ func (s *Server) ListenAndServeWithGracefulShutdown() {
 		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 		<-sigChan
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
 		defer cancel()
 
 		if err := s.Shutdown(ctx); err != nil {
@@ -72,7 +131,7 @@ func (s *Server) ListenAndServeWithGracefulShutdown() {
 	}()
 
 	// Start server
-	logger.Info("DICT Simulator running", zap.String("addr", fmt.Sprintf("http://localhost:%d", s.port)))
+	logger.Info("DICT Simulator running", zap.String("addr", fmt.Sprintf("%s://localhost:%d", s.scheme(), s.port)))
 
 	if err := s.Start(); err != nil {
 		logger.Fatal("server error", zap.Error(err))
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestShutdownRunsHooksInOrder(t *testing.T) {
+	s := New(http.NotFoundHandler(), 0, time.Second)
+
+	var got []string
+	s.OnShutdown("first", func(context.Context) error {
+		got = append(got, "first")
+		return nil
+	})
+	s.OnShutdown("failing", func(context.Context) error {
+		got = append(got, "failing")
+		return errors.New("boom")
+	})
+	s.OnShutdown("last", func(context.Context) error {
+		got = append(got, "last")
+		return nil
+	})
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	want := []string{"first", "failing", "last"}
+	if len(got) != len(want) {
+		t.Fatalf("hooks ran %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("hook %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestShutdownHooksShareTheDeadline(t *testing.T) {
+	s := New(http.NotFoundHandler(), 0, time.Second)
+
+	// The first hook uses up the deadline; the second still runs, with an expired context
+	s.OnShutdown("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	var lateErr error
+	s.OnShutdown("late", func(ctx context.Context) error {
+		lateErr = ctx.Err()
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	s.Shutdown(ctx)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Shutdown took %v, want it bounded by the 50ms deadline", elapsed)
+	}
+	if !errors.Is(lateErr, context.DeadlineExceeded) {
+		t.Errorf("late hook saw ctx.Err() = %v, want DeadlineExceeded", lateErr)
+	}
+}
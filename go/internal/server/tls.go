@@ -0,0 +1,98 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ICPBrasilPolicyArc is the OID arc under which ICP-Brasil certificate policies are registered
+const ICPBrasilPolicyArc = "2.16.76.1.2"
+
+// TLSOptions configures HTTPS and client certificate verification
+type TLSOptions struct {
+	CertFile string
+	KeyFile  string
+
+	// ClientAuth is tls.NoClientCert, tls.VerifyClientCertIfGiven or tls.RequireAndVerifyClientCert
+	ClientAuth   tls.ClientAuthType
+	ClientCAFile string // PEM bundle of the CAs client certificates must chain to
+
+	// RequireICPBrasil additionally requires client certificates to look like ICP-Brasil ones
+	// (see verifyICPBrasilChain), as the real DICT does for participants.
+	RequireICPBrasil bool
+}
+
+// LoadTLSConfig builds the listener's TLS configuration, reading the certificate files
+func LoadTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load server certificate: %w", err)
+	}
+
+	cfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   opts.ClientAuth,
+	}
+	if opts.ClientAuth == tls.NoClientCert {
+		return cfg, nil
+	}
+
+	pem, err := os.ReadFile(opts.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("read client CA bundle: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("client CA bundle %s has no PEM certificates", opts.ClientCAFile)
+	}
+	cfg.ClientCAs = pool
+
+	if opts.RequireICPBrasil {
+		cfg.VerifyPeerCertificate = verifyICPBrasilChain
+	}
+	return cfg, nil
+}
+
+// verifyICPBrasilChain accepts a client certificate only if one of its verified chains has the
+// ICP-Brasil shape: the root, at least one intermediate certification authority (AC), and a leaf
+// issued for client authentication under an ICP-Brasil certificate policy.
+// The standard chain verification has already run; this only adds the ICP-Brasil checks.
+func verifyICPBrasilChain(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+	// No certificate was presented (allowed with VerifyClientCertIfGiven)
+	if len(verifiedChains) == 0 {
+		return nil
+	}
+
+	var problem error
+	for _, chain := range verifiedChains {
+		if problem = checkICPBrasilChain(chain); problem == nil {
+			return nil
+		}
+	}
+	return problem
+}
+
+func checkICPBrasilChain(chain []*x509.Certificate) error {
+	if len(chain) < 3 {
+		return errors.New("client certificate must be issued by an intermediate AC, not directly by the root")
+	}
+
+	leaf := chain[0]
+	if !slices.Contains(leaf.ExtKeyUsage, x509.ExtKeyUsageClientAuth) {
+		return errors.New("client certificate is not issued for client authentication")
+	}
+	if !slices.ContainsFunc(leaf.Policies, isICPBrasilPolicy) {
+		return fmt.Errorf("client certificate has no ICP-Brasil policy (under %s)", ICPBrasilPolicyArc)
+	}
+	return nil
+}
+
+func isICPBrasilPolicy(policy x509.OID) bool {
+	return strings.HasPrefix(policy.String(), ICPBrasilPolicyArc+".")
+}
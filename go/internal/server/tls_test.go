@@ -0,0 +1,211 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// icpPolicy is an ICP-Brasil policy OID (A1 certificates for equipment)
+var icpPolicy = mustOID("2.16.76.1.2.1.1")
+
+func mustOID(s string) x509.OID {
+	oid, err := x509.ParseOID(s)
+	if err != nil {
+		panic(err)
+	}
+	return oid
+}
+
+type issued struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// issue creates a certificate from tmpl, signed by parent (self-signed when parent is nil)
+func issue(t *testing.T, tmpl *x509.Certificate, parent *issued) *issued {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl.SerialNumber = big.NewInt(time.Now().UnixNano())
+	tmpl.NotBefore = time.Now().Add(-time.Hour)
+	tmpl.NotAfter = time.Now().Add(time.Hour)
+
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &issued{cert: cert, key: key}
+}
+
+func ca(t *testing.T, name string, parent *issued) *issued {
+	return issue(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: name},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, parent)
+}
+
+func clientCert(t *testing.T, parent *issued, policies ...x509.OID) *issued {
+	return issue(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "participant 12345678"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		Policies:    policies,
+	}, parent)
+}
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// startTLSServer serves 200s with the configuration LoadTLSConfig builds for opts
+// The server certificate is issued by root for 127.0.0.1.
+func startTLSServer(t *testing.T, root *issued, opts TLSOptions) *httptest.Server {
+	t.Helper()
+	dir := t.TempDir()
+
+	srvCert := issue(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "dict-simulator"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+	}, root)
+	keyDER, err := x509.MarshalECPrivateKey(srvCert.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.CertFile = writePEM(t, dir, "server.pem", "CERTIFICATE", srvCert.cert.Raw)
+	opts.KeyFile = writePEM(t, dir, "server-key.pem", "EC PRIVATE KEY", keyDER)
+	if opts.ClientAuth != tls.NoClientCert {
+		opts.ClientCAFile = writePEM(t, dir, "clients.pem", "CERTIFICATE", root.cert.Raw)
+	}
+
+	cfg, err := LoadTLSConfig(opts)
+	if err != nil {
+		t.Fatalf("LoadTLSConfig() error = %v", err)
+	}
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = cfg
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// get requests srv over TLS, presenting client (and the intermediates) when given
+func get(t *testing.T, srv *httptest.Server, root *issued, client *issued, intermediates ...*issued) error {
+	t.Helper()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root.cert)
+	tlsCfg := &tls.Config{RootCAs: roots}
+	if client != nil {
+		chain := tls.Certificate{Certificate: [][]byte{client.cert.Raw}, PrivateKey: client.key}
+		for _, c := range intermediates {
+			chain.Certificate = append(chain.Certificate, c.cert.Raw)
+		}
+		tlsCfg.Certificates = []tls.Certificate{chain}
+	}
+
+	httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsCfg}}
+	resp, err := httpClient.Get(srv.URL)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func TestTLSWithoutClientCertificates(t *testing.T) {
+	root := ca(t, "root", nil)
+	srv := startTLSServer(t, root, TLSOptions{})
+
+	if err := get(t, srv, root, nil); err != nil {
+		t.Errorf("GET over TLS failed: %v", err)
+	}
+}
+
+func TestTLSRequiresClientCertificate(t *testing.T) {
+	root := ca(t, "root", nil)
+	srv := startTLSServer(t, root, TLSOptions{ClientAuth: tls.RequireAndVerifyClientCert})
+
+	if err := get(t, srv, root, nil); err == nil {
+		t.Error("request without a client certificate succeeded")
+	}
+	if err := get(t, srv, root, clientCert(t, ca(t, "other root", nil))); err == nil {
+		t.Error("request with an untrusted client certificate succeeded")
+	}
+	if err := get(t, srv, root, clientCert(t, root)); err != nil {
+		t.Errorf("request with a trusted client certificate failed: %v", err)
+	}
+}
+
+func TestTLSRequiresICPBrasilChain(t *testing.T) {
+	root := ca(t, "AC Raiz", nil)
+	intermediate := ca(t, "AC Intermediaria", root)
+	srv := startTLSServer(t, root, TLSOptions{ClientAuth: tls.RequireAndVerifyClientCert, RequireICPBrasil: true})
+
+	tests := []struct {
+		name          string
+		client        *issued
+		intermediates []*issued
+		wantOK        bool
+	}{
+		{
+			name:   "issued directly by the root",
+			client: clientCert(t, root, icpPolicy),
+		},
+		{
+			name:          "no ICP-Brasil policy",
+			client:        clientCert(t, intermediate, mustOID("1.2.3.4")),
+			intermediates: []*issued{intermediate},
+		},
+		{
+			name:          "ICP-Brasil chain",
+			client:        clientCert(t, intermediate, icpPolicy),
+			intermediates: []*issued{intermediate},
+			wantOK:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := get(t, srv, root, tt.client, tt.intermediates...)
+			if tt.wantOK && err != nil {
+				t.Errorf("request failed: %v", err)
+			}
+			if !tt.wantOK && err == nil {
+				t.Error("request succeeded, want the certificate rejected")
+			}
+		})
+	}
+}
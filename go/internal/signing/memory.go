@@ -0,0 +1,66 @@
+package signing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often MemoryNonceStore drops expired nonces and correlation IDs
+const sweepInterval = time.Minute
+
+// MemoryNonceStore keeps nonces and correlation IDs in process memory (STORAGE=memory)
+// They are per process: a request replayed against another replica is not detected.
+type MemoryNonceStore struct {
+	mu        sync.Mutex
+	expiry    map[string]time.Time
+	lastSweep time.Time
+	now       func() time.Time // wall clock, like a Redis TTL; replaced in tests
+}
+
+// NewMemoryNonceStore creates an in-memory nonce store
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{
+		expiry: map[string]time.Time{},
+		now:    time.Now,
+	}
+}
+
+// Claim records the nonce unless the participant already used it within its ttl
+func (s *MemoryNonceStore) Claim(ctx context.Context, participant, nonce string, ttl time.Duration) (bool, error) {
+	return s.claim(nonceKey(participant, nonce), ttl), nil
+}
+
+// ClaimCorrelationID records the correlation ID unless the participant already used it within its ttl
+func (s *MemoryNonceStore) ClaimCorrelationID(ctx context.Context, participant, correlationID string, ttl time.Duration) (bool, error) {
+	return s.claim(correlationKey(participant, correlationID), ttl), nil
+}
+
+// claim records key for ttl unless it is already recorded and unexpired
+func (s *MemoryNonceStore) claim(key string, ttl time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	if expiresAt, ok := s.expiry[key]; ok && now.Before(expiresAt) {
+		return false
+	}
+	s.expiry[key] = now.Add(ttl)
+	return true
+}
+
+// sweep drops expired nonces, at most once per sweepInterval
+func (s *MemoryNonceStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, expiresAt := range s.expiry {
+		if !now.Before(expiresAt) {
+			delete(s.expiry, key)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package signing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NonceStore remembers the nonces and correlation IDs each participant has used, so a captured
+// request can't be replayed. Implemented by RedisNonceStore and MemoryNonceStore (STORAGE=memory).
+type NonceStore interface {
+	// Claim records a nonce for ttl; it returns false if the participant already used it
+	Claim(ctx context.Context, participant, nonce string, ttl time.Duration) (bool, error)
+	// ClaimCorrelationID records a correlation ID for ttl; it returns false if the participant already used it
+	ClaimCorrelationID(ctx context.Context, participant, correlationID string, ttl time.Duration) (bool, error)
+}
+
+// nonceKey generates the storage key for a participant's nonce
+// Format: request_nonce:{participant}:{nonce}
+func nonceKey(participant, nonce string) string {
+	return fmt.Sprintf("request_nonce:%s:%s", participant, nonce)
+}
+
+// correlationKey generates the storage key for a participant's correlation ID
+// Format: request_correlation:{participant}:{correlationID}
+func correlationKey(participant, correlationID string) string {
+	return fmt.Sprintf("request_correlation:%s:%s", participant, correlationID)
+}
+
+// RedisNonceStore keeps nonces in Redis, shared by every replica
+type RedisNonceStore struct {
+	client *redis.Client
+}
+
+// NewRedisNonceStore creates a nonce store backed by Redis
+func NewRedisNonceStore(client *redis.Client) *RedisNonceStore {
+	return &RedisNonceStore{client: client}
+}
+
+// Claim records the nonce with SET NX, so only the first request using it succeeds
+func (s *RedisNonceStore) Claim(ctx context.Context, participant, nonce string, ttl time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, nonceKey(participant, nonce), 1, ttl).Result()
+}
+
+// ClaimCorrelationID records the correlation ID with SET NX, so only the first request using it succeeds
+func (s *RedisNonceStore) ClaimCorrelationID(ctx context.Context, participant, correlationID string, ttl time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, correlationKey(participant, correlationID), 1, ttl).Result()
+}
@@ -0,0 +1,151 @@
+package signing
+
+import (
+	"context"
+	"maps"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestParseSecrets(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Secrets
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			input: "",
+			want:  Secrets{},
+		},
+		{
+			name:  "two participants",
+			input: "12345678=first; 87654321=second=with=equals",
+			want:  Secrets{"12345678": "first", "87654321": "second=with=equals"},
+		},
+		{
+			name:    "missing separator",
+			input:   "12345678",
+			wantErr: true,
+		},
+		{
+			name:    "empty secret",
+			input:   "12345678=",
+			wantErr: true,
+		},
+		{
+			name:    "duplicate participant",
+			input:   "12345678=a;12345678=b",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSecrets(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSecrets(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !maps.Equal(got, tt.want) {
+				t.Errorf("ParseSecrets(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"key":"12345678901"}`)
+	sig := Sign("secret", "1700000000", "n1", "POST", "/entries", body)
+
+	if !Verify("secret", sig, "1700000000", "n1", "POST", "/entries", body) {
+		t.Fatal("Verify rejected the signature Sign produced")
+	}
+
+	tampered := map[string]bool{
+		"wrong secret":    Verify("other", sig, "1700000000", "n1", "POST", "/entries", body),
+		"other timestamp": Verify("secret", sig, "1700000001", "n1", "POST", "/entries", body),
+		"other nonce":     Verify("secret", sig, "1700000000", "n2", "POST", "/entries", body),
+		"other method":    Verify("secret", sig, "1700000000", "n1", "PUT", "/entries", body),
+		"other path":      Verify("secret", sig, "1700000000", "n1", "POST", "/entries?x=1", body),
+		"other body":      Verify("secret", sig, "1700000000", "n1", "POST", "/entries", []byte(`{}`)),
+		"not hex":         Verify("secret", "zz"+sig[2:], "1700000000", "n1", "POST", "/entries", body),
+	}
+	for name, ok := range tampered {
+		if ok {
+			t.Errorf("%s: Verify accepted the signature", name)
+		}
+	}
+}
+
+func TestFresh(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	ts := func(offset time.Duration) string { return strconv.FormatInt(now.Add(offset).Unix(), 10) }
+
+	tests := []struct {
+		timestamp string
+		want      bool
+	}{
+		{ts(0), true},
+		{ts(-5 * time.Minute), true},
+		{ts(5 * time.Minute), true},
+		{ts(-5*time.Minute - time.Second), false},
+		{ts(5*time.Minute + time.Second), false},
+		{"", false},
+		{"yesterday", false},
+	}
+	for _, tt := range tests {
+		if got := Fresh(tt.timestamp, now, 5*time.Minute); got != tt.want {
+			t.Errorf("Fresh(%q) = %v, want %v", tt.timestamp, got, tt.want)
+		}
+	}
+}
+
+func TestMemoryNonceStoreClaim(t *testing.T) {
+	s := NewMemoryNonceStore()
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if ok, _ := s.Claim(ctx, "p1", "n1", time.Minute); !ok {
+		t.Fatal("first claim was refused")
+	}
+	if ok, _ := s.Claim(ctx, "p1", "n1", time.Minute); ok {
+		t.Error("replayed nonce was claimed again")
+	}
+	if ok, _ := s.Claim(ctx, "p2", "n1", time.Minute); !ok {
+		t.Error("another participant's nonce was refused")
+	}
+
+	now = now.Add(time.Minute)
+	if ok, _ := s.Claim(ctx, "p1", "n1", time.Minute); !ok {
+		t.Error("expired nonce was refused")
+	}
+}
+
+func TestMemoryNonceStoreClaimCorrelationID(t *testing.T) {
+	s := NewMemoryNonceStore()
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if ok, _ := s.ClaimCorrelationID(ctx, "p1", "c1", time.Minute); !ok {
+		t.Fatal("first claim was refused")
+	}
+	if ok, _ := s.ClaimCorrelationID(ctx, "p1", "c1", time.Minute); ok {
+		t.Error("replayed correlation ID was claimed again")
+	}
+	if ok, _ := s.ClaimCorrelationID(ctx, "p2", "c1", time.Minute); !ok {
+		t.Error("another participant's correlation ID was refused")
+	}
+	// Nonces and correlation IDs don't collide
+	if ok, _ := s.Claim(ctx, "p1", "c1", time.Minute); !ok {
+		t.Error("nonce equal to a claimed correlation ID was refused")
+	}
+
+	now = now.Add(time.Minute)
+	if ok, _ := s.ClaimCorrelationID(ctx, "p1", "c1", time.Minute); !ok {
+		t.Error("expired correlation ID was refused")
+	}
+}
@@ -0,0 +1,119 @@
+package stubs
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dict-simulator/go/internal/clock"
+)
+
+// Stub is a canned response served instead of the route's handler
+// A stub matches when its route, key and headers all match (empty fields match everything).
+type Stub struct {
+	ID              string            `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Route           string            `json:"route,omitempty" example:"GET /entries/{key}"`
+	Key             string            `json:"key,omitempty" example:"+5511999999999"`
+	Headers         map[string]string `json:"headers,omitempty"` // request headers that must carry these values
+	StatusCode      int               `json:"statusCode" validate:"required,min=200,max=599" example:"409"`
+	Body            json.RawMessage   `json:"body,omitempty" swaggertype:"object"` // sent as is, as JSON
+	ResponseHeaders map[string]string `json:"responseHeaders,omitempty"`
+	DelayMs         int               `json:"delayMs,omitempty" validate:"omitempty,min=1,max=60000" example:"1500"`
+	CreatedAt       time.Time         `json:"createdAt"`
+}
+
+// Delay returns how long the stub waits before answering
+func (s Stub) Delay() time.Duration {
+	return time.Duration(s.DelayMs) * time.Millisecond
+}
+
+// matches reports whether the stub applies to a request on route for key
+func (s Stub) matches(route, key string, header http.Header) bool {
+	if s.Route != "" && s.Route != route {
+		return false
+	}
+	if s.Key != "" && s.Key != key {
+		return false
+	}
+	for name, value := range s.Headers {
+		if header.Get(name) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Registry holds the stubs added through /admin/stubs
+// Stubs live in memory and are local to a single simulator instance.
+type Registry struct {
+	mu    sync.RWMutex
+	stubs []Stub
+	clock clock.Clock
+}
+
+// NewRegistry creates a registry with no stubs
+// clk stamps CreatedAt on the stubs it adds.
+func NewRegistry(clk clock.Clock) *Registry {
+	return &Registry{clock: clk}
+}
+
+// Add registers a stub, filling in its ID, and returns it
+func (r *Registry) Add(s Stub) Stub {
+	s.ID = uuid.New().String()
+	s.CreatedAt = r.clock.Now().UTC()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stubs = append(r.stubs, s)
+
+	return s
+}
+
+// List returns a snapshot of the stubs, in registration order
+func (r *Registry) List() []Stub {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stubs := make([]Stub, len(r.stubs))
+	copy(stubs, r.stubs)
+	return stubs
+}
+
+// Remove deletes a stub by ID and reports whether it existed
+func (r *Registry) Remove(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for idx, s := range r.stubs {
+		if s.ID == id {
+			r.stubs = append(r.stubs[:idx], r.stubs[idx+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Clear removes all stubs
+func (r *Registry) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stubs = nil
+}
+
+// Match returns the stub that answers a request on route for key
+// When several match, the most recently added one wins, so a narrower stub can be layered
+// over a broad one without removing it.
+func (r *Registry) Match(route, key string, header http.Header) (Stub, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for idx := len(r.stubs) - 1; idx >= 0; idx-- {
+		if r.stubs[idx].matches(route, key, header) {
+			return r.stubs[idx], true
+		}
+	}
+	return Stub{}, false
+}
@@ -0,0 +1,111 @@
+package stubs
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/dict-simulator/go/internal/clock"
+)
+
+func TestStubMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		stub   Stub
+		route  string
+		key    string
+		header http.Header
+		want   bool
+	}{
+		{
+			name:  "empty stub matches everything",
+			stub:  Stub{},
+			route: "POST /entries",
+			want:  true,
+		},
+		{
+			name:  "route must match exactly",
+			stub:  Stub{Route: "GET /entries/{key}"},
+			route: "PUT /entries/{key}",
+			key:   "12345678909",
+			want:  false,
+		},
+		{
+			name:  "key matches the path value",
+			stub:  Stub{Route: "GET /entries/{key}", Key: "12345678909"},
+			route: "GET /entries/{key}",
+			key:   "12345678909",
+			want:  true,
+		},
+		{
+			name:  "key never matches routes without a key",
+			stub:  Stub{Key: "12345678909"},
+			route: "POST /entries",
+			want:  false,
+		},
+		{
+			name:   "header names are case-insensitive",
+			stub:   Stub{Headers: map[string]string{"x-participant-id": "12345678"}},
+			route:  "GET /entries/{key}",
+			header: http.Header{"X-Participant-Id": {"12345678"}},
+			want:   true,
+		},
+		{
+			name:   "header value must match",
+			stub:   Stub{Headers: map[string]string{"X-Participant-Id": "12345678"}},
+			route:  "GET /entries/{key}",
+			header: http.Header{"X-Participant-Id": {"87654321"}},
+			want:   false,
+		},
+		{
+			name:  "missing header does not match",
+			stub:  Stub{Headers: map[string]string{"X-Participant-Id": "12345678"}},
+			route: "GET /entries/{key}",
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := tt.header
+			if header == nil {
+				header = http.Header{}
+			}
+			if got := tt.stub.matches(tt.route, tt.key, header); got != tt.want {
+				t.Errorf("matches(%q, %q, %v) = %v, want %v", tt.route, tt.key, tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(clock.NewSimulated())
+
+	broad := r.Add(Stub{Route: "GET /entries/{key}", StatusCode: http.StatusServiceUnavailable})
+	narrow := r.Add(Stub{Route: "GET /entries/{key}", Key: "12345678909", StatusCode: http.StatusNotFound})
+	if broad.ID == "" || broad.ID == narrow.ID || broad.CreatedAt.IsZero() {
+		t.Fatalf("Add() = %+v, %+v; want distinct IDs and a creation time", broad, narrow)
+	}
+
+	// The latest stub that matches wins
+	if got, ok := r.Match("GET /entries/{key}", "12345678909", http.Header{}); !ok || got.ID != narrow.ID {
+		t.Errorf("Match() for the narrow key = %+v, %v; want the narrow stub", got, ok)
+	}
+	if got, ok := r.Match("GET /entries/{key}", "52998224725", http.Header{}); !ok || got.ID != broad.ID {
+		t.Errorf("Match() for another key = %+v, %v; want the broad stub", got, ok)
+	}
+	if _, ok := r.Match("POST /entries", "", http.Header{}); ok {
+		t.Error("Match() for another route found a stub")
+	}
+
+	if !r.Remove(narrow.ID) || r.Remove(narrow.ID) {
+		t.Error("Remove() should succeed once")
+	}
+	if got := r.List(); len(got) != 1 || got[0].ID != broad.ID {
+		t.Errorf("List() after Remove = %+v, want the broad stub", got)
+	}
+
+	r.Clear()
+	if got := r.List(); len(got) != 0 {
+		t.Errorf("List() after Clear = %+v, want none", got)
+	}
+}
@@ -0,0 +1,50 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	otelprometheus "go.opentelemetry.io/contrib/bridges/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// MeterProvider is exposed for use with otelhttp; nil unless metrics are pushed over OTLP
+var MeterProvider *sdkmetric.MeterProvider
+
+// InitMeterProvider pushes metrics to the OTLP collector every interval and returns a shutdown function
+// The Prometheus collectors registered with promauto are bridged into each export, so the
+// collector receives the same series /metrics would serve, tagged with the traces' resource.
+func InitMeterProvider(otelEndpoint string, interval time.Duration) (func(context.Context) error, error) {
+	ctx := context.Background()
+
+	endpoint := parseEndpoint(otelEndpoint)
+
+	exporter, err := otlpmetrichttp.New(ctx,
+		otlpmetrichttp.WithEndpoint(endpoint),
+		otlpmetrichttp.WithInsecure(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := newResource(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter,
+			sdkmetric.WithInterval(interval),
+			sdkmetric.WithProducer(otelprometheus.NewMetricProducer()),
+		)),
+		sdkmetric.WithResource(res),
+	)
+
+	// Store and set the provider globally
+	MeterProvider = mp
+	otel.SetMeterProvider(mp)
+
+	return mp.Shutdown, nil
+}
@@ -0,0 +1,77 @@
+package telemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/dict-simulator/go/internal/redact"
+)
+
+// redactingExporter masks tax IDs, emails, phone keys and passwords in spans before they are exported,
+// whichever instrumentation set them: otelhttp's url.path carries the key of GET /entries/{key}
+type redactingExporter struct {
+	sdktrace.SpanExporter
+	redactor *redact.Redactor
+}
+
+// ExportSpans exports the spans as redactedSpans
+func (e redactingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	redacted := make([]sdktrace.ReadOnlySpan, len(spans))
+	for i, span := range spans {
+		redacted[i] = redactedSpan{ReadOnlySpan: span, redactor: e.redactor}
+	}
+	return e.SpanExporter.ExportSpans(ctx, redacted)
+}
+
+// redactedSpan is a finished span whose name, attributes, events and status read masked
+type redactedSpan struct {
+	sdktrace.ReadOnlySpan
+	redactor *redact.Redactor
+}
+
+func (s redactedSpan) Name() string {
+	return s.redactor.Text(s.ReadOnlySpan.Name())
+}
+
+func (s redactedSpan) Attributes() []attribute.KeyValue {
+	return redactAttributes(s.redactor, s.ReadOnlySpan.Attributes())
+}
+
+func (s redactedSpan) Events() []sdktrace.Event {
+	events := s.ReadOnlySpan.Events()
+	out := make([]sdktrace.Event, len(events))
+	for i, event := range events {
+		event.Name = s.redactor.Text(event.Name)
+		event.Attributes = redactAttributes(s.redactor, event.Attributes)
+		out[i] = event
+	}
+	return out
+}
+
+func (s redactedSpan) Status() sdktrace.Status {
+	status := s.ReadOnlySpan.Status()
+	status.Description = s.redactor.Text(status.Description)
+	return status
+}
+
+// redactAttributes returns attrs with their string values masked; the span's own slice is left alone
+func redactAttributes(redactor *redact.Redactor, attrs []attribute.KeyValue) []attribute.KeyValue {
+	out := make([]attribute.KeyValue, len(attrs))
+	for i, attr := range attrs {
+		key := string(attr.Key)
+		switch attr.Value.Type() {
+		case attribute.STRING:
+			attr = attribute.String(key, redactor.Field(key, attr.Value.AsString()))
+		case attribute.STRINGSLICE:
+			values := attr.Value.AsStringSlice()
+			for j := range values {
+				values[j] = redactor.Field(key, values[j])
+			}
+			attr = attribute.StringSlice(key, values)
+		}
+		out[i] = attr
+	}
+	return out
+}
@@ -0,0 +1,60 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/dict-simulator/go/internal/redact"
+)
+
+func TestRedactingExporter(t *testing.T) {
+	exported := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(redactingExporter{
+		SpanExporter: exported,
+		redactor:     redact.New(redact.DefaultFields),
+	}))
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+
+	_, span := tp.Tracer("test").Start(context.Background(), "entries.get")
+	span.SetAttributes(
+		attribute.String("url.path", "/entries/52998224725"),
+		attribute.String("entry.participant", "12345678"),
+		attribute.StringSlice("email", []string{"john@example.com"}),
+	)
+	span.RecordError(errors.New("no entry for +5511987654321"))
+	span.SetStatus(codes.Error, "lookup of 52998224725 failed")
+	span.End()
+
+	spans := exported.GetSpans().Snapshots()
+	if len(spans) != 1 {
+		t.Fatalf("exported %d spans, want 1", len(spans))
+	}
+	got := map[attribute.Key]string{}
+	for _, attr := range spans[0].Attributes() {
+		got[attr.Key] = attr.Value.Emit()
+	}
+	want := map[attribute.Key]string{
+		"url.path":          "/entries/***982247**",
+		"entry.participant": "12345678",
+		"email":             `["j***@example.com"]`,
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %s, want %s", key, got[key], value)
+		}
+	}
+	if desc := spans[0].Status().Description; desc != "lookup of ***982247** failed" {
+		t.Errorf("status description = %q", desc)
+	}
+	for _, attr := range spans[0].Events()[0].Attributes {
+		if attr.Key == "exception.message" && attr.Value.AsString() != "no entry for +55*******4321" {
+			t.Errorf("exception.message = %q", attr.Value.AsString())
+		}
+	}
+}
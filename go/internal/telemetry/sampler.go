@@ -0,0 +1,128 @@
+package telemetry
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Samplers, named as in OTEL_TRACES_SAMPLER plus the rate-limited ones
+const (
+	SamplerAlwaysOn                = "always_on"
+	SamplerAlwaysOff               = "always_off"
+	SamplerTraceIDRatio            = "traceidratio"
+	SamplerParentBasedAlwaysOn     = "parentbased_always_on"
+	SamplerParentBasedAlwaysOff    = "parentbased_always_off"
+	SamplerParentBasedTraceIDRatio = "parentbased_traceidratio"
+	SamplerRateLimited             = "ratelimited"
+	SamplerParentBasedRateLimited  = "parentbased_ratelimited"
+)
+
+// Samplers lists the sampler names ParseSampler accepts
+var Samplers = []string{
+	SamplerAlwaysOn,
+	SamplerAlwaysOff,
+	SamplerTraceIDRatio,
+	SamplerParentBasedAlwaysOn,
+	SamplerParentBasedAlwaysOff,
+	SamplerParentBasedTraceIDRatio,
+	SamplerRateLimited,
+	SamplerParentBasedRateLimited,
+}
+
+// defaultTracesPerSecond is what the rate-limited samplers allow without an argument
+const defaultTracesPerSecond = 100
+
+// ParseSampler returns the sampler named like OTEL_TRACES_SAMPLER, configured by arg as
+// OTEL_TRACES_SAMPLER_ARG would be: the sampled share (0 to 1, default 1) for the ratio samplers
+// and the traces per second (default 100) for the rate-limited ones. Other samplers take no arg.
+// The parentbased_ samplers follow the caller's decision when a request arrives with a traceparent,
+// so traces spanning services are kept or dropped whole.
+func ParseSampler(name, arg string) (sdktrace.Sampler, error) {
+	var root sdktrace.Sampler
+	switch name {
+	case SamplerAlwaysOn, SamplerParentBasedAlwaysOn:
+		root = sdktrace.AlwaysSample()
+	case SamplerAlwaysOff, SamplerParentBasedAlwaysOff:
+		root = sdktrace.NeverSample()
+	case SamplerTraceIDRatio, SamplerParentBasedTraceIDRatio:
+		ratio, err := samplerArg(arg, 1)
+		if err != nil || ratio < 0 || ratio > 1 {
+			return nil, fmt.Errorf("%s takes a ratio between 0 and 1, got %q", name, arg)
+		}
+		root = sdktrace.TraceIDRatioBased(ratio)
+	case SamplerRateLimited, SamplerParentBasedRateLimited:
+		perSecond, err := samplerArg(arg, defaultTracesPerSecond)
+		if err != nil || perSecond <= 0 {
+			return nil, fmt.Errorf("%s takes a positive number of traces per second, got %q", name, arg)
+		}
+		root = newRateLimited(perSecond, time.Now)
+	default:
+		return nil, fmt.Errorf("unknown sampler %q", name)
+	}
+
+	switch name {
+	case SamplerParentBasedAlwaysOn, SamplerParentBasedAlwaysOff, SamplerParentBasedTraceIDRatio, SamplerParentBasedRateLimited:
+		return sdktrace.ParentBased(root), nil
+	}
+	return root, nil
+}
+
+// samplerArg parses a sampler argument, def when it is empty
+func samplerArg(arg string, def float64) (float64, error) {
+	if arg == "" {
+		return def, nil
+	}
+	return strconv.ParseFloat(arg, 64)
+}
+
+// rateLimited samples at most perSecond traces a second, from a token bucket holding a second's worth
+// (at least one), so bursts are cut off rather than averaged out
+type rateLimited struct {
+	perSecond float64
+	now       func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimited(perSecond float64, now func() time.Time) *rateLimited {
+	return &rateLimited{perSecond: perSecond, now: now, tokens: max(perSecond, 1), last: now()}
+}
+
+// ShouldSample samples while a token is left
+func (s *rateLimited) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	decision := sdktrace.Drop
+	if s.take() {
+		decision = sdktrace.RecordAndSample
+	}
+	return sdktrace.SamplingResult{
+		Decision:   decision,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+// take refills the bucket for the time passed and spends a token, reporting whether there was one
+func (s *rateLimited) take() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.tokens = min(s.tokens+now.Sub(s.last).Seconds()*s.perSecond, max(s.perSecond, 1))
+	s.last = now
+	if s.tokens < 1 {
+		return false
+	}
+	s.tokens--
+	return true
+}
+
+// Description names the sampler, as the SDK's own samplers do
+func (s *rateLimited) Description() string {
+	return fmt.Sprintf("RateLimited{%g}", s.perSecond)
+}
@@ -0,0 +1,68 @@
+package telemetry
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// sampled reports whether sampler samples a root span
+func sampled(sampler sdktrace.Sampler) bool {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	result := sampler.ShouldSample(sdktrace.SamplingParameters{ParentContext: context.Background(), TraceID: traceID, Name: "request"})
+	return result.Decision == sdktrace.RecordAndSample
+}
+
+func TestParseSampler(t *testing.T) {
+	tests := []struct {
+		name, arg   string
+		description string
+	}{
+		{name: SamplerAlwaysOn, description: "AlwaysOnSampler"},
+		{name: SamplerParentBasedAlwaysOn, description: "ParentBased{root:AlwaysOnSampler"},
+		{name: SamplerTraceIDRatio, arg: "0.25", description: "TraceIDRatioBased{0.25}"},
+		{name: SamplerParentBasedTraceIDRatio, arg: "0.5", description: "ParentBased{root:TraceIDRatioBased{0.5}"},
+		{name: SamplerRateLimited, arg: "50", description: "RateLimited{50}"},
+		{name: SamplerParentBasedRateLimited, description: "ParentBased{root:RateLimited{100}"},
+	}
+	for _, tt := range tests {
+		sampler, err := ParseSampler(tt.name, tt.arg)
+		if err != nil {
+			t.Errorf("ParseSampler(%q, %q) error = %v", tt.name, tt.arg, err)
+			continue
+		}
+		if got := sampler.Description(); !strings.HasPrefix(got, tt.description) {
+			t.Errorf("ParseSampler(%q, %q) = %s, want %s", tt.name, tt.arg, got, tt.description)
+		}
+	}
+
+	for _, bad := range [][2]string{{"sometimes", ""}, {SamplerTraceIDRatio, "1.5"}, {SamplerRateLimited, "0"}, {SamplerRateLimited, "fast"}} {
+		if _, err := ParseSampler(bad[0], bad[1]); err == nil {
+			t.Errorf("ParseSampler(%q, %q) succeeded, want an error", bad[0], bad[1])
+		}
+	}
+}
+
+func TestRateLimitedSampler(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	sampler := newRateLimited(2, func() time.Time { return now })
+
+	for i, want := range []bool{true, true, false} {
+		if got := sampled(sampler); got != want {
+			t.Errorf("trace %d sampled = %v, want %v", i+1, got, want)
+		}
+	}
+
+	// Half a second refills one token
+	now = now.Add(500 * time.Millisecond)
+	if !sampled(sampler) {
+		t.Error("trace after the refill was dropped")
+	}
+	if sampled(sampler) {
+		t.Error("second trace after a one-token refill was sampled")
+	}
+}
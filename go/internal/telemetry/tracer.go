@@ -16,6 +16,8 @@ import (
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dict-simulator/go/internal/redact"
 )
 
 var (
@@ -33,11 +35,23 @@ func parseEndpoint(endpoint string) string {
 	endpoint = strings.TrimPrefix(endpoint, "https://")
 	endpoint = strings.TrimSuffix(endpoint, "/v1/traces")
 	endpoint = strings.TrimSuffix(endpoint, "/v1/logs")
+	endpoint = strings.TrimSuffix(endpoint, "/v1/metrics")
 	return endpoint
 }
 
-// InitTracer initializes the OpenTelemetry tracer and returns a shutdown function
-func InitTracer(otelEndpoint string) (func(context.Context) error, error) {
+// newResource describes this service; traces, logs and metrics all carry it so they can be joined
+func newResource(ctx context.Context) (*resource.Resource, error) {
+	return resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceName("dict-simulator"),
+			semconv.ServiceVersion("1.0.0"),
+		),
+	)
+}
+
+// InitTracer initializes the OpenTelemetry tracer, sampling with sampler, and returns a shutdown function
+// Spans are masked by redactor before they are exported; nil exports them as they are.
+func InitTracer(otelEndpoint string, sampler sdktrace.Sampler, redactor *redact.Redactor) (func(context.Context) error, error) {
 	ctx := context.Background()
 
 	endpoint := parseEndpoint(otelEndpoint)
@@ -50,19 +64,20 @@ func InitTracer(otelEndpoint string) (func(context.Context) error, error) {
 		return nil, err
 	}
 
-	res, err := resource.New(ctx,
-		resource.WithAttributes(
-			semconv.ServiceName("dict-simulator"),
-			semconv.ServiceVersion("1.0.0"),
-		),
-	)
+	res, err := newResource(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	var spanExporter sdktrace.SpanExporter = exporter
+	if redactor != nil {
+		spanExporter = redactingExporter{SpanExporter: exporter, redactor: redactor}
+	}
+
 	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
+		sdktrace.WithBatcher(spanExporter),
 		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sampler),
 	)
 
 	// Set up W3C TraceContext propagator for distributed tracing
@@ -93,12 +108,7 @@ func InitLoggerProvider(otelEndpoint string) (func(context.Context) error, error
 		return nil, err
 	}
 
-	res, err := resource.New(ctx,
-		resource.WithAttributes(
-			semconv.ServiceName("dict-simulator"),
-			semconv.ServiceVersion("1.0.0"),
-		),
-	)
+	res, err := newResource(ctx)
 	if err != nil {
 		return nil, err
 	}
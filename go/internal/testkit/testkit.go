@@ -0,0 +1,180 @@
+package testkit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// Doer sends a request; *http.Client is one, and Handler adapts an http.Handler
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// handlerDoer serves requests in process, without a listener
+type handlerDoer struct {
+	handler http.Handler
+}
+
+// Handler serves requests with handler directly, so a test needs no server
+func Handler(handler http.Handler) Doer {
+	return handlerDoer{handler: handler}
+}
+
+// Do records the handler's response
+func (d handlerDoer) Do(req *http.Request) (*http.Response, error) {
+	recorder := httptest.NewRecorder()
+	d.handler.ServeHTTP(recorder, req)
+	return recorder.Result(), nil
+}
+
+// Result is the outcome of one of the concurrent requests
+type Result struct {
+	Index  int // position of the request in the batch
+	Status int
+	Header http.Header
+	Body   []byte
+	Err    error // set when the request couldn't be sent or its body read
+}
+
+// Results are the outcomes of a batch, in the order the requests were given
+type Results []Result
+
+// Run sends every request at the same time through doer and waits for all of them
+// The requests are built beforehand, in the test's goroutine, so building one can fail the test.
+// Each goroutine waits on a start barrier, so the requests reach the handler together instead of
+// as fast as goroutines happen to start.
+func Run(doer Doer, reqs []*http.Request) Results {
+	results := make(Results, len(reqs))
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i, req := range reqs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			results[i] = send(doer, i, req)
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	return results
+}
+
+// Repeat builds n requests with build, which gets each request's index
+func Repeat(n int, build func(i int) *http.Request) []*http.Request {
+	reqs := make([]*http.Request, n)
+	for i := range reqs {
+		reqs[i] = build(i)
+	}
+	return reqs
+}
+
+// send runs one request and reads its response
+func send(doer Doer, i int, req *http.Request) Result {
+	resp, err := doer.Do(req)
+	if err != nil {
+		return Result{Index: i, Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	return Result{Index: i, Status: resp.StatusCode, Header: resp.Header, Body: body, Err: err}
+}
+
+// Count returns how many requests answered status
+func (rs Results) Count(status int) int {
+	count := 0
+	for _, r := range rs {
+		if r.Err == nil && r.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Statuses returns how many requests answered each status
+func (rs Results) Statuses() map[int]int {
+	statuses := map[int]int{}
+	for _, r := range rs {
+		if r.Err == nil {
+			statuses[r.Status]++
+		}
+	}
+	return statuses
+}
+
+// WithStatus returns the results that answered status
+func (rs Results) WithStatus(status int) Results {
+	return slices.DeleteFunc(slices.Clone(rs), func(r Result) bool {
+		return r.Err != nil || r.Status != status
+	})
+}
+
+// Invariant checks a property every batch must have, returning why it doesn't hold
+type Invariant func(rs Results) error
+
+// Check fails the test for each request that wasn't answered and each invariant that doesn't hold
+func Check(t testing.TB, rs Results, invariants ...Invariant) {
+	t.Helper()
+
+	for _, r := range rs {
+		if r.Err != nil {
+			t.Errorf("request %d: %v", r.Index, r.Err)
+		}
+	}
+	for _, invariant := range invariants {
+		if err := invariant(rs); err != nil {
+			t.Errorf("%v (statuses %v)", err, rs.Statuses())
+		}
+	}
+}
+
+// ExactlyOne holds when a single request answered status, as when many race for one resource
+func ExactlyOne(status int) Invariant {
+	return Count(status, 1)
+}
+
+// Count holds when exactly n requests answered status
+func Count(status, n int) Invariant {
+	return func(rs Results) error {
+		if got := rs.Count(status); got != n {
+			return fmt.Errorf("%d requests answered %d, want %d", got, status, n)
+		}
+		return nil
+	}
+}
+
+// OnlyStatuses holds when every request answered one of statuses
+// A 500 from a lost race shows up here instead of being counted as one of the losers.
+func OnlyStatuses(statuses ...int) Invariant {
+	return func(rs Results) error {
+		for _, r := range rs {
+			if r.Err == nil && !slices.Contains(statuses, r.Status) {
+				return fmt.Errorf("request %d answered %d, want one of %v: %s", r.Index, r.Status, statuses, r.Body)
+			}
+		}
+		return nil
+	}
+}
+
+// SameResponse holds when every request answered the same status and body, as replays of one
+// idempotent request do
+func SameResponse() Invariant {
+	return func(rs Results) error {
+		for _, r := range rs[min(1, len(rs)):] {
+			if r.Status != rs[0].Status || !bytes.Equal(r.Body, rs[0].Body) {
+				return fmt.Errorf("request %d answered %d %s, request 0 answered %d %s",
+					r.Index, r.Status, r.Body, rs[0].Status, rs[0].Body)
+			}
+		}
+		return nil
+	}
+}
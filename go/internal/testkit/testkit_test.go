@@ -0,0 +1,91 @@
+package testkit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunStartsTogether(t *testing.T) {
+	const n = 8
+
+	// Each request waits until all of them have arrived, so a batch sent one at a time would time out
+	var arrived sync.WaitGroup
+	arrived.Add(n)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived.Done()
+		done := make(chan struct{})
+		go func() {
+			arrived.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+			w.WriteHeader(http.StatusNoContent)
+		case <-time.After(5 * time.Second):
+			w.WriteHeader(http.StatusGatewayTimeout)
+		}
+	})
+
+	rs := Run(Handler(handler), Repeat(n, func(i int) *http.Request {
+		return httptest.NewRequest(http.MethodGet, "/", nil)
+	}))
+
+	if len(rs) != n {
+		t.Fatalf("got %d results, want %d", len(rs), n)
+	}
+	for i, r := range rs {
+		if r.Index != i {
+			t.Errorf("result %d has index %d", i, r.Index)
+		}
+	}
+	Check(t, rs, Count(http.StatusNoContent, n))
+}
+
+func TestInvariants(t *testing.T) {
+	rs := Results{
+		{Index: 0, Status: http.StatusCreated, Body: []byte(`{"id":1}`)},
+		{Index: 1, Status: http.StatusConflict, Body: []byte(`{"error":"KEY_ALREADY_EXISTS"}`)},
+		{Index: 2, Status: http.StatusConflict, Body: []byte(`{"error":"KEY_ALREADY_EXISTS"}`)},
+		{Index: 3, Err: errors.New("connection reset")},
+	}
+
+	tests := []struct {
+		name      string
+		invariant Invariant
+		wantErr   string
+	}{
+		{name: "exactly one created", invariant: ExactlyOne(http.StatusCreated)},
+		{name: "exactly one conflict", invariant: ExactlyOne(http.StatusConflict), wantErr: "2 requests answered 409, want 1"},
+		{name: "count", invariant: Count(http.StatusConflict, 2)},
+		{name: "only statuses", invariant: OnlyStatuses(http.StatusCreated, http.StatusConflict)},
+		{name: "unexpected status", invariant: OnlyStatuses(http.StatusCreated), wantErr: "request 1 answered 409"},
+		{name: "same response", invariant: SameResponse(), wantErr: "request 1 answered 409"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.invariant(rs)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("invariant failed: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("invariant error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if got := rs.Statuses(); got[http.StatusCreated] != 1 || got[http.StatusConflict] != 2 || len(got) != 2 {
+		t.Errorf("Statuses() = %v, want one 201 and two 409s", got)
+	}
+	if got := rs.WithStatus(http.StatusConflict); len(got) != 2 || got[0].Index != 1 {
+		t.Errorf("WithStatus(409) = %+v, want requests 1 and 2", got)
+	}
+	if err := SameResponse()(rs[1:3]); err != nil {
+		t.Errorf("SameResponse() on identical replays: %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package usage
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/logger"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// flushTimeout bounds the last flush, made once Run is cancelled
+const flushTimeout = 5 * time.Second
+
+// key identifies a participant's counts for one day
+type key struct {
+	participant string
+	day         string
+}
+
+// Recorder counts each participant's requests, errors and rate limit tokens per day
+// Counts are kept in memory and added to the reports by Flush, which Run calls periodically, so
+// requests never wait on the reporting collection. Counts not yet flushed are lost if the process
+// dies; Run flushes them once more when it is cancelled.
+type Recorder struct {
+	reports  models.UsageReportRepository
+	clock    clock.Clock
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending map[key]models.UsageCounts
+}
+
+// NewRecorder creates a recorder adding to reports every interval; days follow clk
+func NewRecorder(reports models.UsageReportRepository, clk clock.Clock, interval time.Duration) *Recorder {
+	return &Recorder{
+		reports:  reports,
+		clock:    clk,
+		interval: interval,
+		pending:  make(map[key]models.UsageCounts),
+	}
+}
+
+// Record counts one request of participant, answered with status, that consumed tokens
+func (r *Recorder) Record(participant string, status, tokens int) {
+	counts := models.UsageCounts{Requests: 1, TokensConsumed: int64(tokens)}
+	switch {
+	case status >= http.StatusInternalServerError:
+		counts.ServerErrors = 1
+	case status >= http.StatusBadRequest:
+		counts.ClientErrors = 1
+	}
+
+	k := key{participant: participant, day: r.clock.Now().UTC().Format(models.UsageDayLayout)}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending[k] = r.pending[k].Add(counts)
+}
+
+// Flush adds the pending counts to the reports
+// Counts that fail to be added are kept for the next flush.
+func (r *Recorder) Flush(ctx context.Context) error {
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = make(map[key]models.UsageCounts)
+	r.mu.Unlock()
+
+	var firstErr error
+	for k, counts := range pending {
+		if err := r.reports.Add(ctx, k.participant, k.day, counts); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			r.mu.Lock()
+			r.pending[k] = r.pending[k].Add(counts)
+			r.mu.Unlock()
+		}
+	}
+	return firstErr
+}
+
+// Report flushes the pending counts and returns the reports filter selects, so they are current
+func (r *Recorder) Report(ctx context.Context, filter models.UsageReportFilter) ([]models.UsageReport, error) {
+	if err := r.Flush(ctx); err != nil {
+		return nil, err
+	}
+	return r.reports.List(ctx, filter)
+}
+
+// Run flushes the pending counts every interval until ctx is cancelled, and once more then
+// A zero interval only flushes then (and whenever the reports are read).
+func (r *Recorder) Run(ctx context.Context) {
+	var tick <-chan time.Time
+	if r.interval > 0 {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), flushTimeout)
+			defer cancel()
+			r.flushLogged(flushCtx)
+			return
+		case <-tick:
+			r.flushLogged(ctx)
+		}
+	}
+}
+
+// flushLogged flushes the pending counts, logging a failure
+func (r *Recorder) flushLogged(ctx context.Context) {
+	if err := r.Flush(ctx); err != nil {
+		logger.Error("Failed to flush usage reports", zap.Error(err))
+	}
+}
@@ -0,0 +1,96 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// failingReports refuses every Add while failing is set
+type failingReports struct {
+	models.UsageReportRepository
+	failing bool
+}
+
+func (f *failingReports) Add(ctx context.Context, participant, day string, counts models.UsageCounts) error {
+	if f.failing {
+		return errors.New("unavailable")
+	}
+	return f.UsageReportRepository.Add(ctx, participant, day, counts)
+}
+
+func TestRecorderCountsByDay(t *testing.T) {
+	clk := clock.NewSimulated()
+	r := NewRecorder(models.NewMemoryUsageReportRepository(clk), clk, 0)
+
+	r.Record("12345678", http.StatusCreated, 1)
+	r.Record("12345678", http.StatusTooManyRequests, 0)
+	clk.Advance(24 * time.Hour)
+	r.Record("12345678", http.StatusInternalServerError, 3)
+	r.Record("87654321", http.StatusOK, 1)
+
+	reports, err := r.Report(context.Background(), models.UsageReportFilter{Participant: "12345678"})
+	if err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	if len(reports) != 2 {
+		t.Fatalf("reported %d days, want 2", len(reports))
+	}
+	first, second := reports[0].ToResponse(), reports[1].ToResponse()
+	if first.Day >= second.Day {
+		t.Errorf("days %s and %s are not in order", first.Day, second.Day)
+	}
+	if first.Requests != 2 || first.ClientErrors != 1 || first.ErrorRate != 0.5 || first.TokensConsumed != 1 {
+		t.Errorf("first day = %+v, want 2 requests, 1 client error (rate 0.5) and 1 token", first)
+	}
+	if second.Requests != 1 || second.ServerErrors != 1 || second.TokensConsumed != 3 {
+		t.Errorf("second day = %+v, want 1 request, 1 server error and 3 tokens", second)
+	}
+}
+
+func TestRecorderKeepsCountsThatFailToFlush(t *testing.T) {
+	clk := clock.NewSimulated()
+	reports := &failingReports{UsageReportRepository: models.NewMemoryUsageReportRepository(clk), failing: true}
+	r := NewRecorder(reports, clk, 0)
+
+	r.Record("12345678", http.StatusOK, 1)
+	if err := r.Flush(context.Background()); err == nil {
+		t.Fatal("Flush succeeded while the reports were unavailable")
+	}
+
+	reports.failing = false
+	r.Record("12345678", http.StatusOK, 1)
+	got, err := r.Report(context.Background(), models.UsageReportFilter{})
+	if err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	if len(got) != 1 || got[0].Requests != 2 || got[0].TokensConsumed != 2 {
+		t.Errorf("reports = %+v, want both requests counted once", got)
+	}
+}
+
+func TestRunFlushesWhenCancelled(t *testing.T) {
+	clk := clock.NewSimulated()
+	reports := models.NewMemoryUsageReportRepository(clk)
+	r := NewRecorder(reports, clk, time.Hour)
+	r.Record("12345678", http.StatusOK, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.Run(ctx)
+	}()
+	cancel()
+	<-done
+
+	got, _ := reports.List(context.Background(), models.UsageReportFilter{})
+	if len(got) != 1 || got[0].Requests != 1 {
+		t.Errorf("reports = %+v, want the pending request flushed", got)
+	}
+}
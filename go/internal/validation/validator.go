@@ -1,7 +1,6 @@
 package validation
 
 import (
-	"regexp"
 	"sync"
 
 	"github.com/go-playground/validator/v10"
@@ -12,6 +11,12 @@ var (
 	once     sync.Once
 )
 
+// Check digit weights for CNPJ (Modulo 11)
+var (
+	cnpjWeights1 = [12]int{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+	cnpjWeights2 = [13]int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+)
+
 // Get returns the singleton validator instance with custom validators registered
 func Get() *validator.Validate {
 	once.Do(func() {
@@ -21,6 +26,7 @@ func Get() *validator.Validate {
 		validate.RegisterValidation("participant_id", validateParticipantID)
 		validate.RegisterValidation("tax_id", validateTaxID)
 		validate.RegisterValidation("evp", validateEVP)
+		validate.RegisterValidation("end_to_end_id", validateEndToEndID)
 	})
 	return validate
 }
@@ -32,129 +38,193 @@ func Validate(s any) error {
 
 // validateParticipantID validates an 8-digit ISPB participant ID
 func validateParticipantID(fl validator.FieldLevel) bool {
-	value := fl.Field().String()
-	matched, _ := regexp.MatchString(`^[0-9]{8}$`, value)
-	return matched
+	return IsDigits(fl.Field().String(), 8)
 }
 
 // validateTaxID validates a CPF (11 digits) or CNPJ (14 digits)
 func validateTaxID(fl validator.FieldLevel) bool {
 	value := fl.Field().String()
-	// Check if it's a valid CPF or CNPJ format
-	if matched, _ := regexp.MatchString(`^[0-9]{11}$`, value); matched {
+	switch len(value) {
+	case 11:
 		return IsValidCPF(value)
-	}
-	if matched, _ := regexp.MatchString(`^[0-9]{14}$`, value); matched {
+	case 14:
 		return IsValidCNPJ(value)
+	default:
+		return false
 	}
-	return false
 }
 
 // validateEVP validates a UUID v4 format for EVP keys
 func validateEVP(fl validator.FieldLevel) bool {
-	value := fl.Field().String()
-	// EVP must be lowercase UUID v4
-	matched, _ := regexp.MatchString(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, value)
-	return matched
+	return IsEVP(fl.Field().String())
 }
 
-// IsValidCPF validates CPF using Modulo 11 algorithm
-func IsValidCPF(cpf string) bool {
-	if len(cpf) != 11 {
+// validateEndToEndID validates an SPI end-to-end ID: E, the payer's ISPB, yyyyMMddHHmm and 11 alphanumerics
+func validateEndToEndID(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if len(value) != 32 || value[0] != 'E' || !IsDigits(value[1:21], 20) {
 		return false
 	}
+	for i := 21; i < len(value); i++ {
+		c := value[i]
+		if !isDigit(c) && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	return true
+}
 
-	// All same digits is invalid (e.g., 00000000000, 11111111111, etc.)
-	allSame := true
-	for i := 1; i < len(cpf); i++ {
-		if cpf[i] != cpf[0] {
-			allSame = false
-			break
+// IsDigits reports whether s is exactly n ASCII digits
+func IsDigits(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
 		}
 	}
-	if allSame {
+	return true
+}
+
+// IsEVP reports whether s is a lowercase UUID v4, the format DICT uses for EVP keys
+func IsEVP(s string) bool {
+	if len(s) != 36 || s[14] != '4' {
+		return false
+	}
+	switch s[19] {
+	case '8', '9', 'a', 'b':
+	default:
 		return false
 	}
+	for i := 0; i < len(s); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if s[i] != '-' {
+				return false
+			}
+		default:
+			if c := s[i]; !isDigit(c) && (c < 'a' || c > 'f') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// allSame reports whether every byte of s equals the first, e.g. 00000000000 or 11111111111
+func allSame(s string) bool {
+	for i := 1; i < len(s); i++ {
+		if s[i] != s[0] {
+			return false
+		}
+	}
+	return true
+}
 
-	digits := make([]int, 11)
-	for i, c := range cpf {
-		digits[i] = int(c - '0')
+// IsValidCPF validates CPF using Modulo 11 algorithm
+func IsValidCPF(cpf string) bool {
+	// All same digits is invalid (e.g., 00000000000, 11111111111, etc.)
+	if !IsDigits(cpf, 11) || allSame(cpf) {
+		return false
 	}
 
 	// First check digit
 	sum := 0
 	for i := range 9 {
-		sum += digits[i] * (10 - i)
+		sum += int(cpf[i]-'0') * (10 - i)
 	}
 	remainder := (sum * 10) % 11
 	if remainder == 10 {
 		remainder = 0
 	}
-	if remainder != digits[9] {
+	if remainder != int(cpf[9]-'0') {
 		return false
 	}
 
 	// Second check digit
 	sum = 0
 	for i := range 10 {
-		sum += digits[i] * (11 - i)
+		sum += int(cpf[i]-'0') * (11 - i)
 	}
 	remainder = (sum * 10) % 11
 	if remainder == 10 {
 		remainder = 0
 	}
-	return remainder == digits[10]
+	return remainder == int(cpf[10]-'0')
 }
 
-// IsValidCNPJ validates CNPJ using Modulo 11 algorithm
-func IsValidCNPJ(cnpj string) bool {
-	if len(cnpj) != 14 {
+// IsValidAccountCheckDigit validates an account number whose last digit is its Modulo 11 check digit
+// The other digits are weighted 2 to 9 from the right, repeating; a check digit of 10 or 11 is 0.
+func IsValidAccountCheckDigit(account string) bool {
+	if len(account) < 2 || !IsDigits(account, len(account)) {
 		return false
 	}
 
-	// All same digits is invalid (e.g., 00000000000000, 11111111111111, etc.)
-	allSame := true
-	for i := 1; i < len(cnpj); i++ {
-		if cnpj[i] != cnpj[0] {
-			allSame = false
-			break
-		}
+	last := len(account) - 1
+	sum := 0
+	for i := last - 1; i >= 0; i-- {
+		sum += int(account[i]-'0') * (2 + (last-1-i)%8)
+	}
+	check := 11 - sum%11
+	if check >= 10 {
+		check = 0
 	}
-	if allSame {
+	return check == int(account[last]-'0')
+}
+
+// IsBrazilianPhone reports whether phone is a Brazilian number in E.164: +55, a two-digit area code
+// without zeros, then a 9-digit mobile number starting with 9 or an 8-digit landline starting with 2 to 5
+func IsBrazilianPhone(phone string) bool {
+	if len(phone) < 5 || phone[:3] != "+55" || phone[3] == '0' || phone[4] == '0' ||
+		!IsDigits(phone[3:], len(phone)-3) {
 		return false
 	}
 
-	digits := make([]int, 14)
-	for i, c := range cnpj {
-		digits[i] = int(c - '0')
+	switch number := phone[5:]; len(number) {
+	case 9:
+		return number[0] == '9'
+	case 8:
+		return number[0] >= '2' && number[0] <= '5'
+	default:
+		return false
 	}
+}
 
-	weights1 := []int{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
-	weights2 := []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+// IsValidCNPJ validates CNPJ using Modulo 11 algorithm
+func IsValidCNPJ(cnpj string) bool {
+	// All same digits is invalid (e.g., 00000000000000, 11111111111111, etc.)
+	if !IsDigits(cnpj, 14) || allSame(cnpj) {
+		return false
+	}
 
 	// First check digit
 	sum := 0
-	for i := range 12 {
-		sum += digits[i] * weights1[i]
+	for i, w := range cnpjWeights1 {
+		sum += int(cnpj[i]-'0') * w
 	}
 	remainder := sum % 11
 	firstCheck := 0
 	if remainder >= 2 {
 		firstCheck = 11 - remainder
 	}
-	if firstCheck != digits[12] {
+	if firstCheck != int(cnpj[12]-'0') {
 		return false
 	}
 
 	// Second check digit
 	sum = 0
-	for i := range 13 {
-		sum += digits[i] * weights2[i]
+	for i, w := range cnpjWeights2 {
+		sum += int(cnpj[i]-'0') * w
 	}
 	remainder = sum % 11
 	secondCheck := 0
 	if remainder >= 2 {
 		secondCheck = 11 - remainder
 	}
-	return secondCheck == digits[13]
+	return secondCheck == int(cnpj[13]-'0')
 }
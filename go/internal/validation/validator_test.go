@@ -0,0 +1,155 @@
+package validation
+
+import "testing"
+
+// tagged exercises the custom tags the DICT models use
+type tagged struct {
+	Participant string `validate:"participant_id"`
+	TaxID       string `validate:"tax_id"`
+	EVP         string `validate:"evp"`
+	EndToEndID  string `validate:"end_to_end_id"`
+}
+
+func validTagged() tagged {
+	return tagged{
+		Participant: "12345678",
+		TaxID:       "11144477735",
+		EVP:         "123e4567-e89b-42d3-a456-426614174000",
+		EndToEndID:  "E1234567820240115103000000000001",
+	}
+}
+
+func TestCustomTags(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*tagged)
+		wantOK bool
+	}{
+		{"all valid", func(*tagged) {}, true},
+		{"CNPJ tax ID", func(v *tagged) { v.TaxID = "11222333000181" }, true},
+		{"short participant", func(v *tagged) { v.Participant = "1234567" }, false},
+		{"non-digit participant", func(v *tagged) { v.Participant = "1234567a" }, false},
+		{"tax ID with wrong check digit", func(v *tagged) { v.TaxID = "11144477736" }, false},
+		{"tax ID of 12 digits", func(v *tagged) { v.TaxID = "111444777350" }, false},
+		{"uppercase EVP", func(v *tagged) { v.EVP = "123E4567-E89B-42D3-A456-426614174000" }, false},
+		{"EVP version 1", func(v *tagged) { v.EVP = "123e4567-e89b-12d3-a456-426614174000" }, false},
+		{"EVP bad variant", func(v *tagged) { v.EVP = "123e4567-e89b-42d3-c456-426614174000" }, false},
+		{"EVP misplaced dash", func(v *tagged) { v.EVP = "123e4567e-89b-42d3-a456-426614174000" }, false},
+		{"end-to-end ID with mixed-case suffix", func(v *tagged) { v.EndToEndID = "E12345678202401151030AbCdEfGh123" }, true},
+		{"end-to-end ID without prefix", func(v *tagged) { v.EndToEndID = "X1234567820240115103000000000001" }, false},
+		{"end-to-end ID with letter in timestamp", func(v *tagged) { v.EndToEndID = "E1234567820240115103a00000000001" }, false},
+		{"end-to-end ID with letter in ISPB", func(v *tagged) { v.EndToEndID = "E1234567a20240115103000000000001" }, false},
+		{"end-to-end ID with symbol", func(v *tagged) { v.EndToEndID = "E123456782024011510300000000000-" }, false},
+		{"end-to-end ID too short", func(v *tagged) { v.EndToEndID = "E123456782024011510300000000000" }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := validTagged()
+			tt.modify(&v)
+			if err := Validate(&v); (err == nil) != tt.wantOK {
+				t.Errorf("Validate(%+v) error = %v, want ok = %v", v, err, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestIsDigits(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want bool
+	}{
+		{"12345678", 8, true},
+		{"", 0, true},
+		{"1234567", 8, false},
+		{"123456789", 8, false},
+		{"1234 678", 8, false},
+		{"١٢٣٤٥٦٧٨", 16, false}, // Arabic-Indic digits are not ASCII digits
+	}
+
+	for _, tt := range tests {
+		if got := IsDigits(tt.s, tt.n); got != tt.want {
+			t.Errorf("IsDigits(%q, %d) = %v, want %v", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestIsValidTaxIDRejectsNonDigits(t *testing.T) {
+	if IsValidCPF("1114447773/") {
+		t.Error("IsValidCPF accepted a non-digit")
+	}
+	if IsValidCNPJ("1122233300018/") {
+		t.Error("IsValidCNPJ accepted a non-digit")
+	}
+}
+
+func TestIsValidAccountCheckDigit(t *testing.T) {
+	tests := []struct {
+		account string
+		want    bool
+	}{
+		{"0007654321", false},
+		{"0007654324", true},
+		{"123456789010", true}, // weights repeat after 9
+		{"00", true},           // check digit 11 is 0
+		{"1", false},
+		{"000765432/", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsValidAccountCheckDigit(tt.account); got != tt.want {
+			t.Errorf("IsValidAccountCheckDigit(%q) = %v, want %v", tt.account, got, tt.want)
+		}
+	}
+}
+
+func TestIsBrazilianPhone(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  bool
+	}{
+		{"+5511987654321", true},  // mobile
+		{"+551133334444", true},   // landline
+		{"+5511887654321", false}, // mobile without the leading 9
+		{"+551163334444", false},  // 8 digits starting with 6 are not landlines
+		{"+5501987654321", false}, // area code with a zero
+		{"+14155552671", false},
+		{"+55119876543210", false},
+		{"+55", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsBrazilianPhone(tt.phone); got != tt.want {
+			t.Errorf("IsBrazilianPhone(%q) = %v, want %v", tt.phone, got, tt.want)
+		}
+	}
+}
+
+func BenchmarkCustomTags(b *testing.B) {
+	v := validTagged()
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := Validate(&v); err != nil {
+			b.Fatalf("Validate() error = %v", err)
+		}
+	}
+}
+
+func BenchmarkIsValidCPF(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		if !IsValidCPF("11144477735") {
+			b.Fatal("IsValidCPF failed")
+		}
+	}
+}
+
+func BenchmarkIsValidCNPJ(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		if !IsValidCNPJ("11222333000181") {
+			b.Fatal("IsValidCNPJ failed")
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.uber.org/zap"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/events"
+	"github.com/dict-simulator/go/internal/logger"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+// Headers sent with every callback
+const (
+	SignatureHeader = "X-DICT-Signature"
+	EventIDHeader   = "X-DICT-Event-Id"
+	EventTypeHeader = "X-DICT-Event-Type"
+	AttemptHeader   = "X-DICT-Delivery-Attempt"
+)
+
+// Config controls delivery timeouts and retries
+type Config struct {
+	Timeout        time.Duration // per-attempt HTTP timeout
+	MaxAttempts    int           // including the first delivery
+	InitialBackoff time.Duration // wait before the first retry, doubled on each retry
+	MaxBackoff     time.Duration
+}
+
+// Dispatcher delivers events to the webhooks registered by the event's participant
+// Deliveries run in the background; every attempt is recorded in the delivery log.
+type Dispatcher struct {
+	webhooks   models.WebhookRepository
+	deliveries models.WebhookDeliveryRepository
+	client     *http.Client
+	clock      clock.Clock
+	cfg        Config
+
+	wg       sync.WaitGroup
+	stopping chan struct{}
+	stopOnce sync.Once
+}
+
+// NewDispatcher creates a webhook dispatcher
+func NewDispatcher(webhooks models.WebhookRepository, deliveries models.WebhookDeliveryRepository, clk clock.Clock, cfg Config) *Dispatcher {
+	return &Dispatcher{
+		webhooks:   webhooks,
+		deliveries: deliveries,
+		client: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+		},
+		clock:    clk,
+		cfg:      cfg,
+		stopping: make(chan struct{}),
+	}
+}
+
+// Publish fans the event out to every subscribed webhook without blocking the caller
+func (d *Dispatcher) Publish(ctx context.Context, event events.Event) {
+	// Keep the trace but not the request's cancellation; relayed events bring back the request's trace
+	ctx = event.TraceContext(context.WithoutCancel(ctx))
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+
+		webhooks, err := d.webhooks.FindSubscribers(ctx, event.Participant, event.Type)
+		if err != nil {
+			logger.Error("Failed to find webhook subscribers",
+				zap.String("eventId", event.ID),
+				zap.String("eventType", string(event.Type)),
+				zap.Error(err),
+			)
+			return
+		}
+		if len(webhooks) == 0 {
+			return
+		}
+
+		body, err := json.Marshal(event)
+		if err != nil {
+			logger.Error("Failed to encode webhook event", zap.String("eventId", event.ID), zap.Error(err))
+			return
+		}
+
+		for i := range webhooks {
+			d.wg.Add(1)
+			go func(webhook *models.Webhook) {
+				defer d.wg.Done()
+				d.deliver(ctx, webhook, event, body)
+			}(&webhooks[i])
+		}
+	}()
+}
+
+// Shutdown stops scheduling retries and waits for in-flight deliveries
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	d.stopOnce.Do(func() { close(d.stopping) })
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// deliver sends the event to one webhook, retrying with exponential backoff until it succeeds
+func (d *Dispatcher) deliver(ctx context.Context, webhook *models.Webhook, event events.Event, body []byte) {
+	for attempt := 1; attempt <= d.cfg.MaxAttempts; attempt++ {
+		delivery := d.attempt(ctx, webhook, event, body, attempt)
+
+		if err := d.deliveries.Create(ctx, delivery); err != nil {
+			logger.Error("Failed to record webhook delivery",
+				zap.String("webhookId", webhook.ID.Hex()),
+				zap.String("eventId", event.ID),
+				zap.Error(err),
+			)
+		}
+
+		if delivery.Success || attempt == d.cfg.MaxAttempts {
+			if !delivery.Success {
+				logger.Warn("Webhook delivery failed, giving up",
+					zap.String("webhookId", webhook.ID.Hex()),
+					zap.String("eventId", event.ID),
+					zap.Int("attempts", attempt),
+				)
+			}
+			return
+		}
+
+		select {
+		case <-time.After(d.backoff(attempt)):
+		case <-d.stopping:
+			return
+		}
+	}
+}
+
+// attempt performs a single signed POST and describes its outcome
+func (d *Dispatcher) attempt(ctx context.Context, webhook *models.Webhook, event events.Event, body []byte, attempt int) *models.WebhookDelivery {
+	now := d.clock.Now()
+	delivery := &models.WebhookDelivery{
+		WebhookID: webhook.ID,
+		EventID:   event.ID,
+		EventType: event.Type,
+		URL:       webhook.URL,
+		Attempt:   attempt,
+		CreatedAt: now.UTC(),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, now.Unix(), body))
+	req.Header.Set(EventIDHeader, event.ID)
+	req.Header.Set(EventTypeHeader, string(event.Type))
+	req.Header.Set(AttemptHeader, strconv.Itoa(attempt))
+	// The transport replaces it with its own client span when tracing is enabled
+	propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	start := time.Now()
+	resp, err := d.client.Do(req)
+	delivery.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	delivery.StatusCode = resp.StatusCode
+	delivery.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+	return delivery
+}
+
+// backoff returns the wait before retrying after the given attempt
+// Doubles from InitialBackoff up to MaxBackoff, with up to 20% jitter to spread retries
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	wait := d.cfg.InitialBackoff << (attempt - 1)
+	if wait <= 0 || wait > d.cfg.MaxBackoff {
+		wait = d.cfg.MaxBackoff
+	}
+	if wait <= 0 {
+		return 0
+	}
+	return wait + rand.N(wait/5+1)
+}
+
+// Sign computes the signature header value for a callback body
+// Format: t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>" keyed by the webhook secret>
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dict-simulator/go/internal/clock"
+	"github.com/dict-simulator/go/internal/events"
+	"github.com/dict-simulator/go/internal/models"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"evt-1","type":"ENTRY_CREATED"}`)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1700000000."))
+	mac.Write(body)
+	want := "t=1700000000,v1=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := Sign("secret", 1700000000, body); got != want {
+		t.Errorf("Sign() = %q, want %q", got, want)
+	}
+	if Sign("other-secret", 1700000000, body) == want {
+		t.Errorf("Sign() with a different secret produced the same signature")
+	}
+}
+
+func TestDispatcherBackoff(t *testing.T) {
+	d := &Dispatcher{cfg: Config{
+		InitialBackoff: time.Second,
+		MaxBackoff:     10 * time.Second,
+	}}
+
+	tests := []struct {
+		attempt int
+		base    time.Duration
+	}{
+		{attempt: 1, base: time.Second},
+		{attempt: 2, base: 2 * time.Second},
+		{attempt: 3, base: 4 * time.Second},
+		{attempt: 4, base: 8 * time.Second},
+		{attempt: 5, base: 10 * time.Second}, // capped
+		{attempt: 80, base: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		got := d.backoff(tt.attempt)
+		if got < tt.base || got > tt.base+tt.base/5 {
+			t.Errorf("backoff(%d) = %v, want between %v and %v", tt.attempt, got, tt.base, tt.base+tt.base/5)
+		}
+	}
+}
+
+func TestDispatcherPropagatesTheEventTrace(t *testing.T) {
+	traceparents := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents <- r.Header.Get("traceparent")
+	}))
+	defer srv.Close()
+
+	clk := clock.NewSimulated()
+	webhooks := models.NewMemoryWebhookRepository(clk)
+	if _, err := webhooks.Create(context.Background(), &models.CreateWebhookRequest{
+		Participant: "12345678",
+		URL:         srv.URL,
+		Secret:      "secret",
+		Events:      []events.Type{events.EntryCreated},
+	}); err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	d := NewDispatcher(webhooks, models.NewMemoryWebhookDeliveryRepository(), clk, Config{Timeout: time.Second, MaxAttempts: 1})
+
+	// As relayed from the outbox: the event carries the trace, the context does not
+	event := events.New(events.EntryCreated, "12345678", nil, clk.Now())
+	event.TraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	d.Publish(context.Background(), event)
+	if err := d.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	select {
+	case got := <-traceparents:
+		if got != event.TraceParent {
+			t.Errorf("callback traceparent = %q, want %q", got, event.TraceParent)
+		}
+	default:
+		t.Fatal("the webhook was not called")
+	}
+}
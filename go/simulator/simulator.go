@@ -0,0 +1,252 @@
+// Package simulator embeds the whole DICT simulator in another Go program.
+//
+// New wires every route with in-memory storage and an in-memory rate limiter, so a test suite
+// can run the simulator without MongoDB, Redis or containers:
+//
+//	sim := simulator.New(simulator.WithJWTSecret("test-secret"))
+//	defer sim.Shutdown(context.Background())
+//
+//	srv := httptest.NewServer(sim)
+//	defer srv.Close()
+//
+// Each Simulator has its own stores, clock and fault rules, so parallel tests can each use one.
+package simulator
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/dict-simulator/go/internal/app"
+	"github.com/dict-simulator/go/internal/config"
+	"github.com/dict-simulator/go/internal/jwtkeys"
+	"github.com/dict-simulator/go/internal/keypolicy"
+	"github.com/dict-simulator/go/internal/middleware"
+	"github.com/dict-simulator/go/internal/models"
+	"github.com/dict-simulator/go/internal/quota"
+	"github.com/dict-simulator/go/internal/ratelimit"
+)
+
+// DefaultJWTSecret signs and verifies tokens unless WithJWTSecret is given
+const DefaultJWTSecret = "dict-simulator-embedded"
+
+// DefaultAdminToken unlocks the /admin routes unless WithAdminToken is given
+const DefaultAdminToken = "dict-simulator-admin"
+
+// AdminTokenHeader is the header the /admin routes read the admin token from
+const AdminTokenHeader = middleware.AdminTokenHeader
+
+// Option configures a Simulator
+type Option func(*config.Config)
+
+// WithJWTSecret sets the secret used to sign and verify bearer tokens
+func WithJWTSecret(secret string) Option {
+	return func(cfg *config.Config) {
+		cfg.JWTSecret = secret
+	}
+}
+
+// WithJWTAlgorithm signs tokens with RS256 or ES256 key pairs instead of the secret
+// The public key is served at /.well-known/jwks.json; it is generated by New and never rotated.
+func WithJWTAlgorithm(alg string) Option {
+	return func(cfg *config.Config) {
+		cfg.JWTAlgorithm = alg
+	}
+}
+
+// WithRateLimit enables or disables the DICT rate limiting policies (disabled by default)
+func WithRateLimit(enabled bool) Option {
+	return func(cfg *config.Config) {
+		cfg.RateLimitEnabled = enabled
+	}
+}
+
+// WithRateLimitInitialFill starts new buckets with fill (0 to 1) of their size instead of full,
+// so tests can reach exhaustion without spending the whole budget first
+func WithRateLimitInitialFill(fill float64) Option {
+	return func(cfg *config.Config) {
+		cfg.RateLimitInitialFill = fill
+	}
+}
+
+// WithRateLimitAlgorithms selects the throttling model of each policy, keyed by policy name
+// (e.g. "ENTRIES_READ_PARTICIPANT_ANTISCAN"), with "*" for the policies not listed: "token_bucket"
+// (the default), "sliding_window" or "gcra"
+func WithRateLimitAlgorithms(algorithms map[string]string) Option {
+	return func(cfg *config.Config) {
+		cfg.RateLimitAlgorithms = ratelimit.Algorithms{}
+		for policy, algorithm := range algorithms {
+			cfg.RateLimitAlgorithms[ratelimit.PolicyName(policy)] = ratelimit.Algorithm(algorithm)
+		}
+	}
+}
+
+// WithLoginLockout locks an account for d after maxFailures wrong passwords (off by default)
+// POST /admin/users/{id}/unlock lifts a lock early.
+func WithLoginLockout(maxFailures int, d time.Duration) Option {
+	return func(cfg *config.Config) {
+		cfg.LoginMaxFailures = maxFailures
+		cfg.LoginLockoutDuration = d
+	}
+}
+
+// WithKeyCreationLimit refuses POST /entries once an owner (tax ID) has created limit keys on the
+// simulated day (off by default)
+func WithKeyCreationLimit(limit int) Option {
+	return func(cfg *config.Config) {
+		cfg.KeyCreationDailyLimit = limit
+	}
+}
+
+// WithAccountRules refuses accounts opened before minOpeningDate (unless zero) and, with checkDigit,
+// account numbers that don't end with their Modulo 11 check digit (both off by default)
+func WithAccountRules(minOpeningDate time.Time, checkDigit bool) Option {
+	return func(cfg *config.Config) {
+		cfg.AccountMinOpeningDate = minOpeningDate
+		cfg.AccountNumberCheckDigit = checkDigit
+	}
+}
+
+// WithKeyPolicies refuses creations of keys of disabledKeyTypes (e.g. "EVP") and, with
+// brazilianPhonesOnly, of PHONE keys that aren't +55 numbers. PUT /admin/key-policies changes them later.
+func WithKeyPolicies(disabledKeyTypes []string, brazilianPhonesOnly bool) Option {
+	return func(cfg *config.Config) {
+		cfg.KeyPolicies = keypolicy.Policies{BrazilianPhonesOnly: brazilianPhonesOnly}
+		for _, keyType := range disabledKeyTypes {
+			cfg.KeyPolicies.DisabledKeyTypes = append(cfg.KeyPolicies.DisabledKeyTypes, models.KeyType(keyType))
+		}
+	}
+}
+
+// WithQuota holds every participant to at most maxEntries stored entries and requestsPerMinute DICT
+// requests (zero sets no cap; off by default). /admin/quotas changes it later, per participant too.
+func WithQuota(maxEntries, requestsPerMinute int) Option {
+	return func(cfg *config.Config) {
+		cfg.DefaultQuota = quota.Quota{MaxEntries: maxEntries, RequestsPerMinute: requestsPerMinute}
+	}
+}
+
+// WithAdmin mounts or hides the /admin routes (mounted by default)
+func WithAdmin(enabled bool) Option {
+	return func(cfg *config.Config) {
+		cfg.AdminEnabled = enabled
+	}
+}
+
+// WithTimeTravel mounts or hides the /admin/time routes that move the simulated clock (mounted by default)
+func WithTimeTravel(enabled bool) Option {
+	return func(cfg *config.Config) {
+		cfg.TimeTravelEnabled = enabled
+	}
+}
+
+// WithAdminToken sets the X-Admin-Token value the /admin routes require
+func WithAdminToken(token string) Option {
+	return func(cfg *config.Config) {
+		cfg.AdminToken = token
+	}
+}
+
+// WithDocs serves or hides /openapi.json and the Swagger UI (served by default)
+func WithDocs(enabled bool) Option {
+	return func(cfg *config.Config) {
+		cfg.DocsEnabled = enabled
+	}
+}
+
+// WithOpenAPIValidation turns the contract checks against the OpenAPI document on or off (on by default)
+func WithOpenAPIValidation(enabled bool) Option {
+	return func(cfg *config.Config) {
+		cfg.OpenAPIValidation = enabled
+	}
+}
+
+// WithWebhookRetries sets how many times a callback is attempted and the wait before the first retry
+// The wait doubles on each retry, up to ten times the initial backoff.
+func WithWebhookRetries(maxAttempts int, initialBackoff time.Duration) Option {
+	return func(cfg *config.Config) {
+		cfg.WebhookMaxAttempts = maxAttempts
+		cfg.WebhookInitialBackoff = initialBackoff
+		cfg.WebhookMaxBackoff = 10 * initialBackoff
+	}
+}
+
+// WithRequestSigning requires the entries routes to be signed with X-Signature, using each
+// participant's secret (keyed by ISPB); timestamps may be up to five minutes off.
+func WithRequestSigning(secrets map[string]string) Option {
+	return func(cfg *config.Config) {
+		cfg.RequestSigningEnabled = true
+		cfg.RequestSigningSecrets = secrets
+		cfg.RequestSigningMaxSkew = 5 * time.Minute
+	}
+}
+
+// WithCorrelationReplayWindow refuses signed requests that reuse one of the participant's
+// X-Correlation-Id values within window. It only applies along with WithRequestSigning.
+func WithCorrelationReplayWindow(window time.Duration) Option {
+	return func(cfg *config.Config) {
+		cfg.RequestCorrelationWindow = window
+	}
+}
+
+// Simulator is the DICT API as an http.Handler
+type Simulator struct {
+	app *app.Application
+}
+
+// New creates a Simulator backed by in-memory stores
+// Webhook retries are short by default so tests don't wait on production delays.
+func New(opts ...Option) *Simulator {
+	cfg := &config.Config{
+		Environment:            "test",
+		Storage:                config.StorageMemory,
+		JWTSecret:              DefaultJWTSecret,
+		JWTAlgorithm:           jwtkeys.HS256,
+		RateLimitBucketSize:    60,
+		RateLimitRefillSeconds: 60,
+		RateLimitInitialFill:   1,
+		AdminEnabled:           true,
+		AdminToken:             DefaultAdminToken,
+		TimeTravelEnabled:      true,
+		DocsEnabled:            true,
+		OpenAPIValidation:      true,
+		MaxBodyBytes:           1 << 20,
+		MaxImportBytes:         256 << 20,
+		CompressionEnabled:     true,
+		CompressionMinBytes:    1024,
+		IdempotencyMaxResponse: 64 << 10,
+		Idempotency4xxTTL:      5 * time.Minute,
+		AccessLogSampleRate:    1,
+		WebhookTimeout:         5 * time.Second,
+		WebhookMaxAttempts:     3,
+		WebhookInitialBackoff:  100 * time.Millisecond,
+		WebhookMaxBackoff:      time.Second,
+		EventSource:            config.EventSourceInline,
+		PasswordResetTTL:       time.Hour,
+		OAuthTokenTTL:          time.Hour,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	// No scheduler runs here, so the first key pair signs for the simulator's lifetime and no
+	// reconciliation file is ever generated. Nothing re-reads the configuration either.
+	application, err := app.BuildApplication(context.Background(), cfg, app.Dependencies{})
+	if err != nil {
+		panic("simulator: " + err.Error())
+	}
+	application.Start()
+
+	return &Simulator{app: application}
+}
+
+// ServeHTTP serves the DICT API
+func (s *Simulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.app.ServeHTTP(w, r)
+}
+
+// Shutdown waits for in-flight webhook deliveries; pending retries are abandoned
+// Stop serving requests (e.g. httptest.Server.Close) before calling it.
+func (s *Simulator) Shutdown(ctx context.Context) error {
+	return s.app.Shutdown(ctx)
+}
@@ -0,0 +1,1950 @@
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/dict-simulator/go/internal/signing"
+)
+
+// validCPF passes the CPF check digit validation
+const validCPF = "52998224725"
+
+func startSimulator(t *testing.T, opts ...Option) *httptest.Server {
+	t.Helper()
+
+	sim := New(opts...)
+	srv := httptest.NewServer(sim)
+	t.Cleanup(func() {
+		srv.Close()
+		sim.Shutdown(context.Background())
+	})
+	return srv
+}
+
+func do(t *testing.T, srv *httptest.Server, method, path, token string, body any) *http.Response {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("encode body: %v", err)
+		}
+	}
+
+	req, err := http.NewRequest(method, srv.URL+path, &buf)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	// Admin routes take the admin token instead of a user JWT
+	if strings.HasPrefix(path, "/admin/") {
+		req.Header.Set(AdminTokenHeader, DefaultAdminToken)
+	}
+	// Entry creation requires an idempotency key; each call is a new request
+	if method == http.MethodPost && path == "/entries" {
+		req.Header.Set("X-Idempotency-Key", strconv.FormatInt(time.Now().UnixNano(), 10))
+	}
+
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func register(t *testing.T, srv *httptest.Server) string {
+	t.Helper()
+
+	resp := do(t, srv, http.MethodPost, "/auth/register", "", map[string]string{
+		"email":    "sdk@example.com",
+		"password": "testpassword123",
+		"name":     "SDK Test",
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("register status = %d, want 201", resp.StatusCode)
+	}
+
+	var result struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode register response: %v", err)
+	}
+	return result.Data.Token
+}
+
+func TestSimulatorEntryRoundTrip(t *testing.T) {
+	srv := startSimulator(t)
+	token := register(t, srv)
+
+	resp := do(t, srv, http.MethodPost, "/entries", token, map[string]any{
+		"key":     validCPF,
+		"keyType": "CPF",
+		"account": map[string]any{
+			"participant":   "12345678",
+			"branch":        "0001",
+			"accountNumber": "0007654321",
+			"accountType":   "CACC",
+			"openingDate":   time.Now().UTC().Format(time.RFC3339),
+		},
+		"owner": map[string]any{
+			"type":        "NATURAL_PERSON",
+			"taxIdNumber": validCPF,
+			"name":        "SDK Test",
+		},
+		"reason":    "USER_REQUESTED",
+		"requestId": uuid.New().String(),
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create status = %d, want 201", resp.StatusCode)
+	}
+
+	resp = do(t, srv, http.MethodGet, "/entries/"+validCPF, token, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("get status = %d, want 200", resp.StatusCode)
+	}
+	if resp.Header.Get("X-RateLimit-Limit") != "" {
+		t.Error("rate limit headers set, want rate limiting disabled by default")
+	}
+
+	resp = do(t, srv, http.MethodGet, "/metrics", "", nil)
+	metrics, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read metrics: %v", err)
+	}
+	if !strings.Contains(string(metrics), `dict_entries_created_total{key_type="CPF"}`) {
+		t.Error("metrics do not count the created CPF entry")
+	}
+	if !strings.Contains(string(metrics), `http_requests_total{method="GET",path="/entries/{key}",status="200"}`) {
+		t.Error("metrics do not label the lookup with its route")
+	}
+}
+
+func TestSimulatorSettlements(t *testing.T) {
+	srv := startSimulator(t)
+	token := register(t, srv)
+
+	resp := do(t, srv, http.MethodPost, "/admin/seed", "", map[string]any{"count": 2, "seed": 7, "participants": []map[string]any{{"ispb": "12345678", "weight": 1}}})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("seed status = %d, want 201", resp.StatusCode)
+	}
+
+	var keys []string
+	export := do(t, srv, http.MethodGet, "/admin/export?format=ndjson", "", nil)
+	decoder := json.NewDecoder(export.Body)
+	for decoder.More() {
+		var entry struct {
+			Key string `json:"key"`
+		}
+		if err := decoder.Decode(&entry); err != nil {
+			t.Fatalf("decode export: %v", err)
+		}
+		keys = append(keys, entry.Key)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("exported %d entries, want 2", len(keys))
+	}
+
+	const endToEndID = "E1234567820240115103000000000001"
+	resp = do(t, srv, http.MethodPost, "/settlements", token, map[string]any{
+		"endToEndId": endToEndID,
+		"amount":     15075,
+		"payerKey":   keys[0],
+		"payeeKey":   keys[1],
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("POST /settlements status = %d, want 201", resp.StatusCode)
+	}
+
+	if resp := do(t, srv, http.MethodGet, "/settlements/"+endToEndID, token, nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /settlements status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestSimulatorsAreIsolated(t *testing.T) {
+	first := startSimulator(t)
+	second := startSimulator(t)
+
+	// The same user can register on both since nothing is shared
+	register(t, first)
+	register(t, second)
+}
+
+func TestSimulatorOptions(t *testing.T) {
+	srv := startSimulator(t, WithAdmin(false), WithDocs(false), WithRateLimit(true))
+	token := register(t, srv)
+
+	if resp := do(t, srv, http.MethodGet, "/admin/time", token, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /admin/time status = %d, want 404 with admin disabled", resp.StatusCode)
+	}
+	if resp := do(t, srv, http.MethodGet, "/openapi.json", "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /openapi.json status = %d, want 404 with docs disabled", resp.StatusCode)
+	}
+	if resp := do(t, srv, http.MethodGet, "/entries/"+validCPF, token, nil); resp.Header.Get("X-RateLimit-Limit") == "" {
+		t.Error("rate limit headers missing with rate limiting enabled")
+	}
+}
+
+func TestSimulatorRateLimitInitialFill(t *testing.T) {
+	srv := startSimulator(t, WithRateLimit(true), WithRateLimitInitialFill(0.1))
+	token := register(t, srv)
+
+	// The antiscan bucket holds 50 tokens; a 404 costs 3
+	for _, want := range []string{"5", "2", "0"} {
+		resp := do(t, srv, http.MethodGet, "/entries/"+validCPF, token, nil)
+		if got := resp.Header.Get("X-RateLimit-Remaining"); got != want {
+			t.Fatalf("X-RateLimit-Remaining = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestSimulatorRateLimitAlgorithms(t *testing.T) {
+	srv := startSimulator(t, WithRateLimit(true), WithRateLimitInitialFill(0.1),
+		WithRateLimitAlgorithms(map[string]string{"*": "gcra"}))
+	token := register(t, srv)
+
+	// The same budget as a token bucket, drained by a GCRA
+	for _, want := range []string{"5", "2", "0"} {
+		resp := do(t, srv, http.MethodGet, "/entries/"+validCPF, token, nil)
+		if got := resp.Header.Get("X-RateLimit-Remaining"); got != want {
+			t.Fatalf("X-RateLimit-Remaining = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestSimulatorAdminToken(t *testing.T) {
+	srv := startSimulator(t, WithAdminToken("s3cret"))
+	token := register(t, srv)
+
+	// A user JWT is not enough, and neither is the default token once another is set
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/admin/faults", nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set(AdminTokenHeader, DefaultAdminToken)
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("GET /admin/faults: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("GET /admin/faults status = %d, want 401 without the configured admin token", resp.StatusCode)
+	}
+
+	req.Header.Set(AdminTokenHeader, "s3cret")
+	resp, err = srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("GET /admin/faults: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /admin/faults status = %d, want 200 with the admin token", resp.StatusCode)
+	}
+}
+
+func TestSimulatorLoginLockout(t *testing.T) {
+	srv := startSimulator(t, WithLoginLockout(3, time.Minute))
+	credentials := map[string]string{"email": "lockout@example.com", "password": "testpassword123", "name": "Lockout Test"}
+	var registered struct {
+		Data struct {
+			User struct {
+				ID string `json:"id"`
+			} `json:"user"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(do(t, srv, http.MethodPost, "/auth/register", "", credentials).Body).Decode(&registered); err != nil {
+		t.Fatalf("decode register response: %v", err)
+	}
+	login := func(password string) *http.Response {
+		return do(t, srv, http.MethodPost, "/auth/login", "", map[string]string{"email": credentials["email"], "password": password})
+	}
+
+	for i, want := range []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusLocked} {
+		if resp := login("wrong-password"); resp.StatusCode != want {
+			t.Fatalf("failed login %d status = %d, want %d", i+1, resp.StatusCode, want)
+		}
+	}
+	if resp := login(credentials["password"]); resp.StatusCode != http.StatusLocked || resp.Header.Get("Retry-After") != "60" {
+		t.Fatalf("login while locked status = %d retrying after %q, want 423 even with the right password", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+
+	resp := do(t, srv, http.MethodPost, "/admin/users/"+registered.Data.User.ID+"/unlock", "", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unlock status = %d, want 200", resp.StatusCode)
+	}
+	if resp := login(credentials["password"]); resp.StatusCode != http.StatusOK {
+		t.Errorf("login after unlock status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestSimulatorKeyCreationLimit(t *testing.T) {
+	srv := startSimulator(t, WithKeyCreationLimit(2))
+	token := register(t, srv)
+	create := func(email string) *http.Response {
+		return do(t, srv, http.MethodPost, "/entries", token, map[string]any{
+			"key":     email,
+			"keyType": "EMAIL",
+			"account": map[string]any{
+				"participant":   "12345678",
+				"branch":        "0001",
+				"accountNumber": "0007654321",
+				"accountType":   "CACC",
+				"openingDate":   time.Now().UTC().Format(time.RFC3339),
+			},
+			"owner": map[string]any{
+				"type":        "NATURAL_PERSON",
+				"taxIdNumber": validCPF,
+				"name":        "SDK Test",
+			},
+			"reason":    "USER_REQUESTED",
+			"requestId": uuid.New().String(),
+		})
+	}
+
+	for i, want := range []int{http.StatusCreated, http.StatusCreated, http.StatusTooManyRequests} {
+		resp := create("limit" + strconv.Itoa(i) + "@example.com")
+		if resp.StatusCode != want {
+			t.Fatalf("creation %d status = %d, want %d", i+1, resp.StatusCode, want)
+		}
+		if want == http.StatusTooManyRequests && resp.Header.Get("Retry-After") == "" {
+			t.Error("refused creation has no Retry-After")
+		}
+	}
+
+	// The limit is per simulated day
+	do(t, srv, http.MethodPost, "/admin/time/advance", "", map[string]string{"duration": "24h"})
+	if resp := create("limit3@example.com"); resp.StatusCode != http.StatusCreated {
+		t.Errorf("creation on the next day status = %d, want 201", resp.StatusCode)
+	}
+}
+
+func TestSimulatorRequestIDs(t *testing.T) {
+	srv := startSimulator(t)
+	token := register(t, srv)
+	create := func(key, requestID string) (int, string) {
+		resp := do(t, srv, http.MethodPost, "/entries", token, map[string]any{
+			"key":     key,
+			"keyType": "EMAIL",
+			"account": map[string]any{
+				"participant":   "12345678",
+				"branch":        "0001",
+				"accountNumber": "0007654321",
+				"accountType":   "CACC",
+				"openingDate":   time.Now().UTC().Format(time.RFC3339),
+			},
+			"owner": map[string]any{
+				"type":        "NATURAL_PERSON",
+				"taxIdNumber": validCPF,
+				"name":        "SDK Test",
+			},
+			"reason":    "USER_REQUESTED",
+			"requestId": requestID,
+		})
+		// Successes carry a code, errors an error
+		var result struct {
+			Code  string `json:"code"`
+			Error string `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("decode create response: %v", err)
+		}
+		return resp.StatusCode, result.Code + result.Error
+	}
+
+	used, refused := uuid.New().String(), uuid.New().String()
+	if status, code := create("first@example.com", used); status != http.StatusCreated {
+		t.Fatalf("create status = %d %s, want 201", status, code)
+	}
+
+	// A new idempotency key doesn't make a used requestId new
+	if status, code := create("second@example.com", used); status != http.StatusConflict || code != "REQUEST_ID_ALREADY_USED" {
+		t.Errorf("create with a used requestId = %d %s, want 409 REQUEST_ID_ALREADY_USED", status, code)
+	}
+
+	// A refused request leaves its requestId unused
+	if status, code := create("first@example.com", refused); code != "KEY_ALREADY_EXISTS" {
+		t.Errorf("create of a registered key = %d %s, want KEY_ALREADY_EXISTS", status, code)
+	}
+	if status, code := create("second@example.com", refused); status != http.StatusCreated {
+		t.Errorf("create with the requestId of a refused request = %d %s, want 201", status, code)
+	}
+
+	resp := do(t, srv, http.MethodPost, "/admin/reset", "", map[string]string{"participant": "12345678"})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("reset status = %d, want 200", resp.StatusCode)
+	}
+	var reset struct {
+		Data struct {
+			RequestIdsDeleted int64 `json:"requestIdsDeleted"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reset); err != nil {
+		t.Fatalf("decode reset: %v", err)
+	}
+	if reset.Data.RequestIdsDeleted != 2 {
+		t.Errorf("reset deleted %d requestIds, want 2", reset.Data.RequestIdsDeleted)
+	}
+	if status, code := create("first@example.com", used); status != http.StatusCreated {
+		t.Errorf("create with a requestId used before the reset = %d %s, want 201", status, code)
+	}
+}
+
+func TestSimulatorEntryTimestamps(t *testing.T) {
+	srv := startSimulator(t)
+	token := register(t, srv)
+	create := func(openingDate time.Time) *http.Response {
+		return do(t, srv, http.MethodPost, "/entries", token, map[string]any{
+			"key":     validCPF,
+			"keyType": "CPF",
+			"account": map[string]any{
+				"participant":   "12345678",
+				"branch":        "0001",
+				"accountNumber": "0007654321",
+				"accountType":   "CACC",
+				"openingDate":   openingDate.Format(time.RFC3339),
+			},
+			"owner": map[string]any{
+				"type":        "NATURAL_PERSON",
+				"taxIdNumber": validCPF,
+				"name":        "SDK Test",
+			},
+			"reason":    "USER_REQUESTED",
+			"requestId": uuid.New().String(),
+		})
+	}
+	type entry struct {
+		Account struct {
+			OpeningDate string `json:"openingDate"`
+		} `json:"account"`
+		CreatedAt string `json:"createdAt"`
+		UpdatedAt string `json:"updatedAt"`
+		Sequence  int64  `json:"sequence"`
+	}
+	decode := func(resp *http.Response) entry {
+		t.Helper()
+		var result struct {
+			Data entry `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("decode entry: %v", err)
+		}
+		return result.Data
+	}
+
+	resp := create(time.Now().Add(time.Hour))
+	var refused struct {
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&refused); err != nil {
+		t.Fatalf("decode refused create: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest || refused.Error != "INVALID_OPENING_DATE" {
+		t.Fatalf("create opened in the future = %d %s, want 400 INVALID_OPENING_DATE", resp.StatusCode, refused.Error)
+	}
+
+	// Opening dates given with an offset come back in UTC, like the entry's own timestamps
+	resp = create(time.Now().In(time.FixedZone("BRT", -3*60*60)).Add(-24 * time.Hour))
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create status = %d, want 201", resp.StatusCode)
+	}
+	created := decode(resp)
+	for name, value := range map[string]string{"openingDate": created.Account.OpeningDate, "createdAt": created.CreatedAt} {
+		if !strings.HasSuffix(value, "Z") {
+			t.Errorf("created %s = %s, want UTC", name, value)
+		}
+	}
+	if created.Sequence != 1 {
+		t.Errorf("created sequence = %d, want 1", created.Sequence)
+	}
+
+	resp = do(t, srv, http.MethodPut, "/entries/"+validCPF, token, map[string]any{
+		"key":         validCPF,
+		"participant": "12345678",
+		"reason":      "USER_REQUESTED",
+		"owner":       map[string]any{"name": "SDK Test Renamed"},
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("update status = %d, want 200", resp.StatusCode)
+	}
+	if updated := decode(resp); updated.Sequence != 2 || !strings.HasSuffix(updated.UpdatedAt, "Z") {
+		t.Errorf("updated entry = %+v, want sequence 2 and a UTC updatedAt", updated)
+	}
+}
+
+func TestSimulatorAccountRules(t *testing.T) {
+	srv := startSimulator(t, WithAccountRules(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), true))
+	token := register(t, srv)
+	create := func(branch, accountNumber string, openingDate time.Time) (int, string, []string) {
+		resp := do(t, srv, http.MethodPost, "/entries", token, map[string]any{
+			"key":     validCPF,
+			"keyType": "CPF",
+			"account": map[string]any{
+				"participant":   "12345678",
+				"branch":        branch,
+				"accountNumber": accountNumber,
+				"accountType":   "CACC",
+				"openingDate":   openingDate.Format(time.RFC3339),
+			},
+			"owner": map[string]any{
+				"type":        "NATURAL_PERSON",
+				"taxIdNumber": validCPF,
+				"name":        "SDK Test",
+			},
+			"reason":    "USER_REQUESTED",
+			"requestId": uuid.New().String(),
+		})
+		var result struct {
+			Error      string `json:"error"`
+			Violations []struct {
+				Field string `json:"field"`
+			} `json:"violations"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("decode create response: %v", err)
+		}
+		var fields []string
+		for _, v := range result.Violations {
+			fields = append(fields, v.Field)
+		}
+		return resp.StatusCode, result.Error, fields
+	}
+
+	opened := time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		branch        string
+		accountNumber string
+		openingDate   time.Time
+		wantError     string
+		wantFields    string
+	}{
+		{"invalid branch and number", "01", "0007654321", opened, "INVALID_ACCOUNT", "account.branch,account.accountNumber"},
+		{"opened before 2000", "0001", "0007654324", time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC), "INVALID_OPENING_DATE", "account.openingDate"},
+	}
+	for _, tt := range tests {
+		status, code, fields := create(tt.branch, tt.accountNumber, tt.openingDate)
+		if status != http.StatusBadRequest || code != tt.wantError || strings.Join(fields, ",") != tt.wantFields {
+			t.Errorf("create with %s = %d %s %v, want 400 %s on %s", tt.name, status, code, fields, tt.wantError, tt.wantFields)
+		}
+	}
+
+	if status, code, _ := create("0001", "0007654324", opened); status != http.StatusCreated {
+		t.Errorf("create with a valid account = %d %s, want 201", status, code)
+	}
+}
+
+func TestSimulatorAccountTypes(t *testing.T) {
+	srv := startSimulator(t)
+	token := register(t, srv)
+	create := func(key, accountType string) int {
+		resp := do(t, srv, http.MethodPost, "/entries", token, map[string]any{
+			"key":     key,
+			"keyType": "EMAIL",
+			"account": map[string]any{
+				"participant":   "12345678",
+				"branch":        "0001",
+				"accountNumber": "0007654321",
+				"accountType":   accountType,
+				"openingDate":   time.Now().UTC().Format(time.RFC3339),
+			},
+			"owner": map[string]any{
+				"type":        "NATURAL_PERSON",
+				"taxIdNumber": validCPF,
+				"name":        "SDK Test",
+			},
+			"reason":    "USER_REQUESTED",
+			"requestId": uuid.New().String(),
+		})
+		return resp.StatusCode
+	}
+
+	if status := create("paga@example.com", "PAGA"); status != http.StatusCreated {
+		t.Fatalf("create with a PAGA account status = %d, want 201", status)
+	}
+	if status := create("other@example.com", "OTHR"); status != http.StatusBadRequest {
+		t.Errorf("create with an OTHR account status = %d, want 400", status)
+	}
+
+	resp := do(t, srv, http.MethodPut, "/entries/paga@example.com", token, map[string]any{
+		"key":         "paga@example.com",
+		"participant": "12345678",
+		"reason":      "USER_REQUESTED",
+		"account": map[string]any{
+			"participant":   "12345678",
+			"branch":        "0001",
+			"accountNumber": "0007654321",
+			"accountType":   "TRAN",
+		},
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("update to a TRAN account status = %d, want 200", resp.StatusCode)
+	}
+	var result struct {
+		Data struct {
+			Account struct {
+				AccountType string `json:"accountType"`
+			} `json:"account"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode update response: %v", err)
+	}
+	if result.Data.Account.AccountType != "TRAN" {
+		t.Errorf("updated accountType = %s, want TRAN", result.Data.Account.AccountType)
+	}
+}
+
+func TestSimulatorOwnerMasking(t *testing.T) {
+	srv := startSimulator(t)
+	token := register(t, srv)
+	resp := do(t, srv, http.MethodPost, "/entries", token, map[string]any{
+		"key":     "masking@example.com",
+		"keyType": "EMAIL",
+		"account": map[string]any{
+			"participant":   "12345678",
+			"branch":        "0001",
+			"accountNumber": "0007654321",
+			"accountType":   "CACC",
+			"openingDate":   time.Now().UTC().Format(time.RFC3339),
+		},
+		"owner": map[string]any{
+			"type":        "NATURAL_PERSON",
+			"taxIdNumber": validCPF,
+			"name":        "SDK Test",
+		},
+		"reason":    "USER_REQUESTED",
+		"requestId": uuid.New().String(),
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create status = %d, want 201", resp.StatusCode)
+	}
+
+	owner := func(participant, payer string) (string, string) {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/entries/masking@example.com", nil)
+		if err != nil {
+			t.Fatalf("new request: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		// Users only name a participant alongside the admin token
+		req.Header.Set("X-Participant-Id", participant)
+		req.Header.Set(AdminTokenHeader, DefaultAdminToken)
+		if payer != "" {
+			req.Header.Set("PI-PayerId", payer)
+		}
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatalf("GET entry: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET entry as %s status = %d, want 200", participant, resp.StatusCode)
+		}
+		var result struct {
+			Data struct {
+				Owner struct {
+					TaxIdNumber string `json:"taxIdNumber"`
+					Name        string `json:"name"`
+				} `json:"owner"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("decode entry: %v", err)
+		}
+		return result.Data.Owner.TaxIdNumber, result.Data.Owner.Name
+	}
+
+	if taxID, name := owner("12345678", ""); taxID != validCPF || name != "SDK Test" {
+		t.Errorf("owning participant sees %s %q, want the owner in full", taxID, name)
+	}
+	if taxID, name := owner("87654321", ""); taxID != "***"+validCPF[3:9]+"**" || name != "S. T." {
+		t.Errorf("other participant sees %s %q, want the owner masked", taxID, name)
+	}
+	if taxID, name := owner("87654321", "11144477735"); taxID != validCPF || name != "SDK Test" {
+		t.Errorf("other participant with a payer sees %s %q, want the owner in full", taxID, name)
+	}
+}
+
+func TestSimulatorOwnershipHistory(t *testing.T) {
+	srv := startSimulator(t)
+	token := register(t, srv)
+	create := func(taxID string) {
+		resp := do(t, srv, http.MethodPost, "/entries", token, map[string]any{
+			"key":     "history@example.com",
+			"keyType": "EMAIL",
+			"account": map[string]any{
+				"participant":   "12345678",
+				"branch":        "0001",
+				"accountNumber": "0007654321",
+				"accountType":   "CACC",
+				"openingDate":   time.Now().UTC().Format(time.RFC3339),
+			},
+			"owner": map[string]any{
+				"type":        "NATURAL_PERSON",
+				"taxIdNumber": taxID,
+				"name":        "SDK Test",
+			},
+			"reason":    "USER_REQUESTED",
+			"requestId": uuid.New().String(),
+		})
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("create for %s status = %d, want 201", taxID, resp.StatusCode)
+		}
+	}
+
+	// The key is released by its first owner and registered by another
+	create(validCPF)
+	resp := do(t, srv, http.MethodPost, "/entries/history@example.com/delete", token, map[string]any{
+		"key":         "history@example.com",
+		"participant": "12345678",
+		"reason":      "USER_REQUESTED",
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("delete status = %d, want 200", resp.StatusCode)
+	}
+	create("11144477735")
+
+	resp = do(t, srv, http.MethodGet, "/entries/history@example.com/ownership-history", token, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("ownership history status = %d, want 200", resp.StatusCode)
+	}
+	var result struct {
+		Code string `json:"code"`
+		Data []struct {
+			Participant string `json:"participant"`
+			Owner       struct {
+				TaxIdNumber string `json:"taxIdNumber"`
+			} `json:"owner"`
+			From time.Time  `json:"from"`
+			To   *time.Time `json:"to"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode ownership history: %v", err)
+	}
+	if result.Code != "OWNERSHIP_HISTORY_FOUND" || len(result.Data) != 2 {
+		t.Fatalf("ownership history = %s with %+v, want OWNERSHIP_HISTORY_FOUND with 2 periods", result.Code, result.Data)
+	}
+	// A user naming no participant sees owners masked, as on GET /entries/{key}
+	if current := result.Data[0]; current.Owner.TaxIdNumber != "***444777**" || current.To != nil {
+		t.Errorf("newest period = %+v, want the current one of ***444777**", current)
+	}
+	if previous := result.Data[1]; previous.Owner.TaxIdNumber != "***"+validCPF[3:9]+"**" || previous.To == nil || previous.To.Before(previous.From) {
+		t.Errorf("previous period = %+v, want a closed one of the first owner", previous)
+	}
+}
+
+func TestSimulatorErasure(t *testing.T) {
+	srv := startSimulator(t)
+	token := register(t, srv)
+	create := func(key, keyType, taxID string) {
+		resp := do(t, srv, http.MethodPost, "/entries", token, map[string]any{
+			"key":     key,
+			"keyType": keyType,
+			"account": map[string]any{
+				"participant":   "12345678",
+				"branch":        "0001",
+				"accountNumber": "0007654321",
+				"accountType":   "CACC",
+				"openingDate":   time.Now().UTC().Format(time.RFC3339),
+			},
+			"owner": map[string]any{
+				"type":        "NATURAL_PERSON",
+				"taxIdNumber": taxID,
+				"name":        "SDK Test",
+			},
+			"reason":    "USER_REQUESTED",
+			"requestId": uuid.New().String(),
+		})
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("create %s status = %d, want 201", key, resp.StatusCode)
+		}
+	}
+	create(validCPF, "CPF", validCPF)
+	create("erased@example.com", "EMAIL", validCPF)
+	create("kept@example.com", "EMAIL", "11144477735")
+
+	// Erased entries are reported like deleted ones, here by the inline event source
+	deleted := make(chan string, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event struct {
+			Data struct {
+				Key string `json:"key"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&event); err == nil {
+			deleted <- event.Data.Key
+		}
+	}))
+	t.Cleanup(receiver.Close)
+	resp := do(t, srv, http.MethodPost, "/webhooks", token, map[string]any{
+		"participant": "12345678",
+		"url":         receiver.URL,
+		"events":      []string{"ENTRY_DELETED"},
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("register webhook status = %d, want 201", resp.StatusCode)
+	}
+
+	if resp := do(t, srv, http.MethodPost, "/admin/erasure/12345678900", "", nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("erasure of an invalid CPF status = %d, want 400", resp.StatusCode)
+	}
+
+	resp = do(t, srv, http.MethodPost, "/admin/erasure/"+validCPF, "", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("erasure status = %d, want 200", resp.StatusCode)
+	}
+	var result struct {
+		Code string `json:"code"`
+		Data struct {
+			Entries []struct {
+				Key string `json:"key"`
+			} `json:"entries"`
+			OwnershipPeriodsDeleted int64 `json:"ownershipPeriodsDeleted"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode erasure: %v", err)
+	}
+	if result.Code != "SUBJECT_ERASED" || len(result.Data.Entries) != 2 {
+		t.Errorf("erasure = %s with %+v, want SUBJECT_ERASED with 2 entries", result.Code, result.Data.Entries)
+	}
+	if result.Data.OwnershipPeriodsDeleted != 2 {
+		t.Errorf("erasure deleted %d ownership periods, want 2", result.Data.OwnershipPeriodsDeleted)
+	}
+
+	for key, want := range map[string]int{
+		validCPF:             http.StatusNotFound,
+		"erased@example.com": http.StatusNotFound,
+		"kept@example.com":   http.StatusOK,
+	} {
+		if resp := do(t, srv, http.MethodGet, "/entries/"+key, token, nil); resp.StatusCode != want {
+			t.Errorf("get %s after erasure status = %d, want %d", key, resp.StatusCode, want)
+		}
+	}
+
+	reported := map[string]bool{}
+	for len(reported) < 2 {
+		select {
+		case key := <-deleted:
+			reported[key] = true
+		case <-time.After(5 * time.Second):
+			t.Fatalf("ENTRY_DELETED delivered for %v, want both erased keys", reported)
+		}
+	}
+	if !reported[validCPF] || !reported["erased@example.com"] {
+		t.Errorf("ENTRY_DELETED delivered for %v, want the erased keys", reported)
+	}
+}
+
+func TestSimulatorPasswordChanges(t *testing.T) {
+	srv := startSimulator(t)
+	token := register(t, srv)
+	login := func(password string) int {
+		return do(t, srv, http.MethodPost, "/auth/login", "", map[string]string{"email": "sdk@example.com", "password": password}).StatusCode
+	}
+
+	change := map[string]string{"currentPassword": "wrong-password", "newPassword": "changed123"}
+	if resp := do(t, srv, http.MethodPost, "/auth/change-password", token, change); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("change with a wrong current password status = %d, want 401", resp.StatusCode)
+	}
+	change["currentPassword"] = "testpassword123"
+	if resp := do(t, srv, http.MethodPost, "/auth/change-password", token, change); resp.StatusCode != http.StatusOK {
+		t.Fatalf("change password status = %d, want 200", resp.StatusCode)
+	}
+	if status := login("changed123"); status != http.StatusOK {
+		t.Fatalf("login with the changed password status = %d, want 200", status)
+	}
+
+	// Unknown emails get the same answer, so the route can't be used to find accounts
+	for _, email := range []string{"sdk@example.com", "nobody@example.com"} {
+		if resp := do(t, srv, http.MethodPost, "/auth/password-reset", "", map[string]string{"email": email}); resp.StatusCode != http.StatusAccepted {
+			t.Fatalf("reset request for %s status = %d, want 202", email, resp.StatusCode)
+		}
+	}
+
+	var user struct {
+		Data struct {
+			User struct {
+				ID string `json:"id"`
+			} `json:"user"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(do(t, srv, http.MethodPost, "/auth/login", "", map[string]string{"email": "sdk@example.com", "password": "changed123"}).Body).Decode(&user); err != nil {
+		t.Fatalf("decode login response: %v", err)
+	}
+	var pending struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	resp := do(t, srv, http.MethodGet, "/admin/users/"+user.Data.User.ID+"/password-reset", "", nil)
+	if err := json.NewDecoder(resp.Body).Decode(&pending); err != nil || pending.Data.Token == "" {
+		t.Fatalf("pending reset status = %d, token %q (%v), want a token", resp.StatusCode, pending.Data.Token, err)
+	}
+
+	confirm := map[string]string{"token": pending.Data.Token, "newPassword": "reset123"}
+	if resp := do(t, srv, http.MethodPost, "/auth/password-reset/confirm", "", confirm); resp.StatusCode != http.StatusOK {
+		t.Fatalf("confirm reset status = %d, want 200", resp.StatusCode)
+	}
+	if resp := do(t, srv, http.MethodPost, "/auth/password-reset/confirm", "", confirm); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("second use of a reset token status = %d, want 400", resp.StatusCode)
+	}
+	if status := login("reset123"); status != http.StatusOK {
+		t.Errorf("login with the reset password status = %d, want 200", status)
+	}
+	if resp := do(t, srv, http.MethodGet, "/admin/users/"+user.Data.User.ID+"/password-reset", "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("pending reset after redemption status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestSimulatorUserManagement(t *testing.T) {
+	srv := startSimulator(t)
+	token := register(t, srv)
+
+	var me struct {
+		Data struct {
+			ID       string `json:"id"`
+			Email    string `json:"email"`
+			Disabled bool   `json:"disabled"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(do(t, srv, http.MethodPut, "/auth/me", token, map[string]string{"email": "renamed@example.com"}).Body).Decode(&me); err != nil {
+		t.Fatalf("decode profile update: %v", err)
+	}
+	if me.Data.Email != "renamed@example.com" {
+		t.Fatalf("updated email = %q, want renamed@example.com", me.Data.Email)
+	}
+	if resp := do(t, srv, http.MethodPut, "/auth/me", token, map[string]string{}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("empty profile update status = %d, want 400", resp.StatusCode)
+	}
+
+	var page struct {
+		Data struct {
+			Users []struct {
+				ID string `json:"id"`
+			} `json:"users"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(do(t, srv, http.MethodGet, "/admin/users", "", nil).Body).Decode(&page); err != nil {
+		t.Fatalf("decode user list: %v", err)
+	}
+	if len(page.Data.Users) != 1 || page.Data.Users[0].ID != me.Data.ID {
+		t.Fatalf("listed users = %+v, want only %s", page.Data.Users, me.Data.ID)
+	}
+
+	// Tokens issued before the user was disabled are refused too
+	if resp := do(t, srv, http.MethodPost, "/admin/users/"+me.Data.ID+"/disable", "", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("disable status = %d, want 200", resp.StatusCode)
+	}
+	if resp := do(t, srv, http.MethodGet, "/auth/me", token, nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("GET /auth/me of a disabled user status = %d, want 401", resp.StatusCode)
+	}
+	login := map[string]string{"email": "renamed@example.com", "password": "testpassword123"}
+	if resp := do(t, srv, http.MethodPost, "/auth/login", "", login); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("login of a disabled user status = %d, want 401", resp.StatusCode)
+	}
+
+	if resp := do(t, srv, http.MethodPost, "/admin/users/"+me.Data.ID+"/enable", "", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("enable status = %d, want 200", resp.StatusCode)
+	}
+	if resp := do(t, srv, http.MethodGet, "/auth/me", token, nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /auth/me after enabling status = %d, want 200", resp.StatusCode)
+	}
+
+	if resp := do(t, srv, http.MethodDelete, "/admin/users/"+me.Data.ID, "", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("delete status = %d, want 200", resp.StatusCode)
+	}
+	if resp := do(t, srv, http.MethodGet, "/auth/me", token, nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("GET /auth/me of a deleted user status = %d, want 401", resp.StatusCode)
+	}
+	if resp := do(t, srv, http.MethodDelete, "/admin/users/"+me.Data.ID, "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("second delete status = %d, want 404", resp.StatusCode)
+	}
+
+	// The audit lists those actions oldest first on request, cut down to the fields asked for
+	var audit struct {
+		Data struct {
+			Records    []map[string]any `json:"records"`
+			NextCursor string           `json:"nextCursor"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(do(t, srv, http.MethodGet, "/admin/audit?sort=createdAt&limit=2&fields=action", "", nil).Body).Decode(&audit); err != nil {
+		t.Fatalf("decode audit list: %v", err)
+	}
+	want := []map[string]any{{"action": "admin.users.disable"}, {"action": "admin.users.enable"}}
+	if fmt.Sprint(audit.Data.Records) != fmt.Sprint(want) || audit.Data.NextCursor == "" {
+		t.Fatalf("audit page = %+v, want %v and a next cursor", audit.Data, want)
+	}
+	if resp := do(t, srv, http.MethodGet, "/admin/audit?sort=-createdAt&cursor="+audit.Data.NextCursor, "", nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("cursor with another sort status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestSimulatorOAuthClientCredentials(t *testing.T) {
+	srv := startSimulator(t)
+
+	var client struct {
+		Data struct {
+			ClientID     string `json:"clientId"`
+			ClientSecret string `json:"clientSecret"`
+		} `json:"data"`
+	}
+	resp := do(t, srv, http.MethodPost, "/admin/oauth/clients", "", map[string]any{
+		"participant": "12345678",
+		"name":        "reconciliation job",
+		"scopes":      []string{"entries:read", "reconciliation"},
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("register client status = %d, want 201", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&client); err != nil {
+		t.Fatalf("decode client: %v", err)
+	}
+
+	requestToken := func(form url.Values, basic bool) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, srv.URL+"/oauth/token", strings.NewReader(form.Encode()))
+		if err != nil {
+			t.Fatalf("new request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if basic {
+			req.SetBasicAuth(client.Data.ClientID, client.Data.ClientSecret)
+		}
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatalf("POST /oauth/token: %v", err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	resp = requestToken(url.Values{"grant_type": {"client_credentials"}, "scope": {"entries:read"}}, true)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("token status = %d, want 200", resp.StatusCode)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		Scope       string `json:"scope"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		t.Fatalf("decode token: %v", err)
+	}
+	if token.TokenType != "Bearer" || token.Scope != "entries:read" {
+		t.Fatalf("token = %+v, want a Bearer token for entries:read", token)
+	}
+
+	if resp := do(t, srv, http.MethodGet, "/entries/"+validCPF, token.AccessToken, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /entries/{key} with entries:read status = %d, want 404", resp.StatusCode)
+	}
+	// Granted to the client but not requested for this token
+	if resp := do(t, srv, http.MethodGet, "/files?participant=12345678", token.AccessToken, nil); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("GET /files without reconciliation status = %d, want 403", resp.StatusCode)
+	}
+
+	// Credentials in the body work too, and scopes the client lacks are refused
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {client.Data.ClientID},
+		"client_secret": {client.Data.ClientSecret},
+		"scope":         {"entries:write"},
+	}
+	var oauthErr struct {
+		Error string `json:"error"`
+	}
+	resp = requestToken(form, false)
+	if err := json.NewDecoder(resp.Body).Decode(&oauthErr); err != nil {
+		t.Fatalf("decode token error: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest || oauthErr.Error != "invalid_scope" {
+		t.Errorf("token for an ungranted scope = %d %q, want 400 invalid_scope", resp.StatusCode, oauthErr.Error)
+	}
+
+	form.Set("client_secret", "wrong")
+	form.Del("scope")
+	resp = requestToken(form, false)
+	if err := json.NewDecoder(resp.Body).Decode(&oauthErr); err != nil {
+		t.Fatalf("decode token error: %v", err)
+	}
+	if resp.StatusCode != http.StatusUnauthorized || oauthErr.Error != "invalid_client" {
+		t.Errorf("token with a wrong secret = %d %q, want 401 invalid_client", resp.StatusCode, oauthErr.Error)
+	}
+
+	// Tokens of deleted clients are refused
+	if resp := do(t, srv, http.MethodDelete, "/admin/oauth/clients/"+client.Data.ClientID, "", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("delete client status = %d, want 200", resp.StatusCode)
+	}
+	if resp := do(t, srv, http.MethodGet, "/entries/"+validCPF, token.AccessToken, nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("GET /entries/{key} after deleting the client status = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestSimulatorScopes(t *testing.T) {
+	srv := startSimulator(t)
+	register(t, srv)
+
+	login := func(scope string) *http.Response {
+		t.Helper()
+		return do(t, srv, http.MethodPost, "/auth/login", "", map[string]string{
+			"email":    "sdk@example.com",
+			"password": "testpassword123",
+			"scope":    scope,
+		})
+	}
+
+	resp := login("entries:read")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("login status = %d, want 200", resp.StatusCode)
+	}
+	var result struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode login response: %v", err)
+	}
+	monitoring := result.Data.Token
+
+	if resp := do(t, srv, http.MethodGet, "/entries/"+validCPF, monitoring, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /entries/{key} with entries:read status = %d, want 404", resp.StatusCode)
+	}
+	// Refused before the body is read
+	resp = do(t, srv, http.MethodPost, "/entries", monitoring, map[string]any{"key": validCPF})
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("POST /entries with entries:read status = %d, want 403", resp.StatusCode)
+	}
+
+	// Users never hold the admin scope
+	if resp := login("entries:read admin"); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("login asking for admin status = %d, want 400", resp.StatusCode)
+	}
+
+	var client struct {
+		Data struct {
+			ClientID     string `json:"clientId"`
+			ClientSecret string `json:"clientSecret"`
+		} `json:"data"`
+	}
+	resp = do(t, srv, http.MethodPost, "/admin/oauth/clients", "", map[string]any{
+		"participant": "12345678",
+		"name":        "ops automation",
+		"scopes":      []string{"admin"},
+	})
+	if err := json.NewDecoder(resp.Body).Decode(&client); err != nil {
+		t.Fatalf("decode client: %v", err)
+	}
+	form := url.Values{"grant_type": {"client_credentials"}, "client_id": {client.Data.ClientID}, "client_secret": {client.Data.ClientSecret}}
+	resp, err := srv.Client().PostForm(srv.URL+"/oauth/token", form)
+	if err != nil {
+		t.Fatalf("POST /oauth/token: %v", err)
+	}
+	defer resp.Body.Close()
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		t.Fatalf("decode token: %v", err)
+	}
+
+	// The admin routes take a bearer token with the admin scope in place of X-Admin-Token
+	for _, tt := range []struct {
+		token string
+		want  int
+	}{
+		{token.AccessToken, http.StatusOK},
+		{monitoring, http.StatusForbidden},
+	} {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/admin/time", nil)
+		req.Header.Set("Authorization", "Bearer "+tt.token)
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatalf("GET /admin/time: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.want {
+			t.Errorf("GET /admin/time with a bearer token status = %d, want %d", resp.StatusCode, tt.want)
+		}
+	}
+}
+
+// clientToken registers an OAuth client for participant and returns a token holding all its scopes
+func clientToken(t *testing.T, srv *httptest.Server, participant string, scopes ...string) string {
+	t.Helper()
+
+	var client struct {
+		Data struct {
+			ClientID     string `json:"clientId"`
+			ClientSecret string `json:"clientSecret"`
+		} `json:"data"`
+	}
+	resp := do(t, srv, http.MethodPost, "/admin/oauth/clients", "", map[string]any{
+		"participant": participant,
+		"name":        "client of " + participant,
+		"scopes":      scopes,
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("register client status = %d, want 201", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&client); err != nil {
+		t.Fatalf("decode client: %v", err)
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}, "client_id": {client.Data.ClientID}, "client_secret": {client.Data.ClientSecret}}
+	resp, err := srv.Client().PostForm(srv.URL+"/oauth/token", form)
+	if err != nil {
+		t.Fatalf("POST /oauth/token: %v", err)
+	}
+	defer resp.Body.Close()
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		t.Fatalf("decode token: %v", err)
+	}
+	return token.AccessToken
+}
+
+func TestSimulatorParticipantOwnership(t *testing.T) {
+	srv := startSimulator(t)
+	user := register(t, srv)
+	scopes := []string{"entries:read", "entries:write", "reconciliation", "webhooks", "settlements"}
+	tokenA := clientToken(t, srv, "12345678", scopes...)
+	tokenB := clientToken(t, srv, "87654321", scopes...)
+
+	account := func(participant string) map[string]any {
+		return map[string]any{
+			"participant":   participant,
+			"branch":        "0001",
+			"accountNumber": "0007654321",
+			"accountType":   "CACC",
+			"openingDate":   time.Now().UTC().Format(time.RFC3339),
+		}
+	}
+	entry := func(key, keyType, participant string) map[string]any {
+		return map[string]any{
+			"key":       key,
+			"keyType":   keyType,
+			"account":   account(participant),
+			"owner":     map[string]any{"type": "NATURAL_PERSON", "taxIdNumber": validCPF, "name": "SDK Test"},
+			"reason":    "USER_REQUESTED",
+			"requestId": uuid.New().String(),
+		}
+	}
+
+	// Participant B's key, registered by its own client
+	if resp := do(t, srv, http.MethodPost, "/entries", tokenB, entry(validCPF, "CPF", "87654321")); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create as B status = %d, want 201", resp.StatusCode)
+	}
+	resp := do(t, srv, http.MethodPost, "/webhooks", tokenB, map[string]any{
+		"participant": "87654321",
+		"url":         "https://psp.example.com/dict/callbacks",
+		"events":      []string{"ENTRY_CREATED"},
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("register webhook as B status = %d, want 201", resp.StatusCode)
+	}
+	var webhook struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&webhook); err != nil {
+		t.Fatalf("decode webhook: %v", err)
+	}
+
+	// Participant A's client acting on B in the body, path or query
+	for _, tt := range []struct {
+		method, path string
+		body         any
+	}{
+		{http.MethodPost, "/entries", entry("sdk@example.com", "EMAIL", "87654321")},
+		{http.MethodPut, "/entries/" + validCPF, map[string]any{"key": validCPF, "participant": "87654321", "reason": "USER_REQUESTED", "owner": map[string]any{"name": "Taken Over"}}},
+		{http.MethodPost, "/entries/" + validCPF + "/delete", map[string]any{"key": validCPF, "participant": "87654321", "reason": "USER_REQUESTED"}},
+		{http.MethodPost, "/accounts/87654321/0001/0007654321/close", map[string]any{}},
+		{http.MethodGet, "/participants/87654321/entries", nil},
+		{http.MethodGet, "/files?participant=87654321", nil},
+		{http.MethodGet, "/webhooks?participant=87654321", nil},
+		{http.MethodPost, "/webhooks", map[string]any{"participant": "87654321", "url": "https://attacker.example.com", "events": []string{"ENTRY_CREATED"}}},
+		{http.MethodGet, "/webhooks/" + webhook.Data.ID + "/deliveries", nil},
+		{http.MethodDelete, "/webhooks/" + webhook.Data.ID, nil},
+	} {
+		if resp := do(t, srv, tt.method, tt.path, tokenA, tt.body); resp.StatusCode != http.StatusForbidden {
+			t.Errorf("%s %s as A for B status = %d, want 403", tt.method, tt.path, resp.StatusCode)
+		}
+	}
+
+	// Moving its own key to B's account is refused too
+	if resp := do(t, srv, http.MethodPost, "/entries", tokenA, entry("sdk@example.com", "EMAIL", "12345678")); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create as A status = %d, want 201", resp.StatusCode)
+	}
+	move := map[string]any{"key": "sdk@example.com", "participant": "12345678", "reason": "USER_REQUESTED", "account": map[string]any{"participant": "87654321"}}
+	if resp := do(t, srv, http.MethodPut, "/entries/sdk@example.com", tokenA, move); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("moving A's key to B status = %d, want 403", resp.StatusCode)
+	}
+
+	// B's entry and webhook are untouched, and each participant still acts for itself
+	listOwners := func(token string) []string {
+		t.Helper()
+		resp := do(t, srv, http.MethodGet, "/participants/87654321/entries", token, nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("list B's entries status = %d, want 200", resp.StatusCode)
+		}
+		var page struct {
+			Data struct {
+				Entries []struct {
+					Key   string `json:"key"`
+					Owner struct {
+						TaxIdNumber string `json:"taxIdNumber"`
+					} `json:"owner"`
+				} `json:"entries"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+			t.Fatalf("decode page: %v", err)
+		}
+		var taxIDs []string
+		for _, e := range page.Data.Entries {
+			taxIDs = append(taxIDs, e.Owner.TaxIdNumber)
+		}
+		return taxIDs
+	}
+	if got := listOwners(tokenB); len(got) != 1 || got[0] != validCPF {
+		t.Errorf("B lists owners %v, want its entry in full", got)
+	}
+	// A user speaking for no participant sees the owners masked
+	if got := listOwners(user); len(got) != 1 || got[0] != "***"+validCPF[3:9]+"**" {
+		t.Errorf("user lists owners %v, want its entry masked", got)
+	}
+	if resp := do(t, srv, http.MethodGet, "/webhooks?participant=87654321", tokenB, nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("list webhooks as B status = %d, want 200", resp.StatusCode)
+	}
+	if resp := do(t, srv, http.MethodDelete, "/webhooks/"+webhook.Data.ID, tokenB, nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("delete webhook as B status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestSimulatorJWKS(t *testing.T) {
+	srv := startSimulator(t, WithJWTAlgorithm("ES256"))
+	token := register(t, srv)
+
+	if resp := do(t, srv, http.MethodGet, "/auth/me", token, nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /auth/me status = %d, want 200", resp.StatusCode)
+	}
+
+	resp := do(t, srv, http.MethodGet, "/.well-known/jwks.json", "", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /.well-known/jwks.json status = %d, want 200", resp.StatusCode)
+	}
+	var jwks struct {
+		Keys []struct {
+			KeyType string `json:"kty"`
+			KeyID   string `json:"kid"`
+			Alg     string `json:"alg"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		t.Fatalf("decode JWKS: %v", err)
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		t.Fatalf("parse token: %v", err)
+	}
+	if len(jwks.Keys) != 1 || jwks.Keys[0].KeyID != parsed.Header["kid"] || jwks.Keys[0].Alg != "ES256" || jwks.Keys[0].KeyType != "EC" {
+		t.Errorf("JWKS = %+v, want the EC key the token names (kid %v)", jwks.Keys, parsed.Header["kid"])
+	}
+
+	// The shared secret no longer signs anything the simulator accepts
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, parsed.Claims).SignedString([]byte(DefaultJWTSecret))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if resp := do(t, srv, http.MethodGet, "/auth/me", forged, nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("GET /auth/me with an HS256 token status = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestSimulatorAPIVersions(t *testing.T) {
+	srv := startSimulator(t)
+	token := register(t, srv)
+
+	resp := do(t, srv, http.MethodGet, "/api/v1/auth/me", token, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /api/v1/auth/me status = %d, want 200", resp.StatusCode)
+	}
+	if d := resp.Header.Get("Deprecation"); d != "" {
+		t.Errorf("v1 route Deprecation = %q, want none", d)
+	}
+
+	resp = do(t, srv, http.MethodGet, "/auth/me", token, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /auth/me status = %d, want 200", resp.StatusCode)
+	}
+	if d := resp.Header.Get("Deprecation"); !strings.HasPrefix(d, "@") {
+		t.Errorf("unprefixed route Deprecation = %q, want a date", d)
+	}
+	if link := resp.Header.Get("Link"); link != `</api/v1/auth/me>; rel="successor-version"` {
+		t.Errorf("unprefixed route Link = %q, want its /api/v1 path", link)
+	}
+
+	if resp := do(t, srv, http.MethodGet, "/health", "", nil); resp.Header.Get("Deprecation") != "" {
+		t.Error("GET /health is deprecated, want infrastructure routes left unversioned")
+	}
+	if resp := do(t, srv, http.MethodGet, "/api/v9/auth/me", token, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /api/v9/auth/me status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestSimulatorUnmatchedRoutes(t *testing.T) {
+	srv := startSimulator(t)
+
+	// Wrong methods and unknown paths answer in the API's error format, like every other error
+	var problem struct {
+		Error     string `json:"error"`
+		RequestID string `json:"requestId"`
+	}
+	resp := do(t, srv, http.MethodDelete, "/api/v1/auth/me", "", nil)
+	if resp.StatusCode != http.StatusMethodNotAllowed || resp.Header.Get("Allow") != "GET, HEAD, PUT" {
+		t.Errorf("DELETE /api/v1/auth/me = %d with Allow %q, want 405 with GET, HEAD, PUT", resp.StatusCode, resp.Header.Get("Allow"))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&problem); err != nil || problem.Error != "METHOD_NOT_ALLOWED" {
+		t.Errorf("405 body error = %q (%v), want METHOD_NOT_ALLOWED", problem.Error, err)
+	}
+	// Errors carry the server's request ID next to the correlation ID
+	if id := resp.Header.Get("X-Request-Id"); id == "" || problem.RequestID != id {
+		t.Errorf("requestId = %q, X-Request-Id = %q; want the same server-generated ID", problem.RequestID, id)
+	}
+
+	resp = do(t, srv, http.MethodGet, "/claims", "", nil)
+	if resp.StatusCode != http.StatusNotFound || resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("GET /claims = %d %q, want a JSON 404", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&problem); err != nil || problem.Error != "NOT_FOUND" {
+		t.Errorf("404 body error = %q (%v), want NOT_FOUND", problem.Error, err)
+	}
+}
+
+func TestSimulatorTimeTravel(t *testing.T) {
+	srv := startSimulator(t)
+
+	if resp := do(t, srv, http.MethodPost, "/admin/time/advance", "", map[string]string{"duration": "24h"}); resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST /admin/time/advance status = %d, want 200", resp.StatusCode)
+	}
+
+	// Response metadata follows the simulated clock too
+	var result struct {
+		ResponseTime time.Time `json:"responseTime"`
+	}
+	if err := json.NewDecoder(do(t, srv, http.MethodGet, "/admin/faults", "", nil).Body).Decode(&result); err != nil {
+		t.Fatalf("decode faults response: %v", err)
+	}
+	if result.ResponseTime.Before(time.Now().Add(23 * time.Hour)) {
+		t.Errorf("responseTime = %v, want the simulated time", result.ResponseTime)
+	}
+
+	hidden := startSimulator(t, WithTimeTravel(false))
+	if resp := do(t, hidden, http.MethodGet, "/admin/time", "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /admin/time status = %d, want 404 with time travel disabled", resp.StatusCode)
+	}
+}
+
+func TestSimulatorBrownout(t *testing.T) {
+	srv := startSimulator(t)
+	token := register(t, srv)
+	lookup := func() int {
+		return do(t, srv, http.MethodGet, "/entries/"+validCPF, token, nil).StatusCode
+	}
+	brownout := func() (phase string, intensity float64) {
+		var result struct {
+			Data struct {
+				Phase     string  `json:"phase"`
+				Intensity float64 `json:"intensity"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(do(t, srv, http.MethodGet, "/admin/brownout", "", nil).Body).Decode(&result); err != nil {
+			t.Fatalf("decode brownout response: %v", err)
+		}
+		return result.Data.Phase, result.Data.Intensity
+	}
+
+	if resp := do(t, srv, http.MethodPut, "/admin/brownout", "", map[string]any{"errorRate": 0.5, "rampUp": "-1m"}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("PUT /admin/brownout with a negative ramp status = %d, want 400", resp.StatusCode)
+	}
+	if resp := do(t, srv, http.MethodPut, "/admin/brownout", "", map[string]any{"rampUp": "1m"}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("PUT /admin/brownout without rates status = %d, want 400", resp.StatusCode)
+	}
+
+	// Every request fails at the peak, which is an hour of ramp away
+	if resp := do(t, srv, http.MethodPut, "/admin/brownout", "", map[string]any{"errorRate": 1, "rampUp": "1h"}); resp.StatusCode != http.StatusOK {
+		t.Fatalf("PUT /admin/brownout status = %d, want 200", resp.StatusCode)
+	}
+	if phase, intensity := brownout(); phase != "RAMPING_UP" || intensity > 0.01 {
+		t.Errorf("brownout = %s at %v, want just RAMPING_UP", phase, intensity)
+	}
+	if status := lookup(); status != http.StatusNotFound {
+		t.Errorf("GET /entries/{key} status = %d at the start of the ramp, want 404", status)
+	}
+
+	do(t, srv, http.MethodPost, "/admin/time/advance", "", map[string]string{"duration": "1h"})
+	if phase, intensity := brownout(); phase != "HOLDING" || intensity != 1 {
+		t.Errorf("brownout = %s at %v, want HOLDING at 1", phase, intensity)
+	}
+	if status := lookup(); status != http.StatusServiceUnavailable {
+		t.Errorf("GET /entries/{key} status = %d at the peak, want 503", status)
+	}
+
+	if resp := do(t, srv, http.MethodDelete, "/admin/brownout", "", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("DELETE /admin/brownout status = %d, want 200", resp.StatusCode)
+	}
+	if status := lookup(); status != http.StatusNotFound {
+		t.Errorf("GET /entries/{key} status = %d after the brownout, want 404", status)
+	}
+	if resp := do(t, srv, http.MethodGet, "/admin/brownout", "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /admin/brownout status = %d after stopping it, want 404", resp.StatusCode)
+	}
+}
+
+func TestSimulatorStubs(t *testing.T) {
+	srv := startSimulator(t)
+	token := register(t, srv)
+	stubbedKey := "11144477735"
+
+	if resp := do(t, srv, http.MethodPost, "/admin/stubs", "", map[string]any{"statusCode": 99}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("POST /admin/stubs with status 99 = %d, want 400", resp.StatusCode)
+	}
+
+	resp := do(t, srv, http.MethodPost, "/admin/stubs", "", map[string]any{
+		"route":           "GET /entries/{key}",
+		"key":             stubbedKey,
+		"statusCode":      http.StatusConflict,
+		"body":            map[string]string{"error": "ENTRY_LOCKED_BY_CLAIM"},
+		"responseHeaders": map[string]string{"Retry-After": "30"},
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("POST /admin/stubs status = %d, want 201", resp.StatusCode)
+	}
+	var created struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("decode stub: %v", err)
+	}
+
+	// The stub answers without a token, with its body as is; OpenAPI validation lets it through
+	resp = do(t, srv, http.MethodGet, "/entries/"+stubbedKey, "", nil)
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusConflict || string(body) != `{"error":"ENTRY_LOCKED_BY_CLAIM"}` {
+		t.Errorf("stubbed GET = %d %s, want the stub's 409 and body", resp.StatusCode, body)
+	}
+	if resp.Header.Get("X-Stub-Id") != created.Data.ID || resp.Header.Get("Retry-After") != "30" {
+		t.Errorf("stubbed GET headers = %v, want X-Stub-Id %s and Retry-After", resp.Header, created.Data.ID)
+	}
+	if resp := do(t, srv, http.MethodGet, "/entries/"+validCPF, token, nil); resp.StatusCode != http.StatusNotFound || resp.Header.Get("X-Stub-Id") != "" {
+		t.Errorf("GET of another key = %d, want 404 from the handler", resp.StatusCode)
+	}
+
+	// A stub matching a request header only answers requests carrying it
+	do(t, srv, http.MethodPost, "/admin/stubs", "", map[string]any{
+		"route":      "GET /entries/{key}",
+		"headers":    map[string]string{"X-Scenario": "timeout"},
+		"statusCode": http.StatusGatewayTimeout,
+	})
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/entries/"+validCPF, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("X-Scenario", "timeout")
+	scenario, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("GET with X-Scenario: %v", err)
+	}
+	scenario.Body.Close()
+	if scenario.StatusCode != http.StatusGatewayTimeout {
+		t.Errorf("GET with X-Scenario = %d, want the stub's 504", scenario.StatusCode)
+	}
+
+	if resp := do(t, srv, http.MethodDelete, "/admin/stubs/"+created.Data.ID, "", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("DELETE /admin/stubs/{id} status = %d, want 200", resp.StatusCode)
+	}
+	if resp := do(t, srv, http.MethodDelete, "/admin/stubs/"+created.Data.ID, "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("DELETE /admin/stubs/{id} again status = %d, want 404", resp.StatusCode)
+	}
+	if resp := do(t, srv, http.MethodGet, "/entries/"+stubbedKey, token, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET after removing the stub = %d, want 404 from the handler", resp.StatusCode)
+	}
+
+	do(t, srv, http.MethodDelete, "/admin/stubs", "", nil)
+	var listed struct {
+		Data []any `json:"data"`
+	}
+	if err := json.NewDecoder(do(t, srv, http.MethodGet, "/admin/stubs", "", nil).Body).Decode(&listed); err != nil || len(listed.Data) != 0 {
+		t.Errorf("GET /admin/stubs after clearing = %v (%v), want none", listed.Data, err)
+	}
+}
+
+func TestSimulatorLogLevel(t *testing.T) {
+	srv := startSimulator(t)
+	// The level is the process's, so it is put back for the other tests
+	t.Cleanup(func() { do(t, srv, http.MethodPut, "/admin/log-level", "", map[string]string{"level": "info"}) })
+
+	if resp := do(t, srv, http.MethodPut, "/admin/log-level", "", map[string]string{"level": "verbose"}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("PUT /admin/log-level verbose status = %d, want 400", resp.StatusCode)
+	}
+	if resp := do(t, srv, http.MethodPut, "/admin/log-level", "", map[string]string{"level": "debug"}); resp.StatusCode != http.StatusOK {
+		t.Fatalf("PUT /admin/log-level status = %d, want 200", resp.StatusCode)
+	}
+
+	var result struct {
+		Data struct {
+			Level string `json:"level"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(do(t, srv, http.MethodGet, "/admin/log-level", "", nil).Body).Decode(&result); err != nil {
+		t.Fatalf("decode log level response: %v", err)
+	}
+	if result.Data.Level != "debug" {
+		t.Errorf("level = %q, want debug", result.Data.Level)
+	}
+}
+
+func TestSimulatorKeyPolicies(t *testing.T) {
+	srv := startSimulator(t, WithKeyPolicies([]string{"EVP"}, true))
+	token := register(t, srv)
+	validate := func(key, keyType string) string {
+		t.Helper()
+		var result struct {
+			Data struct {
+				Valid bool   `json:"valid"`
+				Error string `json:"error"`
+			} `json:"data"`
+		}
+		resp := do(t, srv, http.MethodPost, "/keys/validate", token, map[string]string{"key": key, "keyType": keyType})
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("decode validation of %s: %v", key, err)
+		}
+		return result.Data.Error
+	}
+	evp := uuid.New().String()
+
+	if got := validate(evp, "EVP"); got != "KEY_TYPE_DISABLED" {
+		t.Errorf("EVP key error = %q, want KEY_TYPE_DISABLED", got)
+	}
+	if got := validate("+14155552671", "PHONE"); got != "INVALID_PHONE" {
+		t.Errorf("US phone error = %q, want INVALID_PHONE with Brazilian phones only", got)
+	}
+	if got := validate("+5511987654321", "PHONE"); got != "" {
+		t.Errorf("Brazilian phone error = %q, want valid", got)
+	}
+
+	if resp := do(t, srv, http.MethodPut, "/admin/key-policies", "", map[string]any{"disabledKeyTypes": []string{"IBAN"}}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("PUT /admin/key-policies with IBAN status = %d, want 400", resp.StatusCode)
+	}
+	if resp := do(t, srv, http.MethodPut, "/admin/key-policies", "", map[string]any{"disabledKeyTypes": []string{"CNPJ"}}); resp.StatusCode != http.StatusOK {
+		t.Fatalf("PUT /admin/key-policies status = %d, want 200", resp.StatusCode)
+	}
+
+	var result struct {
+		Data struct {
+			DisabledKeyTypes    []string `json:"disabledKeyTypes"`
+			BrazilianPhonesOnly bool     `json:"brazilianPhonesOnly"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(do(t, srv, http.MethodGet, "/admin/key-policies", "", nil).Body).Decode(&result); err != nil {
+		t.Fatalf("decode key policies response: %v", err)
+	}
+	if len(result.Data.DisabledKeyTypes) != 1 || result.Data.DisabledKeyTypes[0] != "CNPJ" || result.Data.BrazilianPhonesOnly {
+		t.Errorf("key policies = %+v, want only CNPJ disabled", result.Data)
+	}
+
+	// The new policies apply at once
+	if got := validate(evp, "EVP"); got != "" {
+		t.Errorf("EVP key error = %q after enabling EVP, want valid", got)
+	}
+	if got := validate("+14155552671", "PHONE"); got != "" {
+		t.Errorf("US phone error = %q after allowing international phones, want valid", got)
+	}
+	if got := validate("11222333000181", "CNPJ"); got != "KEY_TYPE_DISABLED" {
+		t.Errorf("CNPJ key error = %q, want KEY_TYPE_DISABLED", got)
+	}
+}
+
+func TestSimulatorQuotas(t *testing.T) {
+	srv := startSimulator(t, WithQuota(2, 0))
+	token := register(t, srv)
+	create := func(email string) *http.Response {
+		return do(t, srv, http.MethodPost, "/entries", token, map[string]any{
+			"key":     email,
+			"keyType": "EMAIL",
+			"account": map[string]any{
+				"participant":   "12345678",
+				"branch":        "0001",
+				"accountNumber": "0007654321",
+				"accountType":   "CACC",
+				"openingDate":   time.Now().UTC().Format(time.RFC3339),
+			},
+			"owner": map[string]any{
+				"type":        "NATURAL_PERSON",
+				"taxIdNumber": validCPF,
+				"name":        "SDK Test",
+			},
+			"reason":    "USER_REQUESTED",
+			"requestId": uuid.New().String(),
+		})
+	}
+	errorCode := func(resp *http.Response) string {
+		t.Helper()
+		var body struct {
+			Error string `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("decode error response: %v", err)
+		}
+		return body.Error
+	}
+
+	for i, want := range []int{http.StatusCreated, http.StatusCreated, http.StatusForbidden} {
+		resp := create("quota" + strconv.Itoa(i) + "@example.com")
+		if resp.StatusCode != want {
+			t.Fatalf("creation %d status = %d, want %d", i+1, resp.StatusCode, want)
+		}
+		if want == http.StatusForbidden {
+			if got := errorCode(resp); got != "ENTRY_QUOTA_EXCEEDED" {
+				t.Errorf("creation beyond the quota error = %q, want ENTRY_QUOTA_EXCEEDED", got)
+			}
+		}
+	}
+
+	var result struct {
+		Data struct {
+			Quota struct {
+				MaxEntries        int `json:"maxEntries"`
+				RequestsPerMinute int `json:"requestsPerMinute"`
+			} `json:"quota"`
+			Own     bool  `json:"own"`
+			Entries int64 `json:"entries"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(do(t, srv, http.MethodGet, "/admin/quotas/12345678", "", nil).Body).Decode(&result); err != nil {
+		t.Fatalf("decode quota response: %v", err)
+	}
+	if result.Data.Quota.MaxEntries != 2 || result.Data.Own || result.Data.Entries != 2 {
+		t.Errorf("quota = %+v, want the default of 2 entries, both used", result.Data)
+	}
+
+	if resp := do(t, srv, http.MethodPut, "/admin/quotas/1234", "", map[string]int{"maxEntries": 3}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("PUT /admin/quotas/1234 status = %d, want 400", resp.StatusCode)
+	}
+	if resp := do(t, srv, http.MethodPut, "/admin/quotas/12345678", "", map[string]int{"maxEntries": -1}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("PUT /admin/quotas with a negative quota status = %d, want 400", resp.StatusCode)
+	}
+	resp := do(t, srv, http.MethodPut, "/admin/quotas/12345678", "", map[string]int{"maxEntries": 3, "requestsPerMinute": 2})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("PUT /admin/quotas/12345678 status = %d, want 200", resp.StatusCode)
+	}
+	if resp := create("quota3@example.com"); resp.StatusCode != http.StatusCreated {
+		t.Errorf("creation under the raised quota status = %d, want 201", resp.StatusCode)
+	}
+
+	// Requests made for the participant count against its request quota
+	lookup := func() *http.Response {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/entries/quota0@example.com", nil)
+		if err != nil {
+			t.Fatalf("new request: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("X-Participant-Id", "12345678")
+		req.Header.Set(AdminTokenHeader, DefaultAdminToken)
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatalf("GET /entries: %v", err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+	for i := range 2 {
+		if resp := lookup(); resp.StatusCode != http.StatusOK {
+			t.Fatalf("lookup %d status = %d, want 200", i+1, resp.StatusCode)
+		}
+	}
+	resp = lookup()
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "30" {
+		t.Fatalf("lookup beyond the quota status = %d retrying after %q, want 429 after 30s", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+	if got := errorCode(resp); got != "REQUEST_QUOTA_EXCEEDED" {
+		t.Errorf("lookup beyond the quota error = %q, want REQUEST_QUOTA_EXCEEDED", got)
+	}
+
+	// Back on the default quota, which sets no request cap
+	if resp := do(t, srv, http.MethodDelete, "/admin/quotas/12345678", "", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("DELETE /admin/quotas/12345678 status = %d, want 200", resp.StatusCode)
+	}
+	if resp := do(t, srv, http.MethodDelete, "/admin/quotas/12345678", "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("second DELETE /admin/quotas/12345678 status = %d, want 404", resp.StatusCode)
+	}
+	if resp := lookup(); resp.StatusCode != http.StatusOK {
+		t.Errorf("lookup on the default quota status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestSimulatorRequestSigning(t *testing.T) {
+	srv := startSimulator(t, WithRequestSigning(map[string]string{"12345678": "secret"}))
+	token := register(t, srv)
+	path := "/entries/" + validCPF
+
+	if resp := do(t, srv, http.MethodGet, path, token, nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unsigned GET status = %d, want 401", resp.StatusCode)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-Participant-Id", "12345678")
+	req.Header.Set(AdminTokenHeader, DefaultAdminToken)
+	req.Header.Set(signing.TimestampHeader, timestamp)
+	req.Header.Set(signing.NonceHeader, "nonce-1")
+	req.Header.Set(signing.SignatureHeader, signing.Sign("secret", timestamp, "nonce-1", http.MethodGet, path, nil))
+
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("signed GET status = %d, want 404 from the handler", resp.StatusCode)
+	}
+}
+
+func TestSimulatorCorrelationReplay(t *testing.T) {
+	srv := startSimulator(t,
+		WithRequestSigning(map[string]string{"12345678": "secret"}),
+		WithCorrelationReplayWindow(time.Minute),
+	)
+	token := register(t, srv)
+	path := "/entries/" + validCPF
+
+	get := func(nonce, correlationID string) (int, string) {
+		t.Helper()
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		if err != nil {
+			t.Fatalf("new request: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("X-Participant-Id", "12345678")
+		req.Header.Set(AdminTokenHeader, DefaultAdminToken)
+		req.Header.Set(signing.TimestampHeader, timestamp)
+		req.Header.Set(signing.NonceHeader, nonce)
+		req.Header.Set(signing.SignatureHeader, signing.Sign("secret", timestamp, nonce, http.MethodGet, path, nil))
+		if correlationID != "" {
+			req.Header.Set("X-Correlation-Id", correlationID)
+		}
+
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		var result struct {
+			Error string `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("decode GET %s: %v", path, err)
+		}
+		return resp.StatusCode, result.Error
+	}
+
+	if status, _ := get("nonce-1", "corr-1"); status != http.StatusNotFound {
+		t.Errorf("first use of a correlation ID status = %d, want 404 from the handler", status)
+	}
+	// A fresh nonce doesn't make a reused correlation ID acceptable
+	if status, code := get("nonce-2", "corr-1"); status != http.StatusUnauthorized || code != "CORRELATION_ID_REPLAYED" {
+		t.Errorf("reused correlation ID = %d %s, want 401 CORRELATION_ID_REPLAYED", status, code)
+	}
+	if status, _ := get("nonce-3", "corr-2"); status != http.StatusNotFound {
+		t.Errorf("new correlation ID status = %d, want 404 from the handler", status)
+	}
+	// Without the header, the generated correlation ID is not tracked
+	for _, nonce := range []string{"nonce-4", "nonce-5"} {
+		if status, _ := get(nonce, ""); status != http.StatusNotFound {
+			t.Errorf("request without a correlation ID status = %d, want 404 from the handler", status)
+		}
+	}
+}
+
+func TestSimulatorSnapshotNDJSON(t *testing.T) {
+	source := startSimulator(t)
+
+	// More entries than one import batch
+	resp := do(t, source, http.MethodPost, "/admin/seed", "", map[string]any{"count": 2500, "seed": 42})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("seed status = %d, want 201", resp.StatusCode)
+	}
+
+	resp = do(t, source, http.MethodGet, "/admin/export?format=ndjson", "", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("export status = %d, want 200", resp.StatusCode)
+	}
+	snapshot, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	if lines := bytes.Count(snapshot, []byte("\n")); lines != 2500 {
+		t.Fatalf("export has %d lines, want 2500", lines)
+	}
+
+	target := startSimulator(t)
+	req, err := http.NewRequest(http.MethodPost, target.URL+"/admin/import", bytes.NewReader(snapshot))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req.Header.Set(AdminTokenHeader, DefaultAdminToken)
+	imported, err := target.Client().Do(req)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	defer imported.Body.Close()
+	if imported.StatusCode != http.StatusCreated {
+		t.Fatalf("import status = %d, want 201", imported.StatusCode)
+	}
+
+	var result struct {
+		Data struct {
+			Requested int `json:"requested"`
+			Created   int `json:"created"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(imported.Body).Decode(&result); err != nil {
+		t.Fatalf("decode import response: %v", err)
+	}
+	if result.Data.Requested != 2500 || result.Data.Created != 2500 {
+		t.Errorf("import = %+v, want 2500 requested and created", result.Data)
+	}
+}